@@ -0,0 +1,58 @@
+package st
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrTruncated is returned when an object ends in the middle of a field.
+	ErrTruncated = errors.New("st: truncated object")
+	// ErrInvalidHeader is returned for a malformed field header.
+	ErrInvalidHeader = errors.New("st: invalid field header")
+	// ErrVLTooLong is returned when a variable-length value exceeds the
+	// maximum encodable length.
+	ErrVLTooLong = errors.New("st: variable-length value too long")
+	// ErrUnterminated is returned when an inner object or array has no end
+	// marker.
+	ErrUnterminated = errors.New("st: unterminated inner object or array")
+	// ErrAmountRange is returned by Writer for an Amount whose mantissa or
+	// exponent does not fit its encoding.
+	ErrAmountRange = errors.New("st: amount out of range")
+	// ErrUnbalanced is returned by Writer when EndObject or EndArray does
+	// not match the innermost BeginObject or BeginArray.
+	ErrUnbalanced = errors.New("st: unbalanced end of inner object or array")
+)
+
+// UnknownFieldError is returned when a field header does not match any
+// serialized field.
+type UnknownFieldError struct {
+	Type Type
+	Nth  int32
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("st: unknown field (type %d, nth %d)", int32(e.Type), e.Nth)
+}
+
+// TypeMismatchError is returned when a value of one type is read from or
+// written to a field of another type.
+type TypeMismatchError struct {
+	Field *Field
+	Want  Type
+}
+
+func (e *TypeMismatchError) Error() string {
+	return fmt.Sprintf("st: field %s is %s, not %s", e.Field.Name, e.Field.Type, e.Want)
+}
+
+// OrderError is returned by Writer when fields are not written in canonical
+// order.
+type OrderError struct {
+	Field    *Field
+	Previous *Field
+}
+
+func (e *OrderError) Error() string {
+	return fmt.Sprintf("st: field %s written after %s breaks canonical order", e.Field.Name, e.Previous.Name)
+}
@@ -0,0 +1,160 @@
+// Package st implements a struct-oriented binary codec for XRPL serialized
+// objects.
+//
+// Unlike the map-based binarycodec.Encode and binarycodec.Decode, which go
+// through hex strings and map[string]any, st reads and writes the canonical
+// binary format directly: a Reader walks the fields of an object as
+// sub-slices of its input, and a Writer appends typed values to a byte slice.
+// Neither allocates per field. The field table (fields_gen.go) is generated
+// from definitions.json, so every SField known to the map-based codec has a
+// typed counterpart here, e.g. SFAccount or SFLedgerEntryType.
+//
+// Reference: rippled STObject, SField, Serializer
+package st
+
+//go:generate go run gen_fields.go
+
+import "fmt"
+
+// Type is a serialized type code.
+// Reference: rippled SField.h SerializedTypeID
+type Type int32
+
+// Serialized type codes from definitions.json.
+const (
+	TypeUInt16       Type = 1
+	TypeUInt32       Type = 2
+	TypeUInt64       Type = 3
+	TypeHash128      Type = 4
+	TypeHash256      Type = 5
+	TypeAmount       Type = 6
+	TypeBlob         Type = 7
+	TypeAccountID    Type = 8
+	TypeNumber       Type = 9
+	TypeInt32        Type = 10
+	TypeInt64        Type = 11
+	TypeSTObject     Type = 14
+	TypeSTArray      Type = 15
+	TypeUInt8        Type = 16
+	TypeHash160      Type = 17
+	TypePathSet      Type = 18
+	TypeVector256    Type = 19
+	TypeUInt96       Type = 20
+	TypeHash192      Type = 21
+	TypeUInt384      Type = 22
+	TypeUInt512      Type = 23
+	TypeIssue        Type = 24
+	TypeXChainBridge Type = 25
+	TypeCurrency     Type = 26
+)
+
+var typeNames = map[Type]string{
+	TypeUInt16:       "UInt16",
+	TypeUInt32:       "UInt32",
+	TypeUInt64:       "UInt64",
+	TypeHash128:      "Hash128",
+	TypeHash256:      "Hash256",
+	TypeAmount:       "Amount",
+	TypeBlob:         "Blob",
+	TypeAccountID:    "AccountID",
+	TypeNumber:       "Number",
+	TypeInt32:        "Int32",
+	TypeInt64:        "Int64",
+	TypeSTObject:     "STObject",
+	TypeSTArray:      "STArray",
+	TypeUInt8:        "UInt8",
+	TypeHash160:      "Hash160",
+	TypePathSet:      "PathSet",
+	TypeVector256:    "Vector256",
+	TypeUInt96:       "UInt96",
+	TypeHash192:      "Hash192",
+	TypeUInt384:      "UInt384",
+	TypeUInt512:      "UInt512",
+	TypeIssue:        "Issue",
+	TypeXChainBridge: "XChainBridge",
+	TypeCurrency:     "Currency",
+}
+
+// String returns the type name used in definitions.json.
+func (t Type) String() string {
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("Type(%d)", int32(t))
+}
+
+// Field describes a serialized field (rippled SField).
+type Field struct {
+	Name      string
+	Type      Type
+	Nth       int32
+	VLEncoded bool
+	Signing   bool
+}
+
+// Ordinal returns the canonical sort key of the field: fields of an object
+// are serialized in ascending ordinal order.
+func (f *Field) Ordinal() int32 {
+	return int32(f.Type)<<16 | f.Nth
+}
+
+// String returns the field name.
+func (f *Field) String() string {
+	return f.Name
+}
+
+var (
+	fieldsByOrdinal = make(map[int32]*Field, len(allFields))
+	fieldsByName    = make(map[string]*Field, len(allFields))
+)
+
+func init() {
+	for _, f := range allFields {
+		fieldsByOrdinal[f.Ordinal()] = f
+		fieldsByName[f.Name] = f
+	}
+}
+
+// FieldByName returns the serialized field with the given name, or nil.
+func FieldByName(name string) *Field {
+	return fieldsByName[name]
+}
+
+// FieldByCode returns the serialized field with the given type and field
+// code, or nil.
+func FieldByCode(t Type, nth int32) *Field {
+	return fieldsByOrdinal[int32(t)<<16|nth]
+}
+
+// AppendHeader appends the field header (type and field code) of f.
+func AppendHeader(buf []byte, f *Field) []byte {
+	tc, fc := byte(f.Type), byte(f.Nth)
+	switch {
+	case f.Type < 16 && f.Nth < 16:
+		return append(buf, tc<<4|fc)
+	case f.Type < 16:
+		return append(buf, tc<<4, fc)
+	case f.Nth < 16:
+		return append(buf, fc, tc)
+	default:
+		return append(buf, 0, tc, fc)
+	}
+}
+
+// AppendVL appends a variable-length prefix for a value of n bytes.
+func AppendVL(buf []byte, n int) ([]byte, error) {
+	switch {
+	case n < 0:
+		return buf, ErrVLTooLong
+	case n <= 192:
+		return append(buf, byte(n)), nil
+	case n <= 12480:
+		n -= 193
+		return append(buf, byte(193+n>>8), byte(n)), nil
+	case n <= 918744:
+		n -= 12481
+		return append(buf, byte(241+n>>16), byte(n>>8), byte(n)), nil
+	default:
+		return buf, ErrVLTooLong
+	}
+}
@@ -0,0 +1,66 @@
+package st
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/definitions"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFieldsMatchDefinitions checks that the generated field table agrees
+// with the definitions used by the map-based codec.
+func TestFieldsMatchDefinitions(t *testing.T) {
+	defs := definitions.Get()
+	serialized := 0
+	for name, fi := range defs.Fields {
+		if !fi.IsSerialized {
+			continue
+		}
+		serialized++
+		f := FieldByName(name)
+		require.NotNil(t, f, name)
+		require.Equal(t, defs.Types[fi.Type], int32(f.Type), name)
+		require.Equal(t, fi.Type, f.Type.String(), name)
+		require.Equal(t, fi.Nth, f.Nth, name)
+		require.Equal(t, fi.IsVLEncoded, f.VLEncoded, name)
+		require.Equal(t, fi.IsSigningField, f.Signing, name)
+		require.Equal(t, fi.Ordinal, f.Ordinal(), name)
+		require.Same(t, f, FieldByCode(f.Type, f.Nth), name)
+	}
+	require.Equal(t, serialized, len(allFields))
+
+	for i := 1; i < len(allFields); i++ {
+		require.Less(t, allFields[i-1].Ordinal(), allFields[i].Ordinal())
+	}
+}
+
+func TestAppendHeader(t *testing.T) {
+	defs := definitions.Get()
+	for _, f := range allFields {
+		fh := defs.Fields[f.Name].FieldHeader
+		buf := AppendHeader(nil, f)
+		typ, nth, n, ok := readHeader(buf)
+		require.True(t, ok, f.Name)
+		require.Equal(t, len(buf), n, f.Name)
+		require.Equal(t, fh.TypeCode, int32(typ), f.Name)
+		require.Equal(t, fh.FieldCode, nth, f.Name)
+	}
+}
+
+func TestAppendVL(t *testing.T) {
+	for _, n := range []int{0, 1, 192, 193, 12480, 12481, 918744} {
+		buf, err := AppendVL(nil, n)
+		require.NoError(t, err)
+		got, size, ok := readVL(buf)
+		require.True(t, ok)
+		require.Equal(t, n, got)
+		require.Equal(t, len(buf), size)
+	}
+	_, err := AppendVL(nil, 918745)
+	require.ErrorIs(t, err, ErrVLTooLong)
+
+	// The prefix of a 193-byte value is C1 00.
+	buf, _ := AppendVL(nil, 193)
+	require.True(t, bytes.Equal([]byte{0xC1, 0x00}, buf))
+}
@@ -0,0 +1,677 @@
+// Code generated by gen_fields.go from definitions.json; DO NOT EDIT.
+
+package st
+
+// Serialized fields, in canonical order.
+var (
+	SFLedgerEntryType                                 = &Field{Name: "LedgerEntryType", Type: TypeUInt16, Nth: 1, VLEncoded: false, Signing: true}
+	SFTransactionType                                 = &Field{Name: "TransactionType", Type: TypeUInt16, Nth: 2, VLEncoded: false, Signing: true}
+	SFSignerWeight                                    = &Field{Name: "SignerWeight", Type: TypeUInt16, Nth: 3, VLEncoded: false, Signing: true}
+	SFTransferFee                                     = &Field{Name: "TransferFee", Type: TypeUInt16, Nth: 4, VLEncoded: false, Signing: true}
+	SFTradingFee                                      = &Field{Name: "TradingFee", Type: TypeUInt16, Nth: 5, VLEncoded: false, Signing: true}
+	SFDiscountedFee                                   = &Field{Name: "DiscountedFee", Type: TypeUInt16, Nth: 6, VLEncoded: false, Signing: true}
+	SFVersion                                         = &Field{Name: "Version", Type: TypeUInt16, Nth: 16, VLEncoded: false, Signing: true}
+	SFHookStateChangeCount                            = &Field{Name: "HookStateChangeCount", Type: TypeUInt16, Nth: 17, VLEncoded: false, Signing: true}
+	SFHookEmitCount                                   = &Field{Name: "HookEmitCount", Type: TypeUInt16, Nth: 18, VLEncoded: false, Signing: true}
+	SFHookExecutionIndex                              = &Field{Name: "HookExecutionIndex", Type: TypeUInt16, Nth: 19, VLEncoded: false, Signing: true}
+	SFHookApiVersion                                  = &Field{Name: "HookApiVersion", Type: TypeUInt16, Nth: 20, VLEncoded: false, Signing: true}
+	SFLedgerFixType                                   = &Field{Name: "LedgerFixType", Type: TypeUInt16, Nth: 21, VLEncoded: false, Signing: true}
+	SFManagementFeeRate                               = &Field{Name: "ManagementFeeRate", Type: TypeUInt16, Nth: 22, VLEncoded: false, Signing: true}
+	SFNetworkID                                       = &Field{Name: "NetworkID", Type: TypeUInt32, Nth: 1, VLEncoded: false, Signing: true}
+	SFFlags                                           = &Field{Name: "Flags", Type: TypeUInt32, Nth: 2, VLEncoded: false, Signing: true}
+	SFSourceTag                                       = &Field{Name: "SourceTag", Type: TypeUInt32, Nth: 3, VLEncoded: false, Signing: true}
+	SFSequence                                        = &Field{Name: "Sequence", Type: TypeUInt32, Nth: 4, VLEncoded: false, Signing: true}
+	SFPreviousTxnLgrSeq                               = &Field{Name: "PreviousTxnLgrSeq", Type: TypeUInt32, Nth: 5, VLEncoded: false, Signing: true}
+	SFLedgerSequence                                  = &Field{Name: "LedgerSequence", Type: TypeUInt32, Nth: 6, VLEncoded: false, Signing: true}
+	SFCloseTime                                       = &Field{Name: "CloseTime", Type: TypeUInt32, Nth: 7, VLEncoded: false, Signing: true}
+	SFParentCloseTime                                 = &Field{Name: "ParentCloseTime", Type: TypeUInt32, Nth: 8, VLEncoded: false, Signing: true}
+	SFSigningTime                                     = &Field{Name: "SigningTime", Type: TypeUInt32, Nth: 9, VLEncoded: false, Signing: true}
+	SFExpiration                                      = &Field{Name: "Expiration", Type: TypeUInt32, Nth: 10, VLEncoded: false, Signing: true}
+	SFTransferRate                                    = &Field{Name: "TransferRate", Type: TypeUInt32, Nth: 11, VLEncoded: false, Signing: true}
+	SFWalletSize                                      = &Field{Name: "WalletSize", Type: TypeUInt32, Nth: 12, VLEncoded: false, Signing: true}
+	SFOwnerCount                                      = &Field{Name: "OwnerCount", Type: TypeUInt32, Nth: 13, VLEncoded: false, Signing: true}
+	SFDestinationTag                                  = &Field{Name: "DestinationTag", Type: TypeUInt32, Nth: 14, VLEncoded: false, Signing: true}
+	SFLastUpdateTime                                  = &Field{Name: "LastUpdateTime", Type: TypeUInt32, Nth: 15, VLEncoded: false, Signing: true}
+	SFHighQualityIn                                   = &Field{Name: "HighQualityIn", Type: TypeUInt32, Nth: 16, VLEncoded: false, Signing: true}
+	SFHighQualityOut                                  = &Field{Name: "HighQualityOut", Type: TypeUInt32, Nth: 17, VLEncoded: false, Signing: true}
+	SFLowQualityIn                                    = &Field{Name: "LowQualityIn", Type: TypeUInt32, Nth: 18, VLEncoded: false, Signing: true}
+	SFLowQualityOut                                   = &Field{Name: "LowQualityOut", Type: TypeUInt32, Nth: 19, VLEncoded: false, Signing: true}
+	SFQualityIn                                       = &Field{Name: "QualityIn", Type: TypeUInt32, Nth: 20, VLEncoded: false, Signing: true}
+	SFQualityOut                                      = &Field{Name: "QualityOut", Type: TypeUInt32, Nth: 21, VLEncoded: false, Signing: true}
+	SFStampEscrow                                     = &Field{Name: "StampEscrow", Type: TypeUInt32, Nth: 22, VLEncoded: false, Signing: true}
+	SFBondAmount                                      = &Field{Name: "BondAmount", Type: TypeUInt32, Nth: 23, VLEncoded: false, Signing: true}
+	SFLoadFee                                         = &Field{Name: "LoadFee", Type: TypeUInt32, Nth: 24, VLEncoded: false, Signing: true}
+	SFOfferSequence                                   = &Field{Name: "OfferSequence", Type: TypeUInt32, Nth: 25, VLEncoded: false, Signing: true}
+	SFFirstLedgerSequence                             = &Field{Name: "FirstLedgerSequence", Type: TypeUInt32, Nth: 26, VLEncoded: false, Signing: true}
+	SFLastLedgerSequence                              = &Field{Name: "LastLedgerSequence", Type: TypeUInt32, Nth: 27, VLEncoded: false, Signing: true}
+	SFTransactionIndex                                = &Field{Name: "TransactionIndex", Type: TypeUInt32, Nth: 28, VLEncoded: false, Signing: true}
+	SFOperationLimit                                  = &Field{Name: "OperationLimit", Type: TypeUInt32, Nth: 29, VLEncoded: false, Signing: true}
+	SFReferenceFeeUnits                               = &Field{Name: "ReferenceFeeUnits", Type: TypeUInt32, Nth: 30, VLEncoded: false, Signing: true}
+	SFReserveBase                                     = &Field{Name: "ReserveBase", Type: TypeUInt32, Nth: 31, VLEncoded: false, Signing: true}
+	SFReserveIncrement                                = &Field{Name: "ReserveIncrement", Type: TypeUInt32, Nth: 32, VLEncoded: false, Signing: true}
+	SFSetFlag                                         = &Field{Name: "SetFlag", Type: TypeUInt32, Nth: 33, VLEncoded: false, Signing: true}
+	SFClearFlag                                       = &Field{Name: "ClearFlag", Type: TypeUInt32, Nth: 34, VLEncoded: false, Signing: true}
+	SFSignerQuorum                                    = &Field{Name: "SignerQuorum", Type: TypeUInt32, Nth: 35, VLEncoded: false, Signing: true}
+	SFCancelAfter                                     = &Field{Name: "CancelAfter", Type: TypeUInt32, Nth: 36, VLEncoded: false, Signing: true}
+	SFFinishAfter                                     = &Field{Name: "FinishAfter", Type: TypeUInt32, Nth: 37, VLEncoded: false, Signing: true}
+	SFSignerListID                                    = &Field{Name: "SignerListID", Type: TypeUInt32, Nth: 38, VLEncoded: false, Signing: true}
+	SFSettleDelay                                     = &Field{Name: "SettleDelay", Type: TypeUInt32, Nth: 39, VLEncoded: false, Signing: true}
+	SFTicketCount                                     = &Field{Name: "TicketCount", Type: TypeUInt32, Nth: 40, VLEncoded: false, Signing: true}
+	SFTicketSequence                                  = &Field{Name: "TicketSequence", Type: TypeUInt32, Nth: 41, VLEncoded: false, Signing: true}
+	SFNFTokenTaxon                                    = &Field{Name: "NFTokenTaxon", Type: TypeUInt32, Nth: 42, VLEncoded: false, Signing: true}
+	SFMintedNFTokens                                  = &Field{Name: "MintedNFTokens", Type: TypeUInt32, Nth: 43, VLEncoded: false, Signing: true}
+	SFBurnedNFTokens                                  = &Field{Name: "BurnedNFTokens", Type: TypeUInt32, Nth: 44, VLEncoded: false, Signing: true}
+	SFHookStateCount                                  = &Field{Name: "HookStateCount", Type: TypeUInt32, Nth: 45, VLEncoded: false, Signing: true}
+	SFEmitGeneration                                  = &Field{Name: "EmitGeneration", Type: TypeUInt32, Nth: 46, VLEncoded: false, Signing: true}
+	SFVoteWeight                                      = &Field{Name: "VoteWeight", Type: TypeUInt32, Nth: 48, VLEncoded: false, Signing: true}
+	SFFirstNFTokenSequence                            = &Field{Name: "FirstNFTokenSequence", Type: TypeUInt32, Nth: 50, VLEncoded: false, Signing: true}
+	SFOracleDocumentID                                = &Field{Name: "OracleDocumentID", Type: TypeUInt32, Nth: 51, VLEncoded: false, Signing: true}
+	SFPermissionValue                                 = &Field{Name: "PermissionValue", Type: TypeUInt32, Nth: 52, VLEncoded: false, Signing: true}
+	SFMutableFlags                                    = &Field{Name: "MutableFlags", Type: TypeUInt32, Nth: 53, VLEncoded: false, Signing: true}
+	SFStartDate                                       = &Field{Name: "StartDate", Type: TypeUInt32, Nth: 54, VLEncoded: false, Signing: true}
+	SFPaymentInterval                                 = &Field{Name: "PaymentInterval", Type: TypeUInt32, Nth: 55, VLEncoded: false, Signing: true}
+	SFGracePeriod                                     = &Field{Name: "GracePeriod", Type: TypeUInt32, Nth: 56, VLEncoded: false, Signing: true}
+	SFPreviousPaymentDate                             = &Field{Name: "PreviousPaymentDate", Type: TypeUInt32, Nth: 57, VLEncoded: false, Signing: true}
+	SFNextPaymentDueDate                              = &Field{Name: "NextPaymentDueDate", Type: TypeUInt32, Nth: 58, VLEncoded: false, Signing: true}
+	SFPaymentRemaining                                = &Field{Name: "PaymentRemaining", Type: TypeUInt32, Nth: 59, VLEncoded: false, Signing: true}
+	SFPaymentTotal                                    = &Field{Name: "PaymentTotal", Type: TypeUInt32, Nth: 60, VLEncoded: false, Signing: true}
+	SFLoanSequence                                    = &Field{Name: "LoanSequence", Type: TypeUInt32, Nth: 61, VLEncoded: false, Signing: true}
+	SFCoverRateMinimum                                = &Field{Name: "CoverRateMinimum", Type: TypeUInt32, Nth: 62, VLEncoded: false, Signing: true}
+	SFCoverRateLiquidation                            = &Field{Name: "CoverRateLiquidation", Type: TypeUInt32, Nth: 63, VLEncoded: false, Signing: true}
+	SFOverpaymentFee                                  = &Field{Name: "OverpaymentFee", Type: TypeUInt32, Nth: 64, VLEncoded: false, Signing: true}
+	SFInterestRate                                    = &Field{Name: "InterestRate", Type: TypeUInt32, Nth: 65, VLEncoded: false, Signing: true}
+	SFLateInterestRate                                = &Field{Name: "LateInterestRate", Type: TypeUInt32, Nth: 66, VLEncoded: false, Signing: true}
+	SFCloseInterestRate                               = &Field{Name: "CloseInterestRate", Type: TypeUInt32, Nth: 67, VLEncoded: false, Signing: true}
+	SFOverpaymentInterestRate                         = &Field{Name: "OverpaymentInterestRate", Type: TypeUInt32, Nth: 68, VLEncoded: false, Signing: true}
+	SFIndexNext                                       = &Field{Name: "IndexNext", Type: TypeUInt64, Nth: 1, VLEncoded: false, Signing: true}
+	SFIndexPrevious                                   = &Field{Name: "IndexPrevious", Type: TypeUInt64, Nth: 2, VLEncoded: false, Signing: true}
+	SFBookNode                                        = &Field{Name: "BookNode", Type: TypeUInt64, Nth: 3, VLEncoded: false, Signing: true}
+	SFOwnerNode                                       = &Field{Name: "OwnerNode", Type: TypeUInt64, Nth: 4, VLEncoded: false, Signing: true}
+	SFBaseFee                                         = &Field{Name: "BaseFee", Type: TypeUInt64, Nth: 5, VLEncoded: false, Signing: true}
+	SFExchangeRate                                    = &Field{Name: "ExchangeRate", Type: TypeUInt64, Nth: 6, VLEncoded: false, Signing: true}
+	SFLowNode                                         = &Field{Name: "LowNode", Type: TypeUInt64, Nth: 7, VLEncoded: false, Signing: true}
+	SFHighNode                                        = &Field{Name: "HighNode", Type: TypeUInt64, Nth: 8, VLEncoded: false, Signing: true}
+	SFDestinationNode                                 = &Field{Name: "DestinationNode", Type: TypeUInt64, Nth: 9, VLEncoded: false, Signing: true}
+	SFCookie                                          = &Field{Name: "Cookie", Type: TypeUInt64, Nth: 10, VLEncoded: false, Signing: true}
+	SFServerVersion                                   = &Field{Name: "ServerVersion", Type: TypeUInt64, Nth: 11, VLEncoded: false, Signing: true}
+	SFNFTokenOfferNode                                = &Field{Name: "NFTokenOfferNode", Type: TypeUInt64, Nth: 12, VLEncoded: false, Signing: true}
+	SFEmitBurden                                      = &Field{Name: "EmitBurden", Type: TypeUInt64, Nth: 13, VLEncoded: false, Signing: true}
+	SFHookOn                                          = &Field{Name: "HookOn", Type: TypeUInt64, Nth: 16, VLEncoded: false, Signing: true}
+	SFHookInstructionCount                            = &Field{Name: "HookInstructionCount", Type: TypeUInt64, Nth: 17, VLEncoded: false, Signing: true}
+	SFHookReturnCode                                  = &Field{Name: "HookReturnCode", Type: TypeUInt64, Nth: 18, VLEncoded: false, Signing: true}
+	SFReferenceCount                                  = &Field{Name: "ReferenceCount", Type: TypeUInt64, Nth: 19, VLEncoded: false, Signing: true}
+	SFXChainClaimID                                   = &Field{Name: "XChainClaimID", Type: TypeUInt64, Nth: 20, VLEncoded: false, Signing: true}
+	SFXChainAccountCreateCount                        = &Field{Name: "XChainAccountCreateCount", Type: TypeUInt64, Nth: 21, VLEncoded: false, Signing: true}
+	SFXChainAccountClaimCount                         = &Field{Name: "XChainAccountClaimCount", Type: TypeUInt64, Nth: 22, VLEncoded: false, Signing: true}
+	SFAssetPrice                                      = &Field{Name: "AssetPrice", Type: TypeUInt64, Nth: 23, VLEncoded: false, Signing: true}
+	SFMaximumAmount                                   = &Field{Name: "MaximumAmount", Type: TypeUInt64, Nth: 24, VLEncoded: false, Signing: true}
+	SFOutstandingAmount                               = &Field{Name: "OutstandingAmount", Type: TypeUInt64, Nth: 25, VLEncoded: false, Signing: true}
+	SFMPTAmount                                       = &Field{Name: "MPTAmount", Type: TypeUInt64, Nth: 26, VLEncoded: false, Signing: true}
+	SFIssuerNode                                      = &Field{Name: "IssuerNode", Type: TypeUInt64, Nth: 27, VLEncoded: false, Signing: true}
+	SFSubjectNode                                     = &Field{Name: "SubjectNode", Type: TypeUInt64, Nth: 28, VLEncoded: false, Signing: true}
+	SFLockedAmount                                    = &Field{Name: "LockedAmount", Type: TypeUInt64, Nth: 29, VLEncoded: false, Signing: true}
+	SFVaultNode                                       = &Field{Name: "VaultNode", Type: TypeUInt64, Nth: 30, VLEncoded: false, Signing: true}
+	SFLoanBrokerNode                                  = &Field{Name: "LoanBrokerNode", Type: TypeUInt64, Nth: 31, VLEncoded: false, Signing: true}
+	SFEmailHash                                       = &Field{Name: "EmailHash", Type: TypeHash128, Nth: 1, VLEncoded: false, Signing: true}
+	SFLedgerHash                                      = &Field{Name: "LedgerHash", Type: TypeHash256, Nth: 1, VLEncoded: false, Signing: true}
+	SFParentHash                                      = &Field{Name: "ParentHash", Type: TypeHash256, Nth: 2, VLEncoded: false, Signing: true}
+	SFTransactionHash                                 = &Field{Name: "TransactionHash", Type: TypeHash256, Nth: 3, VLEncoded: false, Signing: true}
+	SFAccountHash                                     = &Field{Name: "AccountHash", Type: TypeHash256, Nth: 4, VLEncoded: false, Signing: true}
+	SFPreviousTxnID                                   = &Field{Name: "PreviousTxnID", Type: TypeHash256, Nth: 5, VLEncoded: false, Signing: true}
+	SFLedgerIndex                                     = &Field{Name: "LedgerIndex", Type: TypeHash256, Nth: 6, VLEncoded: false, Signing: true}
+	SFWalletLocator                                   = &Field{Name: "WalletLocator", Type: TypeHash256, Nth: 7, VLEncoded: false, Signing: true}
+	SFRootIndex                                       = &Field{Name: "RootIndex", Type: TypeHash256, Nth: 8, VLEncoded: false, Signing: true}
+	SFAccountTxnID                                    = &Field{Name: "AccountTxnID", Type: TypeHash256, Nth: 9, VLEncoded: false, Signing: true}
+	SFNFTokenID                                       = &Field{Name: "NFTokenID", Type: TypeHash256, Nth: 10, VLEncoded: false, Signing: true}
+	SFEmitParentTxnID                                 = &Field{Name: "EmitParentTxnID", Type: TypeHash256, Nth: 11, VLEncoded: false, Signing: true}
+	SFEmitNonce                                       = &Field{Name: "EmitNonce", Type: TypeHash256, Nth: 12, VLEncoded: false, Signing: true}
+	SFEmitHookHash                                    = &Field{Name: "EmitHookHash", Type: TypeHash256, Nth: 13, VLEncoded: false, Signing: true}
+	SFAMMID                                           = &Field{Name: "AMMID", Type: TypeHash256, Nth: 14, VLEncoded: false, Signing: true}
+	SFBookDirectory                                   = &Field{Name: "BookDirectory", Type: TypeHash256, Nth: 16, VLEncoded: false, Signing: true}
+	SFInvoiceID                                       = &Field{Name: "InvoiceID", Type: TypeHash256, Nth: 17, VLEncoded: false, Signing: true}
+	SFNickname                                        = &Field{Name: "Nickname", Type: TypeHash256, Nth: 18, VLEncoded: false, Signing: true}
+	SFAmendment                                       = &Field{Name: "Amendment", Type: TypeHash256, Nth: 19, VLEncoded: false, Signing: true}
+	SFDigest                                          = &Field{Name: "Digest", Type: TypeHash256, Nth: 21, VLEncoded: false, Signing: true}
+	SFChannel                                         = &Field{Name: "Channel", Type: TypeHash256, Nth: 22, VLEncoded: false, Signing: true}
+	SFConsensusHash                                   = &Field{Name: "ConsensusHash", Type: TypeHash256, Nth: 23, VLEncoded: false, Signing: true}
+	SFCheckID                                         = &Field{Name: "CheckID", Type: TypeHash256, Nth: 24, VLEncoded: false, Signing: true}
+	SFValidatedHash                                   = &Field{Name: "ValidatedHash", Type: TypeHash256, Nth: 25, VLEncoded: false, Signing: true}
+	SFPreviousPageMin                                 = &Field{Name: "PreviousPageMin", Type: TypeHash256, Nth: 26, VLEncoded: false, Signing: true}
+	SFNextPageMin                                     = &Field{Name: "NextPageMin", Type: TypeHash256, Nth: 27, VLEncoded: false, Signing: true}
+	SFNFTokenBuyOffer                                 = &Field{Name: "NFTokenBuyOffer", Type: TypeHash256, Nth: 28, VLEncoded: false, Signing: true}
+	SFNFTokenSellOffer                                = &Field{Name: "NFTokenSellOffer", Type: TypeHash256, Nth: 29, VLEncoded: false, Signing: true}
+	SFHookStateKey                                    = &Field{Name: "HookStateKey", Type: TypeHash256, Nth: 30, VLEncoded: false, Signing: true}
+	SFHookHash                                        = &Field{Name: "HookHash", Type: TypeHash256, Nth: 31, VLEncoded: false, Signing: true}
+	SFHookNamespace                                   = &Field{Name: "HookNamespace", Type: TypeHash256, Nth: 32, VLEncoded: false, Signing: true}
+	SFHookSetTxnID                                    = &Field{Name: "HookSetTxnID", Type: TypeHash256, Nth: 33, VLEncoded: false, Signing: true}
+	SFDomainID                                        = &Field{Name: "DomainID", Type: TypeHash256, Nth: 34, VLEncoded: false, Signing: true}
+	SFVaultID                                         = &Field{Name: "VaultID", Type: TypeHash256, Nth: 35, VLEncoded: false, Signing: true}
+	SFParentBatchID                                   = &Field{Name: "ParentBatchID", Type: TypeHash256, Nth: 36, VLEncoded: false, Signing: true}
+	SFLoanBrokerID                                    = &Field{Name: "LoanBrokerID", Type: TypeHash256, Nth: 37, VLEncoded: false, Signing: true}
+	SFLoanID                                          = &Field{Name: "LoanID", Type: TypeHash256, Nth: 38, VLEncoded: false, Signing: true}
+	SFAmount                                          = &Field{Name: "Amount", Type: TypeAmount, Nth: 1, VLEncoded: false, Signing: true}
+	SFBalance                                         = &Field{Name: "Balance", Type: TypeAmount, Nth: 2, VLEncoded: false, Signing: true}
+	SFLimitAmount                                     = &Field{Name: "LimitAmount", Type: TypeAmount, Nth: 3, VLEncoded: false, Signing: true}
+	SFTakerPays                                       = &Field{Name: "TakerPays", Type: TypeAmount, Nth: 4, VLEncoded: false, Signing: true}
+	SFTakerGets                                       = &Field{Name: "TakerGets", Type: TypeAmount, Nth: 5, VLEncoded: false, Signing: true}
+	SFLowLimit                                        = &Field{Name: "LowLimit", Type: TypeAmount, Nth: 6, VLEncoded: false, Signing: true}
+	SFHighLimit                                       = &Field{Name: "HighLimit", Type: TypeAmount, Nth: 7, VLEncoded: false, Signing: true}
+	SFFee                                             = &Field{Name: "Fee", Type: TypeAmount, Nth: 8, VLEncoded: false, Signing: true}
+	SFSendMax                                         = &Field{Name: "SendMax", Type: TypeAmount, Nth: 9, VLEncoded: false, Signing: true}
+	SFDeliverMin                                      = &Field{Name: "DeliverMin", Type: TypeAmount, Nth: 10, VLEncoded: false, Signing: true}
+	SFAmount2                                         = &Field{Name: "Amount2", Type: TypeAmount, Nth: 11, VLEncoded: false, Signing: true}
+	SFBidMin                                          = &Field{Name: "BidMin", Type: TypeAmount, Nth: 12, VLEncoded: false, Signing: true}
+	SFBidMax                                          = &Field{Name: "BidMax", Type: TypeAmount, Nth: 13, VLEncoded: false, Signing: true}
+	SFMinimumOffer                                    = &Field{Name: "MinimumOffer", Type: TypeAmount, Nth: 16, VLEncoded: false, Signing: true}
+	SFRippleEscrow                                    = &Field{Name: "RippleEscrow", Type: TypeAmount, Nth: 17, VLEncoded: false, Signing: true}
+	SFDeliveredAmount                                 = &Field{Name: "DeliveredAmount", Type: TypeAmount, Nth: 18, VLEncoded: false, Signing: true}
+	SFNFTokenBrokerFee                                = &Field{Name: "NFTokenBrokerFee", Type: TypeAmount, Nth: 19, VLEncoded: false, Signing: true}
+	SFBaseFeeDrops                                    = &Field{Name: "BaseFeeDrops", Type: TypeAmount, Nth: 22, VLEncoded: false, Signing: true}
+	SFReserveBaseDrops                                = &Field{Name: "ReserveBaseDrops", Type: TypeAmount, Nth: 23, VLEncoded: false, Signing: true}
+	SFReserveIncrementDrops                           = &Field{Name: "ReserveIncrementDrops", Type: TypeAmount, Nth: 24, VLEncoded: false, Signing: true}
+	SFLPTokenOut                                      = &Field{Name: "LPTokenOut", Type: TypeAmount, Nth: 25, VLEncoded: false, Signing: true}
+	SFLPTokenIn                                       = &Field{Name: "LPTokenIn", Type: TypeAmount, Nth: 26, VLEncoded: false, Signing: true}
+	SFEPrice                                          = &Field{Name: "EPrice", Type: TypeAmount, Nth: 27, VLEncoded: false, Signing: true}
+	SFPrice                                           = &Field{Name: "Price", Type: TypeAmount, Nth: 28, VLEncoded: false, Signing: true}
+	SFSignatureReward                                 = &Field{Name: "SignatureReward", Type: TypeAmount, Nth: 29, VLEncoded: false, Signing: true}
+	SFMinAccountCreateAmount                          = &Field{Name: "MinAccountCreateAmount", Type: TypeAmount, Nth: 30, VLEncoded: false, Signing: true}
+	SFLPTokenBalance                                  = &Field{Name: "LPTokenBalance", Type: TypeAmount, Nth: 31, VLEncoded: false, Signing: true}
+	SFPublicKey                                       = &Field{Name: "PublicKey", Type: TypeBlob, Nth: 1, VLEncoded: true, Signing: true}
+	SFMessageKey                                      = &Field{Name: "MessageKey", Type: TypeBlob, Nth: 2, VLEncoded: true, Signing: true}
+	SFSigningPubKey                                   = &Field{Name: "SigningPubKey", Type: TypeBlob, Nth: 3, VLEncoded: true, Signing: true}
+	SFTxnSignature                                    = &Field{Name: "TxnSignature", Type: TypeBlob, Nth: 4, VLEncoded: true, Signing: false}
+	SFURI                                             = &Field{Name: "URI", Type: TypeBlob, Nth: 5, VLEncoded: true, Signing: true}
+	SFSignature                                       = &Field{Name: "Signature", Type: TypeBlob, Nth: 6, VLEncoded: true, Signing: false}
+	SFDomain                                          = &Field{Name: "Domain", Type: TypeBlob, Nth: 7, VLEncoded: true, Signing: true}
+	SFFundCode                                        = &Field{Name: "FundCode", Type: TypeBlob, Nth: 8, VLEncoded: true, Signing: true}
+	SFRemoveCode                                      = &Field{Name: "RemoveCode", Type: TypeBlob, Nth: 9, VLEncoded: true, Signing: true}
+	SFExpireCode                                      = &Field{Name: "ExpireCode", Type: TypeBlob, Nth: 10, VLEncoded: true, Signing: true}
+	SFCreateCode                                      = &Field{Name: "CreateCode", Type: TypeBlob, Nth: 11, VLEncoded: true, Signing: true}
+	SFMemoType                                        = &Field{Name: "MemoType", Type: TypeBlob, Nth: 12, VLEncoded: true, Signing: true}
+	SFMemoData                                        = &Field{Name: "MemoData", Type: TypeBlob, Nth: 13, VLEncoded: true, Signing: true}
+	SFMemoFormat                                      = &Field{Name: "MemoFormat", Type: TypeBlob, Nth: 14, VLEncoded: true, Signing: true}
+	SFFulfillment                                     = &Field{Name: "Fulfillment", Type: TypeBlob, Nth: 16, VLEncoded: true, Signing: true}
+	SFCondition                                       = &Field{Name: "Condition", Type: TypeBlob, Nth: 17, VLEncoded: true, Signing: true}
+	SFMasterSignature                                 = &Field{Name: "MasterSignature", Type: TypeBlob, Nth: 18, VLEncoded: true, Signing: false}
+	SFUNLModifyValidator                              = &Field{Name: "UNLModifyValidator", Type: TypeBlob, Nth: 19, VLEncoded: true, Signing: true}
+	SFValidatorToDisable                              = &Field{Name: "ValidatorToDisable", Type: TypeBlob, Nth: 20, VLEncoded: true, Signing: true}
+	SFValidatorToReEnable                             = &Field{Name: "ValidatorToReEnable", Type: TypeBlob, Nth: 21, VLEncoded: true, Signing: true}
+	SFHookStateData                                   = &Field{Name: "HookStateData", Type: TypeBlob, Nth: 22, VLEncoded: true, Signing: true}
+	SFHookReturnString                                = &Field{Name: "HookReturnString", Type: TypeBlob, Nth: 23, VLEncoded: true, Signing: true}
+	SFHookParameterName                               = &Field{Name: "HookParameterName", Type: TypeBlob, Nth: 24, VLEncoded: true, Signing: true}
+	SFHookParameterValue                              = &Field{Name: "HookParameterValue", Type: TypeBlob, Nth: 25, VLEncoded: true, Signing: true}
+	SFDIDDocument                                     = &Field{Name: "DIDDocument", Type: TypeBlob, Nth: 26, VLEncoded: true, Signing: true}
+	SFData                                            = &Field{Name: "Data", Type: TypeBlob, Nth: 27, VLEncoded: true, Signing: true}
+	SFAssetClass                                      = &Field{Name: "AssetClass", Type: TypeBlob, Nth: 28, VLEncoded: true, Signing: true}
+	SFProvider                                        = &Field{Name: "Provider", Type: TypeBlob, Nth: 29, VLEncoded: true, Signing: true}
+	SFMPTokenMetadata                                 = &Field{Name: "MPTokenMetadata", Type: TypeBlob, Nth: 30, VLEncoded: true, Signing: true}
+	SFCredentialType                                  = &Field{Name: "CredentialType", Type: TypeBlob, Nth: 31, VLEncoded: true, Signing: true}
+	SFAccount                                         = &Field{Name: "Account", Type: TypeAccountID, Nth: 1, VLEncoded: true, Signing: true}
+	SFOwner                                           = &Field{Name: "Owner", Type: TypeAccountID, Nth: 2, VLEncoded: true, Signing: true}
+	SFDestination                                     = &Field{Name: "Destination", Type: TypeAccountID, Nth: 3, VLEncoded: true, Signing: true}
+	SFIssuer                                          = &Field{Name: "Issuer", Type: TypeAccountID, Nth: 4, VLEncoded: true, Signing: true}
+	SFAuthorize                                       = &Field{Name: "Authorize", Type: TypeAccountID, Nth: 5, VLEncoded: true, Signing: true}
+	SFUnauthorize                                     = &Field{Name: "Unauthorize", Type: TypeAccountID, Nth: 6, VLEncoded: true, Signing: true}
+	SFRegularKey                                      = &Field{Name: "RegularKey", Type: TypeAccountID, Nth: 8, VLEncoded: true, Signing: true}
+	SFNFTokenMinter                                   = &Field{Name: "NFTokenMinter", Type: TypeAccountID, Nth: 9, VLEncoded: true, Signing: true}
+	SFEmitCallback                                    = &Field{Name: "EmitCallback", Type: TypeAccountID, Nth: 10, VLEncoded: true, Signing: true}
+	SFHolder                                          = &Field{Name: "Holder", Type: TypeAccountID, Nth: 11, VLEncoded: true, Signing: true}
+	SFDelegate                                        = &Field{Name: "Delegate", Type: TypeAccountID, Nth: 12, VLEncoded: true, Signing: true}
+	SFHookAccount                                     = &Field{Name: "HookAccount", Type: TypeAccountID, Nth: 16, VLEncoded: true, Signing: true}
+	SFOtherChainSource                                = &Field{Name: "OtherChainSource", Type: TypeAccountID, Nth: 18, VLEncoded: true, Signing: true}
+	SFOtherChainDestination                           = &Field{Name: "OtherChainDestination", Type: TypeAccountID, Nth: 19, VLEncoded: true, Signing: true}
+	SFAttestationSignerAccount                        = &Field{Name: "AttestationSignerAccount", Type: TypeAccountID, Nth: 20, VLEncoded: true, Signing: true}
+	SFAttestationRewardAccount                        = &Field{Name: "AttestationRewardAccount", Type: TypeAccountID, Nth: 21, VLEncoded: true, Signing: true}
+	SFLockingChainDoor                                = &Field{Name: "LockingChainDoor", Type: TypeAccountID, Nth: 22, VLEncoded: true, Signing: true}
+	SFIssuingChainDoor                                = &Field{Name: "IssuingChainDoor", Type: TypeAccountID, Nth: 23, VLEncoded: true, Signing: true}
+	SFSubject                                         = &Field{Name: "Subject", Type: TypeAccountID, Nth: 24, VLEncoded: true, Signing: true}
+	SFBorrower                                        = &Field{Name: "Borrower", Type: TypeAccountID, Nth: 25, VLEncoded: true, Signing: true}
+	SFCounterparty                                    = &Field{Name: "Counterparty", Type: TypeAccountID, Nth: 26, VLEncoded: true, Signing: true}
+	SFNumber                                          = &Field{Name: "Number", Type: TypeNumber, Nth: 1, VLEncoded: false, Signing: true}
+	SFAssetsAvailable                                 = &Field{Name: "AssetsAvailable", Type: TypeNumber, Nth: 2, VLEncoded: false, Signing: true}
+	SFAssetsMaximum                                   = &Field{Name: "AssetsMaximum", Type: TypeNumber, Nth: 3, VLEncoded: false, Signing: true}
+	SFAssetsTotal                                     = &Field{Name: "AssetsTotal", Type: TypeNumber, Nth: 4, VLEncoded: false, Signing: true}
+	SFLossUnrealized                                  = &Field{Name: "LossUnrealized", Type: TypeNumber, Nth: 5, VLEncoded: false, Signing: true}
+	SFDebtTotal                                       = &Field{Name: "DebtTotal", Type: TypeNumber, Nth: 6, VLEncoded: false, Signing: true}
+	SFDebtMaximum                                     = &Field{Name: "DebtMaximum", Type: TypeNumber, Nth: 7, VLEncoded: false, Signing: true}
+	SFCoverAvailable                                  = &Field{Name: "CoverAvailable", Type: TypeNumber, Nth: 8, VLEncoded: false, Signing: true}
+	SFLoanOriginationFee                              = &Field{Name: "LoanOriginationFee", Type: TypeNumber, Nth: 9, VLEncoded: false, Signing: true}
+	SFLoanServiceFee                                  = &Field{Name: "LoanServiceFee", Type: TypeNumber, Nth: 10, VLEncoded: false, Signing: true}
+	SFLatePaymentFee                                  = &Field{Name: "LatePaymentFee", Type: TypeNumber, Nth: 11, VLEncoded: false, Signing: true}
+	SFClosePaymentFee                                 = &Field{Name: "ClosePaymentFee", Type: TypeNumber, Nth: 12, VLEncoded: false, Signing: true}
+	SFPrincipalOutstanding                            = &Field{Name: "PrincipalOutstanding", Type: TypeNumber, Nth: 13, VLEncoded: false, Signing: true}
+	SFPrincipalRequested                              = &Field{Name: "PrincipalRequested", Type: TypeNumber, Nth: 14, VLEncoded: false, Signing: true}
+	SFTotalValueOutstanding                           = &Field{Name: "TotalValueOutstanding", Type: TypeNumber, Nth: 15, VLEncoded: false, Signing: true}
+	SFPeriodicPayment                                 = &Field{Name: "PeriodicPayment", Type: TypeNumber, Nth: 16, VLEncoded: false, Signing: true}
+	SFManagementFeeOutstanding                        = &Field{Name: "ManagementFeeOutstanding", Type: TypeNumber, Nth: 17, VLEncoded: false, Signing: true}
+	SFLoanScale                                       = &Field{Name: "LoanScale", Type: TypeInt32, Nth: 1, VLEncoded: false, Signing: true}
+	SFObjectEndMarker                                 = &Field{Name: "ObjectEndMarker", Type: TypeSTObject, Nth: 1, VLEncoded: false, Signing: true}
+	SFTransactionMetaData                             = &Field{Name: "TransactionMetaData", Type: TypeSTObject, Nth: 2, VLEncoded: false, Signing: true}
+	SFCreatedNode                                     = &Field{Name: "CreatedNode", Type: TypeSTObject, Nth: 3, VLEncoded: false, Signing: true}
+	SFDeletedNode                                     = &Field{Name: "DeletedNode", Type: TypeSTObject, Nth: 4, VLEncoded: false, Signing: true}
+	SFModifiedNode                                    = &Field{Name: "ModifiedNode", Type: TypeSTObject, Nth: 5, VLEncoded: false, Signing: true}
+	SFPreviousFields                                  = &Field{Name: "PreviousFields", Type: TypeSTObject, Nth: 6, VLEncoded: false, Signing: true}
+	SFFinalFields                                     = &Field{Name: "FinalFields", Type: TypeSTObject, Nth: 7, VLEncoded: false, Signing: true}
+	SFNewFields                                       = &Field{Name: "NewFields", Type: TypeSTObject, Nth: 8, VLEncoded: false, Signing: true}
+	SFTemplateEntry                                   = &Field{Name: "TemplateEntry", Type: TypeSTObject, Nth: 9, VLEncoded: false, Signing: true}
+	SFMemo                                            = &Field{Name: "Memo", Type: TypeSTObject, Nth: 10, VLEncoded: false, Signing: true}
+	SFSignerEntry                                     = &Field{Name: "SignerEntry", Type: TypeSTObject, Nth: 11, VLEncoded: false, Signing: true}
+	SFNFToken                                         = &Field{Name: "NFToken", Type: TypeSTObject, Nth: 12, VLEncoded: false, Signing: true}
+	SFEmitDetails                                     = &Field{Name: "EmitDetails", Type: TypeSTObject, Nth: 13, VLEncoded: false, Signing: true}
+	SFHook                                            = &Field{Name: "Hook", Type: TypeSTObject, Nth: 14, VLEncoded: false, Signing: true}
+	SFPermission                                      = &Field{Name: "Permission", Type: TypeSTObject, Nth: 15, VLEncoded: false, Signing: true}
+	SFSigner                                          = &Field{Name: "Signer", Type: TypeSTObject, Nth: 16, VLEncoded: false, Signing: true}
+	SFMajority                                        = &Field{Name: "Majority", Type: TypeSTObject, Nth: 18, VLEncoded: false, Signing: true}
+	SFDisabledValidator                               = &Field{Name: "DisabledValidator", Type: TypeSTObject, Nth: 19, VLEncoded: false, Signing: true}
+	SFEmittedTxn                                      = &Field{Name: "EmittedTxn", Type: TypeSTObject, Nth: 20, VLEncoded: false, Signing: true}
+	SFHookExecution                                   = &Field{Name: "HookExecution", Type: TypeSTObject, Nth: 21, VLEncoded: false, Signing: true}
+	SFHookDefinition                                  = &Field{Name: "HookDefinition", Type: TypeSTObject, Nth: 22, VLEncoded: false, Signing: true}
+	SFHookParameter                                   = &Field{Name: "HookParameter", Type: TypeSTObject, Nth: 23, VLEncoded: false, Signing: true}
+	SFHookGrant                                       = &Field{Name: "HookGrant", Type: TypeSTObject, Nth: 24, VLEncoded: false, Signing: true}
+	SFVoteEntry                                       = &Field{Name: "VoteEntry", Type: TypeSTObject, Nth: 25, VLEncoded: false, Signing: true}
+	SFAuctionSlot                                     = &Field{Name: "AuctionSlot", Type: TypeSTObject, Nth: 26, VLEncoded: false, Signing: true}
+	SFAuthAccount                                     = &Field{Name: "AuthAccount", Type: TypeSTObject, Nth: 27, VLEncoded: false, Signing: true}
+	SFXChainClaimProofSig                             = &Field{Name: "XChainClaimProofSig", Type: TypeSTObject, Nth: 28, VLEncoded: false, Signing: true}
+	SFXChainCreateAccountProofSig                     = &Field{Name: "XChainCreateAccountProofSig", Type: TypeSTObject, Nth: 29, VLEncoded: false, Signing: true}
+	SFXChainClaimAttestationCollectionElement         = &Field{Name: "XChainClaimAttestationCollectionElement", Type: TypeSTObject, Nth: 30, VLEncoded: false, Signing: true}
+	SFXChainCreateAccountAttestationCollectionElement = &Field{Name: "XChainCreateAccountAttestationCollectionElement", Type: TypeSTObject, Nth: 31, VLEncoded: false, Signing: true}
+	SFPriceData                                       = &Field{Name: "PriceData", Type: TypeSTObject, Nth: 32, VLEncoded: false, Signing: true}
+	SFCredential                                      = &Field{Name: "Credential", Type: TypeSTObject, Nth: 33, VLEncoded: false, Signing: true}
+	SFRawTransaction                                  = &Field{Name: "RawTransaction", Type: TypeSTObject, Nth: 34, VLEncoded: false, Signing: true}
+	SFBatchSigner                                     = &Field{Name: "BatchSigner", Type: TypeSTObject, Nth: 35, VLEncoded: false, Signing: true}
+	SFBook                                            = &Field{Name: "Book", Type: TypeSTObject, Nth: 36, VLEncoded: false, Signing: true}
+	SFCounterpartySignature                           = &Field{Name: "CounterpartySignature", Type: TypeSTObject, Nth: 37, VLEncoded: false, Signing: false}
+	SFArrayEndMarker                                  = &Field{Name: "ArrayEndMarker", Type: TypeSTArray, Nth: 1, VLEncoded: false, Signing: true}
+	SFSigners                                         = &Field{Name: "Signers", Type: TypeSTArray, Nth: 3, VLEncoded: false, Signing: false}
+	SFSignerEntries                                   = &Field{Name: "SignerEntries", Type: TypeSTArray, Nth: 4, VLEncoded: false, Signing: true}
+	SFTemplate                                        = &Field{Name: "Template", Type: TypeSTArray, Nth: 5, VLEncoded: false, Signing: true}
+	SFNecessary                                       = &Field{Name: "Necessary", Type: TypeSTArray, Nth: 6, VLEncoded: false, Signing: true}
+	SFSufficient                                      = &Field{Name: "Sufficient", Type: TypeSTArray, Nth: 7, VLEncoded: false, Signing: true}
+	SFAffectedNodes                                   = &Field{Name: "AffectedNodes", Type: TypeSTArray, Nth: 8, VLEncoded: false, Signing: true}
+	SFMemos                                           = &Field{Name: "Memos", Type: TypeSTArray, Nth: 9, VLEncoded: false, Signing: true}
+	SFNFTokens                                        = &Field{Name: "NFTokens", Type: TypeSTArray, Nth: 10, VLEncoded: false, Signing: true}
+	SFHooks                                           = &Field{Name: "Hooks", Type: TypeSTArray, Nth: 11, VLEncoded: false, Signing: true}
+	SFVoteSlots                                       = &Field{Name: "VoteSlots", Type: TypeSTArray, Nth: 12, VLEncoded: false, Signing: true}
+	SFAdditionalBooks                                 = &Field{Name: "AdditionalBooks", Type: TypeSTArray, Nth: 13, VLEncoded: false, Signing: true}
+	SFMajorities                                      = &Field{Name: "Majorities", Type: TypeSTArray, Nth: 16, VLEncoded: false, Signing: true}
+	SFDisabledValidators                              = &Field{Name: "DisabledValidators", Type: TypeSTArray, Nth: 17, VLEncoded: false, Signing: true}
+	SFHookExecutions                                  = &Field{Name: "HookExecutions", Type: TypeSTArray, Nth: 18, VLEncoded: false, Signing: true}
+	SFHookParameters                                  = &Field{Name: "HookParameters", Type: TypeSTArray, Nth: 19, VLEncoded: false, Signing: true}
+	SFHookGrants                                      = &Field{Name: "HookGrants", Type: TypeSTArray, Nth: 20, VLEncoded: false, Signing: true}
+	SFXChainClaimAttestations                         = &Field{Name: "XChainClaimAttestations", Type: TypeSTArray, Nth: 21, VLEncoded: false, Signing: true}
+	SFXChainCreateAccountAttestations                 = &Field{Name: "XChainCreateAccountAttestations", Type: TypeSTArray, Nth: 22, VLEncoded: false, Signing: true}
+	SFPriceDataSeries                                 = &Field{Name: "PriceDataSeries", Type: TypeSTArray, Nth: 24, VLEncoded: false, Signing: true}
+	SFAuthAccounts                                    = &Field{Name: "AuthAccounts", Type: TypeSTArray, Nth: 25, VLEncoded: false, Signing: true}
+	SFAuthorizeCredentials                            = &Field{Name: "AuthorizeCredentials", Type: TypeSTArray, Nth: 26, VLEncoded: false, Signing: true}
+	SFUnauthorizeCredentials                          = &Field{Name: "UnauthorizeCredentials", Type: TypeSTArray, Nth: 27, VLEncoded: false, Signing: true}
+	SFAcceptedCredentials                             = &Field{Name: "AcceptedCredentials", Type: TypeSTArray, Nth: 28, VLEncoded: false, Signing: true}
+	SFPermissions                                     = &Field{Name: "Permissions", Type: TypeSTArray, Nth: 29, VLEncoded: false, Signing: true}
+	SFRawTransactions                                 = &Field{Name: "RawTransactions", Type: TypeSTArray, Nth: 30, VLEncoded: false, Signing: true}
+	SFBatchSigners                                    = &Field{Name: "BatchSigners", Type: TypeSTArray, Nth: 31, VLEncoded: false, Signing: false}
+	SFCloseResolution                                 = &Field{Name: "CloseResolution", Type: TypeUInt8, Nth: 1, VLEncoded: false, Signing: true}
+	SFMethod                                          = &Field{Name: "Method", Type: TypeUInt8, Nth: 2, VLEncoded: false, Signing: true}
+	SFTransactionResult                               = &Field{Name: "TransactionResult", Type: TypeUInt8, Nth: 3, VLEncoded: false, Signing: true}
+	SFScale                                           = &Field{Name: "Scale", Type: TypeUInt8, Nth: 4, VLEncoded: false, Signing: true}
+	SFAssetScale                                      = &Field{Name: "AssetScale", Type: TypeUInt8, Nth: 5, VLEncoded: false, Signing: true}
+	SFTickSize                                        = &Field{Name: "TickSize", Type: TypeUInt8, Nth: 16, VLEncoded: false, Signing: true}
+	SFUNLModifyDisabling                              = &Field{Name: "UNLModifyDisabling", Type: TypeUInt8, Nth: 17, VLEncoded: false, Signing: true}
+	SFHookResult                                      = &Field{Name: "HookResult", Type: TypeUInt8, Nth: 18, VLEncoded: false, Signing: true}
+	SFWasLockingChainSend                             = &Field{Name: "WasLockingChainSend", Type: TypeUInt8, Nth: 19, VLEncoded: false, Signing: true}
+	SFWithdrawalPolicy                                = &Field{Name: "WithdrawalPolicy", Type: TypeUInt8, Nth: 20, VLEncoded: false, Signing: true}
+	SFTakerPaysCurrency                               = &Field{Name: "TakerPaysCurrency", Type: TypeHash160, Nth: 1, VLEncoded: false, Signing: true}
+	SFTakerPaysIssuer                                 = &Field{Name: "TakerPaysIssuer", Type: TypeHash160, Nth: 2, VLEncoded: false, Signing: true}
+	SFTakerGetsCurrency                               = &Field{Name: "TakerGetsCurrency", Type: TypeHash160, Nth: 3, VLEncoded: false, Signing: true}
+	SFTakerGetsIssuer                                 = &Field{Name: "TakerGetsIssuer", Type: TypeHash160, Nth: 4, VLEncoded: false, Signing: true}
+	SFPaths                                           = &Field{Name: "Paths", Type: TypePathSet, Nth: 1, VLEncoded: false, Signing: true}
+	SFIndexes                                         = &Field{Name: "Indexes", Type: TypeVector256, Nth: 1, VLEncoded: true, Signing: true}
+	SFHashes                                          = &Field{Name: "Hashes", Type: TypeVector256, Nth: 2, VLEncoded: true, Signing: true}
+	SFAmendments                                      = &Field{Name: "Amendments", Type: TypeVector256, Nth: 3, VLEncoded: true, Signing: true}
+	SFNFTokenOffers                                   = &Field{Name: "NFTokenOffers", Type: TypeVector256, Nth: 4, VLEncoded: true, Signing: true}
+	SFCredentialIDs                                   = &Field{Name: "CredentialIDs", Type: TypeVector256, Nth: 5, VLEncoded: true, Signing: true}
+	SFMPTokenIssuanceID                               = &Field{Name: "MPTokenIssuanceID", Type: TypeHash192, Nth: 1, VLEncoded: false, Signing: true}
+	SFShareMPTID                                      = &Field{Name: "ShareMPTID", Type: TypeHash192, Nth: 2, VLEncoded: false, Signing: true}
+	SFLockingChainIssue                               = &Field{Name: "LockingChainIssue", Type: TypeIssue, Nth: 1, VLEncoded: false, Signing: true}
+	SFIssuingChainIssue                               = &Field{Name: "IssuingChainIssue", Type: TypeIssue, Nth: 2, VLEncoded: false, Signing: true}
+	SFAsset                                           = &Field{Name: "Asset", Type: TypeIssue, Nth: 3, VLEncoded: false, Signing: true}
+	SFAsset2                                          = &Field{Name: "Asset2", Type: TypeIssue, Nth: 4, VLEncoded: false, Signing: true}
+	SFXChainBridge                                    = &Field{Name: "XChainBridge", Type: TypeXChainBridge, Nth: 1, VLEncoded: false, Signing: true}
+	SFBaseAsset                                       = &Field{Name: "BaseAsset", Type: TypeCurrency, Nth: 1, VLEncoded: false, Signing: true}
+	SFQuoteAsset                                      = &Field{Name: "QuoteAsset", Type: TypeCurrency, Nth: 2, VLEncoded: false, Signing: true}
+)
+
+// allFields lists every serialized field.
+var allFields = [...]*Field{
+	SFLedgerEntryType,
+	SFTransactionType,
+	SFSignerWeight,
+	SFTransferFee,
+	SFTradingFee,
+	SFDiscountedFee,
+	SFVersion,
+	SFHookStateChangeCount,
+	SFHookEmitCount,
+	SFHookExecutionIndex,
+	SFHookApiVersion,
+	SFLedgerFixType,
+	SFManagementFeeRate,
+	SFNetworkID,
+	SFFlags,
+	SFSourceTag,
+	SFSequence,
+	SFPreviousTxnLgrSeq,
+	SFLedgerSequence,
+	SFCloseTime,
+	SFParentCloseTime,
+	SFSigningTime,
+	SFExpiration,
+	SFTransferRate,
+	SFWalletSize,
+	SFOwnerCount,
+	SFDestinationTag,
+	SFLastUpdateTime,
+	SFHighQualityIn,
+	SFHighQualityOut,
+	SFLowQualityIn,
+	SFLowQualityOut,
+	SFQualityIn,
+	SFQualityOut,
+	SFStampEscrow,
+	SFBondAmount,
+	SFLoadFee,
+	SFOfferSequence,
+	SFFirstLedgerSequence,
+	SFLastLedgerSequence,
+	SFTransactionIndex,
+	SFOperationLimit,
+	SFReferenceFeeUnits,
+	SFReserveBase,
+	SFReserveIncrement,
+	SFSetFlag,
+	SFClearFlag,
+	SFSignerQuorum,
+	SFCancelAfter,
+	SFFinishAfter,
+	SFSignerListID,
+	SFSettleDelay,
+	SFTicketCount,
+	SFTicketSequence,
+	SFNFTokenTaxon,
+	SFMintedNFTokens,
+	SFBurnedNFTokens,
+	SFHookStateCount,
+	SFEmitGeneration,
+	SFVoteWeight,
+	SFFirstNFTokenSequence,
+	SFOracleDocumentID,
+	SFPermissionValue,
+	SFMutableFlags,
+	SFStartDate,
+	SFPaymentInterval,
+	SFGracePeriod,
+	SFPreviousPaymentDate,
+	SFNextPaymentDueDate,
+	SFPaymentRemaining,
+	SFPaymentTotal,
+	SFLoanSequence,
+	SFCoverRateMinimum,
+	SFCoverRateLiquidation,
+	SFOverpaymentFee,
+	SFInterestRate,
+	SFLateInterestRate,
+	SFCloseInterestRate,
+	SFOverpaymentInterestRate,
+	SFIndexNext,
+	SFIndexPrevious,
+	SFBookNode,
+	SFOwnerNode,
+	SFBaseFee,
+	SFExchangeRate,
+	SFLowNode,
+	SFHighNode,
+	SFDestinationNode,
+	SFCookie,
+	SFServerVersion,
+	SFNFTokenOfferNode,
+	SFEmitBurden,
+	SFHookOn,
+	SFHookInstructionCount,
+	SFHookReturnCode,
+	SFReferenceCount,
+	SFXChainClaimID,
+	SFXChainAccountCreateCount,
+	SFXChainAccountClaimCount,
+	SFAssetPrice,
+	SFMaximumAmount,
+	SFOutstandingAmount,
+	SFMPTAmount,
+	SFIssuerNode,
+	SFSubjectNode,
+	SFLockedAmount,
+	SFVaultNode,
+	SFLoanBrokerNode,
+	SFEmailHash,
+	SFLedgerHash,
+	SFParentHash,
+	SFTransactionHash,
+	SFAccountHash,
+	SFPreviousTxnID,
+	SFLedgerIndex,
+	SFWalletLocator,
+	SFRootIndex,
+	SFAccountTxnID,
+	SFNFTokenID,
+	SFEmitParentTxnID,
+	SFEmitNonce,
+	SFEmitHookHash,
+	SFAMMID,
+	SFBookDirectory,
+	SFInvoiceID,
+	SFNickname,
+	SFAmendment,
+	SFDigest,
+	SFChannel,
+	SFConsensusHash,
+	SFCheckID,
+	SFValidatedHash,
+	SFPreviousPageMin,
+	SFNextPageMin,
+	SFNFTokenBuyOffer,
+	SFNFTokenSellOffer,
+	SFHookStateKey,
+	SFHookHash,
+	SFHookNamespace,
+	SFHookSetTxnID,
+	SFDomainID,
+	SFVaultID,
+	SFParentBatchID,
+	SFLoanBrokerID,
+	SFLoanID,
+	SFAmount,
+	SFBalance,
+	SFLimitAmount,
+	SFTakerPays,
+	SFTakerGets,
+	SFLowLimit,
+	SFHighLimit,
+	SFFee,
+	SFSendMax,
+	SFDeliverMin,
+	SFAmount2,
+	SFBidMin,
+	SFBidMax,
+	SFMinimumOffer,
+	SFRippleEscrow,
+	SFDeliveredAmount,
+	SFNFTokenBrokerFee,
+	SFBaseFeeDrops,
+	SFReserveBaseDrops,
+	SFReserveIncrementDrops,
+	SFLPTokenOut,
+	SFLPTokenIn,
+	SFEPrice,
+	SFPrice,
+	SFSignatureReward,
+	SFMinAccountCreateAmount,
+	SFLPTokenBalance,
+	SFPublicKey,
+	SFMessageKey,
+	SFSigningPubKey,
+	SFTxnSignature,
+	SFURI,
+	SFSignature,
+	SFDomain,
+	SFFundCode,
+	SFRemoveCode,
+	SFExpireCode,
+	SFCreateCode,
+	SFMemoType,
+	SFMemoData,
+	SFMemoFormat,
+	SFFulfillment,
+	SFCondition,
+	SFMasterSignature,
+	SFUNLModifyValidator,
+	SFValidatorToDisable,
+	SFValidatorToReEnable,
+	SFHookStateData,
+	SFHookReturnString,
+	SFHookParameterName,
+	SFHookParameterValue,
+	SFDIDDocument,
+	SFData,
+	SFAssetClass,
+	SFProvider,
+	SFMPTokenMetadata,
+	SFCredentialType,
+	SFAccount,
+	SFOwner,
+	SFDestination,
+	SFIssuer,
+	SFAuthorize,
+	SFUnauthorize,
+	SFRegularKey,
+	SFNFTokenMinter,
+	SFEmitCallback,
+	SFHolder,
+	SFDelegate,
+	SFHookAccount,
+	SFOtherChainSource,
+	SFOtherChainDestination,
+	SFAttestationSignerAccount,
+	SFAttestationRewardAccount,
+	SFLockingChainDoor,
+	SFIssuingChainDoor,
+	SFSubject,
+	SFBorrower,
+	SFCounterparty,
+	SFNumber,
+	SFAssetsAvailable,
+	SFAssetsMaximum,
+	SFAssetsTotal,
+	SFLossUnrealized,
+	SFDebtTotal,
+	SFDebtMaximum,
+	SFCoverAvailable,
+	SFLoanOriginationFee,
+	SFLoanServiceFee,
+	SFLatePaymentFee,
+	SFClosePaymentFee,
+	SFPrincipalOutstanding,
+	SFPrincipalRequested,
+	SFTotalValueOutstanding,
+	SFPeriodicPayment,
+	SFManagementFeeOutstanding,
+	SFLoanScale,
+	SFObjectEndMarker,
+	SFTransactionMetaData,
+	SFCreatedNode,
+	SFDeletedNode,
+	SFModifiedNode,
+	SFPreviousFields,
+	SFFinalFields,
+	SFNewFields,
+	SFTemplateEntry,
+	SFMemo,
+	SFSignerEntry,
+	SFNFToken,
+	SFEmitDetails,
+	SFHook,
+	SFPermission,
+	SFSigner,
+	SFMajority,
+	SFDisabledValidator,
+	SFEmittedTxn,
+	SFHookExecution,
+	SFHookDefinition,
+	SFHookParameter,
+	SFHookGrant,
+	SFVoteEntry,
+	SFAuctionSlot,
+	SFAuthAccount,
+	SFXChainClaimProofSig,
+	SFXChainCreateAccountProofSig,
+	SFXChainClaimAttestationCollectionElement,
+	SFXChainCreateAccountAttestationCollectionElement,
+	SFPriceData,
+	SFCredential,
+	SFRawTransaction,
+	SFBatchSigner,
+	SFBook,
+	SFCounterpartySignature,
+	SFArrayEndMarker,
+	SFSigners,
+	SFSignerEntries,
+	SFTemplate,
+	SFNecessary,
+	SFSufficient,
+	SFAffectedNodes,
+	SFMemos,
+	SFNFTokens,
+	SFHooks,
+	SFVoteSlots,
+	SFAdditionalBooks,
+	SFMajorities,
+	SFDisabledValidators,
+	SFHookExecutions,
+	SFHookParameters,
+	SFHookGrants,
+	SFXChainClaimAttestations,
+	SFXChainCreateAccountAttestations,
+	SFPriceDataSeries,
+	SFAuthAccounts,
+	SFAuthorizeCredentials,
+	SFUnauthorizeCredentials,
+	SFAcceptedCredentials,
+	SFPermissions,
+	SFRawTransactions,
+	SFBatchSigners,
+	SFCloseResolution,
+	SFMethod,
+	SFTransactionResult,
+	SFScale,
+	SFAssetScale,
+	SFTickSize,
+	SFUNLModifyDisabling,
+	SFHookResult,
+	SFWasLockingChainSend,
+	SFWithdrawalPolicy,
+	SFTakerPaysCurrency,
+	SFTakerPaysIssuer,
+	SFTakerGetsCurrency,
+	SFTakerGetsIssuer,
+	SFPaths,
+	SFIndexes,
+	SFHashes,
+	SFAmendments,
+	SFNFTokenOffers,
+	SFCredentialIDs,
+	SFMPTokenIssuanceID,
+	SFShareMPTID,
+	SFLockingChainIssue,
+	SFIssuingChainIssue,
+	SFAsset,
+	SFAsset2,
+	SFXChainBridge,
+	SFBaseAsset,
+	SFQuoteAsset,
+}
@@ -0,0 +1,94 @@
+//go:build ignore
+
+// gen_fields generates fields_gen.go from definitions.json. Run it with
+// go generate from the st package directory.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+)
+
+type fieldInfo struct {
+	Nth            int32  `json:"nth"`
+	IsVLEncoded    bool   `json:"isVLEncoded"`
+	IsSerialized   bool   `json:"isSerialized"`
+	IsSigningField bool   `json:"isSigningField"`
+	Type           string `json:"type"`
+}
+
+type field struct {
+	name string
+	info fieldInfo
+	code int32
+}
+
+func main() {
+	raw, err := os.ReadFile("../definitions/definitions.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+	var doc struct {
+		Types  map[string]int32     `json:"TYPES"`
+		Fields [][2]json.RawMessage `json:"FIELDS"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		log.Fatal(err)
+	}
+
+	var fields []field
+	for _, entry := range doc.Fields {
+		var f field
+		if err := json.Unmarshal(entry[0], &f.name); err != nil {
+			log.Fatal(err)
+		}
+		if err := json.Unmarshal(entry[1], &f.info); err != nil {
+			log.Fatal(err)
+		}
+		if !f.info.IsSerialized {
+			continue
+		}
+		code, ok := doc.Types[f.info.Type]
+		if !ok {
+			log.Fatalf("field %s has unknown type %s", f.name, f.info.Type)
+		}
+		f.code = code
+		fields = append(fields, f)
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].code != fields[j].code {
+			return fields[i].code < fields[j].code
+		}
+		return fields[i].info.Nth < fields[j].info.Nth
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_fields.go from definitions.json; DO NOT EDIT.\n\n")
+	buf.WriteString("package st\n\n")
+	buf.WriteString("// Serialized fields, in canonical order.\n")
+	buf.WriteString("var (\n")
+	for _, f := range fields {
+		fmt.Fprintf(&buf, "\tSF%s = &Field{Name: %q, Type: Type%s, Nth: %d, VLEncoded: %t, Signing: %t}\n",
+			f.name, f.name, f.info.Type, f.info.Nth, f.info.IsVLEncoded, f.info.IsSigningField)
+	}
+	buf.WriteString(")\n\n")
+	buf.WriteString("// allFields lists every serialized field.\n")
+	buf.WriteString("var allFields = [...]*Field{\n")
+	for _, f := range fields {
+		fmt.Fprintf(&buf, "\tSF%s,\n", f.name)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("fields_gen.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
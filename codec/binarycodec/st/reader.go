@@ -0,0 +1,420 @@
+package st
+
+import (
+	"encoding/binary"
+)
+
+const (
+	objectEndMarker = 0xE1
+	arrayEndMarker  = 0xF1
+)
+
+// Reader iterates over the fields of a serialized object. Values are
+// sub-slices of the input, so reading does not allocate and values stay
+// valid for as long as the input does.
+//
+//	r := st.NewReader(data)
+//	for r.Next() {
+//		switch r.Field() {
+//		case st.SFSequence:
+//			seq = r.UInt32()
+//		case st.SFBalance:
+//			balance = r.Amount()
+//		}
+//	}
+//	if err := r.Err(); err != nil {
+//		return err
+//	}
+//
+// The typed accessors decode the current field. Calling one that does not
+// match the field's type records a TypeMismatchError, which stops the
+// iteration and is reported by Err.
+type Reader struct {
+	data  []byte
+	off   int
+	field *Field
+	raw   []byte
+	value []byte
+	err   error
+}
+
+// NewReader returns a Reader over the fields of data. For inner objects and
+// arrays, pass the Value of the enclosing field.
+func NewReader(data []byte) Reader {
+	return Reader{data: data}
+}
+
+// Next advances to the next field, returning false at the end of the object
+// or on error.
+func (r *Reader) Next() bool {
+	r.field, r.raw, r.value = nil, nil, nil
+	if r.err != nil || r.off >= len(r.data) {
+		return false
+	}
+	f, size, valueStart, err := scanField(r.data[r.off:])
+	if err != nil {
+		r.err = err
+		return false
+	}
+	r.field = f
+	r.raw = r.data[r.off : r.off+size : r.off+size]
+	r.value = r.raw[valueStart:]
+	if f.Type == TypeSTObject || f.Type == TypeSTArray {
+		// Strip the end marker: the value is the inner fields.
+		r.value = r.value[:len(r.value)-1]
+	}
+	r.off += size
+	return true
+}
+
+// Err returns the first error encountered.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Field returns the current field.
+func (r *Reader) Field() *Field {
+	return r.field
+}
+
+// Raw returns the complete encoding of the current field: header, length
+// prefix and value, including the end marker of inner objects and arrays.
+func (r *Reader) Raw() []byte {
+	return r.raw
+}
+
+// Value returns the value of the current field without header or length
+// prefix. For inner objects and arrays it is the encoded inner fields.
+func (r *Reader) Value() []byte {
+	return r.value
+}
+
+// check records a TypeMismatchError if the current field is not of type t.
+func (r *Reader) check(t Type) bool {
+	if r.err != nil || r.field == nil {
+		return false
+	}
+	if r.field.Type != t {
+		r.err = &TypeMismatchError{Field: r.field, Want: t}
+		return false
+	}
+	return true
+}
+
+// UInt8 returns the value of the current UInt8 field.
+func (r *Reader) UInt8() uint8 {
+	if !r.check(TypeUInt8) {
+		return 0
+	}
+	return r.value[0]
+}
+
+// UInt16 returns the value of the current UInt16 field.
+func (r *Reader) UInt16() uint16 {
+	if !r.check(TypeUInt16) {
+		return 0
+	}
+	return binary.BigEndian.Uint16(r.value)
+}
+
+// UInt32 returns the value of the current UInt32 field.
+func (r *Reader) UInt32() uint32 {
+	if !r.check(TypeUInt32) {
+		return 0
+	}
+	return binary.BigEndian.Uint32(r.value)
+}
+
+// UInt64 returns the value of the current UInt64 field.
+func (r *Reader) UInt64() uint64 {
+	if !r.check(TypeUInt64) {
+		return 0
+	}
+	return binary.BigEndian.Uint64(r.value)
+}
+
+// Int32 returns the value of the current Int32 field.
+func (r *Reader) Int32() int32 {
+	if !r.check(TypeInt32) {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(r.value))
+}
+
+// Hash128 returns the value of the current Hash128 field.
+func (r *Reader) Hash128() (h [16]byte) {
+	if r.check(TypeHash128) {
+		h = [16]byte(r.value)
+	}
+	return h
+}
+
+// Hash160 returns the value of the current Hash160 field.
+func (r *Reader) Hash160() (h [20]byte) {
+	if r.check(TypeHash160) {
+		h = [20]byte(r.value)
+	}
+	return h
+}
+
+// Hash192 returns the value of the current Hash192 field.
+func (r *Reader) Hash192() (h [24]byte) {
+	if r.check(TypeHash192) {
+		h = [24]byte(r.value)
+	}
+	return h
+}
+
+// Hash256 returns the value of the current Hash256 field.
+func (r *Reader) Hash256() (h [32]byte) {
+	if r.check(TypeHash256) {
+		h = [32]byte(r.value)
+	}
+	return h
+}
+
+// Currency returns the value of the current Currency field.
+func (r *Reader) Currency() (c [20]byte) {
+	if r.check(TypeCurrency) {
+		c = [20]byte(r.value)
+	}
+	return c
+}
+
+// AccountID returns the value of the current AccountID field.
+func (r *Reader) AccountID() (id [20]byte) {
+	if !r.check(TypeAccountID) {
+		return id
+	}
+	if len(r.value) != 20 {
+		r.err = ErrTruncated
+		return id
+	}
+	return [20]byte(r.value)
+}
+
+// Blob returns the value of the current Blob field. The slice aliases the
+// input.
+func (r *Reader) Blob() []byte {
+	if !r.check(TypeBlob) {
+		return nil
+	}
+	return r.value
+}
+
+// Amount returns the value of the current Amount field.
+func (r *Reader) Amount() Amount {
+	if !r.check(TypeAmount) {
+		return Amount{}
+	}
+	a, _ := DecodeAmount(r.value)
+	return a
+}
+
+// Issue returns the value of the current Issue field.
+func (r *Reader) Issue() Issue {
+	if !r.check(TypeIssue) {
+		return Issue{}
+	}
+	is, _ := DecodeIssue(r.value)
+	return is
+}
+
+// Number returns the value of the current Number field.
+func (r *Reader) Number() Number {
+	if !r.check(TypeNumber) {
+		return Number{}
+	}
+	return Number{
+		Mantissa: int64(binary.BigEndian.Uint64(r.value)),
+		Exponent: int32(binary.BigEndian.Uint32(r.value[8:])),
+	}
+}
+
+// XChainBridge returns the value of the current XChainBridge field.
+func (r *Reader) XChainBridge() XChainBridge {
+	if !r.check(TypeXChainBridge) {
+		return XChainBridge{}
+	}
+	x, _ := DecodeXChainBridge(r.value)
+	return x
+}
+
+// Vector256 returns the value of the current Vector256 field.
+func (r *Reader) Vector256() Vector256 {
+	if !r.check(TypeVector256) {
+		return nil
+	}
+	if len(r.value)%32 != 0 {
+		r.err = ErrTruncated
+		return nil
+	}
+	return Vector256(r.value)
+}
+
+// Object returns a Reader over the fields of the current inner object.
+func (r *Reader) Object() Reader {
+	if !r.check(TypeSTObject) {
+		return Reader{}
+	}
+	return NewReader(r.value)
+}
+
+// Array returns a Reader over the elements of the current array. Each
+// element is an inner object field such as SignerEntry.
+func (r *Reader) Array() Reader {
+	if !r.check(TypeSTArray) {
+		return Reader{}
+	}
+	return NewReader(r.value)
+}
+
+// scanField decodes the field at the start of b, returning the field, the
+// size of its complete encoding and the offset of its value.
+func scanField(b []byte) (f *Field, size, valueStart int, err error) {
+	t, nth, n, ok := readHeader(b)
+	if !ok {
+		return nil, 0, 0, ErrInvalidHeader
+	}
+	f = FieldByCode(t, nth)
+	if f == nil {
+		return nil, 0, 0, &UnknownFieldError{Type: t, Nth: nth}
+	}
+	if f == SFObjectEndMarker || f == SFArrayEndMarker {
+		return nil, 0, 0, ErrInvalidHeader
+	}
+	if f.VLEncoded {
+		length, vl, ok := readVL(b[n:])
+		if !ok || n+vl+length > len(b) {
+			return nil, 0, 0, ErrTruncated
+		}
+		return f, n + vl + length, n + vl, nil
+	}
+	length, err := valueSize(f.Type, b[n:])
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return f, n + length, n, nil
+}
+
+// readHeader decodes a field header, returning the type code, field code
+// and header length.
+func readHeader(b []byte) (t Type, nth int32, n int, ok bool) {
+	if len(b) == 0 {
+		return 0, 0, 0, false
+	}
+	t, nth, n = Type(b[0]>>4), int32(b[0]&0x0F), 1
+	if t == 0 {
+		if len(b) <= n || b[n] < 16 {
+			return 0, 0, 0, false
+		}
+		t = Type(b[n])
+		n++
+	}
+	if nth == 0 {
+		if len(b) <= n || b[n] < 16 {
+			return 0, 0, 0, false
+		}
+		nth = int32(b[n])
+		n++
+	}
+	return t, nth, n, true
+}
+
+// readVL decodes a variable-length prefix, returning the value length and
+// prefix length.
+func readVL(b []byte) (length, n int, ok bool) {
+	if len(b) == 0 {
+		return 0, 0, false
+	}
+	b1 := int(b[0])
+	switch {
+	case b1 <= 192:
+		return b1, 1, true
+	case b1 <= 240:
+		if len(b) < 2 {
+			return 0, 0, false
+		}
+		return 193 + (b1-193)*256 + int(b[1]), 2, true
+	case b1 <= 254:
+		if len(b) < 3 {
+			return 0, 0, false
+		}
+		return 12481 + (b1-241)*65536 + int(b[1])*256 + int(b[2]), 3, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// fixedSizes holds the value size of each fixed-width type.
+var fixedSizes = map[Type]int{
+	TypeUInt8:    1,
+	TypeUInt16:   2,
+	TypeUInt32:   4,
+	TypeInt32:    4,
+	TypeUInt64:   8,
+	TypeInt64:    8,
+	TypeUInt96:   12,
+	TypeNumber:   12,
+	TypeHash128:  16,
+	TypeHash160:  20,
+	TypeCurrency: 20,
+	TypeHash192:  24,
+	TypeHash256:  32,
+	TypeUInt384:  48,
+	TypeUInt512:  64,
+}
+
+// valueSize returns the encoded size of a value that is not VL-encoded. The
+// size of inner objects and arrays includes their end marker.
+func valueSize(t Type, b []byte) (int, error) {
+	var size int
+	switch t {
+	case TypeAmount:
+		size = amountSize(b)
+	case TypeIssue:
+		size = issueSize(b)
+	case TypeXChainBridge:
+		n, ok := xchainBridgeSize(b)
+		if !ok {
+			return 0, ErrTruncated
+		}
+		size = n
+	case TypePathSet:
+		n, ok := pathSetSize(b)
+		if !ok {
+			return 0, ErrTruncated
+		}
+		size = n
+	case TypeSTObject:
+		return innerSize(b, objectEndMarker)
+	case TypeSTArray:
+		return innerSize(b, arrayEndMarker)
+	default:
+		n, ok := fixedSizes[t]
+		if !ok {
+			return 0, &UnknownFieldError{Type: t}
+		}
+		size = n
+	}
+	if size == 0 || size > len(b) {
+		return 0, ErrTruncated
+	}
+	return size, nil
+}
+
+// innerSize returns the encoded size of an inner object or array up to and
+// including its end marker.
+func innerSize(b []byte, endMarker byte) (int, error) {
+	for off := 0; off < len(b); {
+		if b[off] == endMarker {
+			return off + 1, nil
+		}
+		_, size, _, err := scanField(b[off:])
+		if err != nil {
+			return 0, err
+		}
+		off += size
+	}
+	return 0, ErrUnterminated
+}
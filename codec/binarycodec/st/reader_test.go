@@ -0,0 +1,277 @@
+package st
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec"
+	"github.com/stretchr/testify/require"
+)
+
+// corpus holds canonical encodings taken from the map-based codec tests.
+var corpus = []string{
+	// Payment with paths and memos
+	"1200002200000000240000034A201B009717BE61400000000098968068400000000000000C69D4564B964A845AC0000000000000000000000000555344000000000069D33B18D53385F8A3185516C2EDA5DEDB8AC5C673210379F17CFA0FFD7518181594BE69FE9A10471D6DE1F4055C6D2746AFD6CF89889E74473045022100D55ED1953F860ADC1BC5CD993ABB927F48156ACA31C64737865F4F4FF6D015A80220630704D2BD09C8E99F26090C25F11B28F5D96A1350454402C2CED92B39FFDBAF811469D33B18D53385F8A3185516C2EDA5DEDB8AC5C6831469D33B18D53385F8A3185516C2EDA5DEDB8AC5C6F9EA7C06636C69656E747D077274312E312E31E1F1011201F3B1997562FD742B54D4EBDEA1D6AEA3D4906B8F100000000000000000000000000000000000000000FF014B4E9C06F24296074F7BC48F92A97916C6DC5EA901DD39C650A96EDA48334E70CC4A85B8B2E8502CD310000000000000000000000000000000000000000000",
+	// OfferCreate
+	"120007220008000024001ABED82A2380BF2C2019001ABED764D55920AC9391400000000000000000000000000055534400000000000A20B3C85F482532A9578DBB3950B85CA06594D165400000037E11D60068400000000000000A732103EE83BB432547885C219634A1BC407A9DB0474145D69737D09CCDC63E1DEE7FE3744630440220143759437C04F7B61F012563AFE90D8DAFC46E86035E1D965A9CED282C97D4CE02204CFD241E86F17E011298FC1A39B63386C74306A5DE047E213B0F29EFA4571C2C8114DD76483FACDEE26E60D8A586BB58D09F27045C46",
+	// EscrowFinish with memos
+	"1200022280000000240000000120190000000B68400000000000277573210268D79CD579D077750740FA18A2370B7C2018B2714ECE70BA65C38D223E79BC9C74473045022100F06FB54049D6D50142E5CF2E2AC21946AF305A13E2A2D4BA881B36484DD01A540220311557EC8BEF536D729605A4CB4D4DC51B1E37C06C93434DD5B7651E1E2E28BF811452C7F01AD13B3CA9C1D133FA8F3482D2EF08FA7D82145A380FBD236B6A1CD14B939AD21101E5B6B6FFA2F9EA7D0F04C4D46544659A2D58525043686174E1F1",
+	// IOU amount
+	"6680000000000000000000000000000000000000004C5543000000000020A85019EA62B48F79EB67273B797EB916438FA4",
+	// Vector256
+	"03134073734B611DDA23D3F5F62E20A173B78AB8406AC5015094DA53F53D39B9EDB06C73734B611DDA23D3F5F62E20A173B78AB8406AC5015094DA53F53D39B9EDB06C",
+	// Hash128, Hash160, Hash256
+	"4173734B611DDA23D3F5F62E20A173B78A",
+	"011173734B611DDA23D3F5F62E20A173B78AB8406AC5",
+	"501573734B611DDA23D3F5F62E20A173B78AB8406AC5015094DA53F53D39B9EDB06C",
+	// UInt8, UInt32
+	"011019",
+	"34000000044B82FA09",
+	// Memo inner object
+	"EA7C0F04C4D46544659A2D58525043686174E1",
+}
+
+// reencode copies the fields read by r into w through the typed accessors,
+// so that every value is decoded and re-encoded rather than copied.
+func reencode(w *Writer, r Reader) error {
+	for r.Next() {
+		f := r.Field()
+		switch f.Type {
+		case TypeUInt8:
+			w.UInt8(f, r.UInt8())
+		case TypeUInt16:
+			w.UInt16(f, r.UInt16())
+		case TypeUInt32:
+			w.UInt32(f, r.UInt32())
+		case TypeUInt64:
+			w.UInt64(f, r.UInt64())
+		case TypeInt32:
+			w.Int32(f, r.Int32())
+		case TypeHash128:
+			w.Hash128(f, r.Hash128())
+		case TypeHash160:
+			w.Hash160(f, r.Hash160())
+		case TypeHash192:
+			w.Hash192(f, r.Hash192())
+		case TypeHash256:
+			w.Hash256(f, r.Hash256())
+		case TypeCurrency:
+			w.Currency(f, r.Currency())
+		case TypeAccountID:
+			w.AccountID(f, r.AccountID())
+		case TypeBlob:
+			w.Blob(f, r.Blob())
+		case TypeAmount:
+			w.Amount(f, r.Amount())
+		case TypeIssue:
+			w.Issue(f, r.Issue())
+		case TypeNumber:
+			w.Number(f, r.Number())
+		case TypeXChainBridge:
+			w.XChainBridge(f, r.XChainBridge())
+		case TypeVector256:
+			v := r.Vector256()
+			hashes := make([][32]byte, v.Len())
+			for i := range hashes {
+				hashes[i] = v.At(i)
+			}
+			w.Vector256(f, hashes)
+		case TypeSTObject:
+			w.BeginObject(f)
+			if err := reencode(w, r.Object()); err != nil {
+				return err
+			}
+			w.EndObject()
+		case TypeSTArray:
+			w.BeginArray(f)
+			if err := reencode(w, r.Array()); err != nil {
+				return err
+			}
+			w.EndArray()
+		default:
+			w.Raw(f, r.Value())
+		}
+	}
+	if err := r.Err(); err != nil {
+		return err
+	}
+	return w.Err()
+}
+
+func roundTrip(data []byte) ([]byte, error) {
+	w := NewWriter(nil)
+	if err := reencode(w, NewReader(data)); err != nil {
+		return nil, err
+	}
+	return w.Bytes()
+}
+
+func TestRoundTripMatchesMapCodec(t *testing.T) {
+	for _, h := range corpus {
+		data, err := hex.DecodeString(h)
+		require.NoError(t, err)
+
+		got, err := roundTrip(data)
+		require.NoError(t, err, h)
+
+		m, err := binarycodec.Decode(h)
+		require.NoError(t, err)
+		want, err := binarycodec.Encode(m)
+		require.NoError(t, err)
+		require.Equal(t, want, strings.ToUpper(hex.EncodeToString(got)))
+	}
+}
+
+func TestReaderTypedValues(t *testing.T) {
+	data, _ := hex.DecodeString(corpus[0])
+	r := NewReader(data)
+	var (
+		fields []string
+		amount Amount
+		sendMx Amount
+		memos  int
+		fee    Amount
+		seq    uint32
+	)
+	for r.Next() {
+		fields = append(fields, r.Field().Name)
+		switch r.Field() {
+		case SFSequence:
+			seq = r.UInt32()
+		case SFAmount:
+			amount = r.Amount()
+		case SFSendMax:
+			sendMx = r.Amount()
+		case SFFee:
+			fee = r.Amount()
+		case SFMemos:
+			arr := r.Array()
+			for arr.Next() {
+				require.Same(t, SFMemo, arr.Field())
+				memos++
+			}
+			require.NoError(t, arr.Err())
+		}
+	}
+	require.NoError(t, r.Err())
+	require.Equal(t, []string{
+		"TransactionType", "Flags", "Sequence", "LastLedgerSequence", "Amount", "Fee", "SendMax",
+		"SigningPubKey", "TxnSignature", "Account", "Destination", "Memos", "Paths",
+	}, fields)
+	require.Equal(t, uint32(842), seq)
+	require.Equal(t, XRPAmount(10_000_000), amount)
+	require.Equal(t, XRPAmount(12), fee)
+	require.Equal(t, AmountIOU, sendMx.Kind)
+	require.Equal(t, uint64(6275558355000000), sendMx.Mantissa)
+	require.Equal(t, int32(-16), sendMx.Exponent)
+	require.Equal(t, "USD", string(sendMx.Currency[12:15]))
+	require.Equal(t, 1, memos)
+}
+
+func TestReaderErrors(t *testing.T) {
+	t.Run("type mismatch", func(t *testing.T) {
+		data, _ := hex.DecodeString("34000000044B82FA09")
+		r := NewReader(data)
+		require.True(t, r.Next())
+		require.Zero(t, r.UInt32())
+		var mismatch *TypeMismatchError
+		require.ErrorAs(t, r.Err(), &mismatch)
+		require.Same(t, SFOwnerNode, mismatch.Field)
+		require.False(t, r.Next())
+	})
+	t.Run("truncated", func(t *testing.T) {
+		data, _ := hex.DecodeString(corpus[1])
+		r := NewReader(data[:len(data)-1])
+		for r.Next() {
+		}
+		require.ErrorIs(t, r.Err(), ErrTruncated)
+	})
+	t.Run("unterminated", func(t *testing.T) {
+		data, _ := hex.DecodeString("EA7C0F04C4D46544659A2D58525043686174")
+		r := NewReader(data)
+		require.False(t, r.Next())
+		require.ErrorIs(t, r.Err(), ErrUnterminated)
+	})
+	t.Run("unknown field", func(t *testing.T) {
+		r := NewReader([]byte{0x20, 0xFF, 0, 0, 0, 0})
+		require.False(t, r.Next())
+		var unknown *UnknownFieldError
+		require.ErrorAs(t, r.Err(), &unknown)
+	})
+}
+
+// FuzzRoundTrip checks that any input the map-based codec round-trips
+// unchanged is also read and re-written unchanged by Reader and Writer.
+func FuzzRoundTrip(f *testing.F) {
+	for _, h := range corpus {
+		data, _ := hex.DecodeString(h)
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// The typed path must never panic, whatever the input.
+		got, err := roundTrip(data)
+
+		h := strings.ToUpper(hex.EncodeToString(data))
+		want, ok := mapRoundTrip(h)
+		if !ok || want != h {
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, want, strings.ToUpper(hex.EncodeToString(got)))
+	})
+}
+
+// mapRoundTrip decodes and re-encodes h with the map-based codec. Inputs
+// that make it fail or panic are reported as not ok: the fuzz target only
+// compares the two codecs on input the map-based codec accepts.
+func mapRoundTrip(h string) (enc string, ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	m, err := binarycodec.Decode(h)
+	if err != nil {
+		return "", false
+	}
+	enc, err = binarycodec.Encode(m)
+	return enc, err == nil
+}
+
+func BenchmarkReader(b *testing.B) {
+	data, _ := hex.DecodeString(corpus[0])
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r := NewReader(data)
+		for r.Next() {
+			if r.Field() == SFAmount {
+				_ = r.Amount()
+			}
+		}
+		if r.Err() != nil {
+			b.Fatal(r.Err())
+		}
+	}
+}
+
+func BenchmarkMapDecode(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := binarycodec.Decode(corpus[0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRoundTrip(b *testing.B) {
+	data, _ := hex.DecodeString(corpus[0])
+	w := NewWriter(make([]byte, 0, len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w.Reset(w.buf[:0])
+		if err := reencode(w, NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package st
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// AmountKind distinguishes the three STAmount encodings.
+type AmountKind uint8
+
+const (
+	// AmountXRP is a native amount in drops (8 bytes).
+	AmountXRP AmountKind = iota
+	// AmountIOU is an issued currency amount (48 bytes).
+	AmountIOU
+	// AmountMPT is a multi-purpose token amount (33 bytes).
+	AmountMPT
+)
+
+// Amount is a decoded STAmount.
+//
+// For XRP and MPT amounts Mantissa holds the unsigned quantity. For IOU
+// amounts the value is Mantissa * 10^Exponent; a zero IOU has Mantissa 0 and
+// is always encoded canonically regardless of Exponent and Negative.
+// Reference: rippled STAmount.cpp
+type Amount struct {
+	Kind     AmountKind
+	Negative bool
+	Mantissa uint64
+	Exponent int32
+	Currency [20]byte // IOU only
+	Issuer   [20]byte // IOU only
+	MPTID    [24]byte // MPT only
+}
+
+const (
+	amountNotNative = 0x8000000000000000
+	amountPositive  = 0x4000000000000000
+	amountMPTFlag   = 0x20
+	iouMantissaMask = 1<<54 - 1
+	iouExponentBias = 97
+)
+
+var errAmountLength = errors.New("st: invalid amount length")
+
+// amountSize returns the encoded size of the amount starting at b.
+func amountSize(b []byte) int {
+	switch {
+	case len(b) == 0:
+		return 0
+	case b[0]&0x80 != 0:
+		return 48
+	case b[0]&amountMPTFlag != 0:
+		return 33
+	default:
+		return 8
+	}
+}
+
+// DecodeAmount decodes an encoded STAmount value.
+func DecodeAmount(b []byte) (Amount, error) {
+	var a Amount
+	if len(b) == 0 || len(b) != amountSize(b) {
+		return a, errAmountLength
+	}
+	switch len(b) {
+	case 48:
+		v := binary.BigEndian.Uint64(b)
+		a.Kind = AmountIOU
+		copy(a.Currency[:], b[8:28])
+		copy(a.Issuer[:], b[28:48])
+		if v&iouMantissaMask == 0 {
+			return a, nil
+		}
+		a.Negative = v&amountPositive == 0
+		a.Exponent = int32(v>>54&0xFF) - iouExponentBias
+		a.Mantissa = v & iouMantissaMask
+	case 33:
+		a.Kind = AmountMPT
+		a.Negative = b[0]&0x40 == 0
+		a.Mantissa = binary.BigEndian.Uint64(b[1:9])
+		copy(a.MPTID[:], b[9:33])
+	default:
+		v := binary.BigEndian.Uint64(b)
+		a.Negative = v&amountPositive == 0
+		a.Mantissa = v &^ (amountNotNative | amountPositive)
+	}
+	return a, nil
+}
+
+// validAmount reports whether a fits its encoding: the mantissa must not
+// reach into the flag bits and an IOU exponent must fit its 8-bit field.
+func validAmount(a Amount) bool {
+	switch a.Kind {
+	case AmountXRP:
+		return a.Mantissa < 1<<61
+	case AmountIOU:
+		return a.Mantissa == 0 || a.Mantissa <= iouMantissaMask &&
+			a.Exponent >= -iouExponentBias && a.Exponent <= 255-iouExponentBias
+	default:
+		return true
+	}
+}
+
+// AppendAmount appends the encoding of a. Bits of an out-of-range mantissa
+// or exponent are dropped; Writer.Amount rejects such amounts instead.
+func AppendAmount(buf []byte, a Amount) []byte {
+	switch a.Kind {
+	case AmountIOU:
+		v := uint64(amountNotNative)
+		if a.Mantissa != 0 {
+			if !a.Negative {
+				v |= amountPositive
+			}
+			v |= uint64(a.Exponent+iouExponentBias) & 0xFF << 54
+			v |= a.Mantissa & iouMantissaMask
+		}
+		buf = binary.BigEndian.AppendUint64(buf, v)
+		buf = append(buf, a.Currency[:]...)
+		return append(buf, a.Issuer[:]...)
+	case AmountMPT:
+		head := byte(amountMPTFlag)
+		if !a.Negative {
+			head |= 0x40
+		}
+		buf = append(buf, head)
+		buf = binary.BigEndian.AppendUint64(buf, a.Mantissa)
+		return append(buf, a.MPTID[:]...)
+	default:
+		v := a.Mantissa &^ (amountNotNative | amountPositive)
+		if !a.Negative {
+			v |= amountPositive
+		}
+		return binary.BigEndian.AppendUint64(buf, v)
+	}
+}
+
+// XRPAmount returns a native amount of the given drops.
+func XRPAmount(drops uint64) Amount {
+	return Amount{Kind: AmountXRP, Mantissa: drops}
+}
+
+// Issue identifies an asset: XRP, an issued currency, or an MPT issuance.
+// Reference: rippled STIssue.cpp
+type Issue struct {
+	MPT      bool
+	Currency [20]byte // IOU only; all zero for XRP
+	Issuer   [20]byte // IOU only
+	MPTID    [24]byte // MPT only: big-endian sequence followed by the issuer
+}
+
+// noAccount is the issuer marker that identifies an MPT Issue.
+var noAccount = [20]byte{19: 1}
+
+// issueSize returns the encoded size of the Issue starting at b.
+func issueSize(b []byte) int {
+	switch {
+	case len(b) < 20:
+		return 20
+	case [20]byte(b[:20]) == [20]byte{}:
+		return 20
+	case len(b) >= 40 && [20]byte(b[20:40]) == noAccount:
+		return 44
+	default:
+		return 40
+	}
+}
+
+// DecodeIssue decodes an encoded Issue value.
+func DecodeIssue(b []byte) (Issue, error) {
+	var is Issue
+	if len(b) < 20 || len(b) != issueSize(b) {
+		return is, errors.New("st: invalid issue length")
+	}
+	switch len(b) {
+	case 44:
+		is.MPT = true
+		// The sequence is stored little-endian after the issuer account.
+		binary.BigEndian.PutUint32(is.MPTID[:4], binary.LittleEndian.Uint32(b[40:44]))
+		copy(is.MPTID[4:], b[:20])
+	case 40:
+		copy(is.Currency[:], b[:20])
+		copy(is.Issuer[:], b[20:40])
+	}
+	return is, nil
+}
+
+// AppendIssue appends the encoding of is.
+func AppendIssue(buf []byte, is Issue) []byte {
+	switch {
+	case is.MPT:
+		buf = append(buf, is.MPTID[4:]...)
+		buf = append(buf, noAccount[:]...)
+		return binary.LittleEndian.AppendUint32(buf, binary.BigEndian.Uint32(is.MPTID[:4]))
+	case is.Currency == [20]byte{}:
+		return append(buf, is.Currency[:]...)
+	default:
+		buf = append(buf, is.Currency[:]...)
+		return append(buf, is.Issuer[:]...)
+	}
+}
+
+// Number is a decoded STNumber: Mantissa * 10^Exponent.
+// Reference: rippled STNumber.cpp
+type Number struct {
+	Mantissa int64
+	Exponent int32
+}
+
+// XChainBridge identifies a cross-chain bridge.
+// Reference: rippled STXChainBridge.cpp
+type XChainBridge struct {
+	LockingChainDoor  [20]byte
+	LockingChainIssue Issue
+	IssuingChainDoor  [20]byte
+	IssuingChainIssue Issue
+}
+
+// xchainBridgeSize returns the encoded size of the XChainBridge starting at
+// b: a VL-encoded door account and an Issue for each chain.
+func xchainBridgeSize(b []byte) (int, bool) {
+	off := 0
+	for i := 0; i < 2; i++ {
+		length, n, ok := readVL(b[off:])
+		if !ok || length != 20 {
+			return 0, false
+		}
+		off += n + length
+		if off >= len(b) {
+			return 0, false
+		}
+		off += issueSize(b[off:])
+		if off > len(b) {
+			return 0, false
+		}
+	}
+	return off, true
+}
+
+// DecodeXChainBridge decodes an encoded XChainBridge value.
+func DecodeXChainBridge(b []byte) (XChainBridge, error) {
+	var x XChainBridge
+	if n, ok := xchainBridgeSize(b); !ok || n != len(b) {
+		return x, errors.New("st: invalid XChainBridge")
+	}
+	off := 1
+	copy(x.LockingChainDoor[:], b[off:off+20])
+	off += 20
+	n := issueSize(b[off:])
+	x.LockingChainIssue, _ = DecodeIssue(b[off : off+n])
+	off += n + 1
+	copy(x.IssuingChainDoor[:], b[off:off+20])
+	off += 20
+	x.IssuingChainIssue, _ = DecodeIssue(b[off:])
+	return x, nil
+}
+
+// AppendXChainBridge appends the encoding of x.
+func AppendXChainBridge(buf []byte, x XChainBridge) []byte {
+	buf = append(buf, 20)
+	buf = append(buf, x.LockingChainDoor[:]...)
+	buf = AppendIssue(buf, x.LockingChainIssue)
+	buf = append(buf, 20)
+	buf = append(buf, x.IssuingChainDoor[:]...)
+	return AppendIssue(buf, x.IssuingChainIssue)
+}
+
+// Vector256 is a read-only view of an encoded Vector256 value.
+type Vector256 []byte
+
+// Len returns the number of hashes in the vector.
+func (v Vector256) Len() int {
+	return len(v) / 32
+}
+
+// At returns the i'th hash.
+func (v Vector256) At(i int) [32]byte {
+	return [32]byte(v[i*32 : i*32+32])
+}
+
+// pathSetSize returns the encoded size of the PathSet starting at b, up to
+// and including its end byte.
+func pathSetSize(b []byte) (int, bool) {
+	for off := 0; off < len(b); {
+		typ := b[off]
+		off++
+		switch typ {
+		case 0x00:
+			return off, true
+		case 0xFF:
+			continue
+		}
+		if typ&0x01 != 0 {
+			off += 20
+		}
+		if typ&0x10 != 0 {
+			off += 20
+		}
+		if typ&0x20 != 0 {
+			off += 20
+		}
+	}
+	return 0, false
+}
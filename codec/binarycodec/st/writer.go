@@ -0,0 +1,291 @@
+package st
+
+import (
+	"encoding/binary"
+)
+
+// Writer appends the canonical encoding of an object's fields to a byte
+// slice. Fields must be written in ascending Ordinal order, as rippled
+// serializes them; a field written out of order records an OrderError.
+// Elements of an array are written in the order given.
+//
+//	w := st.NewWriter(buf[:0])
+//	w.UInt16(st.SFLedgerEntryType, 0x0061)
+//	w.UInt32(st.SFFlags, 0)
+//	w.UInt32(st.SFSequence, seq)
+//	w.Amount(st.SFBalance, st.XRPAmount(drops))
+//	w.AccountID(st.SFAccount, account)
+//	data, err := w.Bytes()
+//
+// The first error stops all further writes and is reported by Bytes and
+// Err, so callers only need to check once.
+type Writer struct {
+	buf   []byte
+	cur   frame
+	stack []frame
+	inner [4]frame
+	err   error
+}
+
+// frame tracks the field order of one object or array being written.
+type frame struct {
+	last  *Field
+	array bool
+}
+
+// NewWriter returns a Writer that appends to buf.
+func NewWriter(buf []byte) *Writer {
+	w := &Writer{buf: buf}
+	w.stack = w.inner[:0]
+	return w
+}
+
+// Reset discards any written data and error and makes w append to buf.
+func (w *Writer) Reset(buf []byte) {
+	w.buf, w.cur, w.err = buf, frame{}, nil
+	w.stack = w.stack[:0]
+}
+
+// Err returns the first error encountered.
+func (w *Writer) Err() error {
+	return w.err
+}
+
+// Bytes returns the encoded object. It fails if an error was recorded or an
+// inner object or array has not been ended.
+func (w *Writer) Bytes() ([]byte, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+	if len(w.stack) != 0 {
+		return nil, ErrUnterminated
+	}
+	return w.buf, nil
+}
+
+// header checks that f has type t and follows the previously written field,
+// then appends its header.
+func (w *Writer) header(f *Field, t Type) bool {
+	if w.err != nil {
+		return false
+	}
+	if f.Type != t {
+		w.err = &TypeMismatchError{Field: f, Want: t}
+		return false
+	}
+	if !w.cur.array && w.cur.last != nil && f.Ordinal() <= w.cur.last.Ordinal() {
+		w.err = &OrderError{Field: f, Previous: w.cur.last}
+		return false
+	}
+	w.cur.last = f
+	w.buf = AppendHeader(w.buf, f)
+	return true
+}
+
+// vl appends a length-prefixed value.
+func (w *Writer) vl(v []byte) {
+	buf, err := AppendVL(w.buf, len(v))
+	if err != nil {
+		w.err = err
+		return
+	}
+	w.buf = append(buf, v...)
+}
+
+// UInt8 writes a UInt8 field.
+func (w *Writer) UInt8(f *Field, v uint8) {
+	if w.header(f, TypeUInt8) {
+		w.buf = append(w.buf, v)
+	}
+}
+
+// UInt16 writes a UInt16 field.
+func (w *Writer) UInt16(f *Field, v uint16) {
+	if w.header(f, TypeUInt16) {
+		w.buf = binary.BigEndian.AppendUint16(w.buf, v)
+	}
+}
+
+// UInt32 writes a UInt32 field.
+func (w *Writer) UInt32(f *Field, v uint32) {
+	if w.header(f, TypeUInt32) {
+		w.buf = binary.BigEndian.AppendUint32(w.buf, v)
+	}
+}
+
+// UInt64 writes a UInt64 field.
+func (w *Writer) UInt64(f *Field, v uint64) {
+	if w.header(f, TypeUInt64) {
+		w.buf = binary.BigEndian.AppendUint64(w.buf, v)
+	}
+}
+
+// Int32 writes an Int32 field.
+func (w *Writer) Int32(f *Field, v int32) {
+	if w.header(f, TypeInt32) {
+		w.buf = binary.BigEndian.AppendUint32(w.buf, uint32(v))
+	}
+}
+
+// Hash128 writes a Hash128 field.
+func (w *Writer) Hash128(f *Field, v [16]byte) {
+	if w.header(f, TypeHash128) {
+		w.buf = append(w.buf, v[:]...)
+	}
+}
+
+// Hash160 writes a Hash160 field.
+func (w *Writer) Hash160(f *Field, v [20]byte) {
+	if w.header(f, TypeHash160) {
+		w.buf = append(w.buf, v[:]...)
+	}
+}
+
+// Hash192 writes a Hash192 field.
+func (w *Writer) Hash192(f *Field, v [24]byte) {
+	if w.header(f, TypeHash192) {
+		w.buf = append(w.buf, v[:]...)
+	}
+}
+
+// Hash256 writes a Hash256 field.
+func (w *Writer) Hash256(f *Field, v [32]byte) {
+	if w.header(f, TypeHash256) {
+		w.buf = append(w.buf, v[:]...)
+	}
+}
+
+// Currency writes a Currency field.
+func (w *Writer) Currency(f *Field, v [20]byte) {
+	if w.header(f, TypeCurrency) {
+		w.buf = append(w.buf, v[:]...)
+	}
+}
+
+// AccountID writes an AccountID field.
+func (w *Writer) AccountID(f *Field, v [20]byte) {
+	if w.header(f, TypeAccountID) {
+		w.vl(v[:])
+	}
+}
+
+// Blob writes a Blob field.
+func (w *Writer) Blob(f *Field, v []byte) {
+	if w.header(f, TypeBlob) {
+		w.vl(v)
+	}
+}
+
+// Amount writes an Amount field.
+func (w *Writer) Amount(f *Field, v Amount) {
+	if !w.header(f, TypeAmount) {
+		return
+	}
+	if !validAmount(v) {
+		w.err = ErrAmountRange
+		return
+	}
+	w.buf = AppendAmount(w.buf, v)
+}
+
+// Issue writes an Issue field.
+func (w *Writer) Issue(f *Field, v Issue) {
+	if w.header(f, TypeIssue) {
+		w.buf = AppendIssue(w.buf, v)
+	}
+}
+
+// Number writes a Number field.
+func (w *Writer) Number(f *Field, v Number) {
+	if w.header(f, TypeNumber) {
+		w.buf = binary.BigEndian.AppendUint64(w.buf, uint64(v.Mantissa))
+		w.buf = binary.BigEndian.AppendUint32(w.buf, uint32(v.Exponent))
+	}
+}
+
+// XChainBridge writes an XChainBridge field.
+func (w *Writer) XChainBridge(f *Field, v XChainBridge) {
+	if w.header(f, TypeXChainBridge) {
+		w.buf = AppendXChainBridge(w.buf, v)
+	}
+}
+
+// Vector256 writes a Vector256 field.
+func (w *Writer) Vector256(f *Field, v [][32]byte) {
+	if !w.header(f, TypeVector256) {
+		return
+	}
+	buf, err := AppendVL(w.buf, len(v)*32)
+	if err != nil {
+		w.err = err
+		return
+	}
+	for i := range v {
+		buf = append(buf, v[i][:]...)
+	}
+	w.buf = buf
+}
+
+// Raw writes a field from its encoded value, as returned by Reader.Value.
+// It is used to copy fields through unchanged and for types without a
+// typed writer (PathSet, UInt96, UInt384, UInt512, Int64). Inner objects
+// and arrays are written with their end marker.
+func (w *Writer) Raw(f *Field, value []byte) {
+	if !w.header(f, f.Type) {
+		return
+	}
+	switch {
+	case f.VLEncoded:
+		w.vl(value)
+	case f.Type == TypeSTObject:
+		w.buf = append(append(w.buf, value...), objectEndMarker)
+	case f.Type == TypeSTArray:
+		w.buf = append(append(w.buf, value...), arrayEndMarker)
+	default:
+		w.buf = append(w.buf, value...)
+	}
+}
+
+// BeginObject starts an inner object field. Its fields are written next,
+// followed by EndObject.
+func (w *Writer) BeginObject(f *Field) {
+	if w.header(f, TypeSTObject) {
+		w.push(false)
+	}
+}
+
+// EndObject ends the inner object started by BeginObject.
+func (w *Writer) EndObject() {
+	w.pop(false, objectEndMarker)
+}
+
+// BeginArray starts an array field. Its elements are written next, each as
+// an inner object (BeginObject ... EndObject), followed by EndArray.
+func (w *Writer) BeginArray(f *Field) {
+	if w.header(f, TypeSTArray) {
+		w.push(true)
+	}
+}
+
+// EndArray ends the array started by BeginArray.
+func (w *Writer) EndArray() {
+	w.pop(true, arrayEndMarker)
+}
+
+func (w *Writer) push(array bool) {
+	w.stack = append(w.stack, w.cur)
+	w.cur = frame{array: array}
+}
+
+func (w *Writer) pop(array bool, endMarker byte) {
+	if w.err != nil {
+		return
+	}
+	if len(w.stack) == 0 || w.cur.array != array {
+		w.err = ErrUnbalanced
+		return
+	}
+	w.buf = append(w.buf, endMarker)
+	w.cur = w.stack[len(w.stack)-1]
+	w.stack = w.stack[:len(w.stack)-1]
+}
@@ -0,0 +1,122 @@
+package st
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterMatchesMapCodec(t *testing.T) {
+	var account, dest [20]byte
+	account[0], dest[0] = 0x11, 0x22
+	var usd [20]byte
+	copy(usd[12:], "USD")
+
+	w := NewWriter(nil)
+	w.UInt16(SFTransactionType, 0)
+	w.UInt32(SFFlags, 0x80000000)
+	w.UInt32(SFSequence, 7)
+	w.Amount(SFAmount, Amount{Kind: AmountIOU, Mantissa: 1_000_000_000_000_000, Exponent: -15, Currency: usd, Issuer: dest})
+	w.Amount(SFFee, XRPAmount(10))
+	w.Blob(SFSigningPubKey, nil)
+	w.AccountID(SFAccount, account)
+	w.AccountID(SFDestination, dest)
+	w.BeginArray(SFMemos)
+	w.BeginObject(SFMemo)
+	w.Blob(SFMemoType, []byte("text"))
+	w.Blob(SFMemoData, []byte("hello"))
+	w.EndObject()
+	w.EndArray()
+	got, err := w.Bytes()
+	require.NoError(t, err)
+
+	m, err := binarycodec.Decode(strings.ToUpper(hex.EncodeToString(got)))
+	require.NoError(t, err)
+	require.Equal(t, "Payment", m["TransactionType"])
+	require.Equal(t, "10", m["Fee"])
+	require.Equal(t, uint32(7), m["Sequence"])
+	require.Equal(t, map[string]any{
+		"currency": "USD",
+		"issuer":   m["Destination"],
+		"value":    "1",
+	}, m["Amount"])
+	want, err := binarycodec.Encode(m)
+	require.NoError(t, err)
+	require.Equal(t, want, strings.ToUpper(hex.EncodeToString(got)))
+}
+
+func TestWriterErrors(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		w := NewWriter(nil)
+		w.UInt32(SFSequence, 1)
+		w.UInt32(SFFlags, 0)
+		w.Amount(SFFee, XRPAmount(10))
+		_, err := w.Bytes()
+		var order *OrderError
+		require.ErrorAs(t, err, &order)
+		require.Same(t, SFFlags, order.Field)
+		require.Same(t, SFSequence, order.Previous)
+	})
+	t.Run("duplicate", func(t *testing.T) {
+		w := NewWriter(nil)
+		w.UInt32(SFFlags, 0)
+		w.UInt32(SFFlags, 0)
+		var order *OrderError
+		require.ErrorAs(t, w.Err(), &order)
+	})
+	t.Run("array elements keep their order", func(t *testing.T) {
+		w := NewWriter(nil)
+		w.BeginArray(SFSignerEntries)
+		for i := 0; i < 2; i++ {
+			w.BeginObject(SFSignerEntry)
+			w.UInt16(SFSignerWeight, 1)
+			w.EndObject()
+		}
+		w.EndArray()
+		_, err := w.Bytes()
+		require.NoError(t, err)
+	})
+	t.Run("type mismatch", func(t *testing.T) {
+		w := NewWriter(nil)
+		w.UInt16(SFSequence, 1)
+		var mismatch *TypeMismatchError
+		require.ErrorAs(t, w.Err(), &mismatch)
+		require.Equal(t, TypeUInt16, mismatch.Want)
+	})
+	t.Run("amount range", func(t *testing.T) {
+		for _, a := range []Amount{
+			XRPAmount(1 << 61),
+			{Kind: AmountIOU, Mantissa: 1 << 54},
+			{Kind: AmountIOU, Mantissa: 1, Exponent: 159},
+		} {
+			w := NewWriter(nil)
+			w.Amount(SFAmount, a)
+			require.ErrorIs(t, w.Err(), ErrAmountRange)
+		}
+	})
+	t.Run("unterminated", func(t *testing.T) {
+		w := NewWriter(nil)
+		w.BeginObject(SFMemo)
+		_, err := w.Bytes()
+		require.ErrorIs(t, err, ErrUnterminated)
+	})
+	t.Run("unbalanced", func(t *testing.T) {
+		w := NewWriter(nil)
+		w.BeginArray(SFMemos)
+		w.EndObject()
+		require.ErrorIs(t, w.Err(), ErrUnbalanced)
+	})
+	t.Run("reset", func(t *testing.T) {
+		w := NewWriter(nil)
+		w.UInt32(SFSequence, 1)
+		w.UInt32(SFFlags, 0)
+		w.Reset(nil)
+		w.UInt32(SFFlags, 0)
+		got, err := w.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, []byte{0x22, 0, 0, 0, 0}, got)
+	})
+}
@@ -73,6 +73,9 @@ type Config struct {
 	Crawl          CrawlConfig `toml:"crawl" mapstructure:"crawl"`
 	VL             VLConfig    `toml:"vl" mapstructure:"vl"`
 	BetaRPCAPI     int         `toml:"beta_rpc_api" mapstructure:"beta_rpc_api"`
+	// LedgerCacheMaxBytes bounds the estimated memory of the recent
+	// ledgers kept in memory. Zero leaves only the node_size ledger count.
+	LedgerCacheMaxBytes int64 `toml:"ledger_cache_max_bytes" mapstructure:"ledger_cache_max_bytes"`
 
 	// Special startup commands
 	RPCStartup             []map[string]interface{} `toml:"rpc_startup" mapstructure:"rpc_startup"`
@@ -108,6 +108,24 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, "http", portConfig.Protocol)
 }
 
+func TestLoadConfig_LedgerCacheMaxBytes(t *testing.T) {
+	tempDir := t.TempDir()
+
+	mainConfigPath := filepath.Join(tempDir, "test_config.toml")
+	err := os.WriteFile(mainConfigPath, []byte("ledger_cache_max_bytes = 1073741824\n"+completeTestConfig()), 0644)
+	require.NoError(t, err)
+
+	config, err := LoadConfig(ConfigPaths{Main: mainConfigPath})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1<<30), config.LedgerCacheMaxBytes)
+
+	err = os.WriteFile(mainConfigPath, []byte("ledger_cache_max_bytes = -1\n"+completeTestConfig()), 0644)
+	require.NoError(t, err)
+	_, err = LoadConfig(ConfigPaths{Main: mainConfigPath})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ledger_cache_max_bytes")
+}
+
 func TestLoadConfig_WithValidators(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -17,6 +17,7 @@ type NodeDBConfig struct {
 	BackOffMilliseconds int    `toml:"back_off_milliseconds" mapstructure:"back_off_milliseconds"`
 	AgeThresholdSeconds int    `toml:"age_threshold_seconds" mapstructure:"age_threshold_seconds"`
 	RecoveryWaitSeconds int    `toml:"recovery_wait_seconds" mapstructure:"recovery_wait_seconds"`
+	ReadOnly            bool   `toml:"read_only" mapstructure:"read_only"`
 }
 
 // SQLiteConfig represents the [sqlite] section
@@ -41,9 +42,9 @@ func (n *NodeDBConfig) Validate() error {
 	if n.Type == "" {
 		return fmt.Errorf("node_db type is required")
 	}
-	validTypes := []string{"pebble", "Pebble"}
+	validTypes := []string{"pebble", "Pebble", "nudb", "NuDB"}
 	if !contains_slice(validTypes, n.Type) {
-		return fmt.Errorf("invalid node_db type: %s (valid options: pebble)", n.Type)
+		return fmt.Errorf("invalid node_db type: %s (valid options: pebble, NuDB)", n.Type)
 	}
 
 	// Validate path
@@ -152,6 +153,8 @@ func (n *NodeDBConfig) GetType() string {
 	switch n.Type {
 	case "pebble", "Pebble":
 		return "pebble"
+	case "nudb", "NuDB":
+		return "nudb"
 	default:
 		return n.Type
 	}
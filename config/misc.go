@@ -200,6 +200,14 @@ func ValidateBetaRPCAPI(betaAPI int) error {
 	return nil
 }
 
+// ValidateLedgerCacheMaxBytes validates the ledger cache memory bound
+func ValidateLedgerCacheMaxBytes(maxBytes int64) error {
+	if maxBytes < 0 {
+		return fmt.Errorf("ledger_cache_max_bytes must be non-negative, got %d", maxBytes)
+	}
+	return nil
+}
+
 // ValidateWebsocketPingFrequency validates the websocket ping frequency
 func ValidateWebsocketPingFrequency(frequency int) error {
 	if frequency < 0 {
@@ -239,3 +247,21 @@ func ValidateRelayValidations(relayValidations string) error {
 
 	return fmt.Errorf("invalid relay_validations: %s (valid options: all, trusted, drop_untrusted)", relayValidations)
 }
+
+// GetLedgerCacheSize returns the number of recent ledgers to keep in memory
+// for the configured node_size.
+// Reference: rippled Config.cpp sizedItems (siLedgerSize)
+func (c *Config) GetLedgerCacheSize() int {
+	switch c.NodeSize {
+	case "tiny", "small":
+		return 32
+	case "medium":
+		return 64
+	case "large":
+		return 256
+	case "huge":
+		return 384
+	default:
+		return 0
+	}
+}
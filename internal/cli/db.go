@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+	"github.com/LeJamon/goXRPLd/storage/relationaldb/postgres"
+	sqlitedb "github.com/LeJamon/goXRPLd/storage/relationaldb/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dbPath   string
+	dbDryRun bool
+)
+
+// dbCmd represents the db command group
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Relational database maintenance commands",
+	Long: `Inspect and migrate the relational database described by database_path,
+or by --path. A postgres:// or postgresql:// URL selects PostgreSQL; anything
+else is the directory holding the SQLite ledger.db and transaction.db.
+
+The server applies pending migrations when it starts, and refuses to start
+against a database migrated by a newer build.`,
+}
+
+var dbStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show applied and pending schema migrations",
+	Args:  cobra.NoArgs,
+	RunE:  runDBStatus,
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply pending schema migrations",
+	Long: `Apply pending schema migrations in order. Each migration runs in its own
+transaction; if one fails it is rolled back and the run stops.
+
+Examples:
+    xrpld db migrate --conf xrpld.toml --dry-run
+    xrpld db migrate --path postgres://xrpld@localhost/xrpld`,
+	Args: cobra.NoArgs,
+	RunE: runDBMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(dbCmd)
+	for _, cmd := range []*cobra.Command{dbStatusCmd, dbMigrateCmd} {
+		// Failures are data problems, not usage mistakes; Execute prints them.
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		dbCmd.AddCommand(cmd)
+	}
+
+	dbCmd.PersistentFlags().StringVar(&dbPath, "path", "", "SQLite directory or PostgreSQL URL (default: database_path)")
+	dbMigrateCmd.Flags().BoolVar(&dbDryRun, "dry-run", false, "list pending migrations without applying them")
+}
+
+// schemaRepositoryManager is a repository manager with versioned schemas.
+type schemaRepositoryManager interface {
+	relationaldb.RepositoryManager
+	relationaldb.SchemaManager
+}
+
+// openRelationalDB opens the relational database selected by the command
+// flags without applying pending migrations.
+func openRelationalDB(cmd *cobra.Command) (schemaRepositoryManager, error) {
+	path := dbPath
+	if path == "" && globalConfig != nil {
+		path = globalConfig.DatabasePath
+	}
+	if path == "" {
+		return nil, errors.New("no relational database: pass --conf with database_path, or --path")
+	}
+
+	var rm schemaRepositoryManager
+	if strings.HasPrefix(path, "postgres://") || strings.HasPrefix(path, "postgresql://") {
+		pgConfig := relationaldb.NewConfig()
+		pgConfig.ConnectionString = path
+		pg, err := postgres.NewRepositoryManager(pgConfig)
+		if err != nil {
+			return nil, err
+		}
+		rm = pg
+	} else {
+		sqlite, err := sqlitedb.NewRepositoryManager(path)
+		if err != nil {
+			return nil, err
+		}
+		rm = sqlite
+	}
+
+	rm.SetAutoMigrate(false)
+	if err := rm.Open(cmd.Context()); err != nil {
+		return nil, err
+	}
+	return rm, nil
+}
+
+func runDBStatus(cmd *cobra.Command, args []string) error {
+	rm, err := openRelationalDB(cmd)
+	if err != nil {
+		return err
+	}
+	defer rm.Close(cmd.Context())
+
+	statuses, err := rm.SchemaStatus(cmd.Context())
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		fmt.Printf("%s: version %d, latest %d\n", status.Schema, status.Current, status.Latest)
+		for _, applied := range status.Applied {
+			fmt.Printf("  applied  %3d  %s  %s\n", applied.Version, applied.AppliedAt.Format("2006-01-02 15:04:05"), applied.Description)
+		}
+		for _, pending := range status.Pending {
+			fmt.Printf("  pending  %3d  %s\n", pending.Version, pending.Description)
+		}
+	}
+	return nil
+}
+
+func runDBMigrate(cmd *cobra.Command, args []string) error {
+	rm, err := openRelationalDB(cmd)
+	if err != nil {
+		return err
+	}
+	defer rm.Close(cmd.Context())
+
+	statuses, err := rm.SchemaStatus(cmd.Context())
+	if err != nil {
+		return err
+	}
+	applied, err := rm.Migrate(cmd.Context(), dbDryRun)
+
+	verb := "applied"
+	if dbDryRun {
+		verb = "would apply"
+	}
+	for _, status := range statuses {
+		migrations := applied[status.Schema]
+		if len(migrations) == 0 && err == nil {
+			fmt.Printf("%s: up to date at version %d\n", status.Schema, status.Current)
+			continue
+		}
+		for _, m := range migrations {
+			fmt.Printf("%s: %s %d  %s\n", status.Schema, verb, m.Version, m.Description)
+		}
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+
+	"github.com/LeJamon/goXRPLd/config"
+	"github.com/LeJamon/goXRPLd/internal/ledger/header"
+	xrpllog "github.com/LeJamon/goXRPLd/log"
+	"github.com/LeJamon/goXRPLd/storage/nodestore"
+)
+
+// importNodeDB copies every object in [import_db] into [node_db] before
+// the server opens its node store, as rippled does when started with
+// --import. Objects already present in [node_db] are skipped, so an
+// interrupted import resumes when the server is restarted with --import.
+func importNodeDB(ctx context.Context, cfg *config.Config, log xrpllog.Logger) error {
+	src, dst := cfg.ImportDB, cfg.NodeDB
+	if src.Path == "" {
+		return errors.New("--import requires an [import_db] section")
+	}
+	if dst.Path == "" {
+		return errors.New("--import requires a persistent [node_db]")
+	}
+	if filepath.Clean(src.Path) == filepath.Clean(dst.Path) {
+		return errors.New("[import_db] and [node_db] must use different paths")
+	}
+
+	source, err := nodestore.CreateBackend(src.GetType(), &nodestore.Config{Path: src.Path, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	if err := source.Open(false); err != nil {
+		return err
+	}
+	defer source.Close()
+
+	dest, err := nodestore.CreateBackend(dst.GetType(), &nodestore.Config{Path: dst.Path, CreateIfMissing: true})
+	if err != nil {
+		return err
+	}
+	if err := dest.Open(true); err != nil {
+		return err
+	}
+	defer dest.Close()
+
+	log.Info("Importing node database", "from", src.Path, "to", dst.Path)
+	progress, err := nodestore.Import(ctx, source, dest, &nodestore.ImportOptions{
+		Verify:   true,
+		HashFunc: nodeObjectHash,
+		ProgressCallback: func(p nodestore.ImportProgress) {
+			log.Info("Node import progress", "scanned", p.Scanned, "imported", p.Imported,
+				"skipped", p.Skipped, "bytes", p.Bytes, "elapsed", p.Elapsed.String())
+		},
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("Node import complete", "imported", progress.Imported, "skipped", progress.Skipped)
+	return nil
+}
+
+// nodeObjectHash is the key a node object must be stored under, accepting
+// the ledger headers the ledger service writes as well as rippled's.
+func nodeObjectHash(data nodestore.Blob) nodestore.Hash256 {
+	return nodestore.Hash256(header.NodeObjectHash(data))
+}
@@ -0,0 +1,446 @@
+package cli
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/LeJamon/goXRPLd/config"
+	"github.com/LeJamon/goXRPLd/internal/ledger/header"
+	"github.com/LeJamon/goXRPLd/shamap"
+	kvpebble "github.com/LeJamon/goXRPLd/storage/kvstore/pebble"
+	"github.com/LeJamon/goXRPLd/storage/nodestore"
+	"github.com/spf13/cobra"
+)
+
+var (
+	nodestorePath   string
+	nodestoreType   string
+	nodestoreCount  uint32
+	nodestoreToPath string
+	nodestoreToType string
+)
+
+// nodestoreCmd represents the nodestore command group
+var nodestoreCmd = &cobra.Command{
+	Use:   "nodestore",
+	Short: "Node database maintenance commands",
+	Long: `Inspect and maintain the node database described by [node_db], or by
+--path and --type. The server must not be running against the same database.
+
+Ledgers are named by hash; --count follows parent hashes to cover that many
+ledgers ending at the named one.`,
+}
+
+var nodestoreVerifyCmd = &cobra.Command{
+	Use:   "verify [ledger-hash]",
+	Short: "Check a ledger's state and transaction trees for missing or corrupt nodes",
+	Long: `Walk the state and transaction trees of a ledger from their root hashes
+and report every node that is missing or whose data does not hash to its key.
+Without a ledger hash, every object in the database is checked instead.
+
+Examples:
+    xrpld nodestore verify --conf xrpld.toml 4109C6F2045FC7EFF4CDE8F9905D19C28820D86304080FF886B299F0206E42B5
+    xrpld nodestore verify --conf xrpld.toml <hash> --count 256
+    xrpld nodestore verify --path /var/lib/xrpld/db/nudb --type nudb`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runNodestoreVerify,
+}
+
+var nodestoreStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show object counts by type and size",
+	Args:  cobra.NoArgs,
+	RunE:  runNodestoreStats,
+}
+
+var nodestoreCopyCmd = &cobra.Command{
+	Use:   "copy <ledger-hash>",
+	Short: "Copy the objects reachable from a range of ledgers to another database",
+	Long: `Copy the headers, state trees and transaction trees of a range of ledgers
+into the database given by --to-path and --to-type, for example to prune a
+database down to recent history or to move it to another backend. Objects the
+destination already holds are rewritten unchanged.
+
+Example:
+    xrpld nodestore copy --conf xrpld.toml <hash> --count 1000 --to-path /var/lib/xrpld/db/pruned`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNodestoreCopy,
+}
+
+var nodestoreCompactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Compact the node database",
+	Args:  cobra.NoArgs,
+	RunE:  runNodestoreCompact,
+}
+
+func init() {
+	rootCmd.AddCommand(nodestoreCmd)
+	for _, cmd := range []*cobra.Command{nodestoreVerifyCmd, nodestoreStatsCmd, nodestoreCopyCmd, nodestoreCompactCmd} {
+		// Failures are data problems, not usage mistakes; Execute prints them.
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		nodestoreCmd.AddCommand(cmd)
+	}
+
+	nodestoreCmd.PersistentFlags().StringVar(&nodestorePath, "path", "", "node database path (default: [node_db] path)")
+	nodestoreCmd.PersistentFlags().StringVar(&nodestoreType, "type", "", "node database type: pebble or nudb (default: [node_db] type)")
+
+	for _, cmd := range []*cobra.Command{nodestoreVerifyCmd, nodestoreCopyCmd} {
+		cmd.Flags().Uint32Var(&nodestoreCount, "count", 1, "number of ledgers, following parent hashes back from the named ledger")
+	}
+	nodestoreCopyCmd.Flags().StringVar(&nodestoreToPath, "to-path", "", "destination database path (required)")
+	nodestoreCopyCmd.Flags().StringVar(&nodestoreToType, "to-type", "pebble", "destination database type")
+	_ = nodestoreCopyCmd.MarkFlagRequired("to-path")
+}
+
+// nodestoreSection returns the node database the command operates on.
+func nodestoreSection() (config.NodeDBConfig, error) {
+	var section config.NodeDBConfig
+	if globalConfig != nil {
+		section = globalConfig.NodeDB
+	}
+	if nodestorePath != "" {
+		section.Path = nodestorePath
+	}
+	if nodestoreType != "" {
+		section.Type = nodestoreType
+	}
+	if section.Path == "" {
+		return section, errors.New("no node database: pass --conf with a [node_db] section, or --path")
+	}
+	return section, nil
+}
+
+// openNodeBackend opens the node database in section the way the server
+// does: NuDB through its nodestore backend, Pebble through the kvstore layer.
+func openNodeBackend(section config.NodeDBConfig, readOnly bool) (nodestore.Backend, error) {
+	switch section.GetType() {
+	case "nudb":
+		backend, err := nodestore.CreateBackend("nudb", &nodestore.Config{
+			Path:            section.Path,
+			ReadOnly:        readOnly,
+			CreateIfMissing: !readOnly,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := backend.Open(!readOnly); err != nil {
+			return nil, err
+		}
+		return backend, nil
+	case "pebble", "":
+		store, err := kvpebble.New(section.Path, 256<<20, 500, readOnly)
+		if err != nil {
+			return nil, err
+		}
+		return nodestore.NewKVBackend(store, "pebble("+section.Path+")"), nil
+	default:
+		return nil, fmt.Errorf("unsupported node database type: %s", section.Type)
+	}
+}
+
+// openNodestore opens the node database selected by the command flags.
+func openNodestore(readOnly bool) (nodestore.Backend, error) {
+	section, err := nodestoreSection()
+	if err != nil {
+		return nil, err
+	}
+	return openNodeBackend(section, readOnly)
+}
+
+// backendFamily reads SHAMap nodes straight from a backend for
+// shamap.ScrubTree, bypassing the caches a running server would use.
+type backendFamily struct {
+	backend nodestore.Backend
+}
+
+// Fetch returns the stored node data. An object the backend cannot
+// decode is returned as empty data, which ScrubTree reports as corrupt.
+func (f backendFamily) Fetch(hash [32]byte) ([]byte, error) {
+	node, status := f.backend.Fetch(nodestore.Hash256(hash))
+	switch status {
+	case nodestore.OK:
+		return node.Data, nil
+	case nodestore.NotFound:
+		return nil, nil
+	case nodestore.DataCorrupt:
+		return []byte{}, nil
+	default:
+		return nil, fmt.Errorf("fetch failed: %s", status)
+	}
+}
+
+// StoreBatch is not supported; the scrubber only reads.
+func (f backendFamily) StoreBatch([]shamap.FlushEntry) error {
+	return errors.New("backendFamily is read-only")
+}
+
+// readLedgerHeader fetches the header of the ledger with the given hash
+// and checks that it hashes to it. Both rippled's format (hash prefix,
+// no hash) and the ledger service's (no prefix, trailing hash) are read.
+func readLedgerHeader(backend nodestore.Backend, hash [32]byte) (*header.LedgerHeader, error) {
+	node, status := backend.Fetch(nodestore.Hash256(hash))
+	switch status {
+	case nodestore.OK:
+	case nodestore.NotFound:
+		return nil, fmt.Errorf("ledger %X: header not found", hash)
+	default:
+		return nil, fmt.Errorf("ledger %X: fetch header: %s", hash, status)
+	}
+
+	hdr, err := header.DeserializeHeader(header.Unprefixed(node.Data), false)
+	if err != nil {
+		return nil, fmt.Errorf("ledger %X: corrupt header: %w", hash, err)
+	}
+	if computed := header.StoredHash(node.Data); computed != hash {
+		return nil, fmt.Errorf("ledger %X: corrupt header: hashes to %X", hash, computed)
+	}
+	hdr.Hash = hash
+	return hdr, nil
+}
+
+func parseLedgerHash(s string) ([32]byte, error) {
+	var hash [32]byte
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != len(hash) {
+		return hash, fmt.Errorf("invalid ledger hash: %s", s)
+	}
+	copy(hash[:], b)
+	return hash, nil
+}
+
+// walkLedgers calls fn for up to count ledgers, starting at the ledger
+// with the given hash and following parent hashes. It stops early at a
+// zero parent hash (before genesis) and fails if a header is missing or
+// corrupt.
+func walkLedgers(backend nodestore.Backend, hash [32]byte, count uint32, fn func(*header.LedgerHeader) error) error {
+	for i := uint32(0); i < count && hash != ([32]byte{}); i++ {
+		hdr, err := readLedgerHeader(backend, hash)
+		if err != nil {
+			return err
+		}
+		if err := fn(hdr); err != nil {
+			return err
+		}
+		hash = hdr.ParentHash
+	}
+	return nil
+}
+
+// scrubLedger walks the state and transaction trees of hdr, skipping
+// subtrees in seen and adding every verified node to it.
+func scrubLedger(ctx context.Context, backend nodestore.Backend, hdr *header.LedgerHeader,
+	seen map[[32]byte]struct{}, visit func(nodestore.NodeType, [32]byte, []byte) error) (*shamap.ScrubResult, error) {
+
+	total := &shamap.ScrubResult{}
+	for _, tree := range []struct {
+		root     [32]byte
+		nodeType nodestore.NodeType
+	}{
+		{hdr.AccountHash, nodestore.NodeAccount},
+		{hdr.TxHash, nodestore.NodeTransaction},
+	} {
+		nodeType := tree.nodeType
+		result, err := shamap.ScrubTree(ctx, backendFamily{backend}, tree.root, &shamap.ScrubOptions{
+			Skip: func(hash [32]byte) bool {
+				_, ok := seen[hash]
+				return ok
+			},
+			Visit: func(hash [32]byte, data []byte) error {
+				seen[hash] = struct{}{}
+				if visit != nil {
+					return visit(nodeType, hash, data)
+				}
+				return nil
+			},
+		})
+		if result != nil {
+			total.Add(result, shamap.DefaultScrubMaxReported)
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func printScrubProblems(result *shamap.ScrubResult) {
+	for _, h := range result.Missing {
+		fmt.Printf("    missing %X\n", h)
+	}
+	for _, h := range result.Corrupt {
+		fmt.Printf("    corrupt %X\n", h)
+	}
+}
+
+func runNodestoreVerify(cmd *cobra.Command, args []string) error {
+	backend, err := openNodestore(true)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	if len(args) == 0 {
+		fmt.Printf("Verifying every object in %s\n", backend.Name())
+		result, err := nodestore.NewBackendVerifier(backend).VerifyAll(&nodestore.VerifyOptions{
+			MaxCorruptNodes:  100,
+			ProgressInterval: 1000000,
+			ProgressCallback: func(n int64) { fmt.Printf("  %d objects checked\n", n) },
+			HashFunc:         nodeObjectHash,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(result)
+		for _, h := range result.CorruptHashes {
+			fmt.Printf("    corrupt %X\n", h)
+		}
+		if !result.IsValid() {
+			return errors.New("node database is corrupt")
+		}
+		return nil
+	}
+
+	hash, err := parseLedgerHash(args[0])
+	if err != nil {
+		return err
+	}
+	seen := make(map[[32]byte]struct{})
+	total := &shamap.ScrubResult{}
+	ledgers := 0
+	err = walkLedgers(backend, hash, nodestoreCount, func(hdr *header.LedgerHeader) error {
+		result, err := scrubLedger(cmd.Context(), backend, hdr, seen, nil)
+		if err != nil {
+			return fmt.Errorf("ledger %d: %w", hdr.LedgerIndex, err)
+		}
+		ledgers++
+		status := "OK"
+		if !result.IsValid() {
+			status = "DAMAGED"
+		}
+		fmt.Printf("Ledger %d %X: %s (%s)\n", hdr.LedgerIndex, hdr.Hash, status, result)
+		printScrubProblems(result)
+		total.Add(result, shamap.DefaultScrubMaxReported)
+		return nil
+	})
+	fmt.Printf("Verified %d ledgers: %s\n", ledgers, total)
+	if err != nil {
+		return err
+	}
+	if !total.IsValid() {
+		return fmt.Errorf("%d missing and %d corrupt nodes", total.MissingCount, total.CorruptCount)
+	}
+	return nil
+}
+
+func runNodestoreStats(cmd *cobra.Command, args []string) error {
+	backend, err := openNodestore(true)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	stats, err := nodestore.CollectStats(cmd.Context(), backend)
+	if err != nil {
+		return err
+	}
+	fmt.Print(stats)
+	return nil
+}
+
+func runNodestoreCopy(cmd *cobra.Command, args []string) error {
+	hash, err := parseLedgerHash(args[0])
+	if err != nil {
+		return err
+	}
+	src, err := openNodestore(true)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := openNodeBackend(config.NodeDBConfig{Type: nodestoreToType, Path: nodestoreToPath}, false)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	batch := make([]*nodestore.Node, 0, nodestore.DefaultImportBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if status := dst.StoreBatch(batch); status != nodestore.OK {
+			return fmt.Errorf("store batch: %s", status)
+		}
+		batch = batch[:0]
+		return nil
+	}
+	store := func(nodeType nodestore.NodeType, hash [32]byte, data []byte) error {
+		batch = append(batch, &nodestore.Node{Type: nodeType, Hash: nodestore.Hash256(hash), Data: data})
+		if len(batch) >= nodestore.DefaultImportBatchSize {
+			return flush()
+		}
+		return nil
+	}
+
+	seen := make(map[[32]byte]struct{})
+	total := &shamap.ScrubResult{}
+	ledgers := 0
+	err = walkLedgers(src, hash, nodestoreCount, func(hdr *header.LedgerHeader) error {
+		headerNode, _ := src.Fetch(nodestore.Hash256(hdr.Hash))
+		if err := store(nodestore.NodeLedger, hdr.Hash, headerNode.Data); err != nil {
+			return err
+		}
+		result, err := scrubLedger(cmd.Context(), src, hdr, seen, store)
+		if err != nil {
+			return fmt.Errorf("ledger %d: %w", hdr.LedgerIndex, err)
+		}
+		ledgers++
+		if !result.IsValid() {
+			fmt.Printf("Ledger %d %X: DAMAGED (%s)\n", hdr.LedgerIndex, hdr.Hash, result)
+			printScrubProblems(result)
+		}
+		total.Add(result, shamap.DefaultScrubMaxReported)
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err == nil {
+		if status := dst.Sync(); status != nodestore.OK {
+			err = fmt.Errorf("sync destination: %s", status)
+		}
+	}
+	fmt.Printf("Copied %d ledgers to %s: %s\n", ledgers, dst.Name(), total)
+	if err != nil {
+		return err
+	}
+	if !total.IsValid() {
+		return fmt.Errorf("copy is incomplete: %d missing and %d corrupt nodes in the source",
+			total.MissingCount, total.CorruptCount)
+	}
+	return nil
+}
+
+func runNodestoreCompact(cmd *cobra.Command, args []string) error {
+	backend, err := openNodestore(false)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	compactor, ok := backend.(interface{ Compact() error })
+	if !ok {
+		return fmt.Errorf("%s does not support compaction", backend.Name())
+	}
+	fmt.Printf("Compacting %s\n", backend.Name())
+	if err := compactor.Compact(); err != nil {
+		return err
+	}
+	fmt.Println("Compaction complete")
+	return nil
+}
@@ -13,8 +13,10 @@ import (
 	"syscall"
 	"time"
 
+	addresscodec "github.com/LeJamon/goXRPLd/codec/addresscodec"
 	binarycodec "github.com/LeJamon/goXRPLd/codec/binarycodec"
 	"github.com/LeJamon/goXRPLd/config"
+	"github.com/LeJamon/goXRPLd/internal/consensus"
 	"github.com/LeJamon/goXRPLd/internal/consensus/adaptor"
 	"github.com/LeJamon/goXRPLd/internal/ledger/genesis"
 	"github.com/LeJamon/goXRPLd/internal/ledger/service"
@@ -34,6 +36,7 @@ import (
 
 var (
 	standalone bool
+	importDB   bool
 )
 
 // serverCmd represents the server command (default action)
@@ -59,6 +62,7 @@ func init() {
 
 	// Server-specific flags — operational concerns only
 	serverCmd.Flags().BoolVarP(&standalone, "standalone", "a", false, "run in standalone mode (no peers)")
+	serverCmd.Flags().BoolVar(&importDB, "import", false, "import [import_db] into [node_db] before starting")
 }
 
 func runServer(cmd *cobra.Command, args []string) {
@@ -85,10 +89,31 @@ func runServer(cmd *cobra.Command, args []string) {
 
 	serverLog.Info("Starting goXRPLd", "version", version.Version)
 
+	if importDB {
+		if err := importNodeDB(cmd.Context(), globalConfig, serverLog); err != nil {
+			serverLog.Fatal("Node database import failed", "err", err)
+		}
+	}
+
 	// Initialize storage from config
 	var db nodestore.Database
 	nodestorePath := globalConfig.NodeDB.Path
-	if nodestorePath != "" {
+	if nodestorePath != "" && globalConfig.NodeDB.GetType() == "nudb" {
+		backend, err := nodestore.CreateBackend("nudb", &nodestore.Config{
+			Path:     nodestorePath,
+			ReadOnly: globalConfig.NodeDB.ReadOnly,
+		})
+		if err != nil {
+			serverLog.Fatal("Failed to create storage backend", "err", err)
+		}
+		if err := backend.Open(!globalConfig.NodeDB.ReadOnly); err != nil {
+			serverLog.Fatal("Failed to open storage backend", "err", err)
+		}
+
+		db = nodestore.NewDatabase(backend, 10000, 10*time.Minute)
+		serverLog.Info("Storage initialized", "backend", "nudb", "path", nodestorePath,
+			"read_only", globalConfig.NodeDB.ReadOnly)
+	} else if nodestorePath != "" {
 		store, err := kvpebble.New(nodestorePath, 256<<20, 500, false)
 		if err != nil {
 			serverLog.Fatal("Failed to create storage backend", "err", err)
@@ -112,7 +137,9 @@ func runServer(cmd *cobra.Command, args []string) {
 		if err != nil {
 			serverLog.Warn("PostgreSQL not available", "err", err)
 		} else {
-			if err := repoManager.Open(context.Background()); err != nil {
+			if err := repoManager.Open(context.Background()); errors.Is(err, relationaldb.ErrSchemaVersion) {
+				serverLog.Fatal("PostgreSQL schema is newer than this build", "err", err)
+			} else if err != nil {
 				serverLog.Warn("PostgreSQL connection failed", "err", err)
 				repoManager = nil
 			} else {
@@ -126,7 +153,9 @@ func runServer(cmd *cobra.Command, args []string) {
 		if err != nil {
 			serverLog.Warn("SQLite failed to initialize", "path", dbPath, "err", err)
 		} else {
-			if err := repoManager.Open(context.Background()); err != nil {
+			if err := repoManager.Open(context.Background()); errors.Is(err, relationaldb.ErrSchemaVersion) {
+				serverLog.Fatal("SQLite schema is newer than this build", "path", dbPath, "err", err)
+			} else if err != nil {
 				serverLog.Warn("SQLite failed to open", "path", dbPath, "err", err)
 				repoManager = nil
 			} else {
@@ -189,7 +218,11 @@ func runServer(cmd *cobra.Command, args []string) {
 		NetworkID:    uint32(networkID),
 		NodeStore:    db,
 		RelationalDB: repoManager,
-		Logger:       rootLogger,
+		History: service.HistoryConfig{
+			MaxLedgers: globalConfig.GetLedgerCacheSize(),
+			MaxBytes:   globalConfig.LedgerCacheMaxBytes,
+		},
+		Logger: rootLogger,
 	}
 	cfg.GenesisConfig = genesisConfig
 
@@ -205,12 +238,24 @@ func runServer(cmd *cobra.Command, args []string) {
 	// Wire up RPC services
 	ledgerAdapter := rpc.NewLedgerServiceAdapter(ledgerService)
 	types.InitServices(ledgerAdapter)
+	types.Services.MPTs = ledgerAdapter
+	types.Services.LedgerDiffs = ledgerAdapter
+	types.Services.Owners = ledgerAdapter
+	types.Services.Submissions = ledgerAdapter
+	if repoManager != nil {
+		types.Services.NFTIndex = ledgerAdapter
+	}
 
 	// Start consensus/networking if not in standalone mode
 	var consensusComponents *adaptor.Components
 	if !standalone {
 		var compErr error
-		consensusComponents, compErr = adaptor.NewFromConfig(globalConfig, ledgerService, repoManager.Validation())
+		var overlayOpts []peermanagement.Option
+		if repoManager != nil {
+			overlayOpts = append(overlayOpts,
+				peermanagement.WithReservationStore(adaptor.NewReservationStore(repoManager.PeerReservation())))
+		}
+		consensusComponents, compErr = adaptor.NewFromConfig(globalConfig, ledgerService, repoManager.Validation(), overlayOpts...)
 		if compErr != nil {
 			serverLog.Fatal("Failed to create consensus components", "err", compErr)
 		}
@@ -241,26 +286,28 @@ func runServer(cmd *cobra.Command, args []string) {
 			age := time.Since(vl.CloseTime())
 			return vl.Sequence(), age, true
 		})
-		ledgerAdapter.SetTxBroadcaster(func(txBlob []byte) {
+		ledgerAdapter.SetTxBroadcaster(func(txBlob []byte) int {
 			txMsg := &message.Transaction{
 				RawTransaction: txBlob,
 				Status:         message.TxStatusCurrent,
 			}
 			encoded, err := message.Encode(txMsg)
 			if err != nil {
-				return
+				return 0
 			}
 			frame, err := message.BuildWireMessage(message.TypeTransaction, encoded)
 			if err != nil {
-				return
+				return 0
 			}
-			overlay.Broadcast(frame)
+			relayed := overlay.BroadcastCount(frame)
 			consensusAdaptor.AddPendingTx(txBlob)
+			return relayed
 		})
 
 		// Expose node identity, peer count, and consensus stats to RPC handlers
 		types.Services.NodePublicKey = consensusComponents.Overlay.Identity().EncodedPublicKey()
 		types.Services.PeerCount = consensusComponents.Overlay.PeerCount
+		types.Services.ClusterLoadFee = consensusComponents.Overlay.ClusterFee
 		engine := consensusComponents.Engine
 		types.Services.LastCloseInfo = func() (int, int) {
 			proposers, convergeTime := engine.GetLastCloseInfo()
@@ -272,6 +319,33 @@ func runServer(cmd *cobra.Command, args []string) {
 		// RPC reads for external queries.
 		types.Services.Manifests = consensusComponents.Manifests
 
+		// Admin network RPCs (connect, peer_reservations_*, blacklist)
+		// act directly on the overlay.
+		types.Services.PeerAdmin = consensusComponents.Overlay
+
+		// unl_list: trusted validators from the UNL, flagged when the
+		// validated ledger's ltNEGATIVE_UNL currently disables them.
+		types.Services.UNLList = func() []types.UNLEntry {
+			disabled := make(map[consensus.NodeID]bool)
+			for _, id := range consensusAdaptor.GetNegativeUNL() {
+				disabled[id] = true
+			}
+			trusted := consensusAdaptor.GetTrustedValidators()
+			entries := make([]types.UNLEntry, 0, len(trusted))
+			for _, id := range trusted {
+				encoded, err := addresscodec.EncodeNodePublicKey(id[:])
+				if err != nil {
+					continue
+				}
+				entries = append(entries, types.UNLEntry{
+					PublicKey:   encoded,
+					Trusted:     true,
+					NegativeUNL: disabled[id],
+				})
+			}
+			return entries
+		}
+
 		// Expose the local validator's signing key to validator_info.
 		// Mirrors rippled's getValidationPublicKey gate: empty means
 		// the server is not configured as a validator and the handler
@@ -310,9 +384,26 @@ func runServer(cmd *cobra.Command, args []string) {
 
 	// Create a ledger info provider adapter for WebSocket subscribe responses
 	wsServer.SetLedgerInfoProvider(&ledgerInfoAdapter{ledgerService: ledgerService})
+	if repoManager != nil {
+		wsServer.SetTransactionReplayer(rpc.NewRelationalTxReplayer(repoManager))
+	}
 
 	publisher := rpc.NewPublisher(wsServer.GetSubscriptionManager())
 
+	// Ledgers validated before startup count as published, so that
+	// resume_ledger replays them from the relational database.
+	subManager := wsServer.GetSubscriptionManager()
+	subManager.Publish(func() {
+		subManager.SetPublishedLedger(ledgerService.GetValidatedLedgerIndex())
+	})
+	types.Services.URLSubscriptions = wsServer.GetSubscriptionManager()
+
+	// Report the state changes of submitted transactions to the
+	// connections that submitted them
+	ledgerService.SetSubmissionCallback(func(sub service.Submission) {
+		publisher.PublishSubmission(rpc.SubmissionStatus(sub))
+	})
+
 	// Wire up ledger service events to WebSocket broadcasts
 	ledgerService.SetEventCallback(func(event *service.LedgerAcceptedEvent) {
 		if event == nil || event.LedgerInfo == nil {
@@ -336,8 +427,8 @@ func runServer(cmd *cobra.Command, args []string) {
 			TxnCount:         len(event.TransactionResults),
 			ValidatedLedgers: "",
 		}
-		publisher.PublishLedgerClosed(ledgerCloseEvent)
 
+		txs := make([]rpc.LedgerTransaction, 0, len(event.TransactionResults))
 		for _, txResult := range event.TransactionResults {
 			// Decode binary tx+meta blob to JSON for the event.
 			// TxData is VL-encoded: [VL-length][tx_blob][VL-length][meta_blob]
@@ -355,8 +446,9 @@ func runServer(cmd *cobra.Command, args []string) {
 				Hash:                hex.EncodeToString(txResult.TxHash[:]),
 				Validated:           txResult.Validated,
 			}
-			publisher.PublishTransaction(txEvent, txResult.AffectedAccounts)
+			txs = append(txs, rpc.LedgerTransaction{Event: txEvent, Accounts: txResult.AffectedAccounts})
 		}
+		publisher.PublishLedger(ledgerCloseEvent, txs)
 
 		// Update persistent path_find sessions on ledger close
 		wsServer.UpdatePathFindSessions(func() (types.LedgerStateView, error) {
@@ -406,10 +498,15 @@ func runServer(cmd *cobra.Command, args []string) {
 			serverLog.Fatal("Failed to parse admin nets for port", "name", name, "err", err)
 		}
 		pc := &rpc.PortContext{
-			PortName:  name,
-			AdminNets: adminNets,
-			Limit:     portCfg.Limit,
-			SendQueue: portCfg.SendQueueLimit,
+			PortName:     name,
+			AdminNets:    adminNets,
+			Limit:        portCfg.Limit,
+			SendQueue:    portCfg.SendQueueLimit,
+			Deflate:      portCfg.PermessageDeflate,
+			DeflateLevel: portCfg.CompressLevel,
+		}
+		if unsupported := rpc.UnsupportedDeflateSettings(&portCfg); portCfg.PermessageDeflate && len(unsupported) > 0 {
+			serverLog.Warn("Ignoring unsupported permessage-deflate settings", "name", name, "settings", unsupported)
 		}
 		mux := http.NewServeMux()
 		mux.Handle("/", rpc.PortMiddleware(pc, connLimiter, wsServer))
@@ -526,8 +623,7 @@ func doShutdown(
 		logger.Info("Consensus components stopped")
 	}
 
-	// Note: ledgerService has no Stop method; it is garbage collected
-	_ = ledgerService
+	ledgerService.Stop()
 	if kvDB != nil {
 		kvDB.Close()
 	}
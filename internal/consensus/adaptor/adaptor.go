@@ -95,6 +95,14 @@ type NetworkSender interface {
 	// arrival, matching rippled's haveMessage set semantics
 	// (PeerImp.cpp:3010-3017).
 	PeersThatHave(suppressionHash [32]byte) []uint64
+	// IsClusterPeer reports whether the peer handshaked under a node
+	// key listed in [cluster_nodes]. Cluster members are trusted: their
+	// transactions are relayed without being charged, mirroring
+	// rippled's SF_TRUSTED handling in PeerImp::handleTransaction.
+	IsClusterPeer(peerID uint64) bool
+	// RelayTransaction forwards a transaction blob to every connected
+	// peer except exceptPeer.
+	RelayTransaction(txBlob []byte, exceptPeer uint64) error
 }
 
 // noopSender is a no-op NetworkSender for standalone or test use.
@@ -117,6 +125,8 @@ func (n *noopSender) PeerSupportsReplay(uint64) bool                           {
 func (n *noopSender) ReplayCapablePeersExcluding([]uint64, int) []uint64       { return nil }
 func (n *noopSender) IncPeerBadData(uint64, string)                            {}
 func (n *noopSender) PeersThatHave([32]byte) []uint64                          { return nil }
+func (n *noopSender) IsClusterPeer(uint64) bool                                { return false }
+func (n *noopSender) RelayTransaction([]byte, uint64) error                    { return nil }
 
 // Compile-time interface check.
 var _ consensus.Adaptor = (*Adaptor)(nil)
@@ -148,6 +158,9 @@ type Adaptor struct {
 	// Pending transactions (raw blobs) from RPC submissions and peer relay
 	pendingTxsMu sync.RWMutex
 	pendingTxs   map[consensus.TxID][]byte
+	// clusterTxs holds the pending transactions relayed by cluster
+	// members, whose signatures are not checked again.
+	clusterTxs map[consensus.TxID]struct{}
 
 	// Peer-reported last-closed ledger hashes, keyed by overlay peer
 	// ID. Populated by the router on every inbound statusChange so
@@ -271,7 +284,7 @@ func New(cfg Config) *Adaptor {
 		amendmentVoteIDs = append(amendmentVoteIDs, f.ID)
 	}
 
-	return &Adaptor{
+	a := &Adaptor{
 		ledgerService:     cfg.LedgerService,
 		sender:            sender,
 		identity:          cfg.Identity,
@@ -281,12 +294,17 @@ func New(cfg Config) *Adaptor {
 		operatingMode:     consensus.OpModeDisconnected,
 		txSetCache:        NewTxSetCache(),
 		pendingTxs:        make(map[consensus.TxID][]byte),
+		clusterTxs:        make(map[consensus.TxID]struct{}),
 		peerLCLs:          make(map[uint64]consensus.LedgerID),
 		cookie:            cookie,
 		feeVote:           cfg.FeeVote,
 		amendmentVoteIDs:  amendmentVoteIDs,
 		logger:            logger,
 	}
+	if a.ledgerService != nil {
+		a.ledgerService.SetSignatureVerified(a.isClusterTx)
+	}
+	return a
 }
 
 // UpdatePeerLCL records the last-closed-ledger hash a peer reported
@@ -338,6 +356,17 @@ func (a *Adaptor) PeersThatHave(suppressionHash [32]byte) []uint64 {
 	return a.sender.PeersThatHave(suppressionHash)
 }
 
+// IsClusterPeer reports whether peerID is a [cluster_nodes] member.
+func (a *Adaptor) IsClusterPeer(peerID uint64) bool {
+	return a.sender.IsClusterPeer(peerID)
+}
+
+// RelayTransaction forwards a peer-originated transaction to every
+// other peer, excluding exceptPeer (the originator).
+func (a *Adaptor) RelayTransaction(txBlob []byte, exceptPeer uint64) error {
+	return a.sender.RelayTransaction(txBlob, exceptPeer)
+}
+
 // RelayProposal forwards a peer-originated proposal to other peers,
 // excluding exceptPeer (the originator). Pass 0 for exceptPeer to
 // forward to everyone. Uses proposal.SuppressionHash (populated by
@@ -591,11 +620,33 @@ func (a *Adaptor) AddPendingTx(blob []byte) {
 	a.pendingTxs[txID] = blob
 }
 
+// AddClusterPendingTx adds a transaction relayed by a cluster member to
+// the pending pool. The member already checked its signature, so
+// applying it skips signature verification, as rippled's
+// PeerImp::handleTransaction does for SF_TRUSTED transactions.
+func (a *Adaptor) AddClusterPendingTx(blob []byte) {
+	txID := computeTxID(blob)
+	a.pendingTxsMu.Lock()
+	defer a.pendingTxsMu.Unlock()
+	a.pendingTxs[txID] = blob
+	a.clusterTxs[txID] = struct{}{}
+}
+
+// isClusterTx reports whether txID is a pending transaction relayed by a
+// cluster member.
+func (a *Adaptor) isClusterTx(txID [32]byte) bool {
+	a.pendingTxsMu.RLock()
+	defer a.pendingTxsMu.RUnlock()
+	_, ok := a.clusterTxs[consensus.TxID(txID)]
+	return ok
+}
+
 // ClearPendingTxs removes all pending transactions.
 func (a *Adaptor) ClearPendingTxs() {
 	a.pendingTxsMu.Lock()
 	defer a.pendingTxsMu.Unlock()
 	a.pendingTxs = make(map[consensus.TxID][]byte)
+	a.clusterTxs = make(map[consensus.TxID]struct{})
 }
 
 // RemovePendingTxs removes specific transactions from the pending pool.
@@ -607,6 +658,7 @@ func (a *Adaptor) RemovePendingTxs(txBlobs [][]byte) {
 	for _, blob := range txBlobs {
 		txID := computeTxID(blob)
 		delete(a.pendingTxs, txID)
+		delete(a.clusterTxs, txID)
 	}
 }
 
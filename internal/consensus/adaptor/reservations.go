@@ -0,0 +1,48 @@
+package adaptor
+
+import (
+	"context"
+
+	"github.com/LeJamon/goXRPLd/internal/peermanagement"
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+// reservationStore adapts the relational peer-reservation repository to
+// peermanagement.ReservationStore so peer_reservations_add/_del survive
+// a restart (rippled persists them in the wallet DB's PeerReservations
+// table; goXRPL keeps them in the relational DB).
+type reservationStore struct {
+	repo relationaldb.PeerReservationRepository
+}
+
+// NewReservationStore wraps repo for use with
+// peermanagement.WithReservationStore.
+func NewReservationStore(repo relationaldb.PeerReservationRepository) peermanagement.ReservationStore {
+	return &reservationStore{repo: repo}
+}
+
+func (s *reservationStore) LoadReservations() ([]peermanagement.PeerReservation, error) {
+	records, err := s.repo.List(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	out := make([]peermanagement.PeerReservation, 0, len(records))
+	for _, rec := range records {
+		out = append(out, peermanagement.PeerReservation{
+			NodeID:      rec.PublicKey,
+			Description: rec.Description,
+		})
+	}
+	return out, nil
+}
+
+func (s *reservationStore) SaveReservation(r peermanagement.PeerReservation) error {
+	return s.repo.Upsert(context.Background(), &relationaldb.PeerReservationRecord{
+		PublicKey:   r.NodeID,
+		Description: r.Description,
+	})
+}
+
+func (s *reservationStore) DeleteReservation(nodeID string) error {
+	return s.repo.Delete(context.Background(), nodeID)
+}
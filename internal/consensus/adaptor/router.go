@@ -66,6 +66,12 @@ type Router struct {
 	// does for the same traffic pattern.
 	messageSeen *messageSuppression
 
+	// txSeen dedups inbound transactions by ID so a cluster-originated
+	// transaction is relayed once, however many cluster members send
+	// it. Kept apart from messageSeen so transaction bursts cannot
+	// trim proposal/validation entries.
+	txSeen *messageSuppression
+
 	// manifests is the validator manifest cache. Wired by the
 	// Components bootstrap so the router can apply inbound TMManifests
 	// frames and — on Accepted — relay them to other peers.
@@ -103,6 +109,7 @@ func NewRouter(engine consensus.Engine, adaptor *Adaptor, modeManager *ModeManag
 		peerStates:  make(map[peermanagement.PeerID]*peerLedgerState),
 		replayer:    inbound.NewReplayer(logger, inbound.SystemClock, inbound.DefaultMaxInFlightReplays),
 		messageSeen: newMessageSuppression(messageDedupTTL, messageDedupMaxEntries),
+		txSeen:      newMessageSuppression(messageDedupTTL, messageDedupMaxEntries),
 	}
 }
 
@@ -543,10 +550,23 @@ func validateValidationBounds(v *consensus.Validation) (string, bool) {
 	return "", true
 }
 
+// handleTransaction queues a peer-relayed transaction. A transaction
+// from a cluster member is trusted — the member already checked it —
+// so its signature is not checked again, it is relayed on to every
+// other peer the first time it is seen and its sender is never charged
+// for it, mirroring the SF_TRUSTED path in rippled's
+// PeerImp::handleTransaction. Non-cluster senders are charged for
+// undecodable frames.
 func (r *Router) handleTransaction(msg *peermanagement.InboundMessage) {
+	peerID := uint64(msg.PeerID)
+	cluster := r.adaptor.IsClusterPeer(peerID)
+
 	decoded, err := message.Decode(message.TypeTransaction, msg.Payload)
 	if err != nil {
 		r.logger.Warn("failed to decode transaction", "error", err, "peer", msg.PeerID)
+		if !cluster {
+			r.adaptor.IncPeerBadData(peerID, "transaction-decode")
+		}
 		return
 	}
 	txMsg, ok := decoded.(*message.Transaction)
@@ -559,7 +579,17 @@ func (r *Router) handleTransaction(msg *peermanagement.InboundMessage) {
 		return
 	}
 
-	r.adaptor.AddPendingTx(blob)
+	if !cluster {
+		r.adaptor.AddPendingTx(blob)
+		return
+	}
+	r.adaptor.AddClusterPendingTx(blob)
+	if firstSeen, _ := r.txSeen.observe(computeTxID(blob)); !firstSeen {
+		return
+	}
+	if err := r.adaptor.RelayTransaction(blob, peerID); err != nil {
+		r.logger.Debug("failed to relay cluster transaction", "error", err, "peer", msg.PeerID)
+	}
 }
 
 func (r *Router) handleHaveSet(msg *peermanagement.InboundMessage) {
@@ -0,0 +1,115 @@
+package adaptor
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/internal/peermanagement"
+	"github.com/LeJamon/goXRPLd/internal/peermanagement/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type txRelayCall struct {
+	blob       []byte
+	exceptPeer uint64
+}
+
+// clusterSender marks a fixed set of peers as cluster members and
+// records transaction relays on top of the bad-data recorder.
+type clusterSender struct {
+	badDataRecordingSender
+	cluster map[uint64]bool
+
+	relayMu sync.Mutex
+	relays  []txRelayCall
+}
+
+func (s *clusterSender) IsClusterPeer(peerID uint64) bool { return s.cluster[peerID] }
+
+func (s *clusterSender) RelayTransaction(blob []byte, exceptPeer uint64) error {
+	s.relayMu.Lock()
+	defer s.relayMu.Unlock()
+	s.relays = append(s.relays, txRelayCall{blob: append([]byte(nil), blob...), exceptPeer: exceptPeer})
+	return nil
+}
+
+func (s *clusterSender) getRelays() []txRelayCall {
+	s.relayMu.Lock()
+	defer s.relayMu.Unlock()
+	return append([]txRelayCall(nil), s.relays...)
+}
+
+func txInbound(t *testing.T, peer uint64, payload []byte) *peermanagement.InboundMessage {
+	t.Helper()
+	if payload == nil {
+		var err error
+		payload, err = message.Encode(TransactionToMessage([]byte{0x12, 0x00, 0x00, 0x24, 0x00, 0x00, 0x00, 0x01}))
+		require.NoError(t, err)
+	}
+	return &peermanagement.InboundMessage{
+		PeerID:  peermanagement.PeerID(peer),
+		Type:    uint16(message.TypeTransaction),
+		Payload: payload,
+	}
+}
+
+// TestRouter_HandleTransaction_ClusterRelay covers the SF_TRUSTED
+// path: a cluster member's transaction is queued and relayed once to
+// everyone but the sender, while the same transaction from an outside
+// peer is queued but not relayed.
+func TestRouter_HandleTransaction_ClusterRelay(t *testing.T) {
+	cs := &clusterSender{cluster: map[uint64]bool{7: true, 8: true}}
+	a := New(Config{LedgerService: newTestLedgerService(t), Sender: cs})
+	r := NewRouter(nil, a, nil, make(chan *peermanagement.InboundMessage, 1))
+
+	r.handleMessage(txInbound(t, 42, nil))
+	assert.Empty(t, cs.getRelays(), "transactions from non-cluster peers are not relayed")
+	assert.Len(t, a.GetPendingTxs(), 1)
+
+	r.handleMessage(txInbound(t, 7, nil))
+	relays := cs.getRelays()
+	require.Len(t, relays, 1)
+	assert.Equal(t, uint64(7), relays[0].exceptPeer)
+
+	r.handleMessage(txInbound(t, 8, nil))
+	assert.Len(t, cs.getRelays(), 1, "a transaction is relayed once however many members send it")
+	assert.Len(t, a.GetPendingTxs(), 1)
+}
+
+// TestRouter_HandleTransaction_ClusterSkipsSignature checks that only a
+// transaction relayed by a cluster member is vouched for to the ledger
+// service, so that only it skips signature verification.
+func TestRouter_HandleTransaction_ClusterSkipsSignature(t *testing.T) {
+	cs := &clusterSender{cluster: map[uint64]bool{7: true}}
+	a := New(Config{LedgerService: newTestLedgerService(t), Sender: cs})
+	r := NewRouter(nil, a, nil, make(chan *peermanagement.InboundMessage, 1))
+
+	blob := []byte{0x12, 0x00, 0x00, 0x24, 0x00, 0x00, 0x00, 0x01}
+	r.handleMessage(txInbound(t, 42, nil))
+	assert.False(t, a.isClusterTx(computeTxID(blob)))
+
+	r.handleMessage(txInbound(t, 7, nil))
+	assert.True(t, a.isClusterTx(computeTxID(blob)))
+
+	a.ClearPendingTxs()
+	assert.False(t, a.isClusterTx(computeTxID(blob)))
+}
+
+// TestRouter_HandleTransaction_DecodeFailureCharging checks that an
+// undecodable transaction charges an outside peer but never a cluster
+// member, whose unlimited consumer rippled never charges.
+func TestRouter_HandleTransaction_DecodeFailureCharging(t *testing.T) {
+	cs := &clusterSender{cluster: map[uint64]bool{7: true}}
+	a := New(Config{LedgerService: newTestLedgerService(t), Sender: cs})
+	r := NewRouter(nil, a, nil, make(chan *peermanagement.InboundMessage, 1))
+
+	garbage := []byte{0xFF, 0xFE, 0xFD, 0xFC}
+	r.handleMessage(txInbound(t, 7, garbage))
+	assert.Empty(t, cs.getBadDataCalls())
+
+	r.handleMessage(txInbound(t, 42, garbage))
+	calls := cs.getBadDataCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, badDataCall{peerID: 42, reason: "transaction-decode"}, calls[0])
+}
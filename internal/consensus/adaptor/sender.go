@@ -225,6 +225,22 @@ func (s *OverlaySender) PeersThatHave(suppressionHash [32]byte) []uint64 {
 	return out
 }
 
+// IsClusterPeer forwards to Overlay.IsClusterPeer.
+func (s *OverlaySender) IsClusterPeer(peerID uint64) bool {
+	return s.overlay.IsClusterPeer(peermanagement.PeerID(peerID))
+}
+
+// RelayTransaction wraps txBlob in a TMTransaction and sends it to
+// every connected peer except exceptPeer. Transactions carry no
+// validator key, so the reduce-relay squelch filter does not apply.
+func (s *OverlaySender) RelayTransaction(txBlob []byte, exceptPeer uint64) error {
+	frame, err := encodeFrame(message.TypeTransaction, TransactionToMessage(txBlob))
+	if err != nil {
+		return fmt.Errorf("encode transaction: %w", err)
+	}
+	return s.overlay.BroadcastExcept(peermanagement.PeerID(exceptPeer), frame)
+}
+
 // RequestReplayDelta asks a specific peer for a fast-catchup replay delta
 // (header + every tx blob, in tx-map order) for the given ledger hash.
 // Mirrors rippled's LedgerDeltaAcquire::trigger which sends a
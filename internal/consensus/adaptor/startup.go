@@ -96,10 +96,15 @@ func (c *Components) Stop() {
 // validationRepo is optional — pass nil to disable the on-disk validation
 // archive. When non-nil and [validation_archive] is enabled in config,
 // stale validations are persisted via a batched async writer.
+//
+// extraOpts are appended after the config-derived overlay options, for
+// wiring that needs process-level resources the config does not carry
+// (e.g. the relational-DB-backed peer reservation store).
 func NewFromConfig(
 	appCfg *config.Config,
 	ledgerSvc *service.Service,
 	validationRepo relationaldb.ValidationRepository,
+	extraOpts ...peermanagement.Option,
 ) (*Components, error) {
 	// Create validator identity first (nil if not a validator) so we can
 	// pass its pubkey into the overlay for the self-target TMSquelch
@@ -120,6 +125,7 @@ func NewFromConfig(
 		overlayOpts = append(overlayOpts,
 			peermanagement.WithLocalValidatorPubKey(identity.PublicKey))
 	}
+	overlayOpts = append(overlayOpts, extraOpts...)
 
 	overlay, err := peermanagement.New(overlayOpts...)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/hex"
 
+	"github.com/LeJamon/goXRPLd/shamap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -405,73 +406,31 @@ func (s *Server) GetLedgerDiff(ctx context.Context, req *GetLedgerDiffRequest) (
 		return nil, status.Error(codes.NotFound, "desired ledger not found: "+err.Error())
 	}
 
-	// Collect entries from both ledgers
-	baseEntries := make(map[[32]byte][]byte)
-	desiredEntries := make(map[[32]byte][]byte)
-
-	// Collect base ledger entries
-	err = baseLedger.ForEach(func(key [32]byte, data []byte) bool {
-		if req.IncludeBlobs {
-			dataCopy := make([]byte, len(data))
-			copy(dataCopy, data)
-			baseEntries[key] = dataCopy
-		} else {
-			baseEntries[key] = nil
+	// Walk the state maps together, skipping the subtrees they share
+	var differences []LedgerDiffEntry
+	err = baseLedger.ForEachStateDifference(desiredLedger, nil, func(diff shamap.DifferenceItem) bool {
+		entry := LedgerDiffEntry{Key: diff.Key}
+		switch diff.Type {
+		case shamap.DiffAdded:
+			entry.DiffType = "created"
+		case shamap.DiffRemoved:
+			entry.DiffType = "deleted"
+		default:
+			entry.DiffType = "modified"
 		}
-		return true
-	})
-	if err != nil {
-		return nil, status.Error(codes.Internal, "failed to iterate base ledger")
-	}
-
-	// Collect desired ledger entries
-	err = desiredLedger.ForEach(func(key [32]byte, data []byte) bool {
 		if req.IncludeBlobs {
-			dataCopy := make([]byte, len(data))
-			copy(dataCopy, data)
-			desiredEntries[key] = dataCopy
-		} else {
-			desiredEntries[key] = nil
+			if diff.FirstItem != nil {
+				entry.OldData = diff.FirstItem.Data()
+			}
+			if diff.SecondItem != nil {
+				entry.NewData = diff.SecondItem.Data()
+			}
 		}
+		differences = append(differences, entry)
 		return true
 	})
 	if err != nil {
-		return nil, status.Error(codes.Internal, "failed to iterate desired ledger")
-	}
-
-	// Calculate differences
-	var differences []LedgerDiffEntry
-
-	// Check for created and modified entries
-	for key, newData := range desiredEntries {
-		oldData, exists := baseEntries[key]
-		if !exists {
-			// Entry was created
-			differences = append(differences, LedgerDiffEntry{
-				Key:      key,
-				DiffType: "created",
-				NewData:  newData,
-			})
-		} else if req.IncludeBlobs && !bytesEqual(oldData, newData) {
-			// Entry was modified
-			differences = append(differences, LedgerDiffEntry{
-				Key:      key,
-				DiffType: "modified",
-				OldData:  oldData,
-				NewData:  newData,
-			})
-		}
-	}
-
-	// Check for deleted entries
-	for key, oldData := range baseEntries {
-		if _, exists := desiredEntries[key]; !exists {
-			differences = append(differences, LedgerDiffEntry{
-				Key:      key,
-				DiffType: "deleted",
-				OldData:  oldData,
-			})
-		}
+		return nil, status.Error(codes.Internal, "failed to compare ledgers")
 	}
 
 	resp := &GetLedgerDiffResponse{
@@ -485,19 +444,6 @@ func (s *Server) GetLedgerDiff(ctx context.Context, req *GetLedgerDiffRequest) (
 	return resp, nil
 }
 
-// bytesEqual compares two byte slices for equality.
-func bytesEqual(a, b []byte) bool {
-	if len(a) != len(b) {
-		return false
-	}
-	for i := range a {
-		if a[i] != b[i] {
-			return false
-		}
-	}
-	return true
-}
-
 // GetLedgerObjectRequest is a convenience request for getting objects by different specifiers.
 type GetLedgerObjectRequest struct {
 	// Specifier identifies which ledger to query
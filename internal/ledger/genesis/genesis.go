@@ -462,79 +462,26 @@ func CalculateLedgerHash(h header.LedgerHeader) [32]byte {
 	return common.Sha512Half(data)
 }
 
-// serializeAccountRoot serializes an AccountRoot entry to bytes using the XRPL binary codec.
+// SerializeAccountRoot serializes an AccountRoot entry to bytes.
+// All soeREQUIRED fields for AccountRoot are written, including the zero
+// PreviousTxnID and PreviousTxnLgrSeq of an account that was never threaded.
+// Reference: rippled ledger_entries.macro lines 147-153
 func SerializeAccountRoot(a *ledgerentries.AccountRoot) ([]byte, error) {
-	// Convert account ID to classic address
-	address, err := addresscodec.EncodeAccountIDToClassicAddress(a.Account[:])
-	if err != nil {
-		return nil, fmt.Errorf("failed to encode account address: %w", err)
-	}
-
-	// Build the JSON representation for the binary codec.
-	// All soeREQUIRED fields for AccountRoot must be present (rippled auto-initializes them):
-	// LedgerEntryType, Flags, Account, Sequence, Balance, OwnerCount,
-	// PreviousTxnID, PreviousTxnLgrSeq
-	// Reference: rippled ledger_entries.macro lines 147-153
-	jsonObj := map[string]any{
-		"LedgerEntryType":   "AccountRoot",
-		"Flags":             uint32(0),
-		"Account":           address,
-		"Balance":           fmt.Sprintf("%d", a.Balance), // XRP balance as drops string
-		"Sequence":          a.Sequence,
-		"OwnerCount":        uint32(0),
-		"PreviousTxnID":     "0000000000000000000000000000000000000000000000000000000000000000",
-		"PreviousTxnLgrSeq": uint32(0),
-	}
-
-	// Encode using the binary codec
-	hexStr, err := binarycodec.Encode(jsonObj)
+	data, err := a.MarshalBinary()
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode AccountRoot: %w", err)
 	}
-
-	// Convert hex string to bytes
-	return hex.DecodeString(hexStr)
+	return data, nil
 }
 
-// serializeFeeSettings serializes a FeeSettings entry to bytes using the XRPL binary codec.
+// serializeFeeSettings serializes a FeeSettings entry to bytes, in the legacy
+// format when its legacy BaseFee is set and the XRPFees format otherwise.
 func serializeFeeSettings(f *ledgerentries.FeeSettings) ([]byte, error) {
-	// Build the JSON representation for the binary codec.
-	// Rippled auto-initializes Flags=0 as a required field.
-	jsonObj := map[string]any{
-		"LedgerEntryType": "FeeSettings",
-		"Flags":           uint32(0),
-	}
-
-	if f.IsUsingModernFees() {
-		// Modern format (XRPFees amendment) - uses Amount fields
-		jsonObj["BaseFeeDrops"] = fmt.Sprintf("%d", f.BaseFeeDrops)
-		jsonObj["ReserveBaseDrops"] = fmt.Sprintf("%d", f.ReserveBaseDrops)
-		jsonObj["ReserveIncrementDrops"] = fmt.Sprintf("%d", f.ReserveIncrementDrops)
-	} else {
-		// Legacy format - uses UInt64/UInt32 fields
-		// UInt64 fields (BaseFee) must be hex strings without leading zeros
-		if f.BaseFee != nil {
-			jsonObj["BaseFee"] = fmt.Sprintf("%x", *f.BaseFee)
-		}
-		if f.ReferenceFeeUnits != nil {
-			jsonObj["ReferenceFeeUnits"] = *f.ReferenceFeeUnits
-		}
-		if f.ReserveBase != nil {
-			jsonObj["ReserveBase"] = *f.ReserveBase
-		}
-		if f.ReserveIncrement != nil {
-			jsonObj["ReserveIncrement"] = *f.ReserveIncrement
-		}
-	}
-
-	// Encode using the binary codec
-	hexStr, err := binarycodec.Encode(jsonObj)
+	data, err := f.MarshalBinary()
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode FeeSettings: %w", err)
 	}
-
-	// Convert hex string to bytes
-	return hex.DecodeString(hexStr)
+	return data, nil
 }
 
 // serializeAmendments serializes an amendments list to bytes using the XRPL binary codec.
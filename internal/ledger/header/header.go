@@ -5,6 +5,9 @@ import (
 	"encoding/binary"
 	"errors"
 	"time"
+
+	"github.com/LeJamon/goXRPLd/crypto/common"
+	"github.com/LeJamon/goXRPLd/protocol"
 )
 
 // LCFNoConsensusTime Ledger close flags
@@ -213,3 +216,49 @@ func xrplEpochToTime(epoch uint32) time.Time {
 	}
 	return time.Unix(int64(epoch)+xrplEpochOffset, 0)
 }
+
+// NodeObjectHash returns the key a node object must be stored under: the
+// SHA-512Half of its data for SHAMap nodes and rippled-format headers, and
+// the ledger hash for headers written by the ledger service, which carry
+// no hash prefix and end with the hash itself.
+func NodeObjectHash(data []byte) [32]byte {
+	if len(data) == SizeWithHash && !hasKnownHashPrefix(data) {
+		return StoredHash(data)
+	}
+	return common.Sha512Half(data)
+}
+
+// StoredHash returns the ledger hash of a stored header: the SHA-512Half
+// of the prefixed header fields, without any trailing hash. Both rippled's
+// format (hash prefix, no hash) and the ledger service's (no prefix,
+// trailing hash) are accepted.
+func StoredHash(data []byte) [32]byte {
+	raw := Unprefixed(data)
+	if len(raw) > SizeBase {
+		raw = raw[:SizeBase]
+	}
+	return common.Sha512Half(protocol.HashPrefixLedgerMaster.Bytes(), raw)
+}
+
+// Unprefixed strips the hash prefix from a rippled-format stored header.
+func Unprefixed(data []byte) []byte {
+	if bytes.HasPrefix(data, protocol.HashPrefixLedgerMaster.Bytes()) {
+		return data[len(protocol.HashPrefixLedgerMaster):]
+	}
+	return data
+}
+
+func hasKnownHashPrefix(data []byte) bool {
+	for _, p := range []protocol.HashPrefix{
+		protocol.HashPrefixLedgerMaster,
+		protocol.HashPrefixInnerNode,
+		protocol.HashPrefixLeafNode,
+		protocol.HashPrefixTxNode,
+		protocol.HashPrefixTransactionID,
+	} {
+		if bytes.HasPrefix(data, p[:]) {
+			return true
+		}
+	}
+	return false
+}
@@ -817,6 +817,22 @@ func (l *Ledger) ForEach(fn func(key [32]byte, data []byte) bool) error {
 	})
 }
 
+// ForEachStateDifference calls fn, in key order, for each state entry that
+// differs between l and desired, skipping keys up to and including after
+// when it is non-nil. If fn returns false, iteration stops early. The
+// difference's FirstItem is the entry in l, its SecondItem the one in
+// desired.
+func (l *Ledger) ForEachStateDifference(desired *Ledger, after *[32]byte, fn func(shamap.DifferenceItem) bool) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if desired != l {
+		desired.mu.RLock()
+		defer desired.mu.RUnlock()
+	}
+
+	return l.stateMap.WalkDifferences(desired.stateMap, after, fn)
+}
+
 // Succ returns the first state entry with key > the given key.
 // Uses SHAMap's UpperBound for O(log n) lookup.
 // Reference: rippled ReadView::succ()
@@ -875,6 +891,32 @@ func (l *Ledger) SetStateMapFamily(family shamap.Family) {
 	l.stateMap.SetFamily(family)
 }
 
+// FlushMaps writes the state and transaction map nodes that have not been
+// stored yet to family, so the ledger can later be rebuilt from its header
+// with shamap.NewFromRootHash. An empty map has a zero hash and nothing to
+// store. Returns the number of nodes written.
+func (l *Ledger) FlushMaps(family shamap.FlushingFamily) (int, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var count int
+	for _, m := range []*shamap.SHAMap{l.stateMap, l.txMap} {
+		hash, err := m.Hash()
+		if err != nil {
+			return count, err
+		}
+		if hash == ([32]byte{}) {
+			continue
+		}
+		n, err := family.FlushMap(m, false)
+		count += n
+		if err != nil {
+			return count, fmt.Errorf("flush %s map: %w", m.Type(), err)
+		}
+	}
+	return count, nil
+}
+
 // SerializeHeader returns the serialized ledger header bytes
 func (l *Ledger) SerializeHeader() []byte {
 	l.mu.RLock()
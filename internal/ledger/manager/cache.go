@@ -2,6 +2,7 @@ package manager
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/LeJamon/goXRPLd/internal/ledger"
 	lru "github.com/hashicorp/golang-lru/v2"
@@ -23,7 +24,7 @@ type LedgerCache struct {
 	// Track which ledgers we have complete locally
 	completeness *CompleteLedgerSet
 
-	// Metrics
+	// Metrics, updated atomically since lookups only take the read lock
 	hits   uint64
 	misses uint64
 }
@@ -64,11 +65,11 @@ func (c *LedgerCache) Get(seq uint32) (*ledger.Ledger, bool) {
 
 	ledgerValue, found := c.recentBySeq.Get(seq)
 	if found {
-		c.hits++
+		atomic.AddUint64(&c.hits, 1)
 		return ledgerValue, true
 	}
 
-	c.misses++
+	atomic.AddUint64(&c.misses, 1)
 	return nil, false
 }
 
@@ -79,11 +80,11 @@ func (c *LedgerCache) GetByHash(hash [32]byte) (*ledger.Ledger, bool) {
 
 	ledgerValue, found := c.recentByHash.Get(hash)
 	if found {
-		c.hits++
+		atomic.AddUint64(&c.hits, 1)
 		return ledgerValue, true
 	}
 
-	c.misses++
+	atomic.AddUint64(&c.misses, 1)
 	return nil, false
 }
 
@@ -175,15 +176,17 @@ func (c *LedgerCache) Stats() CacheStats {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	total := c.hits + c.misses
+	hits := atomic.LoadUint64(&c.hits)
+	misses := atomic.LoadUint64(&c.misses)
+	total := hits + misses
 	hitRate := float64(0)
 	if total > 0 {
-		hitRate = float64(c.hits) / float64(total)
+		hitRate = float64(hits) / float64(total)
 	}
 
 	return CacheStats{
-		Hits:         c.hits,
-		Misses:       c.misses,
+		Hits:         hits,
+		Misses:       misses,
 		HitRate:      hitRate,
 		SeqCacheLen:  c.recentBySeq.Len(),
 		HashCacheLen: c.recentByHash.Len(),
@@ -7,6 +7,7 @@ import (
 	"math"
 	"sort"
 	"strconv"
+	"strings"
 
 	addresscodec "github.com/LeJamon/goXRPLd/codec/addresscodec"
 	"github.com/LeJamon/goXRPLd/internal/ledger"
@@ -39,39 +40,10 @@ type AccountInfoResult struct {
 
 // GetAccountInfo retrieves account information from the ledger
 func (s *Service) GetAccountInfo(account string, ledgerIndex string) (*AccountInfoResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	// Determine which ledger to use
-	var targetLedger *ledger.Ledger
-	var validated bool
-
-	switch ledgerIndex {
-	case "current", "":
-		targetLedger = s.openLedger
-		validated = false
-	case "closed":
-		targetLedger = s.closedLedger
-		validated = s.closedLedger == s.validatedLedger
-	case "validated":
-		targetLedger = s.validatedLedger
-		validated = true
-	default:
-		// Try to parse as a number
-		seq, err := strconv.ParseUint(ledgerIndex, 10, 32)
-		if err != nil {
-			return nil, errors.New("invalid ledger_index")
-		}
-		var ok bool
-		targetLedger, ok = s.ledgerHistory[uint32(seq)]
-		if !ok {
-			return nil, ErrLedgerNotFound
-		}
-		validated = targetLedger.IsValidated()
-	}
-
-	if targetLedger == nil {
-		return nil, ErrNoOpenLedger
+	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
+	if err != nil {
+		return nil, err
 	}
 
 	// Decode the account address to get the account ID
@@ -157,9 +129,6 @@ type AccountLinesResult struct {
 
 // GetAccountLines retrieves trust lines for an account
 func (s *Service) GetAccountLines(account string, ledgerIndex string, peer string, limit uint32) (*AccountLinesResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	// Determine which ledger to use
 	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
 	if err != nil {
@@ -317,9 +286,6 @@ type AccountOffersResult struct {
 
 // GetAccountOffers retrieves offers for an account
 func (s *Service) GetAccountOffers(account string, ledgerIndex string, limit uint32) (*AccountOffersResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	// Determine which ledger to use
 	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
 	if err != nil {
@@ -441,9 +407,6 @@ type AccountObjectItem struct {
 
 // GetAccountObjects retrieves all objects owned by an account
 func (s *Service) GetAccountObjects(account string, ledgerIndex string, objType string, limit uint32) (*AccountObjectsResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
 	if err != nil {
 		return nil, err
@@ -537,9 +500,6 @@ type AccountChannelsResult struct {
 
 // GetAccountChannels retrieves payment channels for an account
 func (s *Service) GetAccountChannels(account string, destinationAccount string, ledgerIndex string, limit uint32) (*AccountChannelsResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	// Determine which ledger to use
 	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
 	if err != nil {
@@ -685,9 +645,6 @@ type AccountCurrenciesResult struct {
 
 // GetAccountCurrencies retrieves currencies an account can send and receive
 func (s *Service) GetAccountCurrencies(account string, ledgerIndex string) (*AccountCurrenciesResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	// Determine which ledger to use
 	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
 	if err != nil {
@@ -868,9 +825,6 @@ type AccountNFTsResult struct {
 
 // GetAccountNFTs retrieves NFTs owned by an account
 func (s *Service) GetAccountNFTs(account string, ledgerIndex string, limit uint32) (*AccountNFTsResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	// Determine which ledger to use
 	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
 	if err != nil {
@@ -973,6 +927,8 @@ type GatewayBalancesResult struct {
 	FrozenBalances map[string][]CurrencyBalance // account -> []balance
 	Assets         map[string][]CurrencyBalance // account -> []balance
 	Locked         map[string]string            // currency -> value (escrows)
+	MPTObligations map[string]string            // mpt_issuance_id -> outstanding amount
+	MPTLocked      map[string]string            // mpt_issuance_id -> locked amount
 	LedgerIndex    uint32
 	LedgerHash     [32]byte
 	Validated      bool
@@ -980,9 +936,6 @@ type GatewayBalancesResult struct {
 
 // GetGatewayBalances retrieves obligations and balances for a gateway account
 func (s *Service) GetGatewayBalances(account string, hotWallets []string, ledgerIndex string) (*GatewayBalancesResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	// Determine which ledger to use
 	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
 	if err != nil {
@@ -1166,6 +1119,30 @@ func (s *Service) GetGatewayBalances(account string, hotWallets []string, ledger
 		result.Assets = assets
 	}
 
+	// MPTs have no trust lines: an issuer's obligations are the outstanding
+	// amounts of the issuances in its owner directory.
+	mptObligations := make(map[string]string)
+	mptLocked := make(map[string]string)
+	err = forEachMPTIssuance(targetLedger, accountID, func(info MPTIssuanceInfo) error {
+		id := strings.ToUpper(hex.EncodeToString(info.MPTIssuanceID[:]))
+		if info.OutstandingAmount > 0 {
+			mptObligations[id] = strconv.FormatUint(info.OutstandingAmount, 10)
+		}
+		if info.LockedAmount != nil && *info.LockedAmount > 0 {
+			mptLocked[id] = strconv.FormatUint(*info.LockedAmount, 10)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(mptObligations) > 0 {
+		result.MPTObligations = mptObligations
+	}
+	if len(mptLocked) > 0 {
+		result.MPTLocked = mptLocked
+	}
+
 	return result, nil
 }
 
@@ -1197,9 +1174,6 @@ const (
 
 // GetNoRippleCheck checks trust lines for proper NoRipple flag settings
 func (s *Service) GetNoRippleCheck(account string, role string, ledgerIndex string, limit uint32, transactions bool) (*NoRippleCheckResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	// Determine which ledger to use
 	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
 	if err != nil {
@@ -1437,9 +1411,6 @@ type DepositAuthorizedResult struct {
 // deposit preauthorization.
 // Reference: rippled DepositAuthorized.cpp doDepositAuthorized()
 func (s *Service) GetDepositAuthorized(sourceAccount string, destinationAccount string, ledgerIndex string, credentials []string) (*DepositAuthorizedResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	// Determine which ledger to use
 	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
 	if err != nil {
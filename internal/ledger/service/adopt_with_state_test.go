@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"testing"
 
+	"github.com/LeJamon/goXRPLd/codec/addresscodec"
 	"github.com/LeJamon/goXRPLd/codec/binarycodec"
 	"github.com/LeJamon/goXRPLd/crypto/common"
 	"github.com/LeJamon/goXRPLd/internal/ledger/header"
@@ -72,6 +73,7 @@ func TestAdoptLedgerWithState_PreservesTxMap(t *testing.T) {
 	svc, err := New(cfg)
 	require.NoError(t, err)
 	require.NoError(t, svc.Start())
+	t.Cleanup(svc.Stop)
 
 	// Build a non-empty tx map: 2 distinct tx leaves with proper
 	// VL(tx)+VL(meta) shape so persistLedger and collectTransactionResults
@@ -141,6 +143,7 @@ func TestAdoptLedgerWithState_NilTxMapFallsBackToEmpty(t *testing.T) {
 	svc, err := New(cfg)
 	require.NoError(t, err)
 	require.NoError(t, svc.Start())
+	t.Cleanup(svc.Stop)
 
 	stateMap, err := shamap.New(shamap.TypeState)
 	require.NoError(t, err)
@@ -199,6 +202,7 @@ func TestAdoptLedgerWithState_PersistsToRelationalDB(t *testing.T) {
 	svc, err := New(cfg)
 	require.NoError(t, err)
 	require.NoError(t, svc.Start())
+	t.Cleanup(svc.Stop)
 
 	// Two txs with canonical-hash keys so the DB row's trans_id column
 	// matches the hash we query for.
@@ -257,6 +261,7 @@ func TestAdoptLedgerWithState_PopulatesTxIndex(t *testing.T) {
 	svc, err := New(cfg)
 	require.NoError(t, err)
 	require.NoError(t, svc.Start())
+	t.Cleanup(svc.Stop)
 
 	txMap, err := shamap.New(shamap.TypeTransaction)
 	require.NoError(t, err)
@@ -296,3 +301,66 @@ func TestAdoptLedgerWithState_PopulatesTxIndex(t *testing.T) {
 	assert.Len(t, svc.txIndex, 3,
 		"txIndex must contain exactly the adopted txs, nothing more")
 }
+
+// TestBackfillAccountTxTypes covers account_tx rows indexed before types
+// and directions were recorded: the backfill decodes their raw
+// transactions so filtered pages return them.
+func TestBackfillAccountTxTypes(t *testing.T) {
+	ctx := context.Background()
+
+	rm, err := sqlitedb.NewRepositoryManager(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, rm.Open(ctx))
+	t.Cleanup(func() { _ = rm.Close(ctx) })
+
+	const sender, receiver = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe"
+	txHex, err := binarycodec.Encode(map[string]any{
+		"TransactionType": "Payment",
+		"Account":         sender,
+		"Destination":     receiver,
+		"Amount":          "1000000",
+		"Fee":             "10",
+		"Sequence":        uint32(1),
+	})
+	require.NoError(t, err)
+	txBytes, err := hex.DecodeString(txHex)
+	require.NoError(t, err)
+
+	var senderID, receiverID relationaldb.AccountID
+	_, senderBytes, err := addresscodec.DecodeClassicAddressToAccountID(sender)
+	require.NoError(t, err)
+	copy(senderID[:], senderBytes)
+	_, receiverBytes, err := addresscodec.DecodeClassicAddressToAccountID(receiver)
+	require.NoError(t, err)
+	copy(receiverID[:], receiverBytes)
+
+	// Index the payment for both parties without a type, as rows written
+	// before the columns existed are.
+	txInfo := &relationaldb.TransactionInfo{LedgerSeq: 2, Status: "validated", RawTxn: txBytes}
+	txInfo.Hash[0] = 0xB1
+	require.NoError(t, rm.Transaction().SaveTransaction(ctx, txInfo))
+	require.NoError(t, rm.AccountTransaction().SaveAccountTransaction(ctx, senderID, txInfo))
+	require.NoError(t, rm.AccountTransaction().SaveAccountTransaction(ctx, receiverID, txInfo))
+
+	cfg := DefaultConfig()
+	cfg.RelationalDB = rm
+	svc, err := New(cfg)
+	require.NoError(t, err)
+	require.NoError(t, svc.backfillAccountTxTypes(ctx))
+
+	for _, tt := range []struct {
+		account   relationaldb.AccountID
+		direction relationaldb.AccountTxDirection
+	}{
+		{senderID, relationaldb.AccountTxOutgoing},
+		{receiverID, relationaldb.AccountTxIncoming},
+	} {
+		page, err := rm.AccountTransaction().GetOldestAccountTxsPage(ctx, relationaldb.AccountTxPageOptions{
+			Account: tt.account,
+			Limit:   10,
+			Filter:  relationaldb.AccountTxFilter{TxType: "Payment", Direction: tt.direction},
+		})
+		require.NoError(t, err)
+		assert.Len(t, page.Transactions, 1)
+	}
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/LeJamon/goXRPLd/drops"
+	"github.com/LeJamon/goXRPLd/internal/ledger"
+	"github.com/LeJamon/goXRPLd/internal/ledger/header"
+	"github.com/LeJamon/goXRPLd/keylet"
+	"github.com/LeJamon/goXRPLd/ledger/entry"
+	"github.com/LeJamon/goXRPLd/shamap"
+	"github.com/LeJamon/goXRPLd/storage/nodestore"
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+// Ledger history
+//
+// The service keeps the most recent ledgers in ledgerHistory, bounded by
+// HistoryConfig. Older ledgers are evicted into a manager.LedgerCache and,
+// once dropped from it, are rebuilt on demand from the nodestore: the
+// header is stored under the ledger hash and the state and transaction
+// maps under their node hashes, so a reloaded ledger reads its state
+// lazily through a backed SHAMap. Lookups by sequence resolve the hash
+// through the relational DB first.
+// Reference: rippled LedgerHistory, LedgerMaster::getLedgerBySeq
+
+const (
+	// DefaultHistoryLedgers is the default number of ledgers kept in memory.
+	DefaultHistoryLedgers = 256
+
+	// DefaultHistoryCacheLedgers is the default number of evicted or
+	// reloaded ledgers kept in the ledger cache.
+	DefaultHistoryCacheLedgers = 64
+
+	// ledgerItemOverhead approximates the memory a SHAMap item costs on top
+	// of its data: the leaf node, the item key and a share of the inner
+	// nodes above it.
+	ledgerItemOverhead = 200
+)
+
+// HistoryConfig bounds the ledgers the service keeps in memory.
+type HistoryConfig struct {
+	// MaxLedgers is the number of ledgers kept in memory.
+	// Zero selects DefaultHistoryLedgers.
+	MaxLedgers int
+
+	// MaxBytes bounds the estimated memory of the ledgers kept in memory.
+	// Zero disables the bound. A ledger is charged for its transactions
+	// and for the state entries it changed from its parent, which is what
+	// evicting it frees; state shared with newer ledgers is not counted.
+	MaxBytes int64
+
+	// CacheLedgers is the number of evicted or reloaded ledgers kept in
+	// the ledger cache. Zero selects DefaultHistoryCacheLedgers.
+	CacheLedgers int
+}
+
+// withDefaults returns c with zero fields replaced by their defaults.
+func (c HistoryConfig) withDefaults() HistoryConfig {
+	if c.MaxLedgers <= 0 {
+		c.MaxLedgers = DefaultHistoryLedgers
+	}
+	if c.CacheLedgers <= 0 {
+		c.CacheLedgers = DefaultHistoryCacheLedgers
+	}
+	return c
+}
+
+// addToHistoryLocked stores l in the in-memory history under its sequence
+// and evicts the oldest ledgers if the history is over its bounds.
+// Caller must hold s.mu (write lock) and must have updated closedLedger
+// and validatedLedger first, since those are never evicted.
+func (s *Service) addToHistoryLocked(l *ledger.Ledger) {
+	s.ledgerHistory[l.Sequence()] = l
+	if s.config.History.MaxBytes > 0 {
+		s.historySizes[l] = s.ledgerSizeLocked(l)
+	}
+	s.trimHistoryLocked()
+}
+
+// trimHistoryLocked evicts ledgers from the in-memory history, lowest
+// sequence first, until it is within config.History. The closed and
+// validated ledgers are never evicted. Caller must hold s.mu (write lock).
+func (s *Service) trimHistoryLocked() {
+	limits := s.config.History
+	var total int64
+	if limits.MaxBytes > 0 {
+		total = s.historyBytesLocked()
+	}
+	over := func() bool {
+		return len(s.ledgerHistory) > limits.MaxLedgers ||
+			(limits.MaxBytes > 0 && total > limits.MaxBytes)
+	}
+	if !over() {
+		return
+	}
+
+	seqs := make([]uint32, 0, len(s.ledgerHistory))
+	for seq := range s.ledgerHistory {
+		seqs = append(seqs, seq)
+	}
+	slices.Sort(seqs)
+
+	for _, seq := range seqs {
+		if !over() {
+			break
+		}
+		l := s.ledgerHistory[seq]
+		if l == s.closedLedger || l == s.validatedLedger {
+			continue
+		}
+		delete(s.ledgerHistory, seq)
+		total -= s.historySizes[l]
+		delete(s.historySizes, l)
+		s.ledgerCache.Put(l)
+		if s.canReloadLedgers() && (s.historyFloor == 0 || seq < s.historyFloor) {
+			s.historyFloor = seq
+		}
+	}
+}
+
+// historyBytesLocked returns the estimated memory of the in-memory
+// history, forgetting the sizes of ledgers that left it. Caller must hold
+// s.mu (write lock).
+func (s *Service) historyBytesLocked() int64 {
+	var total int64
+	for l, size := range s.historySizes {
+		if s.ledgerHistory[l.Sequence()] != l {
+			delete(s.historySizes, l)
+			continue
+		}
+		total += size
+	}
+	return total
+}
+
+// ledgerSizeLocked estimates the memory held by l alone: its transactions
+// and the state entries that differ from its parent, when the parent is in
+// the history. The difference walk skips subtrees the two ledgers share,
+// so it costs the size of the change rather than the size of the state.
+// Caller must hold s.mu.
+func (s *Service) ledgerSizeLocked(l *ledger.Ledger) int64 {
+	var size int64
+	_ = l.ForEachTransaction(func(_ [32]byte, data []byte) bool {
+		size += int64(len(data)) + ledgerItemOverhead
+		return true
+	})
+
+	parent, ok := s.ledgerHistory[l.Sequence()-1]
+	if !ok || parent.Hash() != l.ParentHash() {
+		return size
+	}
+	_ = parent.ForEachStateDifference(l, nil, func(d shamap.DifferenceItem) bool {
+		if d.SecondItem != nil {
+			size += int64(len(d.SecondItem.Data())) + ledgerItemOverhead
+		}
+		return true
+	})
+	return size
+}
+
+// canReloadLedgers reports whether ledgers evicted from memory can be
+// reloaded by sequence, which needs both the nodestore and the relational
+// DB.
+func (s *Service) canReloadLedgers() bool {
+	return s.family != nil && s.relationalDB != nil
+}
+
+// historyRangeLocked returns the range of ledger sequences the service can
+// serve: the in-memory history, extended down to the lowest evicted ledger
+// that can be reloaded. Caller must hold s.mu.
+func (s *Service) historyRangeLocked() (minSeq, maxSeq uint32, ok bool) {
+	for seq := range s.ledgerHistory {
+		if !ok || seq < minSeq {
+			minSeq = seq
+		}
+		if !ok || seq > maxSeq {
+			maxSeq = seq
+		}
+		ok = true
+	}
+	if ok && s.historyFloor != 0 && s.historyFloor < minSeq {
+		minSeq = s.historyFloor
+	}
+	return minSeq, maxSeq, ok
+}
+
+// ledgerBySeq returns the ledger with sequence seq from the in-memory
+// history, the ledger cache or storage, in that order. Storage is read
+// without s.mu held, so the caller must not hold it.
+func (s *Service) ledgerBySeq(seq uint32) (*ledger.Ledger, bool) {
+	s.mu.RLock()
+	l, ok := s.cachedLedgerBySeqLocked(seq)
+	s.mu.RUnlock()
+	if ok {
+		return l, true
+	}
+	if !s.canReloadLedgers() {
+		return nil, false
+	}
+
+	hash, err := s.relationalDB.Ledger().GetHashByIndex(context.Background(), relationaldb.LedgerIndex(seq))
+	if err != nil {
+		if !errors.Is(err, relationaldb.ErrLedgerNotFound) {
+			s.logger.Warn("failed to look up ledger hash", "seq", seq, "error", err)
+		}
+		return nil, false
+	}
+	l, err = s.loadLedger([32]byte(*hash))
+	if err != nil {
+		s.logger.Warn("failed to reload ledger", "seq", seq, "error", err)
+		return nil, false
+	}
+	if l == nil || l.Sequence() != seq {
+		return nil, false
+	}
+	return l, true
+}
+
+// cachedLedgerBySeqLocked returns the ledger with sequence seq from the
+// in-memory history or the ledger cache. Caller must hold s.mu (read or
+// write lock).
+func (s *Service) cachedLedgerBySeqLocked(seq uint32) (*ledger.Ledger, bool) {
+	if l, ok := s.ledgerHistory[seq]; ok {
+		return l, true
+	}
+	if l, ok := s.ledgerCache.Get(seq); ok {
+		return l, true
+	}
+	if s.genesisLedger != nil && s.genesisLedger.Sequence() == seq {
+		return s.genesisLedger, true
+	}
+	return nil, false
+}
+
+// ledgerByHash returns the ledger with the given hash from the in-memory
+// history, the ledger cache or the nodestore, in that order. The
+// nodestore is read without s.mu held, so the caller must not hold it.
+func (s *Service) ledgerByHash(hash [32]byte) (*ledger.Ledger, bool) {
+	s.mu.RLock()
+	l, ok := s.cachedLedgerByHashLocked(hash)
+	s.mu.RUnlock()
+	if ok {
+		return l, true
+	}
+	if s.family == nil {
+		return nil, false
+	}
+
+	l, err := s.loadLedger(hash)
+	if err != nil {
+		s.logger.Warn("failed to reload ledger", "hash", fmt.Sprintf("%x", hash[:8]), "error", err)
+		return nil, false
+	}
+	return l, l != nil
+}
+
+// cachedLedgerByHashLocked returns the ledger with the given hash from the
+// in-memory history or the ledger cache. Caller must hold s.mu (read or
+// write lock).
+func (s *Service) cachedLedgerByHashLocked(hash [32]byte) (*ledger.Ledger, bool) {
+	for _, l := range s.ledgerHistory {
+		if l.Hash() == hash {
+			return l, true
+		}
+	}
+	if l, ok := s.ledgerCache.GetByHash(hash); ok {
+		return l, true
+	}
+	if s.genesisLedger != nil && s.genesisLedger.Hash() == hash {
+		return s.genesisLedger, true
+	}
+	return nil, false
+}
+
+// loadLedger rebuilds the validated ledger with the given hash from the
+// nodestore and adds it to the ledger cache. Its maps are backed by the
+// nodestore and load nodes as they are read. Returns nil, nil if the
+// nodestore does not hold the ledger's header. It does not use s.mu: the
+// nodestore and family are fixed at construction and the ledger cache
+// has its own lock.
+func (s *Service) loadLedger(hash [32]byte) (*ledger.Ledger, error) {
+	node, err := s.nodeStore.Fetch(context.Background(), nodestore.Hash256(hash))
+	if err != nil {
+		return nil, err
+	}
+	if node == nil || node.Type != nodestore.NodeLedger {
+		return nil, nil
+	}
+	hdr, err := header.DeserializeHeader(node.Data, true)
+	if err != nil {
+		return nil, fmt.Errorf("ledger header: %w", err)
+	}
+	if hdr.Hash != hash {
+		return nil, fmt.Errorf("ledger header hash is %x, want %x", hdr.Hash[:8], hash[:8])
+	}
+
+	stateMap, err := s.loadMap(shamap.TypeState, hdr.AccountHash)
+	if err != nil {
+		return nil, fmt.Errorf("state map: %w", err)
+	}
+	txMap, err := s.loadMap(shamap.TypeTransaction, hdr.TxHash)
+	if err != nil {
+		return nil, fmt.Errorf("tx map: %w", err)
+	}
+
+	fees, err := stateFees(stateMap)
+	if err != nil {
+		return nil, fmt.Errorf("fee settings: %w", err)
+	}
+
+	l := ledger.NewFromHeader(*hdr, stateMap, txMap, fees)
+	s.ledgerCache.Put(l)
+	return l, nil
+}
+
+// stateFees reads the fees from the FeeSettings entry of stateMap. A
+// missing entry yields the default fees, as in readFeesFromLedger.
+func stateFees(stateMap *shamap.SHAMap) (drops.Fees, error) {
+	var fs entry.FeeSettings
+	item, found, err := stateMap.Get(keylet.Fees().Key)
+	if err != nil {
+		return drops.Fees{}, err
+	}
+	if found {
+		if err := fs.UnmarshalBinary(item.Data()); err != nil {
+			return drops.Fees{}, err
+		}
+	}
+	return drops.Fees{
+		Base:      fs.GetBaseFee(),
+		Reserve:   fs.GetReserveBase(),
+		Increment: fs.GetReserveIncrement(),
+	}, nil
+}
+
+// loadMap returns the immutable map with the given root hash, backed by
+// the nodestore. An empty map has a zero root hash and no stored nodes.
+func (s *Service) loadMap(mapType shamap.Type, rootHash [32]byte) (*shamap.SHAMap, error) {
+	var m *shamap.SHAMap
+	var err error
+	if rootHash == ([32]byte{}) {
+		m, err = shamap.NewBacked(mapType, s.family)
+	} else {
+		m, err = shamap.NewFromRootHash(mapType, rootHash, s.family)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := m.SetImmutable(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/LeJamon/goXRPLd/internal/ledger"
+	"github.com/LeJamon/goXRPLd/internal/ledger/header"
+	"github.com/LeJamon/goXRPLd/shamap"
+	"github.com/LeJamon/goXRPLd/storage/kvstore/memorydb"
+	"github.com/LeJamon/goXRPLd/storage/nodestore"
+	sqlitedb "github.com/LeJamon/goXRPLd/storage/relationaldb/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHistoryTestService starts a standalone service with the given
+// history bounds and, if persist is set, a nodestore and relational DB.
+func newHistoryTestService(t *testing.T, history HistoryConfig, persist bool) *Service {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.History = history
+	if persist {
+		ctx := context.Background()
+		rm, err := sqlitedb.NewRepositoryManager(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, rm.Open(ctx))
+		t.Cleanup(func() { _ = rm.Close(ctx) })
+		cfg.RelationalDB = rm
+		cfg.NodeStore = nodestore.NewKVDatabase(memorydb.New(), "memory", 2000, time.Hour)
+	}
+	svc, err := New(cfg)
+	require.NoError(t, err)
+	require.NoError(t, svc.Start())
+	t.Cleanup(svc.Stop)
+	return svc
+}
+
+// acceptLedgers closes n ledgers and returns them in order.
+func acceptLedgers(t *testing.T, svc *Service, n int) []*ledger.Ledger {
+	t.Helper()
+	closed := make([]*ledger.Ledger, 0, n)
+	for i := 0; i < n; i++ {
+		_, err := svc.AcceptLedger()
+		require.NoError(t, err)
+		closed = append(closed, svc.GetClosedLedger())
+	}
+	return closed
+}
+
+// assertSameHeader checks that got has the serialized header of want.
+func assertSameHeader(t *testing.T, want, got *ledger.Ledger) {
+	t.Helper()
+	assert.Equal(t, want.SerializeHeader(), got.SerializeHeader())
+	assert.Equal(t, want.Hash(), got.Hash())
+}
+
+func TestHistory_BoundedByCount(t *testing.T) {
+	svc := newHistoryTestService(t, HistoryConfig{MaxLedgers: 4, CacheLedgers: 2}, false)
+	closed := acceptLedgers(t, svc, 10)
+
+	assert.Len(t, svc.ledgerHistory, 4)
+	last := closed[len(closed)-1]
+	assert.Same(t, last, svc.ledgerHistory[last.Sequence()])
+
+	// The most recently evicted ledgers are served from the cache.
+	evicted := closed[len(closed)-5]
+	got, err := svc.GetLedgerBySequence(evicted.Sequence())
+	require.NoError(t, err)
+	assert.Same(t, evicted, got)
+
+	// Without storage, ledgers dropped from the cache are gone, except
+	// for genesis which the service always holds.
+	_, err = svc.GetLedgerBySequence(closed[0].Sequence())
+	assert.ErrorIs(t, err, ErrLedgerNotFound)
+	_, err = svc.GetLedgerByHash(closed[0].Hash())
+	assert.ErrorIs(t, err, ErrLedgerNotFound)
+	genesis, err := svc.GetLedgerBySequence(1)
+	require.NoError(t, err)
+	assert.Same(t, svc.genesisLedger, genesis)
+
+	assert.Equal(t, formatRange(closed[6].Sequence(), last.Sequence()), svc.GetServerInfo().CompleteLedgers)
+}
+
+func TestHistory_BoundedByMemory(t *testing.T) {
+	svc := newHistoryTestService(t, HistoryConfig{MaxBytes: 1}, false)
+	closed := acceptLedgers(t, svc, 3)
+
+	// Every ledger is over the bound, but the closed and validated ledger
+	// is never evicted.
+	require.Len(t, svc.ledgerHistory, 1)
+	last := closed[len(closed)-1]
+	assert.Same(t, last, svc.ledgerHistory[last.Sequence()])
+	assert.Len(t, svc.historySizes, 1)
+	assert.Positive(t, svc.historySizes[last])
+
+	// Only the state changed from the parent is charged to the ledger.
+	var stateSize int64
+	require.NoError(t, last.ForEach(func(_ [32]byte, data []byte) bool {
+		stateSize += int64(len(data)) + ledgerItemOverhead
+		return true
+	}))
+	assert.Less(t, svc.historySizes[last], stateSize)
+}
+
+func TestHistory_ReloadFromStorage(t *testing.T) {
+	svc := newHistoryTestService(t, HistoryConfig{MaxLedgers: 2, CacheLedgers: 1}, true)
+	closed := acceptLedgers(t, svc, 6)
+
+	want := closed[0]
+	seq := want.Sequence()
+	_, inHistory := svc.ledgerHistory[seq]
+	require.False(t, inHistory)
+	_, cached := svc.ledgerCache.Get(seq)
+	require.False(t, cached)
+
+	got, err := svc.GetLedgerBySequence(seq)
+	require.NoError(t, err)
+	assert.NotSame(t, want, got)
+	assertSameHeader(t, want, got)
+	assert.True(t, got.IsValidated())
+
+	// Fees come from the reloaded ledger's FeeSettings entry.
+	base, reserve, increment := readFeesFromLedger(want)
+	fees := got.GetFees()
+	assert.Equal(t, base, uint64(fees.Base))
+	assert.Equal(t, reserve, uint64(fees.Reserve))
+	assert.Equal(t, increment, uint64(fees.Increment))
+
+	wantState, err := want.StateMapHash()
+	require.NoError(t, err)
+	gotState, err := got.StateMapHash()
+	require.NoError(t, err)
+	assert.Equal(t, wantState, gotState)
+
+	wantEntries := map[[32]byte][]byte{}
+	require.NoError(t, want.ForEach(func(key [32]byte, data []byte) bool {
+		wantEntries[key] = data
+		return true
+	}))
+	gotEntries := map[[32]byte][]byte{}
+	require.NoError(t, got.ForEach(func(key [32]byte, data []byte) bool {
+		gotEntries[key] = data
+		return true
+	}))
+	assert.Equal(t, wantEntries, gotEntries)
+
+	// The reloaded ledger is cached; hash lookups reach the nodestore
+	// directly.
+	again, err := svc.GetLedgerBySequence(seq)
+	require.NoError(t, err)
+	assert.Same(t, got, again)
+	byHash, err := svc.GetLedgerByHash(closed[1].Hash())
+	require.NoError(t, err)
+	assertSameHeader(t, closed[1], byHash)
+
+	// Ledger 2, closed at startup, was persisted too.
+	initial, err := svc.GetLedgerBySequence(2)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), initial.Sequence())
+
+	last := closed[len(closed)-1]
+	assert.Equal(t, formatRange(1, last.Sequence()), svc.GetServerInfo().CompleteLedgers)
+
+	_, err = svc.GetLedgerBySequence(last.Sequence() + 10)
+	assert.ErrorIs(t, err, ErrLedgerNotFound)
+}
+
+func TestHistory_ReloadWithoutServiceLock(t *testing.T) {
+	svc := newHistoryTestService(t, HistoryConfig{MaxLedgers: 2, CacheLedgers: 1}, true)
+	closed := acceptLedgers(t, svc, 6)
+	want := closed[0]
+
+	// Reading the nodestore must not wait on s.mu: a writer holding it
+	// does not block a reload.
+	svc.mu.Lock()
+	got, err := svc.loadLedger(want.Hash())
+	svc.mu.Unlock()
+	require.NoError(t, err)
+	assertSameHeader(t, want, got)
+
+	// Ledger queries by sequence reload evicted ledgers too.
+	svc.ledgerCache.Clear()
+	byIndex, validated, err := svc.getLedgerForQuery(strconv.FormatUint(uint64(want.Sequence()), 10))
+	require.NoError(t, err)
+	assert.True(t, validated)
+	assertSameHeader(t, want, byIndex)
+}
+
+func TestHistory_ReloadTransactions(t *testing.T) {
+	svc := newHistoryTestService(t, HistoryConfig{MaxLedgers: 2, CacheLedgers: 1}, true)
+
+	stateMap, err := svc.GetClosedLedger().StateMapSnapshot()
+	require.NoError(t, err)
+	stateRoot, err := stateMap.Hash()
+	require.NoError(t, err)
+	txMap, err := shamap.New(shamap.TypeTransaction)
+	require.NoError(t, err)
+	blob, txID := makeTxMetaBlobForTest(t, []byte("history-reload-tx-blob-padding"), 0)
+	require.NoError(t, txMap.PutWithNodeType(txID, blob, shamap.NodeTypeTransactionWithMeta))
+	txRoot, err := txMap.Hash()
+	require.NoError(t, err)
+
+	hdr := &header.LedgerHeader{
+		LedgerIndex: svc.GetClosedLedgerIndex() + 1,
+		Hash:        [32]byte{0xC2},
+		ParentHash:  svc.GetClosedLedger().Hash(),
+		TxHash:      txRoot,
+		AccountHash: stateRoot,
+	}
+	require.NoError(t, svc.AdoptLedgerWithState(hdr, stateMap, txMap))
+	acceptLedgers(t, svc, 3)
+
+	_, inHistory := svc.ledgerHistory[hdr.LedgerIndex]
+	require.False(t, inHistory)
+
+	result, err := svc.GetTransaction(txID)
+	require.NoError(t, err)
+	assert.Equal(t, hdr.LedgerIndex, result.LedgerIndex)
+	assert.Equal(t, hdr.Hash, result.LedgerHash)
+}
+
+// An import of the nodestore the service wrote verifies every object,
+// headers included, and the copy rebuilds the same ledgers.
+func TestHistory_ImportPersistedNodeStore(t *testing.T) {
+	ctx := context.Background()
+	rm, err := sqlitedb.NewRepositoryManager(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, rm.Open(ctx))
+	t.Cleanup(func() { _ = rm.Close(ctx) })
+
+	srcStore := memorydb.New()
+	cfg := DefaultConfig()
+	cfg.RelationalDB = rm
+	cfg.NodeStore = nodestore.NewKVDatabase(srcStore, "memory", 2000, time.Hour)
+	svc, err := New(cfg)
+	require.NoError(t, err)
+	require.NoError(t, svc.Start())
+	t.Cleanup(svc.Stop)
+	closed := acceptLedgers(t, svc, 3)
+
+	dstStore := memorydb.New()
+	progress, err := nodestore.Import(ctx, nodestore.NewKVBackend(srcStore, "src"),
+		nodestore.NewKVBackend(dstStore, "dst"), &nodestore.ImportOptions{
+			Verify:   true,
+			HashFunc: func(data nodestore.Blob) nodestore.Hash256 { return header.NodeObjectHash(data) },
+		})
+	require.NoError(t, err)
+	assert.Positive(t, progress.Imported)
+	assert.Zero(t, progress.Skipped)
+
+	importedCfg := DefaultConfig()
+	importedCfg.NodeStore = nodestore.NewKVDatabase(dstStore, "memory", 2000, time.Hour)
+	imported, err := New(importedCfg)
+	require.NoError(t, err)
+	for _, want := range closed {
+		got, err := imported.loadLedger(want.Hash())
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assertSameHeader(t, want, got)
+
+		wantState, err := want.StateMapHash()
+		require.NoError(t, err)
+		gotState, err := got.StateMapHash()
+		require.NoError(t, err)
+		assert.Equal(t, wantState, gotState)
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLedgerDiff(t *testing.T) {
+	svc := newHistoryTestService(t, HistoryConfig{}, false)
+	closed := acceptLedgers(t, svc, 5)
+	base := strconv.Itoa(int(closed[0].Sequence()))
+	desired := strconv.Itoa(int(closed[4].Sequence()))
+
+	same, err := svc.GetLedgerDiff(base, base, "", nil, 10)
+	require.NoError(t, err)
+	assert.Empty(t, same.Entries)
+	assert.Nil(t, same.Marker)
+
+	// Closing ledgers updates the skip list of recent ledger hashes.
+	diff, err := svc.GetLedgerDiff(base, desired, "hashes", nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, closed[0].Sequence(), diff.BaseLedgerIndex)
+	assert.Equal(t, closed[4].Hash(), diff.DesiredLedgerHash)
+	require.NotEmpty(t, diff.Entries)
+	for _, entry := range diff.Entries {
+		assert.Equal(t, LedgerDiffModified, entry.Status)
+		assert.Equal(t, "LedgerHashes", getLedgerEntryType(entry.After))
+		assert.NotNil(t, entry.Before)
+	}
+
+	none, err := svc.GetLedgerDiff(base, desired, "offer", nil, 10)
+	require.NoError(t, err)
+	assert.Empty(t, none.Entries)
+
+	// Reversing the ledgers swaps before and after.
+	reversed, err := svc.GetLedgerDiff(desired, base, "hashes", nil, 10)
+	require.NoError(t, err)
+	require.Len(t, reversed.Entries, len(diff.Entries))
+	assert.Equal(t, diff.Entries[0].Before, reversed.Entries[0].After)
+
+	// Paging one entry at a time returns every difference once.
+	all, err := svc.GetLedgerDiff(base, desired, "", nil, 1000)
+	require.NoError(t, err)
+	var paged []LedgerDiffEntry
+	var marker *[32]byte
+	for {
+		page, err := svc.GetLedgerDiff(base, desired, "", marker, 1)
+		require.NoError(t, err)
+		paged = append(paged, page.Entries...)
+		if page.Marker == nil {
+			break
+		}
+		marker = page.Marker
+	}
+	assert.Equal(t, all.Entries, paged)
+
+	_, err = svc.GetLedgerDiff(base, "99999", "", nil, 10)
+	assert.ErrorIs(t, err, ErrLedgerNotFound)
+}
@@ -66,9 +66,6 @@ type LedgerEntryResult struct {
 
 // GetLedgerEntry retrieves a specific ledger entry by its index/key
 func (s *Service) GetLedgerEntry(entryKey [32]byte, ledgerIndex string) (*LedgerEntryResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
 	if err != nil {
 		return nil, err
@@ -151,9 +148,6 @@ func formatCloseTimeISO(t time.Time) string {
 
 // GetLedgerData retrieves all ledger state entries with optional pagination
 func (s *Service) GetLedgerData(ledgerIndex string, limit uint32, marker string) (*LedgerDataResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
 	if err != nil {
 		return nil, err
@@ -237,28 +231,21 @@ func (s *Service) GetLedgerData(ledgerIndex string, limit uint32, marker string)
 	return result, nil
 }
 
-// getLedgerForQuery is a helper function to get ledger for query
+// getLedgerForQuery is a helper function to get ledger for query. A
+// ledger that has left memory is reloaded from storage without s.mu
+// held, so the caller must not hold it.
 func (s *Service) getLedgerForQuery(ledgerIndex string) (*ledger.Ledger, bool, error) {
-	var targetLedger *ledger.Ledger
-	var validated bool
+	s.mu.RLock()
+	targetLedger, validated, named := s.namedLedgerLocked(ledgerIndex)
+	s.mu.RUnlock()
 
-	switch ledgerIndex {
-	case "current", "":
-		targetLedger = s.openLedger
-		validated = false
-	case "closed":
-		targetLedger = s.closedLedger
-		validated = s.closedLedger == s.validatedLedger
-	case "validated":
-		targetLedger = s.validatedLedger
-		validated = true
-	default:
+	if !named {
 		seq, err := strconv.ParseUint(ledgerIndex, 10, 32)
 		if err != nil {
 			return nil, false, errors.New("invalid ledger_index")
 		}
 		var ok bool
-		targetLedger, ok = s.ledgerHistory[uint32(seq)]
+		targetLedger, ok = s.ledgerBySeq(uint32(seq))
 		if !ok {
 			return nil, false, ErrLedgerNotFound
 		}
@@ -271,3 +258,18 @@ func (s *Service) getLedgerForQuery(ledgerIndex string) (*ledger.Ledger, bool, e
 
 	return targetLedger, validated, nil
 }
+
+// namedLedgerLocked resolves the "current", "closed" and "validated"
+// ledger names; named is false for any other ledgerIndex.
+// Caller must hold s.mu (read or write lock).
+func (s *Service) namedLedgerLocked(ledgerIndex string) (l *ledger.Ledger, validated, named bool) {
+	switch ledgerIndex {
+	case "current", "":
+		return s.openLedger, false, true
+	case "closed":
+		return s.closedLedger, s.closedLedger == s.validatedLedger, true
+	case "validated":
+		return s.validatedLedger, true, true
+	}
+	return nil, false, false
+}
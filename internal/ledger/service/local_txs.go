@@ -0,0 +1,424 @@
+package service
+
+import (
+	"encoding/hex"
+
+	addresscodec "github.com/LeJamon/goXRPLd/codec/addresscodec"
+	"github.com/LeJamon/goXRPLd/codec/binarycodec"
+	"github.com/LeJamon/goXRPLd/internal/ledger"
+	"github.com/LeJamon/goXRPLd/internal/ledger/state"
+	"github.com/LeJamon/goXRPLd/internal/tx"
+	"github.com/LeJamon/goXRPLd/keylet"
+)
+
+// Tracking of the transactions submitted to this server. Like rippled's
+// LocalTxs, a submitted transaction that is not yet in a closed ledger is
+// re-applied to each new open ledger for a few ledgers, and tracked until
+// it is included, can no longer be included, or is rejected. Each
+// submission's state is kept for the tx_status method and reported to the
+// submission callback as it changes.
+
+// SubmissionState is the state of a locally submitted transaction
+type SubmissionState string
+
+const (
+	// SubmissionQueued is not in the open ledger: it is held to retry in a
+	// later one or, once past the retry window, waits for a ledger built
+	// elsewhere to include it
+	SubmissionQueued SubmissionState = "queued"
+	// SubmissionOpenLedger is applied to the open ledger
+	SubmissionOpenLedger SubmissionState = "open_ledger"
+	// SubmissionIncluded is in a closed ledger that is not yet validated
+	SubmissionIncluded SubmissionState = "included"
+	// SubmissionValidated is in a validated ledger
+	SubmissionValidated SubmissionState = "validated"
+	// SubmissionExpired was not included by the last ledger it could be
+	SubmissionExpired SubmissionState = "expired"
+	// SubmissionDropped was rejected when re-applied, or another
+	// transaction used its sequence or ticket. Evictions from the
+	// transaction queue are not tracked, so they do not drop a submission.
+	SubmissionDropped SubmissionState = "dropped"
+)
+
+// Final reports whether the state can no longer change
+func (st SubmissionState) Final() bool {
+	return st == SubmissionValidated || st == SubmissionExpired || st == SubmissionDropped
+}
+
+// Submission is the state of a transaction submitted to this server
+type Submission struct {
+	Hash               [32]byte
+	Account            string
+	Sequence           uint32 // Sequence, or TicketSequence
+	LastLedgerSequence uint32 // 0 if the transaction has none
+	State              SubmissionState
+	EngineResult       string // latest result, or the result in LedgerIndex
+	SubmittedLedger    uint32 // open ledger at the first submission
+	LedgerIndex        uint32 // ledger that includes the transaction
+	Relayed            int    // peers the transaction was relayed to
+	Attempts           int    // times applied to an open ledger
+}
+
+const (
+	// localTxHoldLedgers is how many ledgers after its submission a
+	// transaction is re-applied to the open ledger for, as rippled's
+	// LocalTxs holdLedgers. It remains tracked after that.
+	localTxHoldLedgers = 5
+
+	// localTxRetainLedgers is how many ledgers a submission remains
+	// queryable for once its state is final.
+	localTxRetainLedgers = 256
+
+	// submissionQueueSize is how many changes the submission callback
+	// may fall behind by before further changes are dropped.
+	submissionQueueSize = 4096
+)
+
+// localTx is a tracked submission with what re-applying it needs
+type localTx struct {
+	Submission
+	ptx pendingTx
+
+	// ticket is set if Sequence is a TicketSequence
+	ticket bool
+
+	// retryUntil is the last open ledger the transaction is re-applied to
+	retryUntil uint32
+
+	// finalAt is the ledger in which the state became final
+	finalAt uint32
+}
+
+// SetSubmissionCallback sets the function called with a submission each
+// time its state changes. Calls are made one at a time, in the order of
+// the changes, from a goroutine of the service.
+func (s *Service) SetSubmissionCallback(callback func(Submission)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.submissionQueue != nil {
+		close(s.submissionQueue)
+		s.submissionQueue = nil
+	}
+	if callback == nil {
+		return
+	}
+	s.submissionQueue = make(chan Submission, submissionQueueSize)
+	go dispatchSubmissions(s.submissionQueue, callback)
+}
+
+// dispatchSubmissions passes the changes sent on queue to callback until
+// queue is closed.
+func dispatchSubmissions(queue <-chan Submission, callback func(Submission)) {
+	for sub := range queue {
+		callback(sub)
+	}
+}
+
+// GetSubmission returns the state of a transaction submitted to this server
+func (s *Service) GetSubmission(hash [32]byte) (Submission, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.localTxs[hash]
+	if !ok {
+		return Submission{}, false
+	}
+	return t.Submission, true
+}
+
+// RecordRelay records the number of peers a submitted transaction was
+// relayed to
+func (s *Service) RecordRelay(hash [32]byte, peers int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.localTxs[hash]
+	if !ok || t.State.Final() {
+		return
+	}
+	t.Relayed = peers
+	s.notifySubmissionsLocked([]Submission{t.Submission})
+}
+
+// trackSubmissionLocked records the result of submitting a transaction
+// and returns its state. Transactions that were neither applied nor held
+// for retry are tracked only if they were submitted before, in which case
+// they keep their state. Caller must hold s.mu.
+func (s *Service) trackSubmissionLocked(common *tx.Common, ptx pendingTx, result tx.Result, applied bool) SubmissionState {
+	var state SubmissionState
+	switch {
+	case applied:
+		state = SubmissionOpenLedger
+	case result.ShouldRetry():
+		state = SubmissionQueued
+	}
+
+	t, ok := s.localTxs[ptx.hash]
+	if ok && (t.State.Final() || t.State == SubmissionIncluded) {
+		return t.State
+	}
+	if !ok {
+		if state == "" {
+			return ""
+		}
+		openSeq := s.openLedger.Sequence()
+		t = &localTx{
+			Submission: Submission{
+				Hash:            ptx.hash,
+				Account:         common.Account,
+				Sequence:        ptx.sequence,
+				SubmittedLedger: openSeq,
+			},
+			ptx:        ptx,
+			ticket:     common.TicketSequence != nil,
+			retryUntil: openSeq + localTxHoldLedgers,
+		}
+		if common.LastLedgerSequence != nil {
+			t.LastLedgerSequence = *common.LastLedgerSequence
+		}
+		s.localTxs[ptx.hash] = t
+	}
+	if state == "" {
+		return t.State
+	}
+
+	t.State = state
+	t.EngineResult = result.String()
+	t.Attempts++
+	s.notifySubmissionsLocked([]Submission{t.Submission})
+	return state
+}
+
+// sweepLocalTxsLocked updates the submissions after closed was closed and
+// a new open ledger created on it: those in closed become included, those
+// whose LastLedgerSequence closed was expire, those whose sequence or
+// ticket closed used are dropped, and the rest still in their retry window
+// are re-applied to the open ledger in canonical order. Caller must hold s.mu.
+//
+// Reference: rippled LocalTxs::sweep and LocalTxs::apply
+func (s *Service) sweepLocalTxsLocked(closed *ledger.Ledger) {
+	if len(s.localTxs) == 0 {
+		return
+	}
+	closedSeq := closed.Sequence()
+
+	var changed []Submission
+	var retry []pendingTx
+	for hash, t := range s.localTxs {
+		if t.State.Final() {
+			if closedSeq > t.finalAt+localTxRetainLedgers {
+				delete(s.localTxs, hash)
+			}
+			continue
+		}
+		if t.State == SubmissionIncluded {
+			continue
+		}
+
+		if seq, l := s.includingLedgerLocked(closed, hash); l != nil {
+			t.State = SubmissionIncluded
+			t.LedgerIndex = seq
+			t.EngineResult = transactionResult(l, hash)
+			changed = append(changed, t.Submission)
+			continue
+		}
+		if t.LastLedgerSequence != 0 && closedSeq >= t.LastLedgerSequence {
+			t.State = SubmissionExpired
+			t.finalAt = closedSeq
+			changed = append(changed, t.Submission)
+			continue
+		}
+		if sequenceUsed(closed, t) {
+			t.State = SubmissionDropped
+			t.finalAt = closedSeq
+			changed = append(changed, t.Submission)
+			continue
+		}
+		if closedSeq < t.retryUntil {
+			retry = append(retry, t.ptx)
+			continue
+		}
+		if t.State != SubmissionQueued {
+			t.State = SubmissionQueued
+			changed = append(changed, t.Submission)
+		}
+	}
+
+	if len(retry) > 0 && s.openLedger != nil {
+		canonicalSort(retry)
+		for _, ptx := range retry {
+			t := s.localTxs[ptx.hash]
+			if s.reapplyLocalTxLocked(t, closedSeq) {
+				changed = append(changed, t.Submission)
+			}
+		}
+	}
+
+	changed = append(changed, s.validateLocalTxsLocked()...)
+	s.notifySubmissionsLocked(changed)
+}
+
+// includingLedgerLocked returns the ledger, closed or one before it, that
+// includes the transaction, or nil. Caller must hold s.mu.
+func (s *Service) includingLedgerLocked(closed *ledger.Ledger, hash [32]byte) (uint32, *ledger.Ledger) {
+	if closed.TxExists(hash) {
+		return closed.Sequence(), closed
+	}
+	if seq, ok := s.txIndex[hash]; ok && seq <= closed.Sequence() {
+		if l, ok := s.ledgerHistory[seq]; ok && l.TxExists(hash) {
+			return seq, l
+		}
+	}
+	return 0, nil
+}
+
+// sequenceUsed reports whether l shows the sequence or ticket of a
+// submission as used, so that it can no longer be included. A ticket is
+// used once the account's sequence has passed it and it no longer exists.
+//
+// Reference: rippled LocalTxs::sweep
+func sequenceUsed(l *ledger.Ledger, t *localTx) bool {
+	_, accountID, err := addresscodec.DecodeClassicAddressToAccountID(t.Account)
+	if err != nil || len(accountID) != 20 {
+		return false
+	}
+	var id [20]byte
+	copy(id[:], accountID)
+
+	data, err := l.Read(keylet.Account(id))
+	if err != nil || data == nil {
+		return false
+	}
+	accountRoot, err := state.ParseAccountRootFromBytes(data)
+	if err != nil || accountRoot.Sequence <= t.Sequence {
+		return false
+	}
+	if !t.ticket {
+		return true
+	}
+	exists, err := l.Exists(keylet.Ticket(id, t.Sequence))
+	return err == nil && !exists
+}
+
+// reapplyLocalTxLocked applies a submission to the open ledger built on
+// ledger closedSeq and reports whether its state changed. Caller must hold s.mu.
+func (s *Service) reapplyLocalTxLocked(t *localTx, closedSeq uint32) bool {
+	transaction, err := tx.ParseFromBinary(t.ptx.txBlob)
+	if err != nil {
+		t.State = SubmissionDropped
+		t.finalAt = closedSeq
+		return true
+	}
+	transaction.SetRawBytes(t.ptx.txBlob)
+
+	engine := tx.NewEngine(s.openLedger, s.openEngineConfigLocked())
+	applyResult := engine.Apply(transaction)
+	t.Attempts++
+	t.EngineResult = applyResult.Result.String()
+
+	previous := t.State
+	switch {
+	case applyResult.Applied:
+		t.State = SubmissionOpenLedger
+		if !s.isPendingLocked(t.Hash) {
+			s.pendingTxs = append(s.pendingTxs, t.ptx)
+		}
+	case applyResult.Result.ShouldRetry():
+		t.State = SubmissionQueued
+	default:
+		t.State = SubmissionDropped
+		t.finalAt = closedSeq
+	}
+	return t.State != previous
+}
+
+// isPendingLocked reports whether a transaction is among the pending
+// transactions of the open ledger. Caller must hold s.mu.
+func (s *Service) isPendingLocked(hash [32]byte) bool {
+	for _, ptx := range s.pendingTxs {
+		if ptx.hash == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// validateLocalTxsLocked marks the submissions included in ledgers up to
+// the validated ledger as validated and returns them. A submission whose
+// ledger was replaced by one without it goes back to queued, to be
+// re-applied at the next close. Caller must hold s.mu.
+func (s *Service) validateLocalTxsLocked() []Submission {
+	if s.validatedLedger == nil {
+		return nil
+	}
+	validatedSeq := s.validatedLedger.Sequence()
+
+	var changed []Submission
+	for hash, t := range s.localTxs {
+		if t.State != SubmissionIncluded {
+			continue
+		}
+		if l, ok := s.ledgerHistory[t.LedgerIndex]; ok && !l.TxExists(hash) {
+			t.State = SubmissionQueued
+			t.LedgerIndex = 0
+			changed = append(changed, t.Submission)
+			continue
+		}
+		if t.LedgerIndex <= validatedSeq {
+			t.State = SubmissionValidated
+			t.finalAt = validatedSeq
+			changed = append(changed, t.Submission)
+		}
+	}
+	return changed
+}
+
+// notifySubmissionsLocked queues the changed submissions, in order, for
+// the submission callback, which runs outside s.mu so it cannot deadlock
+// against the service. Changes that find the queue full are dropped and
+// counted rather than stall the service; tx_status still reports them.
+// Caller must hold s.mu.
+func (s *Service) notifySubmissionsLocked(changed []Submission) {
+	if s.submissionQueue == nil {
+		return
+	}
+	for _, sub := range changed {
+		select {
+		case s.submissionQueue <- sub:
+		default:
+			if s.droppedSubmissions == 0 {
+				s.logger.Warn("submission callback is behind, dropping submission changes")
+			}
+			s.droppedSubmissions++
+		}
+	}
+}
+
+// DroppedSubmissionChanges returns how many submission changes were not
+// passed to the submission callback because it had fallen
+// submissionQueueSize changes behind.
+func (s *Service) DroppedSubmissionChanges() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.droppedSubmissions
+}
+
+// transactionResult returns the TransactionResult in the metadata of a
+// transaction of l, or "" if it cannot be read.
+func transactionResult(l *ledger.Ledger, hash [32]byte) string {
+	data, found, err := l.GetTransaction(hash)
+	if err != nil || !found {
+		return ""
+	}
+	_, metaBlob, err := tx.SplitTxWithMetaBlob(data)
+	if err != nil || len(metaBlob) == 0 {
+		return ""
+	}
+	meta, err := binarycodec.Decode(hex.EncodeToString(metaBlob))
+	if err != nil {
+		return ""
+	}
+	result, _ := meta["TransactionResult"].(string)
+	return result
+}
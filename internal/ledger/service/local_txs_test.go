@@ -0,0 +1,196 @@
+package service
+
+import (
+	"encoding/hex"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec"
+	"github.com/LeJamon/goXRPLd/internal/tx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// submitPayment submits a payment of 1000 XRP from the genesis account
+func submitPayment(t *testing.T, svc *Service, fields map[string]interface{}) *SubmitResult {
+	t.Helper()
+	genesisAccount, err := svc.GetGenesisAccount()
+	require.NoError(t, err)
+
+	txMap := map[string]interface{}{
+		"TransactionType": "Payment",
+		"Account":         genesisAccount,
+		"Destination":     "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe",
+		"Amount":          "1000000000",
+		"Fee":             "10",
+		"SigningPubKey":   "",
+	}
+	for k, v := range fields {
+		txMap[k] = v
+	}
+	encoded, err := binarycodec.Encode(txMap)
+	require.NoError(t, err)
+	blob, err := hex.DecodeString(encoded)
+	require.NoError(t, err)
+	transaction, err := tx.ParseFromBinary(blob)
+	require.NoError(t, err)
+
+	result, err := svc.SubmitTransaction(transaction, blob)
+	require.NoError(t, err)
+	return result
+}
+
+func TestLocalTxsLifecycle(t *testing.T) {
+	svc := newHistoryTestService(t, HistoryConfig{}, false)
+
+	var mu sync.Mutex
+	var notified []Submission
+	svc.SetSubmissionCallback(func(sub Submission) {
+		mu.Lock()
+		defer mu.Unlock()
+		notified = append(notified, sub)
+	})
+
+	// Sequence 2 cannot apply before sequence 1: it is held.
+	second := submitPayment(t, svc, map[string]interface{}{"Sequence": uint32(2)})
+	assert.False(t, second.Applied)
+	assert.True(t, second.Queued)
+	sub, ok := svc.GetSubmission(second.Hash)
+	require.True(t, ok)
+	assert.Equal(t, SubmissionQueued, sub.State)
+	assert.Equal(t, "terPRE_SEQ", sub.EngineResult)
+
+	first := submitPayment(t, svc, map[string]interface{}{"Sequence": uint32(1)})
+	require.True(t, first.Applied)
+	svc.RecordRelay(first.Hash, 3)
+	sub, _ = svc.GetSubmission(first.Hash)
+	assert.Equal(t, SubmissionOpenLedger, sub.State)
+	assert.Equal(t, 3, sub.Relayed)
+
+	// The close includes the first, and the second is re-applied to the
+	// new open ledger.
+	closed, err := svc.AcceptLedger()
+	require.NoError(t, err)
+	sub, _ = svc.GetSubmission(first.Hash)
+	assert.Equal(t, SubmissionValidated, sub.State)
+	assert.Equal(t, closed, sub.LedgerIndex)
+	assert.Equal(t, "tesSUCCESS", sub.EngineResult)
+	sub, _ = svc.GetSubmission(second.Hash)
+	assert.Equal(t, SubmissionOpenLedger, sub.State)
+	assert.Equal(t, 2, sub.Attempts)
+
+	closed, err = svc.AcceptLedger()
+	require.NoError(t, err)
+	sub, _ = svc.GetSubmission(second.Hash)
+	assert.Equal(t, SubmissionValidated, sub.State)
+	assert.Equal(t, closed, sub.LedgerIndex)
+
+	// A held transaction whose LastLedgerSequence passes expires.
+	current := svc.GetCurrentLedgerIndex()
+	late := submitPayment(t, svc, map[string]interface{}{"Sequence": uint32(5), "LastLedgerSequence": current})
+	require.True(t, late.Queued)
+	_, err = svc.AcceptLedger()
+	require.NoError(t, err)
+	sub, _ = svc.GetSubmission(late.Hash)
+	assert.Equal(t, SubmissionExpired, sub.State)
+	assert.Equal(t, current, sub.LastLedgerSequence)
+
+	// A rejected transaction is not tracked.
+	rejected := submitPayment(t, svc, map[string]interface{}{"Sequence": uint32(1), "Amount": "2000000000"})
+	_, ok = svc.GetSubmission(rejected.Hash)
+	assert.False(t, ok)
+
+	// Changes are reported in the order they happened.
+	statesOf := func(hash [32]byte) []SubmissionState {
+		mu.Lock()
+		defer mu.Unlock()
+		var states []SubmissionState
+		for _, sub := range notified {
+			if sub.Hash == hash {
+				states = append(states, sub.State)
+			}
+		}
+		return states
+	}
+	want := []SubmissionState{SubmissionQueued, SubmissionOpenLedger, SubmissionIncluded, SubmissionValidated}
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(want, statesOf(second.Hash))
+	}, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		states := statesOf(late.Hash)
+		return len(states) > 0 && states[len(states)-1] == SubmissionExpired
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestLocalTxsRetryWindow(t *testing.T) {
+	svc := newHistoryTestService(t, HistoryConfig{}, false)
+
+	// Without a LastLedgerSequence, a held transaction is re-applied for
+	// localTxHoldLedgers ledgers and stays tracked after that.
+	held := submitPayment(t, svc, map[string]interface{}{"Sequence": uint32(2)})
+	require.True(t, held.Queued)
+	acceptLedgers(t, svc, localTxHoldLedgers+2)
+	sub, ok := svc.GetSubmission(held.Hash)
+	require.True(t, ok)
+	assert.Equal(t, SubmissionQueued, sub.State)
+	assert.Equal(t, 1+localTxHoldLedgers, sub.Attempts)
+
+	// It is still reported once it makes it into a ledger.
+	first := submitPayment(t, svc, map[string]interface{}{"Sequence": uint32(1)})
+	require.True(t, first.Applied)
+	acceptLedgers(t, svc, 1)
+	sub, _ = svc.GetSubmission(held.Hash)
+	assert.Equal(t, SubmissionQueued, sub.State)
+	again := submitPayment(t, svc, map[string]interface{}{"Sequence": uint32(2)})
+	require.True(t, again.Applied)
+	acceptLedgers(t, svc, 1)
+	sub, _ = svc.GetSubmission(held.Hash)
+	assert.Equal(t, SubmissionValidated, sub.State)
+
+	// A held transaction whose sequence another transaction used is dropped.
+	replaced := submitPayment(t, svc, map[string]interface{}{"Sequence": uint32(4)})
+	require.True(t, replaced.Queued)
+	require.True(t, submitPayment(t, svc, map[string]interface{}{"Sequence": uint32(3)}).Applied)
+	require.True(t, submitPayment(t, svc, map[string]interface{}{"Sequence": uint32(4), "Amount": "1500000"}).Applied)
+	acceptLedgers(t, svc, 1)
+	sub, _ = svc.GetSubmission(replaced.Hash)
+	assert.Equal(t, SubmissionDropped, sub.State)
+}
+
+// A submission callback that falls behind drops changes instead of
+// blocking the service, which holds its lock while notifying.
+func TestLocalTxsSlowCallback(t *testing.T) {
+	svc := newHistoryTestService(t, HistoryConfig{}, false)
+
+	release := make(chan struct{})
+	received := make(chan struct{}, 1)
+	svc.SetSubmissionCallback(func(Submission) {
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+		<-release
+	})
+	defer close(release)
+
+	changed := make([]Submission, submissionQueueSize+3)
+	svc.mu.Lock()
+	svc.notifySubmissionsLocked(changed[:1])
+	svc.mu.Unlock()
+	<-received // the callback holds the first change
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		svc.mu.Lock()
+		defer svc.mu.Unlock()
+		svc.notifySubmissionsLocked(changed[1:])
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("notifying a slow submission callback blocked")
+	}
+	assert.Equal(t, uint64(2), svc.DroppedSubmissionChanges())
+}
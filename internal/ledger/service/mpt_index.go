@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+
+	addresscodec "github.com/LeJamon/goXRPLd/codec/addresscodec"
+	"github.com/LeJamon/goXRPLd/internal/ledger"
+	"github.com/LeJamon/goXRPLd/internal/ledger/state"
+	"github.com/LeJamon/goXRPLd/shamap"
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+// mptHolders returns the holders named by the MPToken nodes in the
+// metadata of a successful transaction, for the mpt_holders index. Like
+// Clio, only the (issuance, holder) pair is indexed; amounts and flags are
+// read from ledger state when queried. Modified and deleted nodes are
+// included so that holders created before indexing started are picked up
+// as their tokens change.
+func mptHolders(meta map[string]interface{}) []relationaldb.MPTHolder {
+	if result, _ := meta["TransactionResult"].(string); result != "tesSUCCESS" {
+		return nil
+	}
+
+	var holders []relationaldb.MPTHolder
+	nodes, _ := meta["AffectedNodes"].([]interface{})
+	for _, n := range nodes {
+		wrapper, ok := n.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for kind, v := range wrapper {
+			node, ok := v.(map[string]interface{})
+			if !ok || node["LedgerEntryType"] != "MPToken" {
+				continue
+			}
+			fields, _ := node["FinalFields"].(map[string]interface{})
+			if kind == "CreatedNode" {
+				fields, _ = node["NewFields"].(map[string]interface{})
+			}
+			if holder, ok := parseMPTHolder(fields); ok {
+				holders = append(holders, holder)
+			}
+		}
+	}
+	return holders
+}
+
+// parseMPTHolder reads the Account and MPTokenIssuanceID of MPToken fields.
+func parseMPTHolder(fields map[string]interface{}) (relationaldb.MPTHolder, bool) {
+	var holder relationaldb.MPTHolder
+	account, _ := fields["Account"].(string)
+	_, accountBytes, err := addresscodec.DecodeClassicAddressToAccountID(account)
+	if err != nil {
+		return holder, false
+	}
+	issuanceHex, _ := fields["MPTokenIssuanceID"].(string)
+	issuanceID, err := hex.DecodeString(issuanceHex)
+	if err != nil || len(issuanceID) != 24 {
+		return holder, false
+	}
+	copy(holder.Holder[:], accountBytes)
+	copy(holder.MPTIssuanceID[:], issuanceID)
+	return holder, true
+}
+
+// trackMPTIndexLocked records that l was persisted. While each ledger
+// follows the last, the holders named by its metadata keep the index
+// complete. After a gap, as on the first ledger persisted at startup,
+// holders that changed in the missing ledgers are unknown, so the index
+// is backfilled from l's state. The caller must hold s.mu.
+func (s *Service) trackMPTIndexLocked(l *ledger.Ledger) {
+	last := s.mptLastPersisted
+	s.mptLastPersisted = l
+	follows := last != nil && l.Sequence() == last.Sequence()+1 && l.ParentHash() == last.Hash()
+
+	switch {
+	case s.mptBackfilling:
+		if !follows {
+			s.mptBackfillGap = true
+		}
+	case follows && s.mptIndexed == last:
+		s.mptIndexed = l
+	default:
+		s.mptIndexed = nil
+		s.backfillMPTIndexLocked(l)
+	}
+}
+
+// backfillMPTIndexLocked saves the holder of every MPToken in base's
+// state to the index. The walk runs without s.mu; ledgers persisted
+// meanwhile index their own metadata, so once it is done the index is
+// complete up to the last of them. The caller must hold s.mu.
+func (s *Service) backfillMPTIndexLocked(base *ledger.Ledger) {
+	s.mptBackfilling = true
+	s.mptBackfillGap = false
+	s.mptBackfills.Add(1)
+	go func() {
+		defer s.mptBackfills.Done()
+		holders, err := mptHoldersInState(base, nil)
+		if err == nil {
+			err = s.relationalDB.MPT().SaveMPTHolders(context.Background(), holders)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.mptBackfilling = false
+		if err != nil {
+			s.logger.Warn("failed to backfill MPT holder index", "ledger", base.Sequence(), "error", err)
+			return
+		}
+		if s.mptBackfillGap {
+			s.backfillMPTIndexLocked(s.mptLastPersisted)
+			return
+		}
+		s.mptIndexFloor = base.Sequence()
+		s.mptIndexed = s.mptLastPersisted
+	}()
+}
+
+// mptHoldersInState returns the holder of each MPToken in l's state, or
+// only those of the issuance when mptID is non-nil.
+func mptHoldersInState(l *ledger.Ledger, mptID *[24]byte) ([]relationaldb.MPTHolder, error) {
+	var holders []relationaldb.MPTHolder
+	err := l.ForEach(func(_ [32]byte, data []byte) bool {
+		if holder, ok := mptHolderOf(data, mptID); ok {
+			holders = append(holders, holder)
+		}
+		return true
+	})
+	return holders, err
+}
+
+// mptHoldersChanged returns the holders of the issuance whose MPToken was
+// created or modified between from and to.
+func mptHoldersChanged(from, to *ledger.Ledger, mptID [24]byte) ([]relationaldb.MPTHolder, error) {
+	var holders []relationaldb.MPTHolder
+	err := from.ForEachStateDifference(to, nil, func(diff shamap.DifferenceItem) bool {
+		if diff.SecondItem == nil {
+			return true
+		}
+		if holder, ok := mptHolderOf(diff.SecondItem.Data(), &mptID); ok {
+			holders = append(holders, holder)
+		}
+		return true
+	})
+	return holders, err
+}
+
+// mptHolderOf returns the holder of an MPToken entry, if data is one of
+// the issuance (or of any issuance when mptID is nil).
+func mptHolderOf(data []byte, mptID *[24]byte) (relationaldb.MPTHolder, bool) {
+	if getLedgerEntryType(data) != "MPToken" {
+		return relationaldb.MPTHolder{}, false
+	}
+	token, err := state.ParseMPToken(data)
+	if err != nil || (mptID != nil && token.MPTokenIssuanceID != *mptID) {
+		return relationaldb.MPTHolder{}, false
+	}
+	return relationaldb.MPTHolder{MPTIssuanceID: token.MPTokenIssuanceID, Holder: relationaldb.AccountID(token.Account)}, true
+}
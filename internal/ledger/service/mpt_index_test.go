@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	addresscodec "github.com/LeJamon/goXRPLd/codec/addresscodec"
+	binarycodec "github.com/LeJamon/goXRPLd/codec/binarycodec"
+	"github.com/LeJamon/goXRPLd/keylet"
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMPTHoldersFromMeta(t *testing.T) {
+	issuance := [24]byte{0, 0, 0, 5, 0xa1}
+	issuanceHex := strings.ToUpper(hex.EncodeToString(issuance[:]))
+	bob, _ := addresscodec.EncodeAccountIDToClassicAddress(nftBob[:])
+	alice, _ := addresscodec.EncodeAccountIDToClassicAddress(nftAlice[:])
+
+	meta := nftMeta(
+		map[string]interface{}{"CreatedNode": map[string]interface{}{
+			"LedgerEntryType": "MPToken",
+			"NewFields":       map[string]interface{}{"Account": bob, "MPTokenIssuanceID": issuanceHex},
+		}},
+		map[string]interface{}{"ModifiedNode": map[string]interface{}{
+			"LedgerEntryType": "MPToken",
+			"FinalFields":     map[string]interface{}{"Account": alice, "MPTokenIssuanceID": issuanceHex, "MPTAmount": "64"},
+		}},
+		map[string]interface{}{"ModifiedNode": map[string]interface{}{
+			"LedgerEntryType": "MPTokenIssuance",
+			"FinalFields":     map[string]interface{}{"Issuer": alice},
+		}},
+	)
+	assert.ElementsMatch(t, []relationaldb.MPTHolder{
+		{MPTIssuanceID: issuance, Holder: nftBob},
+		{MPTIssuanceID: issuance, Holder: nftAlice},
+	}, mptHolders(meta))
+
+	meta["TransactionResult"] = "tecNO_AUTH"
+	assert.Empty(t, mptHolders(meta))
+}
+
+// insertMPTEntry encodes entry and inserts it into the open ledger at key.
+func insertMPTEntry(t *testing.T, svc *Service, key keylet.Keylet, entry map[string]interface{}) {
+	t.Helper()
+	encoded, err := binarycodec.Encode(entry)
+	require.NoError(t, err)
+	data, _ := hex.DecodeString(encoded)
+	require.NoError(t, svc.openLedger.Insert(key, data))
+}
+
+// waitMPTIndexed waits for the holder index backfill to complete.
+func waitMPTIndexed(t *testing.T, svc *Service) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		svc.mu.RLock()
+		defer svc.mu.RUnlock()
+		return !svc.mptBackfilling && svc.mptIndexed != nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestGetMPTHolders_Unindexed(t *testing.T) {
+	svc := newHistoryTestService(t, HistoryConfig{}, true)
+	waitMPTIndexed(t, svc)
+
+	issuer, _ := addresscodec.EncodeAccountIDToClassicAddress(nftAlice[:])
+	mptID := keylet.MakeMPTID(1, nftAlice)
+	insertMPTEntry(t, svc, keylet.MPTIssuance(mptID), map[string]interface{}{
+		"LedgerEntryType": "MPTokenIssuance", "Flags": uint32(0), "Issuer": issuer, "Sequence": uint32(1),
+		"OutstandingAmount": "0", "OwnerNode": "0",
+	})
+	carol := relationaldb.AccountID{0xc0}
+	for _, holder := range []relationaldb.AccountID{carol, nftBob} {
+		account, _ := addresscodec.EncodeAccountIDToClassicAddress(holder[:])
+		insertMPTEntry(t, svc, keylet.MPTokenByID(mptID, holder), map[string]interface{}{
+			"LedgerEntryType": "MPToken", "Flags": uint32(0), "Account": account,
+			"MPTokenIssuanceID": strings.ToUpper(hex.EncodeToString(mptID[:])), "MPTAmount": "64", "OwnerNode": "0",
+		})
+	}
+	accounts := func(result *MPTHoldersResult) []string {
+		var out []string
+		for _, h := range result.Holders {
+			out = append(out, h.Account)
+		}
+		return out
+	}
+	bobAddr, _ := addresscodec.EncodeAccountIDToClassicAddress(nftBob[:])
+	carolAddr, _ := addresscodec.EncodeAccountIDToClassicAddress(carol[:])
+
+	// The open ledger is newer than any indexed one: its holders come from
+	// the state changes since, paged in account order.
+	first, err := svc.GetMPTHolders(mptID, "current", nil, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{bobAddr}, accounts(first))
+	require.NotNil(t, first.Marker)
+	second, err := svc.GetMPTHolders(mptID, "current", first.Marker, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{carolAddr}, accounts(second))
+	assert.Nil(t, second.Marker)
+
+	// Without an index the requested ledger is scanned.
+	svc.mu.Lock()
+	svc.mptIndexed = nil
+	svc.mu.Unlock()
+	scanned, err := svc.GetMPTHolders(mptID, "current", nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{bobAddr, carolAddr}, accounts(scanned))
+
+	// A ledger that does not follow the last persisted one, as after a
+	// restart, backfills the index from its state.
+	svc.mu.Lock()
+	svc.mptLastPersisted = nil
+	svc.mu.Unlock()
+	_, err = svc.AcceptLedger()
+	require.NoError(t, err)
+	waitMPTIndexed(t, svc)
+	page, err := svc.relationalDB.MPT().GetMPTHolders(context.Background(), relationaldb.MPTHoldersOptions{MPTIssuanceID: mptID, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []relationaldb.AccountID{nftBob, carol}, page.Holders)
+	closed, err := svc.GetMPTHolders(mptID, "closed", nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{bobAddr, carolAddr}, accounts(closed))
+}
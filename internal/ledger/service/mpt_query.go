@@ -0,0 +1,279 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+
+	addresscodec "github.com/LeJamon/goXRPLd/codec/addresscodec"
+	"github.com/LeJamon/goXRPLd/internal/ledger"
+	"github.com/LeJamon/goXRPLd/internal/ledger/state"
+	"github.com/LeJamon/goXRPLd/keylet"
+	"github.com/LeJamon/goXRPLd/ledger/entry"
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+// ErrMPTIssuanceNotFound is returned when an issuance is not in the ledger.
+var ErrMPTIssuanceNotFound = errors.New("MPT issuance not found")
+
+// ErrNoMPTIndex is returned when no relational database holds the index.
+var ErrNoMPTIndex = errors.New("MPT holder index not available (no database configured)")
+
+// errStopWalk stops a directory walk early.
+var errStopWalk = errors.New("stop walk")
+
+// MPTHolderInfo is a holder's MPToken for the mpt_holders RPC. Locked is
+// set when either the token or the whole issuance is locked.
+type MPTHolderInfo struct {
+	Account      string
+	Flags        uint32
+	MPTAmount    uint64
+	LockedAmount *uint64
+	MPTokenIndex [32]byte
+	Locked       bool
+}
+
+// MPTHoldersResult contains the result of the mpt_holders RPC
+type MPTHoldersResult struct {
+	MPTIssuanceID [24]byte
+	Holders       []MPTHolderInfo
+	LedgerIndex   uint32
+	Validated     bool
+	Limit         uint32
+	Marker        *[20]byte
+}
+
+// MPTIssuanceInfo summarizes an MPTokenIssuance ledger entry
+type MPTIssuanceInfo struct {
+	MPTIssuanceID     [24]byte
+	Index             [32]byte
+	Sequence          uint32
+	Flags             uint32
+	AssetScale        uint8
+	TransferFee       uint16
+	MaximumAmount     *uint64
+	OutstandingAmount uint64
+	LockedAmount      *uint64
+	MPTokenMetadata   string
+	DomainID          *string
+}
+
+// AccountMPTIssuancesResult contains the result of the
+// account_mpt_issuances RPC
+type AccountMPTIssuancesResult struct {
+	Account     string
+	Issuances   []MPTIssuanceInfo
+	LedgerIndex uint32
+	LedgerHash  [32]byte
+	Validated   bool
+	Limit       uint32
+	Marker      *[32]byte
+}
+
+// GetMPTHolders returns a page of the holders of an issuance as of a
+// ledger. Candidates come in account order, mostly from the holder index
+// (see mptHolderCandidates); those without an MPToken in the ledger are
+// skipped, so a page can be short while a marker is still returned.
+// Reference: Clio MPTHolders.cpp
+func (s *Service) GetMPTHolders(mptID [24]byte, ledgerIndex string, marker *[20]byte, limit uint32) (*MPTHoldersResult, error) {
+	if s.relationalDB == nil {
+		return nil, ErrNoMPTIndex
+	}
+	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	issuanceData, err := targetLedger.Read(keylet.MPTIssuance(mptID))
+	if err != nil || issuanceData == nil {
+		return nil, ErrMPTIssuanceNotFound
+	}
+	issuance, err := state.ParseMPTokenIssuance(issuanceData)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, more, err := s.mptHolderCandidates(targetLedger, mptID, marker, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MPTHoldersResult{
+		MPTIssuanceID: mptID,
+		Holders:       make([]MPTHolderInfo, 0, len(candidates)),
+		LedgerIndex:   targetLedger.Sequence(),
+		Validated:     validated,
+		Limit:         limit,
+	}
+	for _, holder := range candidates {
+		tokenKey := keylet.MPTokenByID(mptID, holder)
+		data, err := targetLedger.Read(tokenKey)
+		if err != nil || data == nil {
+			continue
+		}
+		token, err := state.ParseMPToken(data)
+		if err != nil {
+			continue
+		}
+		account, _ := addresscodec.EncodeAccountIDToClassicAddress(holder[:])
+		result.Holders = append(result.Holders, MPTHolderInfo{
+			Account:      account,
+			Flags:        token.Flags,
+			MPTAmount:    token.MPTAmount,
+			LockedAmount: token.LockedAmount,
+			MPTokenIndex: tokenKey.Key,
+			Locked:       token.Flags&entry.LsfMPTLocked != 0 || issuance.Flags&entry.LsfMPTLocked != 0,
+		})
+	}
+	if more && len(candidates) > 0 {
+		m := [20]byte(candidates[len(candidates)-1])
+		result.Marker = &m
+	}
+	return result, nil
+}
+
+// mptHolderCandidates returns up to limit possible holders of an issuance
+// after marker, in account order, and whether more may follow. They come
+// from the holder index, plus the holders whose MPToken changed in the
+// ledgers after the last one indexed, such as the open ledger. When the
+// index does not cover the ledger at all, its state is scanned instead.
+func (s *Service) mptHolderCandidates(l *ledger.Ledger, mptID [24]byte, marker *[20]byte, limit uint32) ([]relationaldb.AccountID, bool, error) {
+	s.mu.RLock()
+	indexed, floor := s.mptIndexed, s.mptIndexFloor
+	s.mu.RUnlock()
+
+	var extra []relationaldb.MPTHolder
+	var err error
+	useIndex := true
+	switch {
+	case indexed == nil || l.Sequence() < floor:
+		extra, err = mptHoldersInState(l, &mptID)
+		useIndex = false
+	case l.Sequence() > indexed.Sequence():
+		extra, err = mptHoldersChanged(indexed, l, mptID)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var candidates []relationaldb.AccountID
+	var last *relationaldb.AccountID
+	if useIndex {
+		options := relationaldb.MPTHoldersOptions{MPTIssuanceID: mptID, Limit: limit}
+		if marker != nil {
+			m := relationaldb.AccountID(*marker)
+			options.Marker = &m
+		}
+		page, err := s.relationalDB.MPT().GetMPTHolders(context.Background(), options)
+		if err != nil {
+			return nil, false, err
+		}
+		candidates = append(candidates, page.Holders...)
+		last = page.Marker
+	}
+	// Holders past the end of the index page belong to a later page.
+	for _, holder := range extra {
+		if marker != nil && bytes.Compare(holder.Holder[:], marker[:]) <= 0 {
+			continue
+		}
+		if last != nil && bytes.Compare(holder.Holder[:], last[:]) > 0 {
+			continue
+		}
+		candidates = append(candidates, holder.Holder)
+	}
+
+	slices.SortFunc(candidates, func(a, b relationaldb.AccountID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	candidates = slices.Compact(candidates)
+	more := last != nil
+	if uint32(len(candidates)) > limit {
+		candidates = candidates[:limit]
+		more = true
+	}
+	return candidates, more, nil
+}
+
+// GetAccountMPTIssuances returns a page of the issuances an account
+// created, in owner directory order, starting after marker.
+func (s *Service) GetAccountMPTIssuances(account string, ledgerIndex string, marker *[32]byte, limit uint32) (*AccountMPTIssuancesResult, error) {
+	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	_, accountIDBytes, err := addresscodec.DecodeClassicAddressToAccountID(account)
+	if err != nil {
+		return nil, errors.New("invalid account address: " + err.Error())
+	}
+	var accountID [20]byte
+	copy(accountID[:], accountIDBytes)
+
+	exists, err := targetLedger.Exists(keylet.Account(accountID))
+	if err != nil {
+		return nil, errors.New("failed to check account existence: " + err.Error())
+	}
+	if !exists {
+		return nil, errors.New("account not found")
+	}
+
+	result := &AccountMPTIssuancesResult{
+		Account:     account,
+		Issuances:   make([]MPTIssuanceInfo, 0),
+		LedgerIndex: targetLedger.Sequence(),
+		LedgerHash:  targetLedger.Hash(),
+		Validated:   validated,
+		Limit:       limit,
+	}
+
+	started := marker == nil
+	err = forEachMPTIssuance(targetLedger, accountID, func(info MPTIssuanceInfo) error {
+		if !started {
+			started = info.Index == *marker
+			return nil
+		}
+		if uint32(len(result.Issuances)) == limit {
+			last := result.Issuances[len(result.Issuances)-1].Index
+			result.Marker = &last
+			return errStopWalk
+		}
+		result.Issuances = append(result.Issuances, info)
+		return nil
+	})
+	if err != nil && err != errStopWalk {
+		return nil, err
+	}
+	if !started {
+		return nil, errors.New("invalid marker")
+	}
+	return result, nil
+}
+
+// forEachMPTIssuance calls fn for each MPTokenIssuance in an account's
+// owner directory.
+func forEachMPTIssuance(l *ledger.Ledger, accountID [20]byte, fn func(MPTIssuanceInfo) error) error {
+	return state.DirForEach(l, keylet.OwnerDir(accountID), func(itemKey [32]byte) error {
+		data, err := l.Read(keylet.Keylet{Key: itemKey})
+		if err != nil || data == nil || getLedgerEntryType(data) != "MPTokenIssuance" {
+			return nil
+		}
+		issuance, err := state.ParseMPTokenIssuance(data)
+		if err != nil {
+			return nil
+		}
+		return fn(MPTIssuanceInfo{
+			MPTIssuanceID:     keylet.MakeMPTID(issuance.Sequence, issuance.Issuer),
+			Index:             itemKey,
+			Sequence:          issuance.Sequence,
+			Flags:             issuance.Flags,
+			AssetScale:        issuance.AssetScale,
+			TransferFee:       issuance.TransferFee,
+			MaximumAmount:     issuance.MaximumAmount,
+			OutstandingAmount: issuance.OutstandingAmount,
+			LockedAmount:      issuance.LockedAmount,
+			MPTokenMetadata:   issuance.MPTokenMetadata,
+			DomainID:          issuance.DomainID,
+		})
+	})
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"sort"
+
+	"github.com/LeJamon/goXRPLd/internal/ledger"
+	"github.com/LeJamon/goXRPLd/internal/ledger/state"
+	"github.com/LeJamon/goXRPLd/internal/tx/nftoken"
+	"github.com/LeJamon/goXRPLd/shamap"
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+// NFT indexing for the nft_info, nft_history and nfts_by_issuer methods.
+// Like Clio's NFTHelpers.cpp, NFT states and the transactions that touched
+// an NFT are derived from the NFTokenPage and NFTokenOffer nodes in the
+// metadata of successful transactions. NFTs minted before indexing started
+// are backfilled from the NFTokenPages in state.
+
+// nftToken is an NFT held in an NFTokenPage.
+type nftToken struct {
+	owner relationaldb.AccountID
+	uri   []byte
+}
+
+// nftIndexBatch collects the NFT changes of a ledger's transactions, which
+// are visited in map order, for saving in transaction order.
+type nftIndexBatch struct {
+	seq relationaldb.LedgerIndex
+	txs []nftTxChanges
+}
+
+// nftTxChanges are the NFT changes of one transaction.
+type nftTxChanges struct {
+	txnSeq uint32
+	hash   relationaldb.Hash
+	states []relationaldb.NFTRecord
+	ids    []relationaldb.Hash
+}
+
+// add records the NFT changes in the metadata of a transaction.
+func (b *nftIndexBatch) add(hash relationaldb.Hash, txnSeq uint32, meta map[string]interface{}) {
+	if result, _ := meta["TransactionResult"].(string); result != "tesSUCCESS" {
+		return
+	}
+	states, ids := nftChanges(b.seq, meta)
+	if len(ids) > 0 {
+		b.txs = append(b.txs, nftTxChanges{txnSeq: txnSeq, hash: hash, states: states, ids: ids})
+	}
+}
+
+// save writes the collected changes. When several transactions of the
+// ledger change an NFT, the state after the last one is kept.
+func (b *nftIndexBatch) save(ctx context.Context, repo relationaldb.NFTRepository) error {
+	sort.Slice(b.txs, func(i, j int) bool { return b.txs[i].txnSeq < b.txs[j].txnSeq })
+
+	var states []relationaldb.NFTRecord
+	var taxons []uint32
+	var links []relationaldb.NFTTransaction
+	for _, t := range b.txs {
+		for _, state := range t.states {
+			states = append(states, state)
+			taxons = append(taxons, nftTaxon(state.NFTokenID))
+		}
+		for _, id := range t.ids {
+			links = append(links, relationaldb.NFTTransaction{
+				NFTokenID: id, LedgerSeq: b.seq, TxnSeq: t.txnSeq, Hash: t.hash,
+			})
+		}
+	}
+	if err := repo.SaveNFTs(ctx, states, taxons); err != nil {
+		return err
+	}
+	return repo.SaveNFTTransactions(ctx, links)
+}
+
+// nftChanges returns the NFT states a transaction produced, for mints,
+// transfers, burns and URI changes, and the IDs of every NFT it touched,
+// which also covers offers created, cancelled or accepted.
+func nftChanges(seq relationaldb.LedgerIndex, meta map[string]interface{}) ([]relationaldb.NFTRecord, []relationaldb.Hash) {
+	before := make(map[relationaldb.Hash]nftToken)
+	after := make(map[relationaldb.Hash]nftToken)
+	touched := make(map[relationaldb.Hash]bool)
+
+	nodes, _ := meta["AffectedNodes"].([]interface{})
+	for _, n := range nodes {
+		wrapper, ok := n.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for kind, v := range wrapper {
+			node, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			finalFields, _ := node["FinalFields"].(map[string]interface{})
+			previousFields, _ := node["PreviousFields"].(map[string]interface{})
+			newFields, _ := node["NewFields"].(map[string]interface{})
+
+			switch node["LedgerEntryType"] {
+			case "NFTokenPage":
+				owner, ok := nftPageOwner(node["LedgerIndex"])
+				if !ok {
+					continue
+				}
+				// The tokens before the transaction are the previous ones
+				// when the page's token list changed, else the final ones.
+				switch kind {
+				case "CreatedNode":
+					collectNFTokens(after, owner, newFields)
+				case "ModifiedNode":
+					if _, changed := previousFields["NFTokens"]; changed {
+						collectNFTokens(before, owner, previousFields)
+					} else {
+						collectNFTokens(before, owner, finalFields)
+					}
+					collectNFTokens(after, owner, finalFields)
+				case "DeletedNode":
+					if _, changed := previousFields["NFTokens"]; changed {
+						collectNFTokens(before, owner, previousFields)
+					} else {
+						collectNFTokens(before, owner, finalFields)
+					}
+				}
+			case "NFTokenOffer":
+				fields := finalFields
+				if kind == "CreatedNode" {
+					fields = newFields
+				}
+				if id, ok := parseNFTokenID(fields["NFTokenID"]); ok {
+					touched[id] = true
+				}
+			}
+		}
+	}
+
+	var states []relationaldb.NFTRecord
+	for id, token := range after {
+		prev, existed := before[id]
+		if existed && prev.owner == token.owner && string(prev.uri) == string(token.uri) {
+			// Moved between pages of the same owner.
+			continue
+		}
+		states = append(states, relationaldb.NFTRecord{NFTokenID: id, LedgerSeq: seq, Owner: token.owner, URI: token.uri})
+		touched[id] = true
+	}
+	for id, token := range before {
+		if _, kept := after[id]; kept {
+			continue
+		}
+		states = append(states, relationaldb.NFTRecord{NFTokenID: id, LedgerSeq: seq, Owner: token.owner, IsBurned: true, URI: token.uri})
+		touched[id] = true
+	}
+	sort.Slice(states, func(i, j int) bool { return string(states[i].NFTokenID[:]) < string(states[j].NFTokenID[:]) })
+
+	ids := make([]relationaldb.Hash, 0, len(touched))
+	for id := range touched {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return string(ids[i][:]) < string(ids[j][:]) })
+	return states, ids
+}
+
+// collectNFTokens adds the tokens in the NFTokens array of fields to
+// tokens, held by owner.
+func collectNFTokens(tokens map[relationaldb.Hash]nftToken, owner relationaldb.AccountID, fields map[string]interface{}) {
+	list, _ := fields["NFTokens"].([]interface{})
+	for _, item := range list {
+		wrapper, _ := item.(map[string]interface{})
+		token, _ := wrapper["NFToken"].(map[string]interface{})
+		id, ok := parseNFTokenID(token["NFTokenID"])
+		if !ok {
+			continue
+		}
+		var uri []byte
+		if s, ok := token["URI"].(string); ok {
+			uri, _ = hex.DecodeString(s)
+		}
+		tokens[id] = nftToken{owner: owner, uri: uri}
+	}
+}
+
+// nftPageOwner returns the owner of an NFTokenPage, the first 20 bytes of
+// its key.
+func nftPageOwner(v interface{}) (relationaldb.AccountID, bool) {
+	var owner relationaldb.AccountID
+	s, _ := v.(string)
+	key, err := hex.DecodeString(s)
+	if err != nil || len(key) != 32 {
+		return owner, false
+	}
+	copy(owner[:], key[:20])
+	return owner, true
+}
+
+func parseNFTokenID(v interface{}) (relationaldb.Hash, bool) {
+	var id relationaldb.Hash
+	s, _ := v.(string)
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 32 {
+		return id, false
+	}
+	copy(id[:], b)
+	return id, true
+}
+
+// nftTaxon returns the unciphered taxon of an NFTokenID. The cipher is an
+// XOR with a value derived from the serial, so ciphering again deciphers.
+func nftTaxon(id relationaldb.Hash) uint32 {
+	return nftoken.CipheredTaxon(binary.BigEndian.Uint32(id[28:32]), binary.BigEndian.Uint32(id[24:28]))
+}
+
+// trackNFTIndexLocked records that l was persisted. Like the MPT holder
+// index (see trackMPTIndexLocked), the metadata of each ledger that
+// follows the last keeps the NFT index complete, and after a gap the
+// index is backfilled from l's state. The caller must hold s.mu.
+func (s *Service) trackNFTIndexLocked(l *ledger.Ledger) {
+	last := s.nftLastPersisted
+	s.nftLastPersisted = l
+	follows := last != nil && l.Sequence() == last.Sequence()+1 && l.ParentHash() == last.Hash()
+
+	switch {
+	case s.nftBackfilling:
+		if !follows {
+			s.nftBackfillGap = true
+		}
+	case follows && s.nftIndexed == last:
+		s.nftIndexed = l
+	default:
+		s.nftIndexed = nil
+		s.backfillNFTIndexLocked(l)
+	}
+}
+
+// backfillNFTIndexLocked saves the state of every NFT held in base to the
+// index, as of base. The walk runs without s.mu; ledgers persisted
+// meanwhile index their own metadata. The caller must hold s.mu.
+func (s *Service) backfillNFTIndexLocked(base *ledger.Ledger) {
+	s.nftBackfilling = true
+	s.nftBackfillGap = false
+	s.nftBackfills.Add(1)
+	go func() {
+		defer s.nftBackfills.Done()
+		nfts, err := nftsInState(base, nil)
+		if err == nil {
+			states := make([]relationaldb.NFTRecord, 0, len(nfts))
+			taxons := make([]uint32, 0, len(nfts))
+			for _, nft := range nfts {
+				states = append(states, nft)
+				taxons = append(taxons, nftTaxon(nft.NFTokenID))
+			}
+			err = s.relationalDB.NFT().SaveNFTs(context.Background(), states, taxons)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.nftBackfilling = false
+		if err != nil {
+			s.logger.Warn("failed to backfill NFT index", "ledger", base.Sequence(), "error", err)
+			return
+		}
+		if s.nftBackfillGap {
+			s.backfillNFTIndexLocked(s.nftLastPersisted)
+			return
+		}
+		s.nftIndexFloor = base.Sequence()
+		s.nftIndexed = s.nftLastPersisted
+	}()
+}
+
+// nftsInState returns the state of each NFT held in l, or only of those
+// match accepts when it is non-nil.
+func nftsInState(l *ledger.Ledger, match func(relationaldb.Hash) bool) (map[relationaldb.Hash]relationaldb.NFTRecord, error) {
+	nfts := make(map[relationaldb.Hash]relationaldb.NFTRecord)
+	err := l.ForEach(func(key [32]byte, data []byte) bool {
+		addPageNFTs(nfts, relationaldb.LedgerIndex(l.Sequence()), key, data, match)
+		return true
+	})
+	return nfts, err
+}
+
+// nftsChanged returns the state in to of each NFT that match accepts and
+// whose page changed between from and to. An NFT that left a changed page
+// without joining another was burned.
+func nftsChanged(from, to *ledger.Ledger, match func(relationaldb.Hash) bool) (map[relationaldb.Hash]relationaldb.NFTRecord, error) {
+	seq := relationaldb.LedgerIndex(to.Sequence())
+	nfts := make(map[relationaldb.Hash]relationaldb.NFTRecord)
+	left := make(map[relationaldb.Hash]relationaldb.NFTRecord)
+	err := from.ForEachStateDifference(to, nil, func(diff shamap.DifferenceItem) bool {
+		if diff.FirstItem != nil {
+			addPageNFTs(left, seq, diff.Key, diff.FirstItem.Data(), match)
+		}
+		if diff.SecondItem != nil {
+			addPageNFTs(nfts, seq, diff.Key, diff.SecondItem.Data(), match)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	for id, nft := range left {
+		if _, held := nfts[id]; !held {
+			nft.IsBurned = true
+			nfts[id] = nft
+		}
+	}
+	return nfts, nil
+}
+
+// addPageNFTs adds the NFTs of an NFTokenPage entry, owned by the account
+// in the first 20 bytes of its key, to nfts. Other entries are ignored.
+func addPageNFTs(nfts map[relationaldb.Hash]relationaldb.NFTRecord, seq relationaldb.LedgerIndex, key [32]byte, data []byte, match func(relationaldb.Hash) bool) {
+	if getLedgerEntryType(data) != "NFTokenPage" {
+		return
+	}
+	page, err := state.ParseNFTokenPage(data)
+	if err != nil {
+		return
+	}
+	for _, token := range page.NFTokens {
+		id := relationaldb.Hash(token.NFTokenID)
+		if match != nil && !match(id) {
+			continue
+		}
+		uri, _ := hex.DecodeString(token.URI)
+		nfts[id] = relationaldb.NFTRecord{NFTokenID: id, LedgerSeq: seq, Owner: relationaldb.AccountID(key[:20]), URI: uri}
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	addresscodec "github.com/LeJamon/goXRPLd/codec/addresscodec"
+	"github.com/LeJamon/goXRPLd/internal/tx/nftoken"
+	"github.com/LeJamon/goXRPLd/keylet"
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+	"github.com/LeJamon/goXRPLd/storage/relationaldb/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	nftAlice = relationaldb.AccountID{0xa1}
+	nftBob   = relationaldb.AccountID{0xb0}
+)
+
+// testNFTokenID builds an NFTokenID issued by nftAlice.
+func testNFTokenID(taxon, serial uint32) relationaldb.Hash {
+	var id relationaldb.Hash
+	copy(id[4:24], nftAlice[:])
+	ciphered := nftoken.CipheredTaxon(serial, taxon)
+	id[24], id[25], id[26], id[27] = byte(ciphered>>24), byte(ciphered>>16), byte(ciphered>>8), byte(ciphered)
+	id[28], id[29], id[30], id[31] = byte(serial>>24), byte(serial>>16), byte(serial>>8), byte(serial)
+	return id
+}
+
+// pageKey returns the key of an NFTokenPage of owner.
+func pageKey(owner relationaldb.AccountID, last byte) string {
+	var key [32]byte
+	copy(key[:], owner[:])
+	key[31] = last
+	return strings.ToUpper(hex.EncodeToString(key[:]))
+}
+
+func nfTokens(ids ...relationaldb.Hash) []interface{} {
+	list := make([]interface{}, len(ids))
+	for i, id := range ids {
+		list[i] = map[string]interface{}{"NFToken": map[string]interface{}{
+			"NFTokenID": strings.ToUpper(hex.EncodeToString(id[:])),
+			"URI":       "697066733A2F2F61",
+		}}
+	}
+	return list
+}
+
+func nftMeta(nodes ...map[string]interface{}) map[string]interface{} {
+	affected := make([]interface{}, len(nodes))
+	for i, n := range nodes {
+		affected[i] = n
+	}
+	return map[string]interface{}{"TransactionResult": "tesSUCCESS", "AffectedNodes": affected}
+}
+
+func TestNFTChanges(t *testing.T) {
+	minted := testNFTokenID(7, 1)
+	held := testNFTokenID(7, 2)
+
+	t.Run("mint", func(t *testing.T) {
+		states, ids := nftChanges(10, nftMeta(map[string]interface{}{
+			"ModifiedNode": map[string]interface{}{
+				"LedgerEntryType": "NFTokenPage",
+				"LedgerIndex":     pageKey(nftAlice, 0xff),
+				"PreviousFields":  map[string]interface{}{"NFTokens": nfTokens(held)},
+				"FinalFields":     map[string]interface{}{"NFTokens": nfTokens(held, minted)},
+			},
+		}))
+		require.Len(t, states, 1)
+		assert.Equal(t, relationaldb.NFTRecord{
+			NFTokenID: minted, LedgerSeq: 10, Owner: nftAlice, URI: []byte("ipfs://a"),
+		}, states[0])
+		assert.Equal(t, []relationaldb.Hash{minted}, ids)
+	})
+
+	t.Run("transfer on offer acceptance", func(t *testing.T) {
+		states, ids := nftChanges(11, nftMeta(
+			map[string]interface{}{"DeletedNode": map[string]interface{}{
+				"LedgerEntryType": "NFTokenPage",
+				"LedgerIndex":     pageKey(nftAlice, 0xff),
+				"FinalFields":     map[string]interface{}{"NFTokens": nfTokens(minted)},
+			}},
+			map[string]interface{}{"CreatedNode": map[string]interface{}{
+				"LedgerEntryType": "NFTokenPage",
+				"LedgerIndex":     pageKey(nftBob, 0xff),
+				"NewFields":       map[string]interface{}{"NFTokens": nfTokens(minted)},
+			}},
+			map[string]interface{}{"DeletedNode": map[string]interface{}{
+				"LedgerEntryType": "NFTokenOffer",
+				"LedgerIndex":     pageKey(nftBob, 1),
+				"FinalFields":     map[string]interface{}{"NFTokenID": hex.EncodeToString(minted[:])},
+			}},
+		))
+		require.Len(t, states, 1)
+		assert.Equal(t, nftBob, states[0].Owner)
+		assert.False(t, states[0].IsBurned)
+		assert.Equal(t, []relationaldb.Hash{minted}, ids)
+	})
+
+	t.Run("burn", func(t *testing.T) {
+		states, _ := nftChanges(12, nftMeta(map[string]interface{}{
+			"ModifiedNode": map[string]interface{}{
+				"LedgerEntryType": "NFTokenPage",
+				"LedgerIndex":     pageKey(nftAlice, 0xff),
+				"PreviousFields":  map[string]interface{}{"NFTokens": nfTokens(held, minted)},
+				"FinalFields":     map[string]interface{}{"NFTokens": nfTokens(held)},
+			},
+		}))
+		require.Len(t, states, 1)
+		assert.Equal(t, minted, states[0].NFTokenID)
+		assert.Equal(t, nftAlice, states[0].Owner)
+		assert.True(t, states[0].IsBurned)
+		assert.Equal(t, []byte("ipfs://a"), states[0].URI)
+	})
+
+	t.Run("page split", func(t *testing.T) {
+		states, ids := nftChanges(13, nftMeta(
+			map[string]interface{}{"ModifiedNode": map[string]interface{}{
+				"LedgerEntryType": "NFTokenPage",
+				"LedgerIndex":     pageKey(nftAlice, 0xff),
+				"PreviousFields":  map[string]interface{}{"NFTokens": nfTokens(held, minted)},
+				"FinalFields":     map[string]interface{}{"NFTokens": nfTokens(minted)},
+			}},
+			map[string]interface{}{"CreatedNode": map[string]interface{}{
+				"LedgerEntryType": "NFTokenPage",
+				"LedgerIndex":     pageKey(nftAlice, 0x01),
+				"NewFields":       map[string]interface{}{"NFTokens": nfTokens(held)},
+			}},
+		))
+		assert.Empty(t, states, "moving between an owner's pages changes nothing")
+		assert.Empty(t, ids)
+	})
+
+	t.Run("offer created", func(t *testing.T) {
+		states, ids := nftChanges(14, nftMeta(map[string]interface{}{
+			"CreatedNode": map[string]interface{}{
+				"LedgerEntryType": "NFTokenOffer",
+				"LedgerIndex":     pageKey(nftBob, 2),
+				"NewFields":       map[string]interface{}{"NFTokenID": hex.EncodeToString(held[:])},
+			},
+		}))
+		assert.Empty(t, states)
+		assert.Equal(t, []relationaldb.Hash{held}, ids)
+	})
+}
+
+func TestNFTTaxon(t *testing.T) {
+	assert.Equal(t, uint32(7), nftTaxon(testNFTokenID(7, 1)))
+	assert.Equal(t, uint32(0xFFFFFFFF), nftTaxon(testNFTokenID(0xFFFFFFFF, 12345)))
+}
+
+func TestNFTIndexBatchSave(t *testing.T) {
+	rm, err := sqlite.NewRepositoryManager(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, rm.Open(context.Background()))
+	defer rm.Close(context.Background())
+
+	id := testNFTokenID(7, 1)
+	mint := nftMeta(map[string]interface{}{"CreatedNode": map[string]interface{}{
+		"LedgerEntryType": "NFTokenPage",
+		"LedgerIndex":     pageKey(nftAlice, 0xff),
+		"NewFields":       map[string]interface{}{"NFTokens": nfTokens(id)},
+	}})
+	transfer := nftMeta(
+		map[string]interface{}{"DeletedNode": map[string]interface{}{
+			"LedgerEntryType": "NFTokenPage",
+			"LedgerIndex":     pageKey(nftAlice, 0xff),
+			"FinalFields":     map[string]interface{}{"NFTokens": nfTokens(id)},
+		}},
+		map[string]interface{}{"CreatedNode": map[string]interface{}{
+			"LedgerEntryType": "NFTokenPage",
+			"LedgerIndex":     pageKey(nftBob, 0xff),
+			"NewFields":       map[string]interface{}{"NFTokens": nfTokens(id)},
+		}},
+	)
+	failed := nftMeta()
+	failed["TransactionResult"] = "tecNO_ENTRY"
+
+	// Added out of order, as the transaction map is walked.
+	batch := &nftIndexBatch{seq: 20}
+	batch.add(relationaldb.Hash{2}, 1, transfer)
+	batch.add(relationaldb.Hash{3}, 2, failed)
+	batch.add(relationaldb.Hash{1}, 0, mint)
+	require.NoError(t, batch.save(context.Background(), rm.NFT()))
+
+	nft, err := rm.NFT().GetNFT(context.Background(), id, 20)
+	require.NoError(t, err)
+	require.NotNil(t, nft)
+	assert.Equal(t, nftBob, nft.Owner, "the state after the ledger's last change is kept")
+
+	issued, err := rm.NFT().GetNFTsByIssuer(context.Background(), relationaldb.NFTsByIssuerOptions{
+		Issuer: nftAlice, Taxon: func() *uint32 { v := uint32(7); return &v }(), LedgerSeq: 20, Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, issued.NFTs, 1)
+	assert.Equal(t, id, issued.NFTs[0].NFTokenID)
+}
+
+// waitNFTIndexed waits for the NFT index backfill to complete.
+func waitNFTIndexed(t *testing.T, svc *Service) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		svc.mu.RLock()
+		defer svc.mu.RUnlock()
+		return !svc.nftBackfilling && svc.nftIndexed != nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestNFTIndex_Unindexed(t *testing.T) {
+	svc := newHistoryTestService(t, HistoryConfig{}, true)
+	waitNFTIndexed(t, svc)
+
+	first, second := testNFTokenID(1, 1), testNFTokenID(2, 2)
+	insertMPTEntry(t, svc, keylet.NFTokenPageMax(nftBob), map[string]interface{}{
+		"LedgerEntryType": "NFTokenPage", "Flags": uint32(0), "NFTokens": nfTokens(second, first),
+	})
+	issuer, _ := addresscodec.EncodeAccountIDToClassicAddress(nftAlice[:])
+	bob, _ := addresscodec.EncodeAccountIDToClassicAddress(nftBob[:])
+	ids := func(result *NFTsByIssuerResult) []relationaldb.Hash {
+		var out []relationaldb.Hash
+		for _, nft := range result.NFTs {
+			out = append(out, relationaldb.Hash(nft.NFTokenID))
+		}
+		return out
+	}
+
+	// The open ledger is newer than any indexed one: its NFTs come from
+	// the state changes since.
+	info, err := svc.GetNFTInfo(first, "current")
+	require.NoError(t, err)
+	assert.Equal(t, bob, info.NFT.Owner)
+	assert.Equal(t, []byte("ipfs://a"), info.NFT.URI)
+	page, err := svc.GetNFTsByIssuer(issuer, nil, "current", nil, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []relationaldb.Hash{first}, ids(page))
+	require.NotNil(t, page.Marker)
+	page, err = svc.GetNFTsByIssuer(issuer, nil, "current", page.Marker, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []relationaldb.Hash{second}, ids(page))
+	assert.Nil(t, page.Marker)
+
+	// Without an index the requested ledger is scanned.
+	svc.mu.Lock()
+	svc.nftIndexed = nil
+	svc.mu.Unlock()
+	taxon := uint32(2)
+	page, err = svc.GetNFTsByIssuer(issuer, &taxon, "current", nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []relationaldb.Hash{second}, ids(page))
+
+	// A ledger that does not follow the last persisted one, as after a
+	// restart, backfills the index from its state.
+	svc.mu.Lock()
+	svc.nftLastPersisted = nil
+	svc.mu.Unlock()
+	closed, err := svc.AcceptLedger()
+	require.NoError(t, err)
+	waitNFTIndexed(t, svc)
+	record, err := svc.relationalDB.NFT().GetNFT(context.Background(), second, relationaldb.LedgerIndex(closed))
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, nftBob, record.Owner)
+	info, err = svc.GetNFTInfo(second, "closed")
+	require.NoError(t, err)
+	assert.Equal(t, bob, info.NFT.Owner)
+}
@@ -1,12 +1,17 @@
 package service
 
 import (
+	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
+	"slices"
 	"strconv"
 
 	addresscodec "github.com/LeJamon/goXRPLd/codec/addresscodec"
+	"github.com/LeJamon/goXRPLd/internal/ledger"
 	"github.com/LeJamon/goXRPLd/keylet"
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
 )
 
 // NFTOfferInfo represents an individual NFToken offer for nft_buy_offers/nft_sell_offers RPC
@@ -45,9 +50,6 @@ func (s *Service) GetNFTSellOffers(nftID [32]byte, ledgerIndex string, limit uin
 // getNFTOffers is the common implementation for both buy and sell offers
 // Reference: rippled NFTOffers.cpp enumerateNFTOffers
 func (s *Service) getNFTOffers(nftID [32]byte, ledgerIndex string, limit uint32, marker string, isSellOffers bool) (*NFTOffersResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	// Get the target ledger
 	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
 	if err != nil {
@@ -556,3 +558,274 @@ func parseDirectoryIndexesForNFT(data []byte) [][32]byte {
 
 	return indexes
 }
+
+// ErrNFTNotFound is returned when the NFT index has no record of an NFT.
+var ErrNFTNotFound = errors.New("NFT not found")
+
+// errNoNFTIndex is returned when no relational database holds the index.
+var errNoNFTIndex = errors.New("NFT index not available (no database configured)")
+
+// IndexedNFT is the state of an NFT from the NFT index, with the fields
+// encoded in its NFTokenID decoded.
+type IndexedNFT struct {
+	NFTokenID   [32]byte
+	Owner       string
+	IsBurned    bool
+	Flags       uint16
+	TransferFee uint16
+	Issuer      string
+	Taxon       uint32
+	Serial      uint32
+	URI         []byte
+}
+
+// NFTInfoResult contains the result of the nft_info RPC
+type NFTInfoResult struct {
+	NFT         IndexedNFT
+	LedgerIndex uint32
+	Validated   bool
+}
+
+// NFTHistoryResult contains the result of the nft_history RPC
+type NFTHistoryResult struct {
+	LedgerMin    uint32
+	LedgerMax    uint32
+	Limit        uint32
+	Marker       *relationaldb.AccountTxMarker
+	Transactions []AccountTransaction
+}
+
+// NFTsByIssuerResult contains the result of the nfts_by_issuer RPC
+type NFTsByIssuerResult struct {
+	NFTs        []IndexedNFT
+	LedgerIndex uint32
+	Validated   bool
+	Limit       uint32
+	Marker      *[32]byte
+}
+
+// GetNFTInfo returns the state of an NFT as of a ledger.
+// Reference: Clio NFTInfo.cpp
+func (s *Service) GetNFTInfo(nftID [32]byte, ledgerIndex string) (*NFTInfoResult, error) {
+	if s.relationalDB == nil {
+		return nil, errNoNFTIndex
+	}
+	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	id := relationaldb.Hash(nftID)
+	extra, useIndex, err := s.nftsNotIndexed(targetLedger, func(other relationaldb.Hash) bool { return other == id })
+	if err != nil {
+		return nil, err
+	}
+	var record *relationaldb.NFTRecord
+	if nft, ok := extra[id]; ok {
+		record = &nft
+	} else if useIndex {
+		record, err = s.relationalDB.NFT().GetNFT(context.Background(), id, relationaldb.LedgerIndex(targetLedger.Sequence()))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if record == nil {
+		return nil, ErrNFTNotFound
+	}
+	return &NFTInfoResult{
+		NFT:         indexedNFT(record),
+		LedgerIndex: targetLedger.Sequence(),
+		Validated:   validated,
+	}, nil
+}
+
+// GetNFTHistory returns a page of the transactions that touched an NFT.
+// The ledger range and marker work as for GetAccountTransactions.
+// Reference: Clio NFTHistory.cpp
+func (s *Service) GetNFTHistory(nftID [32]byte, ledgerMin, ledgerMax int64, limit uint32, marker *relationaldb.AccountTxMarker, forward bool) (*NFTHistoryResult, error) {
+	if s.relationalDB == nil {
+		return nil, errNoNFTIndex
+	}
+
+	minLedger := relationaldb.LedgerIndex(1)
+	if ledgerMin > 0 {
+		minLedger = relationaldb.LedgerIndex(ledgerMin)
+	}
+	maxLedger := relationaldb.LedgerIndex(0xFFFFFFFF)
+	s.mu.RLock()
+	if s.validatedLedger != nil {
+		maxLedger = relationaldb.LedgerIndex(s.validatedLedger.Sequence())
+	}
+	s.mu.RUnlock()
+	if ledgerMax > 0 && relationaldb.LedgerIndex(ledgerMax) < maxLedger {
+		maxLedger = relationaldb.LedgerIndex(ledgerMax)
+	}
+
+	page, err := s.relationalDB.NFT().GetNFTHistory(context.Background(), relationaldb.NFTHistoryOptions{
+		NFTokenID: relationaldb.Hash(nftID),
+		MinLedger: minLedger,
+		MaxLedger: maxLedger,
+		Marker:    marker,
+		Limit:     limit,
+		Forward:   forward,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NFTHistoryResult{
+		LedgerMin:    uint32(minLedger),
+		LedgerMax:    uint32(maxLedger),
+		Limit:        limit,
+		Marker:       page.Marker,
+		Transactions: make([]AccountTransaction, 0, len(page.Transactions)),
+	}
+	for _, txInfo := range page.Transactions {
+		result.Transactions = append(result.Transactions, AccountTransaction{
+			Hash:        [32]byte(txInfo.Hash),
+			LedgerIndex: uint32(txInfo.LedgerSeq),
+			TxnSeq:      txInfo.TxnSeq,
+			TxBlob:      txInfo.RawTxn,
+			Meta:        txInfo.TxnMeta,
+		})
+	}
+	return result, nil
+}
+
+// GetNFTsByIssuer returns a page of the NFTs an account issued, as of a
+// ledger, optionally limited to one taxon. NFTs are ordered by ID and
+// marker is the last ID of the previous page.
+// Reference: Clio NFTsByIssuer.cpp
+func (s *Service) GetNFTsByIssuer(issuer string, taxon *uint32, ledgerIndex string, marker *[32]byte, limit uint32) (*NFTsByIssuerResult, error) {
+	if s.relationalDB == nil {
+		return nil, errNoNFTIndex
+	}
+	_, issuerBytes, err := addresscodec.DecodeClassicAddressToAccountID(issuer)
+	if err != nil {
+		return nil, errors.New("invalid account address: " + err.Error())
+	}
+	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	options := relationaldb.NFTsByIssuerOptions{
+		Taxon:     taxon,
+		LedgerSeq: relationaldb.LedgerIndex(targetLedger.Sequence()),
+		Limit:     limit,
+	}
+	copy(options.Issuer[:], issuerBytes)
+	if marker != nil {
+		m := relationaldb.Hash(*marker)
+		options.Marker = &m
+	}
+
+	nfts, more, err := s.issuerNFTs(targetLedger, options)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NFTsByIssuerResult{
+		NFTs:        make([]IndexedNFT, 0, len(nfts)),
+		LedgerIndex: targetLedger.Sequence(),
+		Validated:   validated,
+		Limit:       limit,
+	}
+	for i := range nfts {
+		result.NFTs = append(result.NFTs, indexedNFT(&nfts[i]))
+	}
+	if more && len(nfts) > 0 {
+		m := [32]byte(nfts[len(nfts)-1].NFTokenID)
+		result.Marker = &m
+	}
+	return result, nil
+}
+
+// issuerNFTs returns up to options.Limit of the issuer's NFTs after the
+// marker, in ID order, and whether more may follow. They come from the NFT
+// index, updated with the NFTs whose page changed in the ledgers after the
+// last one indexed. When the index does not cover the ledger at all, its
+// state is scanned instead.
+func (s *Service) issuerNFTs(l *ledger.Ledger, options relationaldb.NFTsByIssuerOptions) ([]relationaldb.NFTRecord, bool, error) {
+	extra, useIndex, err := s.nftsNotIndexed(l, func(id relationaldb.Hash) bool {
+		return relationaldb.AccountID(id[4:24]) == options.Issuer &&
+			(options.Taxon == nil || nftTaxon(id) == *options.Taxon)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	var nfts []relationaldb.NFTRecord
+	var last *relationaldb.Hash
+	if useIndex {
+		page, err := s.relationalDB.NFT().GetNFTsByIssuer(context.Background(), options)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, nft := range page.NFTs {
+			if _, changed := extra[nft.NFTokenID]; !changed {
+				nfts = append(nfts, nft)
+			}
+		}
+		last = page.Marker
+	}
+	// NFTs past the end of the index page belong to a later page.
+	for id, nft := range extra {
+		if options.Marker != nil && bytes.Compare(id[:], options.Marker[:]) <= 0 {
+			continue
+		}
+		if last != nil && bytes.Compare(id[:], last[:]) > 0 {
+			continue
+		}
+		nfts = append(nfts, nft)
+	}
+
+	slices.SortFunc(nfts, func(a, b relationaldb.NFTRecord) int {
+		return bytes.Compare(a.NFTokenID[:], b.NFTokenID[:])
+	})
+	more := last != nil
+	if uint32(len(nfts)) > options.Limit {
+		nfts = nfts[:options.Limit]
+		more = true
+	}
+	return nfts, more, nil
+}
+
+// nftsNotIndexed returns the state in l of the NFTs match accepts that the
+// index may not hold as of l, and whether the index covers l. They are the
+// NFTs whose page changed in the ledgers after the last one indexed, such
+// as the open ledger. When the index does not cover l, as before its
+// backfill completes, all of l's NFTs are read from state.
+func (s *Service) nftsNotIndexed(l *ledger.Ledger, match func(relationaldb.Hash) bool) (map[relationaldb.Hash]relationaldb.NFTRecord, bool, error) {
+	s.mu.RLock()
+	indexed, floor := s.nftIndexed, s.nftIndexFloor
+	s.mu.RUnlock()
+
+	switch {
+	case indexed == nil || l.Sequence() < floor:
+		nfts, err := nftsInState(l, match)
+		return nfts, false, err
+	case l.Sequence() > indexed.Sequence():
+		nfts, err := nftsChanged(indexed, l, match)
+		return nfts, true, err
+	}
+	return nil, true, nil
+}
+
+// indexedNFT decodes an NFT index record.
+func indexedNFT(record *relationaldb.NFTRecord) IndexedNFT {
+	id := [32]byte(record.NFTokenID)
+	owner, _ := addresscodec.EncodeAccountIDToClassicAddress(record.Owner[:])
+	issuer, _ := addresscodec.EncodeAccountIDToClassicAddress(id[4:24])
+	return IndexedNFT{
+		NFTokenID:   id,
+		Owner:       owner,
+		IsBurned:    record.IsBurned,
+		Flags:       binary.BigEndian.Uint16(id[0:2]),
+		TransferFee: binary.BigEndian.Uint16(id[2:4]),
+		Issuer:      issuer,
+		Taxon:       nftTaxon(record.NFTokenID),
+		Serial:      binary.BigEndian.Uint32(id[28:32]),
+		URI:         record.URI,
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/LeJamon/goXRPLd/internal/ledger/state"
 	"github.com/LeJamon/goXRPLd/internal/tx"
+	"github.com/LeJamon/goXRPLd/ledger/entry"
 )
 
 // BookOffer represents an offer in an order book
@@ -14,6 +15,7 @@ type BookOffer struct {
 	LedgerEntryType string      `json:"LedgerEntryType"`
 	OwnerNode       string      `json:"OwnerNode"`
 	Sequence        uint32      `json:"Sequence"`
+	DomainID        string      `json:"DomainID,omitempty"`
 	TakerGets       interface{} `json:"TakerGets"`
 	TakerPays       interface{} `json:"TakerPays"`
 	Index           string      `json:"index"`
@@ -31,11 +33,19 @@ type BookOffersResult struct {
 	Validated   bool        `json:"validated"`
 }
 
-// GetBookOffers retrieves offers from an order book
-func (s *Service) GetBookOffers(takerGets, takerPays tx.Amount, ledgerIndex string, limit uint32) (*BookOffersResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// offerInBook reports whether an offer belongs to the open book (domain
+// nil) or to the given permissioned domain's book. Hybrid offers sit in
+// both their domain book and the open book.
+func offerInBook(offer *state.LedgerOffer, domain *[32]byte) bool {
+	if domain != nil {
+		return offer.DomainID == *domain
+	}
+	return offer.DomainID == [32]byte{} || offer.Flags&entry.OfferHybrid != 0
+}
 
+// GetBookOffers retrieves offers from an order book. A non-nil domain
+// selects that permissioned domain's book instead of the open DEX.
+func (s *Service) GetBookOffers(takerGets, takerPays tx.Amount, domain *[32]byte, ledgerIndex string, limit uint32) (*BookOffersResult, error) {
 	// Determine which ledger to use
 	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
 	if err != nil {
@@ -85,6 +95,9 @@ func (s *Service) GetBookOffers(takerGets, takerPays tx.Amount, ledgerIndex stri
 		if !getsMatch || !paysMatch {
 			return true
 		}
+		if !offerInBook(offer, domain) {
+			return true
+		}
 
 		// Build book offer response
 		bookOffer := BookOffer{
@@ -95,6 +108,9 @@ func (s *Service) GetBookOffers(takerGets, takerPays tx.Amount, ledgerIndex stri
 			Index:           formatHash(key),
 			Quality:         calculateOfferQuality(offer.TakerPays, offer.TakerGets),
 		}
+		if offer.DomainID != ([32]byte{}) {
+			bookOffer.DomainID = formatHashHex(offer.DomainID)
+		}
 
 		// Format TakerGets
 		if offer.TakerGets.IsNative() {
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+
+	addresscodec "github.com/LeJamon/goXRPLd/codec/addresscodec"
+	"github.com/LeJamon/goXRPLd/internal/ledger/state"
+	"github.com/LeJamon/goXRPLd/keylet"
+)
+
+// OwnedEntry is an entry of an account's owner directory
+type OwnedEntry struct {
+	Index [32]byte
+	Data  []byte
+}
+
+// OwnerInfoResult contains the result of an owner_info query on one ledger
+type OwnerInfoResult struct {
+	LedgerIndex uint32
+	LedgerHash  [32]byte
+	Validated   bool
+	Offers      []OwnedEntry
+	RippleLines []OwnedEntry
+}
+
+// GetOwnerInfo returns the offers and trust lines in an account's owner
+// directory, in directory order. Like rippled's NetworkOPs::getOwnerInfo
+// it does not require the account to exist: without a directory both
+// lists are empty.
+func (s *Service) GetOwnerInfo(account string, ledgerIndex string) (*OwnerInfoResult, error) {
+	targetLedger, validated, err := s.getLedgerForQuery(ledgerIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	_, accountIDBytes, err := addresscodec.DecodeClassicAddressToAccountID(account)
+	if err != nil {
+		return nil, errors.New("invalid account address: " + err.Error())
+	}
+	var accountID [20]byte
+	copy(accountID[:], accountIDBytes)
+
+	result := &OwnerInfoResult{
+		LedgerIndex: targetLedger.Sequence(),
+		LedgerHash:  targetLedger.Hash(),
+		Validated:   validated,
+	}
+	err = state.DirForEach(targetLedger, keylet.OwnerDir(accountID), func(itemKey [32]byte) error {
+		data, err := targetLedger.Read(keylet.Keylet{Key: itemKey})
+		if err != nil || data == nil {
+			return nil
+		}
+		switch getLedgerEntryType(data) {
+		case "Offer":
+			result.Offers = append(result.Offers, OwnedEntry{Index: itemKey, Data: data})
+		case "RippleState":
+			result.RippleLines = append(result.RippleLines, OwnedEntry{Index: itemKey, Data: data})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"encoding/hex"
+	"testing"
+
+	addresscodec "github.com/LeJamon/goXRPLd/codec/addresscodec"
+	"github.com/LeJamon/goXRPLd/codec/binarycodec"
+	"github.com/LeJamon/goXRPLd/internal/ledger/state"
+	"github.com/LeJamon/goXRPLd/keylet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOwnerInfo(t *testing.T) {
+	svc := newHistoryTestService(t, HistoryConfig{}, false)
+	alice, _ := addresscodec.EncodeAccountIDToClassicAddress(nftAlice[:])
+	bob, _ := addresscodec.EncodeAccountIDToClassicAddress(nftBob[:])
+
+	// Put an offer, a trust line and a ticket in alice's owner directory
+	// of the open ledger.
+	usd := func(issuer string) map[string]interface{} {
+		return map[string]interface{}{"currency": "USD", "issuer": issuer, "value": "0"}
+	}
+	entries := []map[string]interface{}{
+		{"LedgerEntryType": "Offer", "Account": alice, "Sequence": uint32(1), "TakerGets": "100", "TakerPays": usd(bob)},
+		{"LedgerEntryType": "RippleState", "Balance": usd(alice), "LowLimit": usd(alice), "HighLimit": usd(bob)},
+		{"LedgerEntryType": "Ticket", "Account": alice, "TicketSequence": uint32(2)},
+	}
+	ownerDir := keylet.OwnerDir(nftAlice)
+	for i, entry := range entries {
+		encoded, err := binarycodec.Encode(entry)
+		require.NoError(t, err)
+		data, _ := hex.DecodeString(encoded)
+		key := [32]byte{0xee, byte(i)}
+		require.NoError(t, svc.openLedger.Insert(keylet.Keylet{Key: key}, data))
+		_, err = state.DirInsert(svc.openLedger, ownerDir, key, func(dir *state.DirectoryNode) {
+			dir.Owner = nftAlice
+		})
+		require.NoError(t, err)
+	}
+
+	current, err := svc.GetOwnerInfo(alice, "current")
+	require.NoError(t, err)
+	assert.False(t, current.Validated)
+	require.Len(t, current.Offers, 1)
+	assert.Equal(t, [32]byte{0xee, 0}, current.Offers[0].Index)
+	require.Len(t, current.RippleLines, 1)
+	assert.Equal(t, [32]byte{0xee, 1}, current.RippleLines[0].Index)
+
+	// The validated ledger has not seen them yet.
+	accepted, err := svc.GetOwnerInfo(alice, "validated")
+	require.NoError(t, err)
+	assert.True(t, accepted.Validated)
+	assert.Empty(t, accepted.Offers)
+	assert.Empty(t, accepted.RippleLines)
+
+	_, err = svc.GetOwnerInfo("not-an-address", "current")
+	assert.Error(t, err)
+}
@@ -34,33 +34,15 @@ func (s *Service) persistLedger(l *ledger.Ledger) error {
 	return nil
 }
 
-// persistToNodeStore writes ledger state to the nodestore
+// persistToNodeStore writes the ledger's state and transaction map nodes,
+// keyed by node hash, followed by its header keyed by ledger hash. This is
+// everything loadLedger needs to rebuild the ledger once it has left memory.
+// Reference: rippled Ledger.cpp pendSaveValidated / saveValidatedLedger
 func (s *Service) persistToNodeStore(ctx context.Context, l *ledger.Ledger, seq uint32) error {
-	// Collect nodes to store in batch
-	var nodes []*nodestore.Node
-
-	// Persist state map entries
-	err := l.ForEach(func(key [32]byte, data []byte) bool {
-		node := &nodestore.Node{
-			Type:      nodestore.NodeAccount,
-			Hash:      nodestore.Hash256(key),
-			Data:      data,
-			LedgerSeq: seq,
-		}
-		nodes = append(nodes, node)
-		return true
-	})
-	if err != nil {
+	if _, err := l.FlushMaps(s.family); err != nil {
 		return err
 	}
 
-	// Store nodes in batch for efficiency
-	if len(nodes) > 0 {
-		if err := s.nodeStore.StoreBatch(ctx, nodes); err != nil {
-			return err
-		}
-	}
-
 	// Persist ledger header
 	headerData := l.SerializeHeader()
 	headerNode := &nodestore.Node{
@@ -77,7 +59,8 @@ func (s *Service) persistToNodeStore(ctx context.Context, l *ledger.Ledger, seq
 	return s.nodeStore.Sync()
 }
 
-// persistToRelationalDB writes ledger metadata and transactions to the relational database
+// persistToRelationalDB writes ledger metadata and transactions to the relational database.
+// The caller must hold s.mu, which guards the MPT holder index tracking.
 func (s *Service) persistToRelationalDB(ctx context.Context, l *ledger.Ledger) error {
 	h := l.Header()
 
@@ -106,6 +89,8 @@ func (s *Service) persistToRelationalDB(ctx context.Context, l *ledger.Ledger) e
 
 	// Persist transactions to the relational DB for account_tx / tx_history queries
 	seq := relationaldb.LedgerIndex(l.Sequence())
+	nfts := &nftIndexBatch{seq: seq}
+	var holders []relationaldb.MPTHolder
 
 	l.ForEachTransaction(func(txHashBytes [32]byte, txData []byte) bool {
 		txBlob, metaBlob, err := tx.SplitTxWithMetaBlob(txData)
@@ -114,30 +99,14 @@ func (s *Service) persistToRelationalDB(ctx context.Context, l *ledger.Ledger) e
 			return true // skip this tx, continue
 		}
 
-		// Extract Account (sender) and optional Destination from the tx blob
-		var accountID relationaldb.AccountID
-		var destinationID relationaldb.AccountID
-
-		txBlobHex := hex.EncodeToString(txBlob)
-		txJSON, decErr := binarycodec.Decode(txBlobHex)
-		if decErr == nil {
-			if accountStr, ok := txJSON["Account"].(string); ok {
-				if _, accountBytes, err := addresscodec.DecodeClassicAddressToAccountID(accountStr); err == nil && len(accountBytes) == 20 {
-					copy(accountID[:], accountBytes)
-				}
-			}
-			if destStr, ok := txJSON["Destination"].(string); ok {
-				if _, destBytes, err := addresscodec.DecodeClassicAddressToAccountID(destStr); err == nil && len(destBytes) == 20 {
-					copy(destinationID[:], destBytes)
-				}
-			}
-		}
+		accountID, destinationID, txType := txIndexFields(txBlob)
 
 		// Extract TransactionIndex from metadata
 		var txnSeq uint32
+		var metaJSON map[string]interface{}
 		if len(metaBlob) > 0 {
 			metaHex := hex.EncodeToString(metaBlob)
-			if metaJSON, err := binarycodec.Decode(metaHex); err == nil {
+			if metaJSON, err = binarycodec.Decode(metaHex); err == nil {
 				if v, ok := metaJSON["TransactionIndex"].(float64); ok {
 					txnSeq = uint32(v)
 				}
@@ -145,13 +114,15 @@ func (s *Service) persistToRelationalDB(ctx context.Context, l *ledger.Ledger) e
 		}
 
 		txInfo := &relationaldb.TransactionInfo{
-			Hash:      relationaldb.Hash(txHashBytes),
-			LedgerSeq: seq,
-			TxnSeq:    txnSeq,
-			Status:    "validated",
-			RawTxn:    txBlob,
-			TxnMeta:   metaBlob,
-			Account:   accountID,
+			Hash:        relationaldb.Hash(txHashBytes),
+			LedgerSeq:   seq,
+			TxnSeq:      txnSeq,
+			Status:      "validated",
+			RawTxn:      txBlob,
+			TxnMeta:     metaBlob,
+			Account:     accountID,
+			TxType:      txType,
+			Destination: destinationID,
 		}
 
 		// Save to transactions table
@@ -174,8 +145,83 @@ func (s *Service) persistToRelationalDB(ctx context.Context, l *ledger.Ledger) e
 			}
 		}
 
+		if metaJSON != nil {
+			nfts.add(txInfo.Hash, txnSeq, metaJSON)
+			holders = append(holders, mptHolders(metaJSON)...)
+		}
+
 		return true // continue
 	})
 
+	if err := nfts.save(ctx, s.relationalDB.NFT()); err != nil {
+		s.logger.Warn("failed to save NFT index", "ledger", seq, "error", err)
+	}
+	if err := s.relationalDB.MPT().SaveMPTHolders(ctx, holders); err != nil {
+		s.logger.Warn("failed to save MPT holder index", "ledger", seq, "error", err)
+	}
+	s.trackMPTIndexLocked(l)
+	s.trackNFTIndexLocked(l)
+
+	return nil
+}
+
+// txIndexFields extracts the Account (sender), optional Destination and
+// transaction type of a tx blob, which the account_transactions index
+// records. They are zero if the blob cannot be decoded.
+func txIndexFields(txBlob []byte) (accountID, destinationID relationaldb.AccountID, txType string) {
+	txJSON, err := binarycodec.Decode(hex.EncodeToString(txBlob))
+	if err != nil {
+		return accountID, destinationID, ""
+	}
+	txType, _ = txJSON["TransactionType"].(string)
+	if accountStr, ok := txJSON["Account"].(string); ok {
+		if _, accountBytes, err := addresscodec.DecodeClassicAddressToAccountID(accountStr); err == nil && len(accountBytes) == 20 {
+			copy(accountID[:], accountBytes)
+		}
+	}
+	if destStr, ok := txJSON["Destination"].(string); ok {
+		if _, destBytes, err := addresscodec.DecodeClassicAddressToAccountID(destStr); err == nil && len(destBytes) == 20 {
+			copy(destinationID[:], destBytes)
+		}
+	}
+	return accountID, destinationID, txType
+}
+
+// accountTxTypeBatch is the number of account_transactions rows typed per
+// query by backfillAccountTxTypes.
+const accountTxTypeBatch = 512
+
+// backfillAccountTxTypes records the transaction type and direction of
+// account_transactions rows indexed before the columns existed, decoding
+// their raw transactions, so that account_tx filters match them. Rows
+// whose transaction cannot be decoded keep an empty type. Until it
+// completes, filtered pages can miss those rows.
+func (s *Service) backfillAccountTxTypes(ctx context.Context) error {
+	repo := s.relationalDB.AccountTransaction()
+	var marker *relationaldb.UntypedAccountTx
+	typed := 0
+	for {
+		rows, err := repo.GetUntypedAccountTransactions(ctx, marker, accountTxTypeBatch)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			break
+		}
+		for i := range rows {
+			account, destination, txType := txIndexFields(rows[i].RawTxn)
+			rows[i].TxType = txType
+			rows[i].Direction = relationaldb.AccountTxDirectionOf(rows[i].Account,
+				&relationaldb.TransactionInfo{Account: account, Destination: destination})
+		}
+		if err := repo.SetAccountTransactionTypes(ctx, rows); err != nil {
+			return err
+		}
+		typed += len(rows)
+		marker = &rows[len(rows)-1]
+	}
+	if typed > 0 {
+		s.logger.Info("backfilled account transaction types", "rows", typed)
+	}
 	return nil
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"github.com/LeJamon/goXRPLd/internal/ledger"
 	"github.com/LeJamon/goXRPLd/internal/ledger/genesis"
 	"github.com/LeJamon/goXRPLd/internal/ledger/header"
+	"github.com/LeJamon/goXRPLd/internal/ledger/manager"
 	"github.com/LeJamon/goXRPLd/internal/tx"
 	xrpllog "github.com/LeJamon/goXRPLd/log"
 	"github.com/LeJamon/goXRPLd/shamap"
@@ -47,6 +49,10 @@ type Config struct {
 	// RelationalDB is the repository manager for transaction indexing (optional)
 	RelationalDB relationaldb.RepositoryManager
 
+	// History bounds the ledgers kept in memory. Zero values select the
+	// defaults.
+	History HistoryConfig
+
 	// Logger is the logger for the ledger service.
 	// If nil, xrpllog.Discard() is used.
 	Logger xrpllog.Logger
@@ -124,9 +130,25 @@ type Service struct {
 	// Genesis ledger
 	genesisLedger *ledger.Ledger
 
-	// Ledger history (sequence -> ledger) - in-memory cache
+	// Ledger history (sequence -> ledger) - in-memory window, bounded by
+	// config.History. See history.go.
 	ledgerHistory map[uint32]*ledger.Ledger
 
+	// historySizes holds the estimated memory of each ledger in
+	// ledgerHistory. Only maintained when History.MaxBytes is set.
+	historySizes map[*ledger.Ledger]int64
+
+	// historyFloor is the lowest sequence evicted from ledgerHistory that
+	// can still be reloaded from storage, or 0 if none has been.
+	historyFloor uint32
+
+	// ledgerCache holds ledgers recently evicted from ledgerHistory or
+	// reloaded from storage.
+	ledgerCache *manager.LedgerCache
+
+	// family reads and writes SHAMap nodes in nodeStore (nil if in-memory only)
+	family *shamap.NodeStoreFamily
+
 	// Transaction index (hash -> ledger sequence) - in-memory cache
 	txIndex map[[32]byte]uint32
 
@@ -138,6 +160,55 @@ type Service struct {
 	// Reference: rippled CanonicalTXSet / retriableTxs
 	pendingTxs []pendingTx
 
+	// localTxs tracks the transactions submitted to this server, by hash,
+	// re-applying those not yet in a closed ledger after each close.
+	localTxs map[[32]byte]*localTx
+
+	// submissionQueue feeds the submission callback, in order, each time
+	// a submission's state changes (nil if no callback is set).
+	submissionQueue chan Submission
+
+	// droppedSubmissions counts the changes not queued for the submission
+	// callback because submissionQueue was full.
+	droppedSubmissions uint64
+
+	// mptIndexed is the newest persisted ledger whose MPT holders are all
+	// in the holder index, and mptIndexFloor the ledger the index was
+	// backfilled from; mptIndexed is nil until a backfill completes. See
+	// mpt_index.go.
+	mptIndexed    *ledger.Ledger
+	mptIndexFloor uint32
+
+	// mptLastPersisted is the last ledger persisted to relationalDB.
+	mptLastPersisted *ledger.Ledger
+
+	// mptBackfilling is set while a holder index backfill runs, and
+	// mptBackfillGap when a ledger persisted meanwhile did not follow the
+	// one before it.
+	mptBackfilling bool
+	mptBackfillGap bool
+
+	// mptBackfills tracks running backfills, for Stop.
+	mptBackfills sync.WaitGroup
+
+	// nftIndexed, nftIndexFloor, nftLastPersisted, nftBackfilling,
+	// nftBackfillGap and nftBackfills track the NFT index like the mpt
+	// fields above track the holder index. See nft_index.go.
+	nftIndexed       *ledger.Ledger
+	nftIndexFloor    uint32
+	nftLastPersisted *ledger.Ledger
+	nftBackfilling   bool
+	nftBackfillGap   bool
+	nftBackfills     sync.WaitGroup
+
+	// txTypeBackfill tracks the backfill of account transaction types
+	// started by Start, for Stop. See backfillAccountTxTypes.
+	txTypeBackfill sync.WaitGroup
+
+	// signatureVerified reports transactions whose signature a cluster
+	// member already checked; see SetSignatureVerified.
+	signatureVerified func(txID [32]byte) bool
+
 	// EventCallback is called when a ledger becomes validated by consensus.
 	// Fires at quorum-gate time from SetValidatedLedger, not at close time,
 	// so WebSocket subscribers see ledger_index advances in lockstep with
@@ -201,17 +272,31 @@ func New(cfg Config) (*Service, error) {
 	if logger == nil {
 		logger = xrpllog.Discard()
 	}
+	cfg.History = cfg.History.withDefaults()
+	ledgerCache, err := manager.NewLedgerCache(manager.LedgerCacheConfig{
+		MaxRecentLedgers: cfg.History.CacheLedgers,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ledger cache: %w", err)
+	}
+
 	s := &Service{
 		config:                   cfg,
 		logger:                   logger.Named(xrpllog.PartitionLedger),
 		nodeStore:                cfg.NodeStore,
 		relationalDB:             cfg.RelationalDB,
 		ledgerHistory:            make(map[uint32]*ledger.Ledger),
+		historySizes:             make(map[*ledger.Ledger]int64),
+		ledgerCache:              ledgerCache,
 		txIndex:                  make(map[[32]byte]uint32),
 		txPositionIndex:          make(map[[32]byte]uint32),
 		pendingValidation:        make(map[[32]byte]*LedgerAcceptedEvent),
 		pendingLedgerValidations: make(map[uint32]pendingValidationEntry),
 		heldAdoptions:            make(map[uint32]*pendingAdopt),
+		localTxs:                 make(map[[32]byte]*localTx),
+	}
+	if cfg.NodeStore != nil {
+		s.family = shamap.NewNodeStoreFamily(cfg.NodeStore)
 	}
 
 	return s, nil
@@ -224,6 +309,16 @@ func (s *Service) SetEventCallback(callback EventCallback) {
 	s.eventCallback = callback
 }
 
+// SetSignatureVerified sets the function reporting whether a
+// transaction's signature is already known to be good. Transactions it
+// vouches for skip signature verification when a consensus result is
+// applied, as rippled does for transactions relayed by cluster members.
+func (s *Service) SetSignatureVerified(verified func(txID [32]byte) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.signatureVerified = verified
+}
+
 // SetEventHooks sets the event hooks for ledger events
 // This provides a more structured callback mechanism than SetEventCallback
 func (s *Service) SetEventHooks(hooks *EventHooks) {
@@ -239,6 +334,15 @@ func (s *Service) GetEventHooks() *EventHooks {
 	return s.hooks
 }
 
+// Stop waits for the service's background work, backfills of the MPT
+// holder and NFT indexes and of account transaction types, to finish so
+// that its storage can be closed.
+func (s *Service) Stop() {
+	s.mptBackfills.Wait()
+	s.nftBackfills.Wait()
+	s.txTypeBackfill.Wait()
+}
+
 // Start initializes the service with a genesis ledger
 func (s *Service) Start() error {
 	s.mu.Lock()
@@ -261,7 +365,7 @@ func (s *Service) Start() error {
 	)
 
 	s.genesisLedger = genesisLedger
-	s.ledgerHistory[genesisLedger.Sequence()] = genesisLedger
+	s.addToHistoryLocked(genesisLedger)
 
 	hash := genesisLedger.Hash()
 	s.logger.Info("Genesis ledger created",
@@ -282,9 +386,14 @@ func (s *Service) Start() error {
 		if err := nextLedger.SetValidated(); err != nil {
 			return errors.New("failed to validate initial ledger: " + err.Error())
 		}
+		// Persist it like any closed ledger so it can be reloaded once it
+		// leaves the in-memory history.
+		if err := s.persistLedger(nextLedger); err != nil {
+			return errors.New("failed to persist initial ledger: " + err.Error())
+		}
 		s.closedLedger = nextLedger
 		s.validatedLedger = nextLedger
-		s.ledgerHistory[nextLedger.Sequence()] = nextLedger
+		s.addToHistoryLocked(nextLedger)
 
 		// Create the open ledger (ledger 3)
 		openLedger, err := ledger.NewOpen(nextLedger, time.Now())
@@ -310,6 +419,16 @@ func (s *Service) Start() error {
 	// Reset pending transactions
 	s.pendingTxs = nil
 
+	if s.relationalDB != nil {
+		s.txTypeBackfill.Add(1)
+		go func() {
+			defer s.txTypeBackfill.Done()
+			if err := s.backfillAccountTxTypes(context.Background()); err != nil {
+				s.logger.Warn("failed to backfill account transaction types", "error", err)
+			}
+		}()
+	}
+
 	s.logger.Info("Ledger service started",
 		"standalone", s.config.Standalone,
 		"openLedger", s.openLedger.Sequence(),
@@ -340,29 +459,24 @@ func (s *Service) GetValidatedLedger() *ledger.Ledger {
 	return s.validatedLedger
 }
 
-// GetLedgerBySequence returns a ledger by its sequence number
+// GetLedgerBySequence returns a ledger by its sequence number. Ledgers
+// that have left the in-memory history are reloaded from storage.
 func (s *Service) GetLedgerBySequence(seq uint32) (*ledger.Ledger, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	l, ok := s.ledgerHistory[seq]
+	l, ok := s.ledgerBySeq(seq)
 	if !ok {
 		return nil, ErrLedgerNotFound
 	}
 	return l, nil
 }
 
-// GetLedgerByHash returns a ledger by its hash
+// GetLedgerByHash returns a ledger by its hash. Ledgers that have left the
+// in-memory history are reloaded from storage.
 func (s *Service) GetLedgerByHash(hash [32]byte) (*ledger.Ledger, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	for _, l := range s.ledgerHistory {
-		if l.Hash() == hash {
-			return l, nil
-		}
+	l, ok := s.ledgerByHash(hash)
+	if !ok {
+		return nil, ErrLedgerNotFound
 	}
-	return nil, ErrLedgerNotFound
+	return l, nil
 }
 
 // GetCurrentLedgerIndex returns the current open ledger index
@@ -630,7 +744,7 @@ func (s *Service) AcceptLedger() (uint32, error) {
 	closedLedgerHash := s.openLedger.Hash()
 	s.closedLedger = s.openLedger
 	s.validatedLedger = s.openLedger
-	s.ledgerHistory[closedSeq] = s.openLedger
+	s.addToHistoryLocked(s.openLedger)
 
 	// Standalone already promotes to validated above, so any stashed
 	// validation at this seq is redundant — but drain it so the entry
@@ -651,6 +765,9 @@ func (s *Service) AcceptLedger() (uint32, error) {
 	}
 	s.openLedger = newOpen
 
+	// Re-apply the local transactions the closed ledger did not include
+	s.sweepLocalTxsLocked(s.closedLedger)
+
 	// Build ledger info for callbacks
 	ledgerInfo := &LedgerInfo{
 		Sequence:   closedSeq,
@@ -744,21 +861,11 @@ func (s *Service) fireLedgerClosedHooksLocked(
 
 // getValidatedLedgersRange returns a string representation of validated ledger range
 func (s *Service) getValidatedLedgersRange() string {
-	if len(s.ledgerHistory) == 0 {
+	minSeq, maxSeq, ok := s.historyRangeLocked()
+	if !ok {
 		return "empty"
 	}
 
-	minSeq := uint32(0xFFFFFFFF)
-	maxSeq := uint32(0)
-	for seq := range s.ledgerHistory {
-		if seq < minSeq {
-			minSeq = seq
-		}
-		if seq > maxSeq {
-			maxSeq = seq
-		}
-	}
-
 	if minSeq == maxSeq {
 		return strconv.FormatUint(uint64(minSeq), 10)
 	}
@@ -914,6 +1021,8 @@ func (s *Service) fixMismatchLocked(adopted *ledger.Ledger) {
 		}
 
 		delete(s.ledgerHistory, seq)
+		delete(s.historySizes, l)
+		s.ledgerCache.Remove(seq)
 	}
 
 	// Defense-in-depth: if closedLedger was pointing at one of the
@@ -1051,17 +1160,7 @@ func (s *Service) GetServerInfo() ServerInfo {
 	}
 
 	// Calculate complete ledgers range
-	if len(s.ledgerHistory) > 0 {
-		minSeq := uint32(0xFFFFFFFF)
-		maxSeq := uint32(0)
-		for seq := range s.ledgerHistory {
-			if seq < minSeq {
-				minSeq = seq
-			}
-			if seq > maxSeq {
-				maxSeq = seq
-			}
-		}
+	if minSeq, maxSeq, ok := s.historyRangeLocked(); ok {
 		if minSeq == maxSeq {
 			info.CompleteLedgers = strconv.FormatUint(uint64(minSeq), 10)
 		} else {
@@ -1139,7 +1238,7 @@ func (s *Service) AcceptConsensusResult(parent *ledger.Ledger, txBlobs [][]byte,
 	// ledger detection), reset internal state to build on the correct chain.
 	if parent != nil && parent.Sequence() != s.closedLedger.Sequence() {
 		s.closedLedger = parent
-		s.ledgerHistory[parent.Sequence()] = parent
+		s.addToHistoryLocked(parent)
 		newOpen, err := ledger.NewOpen(parent, closeTime)
 		if err != nil {
 			return 0, fmt.Errorf("failed to create open ledger from parent: %w", err)
@@ -1178,6 +1277,7 @@ func (s *Service) AcceptConsensusResult(parent *ledger.Ledger, txBlobs [][]byte,
 			ReserveIncrement:          reserveIncrement,
 			LedgerSequence:            freshLedger.Sequence(),
 			SkipSignatureVerification: false,
+			SignatureVerified:         s.signatureVerified,
 			NetworkID:                 s.config.NetworkID,
 			Logger:                    s.config.Logger,
 		}
@@ -1331,7 +1431,7 @@ func (s *Service) AcceptConsensusResult(parent *ledger.Ledger, txBlobs [][]byte,
 	closedSeq := s.openLedger.Sequence()
 	closedLedgerHash := s.openLedger.Hash()
 	s.closedLedger = s.openLedger
-	s.ledgerHistory[closedSeq] = s.openLedger
+	s.addToHistoryLocked(s.openLedger)
 
 	// Drain any validation that arrived before this close (validation
 	// tracker leading the consensus close). Fail-safe on expired/mismatch.
@@ -1355,6 +1455,9 @@ func (s *Service) AcceptConsensusResult(parent *ledger.Ledger, txBlobs [][]byte,
 	}
 	s.openLedger = newOpen
 
+	// Re-apply the local transactions the closed ledger did not include
+	s.sweepLocalTxsLocked(s.closedLedger)
+
 	// Fire event hooks
 	ledgerInfo := &LedgerInfo{
 		Sequence:   closedSeq,
@@ -1464,6 +1567,7 @@ func (s *Service) SetValidatedLedger(seq uint32, expectedHash [32]byte) {
 	}
 	_ = l.SetValidated()
 	s.validatedLedger = l
+	s.notifySubmissionsLocked(s.validateLocalTxsLocked())
 
 	// Drain any stashed ledger-accepted event for this hash.
 	// Fire on a goroutine (after releasing the lock) so subscriber
@@ -1846,7 +1950,7 @@ func (s *Service) AdoptLedgerHeader(h *header.LedgerHeader) error {
 	// (typically genesis for a first-time sync) until the
 	// ValidationTracker fires OnLedgerFullyValidated.
 	s.closedLedger = adopted
-	s.ledgerHistory[h.LedgerIndex] = adopted
+	s.addToHistoryLocked(adopted)
 
 	// Create new open ledger on top
 	openLedger, err := ledger.NewOpen(adopted, time.Now())
@@ -1909,7 +2013,7 @@ func (s *Service) ReAdoptLedgerHeader(h *header.LedgerHeader) error {
 	// set after trusted-validation quorum lands. Leaving validatedLedger
 	// alone lets the quorum gate in SetValidatedLedger do its job.
 	s.closedLedger = adopted
-	s.ledgerHistory[h.LedgerIndex] = adopted
+	s.addToHistoryLocked(adopted)
 
 	// Create new open ledger on top
 	openLedger, err := ledger.NewOpen(adopted, time.Now())
@@ -1987,7 +2091,7 @@ func (s *Service) adoptLedgerWithStateLocked(
 	// Same reasoning as ReAdoptLedgerHeader: peer-adopted ledgers advance
 	// closedLedger but not validatedLedger. The quorum gate owns that.
 	s.closedLedger = adopted
-	s.ledgerHistory[h.LedgerIndex] = adopted
+	s.addToHistoryLocked(adopted)
 	s.needsInitialSync = false
 
 	// If a trusted validation for this seq arrived before we got here
@@ -2023,6 +2127,9 @@ func (s *Service) adoptLedgerWithStateLocked(
 	}
 	s.openLedger = openLedger
 
+	// Re-apply the local transactions the adopted ledger did not include
+	s.sweepLocalTxsLocked(adopted)
+
 	// Fire hooks.OnLedgerClosed + hooks.OnTransaction so WebSocket
 	// `ledger` and `transactions` stream subscribers see peer-adopted
 	// ledgers. Without this, the streams silently skip every ledger
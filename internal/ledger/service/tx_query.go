@@ -6,9 +6,9 @@ import (
 
 	addresscodec "github.com/LeJamon/goXRPLd/codec/addresscodec"
 	"github.com/LeJamon/goXRPLd/internal/ledger"
-	"github.com/LeJamon/goXRPLd/internal/ledger/state"
 	"github.com/LeJamon/goXRPLd/internal/tx"
 	"github.com/LeJamon/goXRPLd/keylet"
+	"github.com/LeJamon/goXRPLd/ledger/entry"
 	"github.com/LeJamon/goXRPLd/storage/relationaldb"
 )
 
@@ -34,6 +34,13 @@ type SubmitResult struct {
 
 	// ValidatedLedger is the highest validated ledger sequence
 	ValidatedLedger uint32
+
+	// Hash is the transaction hash, zero if the blob could not be hashed
+	Hash [32]byte
+
+	// Queued indicates the transaction was held to retry in a later
+	// open ledger
+	Queued bool
 }
 
 // SubmitTransaction submits a transaction to the open ledger.
@@ -47,23 +54,8 @@ func (s *Service) SubmitTransaction(transaction tx.Transaction, rawBlob []byte)
 		return nil, ErrNoOpenLedger
 	}
 
-	// Read fee settings from the FeeSettings SLE in the open ledger
-	baseFee, reserveBase, reserveIncrement := readFeesFromLedger(s.openLedger)
-
-	// Create engine config from current state
-	engineConfig := tx.EngineConfig{
-		BaseFee:                   baseFee,
-		ReserveBase:               reserveBase,
-		ReserveIncrement:          reserveIncrement,
-		LedgerSequence:            s.openLedger.Sequence(),
-		SkipSignatureVerification: s.config.Standalone, // Skip signatures in standalone mode
-		OpenLedger:                true,                // Live submission: check fee adequacy
-		NetworkID:                 s.config.NetworkID,
-		Logger:                    s.config.Logger,
-	}
-
 	// Create engine with the open ledger as the view
-	engine := tx.NewEngine(s.openLedger, engineConfig)
+	engine := tx.NewEngine(s.openLedger, s.openEngineConfigLocked())
 
 	// Apply the transaction
 	applyResult := engine.Apply(transaction)
@@ -82,36 +74,65 @@ func (s *Service) SubmitTransaction(transaction tx.Transaction, rawBlob []byte)
 		result.ValidatedLedger = s.validatedLedger.Sequence()
 	}
 
+	if rawBlob == nil {
+		return result, nil
+	}
+
+	common := transaction.GetCommon()
+
+	// Decode account address to raw 20-byte AccountID
+	var accountID [20]byte
+	_, accountBytes, err := addresscodec.DecodeClassicAddressToAccountID(common.Account)
+	if err == nil && len(accountBytes) == 20 {
+		copy(accountID[:], accountBytes)
+	}
+
+	// Compute transaction hash from the original signed blob.
+	// We set raw bytes so ComputeTransactionHash uses the exact signed bytes
+	// rather than re-serializing (which can produce a different blob/hash).
+	transaction.SetRawBytes(rawBlob)
+	txHash, hashErr := tx.ComputeTransactionHash(transaction)
+	if hashErr != nil {
+		return result, nil
+	}
+	result.Hash = txHash
+
+	ptx := pendingTx{
+		txBlob:   rawBlob,
+		hash:     txHash,
+		account:  accountID,
+		sequence: common.SeqProxy(),
+	}
+
 	// Track successfully applied transactions for canonical re-ordering at AcceptLedger.
 	// Reference: rippled accumulates applied txs for CanonicalTXSet reapply.
-	if applyResult.Applied && rawBlob != nil {
-		common := transaction.GetCommon()
-
-		// Decode account address to raw 20-byte AccountID
-		var accountID [20]byte
-		_, accountBytes, err := addresscodec.DecodeClassicAddressToAccountID(common.Account)
-		if err == nil && len(accountBytes) == 20 {
-			copy(accountID[:], accountBytes)
-		}
-
-		// Compute transaction hash from the original signed blob.
-		// We set raw bytes so ComputeTransactionHash uses the exact signed bytes
-		// rather than re-serializing (which can produce a different blob/hash).
-		transaction.SetRawBytes(rawBlob)
-		txHash, hashErr := tx.ComputeTransactionHash(transaction)
-		if hashErr == nil {
-			s.pendingTxs = append(s.pendingTxs, pendingTx{
-				txBlob:   rawBlob,
-				hash:     txHash,
-				account:  accountID,
-				sequence: common.SeqProxy(),
-			})
-		}
+	if applyResult.Applied {
+		s.pendingTxs = append(s.pendingTxs, ptx)
 	}
 
+	result.Queued = s.trackSubmissionLocked(common, ptx, applyResult.Result, applyResult.Applied) == SubmissionQueued
+
 	return result, nil
 }
 
+// openEngineConfigLocked returns the engine configuration for applying
+// submitted transactions to the open ledger. Caller must hold s.mu.
+func (s *Service) openEngineConfigLocked() tx.EngineConfig {
+	// Read fee settings from the FeeSettings SLE in the open ledger
+	baseFee, reserveBase, reserveIncrement := readFeesFromLedger(s.openLedger)
+
+	return tx.EngineConfig{
+		BaseFee:                   baseFee,
+		ReserveBase:               reserveBase,
+		ReserveIncrement:          reserveIncrement,
+		LedgerSequence:            s.openLedger.Sequence(),
+		SkipSignatureVerification: s.config.Standalone, // Skip signatures in standalone mode
+		OpenLedger:                true,                // Live submission: check fee adequacy
+		NetworkID:                 s.config.NetworkID,
+		Logger:                    s.config.Logger,
+	}
+}
+
 // readFeesFromLedger reads fee settings from the FeeSettings SLE in the given
 // ledger. It supports both the modern XRPFees format (BaseFeeDrops /
 // ReserveBaseDrops / ReserveIncrementDrops) and the legacy format (BaseFee /
@@ -134,12 +155,12 @@ func readFeesFromLedger(l *ledger.Ledger) (baseFee, reserveBase, reserveIncremen
 		return defaultBaseFee, defaultReserveBase, defaultReserveIncrement
 	}
 
-	feeSettings, err := state.ParseFeeSettings(data)
-	if err != nil {
+	var feeSettings entry.FeeSettings
+	if err := feeSettings.UnmarshalBinary(data); err != nil {
 		return defaultBaseFee, defaultReserveBase, defaultReserveIncrement
 	}
 
-	return feeSettings.GetBaseFee(), feeSettings.GetReserveBase(), feeSettings.GetReserveIncrement()
+	return uint64(feeSettings.GetBaseFee()), uint64(feeSettings.GetReserveBase()), uint64(feeSettings.GetReserveIncrement())
 }
 
 // GetCurrentFees returns the current fee settings read from the FeeSettings
@@ -188,17 +209,17 @@ type TransactionResult struct {
 
 // GetTransaction retrieves a transaction by its hash
 func (s *Service) GetTransaction(txHash [32]byte) (*TransactionResult, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	// Look up which ledger contains this transaction
+	s.mu.RLock()
 	ledgerSeq, found := s.txIndex[txHash]
+	txIndex := s.txPositionIndex[txHash]
+	s.mu.RUnlock()
 	if !found {
 		return nil, errors.New("transaction not found")
 	}
 
 	// Get the ledger
-	l, ok := s.ledgerHistory[ledgerSeq]
+	l, ok := s.ledgerBySeq(ledgerSeq)
 	if !ok {
 		return nil, errors.New("ledger not found")
 	}
@@ -217,7 +238,7 @@ func (s *Service) GetTransaction(txHash [32]byte) (*TransactionResult, error) {
 		LedgerIndex: ledgerSeq,
 		LedgerHash:  l.Hash(),
 		Validated:   l.IsValidated(),
-		TxIndex:     s.txPositionIndex[txHash],
+		TxIndex:     txIndex,
 	}, nil
 }
 
@@ -318,8 +339,9 @@ type AccountTransaction struct {
 	Meta        []byte   `json:"meta,omitempty"`
 }
 
-// GetAccountTransactions retrieves transaction history for an account
-func (s *Service) GetAccountTransactions(account string, ledgerMin, ledgerMax int64, limit uint32, marker *relationaldb.AccountTxMarker, forward bool) (*AccountTxResult, error) {
+// GetAccountTransactions retrieves transaction history for an account,
+// optionally narrowed by filter
+func (s *Service) GetAccountTransactions(account string, ledgerMin, ledgerMax int64, limit uint32, marker *relationaldb.AccountTxMarker, forward bool, filter relationaldb.AccountTxFilter) (*AccountTxResult, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -370,6 +392,7 @@ func (s *Service) GetAccountTransactions(account string, ledgerMin, ledgerMax in
 		MaxLedger: maxLedger,
 		Marker:    marker,
 		Limit:     limit,
+		Filter:    filter,
 	}
 
 	var txResult *relationaldb.AccountTxResult
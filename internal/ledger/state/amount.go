@@ -1,6 +1,7 @@
 package state
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -350,6 +351,18 @@ func NewMPTAmountWithIssuanceID(value int64, issuer, mptIssuanceID string) Amoun
 	}
 }
 
+// MPTIssuerFromID returns the r-address of the issuer embedded in a hex
+// MPTokenIssuanceID (sequence(4) || issuer AccountID(20)).
+func MPTIssuerFromID(mptIssuanceID string) (string, bool) {
+	id, err := hex.DecodeString(mptIssuanceID)
+	if err != nil || len(id) != 24 {
+		return "", false
+	}
+	var issuer [20]byte
+	copy(issuer[:], id[4:])
+	return EncodeAccountIDSafe(issuer), true
+}
+
 // MPTRaw returns the raw int64 value for MPT amounts, if available.
 // Returns (value, true) for MPT amounts, (0, false) for other amounts.
 func (a Amount) MPTRaw() (int64, bool) {
@@ -0,0 +1,173 @@
+package peermanagement
+
+import (
+	"fmt"
+	"time"
+)
+
+// ResourceWarningThreshold is the default balance cut-off for the
+// `blacklist` RPC when no threshold is supplied. Matches rippled's
+// Resource::warningThreshold (Tuning.h) used by Logic::getJson().
+const ResourceWarningThreshold = 5000
+
+// evictedTTL bounds how long an evicted endpoint stays visible in
+// ResourceJSON. Mirrors rippled's Resource::secondsUntilExpiration —
+// the window during which a dropped consumer's entry is retained.
+const evictedTTL = 300 * time.Second
+
+// evictedEntry is one remembered bad-data eviction.
+type evictedEntry struct {
+	balance int64
+	inbound bool
+	at      time.Time
+}
+
+// recordEviction remembers a peer dropped for bad data, keyed by its
+// remote IP (rippled keys resource consumers by address, not port).
+func (o *Overlay) recordEviction(peer *Peer, balance uint32) {
+	key := peerResourceKey(peer)
+	if key == "" {
+		return
+	}
+	now := o.clock()
+
+	o.evictedMu.Lock()
+	defer o.evictedMu.Unlock()
+	if o.evicted == nil {
+		o.evicted = make(map[string]evictedEntry)
+	}
+	for k, e := range o.evicted {
+		if now.Sub(e.at) >= evictedTTL {
+			delete(o.evicted, k)
+		}
+	}
+	o.evicted[key] = evictedEntry{balance: int64(balance), inbound: peer.Inbound(), at: now}
+}
+
+// ResourceJSON backs the `blacklist` admin RPC. It reports every
+// connected peer whose bad-data balance is at or above threshold plus
+// every endpoint evicted within evictedTTL, keyed by remote address.
+// Entry shape follows rippled Resource::Logic::getJson ("local",
+// "remote", "type"), where "remote" is the balance cluster members
+// gossiped for the address; the threshold applies to the sum.
+// Evicted endpoints additionally carry "dropped": true.
+func (o *Overlay) ResourceJSON(threshold int) map[string]any {
+	out := map[string]any{}
+	now := o.clock()
+
+	o.peersMu.RLock()
+	for _, peer := range o.peers {
+		key := peerResourceKey(peer)
+		balance := peer.Load()
+		remote := o.gossipBalance(key, now)
+		if balance+remote < int64(threshold) {
+			continue
+		}
+		out[key] = map[string]any{
+			"local":  balance,
+			"remote": remote,
+			"type":   consumerType(peer.Inbound()),
+		}
+	}
+	o.peersMu.RUnlock()
+
+	o.evictedMu.Lock()
+	for k, e := range o.evicted {
+		if now.Sub(e.at) >= evictedTTL {
+			delete(o.evicted, k)
+			continue
+		}
+		if e.balance < int64(threshold) {
+			continue
+		}
+		if _, live := out[k]; live {
+			continue
+		}
+		out[k] = map[string]any{
+			"local":   e.balance,
+			"remote":  0,
+			"type":    consumerType(e.inbound),
+			"dropped": true,
+		}
+	}
+	o.evictedMu.Unlock()
+
+	return out
+}
+
+// clock returns the configured clock, falling back to time.Now for
+// overlays built without New (tests seed bare &Overlay{} values).
+func (o *Overlay) clock() time.Time {
+	if o.cfg.Clock != nil {
+		return o.cfg.Clock()
+	}
+	return time.Now()
+}
+
+func consumerType(inbound bool) string {
+	if inbound {
+		return "inbound"
+	}
+	return "outbound"
+}
+
+// Reservations returns the peer reservation table.
+func (o *Overlay) Reservations() *ReservationTable {
+	return o.discovery.Reservations()
+}
+
+// AddReservation validates nodeKey as a base58 node public key and
+// inserts or replaces its reservation. Returns the previous entry in
+// the peer_reservations_* JSON shape, or nil when the key was new.
+// Mirrors rippled doPeerReservationsAdd (Reservations.cpp).
+func (o *Overlay) AddReservation(nodeKey, description string) (map[string]any, error) {
+	tok, err := ParsePublicKeyToken(nodeKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	prev, err := o.discovery.Reservations().InsertOrAssign(PeerReservation{
+		NodeID:      tok.Encode(),
+		Description: description,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservationJSON(prev), nil
+}
+
+// DeleteReservation removes the reservation for nodeKey, returning the
+// removed entry in JSON form or nil when none existed.
+func (o *Overlay) DeleteReservation(nodeKey string) (map[string]any, error) {
+	tok, err := ParsePublicKeyToken(nodeKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	prev, err := o.discovery.Reservations().Erase(tok.Encode())
+	if err != nil {
+		return nil, err
+	}
+	return reservationJSON(prev), nil
+}
+
+// ReservationsJSON lists every reservation ordered by node key.
+func (o *Overlay) ReservationsJSON() []map[string]any {
+	list := o.discovery.Reservations().List()
+	out := make([]map[string]any, 0, len(list))
+	for i := range list {
+		out = append(out, reservationJSON(&list[i]))
+	}
+	return out
+}
+
+// reservationJSON renders a reservation like rippled's
+// PeerReservation::toJson: "node" always, "description" only when set.
+func reservationJSON(r *PeerReservation) map[string]any {
+	if r == nil {
+		return nil
+	}
+	entry := map[string]any{"node": r.NodeID}
+	if r.Description != "" {
+		entry["description"] = r.Description
+	}
+	return entry
+}
@@ -0,0 +1,202 @@
+package peermanagement
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memReservationStore is an in-memory ReservationStore that can be told
+// to fail writes, so tests can check the table stays consistent with
+// its backing store.
+type memReservationStore struct {
+	rows    map[string]PeerReservation
+	failErr error
+}
+
+func newMemReservationStore() *memReservationStore {
+	return &memReservationStore{rows: make(map[string]PeerReservation)}
+}
+
+func (s *memReservationStore) LoadReservations() ([]PeerReservation, error) {
+	out := make([]PeerReservation, 0, len(s.rows))
+	for _, r := range s.rows {
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (s *memReservationStore) SaveReservation(r PeerReservation) error {
+	if s.failErr != nil {
+		return s.failErr
+	}
+	s.rows[r.NodeID] = r
+	return nil
+}
+
+func (s *memReservationStore) DeleteReservation(nodeID string) error {
+	if s.failErr != nil {
+		return s.failErr
+	}
+	delete(s.rows, nodeID)
+	return nil
+}
+
+func testNodeKey(t *testing.T) string {
+	t.Helper()
+	ident, err := NewIdentity()
+	require.NoError(t, err)
+	return ident.EncodedPublicKey()
+}
+
+// TestReservationTable_InsertEraseRoundTrip checks the rippled
+// insert_or_assign / erase contract: the previous entry is returned on
+// replace and delete, nil when there was none, and every mutation is
+// mirrored into the store.
+func TestReservationTable_InsertEraseRoundTrip(t *testing.T) {
+	store := newMemReservationStore()
+	table := NewReservationTable(store)
+
+	prev, err := table.InsertOrAssign(PeerReservation{NodeID: "nB", Description: "first"})
+	require.NoError(t, err)
+	assert.Nil(t, prev, "new reservation has no previous entry")
+
+	prev, err = table.InsertOrAssign(PeerReservation{NodeID: "nB", Description: "second"})
+	require.NoError(t, err)
+	require.NotNil(t, prev)
+	assert.Equal(t, "first", prev.Description)
+	assert.Equal(t, "second", store.rows["nB"].Description)
+
+	_, err = table.InsertOrAssign(PeerReservation{NodeID: "nA"})
+	require.NoError(t, err)
+	list := table.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, "nA", list[0].NodeID, "List is ordered by node key")
+
+	prev, err = table.Erase("nB")
+	require.NoError(t, err)
+	require.NotNil(t, prev)
+	assert.Equal(t, "second", prev.Description)
+	assert.False(t, table.Contains("nB"))
+	assert.NotContains(t, store.rows, "nB")
+
+	prev, err = table.Erase("nB")
+	require.NoError(t, err)
+	assert.Nil(t, prev, "erasing an absent key returns nil")
+}
+
+// TestReservationTable_StoreFailureLeavesTableUnchanged guards the
+// write-through ordering: a failed persist must not leave a reservation
+// that would vanish on restart.
+func TestReservationTable_StoreFailureLeavesTableUnchanged(t *testing.T) {
+	store := newMemReservationStore()
+	table := NewReservationTable(store)
+	_, err := table.InsertOrAssign(PeerReservation{NodeID: "nA"})
+	require.NoError(t, err)
+
+	store.failErr = errors.New("disk full")
+	_, err = table.InsertOrAssign(PeerReservation{NodeID: "nB"})
+	require.Error(t, err)
+	assert.False(t, table.Contains("nB"))
+
+	_, err = table.Erase("nA")
+	require.Error(t, err)
+	assert.True(t, table.Contains("nA"))
+}
+
+// TestReservationTable_Load restores reservations from the store, the
+// startup path that makes peer_reservations_add survive a restart.
+func TestReservationTable_Load(t *testing.T) {
+	store := newMemReservationStore()
+	store.rows["nA"] = PeerReservation{NodeID: "nA", Description: "hub"}
+
+	table := NewReservationTable(store)
+	require.NoError(t, table.Load())
+	assert.True(t, table.Contains("nA"))
+}
+
+// TestOverlay_AddReservation_NormalizesAndValidates checks the admin
+// surface: malformed keys wrap ErrInvalidPublicKey, and the JSON shape
+// matches rippled's PeerReservation::toJson.
+func TestOverlay_AddReservation_NormalizesAndValidates(t *testing.T) {
+	o, err := New(WithReservationStore(newMemReservationStore()))
+	require.NoError(t, err)
+
+	_, err = o.AddReservation("not-a-key", "")
+	assert.ErrorIs(t, err, ErrInvalidPublicKey)
+
+	key := testNodeKey(t)
+	prev, err := o.AddReservation(key, "")
+	require.NoError(t, err)
+	assert.Nil(t, prev)
+
+	prev, err = o.AddReservation(key, "hub")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"node": key}, prev,
+		"empty description is omitted from the previous entry")
+
+	assert.Equal(t, []map[string]any{{"node": key, "description": "hub"}}, o.ReservationsJSON())
+
+	prev, err = o.DeleteReservation(key)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"node": key, "description": "hub"}, prev)
+	assert.Empty(t, o.ReservationsJSON())
+}
+
+// TestOverlay_CanAcceptInbound_ReservedPeersDoNotCount verifies that a
+// reserved inbound peer does not consume one of the MaxInbound slots.
+func TestOverlay_CanAcceptInbound_ReservedPeersDoNotCount(t *testing.T) {
+	o, err := New()
+	require.NoError(t, err)
+	o.cfg.MaxInbound = 1
+
+	ident, err := NewIdentity()
+	require.NoError(t, err)
+	peer := NewPeer(PeerID(1), Endpoint{Host: "10.0.0.1", Port: 51235}, true, ident, make(chan Event, 1))
+	tok, err := ParsePublicKeyToken(ident.EncodedPublicKey())
+	require.NoError(t, err)
+	peer.remotePubKey = tok
+	o.peers[peer.ID()] = peer
+
+	assert.False(t, o.canAcceptInbound(), "unreserved peer fills the only inbound slot")
+
+	_, err = o.AddReservation(ident.EncodedPublicKey(), "")
+	require.NoError(t, err)
+	assert.True(t, o.canAcceptInbound(), "reserved peer must not consume an inbound slot")
+}
+
+// TestOverlay_ResourceJSON_ThresholdAndEvicted checks the blacklist
+// view: only peers at or above threshold are listed, and a peer evicted
+// for bad data stays visible (flagged dropped) after disconnect.
+func TestOverlay_ResourceJSON_ThresholdAndEvicted(t *testing.T) {
+	o := &Overlay{
+		peers:  make(map[PeerID]*Peer),
+		events: make(chan Event, 8),
+	}
+
+	quiet := newTestPeer(t, PeerID(1))
+	quiet.endpoint = Endpoint{Host: "10.0.0.1", Port: 51235}
+	offender := newTestPeer(t, PeerID(2))
+	offender.endpoint = Endpoint{Host: "10.0.0.2", Port: 51235}
+	o.peers[quiet.ID()] = quiet
+	o.peers[offender.ID()] = offender
+
+	hitsToEvict := (EvictBadDataThreshold + weightDefaultBadData - 1) / weightDefaultBadData
+	for i := 0; i < hitsToEvict; i++ {
+		offender.IncBadData("unit")
+	}
+
+	out := o.ResourceJSON(ResourceWarningThreshold)
+	require.Contains(t, out, "10.0.0.2")
+	assert.NotContains(t, out, "10.0.0.1", "peers below threshold are not listed")
+
+	o.evictBadDataPeers()
+
+	out = o.ResourceJSON(ResourceWarningThreshold)
+	require.Contains(t, out, "10.0.0.2")
+	entry := out["10.0.0.2"].(map[string]any)
+	assert.Equal(t, true, entry["dropped"])
+	assert.Equal(t, "outbound", entry["type"])
+}
@@ -5,10 +5,10 @@
 // member by the peers RPC.
 //
 // Mirrors rippled's overlay::Cluster (rippled/src/xrpld/overlay/Cluster.h
-// and Cluster.cpp). The rippled-side resource-charge relaxation and
-// raw-relay fast-path that depend on cluster membership are out of
-// scope for this package — we only mirror the membership state and
-// the Cluster::load parser semantics.
+// and Cluster.cpp). This package only mirrors the membership state and
+// the Cluster::load parser semantics; the TMCluster broadcast, cluster
+// fee, resource gossip and trusted relaying that depend on membership
+// live in the overlay.
 package cluster
 
 import (
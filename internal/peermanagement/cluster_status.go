@@ -0,0 +1,288 @@
+package peermanagement
+
+import (
+	"log/slog"
+	"net"
+	"sort"
+	"time"
+
+	addresscodec "github.com/LeJamon/goXRPLd/codec/addresscodec"
+	"github.com/LeJamon/goXRPLd/internal/peermanagement/cluster"
+	"github.com/LeJamon/goXRPLd/internal/peermanagement/message"
+	"github.com/LeJamon/goXRPLd/protocol"
+)
+
+// ClusterInterval is how often a clustered node broadcasts TMCluster
+// to its cluster peers. Matches NetworkOPsImp::setClusterTimer (10s).
+const ClusterInterval = 10 * time.Second
+
+// clusterFeeWindow bounds which member reports feed the cluster fee.
+// PeerImp::onMessage(TMCluster) only counts reports newer than
+// now - 90s when computing the median.
+const clusterFeeWindow = 90 * time.Second
+
+// clusterMaxLedgerAge gates our own load report: processClusterTimer
+// reports the local fee only while the validated ledger is at most
+// four minutes old, and 0 otherwise.
+const clusterMaxLedgerAge = 4 * time.Minute
+
+// minimumGossipBalance is the smallest local balance exported in the
+// TMCluster load_sources list. Resource Tuning.h minimumGossipBalance.
+const minimumGossipBalance = 1000
+
+// gossipExpiration bounds how long one cluster member's imported
+// balances stay in effect. Resource Tuning.h gossipExpirationSeconds.
+const gossipExpiration = 30 * time.Second
+
+// gossipImport is the latest set of load sources received from one
+// cluster member. A newer import from the same origin replaces it,
+// mirroring Resource::Logic::importConsumers.
+type gossipImport struct {
+	items   map[string]int64
+	expires time.Time
+}
+
+// peerResourceKey is the address a peer's resource balance is tracked
+// under. rippled keys consumers by remote IP, not port.
+func peerResourceKey(peer *Peer) string {
+	if ip := peer.RemoteIP(); ip != "" {
+		return ip
+	}
+	return peer.Endpoint().Host
+}
+
+// peerInCluster reports whether the peer handshaked under a node key
+// listed in [cluster_nodes].
+func (o *Overlay) peerInCluster(peer *Peer) bool {
+	if o.cluster == nil {
+		return false
+	}
+	key := peer.RemotePublicKey()
+	if key == nil {
+		return false
+	}
+	_, ok := o.cluster.Member(key.Bytes())
+	return ok
+}
+
+// IsClusterPeer reports whether the connected peer is a cluster member.
+// The consensus router uses it to trust and relay cluster-originated
+// transactions, as PeerImp::handleTransaction does with SF_TRUSTED.
+func (o *Overlay) IsClusterPeer(peerID PeerID) bool {
+	o.peersMu.RLock()
+	peer, ok := o.peers[peerID]
+	o.peersMu.RUnlock()
+	return ok && o.peerInCluster(peer)
+}
+
+// ClusterFee returns the median load fee reported by cluster members
+// within clusterFeeWindow, or 0 when none reported. Mirrors
+// LoadFeeTrack::getClusterFee as set from onMessage(TMCluster).
+func (o *Overlay) ClusterFee() uint32 {
+	return o.clusterFee.Load()
+}
+
+// localLoadFee is the fee we report for ourselves in TMCluster. goXRPL
+// has no local load tracker yet, so a synced node reports the
+// unloaded reference fee.
+func (o *Overlay) localLoadFee() uint32 {
+	provider := o.validLedgerProviderSnapshot()
+	if provider == nil {
+		return 0
+	}
+	_, age, ok := provider()
+	if !ok || age > clusterMaxLedgerAge {
+		return 0
+	}
+	return protocol.LoadFeeBase
+}
+
+// sendClusterStatus refreshes our own registry entry and sends the
+// whole registry plus our exported resource balances to every
+// connected cluster peer. Mirrors NetworkOPsImp::processClusterTimer;
+// a no-op when [cluster_nodes] is empty.
+func (o *Overlay) sendClusterStatus(now time.Time) {
+	if o.cluster == nil || o.cluster.Size() == 0 || o.identity == nil {
+		return
+	}
+	now = now.Truncate(time.Second)
+	if !o.cluster.Update(o.identity.PublicKey(), "", o.localLoadFee(), now) {
+		return
+	}
+
+	msg := &message.Cluster{}
+	o.cluster.ForEach(func(m cluster.Member) {
+		encoded, err := addresscodec.EncodeNodePublicKey(m.Identity)
+		if err != nil {
+			return
+		}
+		msg.ClusterNodes = append(msg.ClusterNodes, message.ClusterNode{
+			PublicKey:  encoded,
+			ReportTime: toNetClock(m.ReportTime),
+			NodeLoad:   m.LoadFee,
+			NodeName:   m.Name,
+		})
+	})
+	msg.LoadSources = o.exportGossip()
+
+	encoded, err := message.Encode(msg)
+	if err != nil {
+		slog.Warn("Failed to encode cluster status", "t", "Overlay", "err", err)
+		return
+	}
+	frame, err := message.BuildWireMessage(message.TypeCluster, encoded)
+	if err != nil {
+		return
+	}
+
+	o.peersMu.RLock()
+	defer o.peersMu.RUnlock()
+	for _, peer := range o.peers {
+		if peer.State() == PeerStateConnected && o.peerInCluster(peer) {
+			peer.Send(frame)
+		}
+	}
+}
+
+// exportGossip lists every connected peer whose balance is at least
+// minimumGossipBalance, like Resource::Logic::exportConsumers.
+func (o *Overlay) exportGossip() []message.LoadSource {
+	o.peersMu.RLock()
+	defer o.peersMu.RUnlock()
+
+	var out []message.LoadSource
+	for _, peer := range o.peers {
+		balance := peer.Load()
+		if balance < minimumGossipBalance {
+			continue
+		}
+		out = append(out, message.LoadSource{
+			Name: peerResourceKey(peer),
+			Cost: uint32(balance),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// handleCluster applies an inbound TMCluster. Only cluster members may
+// send one; anyone else is charged and ignored. Member reports update
+// the registry, load sources replace the sender's previous gossip, and
+// the cluster fee is recomputed. Mirrors PeerImp::onMessage(TMCluster).
+func (o *Overlay) handleCluster(evt Event) {
+	o.peersMu.RLock()
+	peer, ok := o.peers[evt.PeerID]
+	o.peersMu.RUnlock()
+	if !ok {
+		return
+	}
+	if !o.peerInCluster(peer) {
+		o.IncPeerBadData(evt.PeerID, "cluster-unknown")
+		return
+	}
+
+	decoded, err := message.Decode(message.TypeCluster, evt.Payload)
+	if err != nil {
+		o.IncPeerBadData(evt.PeerID, "cluster-decode")
+		return
+	}
+	msg, ok := decoded.(*message.Cluster)
+	if !ok {
+		return
+	}
+
+	for _, node := range msg.ClusterNodes {
+		identity, err := addresscodec.DecodeNodePublicKey(node.PublicKey)
+		if err != nil {
+			continue
+		}
+		o.cluster.Update(identity, node.NodeName, node.NodeLoad, fromNetClock(node.ReportTime))
+	}
+
+	now := o.clock()
+	if len(msg.LoadSources) > 0 {
+		o.importGossip(peer.RemotePublicKey().Encode(), msg.LoadSources, now)
+	}
+	o.updateClusterFee(now)
+}
+
+// importGossip replaces the balances last imported from origin.
+// Entries whose name is not an IP address (with or without port) are
+// dropped, as rippled drops items that parse to an empty endpoint.
+func (o *Overlay) importGossip(origin string, sources []message.LoadSource, now time.Time) {
+	items := make(map[string]int64, len(sources))
+	for _, src := range sources {
+		host := src.Name
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			continue
+		}
+		items[ip.String()] += int64(src.Cost)
+	}
+
+	o.gossipMu.Lock()
+	defer o.gossipMu.Unlock()
+	if o.gossip == nil {
+		o.gossip = make(map[string]gossipImport)
+	}
+	o.gossip[origin] = gossipImport{items: items, expires: now.Add(gossipExpiration)}
+}
+
+// gossipBalance sums the balances cluster members reported for addr,
+// ignoring imports older than gossipExpiration. This is the "remote"
+// half of rippled's Entry::balance.
+func (o *Overlay) gossipBalance(addr string, now time.Time) int64 {
+	if addr == "" {
+		return 0
+	}
+	o.gossipMu.Lock()
+	defer o.gossipMu.Unlock()
+
+	var total int64
+	for origin, imp := range o.gossip {
+		if !now.Before(imp.expires) {
+			delete(o.gossip, origin)
+			continue
+		}
+		total += imp.items[addr]
+	}
+	return total
+}
+
+// updateClusterFee sets the cluster fee to the median of the load fees
+// reported within clusterFeeWindow (the upper median for an even
+// count, as std::nth_element at size/2 picks), or 0 when none are
+// recent enough.
+func (o *Overlay) updateClusterFee(now time.Time) {
+	threshold := now.Add(-clusterFeeWindow)
+	var fees []uint32
+	o.cluster.ForEach(func(m cluster.Member) {
+		if !m.ReportTime.Before(threshold) {
+			fees = append(fees, m.LoadFee)
+		}
+	})
+
+	var fee uint32
+	if len(fees) > 0 {
+		sort.Slice(fees, func(i, j int) bool { return fees[i] < fees[j] })
+		fee = fees[len(fees)/2]
+	}
+	o.clusterFee.Store(fee)
+}
+
+// toNetClock converts a wall-clock time to XRPL network-clock seconds.
+// The zero time maps to 0.
+func toNetClock(t time.Time) uint32 {
+	if t.IsZero() || t.Unix() < XRPLEpochOffset {
+		return 0
+	}
+	return uint32(t.Unix() - XRPLEpochOffset)
+}
+
+// fromNetClock is the inverse of toNetClock.
+func fromNetClock(s uint32) time.Time {
+	return time.Unix(int64(s)+XRPLEpochOffset, 0)
+}
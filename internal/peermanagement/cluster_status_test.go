@@ -0,0 +1,182 @@
+package peermanagement
+
+import (
+	"testing"
+	"time"
+
+	"github.com/LeJamon/goXRPLd/internal/peermanagement/message"
+	"github.com/LeJamon/goXRPLd/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clusterFixture is an overlay whose [cluster_nodes] lists one peer
+// identity, with that member and one outsider connected.
+type clusterFixture struct {
+	o        *Overlay
+	now      time.Time
+	member   *Peer
+	outsider *Peer
+}
+
+func newClusterFixture(t *testing.T) *clusterFixture {
+	t.Helper()
+	f := &clusterFixture{now: time.Unix(1_700_000_000, 0)}
+
+	memberIdent, err := NewIdentity()
+	require.NoError(t, err)
+	o, err := New(
+		WithClusterNodes(memberIdent.EncodedPublicKey()+" alpha"),
+		WithClock(func() time.Time { return f.now }),
+	)
+	require.NoError(t, err)
+	f.o = o
+
+	f.member = newTestPeer(t, PeerID(1))
+	f.member.endpoint = Endpoint{Host: "10.0.0.1", Port: 51235}
+	tok, err := ParsePublicKeyToken(memberIdent.EncodedPublicKey())
+	require.NoError(t, err)
+	f.member.remotePubKey = tok
+	f.member.setState(PeerStateConnected)
+
+	f.outsider = newTestPeer(t, PeerID(2))
+	f.outsider.endpoint = Endpoint{Host: "10.0.0.2", Port: 51235}
+	f.outsider.remotePubKey, err = ParsePublicKeyToken(testNodeKey(t))
+	require.NoError(t, err)
+	f.outsider.setState(PeerStateConnected)
+
+	o.peers[f.member.ID()] = f.member
+	o.peers[f.outsider.ID()] = f.outsider
+	return f
+}
+
+func (f *clusterFixture) clusterEvent(t *testing.T, from PeerID, msg *message.Cluster) Event {
+	t.Helper()
+	payload, err := message.Encode(msg)
+	require.NoError(t, err)
+	return Event{PeerID: from, MessageType: uint16(message.TypeCluster), Payload: payload}
+}
+
+// TestOverlay_HandleCluster_UpdatesRegistryGossipAndFee feeds a
+// TMCluster from a member: node reports land in the registry, the
+// cluster fee becomes the median of recent reports, and load sources
+// become the address's remote balance.
+func TestOverlay_HandleCluster_UpdatesRegistryGossipAndFee(t *testing.T) {
+	f := newClusterFixture(t)
+	member := f.member.RemotePublicKey().Encode()
+	other := testNodeKey(t)
+	third := testNodeKey(t)
+	report := toNetClock(f.now)
+
+	f.o.onMessageReceived(f.clusterEvent(t, f.member.ID(), &message.Cluster{
+		ClusterNodes: []message.ClusterNode{
+			{PublicKey: member, ReportTime: report, NodeLoad: 256},
+			{PublicKey: other, ReportTime: report, NodeLoad: 1024, NodeName: "beta"},
+			{PublicKey: third, ReportTime: toNetClock(f.now.Add(-2 * clusterFeeWindow)), NodeLoad: 9000},
+			{PublicKey: "garbage", ReportTime: report, NodeLoad: 1},
+		},
+		LoadSources: []message.LoadSource{
+			{Name: "10.0.0.2", Cost: 30000},
+			{Name: "203.0.113.7:51235", Cost: 1500},
+			{Name: "not-an-ip", Cost: 99999},
+		},
+	}))
+
+	assert.Equal(t, 3, f.o.cluster.Size(), "garbage key is skipped; the stale member is still recorded")
+	assert.Equal(t, uint32(1024), f.o.ClusterFee(), "median of the two recent reports (upper of an even count)")
+	assert.Equal(t, "beta", f.o.ClusterJSON()[other].(map[string]any)["tag"])
+
+	assert.Equal(t, int64(30000), f.o.gossipBalance("10.0.0.2", f.now))
+	assert.Equal(t, int64(1500), f.o.gossipBalance("203.0.113.7", f.now), "port is stripped")
+	assert.Zero(t, f.o.gossipBalance("10.0.0.2", f.now.Add(gossipExpiration)), "imports expire")
+}
+
+// TestOverlay_HandleCluster_FromNonMemberIsCharged mirrors rippled's
+// "unknown cluster" charge: only cluster members may send TMCluster.
+func TestOverlay_HandleCluster_FromNonMemberIsCharged(t *testing.T) {
+	f := newClusterFixture(t)
+	f.o.onMessageReceived(f.clusterEvent(t, f.outsider.ID(), &message.Cluster{
+		ClusterNodes: []message.ClusterNode{{PublicKey: testNodeKey(t), ReportTime: 1, NodeLoad: 512}},
+	}))
+
+	assert.Positive(t, f.outsider.Load())
+	assert.Equal(t, 1, f.o.cluster.Size(), "registry untouched")
+	assert.Zero(t, f.o.ClusterFee())
+}
+
+// TestOverlay_EvictBadDataPeers_ClusterAndGossip checks the resource
+// side of cluster mode: a cluster peer is never evicted however high
+// its balance, and gossip from a member pushes an outsider over the
+// drop threshold and keeps it from reconnecting.
+func TestOverlay_EvictBadDataPeers_ClusterAndGossip(t *testing.T) {
+	f := newClusterFixture(t)
+	f.member.badDataBalance.Store(EvictBadDataThreshold * 2)
+	f.outsider.badDataBalance.Store(EvictBadDataThreshold / 2)
+
+	f.o.evictBadDataPeers()
+	assert.True(t, f.o.IsClusterPeer(f.member.ID()))
+	assert.Contains(t, f.o.peers, f.outsider.ID())
+
+	f.o.importGossip("origin", []message.LoadSource{{Name: "10.0.0.2", Cost: EvictBadDataThreshold}}, f.now)
+	out := f.o.ResourceJSON(ResourceWarningThreshold)
+	require.Contains(t, out, "10.0.0.2")
+	assert.Equal(t, int64(EvictBadDataThreshold), out["10.0.0.2"].(map[string]any)["remote"])
+
+	f.o.evictBadDataPeers()
+	assert.NotContains(t, f.o.peers, f.outsider.ID())
+	assert.Contains(t, f.o.peers, f.member.ID(), "cluster peers are never evicted")
+
+	again := NewPeer(PeerID(3), Endpoint{Host: "10.0.0.2", Port: 40000}, true, f.o.identity, make(chan Event, 1))
+	assert.False(t, f.o.admitInbound(again, ""), "gossiped offender is refused at handoff")
+}
+
+// TestOverlay_SendClusterStatus_OnlyToMembers checks the periodic
+// broadcast: our own report joins the registry, and the frame carries
+// every member plus exported balances but reaches cluster peers only.
+func TestOverlay_SendClusterStatus_OnlyToMembers(t *testing.T) {
+	f := newClusterFixture(t)
+	f.outsider.badDataBalance.Store(minimumGossipBalance)
+	f.o.SetValidLedgerProvider(func() (uint32, time.Duration, bool) { return 10, time.Second, true })
+
+	f.o.sendClusterStatus(f.now)
+
+	var frame []byte
+	select {
+	case frame = <-f.member.send:
+	default:
+		t.Fatal("cluster member did not receive TMCluster")
+	}
+	select {
+	case <-f.outsider.send:
+		t.Fatal("TMCluster must not reach non-cluster peers")
+	default:
+	}
+
+	decoded, err := message.Decode(message.TypeCluster, frame[message.HeaderSizeUncompressed:])
+	require.NoError(t, err)
+	msg := decoded.(*message.Cluster)
+
+	require.Len(t, msg.ClusterNodes, 2)
+	self := f.o.identity.EncodedPublicKey()
+	var selfNode *message.ClusterNode
+	for i := range msg.ClusterNodes {
+		if msg.ClusterNodes[i].PublicKey == self {
+			selfNode = &msg.ClusterNodes[i]
+		}
+	}
+	require.NotNil(t, selfNode)
+	assert.Equal(t, uint32(protocol.LoadFeeBase), selfNode.NodeLoad)
+	assert.Equal(t, toNetClock(f.now), selfNode.ReportTime)
+	assert.Equal(t, []message.LoadSource{{Name: "10.0.0.2", Cost: minimumGossipBalance}}, msg.LoadSources)
+
+	select {
+	case <-f.member.send:
+	default:
+	}
+	f.o.sendClusterStatus(f.now)
+	select {
+	case <-f.member.send:
+		t.Fatal("a report with an unchanged time is not resent")
+	default:
+	}
+}
@@ -41,12 +41,18 @@ type Config struct {
 	BootstrapPeers []string
 	FixedPeers     []string
 
-	// Privacy
-	PrivateMode bool // Don't share our address with peers
+	// Privacy. PrivateMode ([peer_private]) keeps our endpoint out of
+	// mtENDPOINTS gossip and restricts connections, in both
+	// directions, to fixed and reserved peers.
+	PrivateMode bool
 
 	// Storage
 	DataDir string // For boot cache persistence
 
+	// ReservationStore persists peer_reservations_* changes. Nil keeps
+	// reservations in memory only.
+	ReservationStore ReservationStore
+
 	// Timeouts
 	ConnectTimeout   time.Duration
 	HandshakeTimeout time.Duration
@@ -162,10 +168,14 @@ func WithNetworkID(id uint32) Option {
 	}
 }
 
-// WithMaxPeers sets the maximum total number of peers.
+// WithMaxPeers sets the maximum total number of peers and derives the
+// inbound/outbound budgets from it via SlotBudgets, as rippled does for
+// [peers_max]. Under PrivateMode the inbound budget is zero. A later
+// WithMaxInbound/WithMaxOutbound overrides the derived value.
 func WithMaxPeers(n int) Option {
 	return func(c *Config) {
 		c.MaxPeers = n
+		c.MaxInbound, c.MaxOutbound = SlotBudgets(n, !c.PrivateMode)
 	}
 }
 
@@ -190,17 +200,24 @@ func WithBootstrapPeers(peers ...string) Option {
 	}
 }
 
-// WithFixedPeers sets peers that should always be connected.
+// WithFixedPeers sets peers that should always be connected. Fixed
+// peers are redialled with backoff, bypass the slot budgets and are
+// the only peers a PrivateMode node talks to.
 func WithFixedPeers(peers ...string) Option {
 	return func(c *Config) {
 		c.FixedPeers = peers
 	}
 }
 
-// WithPrivateMode enables private mode (don't share our address).
+// WithPrivateMode enables private mode: our address is not gossiped and
+// only fixed/reserved peers are connected. It zeroes the inbound budget
+// whether it comes before or after WithMaxPeers.
 func WithPrivateMode(enabled bool) Option {
 	return func(c *Config) {
 		c.PrivateMode = enabled
+		if enabled {
+			c.MaxInbound = 0
+		}
 	}
 }
 
@@ -211,6 +228,15 @@ func WithDataDir(path string) Option {
 	}
 }
 
+// WithReservationStore sets the persistence backend for the peer
+// reservation table. Reservations are loaded from it when discovery
+// starts.
+func WithReservationStore(store ReservationStore) Option {
+	return func(c *Config) {
+		c.ReservationStore = store
+	}
+}
+
 // WithConnectTimeout sets the connection timeout.
 func WithConnectTimeout(d time.Duration) Option {
 	return func(c *Config) {
@@ -3,22 +3,26 @@ package peermanagement
 import (
 	"context"
 	"encoding/json"
+	"log/slog"
 	"math/rand"
 	"net"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 )
 
 // Discovery constants.
 const (
-	DefaultBootCacheFile   = "peerfinder.cache"
-	MaxCachedEndpoints     = 1000
-	CacheEntryTTL          = 7 * 24 * time.Hour
-	RecentEndpointTTL      = 5 * time.Minute
-	MaxHops                = 3
-	DefaultReservationFile = "peer_reservations.json"
+	DefaultBootCacheFile = "peerfinder.cache"
+	MaxCachedEndpoints   = 1000
+	CacheEntryTTL        = 7 * 24 * time.Hour
+	RecentEndpointTTL    = 5 * time.Minute
+
+	// MaxHops is the furthest gossiped endpoint we store or relay
+	// (rippled PeerFinder Tuning::maxHops).
+	MaxHops = 6
 )
 
 // SlotState represents the connection state of a peer slot.
@@ -63,6 +67,11 @@ type Slot struct {
 
 	createdAt   time.Time
 	activatedAt time.Time
+
+	// mtENDPOINTS pacing, guarded by Discovery.mu (rippled
+	// SlotImp::whenSendEndpoints / whenAcceptEndpoints).
+	whenSendEndpoints   time.Time
+	whenAcceptEndpoints time.Time
 }
 
 // NewInboundSlot creates a new slot for an inbound connection.
@@ -349,30 +358,61 @@ func (bc *BootCache) GetEndpoints(limit int) []*CachedEndpoint {
 	return entries
 }
 
-// PeerReservation represents a reserved peer slot.
+// PeerReservation represents a reserved peer slot. NodeID is the
+// base58-encoded node public key ("n...") of the reserved peer.
 type PeerReservation struct {
 	NodeID      string `json:"node_id"`
 	Description string `json:"description,omitempty"`
 }
 
-// ReservationTable manages peer reservations.
+// ReservationStore persists peer reservations across restarts. The
+// production implementation adapts relationaldb.PeerReservationRepository
+// (see internal/consensus/adaptor); this package stays free of storage
+// imports. Mirrors rippled's PeerReservationTable backing onto the
+// PeerReservations wallet-DB table.
+type ReservationStore interface {
+	LoadReservations() ([]PeerReservation, error)
+	SaveReservation(r PeerReservation) error
+	DeleteReservation(nodeID string) error
+}
+
+// ReservationTable manages peer reservations. A reserved peer bypasses
+// the inbound slot limit and does not consume a slot once connected,
+// matching rippled's PeerFinder treatment of reserved slots
+// (PeerFinder Counts::add skips reserved slots).
 type ReservationTable struct {
 	mu           sync.RWMutex
 	reservations map[string]*PeerReservation
-	filePath     string
-	dirty        bool
+	store        ReservationStore
 }
 
-// NewReservationTable creates a new reservation table.
-func NewReservationTable(dataDir string) *ReservationTable {
-	var filePath string
-	if dataDir != "" {
-		filePath = filepath.Join(dataDir, DefaultReservationFile)
-	}
+// NewReservationTable creates a new reservation table. store may be nil,
+// in which case reservations live in memory only.
+func NewReservationTable(store ReservationStore) *ReservationTable {
 	return &ReservationTable{
 		reservations: make(map[string]*PeerReservation),
-		filePath:     filePath,
+		store:        store,
+	}
+}
+
+// Load replaces the in-memory table with the contents of the store.
+// No-op without a store.
+func (t *ReservationTable) Load() error {
+	if t.store == nil {
+		return nil
+	}
+	list, err := t.store.LoadReservations()
+	if err != nil {
+		return err
 	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reservations = make(map[string]*PeerReservation, len(list))
+	for i := range list {
+		r := list[i]
+		t.reservations[r.NodeID] = &r
+	}
+	return nil
 }
 
 // Contains returns true if the node has a reservation.
@@ -383,12 +423,54 @@ func (t *ReservationTable) Contains(nodeID string) bool {
 	return exists
 }
 
-// Insert adds a reservation.
-func (t *ReservationTable) Insert(r *PeerReservation) {
+// InsertOrAssign adds a reservation or replaces the description of an
+// existing one, returning the previous entry (nil when new). The store
+// is written before the in-memory table so a persistence failure leaves
+// both unchanged. Mirrors rippled PeerReservationTable::insert_or_assign.
+func (t *ReservationTable) InsertOrAssign(r PeerReservation) (*PeerReservation, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.store != nil {
+		if err := t.store.SaveReservation(r); err != nil {
+			return nil, err
+		}
+	}
+	prev := t.reservations[r.NodeID]
+	t.reservations[r.NodeID] = &r
+	return prev, nil
+}
+
+// Erase removes a reservation, returning the removed entry (nil when
+// none existed). Mirrors rippled PeerReservationTable::erase.
+func (t *ReservationTable) Erase(nodeID string) (*PeerReservation, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
-	t.reservations[r.NodeID] = r
-	t.dirty = true
+
+	prev, exists := t.reservations[nodeID]
+	if !exists {
+		return nil, nil
+	}
+	if t.store != nil {
+		if err := t.store.DeleteReservation(nodeID); err != nil {
+			return nil, err
+		}
+	}
+	delete(t.reservations, nodeID)
+	return prev, nil
+}
+
+// List returns a copy of every reservation ordered by NodeID.
+func (t *ReservationTable) List() []PeerReservation {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	out := make([]PeerReservation, 0, len(t.reservations))
+	for _, r := range t.reservations {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].NodeID < out[j].NodeID })
+	return out
 }
 
 // DiscoveredPeer stores information about a discovered peer.
@@ -409,8 +491,10 @@ type Discovery struct {
 
 	peers       map[string]*DiscoveredPeer
 	connected   map[PeerID]*DiscoveredPeer
-	slots       map[string]*Slot
-	fixedPeers  map[string]bool
+	slots       map[PeerID]*Slot
+	fixed       map[string]*fixedPeer
+	connecting  map[string]bool
+	livecache   *Livecache
 	bootCache   *BootCache
 	reservation *ReservationTable
 
@@ -422,22 +506,24 @@ type Discovery struct {
 // NewDiscovery creates a new Discovery instance.
 func NewDiscovery(cfg *Config, events chan<- Event) *Discovery {
 	d := &Discovery{
-		cfg:        *cfg,
-		peers:      make(map[string]*DiscoveredPeer),
-		connected:  make(map[PeerID]*DiscoveredPeer),
-		slots:      make(map[string]*Slot),
-		fixedPeers: make(map[string]bool),
-		events:     events,
-		closeCh:    make(chan struct{}),
+		cfg:         *cfg,
+		peers:       make(map[string]*DiscoveredPeer),
+		connected:   make(map[PeerID]*DiscoveredPeer),
+		slots:       make(map[PeerID]*Slot),
+		fixed:       make(map[string]*fixedPeer),
+		connecting:  make(map[string]bool),
+		livecache:   NewLivecache(),
+		reservation: NewReservationTable(cfg.ReservationStore),
+		events:      events,
+		closeCh:     make(chan struct{}),
 	}
 
 	for _, addr := range cfg.FixedPeers {
-		d.fixedPeers[addr] = true
+		d.fixed[addr] = &fixedPeer{host: endpointHost(addr)}
 	}
 
 	if cfg.DataDir != "" {
 		d.bootCache = NewBootCache(cfg.DataDir)
-		d.reservation = NewReservationTable(cfg.DataDir)
 	}
 
 	return d
@@ -449,6 +535,12 @@ func (d *Discovery) Start(ctx context.Context) error {
 		d.bootCache.Load()
 	}
 
+	// A store failure is not fatal: the node still runs, reservations
+	// added via RPC simply start from an empty table.
+	if err := d.reservation.Load(); err != nil {
+		slog.Warn("Failed to load peer reservations", "t", "Discovery", "err", err)
+	}
+
 	for _, addr := range d.cfg.BootstrapPeers {
 		d.AddPeer(addr, 0, 0)
 	}
@@ -477,7 +569,10 @@ func (d *Discovery) Stop() {
 func (d *Discovery) AddPeer(address string, hops uint32, source PeerID) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
+	d.addPeerLocked(address, hops, source)
+}
 
+func (d *Discovery) addPeerLocked(address string, hops uint32, source PeerID) {
 	if existing, exists := d.peers[address]; exists {
 		if hops < existing.Hops {
 			existing.Hops = hops
@@ -530,11 +625,30 @@ func (d *Discovery) ConnectedCount() int {
 	return len(d.connected)
 }
 
+// Reservations returns the peer reservation table. Always non-nil.
+func (d *Discovery) Reservations() *ReservationTable {
+	return d.reservation
+}
+
+// IsReserved reports whether the node public key (base58) holds a
+// reservation.
+func (d *Discovery) IsReserved(nodeID string) bool {
+	return nodeID != "" && d.reservation.Contains(nodeID)
+}
+
 // NeedsMorePeers returns true if we should connect to more peers.
+// Fixed peers are dialled outside the outbound budget and do not count
+// against it (rippled PeerFinder Counts tallies fixed slots apart).
 func (d *Discovery) NeedsMorePeers() bool {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
-	return len(d.connected) < d.cfg.MaxOutbound
+	n := 0
+	for _, peer := range d.connected {
+		if _, fixed := d.fixed[peer.Address]; !fixed {
+			n++
+		}
+	}
+	return n < d.cfg.MaxOutbound
 }
 
 // SelectPeersToConnect returns candidate addresses to connect to.
@@ -542,16 +656,22 @@ func (d *Discovery) SelectPeersToConnect(count int) []string {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
+	// Fixed peers follow their own backoff schedule (FixedDue) and
+	// in-flight dials are skipped so a slow handshake is not doubled.
 	var candidates []string
 	for _, peer := range d.peers {
-		if !peer.Connected && peer.Hops <= MaxHops {
-			candidates = append(candidates, peer.Address)
+		if peer.Connected || peer.Hops > MaxHops || d.connecting[peer.Address] {
+			continue
 		}
+		if _, fixed := d.fixed[peer.Address]; fixed {
+			continue
+		}
+		candidates = append(candidates, peer.Address)
 	}
 
 	if d.bootCache != nil {
 		for _, entry := range d.bootCache.GetEndpoints(50) {
-			if _, exists := d.peers[entry.Address]; !exists {
+			if _, exists := d.peers[entry.Address]; !exists && !d.connecting[entry.Address] {
 				candidates = append(candidates, entry.Address)
 			}
 		}
@@ -589,6 +709,8 @@ func (d *Discovery) prune() {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	d.livecache.Expire(time.Now())
+
 	cutoff := time.Now().Add(-1 * time.Hour)
 	for addr, peer := range d.peers {
 		if !peer.Connected && peer.LastSeen.Before(cutoff) {
@@ -564,11 +564,9 @@ func TestFixedPeerHandling(t *testing.T) {
 	d := NewDiscovery(cfg, events)
 
 	// Fixed peers should be tracked
-	d.mu.RLock()
-	fixed1 := d.fixedPeers["65.0.0.1:5"]
-	fixed2 := d.fixedPeers["65.0.0.2:5"]
-	nonFixed := d.fixedPeers["192.168.1.1:51235"]
-	d.mu.RUnlock()
+	fixed1 := d.IsFixed("65.0.0.1:5")
+	fixed2 := d.IsFixed("65.0.0.2:5")
+	nonFixed := d.IsFixed("192.168.1.1:51235")
 
 	if !fixed1 {
 		t.Error("65.0.0.1:5 should be a fixed peer")
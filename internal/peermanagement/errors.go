@@ -85,6 +85,24 @@ func NewEndpointError(endpoint Endpoint, op string, err error) *PeerError {
 	}
 }
 
+// RedirectError reports an HTTP 503 handshake rejection from a peer
+// with no free slots, carrying the alternate endpoints it suggested
+// (rippled OverlayImpl::makeRedirectResponse "peer-ips").
+type RedirectError struct {
+	Endpoints []string
+}
+
+// Error returns the error message.
+func (e *RedirectError) Error() string {
+	return fmt.Sprintf("peer has no free slots, redirected to %d endpoints", len(e.Endpoints))
+}
+
+// Unwrap returns ErrSlotUnavailable so callers can match the refusal
+// without caring about the redirect list.
+func (e *RedirectError) Unwrap() error {
+	return ErrSlotUnavailable
+}
+
 // HandshakeError provides detailed handshake failure information.
 type HandshakeError struct {
 	Endpoint Endpoint
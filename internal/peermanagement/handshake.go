@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
@@ -39,6 +40,7 @@ const (
 	HeaderRemoteIP         = "Remote-IP"
 	HeaderLocalIP          = "Local-IP"
 	HeaderServer           = "Server"
+	HeaderRemoteAddress    = "Remote-Address"
 )
 
 const (
@@ -151,6 +153,66 @@ func BuildHandshakeResponse(id *Identity, sharedValue []byte, cfg HandshakeConfi
 	return resp
 }
 
+// maxRedirectBody bounds how much of a 503 body we read when parsing
+// the alternate endpoint list.
+const maxRedirectBody = 64 * 1024
+
+// BuildRedirectResponse builds the HTTP 503 reply sent to an inbound
+// peer we cannot give a slot. The JSON body lists alternate endpoints
+// under "peer-ips" so the peer can route around a full node. Mirrors
+// rippled OverlayImpl::makeRedirectResponse.
+func BuildRedirectResponse(cfg HandshakeConfig, remoteAddr string, peerIPs []string) *http.Response {
+	if peerIPs == nil {
+		peerIPs = []string{}
+	}
+	body, _ := json.Marshal(map[string][]string{"peer-ips": peerIPs})
+
+	resp := &http.Response{
+		StatusCode:    http.StatusServiceUnavailable,
+		Status:        "503 Service Unavailable",
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Close:         true,
+	}
+	if cfg.UserAgent != "" {
+		resp.Header.Set(HeaderServer, cfg.UserAgent)
+	}
+	if remoteAddr != "" {
+		resp.Header.Set(HeaderRemoteAddress, remoteAddr)
+	}
+	resp.Header.Set("Content-Type", "application/json")
+	return resp
+}
+
+// ParseRedirectBody extracts the "peer-ips" list from a 503 handshake
+// body, keeping only entries that parse as host:port. Mirrors the
+// service_unavailable branch of rippled ConnectAttempt::processResponse;
+// a malformed body yields an empty list rather than an error.
+func ParseRedirectBody(r io.Reader) []string {
+	var body struct {
+		PeerIPs []json.RawMessage `json:"peer-ips"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r, maxRedirectBody)).Decode(&body); err != nil {
+		return nil
+	}
+	var out []string
+	for _, raw := range body.PeerIPs {
+		var addr string
+		if err := json.Unmarshal(raw, &addr); err != nil {
+			continue
+		}
+		if _, err := ParseEndpoint(addr); err != nil {
+			continue
+		}
+		out = append(out, addr)
+	}
+	return out
+}
+
 func addHandshakeHeaders(h http.Header, id *Identity, sharedValue []byte, cfg HandshakeConfig) {
 	if cfg.NetworkID > 0 {
 		h.Set(HeaderNetworkID, strconv.FormatUint(uint64(cfg.NetworkID), 10))
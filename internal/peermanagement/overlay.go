@@ -19,6 +19,7 @@ import (
 	"github.com/LeJamon/goXRPLd/internal/peermanagement/cluster"
 	"github.com/LeJamon/goXRPLd/internal/peermanagement/message"
 	"github.com/LeJamon/goXRPLd/internal/peermanagement/peertls"
+	"github.com/LeJamon/goXRPLd/protocol"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -129,6 +130,8 @@ func BadDataWeight(reason string) int {
 		"validation-decode",
 		"validation-parse",
 		"ledger-data-decode",
+		"transaction-decode",
+		"cluster-decode",
 		"squelch-ignored":
 		return weightMalformedReq
 	// A peer that didn't respond or returned benign "no data" — lowest.
@@ -227,6 +230,20 @@ type Overlay struct {
 	// droppedMessages so the two traffic classes can be distinguished.
 	droppedLedgerResponses atomic.Uint64
 
+	// evicted remembers endpoints recently disconnected for bad data so
+	// the `blacklist` RPC can report them after the peer is gone —
+	// rippled's Resource::Manager keeps a dropped consumer's entry until
+	// its balance decays. Bounded by evictedTTL.
+	evicted   map[string]evictedEntry
+	evictedMu sync.Mutex
+
+	// clusterFee is the median load fee reported by cluster members
+	// (LoadFeeTrack::clusterFee); gossip holds the resource balances
+	// each member last exported to us, keyed by its node public key.
+	clusterFee atomic.Uint32
+	gossip     map[string]gossipImport
+	gossipMu   sync.Mutex
+
 	// Network
 	listener net.Listener
 
@@ -426,6 +443,7 @@ func New(opts ...Option) (*Overlay, error) {
 		messages:       make(chan *InboundMessage, 256),
 		relayedIndex:   make(map[[32]byte]*relayedEntry),
 		clockForIndex:  time.Now,
+		evicted:        make(map[string]evictedEntry),
 	}
 
 	// Wire reduce-relay callbacks. The squelch callback constructs and
@@ -590,12 +608,6 @@ func (o *Overlay) handleInbound(ctx context.Context, conn net.Conn) {
 		}
 	}()
 
-	if !o.canAcceptInbound() {
-		slog.Info("Inbound rejected: no slots", "t", "Overlay", "remote", conn.RemoteAddr())
-		conn.Close()
-		return
-	}
-
 	remoteAddr := conn.RemoteAddr().String()
 	endpoint, _ := ParseEndpoint(remoteAddr)
 
@@ -701,6 +713,19 @@ func (o *Overlay) performInboundHandshake(ctx context.Context, peer *Peer, tlsCo
 	peer.remotePubKey = peerPubKey
 	peer.mu.Unlock()
 
+	// Slot admission runs once the peer's identity is known so a
+	// reserved node can bypass the inbound limit — rippled's
+	// PeerFinder::Logic::activate checks reserved/fixed before "full".
+	// A refused peer gets a 503 listing alternate endpoints so it can
+	// route around us (OverlayImpl::makeRedirectResponse).
+	if !o.admitInbound(peer, peerPubKey.Encode()) {
+		slog.Info("Inbound rejected: no slots", "t", "Overlay", "remote", peer.Endpoint().String())
+		redirect := BuildRedirectResponse(hsCfg, peer.Endpoint().String(),
+			o.discovery.RedirectEndpoints(RedirectEndpointCount, peer.Endpoint().Host))
+		_ = redirect.Write(tlsConn)
+		return NewHandshakeError(peer.Endpoint(), "activate", ErrMaxInboundReached)
+	}
+
 	peerRemote := tcpRemoteIP(tlsConn)
 	extras, extraErr := ParseHandshakeExtras(
 		req.Header,
@@ -785,6 +810,7 @@ func (o *Overlay) onPeerConnected(evt Event) {
 	// use ephemeral source ports that aren't connectable.
 	if !evt.Inbound {
 		o.discovery.MarkConnected(evt.Endpoint.String(), evt.PeerID)
+		o.discovery.OnFixedSuccess(evt.Endpoint.String(), o.clock())
 	}
 }
 
@@ -821,6 +847,9 @@ func (o *Overlay) onPeerFailed(evt Event) {
 	if o.discovery.bootCache != nil {
 		o.discovery.bootCache.MarkFailed(evt.Endpoint.String())
 	}
+	if !evt.Inbound {
+		o.discovery.OnFixedFailure(evt.Endpoint.String(), o.clock())
+	}
 }
 
 func (o *Overlay) onMessageReceived(evt Event) {
@@ -858,6 +887,20 @@ func (o *Overlay) onMessageReceived(evt Event) {
 		return
 	}
 
+	// mtENDPOINTS feeds PeerFinder's livecache and never reaches the
+	// router (PeerImp::onMessage(TMEndpoints) → on_endpoints).
+	if msgType == message.TypeEndpoints {
+		o.handleEndpoints(evt)
+		return
+	}
+
+	// mtCLUSTER carries member load reports and resource gossip; it is
+	// consumed here and never relayed (PeerImp::onMessage(TMCluster)).
+	if msgType == message.TypeCluster {
+		o.handleCluster(evt)
+		return
+	}
+
 	// mtSTATUS_CHANGE refreshes Closed-/Previous-Ledger hints
 	// (PeerImp.cpp:1812-1862).
 	if msgType == message.TypeStatusChange {
@@ -1172,7 +1215,9 @@ func (o *Overlay) onLedgerResponse(evt Event) {
 	o.Send(evt.PeerID, evt.Payload)
 }
 
-// discoveryLoop periodically attempts to connect to new peers.
+// discoveryLoop periodically attempts to connect to new peers. Fixed
+// peers are checked every second so their backoff schedule is honoured
+// at rippled's granularity; general autoconnect runs every 10s.
 func (o *Overlay) discoveryLoop(ctx context.Context) error {
 	// Immediate first attempt on startup
 	o.autoconnect(ctx)
@@ -1180,19 +1225,28 @@ func (o *Overlay) discoveryLoop(ctx context.Context) error {
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
 
+	fixedTicker := time.NewTicker(time.Second)
+	defer fixedTicker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-ticker.C:
 			o.autoconnect(ctx)
+		case <-fixedTicker.C:
+			o.connectFixed(ctx)
 		}
 	}
 }
 
-// autoconnect attempts to connect to peers if we need more.
+// autoconnect attempts to connect to peers if we need more. Fixed peers
+// come first and ignore the outbound budget; under PrivateMode nothing
+// else is dialled. Mirrors rippled's PeerFinder::Logic::autoconnect.
 func (o *Overlay) autoconnect(ctx context.Context) {
-	if !o.discovery.NeedsMorePeers() {
+	o.connectFixed(ctx)
+
+	if o.cfg.PrivateMode || !o.discovery.NeedsMorePeers() {
 		return
 	}
 
@@ -1208,15 +1262,129 @@ func (o *Overlay) autoconnect(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		default:
-			go func(a string) {
-				if err := o.Connect(a); err != nil {
-					slog.Info("Peer connection failed", "t", "Overlay", "addr", a, "err", err)
-				} else {
-					slog.Info("Peer connected", "t", "Overlay", "addr", a)
-				}
-			}(addr)
+			o.dial(addr)
+		}
+	}
+}
+
+// connectFixed dials every ips_fixed peer whose backoff has elapsed.
+func (o *Overlay) connectFixed(ctx context.Context) {
+	for _, addr := range o.discovery.FixedDue(o.clock()) {
+		if ctx.Err() != nil {
+			return
+		}
+		if ep, err := ParseEndpoint(addr); err == nil && o.isConnectedTo(ep) {
+			continue
+		}
+		o.dial(addr)
+	}
+}
+
+// dial connects to addr in the background unless a dial to it is
+// already in flight.
+func (o *Overlay) dial(addr string) {
+	if !o.discovery.BeginConnect(addr) {
+		return
+	}
+	go func() {
+		defer o.discovery.EndConnect(addr)
+		if err := o.Connect(addr); err != nil {
+			slog.Info("Peer connection failed", "t", "Overlay", "addr", addr, "err", err)
+		} else {
+			slog.Info("Peer connected", "t", "Overlay", "addr", addr)
+		}
+	}()
+}
+
+// handleEndpoints ingests an mtENDPOINTS message into the livecache.
+// Oversized lists are charged and dropped as rippled's
+// PeerImp::onMessage(TMEndpoints) does for >= 1024 entries.
+func (o *Overlay) handleEndpoints(evt Event) {
+	decoded, err := message.Decode(message.TypeEndpoints, evt.Payload)
+	if err != nil {
+		o.IncPeerBadData(evt.PeerID, "endpoints-malformed")
+		return
+	}
+	eps, ok := decoded.(*message.Endpoints)
+	if !ok {
+		return
+	}
+	if len(eps.EndpointsV2) >= 1024 {
+		o.IncPeerBadData(evt.PeerID, "endpoints-oversize")
+		return
+	}
+
+	o.peersMu.RLock()
+	peer, exists := o.peers[evt.PeerID]
+	o.peersMu.RUnlock()
+	if !exists {
+		return
+	}
+
+	if !o.discovery.OnEndpoints(evt.PeerID, peerHost(peer), eps.EndpointsV2, o.clock()) {
+		slog.Debug("Endpoints ignored: sent too early", "t", "Overlay", "peer", evt.PeerID)
+	}
+}
+
+// sendEndpoints gossips livecache entries (and our own endpoint unless
+// PrivateMode) to every peer whose EndpointsInterval has elapsed.
+// Mirrors rippled's OverlayImpl::sendEndpoints timer step.
+func (o *Overlay) sendEndpoints() {
+	o.peersMu.RLock()
+	peers := make(map[PeerID]*Peer, len(o.peers))
+	hosts := make(map[PeerID]string, len(o.peers))
+	for id, p := range o.peers {
+		peers[id] = p
+		hosts[id] = peerHost(p)
+	}
+	o.peersMu.RUnlock()
+
+	batches := o.discovery.BuildEndpoints(o.clock(), o.selfEndpoint(), hosts)
+	for id, list := range batches {
+		peer := peers[id]
+		if peer == nil {
+			continue
+		}
+		encoded, err := message.Encode(&message.Endpoints{Version: 2, EndpointsV2: list})
+		if err != nil {
+			continue
+		}
+		frame, err := message.BuildWireMessage(message.TypeEndpoints, encoded)
+		if err != nil {
+			continue
 		}
+		if err := peer.Send(frame); err != nil {
+			slog.Debug("Endpoints send failed", "t", "Overlay", "peer", id, "err", err)
+		}
+	}
+}
+
+// selfEndpoint is our own hops-0 entry for mtENDPOINTS, or nil when we
+// must not advertise (PrivateMode, or no listener). The receiver
+// substitutes the address it sees on the socket, so only the port has
+// to be right; PublicIP is used when known.
+func (o *Overlay) selfEndpoint() *message.Endpointv2 {
+	if o.cfg.PrivateMode {
+		return nil
+	}
+	_, port, err := net.SplitHostPort(o.ListenAddr())
+	if err != nil {
+		return nil
+	}
+	host := "0.0.0.0"
+	if o.cfg.PublicIP != nil && !o.cfg.PublicIP.IsUnspecified() {
+		host = o.cfg.PublicIP.String()
+	}
+	return &message.Endpointv2{Endpoint: net.JoinHostPort(host, port), Hops: 0}
+}
+
+// peerHost returns the address a peer is reachable at as seen on the
+// socket, falling back to its dialled endpoint.
+func peerHost(peer *Peer) string {
+	if ip := peer.RemoteIP(); ip != "" {
+		return ip
 	}
+	return peer.Endpoint().Host
 }
 
 // maintenanceLoop performs periodic maintenance tasks.
@@ -1241,6 +1409,11 @@ func (o *Overlay) maintenanceLoop(ctx context.Context) error {
 	idleSweepTicker := time.NewTicker(Idled / 2)
 	defer idleSweepTicker.Stop()
 
+	// clusterTicker drives the TMCluster status broadcast
+	// (NetworkOPsImp::processClusterTimer).
+	clusterTicker := time.NewTicker(ClusterInterval)
+	defer clusterTicker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -1253,6 +1426,8 @@ func (o *Overlay) maintenanceLoop(ctx context.Context) error {
 			if o.relay != nil {
 				o.relay.deleteIdlePeers(now)
 			}
+		case <-clusterTicker.C:
+			o.sendClusterStatus(o.clock())
 		}
 	}
 }
@@ -1260,6 +1435,9 @@ func (o *Overlay) maintenanceLoop(ctx context.Context) error {
 func (o *Overlay) performMaintenance() {
 	// Cleanup expired ledger requests
 	o.ledgerSync.CleanupExpiredRequests()
+	// Endpoint gossip is paced per slot, so a 1s tick only sends to
+	// peers whose EndpointsInterval has elapsed.
+	o.sendEndpoints()
 	// Evict peers that have accumulated enough bad-data events to cross
 	// the threshold. Runs here (not inline in IncBadData) so the
 	// disconnect happens off any hot receive path and so a single tick
@@ -1285,6 +1463,10 @@ func (o *Overlay) decayBadData() {
 // exhaust their balance. Must be safe to call with the peer map locked
 // for read only — we collect offenders under RLock, then disconnect
 // them after releasing the lock to avoid holding it across Close().
+//
+// The balance includes what cluster members gossiped for the peer's
+// address. Cluster peers themselves are never evicted: rippled gives
+// them an unlimited Resource::Consumer.
 func (o *Overlay) evictBadDataPeers() {
 	type offender struct {
 		id    PeerID
@@ -1293,10 +1475,15 @@ func (o *Overlay) evictBadDataPeers() {
 	}
 	var toEvict []offender
 
+	now := o.clock()
 	o.peersMu.RLock()
 	for id, peer := range o.peers {
-		if n := peer.BadDataCount(); n >= EvictBadDataThreshold {
-			toEvict = append(toEvict, offender{id: id, peer: peer, count: n})
+		if o.peerInCluster(peer) {
+			continue
+		}
+		n := int64(peer.BadDataCount()) + o.gossipBalance(peerResourceKey(peer), now)
+		if n >= EvictBadDataThreshold {
+			toEvict = append(toEvict, offender{id: id, peer: peer, count: uint32(n)})
 		}
 	}
 	o.peersMu.RUnlock()
@@ -1313,6 +1500,7 @@ func (o *Overlay) evictBadDataPeers() {
 		// removePeer callback fires; defensively remove here too so a
 		// caller-driven test path (no running goroutine) still sees the
 		// peer gone after this function returns.
+		o.recordEviction(off.peer, off.count)
 		off.peer.Close()
 		o.removePeer(off.id)
 	}
@@ -1370,8 +1558,9 @@ func (o *Overlay) Connect(addr string) error {
 		return ErrAlreadyConnected
 	}
 
-	// Check if we can make more outbound connections
-	if o.outboundCount() >= o.cfg.MaxOutbound {
+	// Check if we can make more outbound connections. Fixed peers
+	// are dialled outside the outbound budget.
+	if !o.discovery.IsFixed(addr) && o.outboundCount() >= o.cfg.MaxOutbound {
 		return ErrMaxPeersReached
 	}
 
@@ -1402,6 +1591,10 @@ func (o *Overlay) Connect(addr string) error {
 	}
 
 	if err := peer.Connect(ctx, cfg); err != nil {
+		var redirect *RedirectError
+		if errors.As(err, &redirect) {
+			o.discovery.OnRedirects(addr, redirect.Endpoints)
+		}
 		o.events <- Event{
 			Type:     EventPeerFailed,
 			PeerID:   peerID,
@@ -1454,6 +1647,21 @@ func (o *Overlay) Broadcast(msg []byte) error {
 	return nil
 }
 
+// BroadcastCount sends a message to every connected peer, as Broadcast
+// does, and returns how many of them accepted it into their send queue.
+func (o *Overlay) BroadcastCount(msg []byte) int {
+	o.peersMu.RLock()
+	defer o.peersMu.RUnlock()
+
+	sent := 0
+	for _, peer := range o.peers {
+		if peer.State() == PeerStateConnected && peer.Send(msg) == nil {
+			sent++
+		}
+	}
+	return sent
+}
+
 // BroadcastExcept sends a message to every connected peer except the
 // one identified by exceptPeer. Used for gossip of peer-originated
 // messages that are NOT per-validator (manifests) — the per-validator
@@ -1721,10 +1929,6 @@ func (o *Overlay) PeersJSON() []map[string]any {
 	return out
 }
 
-// clusterFeeRef mirrors rippled's LoadFeeTrack::getLoadBase() default.
-// Replace with a live reference once goXRPL grows a load-fee tracker.
-const clusterFeeRef uint32 = 256
-
 // ClusterJSON returns the top-level cluster object for the `peers`
 // RPC response, mirroring rippled doPeers (Peers.cpp:59-80).
 func (o *Overlay) ClusterJSON() map[string]any {
@@ -1752,8 +1956,8 @@ func (o *Overlay) ClusterJSON() map[string]any {
 		if m.Name != "" {
 			entry["tag"] = m.Name
 		}
-		if m.LoadFee != clusterFeeRef && m.LoadFee != 0 {
-			entry["fee"] = float64(m.LoadFee) / float64(clusterFeeRef)
+		if m.LoadFee != protocol.LoadFeeBase && m.LoadFee != 0 {
+			entry["fee"] = float64(m.LoadFee) / protocol.LoadFeeBase
 		}
 		if !m.ReportTime.IsZero() {
 			age := int64(now.Sub(m.ReportTime).Seconds())
@@ -1818,6 +2022,10 @@ func (o *Overlay) addPeer(peer *Peer) {
 	o.peers[peer.ID()] = peer
 	o.peersMu.Unlock()
 
+	if o.discovery != nil {
+		o.discovery.OpenSlot(peer.ID(), peer.remoteAddr(), peer.Inbound(), o.peerFixed(peer))
+	}
+
 	o.events <- Event{
 		Type:     EventPeerConnected,
 		PeerID:   peer.ID(),
@@ -1833,6 +2041,10 @@ func (o *Overlay) removePeer(peerID PeerID) {
 	delete(o.peers, peerID)
 	o.peersMu.Unlock()
 
+	if o.discovery != nil {
+		o.discovery.CloseSlot(peerID)
+	}
+
 	if exists {
 		o.events <- Event{
 			Type:     EventPeerDisconnected,
@@ -1860,28 +2072,74 @@ func (o *Overlay) isConnectedTo(endpoint Endpoint) bool {
 	return false
 }
 
+// admitInbound decides whether a handshaked inbound peer gets a slot.
+// Reserved and fixed peers always do; under PrivateMode nobody else
+// does; otherwise the inbound budget applies. Mirrors the order of
+// rippled's PeerFinder::Logic::activate. Before any of that, an address
+// whose cluster-gossiped balance already exceeds the drop threshold is
+// refused, as OverlayImpl::onHandoff does via Consumer::disconnect.
+func (o *Overlay) admitInbound(peer *Peer, nodeKey string) bool {
+	if !o.peerInCluster(peer) && o.gossipBalance(peer.Endpoint().Host, o.clock()) >= EvictBadDataThreshold {
+		return false
+	}
+	if o.discovery.IsReserved(nodeKey) || o.peerFixed(peer) {
+		return true
+	}
+	if o.cfg.PrivateMode {
+		return false
+	}
+	return o.canAcceptInbound()
+}
+
 // canAcceptInbound checks if we can accept another inbound connection.
+// Reserved and fixed peers do not occupy a slot, matching rippled's
+// PeerFinder Counts which only tally non-fixed, non-reserved slots as
+// active.
 func (o *Overlay) canAcceptInbound() bool {
 	o.peersMu.RLock()
 	defer o.peersMu.RUnlock()
 
 	count := 0
 	for _, peer := range o.peers {
-		if peer.Inbound() {
+		if peer.Inbound() && !o.peerReserved(peer) && !o.peerFixed(peer) {
 			count++
 		}
 	}
 	return count < o.cfg.MaxInbound
 }
 
-// outboundCount returns the number of outbound connections.
+// peerFixed reports whether the peer is an ips_fixed entry: by dialled
+// address for outbound peers, by host for either direction.
+func (o *Overlay) peerFixed(peer *Peer) bool {
+	if o.discovery == nil {
+		return false
+	}
+	ep := peer.Endpoint()
+	return o.discovery.IsFixed(ep.String()) ||
+		o.discovery.IsFixedHost(ep.Host) ||
+		o.discovery.IsFixedHost(peer.RemoteIP())
+}
+
+// peerReserved reports whether the peer's node key holds a reservation.
+func (o *Overlay) peerReserved(peer *Peer) bool {
+	peer.mu.RLock()
+	key := peer.remotePubKey
+	peer.mu.RUnlock()
+	if key == nil {
+		return false
+	}
+	return o.discovery.IsReserved(key.Encode())
+}
+
+// outboundCount returns the number of outbound connections counted
+// against MaxOutbound; fixed peers are excluded.
 func (o *Overlay) outboundCount() int {
 	o.peersMu.RLock()
 	defer o.peersMu.RUnlock()
 
 	count := 0
 	for _, peer := range o.peers {
-		if !peer.Inbound() {
+		if !peer.Inbound() && !o.peerFixed(peer) {
 			count++
 		}
 	}
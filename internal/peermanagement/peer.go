@@ -156,6 +156,17 @@ func (p *Peer) Endpoint() Endpoint {
 	return p.endpoint
 }
 
+// remoteAddr returns the socket's remote address, or nil before the
+// connection is established.
+func (p *Peer) remoteAddr() net.Addr {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.conn == nil {
+		return nil
+	}
+	return p.conn.RemoteAddr()
+}
+
 // RemoteIP is the IP from the actual TCP connection (not the self-reported header).
 func (p *Peer) RemoteIP() string {
 	p.mu.RLock()
@@ -437,6 +448,14 @@ func (p *Peer) performHandshake(ctx context.Context, tlsConn peertls.PeerConn) e
 		return NewHandshakeError(p.endpoint, "read_response", err)
 	}
 
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		// Full peer: collect its suggested alternates so discovery can
+		// route around it (rippled ConnectAttempt::processResponse).
+		eps := ParseRedirectBody(resp.Body)
+		resp.Body.Close()
+		return NewHandshakeError(p.endpoint, "redirect", &RedirectError{Endpoints: eps})
+	}
+
 	if resp.StatusCode != http.StatusSwitchingProtocols {
 		body := make([]byte, 1024)
 		n, _ := resp.Body.Read(body)
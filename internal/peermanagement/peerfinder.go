@@ -0,0 +1,425 @@
+package peermanagement
+
+import (
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/LeJamon/goXRPLd/internal/peermanagement/message"
+)
+
+// PeerFinder tuning. Values follow rippled's PeerFinder/detail/Tuning.h
+// so a goXRPL node gossips and backs off at the same cadence as the
+// rest of the network.
+const (
+	// outPercent of peers_max is budgeted for outbound slots, never
+	// fewer than minOutCount (Tuning::outPercent / Tuning::minOutCount).
+	outPercent  = 15
+	minOutCount = 10
+
+	// LivecacheTTL is how long a gossiped endpoint stays usable
+	// (Tuning::liveCacheSecondsToLive).
+	LivecacheTTL = 30 * time.Second
+
+	// EndpointsInterval is the minimum spacing between mtENDPOINTS
+	// messages on one slot, in both directions
+	// (Tuning::secondsPerMessage).
+	EndpointsInterval = 151 * time.Second
+
+	// numberOfEndpoints caps one outgoing mtENDPOINTS message
+	// (Tuning::numberOfEndpoints = 2 * maxHops).
+	numberOfEndpoints = 2 * MaxHops
+
+	// numberOfEndpointsMax caps how many entries of an incoming
+	// mtENDPOINTS message are processed (Tuning::numberOfEndpointsMax).
+	numberOfEndpointsMax = 64
+
+	// RedirectEndpointCount caps the alternate endpoints returned in a
+	// 503 handshake rejection (Tuning::redirectEndpointCount).
+	RedirectEndpointCount = 10
+)
+
+// fixedBackoff is the reconnect schedule for ips_fixed peers, indexed by
+// consecutive failure count and clamped at the last entry. Mirrors
+// rippled's Tuning::connectionBackoff used by PeerFinder::Fixed.
+var fixedBackoff = []time.Duration{
+	1 * time.Minute, 1 * time.Minute, 2 * time.Minute, 3 * time.Minute,
+	5 * time.Minute, 8 * time.Minute, 13 * time.Minute, 21 * time.Minute,
+	34 * time.Minute, 55 * time.Minute,
+}
+
+// SlotBudgets splits peers_max into inbound and outbound slot budgets
+// the way rippled's PeerFinder::Config::applyTuning does: outbound gets
+// outPercent of the total (at least minOutCount, at most maxPeers) and
+// inbound gets the remainder. wantIncoming=false (peer_private, or no
+// listening port) zeroes the inbound budget.
+func SlotBudgets(maxPeers int, wantIncoming bool) (inbound, outbound int) {
+	if maxPeers <= 0 {
+		return 0, 0
+	}
+	outbound = maxPeers * outPercent / 100
+	if outbound < minOutCount {
+		outbound = minOutCount
+	}
+	if outbound > maxPeers {
+		outbound = maxPeers
+	}
+	if wantIncoming {
+		inbound = maxPeers - outbound
+	}
+	return inbound, outbound
+}
+
+// fixedPeer is the reconnect state of one ips_fixed entry
+// (rippled PeerFinder::Fixed).
+type fixedPeer struct {
+	host     string
+	failures int
+	when     time.Time
+}
+
+// Livecache holds endpoints learned through mtENDPOINTS gossip. Each
+// entry keeps the lowest hop count seen and expires LivecacheTTL after
+// it was last refreshed. Mirrors rippled's PeerFinder::Livecache.
+type Livecache struct {
+	entries map[string]*livecacheEntry
+}
+
+type livecacheEntry struct {
+	hops    uint32
+	expires time.Time
+}
+
+// NewLivecache creates an empty livecache.
+func NewLivecache() *Livecache {
+	return &Livecache{entries: make(map[string]*livecacheEntry)}
+}
+
+// Insert records addr at the given hop distance from us. A known
+// endpoint is refreshed and keeps the lower hop count.
+func (lc *Livecache) Insert(addr string, hops uint32, now time.Time) {
+	if e, ok := lc.entries[addr]; ok {
+		if hops < e.hops {
+			e.hops = hops
+		}
+		e.expires = now.Add(LivecacheTTL)
+		return
+	}
+	lc.entries[addr] = &livecacheEntry{hops: hops, expires: now.Add(LivecacheTTL)}
+}
+
+// Expire drops entries whose TTL has elapsed.
+func (lc *Livecache) Expire(now time.Time) {
+	for addr, e := range lc.entries {
+		if !now.Before(e.expires) {
+			delete(lc.entries, addr)
+		}
+	}
+}
+
+// Len returns the number of live entries.
+func (lc *Livecache) Len() int {
+	return len(lc.entries)
+}
+
+// Endpoints returns every entry ordered by ascending hop count, shuffled
+// within each hop bucket so repeated handouts spread load the way
+// rippled's Livecache::hops_t::shuffle does.
+func (lc *Livecache) Endpoints() []message.Endpointv2 {
+	out := make([]message.Endpointv2, 0, len(lc.entries))
+	for addr, e := range lc.entries {
+		out = append(out, message.Endpointv2{Endpoint: addr, Hops: e.hops})
+	}
+	rand.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Hops < out[j].Hops })
+	return out
+}
+
+// endpointHost returns the host part of a "host:port" address, or the
+// address unchanged when it carries no port.
+func endpointHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// IsFixed reports whether addr is an ips_fixed entry.
+func (d *Discovery) IsFixed(addr string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	_, ok := d.fixed[addr]
+	return ok
+}
+
+// IsFixedHost reports whether host matches the host of an ips_fixed
+// entry. Inbound connections arrive from an ephemeral port, so rippled's
+// PeerFinder::Logic::fixed(address) matches fixed peers by address only.
+func (d *Discovery) IsFixedHost(host string) bool {
+	if host == "" {
+		return false
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, f := range d.fixed {
+		if f.host == host {
+			return true
+		}
+	}
+	return false
+}
+
+// FixedDue returns the ips_fixed addresses that are neither connected
+// nor mid-dial and whose backoff has elapsed.
+func (d *Discovery) FixedDue(now time.Time) []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var due []string
+	for addr, f := range d.fixed {
+		if now.Before(f.when) || d.connecting[addr] {
+			continue
+		}
+		if p, ok := d.peers[addr]; ok && p.Connected {
+			continue
+		}
+		due = append(due, addr)
+	}
+	sort.Strings(due)
+	return due
+}
+
+// OnFixedSuccess resets the backoff of a fixed peer once its connection
+// is up, so a later disconnect reconnects immediately
+// (rippled Fixed::success).
+func (d *Discovery) OnFixedSuccess(addr string, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if f, ok := d.fixed[addr]; ok {
+		f.failures = 0
+		f.when = now
+	}
+}
+
+// OnFixedFailure schedules the next attempt for a fixed peer after a
+// failed dial (rippled Fixed::failure).
+func (d *Discovery) OnFixedFailure(addr string, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	f, ok := d.fixed[addr]
+	if !ok {
+		return
+	}
+	idx := f.failures
+	if idx >= len(fixedBackoff) {
+		idx = len(fixedBackoff) - 1
+	}
+	f.when = now.Add(fixedBackoff[idx])
+	f.failures++
+}
+
+// BeginConnect marks addr as being dialled. Returns false when a dial
+// to addr is already in flight, so autoconnect never races itself.
+func (d *Discovery) BeginConnect(addr string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.connecting[addr] {
+		return false
+	}
+	d.connecting[addr] = true
+	return true
+}
+
+// EndConnect clears the in-flight marker set by BeginConnect.
+func (d *Discovery) EndConnect(addr string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.connecting, addr)
+}
+
+// OpenSlot registers a handshaked peer for endpoint gossip.
+func (d *Discovery) OpenSlot(id PeerID, remote net.Addr, inbound, fixed bool) {
+	var slot *Slot
+	if inbound {
+		slot = NewInboundSlot(nil, remote, fixed)
+	} else {
+		slot = NewOutboundSlot(remote, fixed)
+	}
+	slot.Activate()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.slots[id] = slot
+}
+
+// CloseSlot forgets a peer's gossip state.
+func (d *Discovery) CloseSlot(id PeerID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.slots, id)
+}
+
+// OnEndpoints ingests an mtENDPOINTS message from peer id. remoteHost is
+// the address seen on the socket; a hops==0 entry describes the sender
+// itself, so only its port is trusted. Entries are stored one hop
+// further away than advertised; those advertised at MaxHops or more are
+// dropped. Returns false
+// when the peer sent the message before EndpointsInterval elapsed,
+// matching rippled's PeerFinder::Logic::on_endpoints which ignores
+// early messages.
+func (d *Discovery) OnEndpoints(id PeerID, remoteHost string, eps []message.Endpointv2, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	slot, ok := d.slots[id]
+	if !ok {
+		return false
+	}
+	if now.Before(slot.whenAcceptEndpoints) {
+		return false
+	}
+	slot.whenAcceptEndpoints = now.Add(EndpointsInterval)
+
+	if len(eps) > numberOfEndpointsMax {
+		eps = eps[:numberOfEndpointsMax]
+	}
+	for _, ep := range eps {
+		if ep.Hops >= MaxHops {
+			continue
+		}
+		host, port, err := net.SplitHostPort(ep.Endpoint)
+		if err != nil {
+			continue
+		}
+		if ep.Hops == 0 {
+			if remoteHost == "" {
+				continue
+			}
+			host = remoteHost
+		}
+		if p, err := strconv.Atoi(port); err != nil || p <= 0 || p > 65535 {
+			continue
+		}
+		addr := net.JoinHostPort(host, port)
+		hops := ep.Hops + 1
+
+		slot.recentEndpoints.Insert(addr, hops)
+		d.livecache.Insert(addr, hops, now)
+		d.addPeerLocked(addr, hops, id)
+		if d.bootCache != nil {
+			if p, err := strconv.Atoi(port); err == nil {
+				d.bootCache.Insert(addr, uint16(p))
+			}
+		}
+	}
+	return true
+}
+
+// BuildEndpoints returns the mtENDPOINTS payload due for each slot at
+// now, keyed by peer. hosts maps each peer to the address seen on its
+// socket; it is passed in rather than looked up so Discovery never
+// takes the overlay's peer lock. self, when non-nil, is our own listening endpoint
+// advertised at hops 0 (omitted under peer_private). Entries the peer
+// already told us about at an equal or lower hop count, and the peer's
+// own address, are filtered out. Mirrors rippled's
+// PeerFinder::Logic::buildEndpointsForPeers.
+func (d *Discovery) BuildEndpoints(now time.Time, self *message.Endpointv2, hosts map[PeerID]string) map[PeerID][]message.Endpointv2 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.livecache.Expire(now)
+	cached := d.livecache.Endpoints()
+
+	out := make(map[PeerID][]message.Endpointv2)
+	for id, slot := range d.slots {
+		if now.Before(slot.whenSendEndpoints) {
+			continue
+		}
+		slot.whenSendEndpoints = now.Add(EndpointsInterval)
+		slot.recentEndpoints.Expire()
+
+		var list []message.Endpointv2
+		if self != nil {
+			list = append(list, *self)
+		}
+		peerHost := hosts[id]
+		for _, ep := range cached {
+			if len(list) >= numberOfEndpoints {
+				break
+			}
+			if ep.Hops > MaxHops {
+				continue
+			}
+			if peerHost != "" && endpointHost(ep.Endpoint) == peerHost {
+				continue
+			}
+			if slot.recentEndpoints.Filter(ep.Endpoint, ep.Hops) {
+				continue
+			}
+			list = append(list, ep)
+		}
+		for _, ep := range list {
+			if ep.Hops > 0 {
+				slot.recentEndpoints.Insert(ep.Endpoint, ep.Hops)
+			}
+		}
+		if len(list) > 0 {
+			out[id] = list
+		}
+	}
+	return out
+}
+
+// RedirectEndpoints returns up to limit alternate endpoints to hand a
+// peer we are refusing for lack of slots, skipping the peer's own host.
+// Livecache entries come first (rippled's PeerFinder::Logic::redirect
+// draws only from the livecache); the boot cache tops the list up so a
+// node that has not yet heard any gossip can still redirect.
+func (d *Discovery) RedirectEndpoints(limit int, excludeHost string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var out []string
+	add := func(addr string) {
+		if len(out) >= limit || seen[addr] || endpointHost(addr) == excludeHost {
+			return
+		}
+		seen[addr] = true
+		out = append(out, addr)
+	}
+
+	d.livecache.Expire(time.Now())
+	for _, ep := range d.livecache.Endpoints() {
+		add(ep.Endpoint)
+	}
+	if d.bootCache != nil && len(out) < limit {
+		for _, entry := range d.bootCache.GetEndpoints(limit * 2) {
+			add(entry.Address)
+		}
+	}
+	return out
+}
+
+// OnRedirects records the alternate endpoints returned by a peer that
+// refused us with HTTP 503, so the next autoconnect pass can try them.
+// Mirrors rippled's PeerFinder::Logic::onRedirects feeding the boot
+// cache.
+func (d *Discovery) OnRedirects(from string, addrs []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, addr := range addrs {
+		ep, err := ParseEndpoint(addr)
+		if err != nil || ep.Port == 0 || ep.Host == endpointHost(from) {
+			continue
+		}
+		d.addPeerLocked(ep.String(), 1, 0)
+		if d.bootCache != nil {
+			d.bootCache.Insert(ep.String(), ep.Port)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package peermanagement
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/LeJamon/goXRPLd/internal/peermanagement/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSlotBudgets checks the peers_max split against rippled's
+// PeerFinder::Config tuning: 15% outbound with a floor of 10.
+func TestSlotBudgets(t *testing.T) {
+	tests := []struct {
+		maxPeers     int
+		wantIncoming bool
+		in, out      int
+	}{
+		{21, true, 11, 10},
+		{100, true, 85, 15},
+		{8, true, 0, 8},
+		{21, false, 0, 10},
+		{0, true, 0, 0},
+	}
+	for _, tt := range tests {
+		in, out := SlotBudgets(tt.maxPeers, tt.wantIncoming)
+		assert.Equal(t, tt.in, in, "inbound for peers_max=%d", tt.maxPeers)
+		assert.Equal(t, tt.out, out, "outbound for peers_max=%d", tt.maxPeers)
+	}
+
+	cfg := DefaultConfig()
+	WithMaxPeers(40)(&cfg)
+	assert.Equal(t, 30, cfg.MaxInbound)
+	assert.Equal(t, 10, cfg.MaxOutbound)
+	require.NoError(t, cfg.Validate())
+
+	// PrivateMode zeroes the inbound budget in either option order.
+	for _, opts := range [][]Option{
+		{WithPrivateMode(true), WithMaxPeers(40)},
+		{WithMaxPeers(40), WithPrivateMode(true)},
+	} {
+		cfg := DefaultConfig()
+		for _, opt := range opts {
+			opt(&cfg)
+		}
+		assert.Equal(t, 0, cfg.MaxInbound)
+		assert.Equal(t, 10, cfg.MaxOutbound)
+		require.NoError(t, cfg.Validate())
+	}
+}
+
+func TestLivecache_InsertKeepsLowestHopsAndExpires(t *testing.T) {
+	lc := NewLivecache()
+	now := time.Unix(1000, 0)
+
+	lc.Insert("10.0.0.1:51235", 3, now)
+	lc.Insert("10.0.0.1:51235", 1, now)
+	lc.Insert("10.0.0.1:51235", 4, now)
+	lc.Insert("10.0.0.2:51235", 2, now)
+
+	eps := lc.Endpoints()
+	require.Len(t, eps, 2)
+	assert.Equal(t, message.Endpointv2{Endpoint: "10.0.0.1:51235", Hops: 1}, eps[0])
+	assert.Equal(t, uint32(2), eps[1].Hops)
+
+	lc.Expire(now.Add(LivecacheTTL - time.Second))
+	assert.Equal(t, 2, lc.Len())
+	lc.Expire(now.Add(LivecacheTTL))
+	assert.Equal(t, 0, lc.Len())
+}
+
+// TestDiscovery_FixedBackoff walks the connectionBackoff schedule and
+// checks a success resets it.
+func TestDiscovery_FixedBackoff(t *testing.T) {
+	cfg := &Config{MaxPeers: 10, MaxOutbound: 10, FixedPeers: []string{"10.0.0.9:51235"}}
+	d := NewDiscovery(cfg, make(chan Event, 1))
+	now := time.Unix(1000, 0)
+
+	assert.Equal(t, []string{"10.0.0.9:51235"}, d.FixedDue(now), "fixed peers are due immediately")
+
+	d.OnFixedFailure("10.0.0.9:51235", now)
+	assert.Empty(t, d.FixedDue(now))
+	assert.NotEmpty(t, d.FixedDue(now.Add(fixedBackoff[0])))
+
+	for i := 0; i < 20; i++ {
+		d.OnFixedFailure("10.0.0.9:51235", now)
+	}
+	assert.Empty(t, d.FixedDue(now.Add(54*time.Minute)), "backoff clamps at the last step")
+	assert.NotEmpty(t, d.FixedDue(now.Add(55*time.Minute)))
+
+	d.OnFixedSuccess("10.0.0.9:51235", now)
+	assert.NotEmpty(t, d.FixedDue(now))
+
+	require.True(t, d.BeginConnect("10.0.0.9:51235"))
+	assert.False(t, d.BeginConnect("10.0.0.9:51235"), "second dial while in flight is refused")
+	assert.Empty(t, d.FixedDue(now), "in-flight fixed peer is not redialled")
+	d.EndConnect("10.0.0.9:51235")
+
+	d.MarkConnected("10.0.0.9:51235", PeerID(1))
+	assert.Empty(t, d.FixedDue(now), "connected fixed peer is not redialled")
+	assert.True(t, d.NeedsMorePeers(), "fixed peers do not consume the outbound budget")
+}
+
+// TestDiscovery_OnEndpoints covers hop handling and pacing of inbound
+// mtENDPOINTS: hops==0 entries take the socket address, everything is
+// stored one hop further, entries at MaxHops or more are dropped, and a
+// second message inside EndpointsInterval is ignored.
+func TestDiscovery_OnEndpoints(t *testing.T) {
+	d := NewDiscovery(&Config{MaxPeers: 10, MaxOutbound: 10}, make(chan Event, 1))
+	now := time.Unix(1000, 0)
+	d.OpenSlot(PeerID(1), nil, false, false)
+
+	ok := d.OnEndpoints(PeerID(1), "203.0.113.5", []message.Endpointv2{
+		{Endpoint: "0.0.0.0:2459", Hops: 0},
+		{Endpoint: "198.51.100.7:51235", Hops: 2},
+		{Endpoint: "198.51.100.8:51235", Hops: MaxHops + 1},
+		{Endpoint: "198.51.100.9:51235", Hops: MaxHops},
+		{Endpoint: "garbage", Hops: 1},
+	}, now)
+	require.True(t, ok)
+
+	byAddr := map[string]uint32{}
+	for _, ep := range d.livecache.Endpoints() {
+		byAddr[ep.Endpoint] = ep.Hops
+	}
+	assert.Equal(t, map[string]uint32{
+		"203.0.113.5:2459":   1,
+		"198.51.100.7:51235": 3,
+	}, byAddr)
+
+	assert.False(t, d.OnEndpoints(PeerID(1), "203.0.113.5", nil, now.Add(time.Second)),
+		"endpoints inside EndpointsInterval are ignored")
+	assert.True(t, d.OnEndpoints(PeerID(1), "203.0.113.5", nil, now.Add(EndpointsInterval)))
+	assert.False(t, d.OnEndpoints(PeerID(2), "203.0.113.6", nil, now), "unknown slot")
+}
+
+// TestDiscovery_BuildEndpoints checks outgoing gossip: our own entry
+// leads, the recipient is not told about itself or about endpoints it
+// just sent us, and each slot is paced by EndpointsInterval.
+func TestDiscovery_BuildEndpoints(t *testing.T) {
+	d := NewDiscovery(&Config{MaxPeers: 10, MaxOutbound: 10}, make(chan Event, 1))
+	now := time.Unix(1000, 0)
+	d.OpenSlot(PeerID(1), nil, false, false)
+	d.OpenSlot(PeerID(2), nil, true, false)
+
+	d.OnEndpoints(PeerID(1), "203.0.113.5", []message.Endpointv2{
+		{Endpoint: "198.51.100.7:51235", Hops: 1},
+	}, now)
+	d.livecache.Insert("203.0.113.9:51235", 1, now)
+
+	self := &message.Endpointv2{Endpoint: "0.0.0.0:51235", Hops: 0}
+	hosts := map[PeerID]string{1: "203.0.113.5", 2: "203.0.113.9"}
+	batches := d.BuildEndpoints(now, self, hosts)
+
+	require.Contains(t, batches, PeerID(1))
+	assert.Equal(t, *self, batches[1][0])
+	assert.NotContains(t, batches[1], message.Endpointv2{Endpoint: "198.51.100.7:51235", Hops: 2},
+		"endpoint learned from peer 1 is not echoed back")
+
+	require.Contains(t, batches, PeerID(2))
+	for _, ep := range batches[2] {
+		assert.NotEqual(t, "203.0.113.9:51235", ep.Endpoint, "peer is not told about itself")
+	}
+	assert.Contains(t, batches[2], message.Endpointv2{Endpoint: "198.51.100.7:51235", Hops: 2})
+
+	assert.Empty(t, d.BuildEndpoints(now.Add(time.Second), self, hosts), "slots are paced")
+
+	private := d.BuildEndpoints(now.Add(EndpointsInterval), nil, hosts)
+	for _, list := range private {
+		for _, ep := range list {
+			assert.NotZero(t, ep.Hops, "no hops-0 self entry without an advertised endpoint")
+		}
+	}
+}
+
+// TestRedirectResponse_RoundTrip writes the 503 an inbound peer gets
+// when we are full and parses it the way an outbound dialer does.
+func TestRedirectResponse_RoundTrip(t *testing.T) {
+	resp := BuildRedirectResponse(HandshakeConfig{UserAgent: "goXRPL/test"}, "203.0.113.5:40000",
+		[]string{"198.51.100.7:51235", "198.51.100.8:2459"})
+
+	var buf bytes.Buffer
+	require.NoError(t, resp.Write(&buf))
+
+	parsed, err := http.ReadResponse(bufio.NewReader(&buf), nil)
+	require.NoError(t, err)
+	defer parsed.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, parsed.StatusCode)
+	assert.Equal(t, "203.0.113.5:40000", parsed.Header.Get(HeaderRemoteAddress))
+	assert.Equal(t, "application/json", parsed.Header.Get("Content-Type"))
+	assert.Equal(t, []string{"198.51.100.7:51235", "198.51.100.8:2459"}, ParseRedirectBody(parsed.Body))
+
+	assert.Equal(t, []string{"198.51.100.7:51235"},
+		ParseRedirectBody(bytes.NewReader([]byte(`{"peer-ips":["198.51.100.7:51235","nope",5]}`))),
+		"malformed entries are skipped")
+	assert.Empty(t, ParseRedirectBody(bytes.NewReader([]byte(`not json`))))
+
+	var target *RedirectError
+	err = NewHandshakeError(Endpoint{Host: "x", Port: 1}, "redirect", &RedirectError{Endpoints: []string{"a:1"}})
+	require.True(t, errors.As(err, &target))
+	assert.ErrorIs(t, err, ErrSlotUnavailable)
+}
+
+// TestDiscovery_Redirects covers both ends of the redirect flow: the
+// list we hand out skips the refused peer, and a list we receive is
+// queued for autoconnect.
+func TestDiscovery_Redirects(t *testing.T) {
+	d := NewDiscovery(&Config{MaxPeers: 10, MaxOutbound: 10}, make(chan Event, 1))
+	now := time.Now()
+	d.livecache.Insert("198.51.100.7:51235", 1, now)
+	d.livecache.Insert("203.0.113.5:51235", 1, now)
+
+	assert.Equal(t, []string{"198.51.100.7:51235"}, d.RedirectEndpoints(RedirectEndpointCount, "203.0.113.5"))
+
+	d.OnRedirects("203.0.113.5:51235", []string{"198.51.100.9:51235", "203.0.113.5:2459", "bad"})
+	candidates := d.SelectPeersToConnect(0)
+	assert.Equal(t, []string{"198.51.100.9:51235"}, candidates)
+}
+
+// TestOverlay_AdmitInbound_FixedAndPrivate verifies slot admission:
+// fixed peers bypass a full inbound budget, and PrivateMode refuses
+// everyone who is neither fixed nor reserved.
+func TestOverlay_AdmitInbound_FixedAndPrivate(t *testing.T) {
+	o, err := New(WithFixedPeers("10.0.0.9:51235"))
+	require.NoError(t, err)
+	o.cfg.MaxInbound = 0
+
+	ident, err := NewIdentity()
+	require.NoError(t, err)
+	fixed := NewPeer(PeerID(1), Endpoint{Host: "10.0.0.9", Port: 40000}, true, ident, make(chan Event, 1))
+	stranger := NewPeer(PeerID(2), Endpoint{Host: "10.0.0.10", Port: 40000}, true, ident, make(chan Event, 1))
+
+	assert.True(t, o.admitInbound(fixed, ""), "fixed peer bypasses a full budget")
+	assert.False(t, o.admitInbound(stranger, ""))
+
+	o.cfg.MaxInbound = 10
+	assert.True(t, o.admitInbound(stranger, ""))
+
+	o.cfg.PrivateMode = true
+	assert.False(t, o.admitInbound(stranger, ""), "private mode refuses non-fixed peers")
+	assert.True(t, o.admitInbound(fixed, ""))
+	assert.Nil(t, o.selfEndpoint(), "private mode never advertises our endpoint")
+}
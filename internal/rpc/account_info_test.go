@@ -92,10 +92,10 @@ func (m *mockLedgerService) GetAccountLines(account string, ledgerIndex string,
 func (m *mockLedgerService) GetAccountOffers(account string, ledgerIndex string, limit uint32) (*types.AccountOffersResult, error) {
 	return nil, errors.New("not implemented")
 }
-func (m *mockLedgerService) GetBookOffers(takerGets, takerPays types.Amount, ledgerIndex string, limit uint32) (*types.BookOffersResult, error) {
+func (m *mockLedgerService) GetBookOffers(takerGets, takerPays types.Amount, domain *[32]byte, ledgerIndex string, limit uint32) (*types.BookOffersResult, error) {
 	return nil, errors.New("not implemented")
 }
-func (m *mockLedgerService) GetAccountTransactions(account string, ledgerMin, ledgerMax int64, limit uint32, marker *types.AccountTxMarker, forward bool) (*types.AccountTxResult, error) {
+func (m *mockLedgerService) GetAccountTransactions(account string, ledgerMin, ledgerMax int64, limit uint32, marker *types.AccountTxMarker, forward bool, filter types.AccountTxFilter) (*types.AccountTxResult, error) {
 	return nil, errors.New("not implemented")
 }
 func (m *mockLedgerService) GetTransactionHistory(startIndex uint32) (*types.TxHistoryResult, error) {
@@ -18,6 +18,7 @@ import (
 type accountTxMock struct {
 	*mockLedgerService
 	getAccountTransactionsFn func(account string, ledgerMin, ledgerMax int64, limit uint32, marker *types.AccountTxMarker, forward bool) (*types.AccountTxResult, error)
+	lastFilter               types.AccountTxFilter
 }
 
 func newAccountTxMock() *accountTxMock {
@@ -26,7 +27,8 @@ func newAccountTxMock() *accountTxMock {
 	}
 }
 
-func (m *accountTxMock) GetAccountTransactions(account string, ledgerMin, ledgerMax int64, limit uint32, marker *types.AccountTxMarker, forward bool) (*types.AccountTxResult, error) {
+func (m *accountTxMock) GetAccountTransactions(account string, ledgerMin, ledgerMax int64, limit uint32, marker *types.AccountTxMarker, forward bool, filter types.AccountTxFilter) (*types.AccountTxResult, error) {
+	m.lastFilter = filter
 	if m.getAccountTransactionsFn != nil {
 		return m.getAccountTransactionsFn(account, ledgerMin, ledgerMax, limit, marker, forward)
 	}
@@ -1368,3 +1370,80 @@ func TestAccountTxAccountPassedToService(t *testing.T) {
 	assert.Equal(t, validAccount, resp["account"],
 		"Response should echo back the account")
 }
+
+// TestAccountTxFilters tests the tx_type and direction parameters
+func TestAccountTxFilters(t *testing.T) {
+	mock := newAccountTxMock()
+	cleanup := setupTestServicesAccountTx(mock)
+	defer cleanup()
+
+	method := &handlers.AccountTxMethod{}
+	validAccount := "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	ctx := &types.RpcContext{
+		Context:    context.Background(),
+		Role:       types.RoleGuest,
+		ApiVersion: types.ApiVersion1,
+	}
+	mock.getAccountTransactionsFn = func(account string, ledgerMin, ledgerMax int64, limit uint32, marker *types.AccountTxMarker, forward bool) (*types.AccountTxResult, error) {
+		return &types.AccountTxResult{
+			Account:      account,
+			Transactions: []types.AccountTransaction{},
+			Marker:       &types.AccountTxMarker{LedgerSeq: 10, TxnSeq: 2},
+			Validated:    true,
+		}, nil
+	}
+
+	t.Run("Filters passed to service and echoed", func(t *testing.T) {
+		params := map[string]interface{}{
+			"account":   validAccount,
+			"tx_type":   "Payment",
+			"direction": "incoming",
+			"marker":    map[string]interface{}{"ledger": 5, "seq": 1},
+		}
+		paramsJSON, err := json.Marshal(params)
+		require.NoError(t, err)
+
+		result, rpcErr := method.Handle(ctx, paramsJSON)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, types.AccountTxFilter{TxType: "Payment", Direction: types.AccountTxIncoming}, mock.lastFilter)
+
+		resp := result.(map[string]interface{})
+		assert.Equal(t, "Payment", resp["tx_type"])
+		assert.Equal(t, "incoming", resp["direction"])
+		assert.NotNil(t, resp["marker"])
+	})
+
+	t.Run("No filter", func(t *testing.T) {
+		paramsJSON, err := json.Marshal(map[string]interface{}{"account": validAccount})
+		require.NoError(t, err)
+
+		result, rpcErr := method.Handle(ctx, paramsJSON)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, types.AccountTxFilter{}, mock.lastFilter)
+
+		resp := result.(map[string]interface{})
+		assert.NotContains(t, resp, "tx_type")
+		assert.NotContains(t, resp, "direction")
+	})
+
+	for _, tc := range []struct {
+		field string
+		value string
+	}{
+		{"tx_type", "NotATransaction"},
+		{"direction", "sideways"},
+	} {
+		t.Run("Invalid "+tc.field, func(t *testing.T) {
+			paramsJSON, err := json.Marshal(map[string]interface{}{
+				"account": validAccount,
+				tc.field:  tc.value,
+			})
+			require.NoError(t, err)
+
+			_, rpcErr := method.Handle(ctx, paramsJSON)
+			require.NotNil(t, rpcErr)
+			assert.Equal(t, types.RpcINVALID_PARAMS, rpcErr.Code)
+			assert.Contains(t, rpcErr.Message, tc.field)
+		})
+	}
+}
@@ -91,6 +91,7 @@ func allGuestMethods() []guestMethodEntry {
 		{"subscribe", &handlers.SubscribeMethod{}},
 		{"unsubscribe", &handlers.UnsubscribeMethod{}},
 		{"owner_info", &handlers.OwnerInfoMethod{}},
+		{"tx_status", &handlers.TxStatusMethod{}},
 		{"simulate", &handlers.SimulateMethod{}},
 		{"json", &handlers.JsonMethod{}},
 		{"channel_verify", &handlers.ChannelVerifyMethod{}},
@@ -174,7 +175,7 @@ func TestAdminMethodCount(t *testing.T) {
 // added to the handlers package but forgotten in this test catalogue.
 // Update the expected count when adding new guest handlers.
 func TestGuestMethodCount(t *testing.T) {
-	const expectedGuestCount = 40
+	const expectedGuestCount = 41
 
 	got := len(allGuestMethods())
 	assert.Equal(t, expectedGuestCount, got,
@@ -204,7 +205,7 @@ func TestAllMethodsCovered(t *testing.T) {
 	// The expected total is the sum of all three role categories.
 	// Every handler struct in the handlers package must appear in exactly
 	// one of: allAdminMethods, allGuestMethods, allUserMethods.
-	const expectedTotal = 28 + 40 + 10 // 78
+	const expectedTotal = 28 + 41 + 10 // 79
 
 	total := len(allAdminMethods()) + len(allGuestMethods()) + len(allUserMethods())
 	assert.Equal(t, expectedTotal, total,
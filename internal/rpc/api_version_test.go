@@ -105,6 +105,7 @@ func allHandlers() map[string]types.MethodHandler {
 		"ledger_cleaner":  &handlers.LedgerCleanerMethod{},
 		"ledger_diff":     &handlers.LedgerDiffMethod{},
 		"tx_reduce_relay": &handlers.TxReduceRelayMethod{},
+		"tx_status":       &handlers.TxStatusMethod{},
 		"connect":         &handlers.ConnectMethod{},
 		"print":           &handlers.PrintMethod{},
 		"validator_info":  &handlers.ValidatorInfoMethod{},
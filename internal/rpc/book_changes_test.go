@@ -453,3 +453,132 @@ func TestBookChangesNilParams(t *testing.T) {
 	require.Nil(t, rpcErr, "Expected no error with nil params")
 	require.NotNil(t, result)
 }
+
+// TestBookChangesDomain checks that offers in a permissioned domain form
+// their own book, reported with its "domain", and that the optional
+// "domain" parameter restricts the report to that domain.
+func TestBookChangesDomain(t *testing.T) {
+	mock := newMockLedgerServiceBC()
+	cleanup := setupTestServicesBC(mock)
+	defer cleanup()
+
+	const domainHex = "9F2D6A8C1B3E5F7092A4C6E8D0B2F4A6C8E0D2B4F6A8C0E2D4B6F8A0C2E4D6B8"
+	usd := func(v string) map[string]interface{} {
+		return map[string]interface{}{"currency": "USD", "issuer": "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", "value": v}
+	}
+	offerNode := func(domain string) map[string]interface{} {
+		final := map[string]interface{}{"TakerGets": "1000", "TakerPays": usd("10")}
+		if domain != "" {
+			final["DomainID"] = domain
+		}
+		return map[string]interface{}{"ModifiedNode": map[string]interface{}{
+			"LedgerEntryType": "Offer",
+			"FinalFields":     final,
+			"PreviousFields":  map[string]interface{}{"TakerGets": "2000", "TakerPays": usd("20")},
+		}}
+	}
+
+	ledger2 := newMockLedgerReaderBC(2)
+	for i, domain := range []string{"", domainHex} {
+		blob, err := json.Marshal(map[string]interface{}{
+			"tx_json": map[string]interface{}{"TransactionType": "Payment"},
+			"meta":    map[string]interface{}{"AffectedNodes": []interface{}{offerNode(domain)}},
+		})
+		require.NoError(t, err)
+		ledger2.txs[[32]byte{byte(i + 1)}] = blob
+	}
+	mock.addLedger(ledger2)
+
+	method := &handlers.BookChangesMethod{}
+	ctx := &types.RpcContext{Context: context.Background(), Role: types.RoleGuest, ApiVersion: types.ApiVersion1}
+	changesFor := func(params map[string]interface{}) []map[string]interface{} {
+		paramsJSON, _ := json.Marshal(params)
+		result, rpcErr := method.Handle(ctx, paramsJSON)
+		require.Nil(t, rpcErr)
+		return result.(map[string]interface{})["changes"].([]map[string]interface{})
+	}
+
+	all := changesFor(map[string]interface{}{"ledger_index": 2})
+	require.Len(t, all, 2, "open and domain books are reported separately")
+	var domains []interface{}
+	for _, c := range all {
+		domains = append(domains, c["domain"])
+	}
+	assert.ElementsMatch(t, []interface{}{nil, domainHex}, domains)
+
+	only := changesFor(map[string]interface{}{"ledger_index": 2, "domain": strings.ToLower(domainHex)})
+	require.Len(t, only, 1)
+	assert.Equal(t, domainHex, only[0]["domain"])
+
+	paramsJSON, _ := json.Marshal(map[string]interface{}{"ledger_index": 2, "domain": "xyz"})
+	_, rpcErr := method.Handle(ctx, paramsJSON)
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, types.RpcDOMAIN_MALFORMED, rpcErr.Code)
+}
+
+// TestBookChangesAMMAndOrder checks that AMM swaps are reported like
+// crossed offers while deposits are not, and that open and close follow
+// the ledger's transaction order.
+func TestBookChangesAMMAndOrder(t *testing.T) {
+	mock := newMockLedgerServiceBC()
+	cleanup := setupTestServicesBC(mock)
+	defer cleanup()
+
+	const (
+		amm    = "rPMh7Pi9ct699iZUTWaytJUoHcJ7cgyziK"
+		issuer = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	)
+	usd := func(v string) map[string]interface{} {
+		return map[string]interface{}{"currency": "USD", "issuer": issuer, "value": v}
+	}
+	// The AMM pool as the AMM's AccountRoot and its USD line, the AMM
+	// being the high account so the low-side balance is negative.
+	pool := func(prevDrops, drops, prevUSD, curUSD string) []interface{} {
+		return []interface{}{
+			map[string]interface{}{"ModifiedNode": map[string]interface{}{
+				"LedgerEntryType": "AccountRoot",
+				"FinalFields":     map[string]interface{}{"Account": amm, "AMMID": "AB", "Balance": drops},
+				"PreviousFields":  map[string]interface{}{"Balance": prevDrops},
+			}},
+			map[string]interface{}{"ModifiedNode": map[string]interface{}{
+				"LedgerEntryType": "RippleState",
+				"FinalFields": map[string]interface{}{
+					"Flags":     float64(0x01000000),
+					"Balance":   usd("-" + curUSD),
+					"LowLimit":  usd("0"),
+					"HighLimit": map[string]interface{}{"currency": "USD", "issuer": amm, "value": "0"},
+				},
+				"PreviousFields": map[string]interface{}{"Balance": usd("-" + prevUSD)},
+			}},
+		}
+	}
+
+	ledger2 := newMockLedgerReaderBC(2)
+	for i, nodes := range [][]interface{}{
+		pool("1000000", "1001000", "100", "90"), // swap 1000 drops in for 10 USD: rate 100
+		pool("1001000", "1003000", "90", "80"),  // swap 2000 drops in for 10 USD: rate 200
+		pool("1003000", "1004000", "80", "85"),  // deposit of both assets
+	} {
+		blob, err := json.Marshal(map[string]interface{}{
+			"tx_json": map[string]interface{}{"TransactionType": "Payment"},
+			"meta":    map[string]interface{}{"TransactionIndex": i, "AffectedNodes": nodes},
+		})
+		require.NoError(t, err)
+		ledger2.txs[[32]byte{byte(10 - i)}] = blob
+	}
+	mock.addLedger(ledger2)
+
+	paramsJSON, _ := json.Marshal(map[string]interface{}{"ledger_index": 2})
+	result, rpcErr := (&handlers.BookChangesMethod{}).Handle(&types.RpcContext{Context: context.Background()}, paramsJSON)
+	require.Nil(t, rpcErr)
+	changes := result.(map[string]interface{})["changes"].([]map[string]interface{})
+	require.Len(t, changes, 1)
+	assert.Equal(t, "XRP_drops", changes[0]["currency_a"])
+	assert.Equal(t, "USD."+issuer, changes[0]["currency_b"])
+	assert.Equal(t, "3000", changes[0]["volume_a"])
+	assert.Equal(t, "20", changes[0]["volume_b"])
+	assert.Equal(t, "100", changes[0]["open"])
+	assert.Equal(t, "200", changes[0]["close"])
+	assert.Equal(t, "200", changes[0]["high"])
+	assert.Equal(t, "100", changes[0]["low"])
+}
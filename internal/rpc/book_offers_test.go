@@ -2,8 +2,10 @@ package rpc
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/LeJamon/goXRPLd/internal/rpc/handlers"
@@ -16,9 +18,11 @@ import (
 type bookOffersMock struct {
 	*mockLedgerService
 	getBookOffersFn func(takerGets, takerPays types.Amount, ledgerIndex string, limit uint32) (*types.BookOffersResult, error)
+	lastDomain      *[32]byte
 }
 
-func (m *bookOffersMock) GetBookOffers(takerGets, takerPays types.Amount, ledgerIndex string, limit uint32) (*types.BookOffersResult, error) {
+func (m *bookOffersMock) GetBookOffers(takerGets, takerPays types.Amount, domain *[32]byte, ledgerIndex string, limit uint32) (*types.BookOffersResult, error) {
+	m.lastDomain = domain
 	if m.getBookOffersFn != nil {
 		return m.getBookOffersFn(takerGets, takerPays, ledgerIndex, limit)
 	}
@@ -985,3 +989,52 @@ func TestBookOffersNilOffersArray(t *testing.T) {
 
 	assert.Contains(t, resp, "offers", "Response must contain offers key even when nil")
 }
+
+// TestBookOffersDomain tests the permissioned DEX "domain" parameter:
+// a well-formed domain is passed to the service, anything else is
+// rpcDOMAIN_MALFORMED. Based on rippled PermissionedDEX_test.cpp.
+func TestBookOffersDomain(t *testing.T) {
+	mock := newBookOffersMock()
+	cleanup := setupBookOffersTestServices(mock)
+	defer cleanup()
+	mock.getBookOffersFn = func(takerGets, takerPays types.Amount, ledgerIndex string, limit uint32) (*types.BookOffersResult, error) {
+		return &types.BookOffersResult{LedgerIndex: 2, Offers: []types.BookOffer{}, Validated: true}, nil
+	}
+
+	method := &handlers.BookOffersMethod{}
+	ctx := &types.RpcContext{
+		Context:    context.Background(),
+		Role:       types.RoleGuest,
+		ApiVersion: types.ApiVersion1,
+	}
+	call := func(domain interface{}) *types.RpcError {
+		params := map[string]interface{}{
+			"taker_pays": map[string]interface{}{"currency": "XRP"},
+			"taker_gets": map[string]interface{}{
+				"currency": "USD",
+				"issuer":   "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+			},
+		}
+		if domain != nil {
+			params["domain"] = domain
+		}
+		paramsJSON, _ := json.Marshal(params)
+		_, rpcErr := method.Handle(ctx, paramsJSON)
+		return rpcErr
+	}
+
+	require.Nil(t, call(nil))
+	assert.Nil(t, mock.lastDomain, "no domain means the open book")
+
+	domainHex := "9F2D6A8C1B3E5F7092A4C6E8D0B2F4A6C8E0D2B4F6A8C0E2D4B6F8A0C2E4D6B8"
+	require.Nil(t, call(domainHex))
+	require.NotNil(t, mock.lastDomain)
+	assert.Equal(t, domainHex, strings.ToUpper(hex.EncodeToString(mock.lastDomain[:])))
+
+	for _, bad := range []interface{}{"not-hex", domainHex[:62], 42} {
+		rpcErr := call(bad)
+		require.NotNil(t, rpcErr, "domain %v", bad)
+		assert.Equal(t, types.RpcDOMAIN_MALFORMED, rpcErr.Code)
+		assert.Equal(t, "domainMalformed", rpcErr.ErrorString)
+	}
+}
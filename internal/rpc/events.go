@@ -31,6 +31,7 @@ type LedgerCloseEvent struct {
 	// Optional fields for API v2+
 	ValidatedHash string `json:"validated_hash,omitempty"` // Hash of the validated ledger (API v2)
 	Validated     bool   `json:"validated,omitempty"`      // Whether this ledger is validated
+	StreamSeq     uint64 `json:"stream_seq,omitempty"`     // Position in the stream, for gap detection
 }
 
 // NewLedgerCloseEvent creates a new LedgerCloseEvent with required fields
@@ -74,7 +75,11 @@ type TransactionEvent struct {
 	Validated           bool            `json:"validated"`                      // Whether tx is in a validated ledger
 	Status              string          `json:"status,omitempty"`               // Status for proposed transactions
 	// Account subscription specific fields
-	Account string `json:"account,omitempty"` // Account that was affected (for account subscriptions)
+	Account   string `json:"account,omitempty"`    // Account that was affected (for account subscriptions)
+	StreamSeq uint64 `json:"stream_seq,omitempty"` // Position in the stream, for gap detection
+	// Replayed marks events sent for subscribe's resume_ledger rather
+	// than live; they carry no StreamSeq.
+	Replayed bool `json:"replayed,omitempty"`
 }
 
 // NewTransactionEvent creates a new TransactionEvent
@@ -124,6 +129,7 @@ type ValidationEvent struct {
 	SigningTime         uint32   `json:"signing_time"`             // When validation was signed
 	ValidatedHash       string   `json:"validated_hash,omitempty"` // Hash of highest validated ledger
 	ValidationPublicKey string   `json:"validation_public_key"`    // Public key used to sign validation
+	StreamSeq           uint64   `json:"stream_seq,omitempty"`     // Position in the stream, for gap detection
 }
 
 // NewValidationEvent creates a new ValidationEvent
@@ -162,6 +168,7 @@ type ServerStatusEvent struct {
 	LoadFactorFeeQueue      int    `json:"load_factor_fee_queue,omitempty"`      // Fee queue load factor
 	LoadFactorServer        int    `json:"load_factor_server,omitempty"`         // Server load factor
 	ServerStatus            string `json:"server_status,omitempty"`              // Current server status
+	StreamSeq               uint64 `json:"stream_seq,omitempty"`                 // Position in the stream, for gap detection
 }
 
 // NewServerStatusEvent creates a new ServerStatusEvent
@@ -176,8 +183,9 @@ func NewServerStatusEvent(loadBase, loadFactor int) *ServerStatusEvent {
 // ConsensusEvent represents consensus phase changes
 // This is sent to subscribers of the "consensus" stream
 type ConsensusEvent struct {
-	Type      string `json:"type"`      // Always "consensusPhase"
-	Consensus string `json:"consensus"` // Current consensus phase (open, establish, accepted)
+	Type      string `json:"type"`                 // Always "consensusPhase"
+	Consensus string `json:"consensus"`            // Current consensus phase (open, establish, accepted)
+	StreamSeq uint64 `json:"stream_seq,omitempty"` // Position in the stream, for gap detection
 }
 
 // NewConsensusEvent creates a new ConsensusEvent
@@ -198,11 +206,12 @@ const (
 // ManifestEvent represents a validator manifest update
 // This is sent to subscribers of the "manifests" stream
 type ManifestEvent struct {
-	Type       string `json:"type"`        // Always "manifestReceived"
-	MasterKey  string `json:"master_key"`  // Master public key
-	Sequence   uint32 `json:"seq"`         // Manifest sequence number
-	Signature  string `json:"signature"`   // Manifest signature
-	SigningKey string `json:"signing_key"` // Ephemeral signing key
+	Type       string `json:"type"`                 // Always "manifestReceived"
+	MasterKey  string `json:"master_key"`           // Master public key
+	Sequence   uint32 `json:"seq"`                  // Manifest sequence number
+	Signature  string `json:"signature"`            // Manifest signature
+	SigningKey string `json:"signing_key"`          // Ephemeral signing key
+	StreamSeq  uint64 `json:"stream_seq,omitempty"` // Position in the stream, for gap detection
 }
 
 // NewManifestEvent creates a new ManifestEvent
@@ -226,6 +235,7 @@ type PeerStatusEvent struct {
 	LedgerIndex    uint32 `json:"ledger_index,omitempty"`     // Ledger index (if relevant)
 	LedgerIndexMax uint32 `json:"ledger_index_max,omitempty"` // Max ledger index peer has
 	LedgerIndexMin uint32 `json:"ledger_index_min,omitempty"` // Min ledger index peer has
+	StreamSeq      uint64 `json:"stream_seq,omitempty"`       // Position in the stream, for gap detection
 }
 
 // Peer status actions
@@ -255,6 +265,7 @@ type OrderBookChangeEvent struct {
 	LedgerTime  uint32          `json:"ledger_time,omitempty"`
 	TakerGets   json.RawMessage `json:"taker_gets,omitempty"` // What the offer provides
 	TakerPays   json.RawMessage `json:"taker_pays,omitempty"` // What the offer requests
+	Domain      string          `json:"domain,omitempty"`     // Permissioned domain of the book
 	// The transaction that caused the change
 	Transaction json.RawMessage `json:"transaction,omitempty"`
 	Meta        json.RawMessage `json:"meta,omitempty"`
@@ -271,6 +282,7 @@ type PathFindEvent struct {
 	DestinationAmount  json.RawMessage   `json:"destination_amount"`  // Amount to deliver
 	FullReply          bool              `json:"full_reply"`          // Whether this is a full reply
 	Alternatives       []PathAlternative `json:"alternatives"`        // Alternative paths found
+	Domain             string            `json:"domain,omitempty"`    // Permissioned domain, if requested
 }
 
 // PathAlternative represents a single path alternative
@@ -301,6 +313,7 @@ type ProposedTransactionEvent struct {
 	Validated           bool            `json:"validated"`             // Always false for proposed
 	Status              string          `json:"status,omitempty"`      // "proposed"
 	Account             string          `json:"account,omitempty"`     // Affected account
+	StreamSeq           uint64          `json:"stream_seq,omitempty"`  // Position in the stream, for gap detection
 }
 
 // NewProposedTransactionEvent creates a new proposed transaction event
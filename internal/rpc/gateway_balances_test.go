@@ -100,10 +100,10 @@ func (m *mockGatewayBalancesLedgerService) GetAccountLines(account string, ledge
 func (m *mockGatewayBalancesLedgerService) GetAccountOffers(account string, ledgerIndex string, limit uint32) (*types.AccountOffersResult, error) {
 	return nil, errors.New("not implemented")
 }
-func (m *mockGatewayBalancesLedgerService) GetBookOffers(takerGets, takerPays types.Amount, ledgerIndex string, limit uint32) (*types.BookOffersResult, error) {
+func (m *mockGatewayBalancesLedgerService) GetBookOffers(takerGets, takerPays types.Amount, domain *[32]byte, ledgerIndex string, limit uint32) (*types.BookOffersResult, error) {
 	return nil, errors.New("not implemented")
 }
-func (m *mockGatewayBalancesLedgerService) GetAccountTransactions(account string, ledgerMin, ledgerMax int64, limit uint32, marker *types.AccountTxMarker, forward bool) (*types.AccountTxResult, error) {
+func (m *mockGatewayBalancesLedgerService) GetAccountTransactions(account string, ledgerMin, ledgerMax int64, limit uint32, marker *types.AccountTxMarker, forward bool, filter types.AccountTxFilter) (*types.AccountTxResult, error) {
 	return nil, errors.New("not implemented")
 }
 func (m *mockGatewayBalancesLedgerService) GetTransactionHistory(startIndex uint32) (*types.TxHistoryResult, error) {
@@ -552,6 +552,32 @@ func TestGatewayBalancesBasic(t *testing.T) {
 		assert.Equal(t, "10", charleyBal["value"])
 	})
 
+	t.Run("MPT issuer returns mpt_obligations and mpt_locked", func(t *testing.T) {
+		mptID := "0000000597E6AD9B36EDCBF9A5E78EF8B36A51DA0D1C7C5D"
+		mock.gatewayBalancesResult = &types.GatewayBalancesResult{
+			Account:        aliceAccount,
+			MPTObligations: map[string]string{mptID: "1000"},
+			MPTLocked:      map[string]string{mptID: "25"},
+			LedgerIndex:    3,
+			Validated:      true,
+		}
+		mock.gatewayBalancesErr = nil
+
+		paramsJSON, err := json.Marshal(map[string]interface{}{"account": aliceAccount})
+		require.NoError(t, err)
+		result, rpcErr := method.Handle(ctx, paramsJSON)
+		require.Nil(t, rpcErr)
+
+		resultJSON, err := json.Marshal(result)
+		require.NoError(t, err)
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(resultJSON, &resp))
+
+		assert.Equal(t, map[string]interface{}{mptID: "1000"}, resp["mpt_obligations"])
+		assert.Equal(t, map[string]interface{}{mptID: "25"}, resp["mpt_locked"])
+		assert.Empty(t, resp["obligations"])
+	})
+
 	// Test for variable not used warning
 	_ = bobAccount
 }
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+)
+
+// AccountMptIssuancesMethod handles the account_mpt_issuances RPC method,
+// which lists the MPT issuances an account created with their supply, in
+// owner directory order. It is a shortcut for account_objects filtered to
+// mpt_issuance, with the mpt_issuance_id of each issuance computed.
+type AccountMptIssuancesMethod struct{ BaseHandler }
+
+func (m *AccountMptIssuancesMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
+	var request struct {
+		types.AccountParam
+		types.LedgerSpecifier
+		Limit  uint32 `json:"limit,omitempty"`
+		Marker string `json:"marker,omitempty"`
+	}
+
+	if err := ParseParams(params, &request); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateAccount(request.Account); err != nil {
+		return nil, err
+	}
+
+	// The marker is the index of the last issuance of the previous page.
+	var marker *[32]byte
+	if request.Marker != "" {
+		var index [32]byte
+		b, err := hex.DecodeString(request.Marker)
+		if err != nil || len(b) != len(index) {
+			return nil, types.RpcErrorInvalidParams("Invalid marker.")
+		}
+		copy(index[:], b)
+		marker = &index
+	}
+
+	mpts, rpcErr := requireMPTQueries()
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	ledgerIndex := "current"
+	if request.LedgerIndex != "" {
+		ledgerIndex = request.LedgerIndex.String()
+	}
+
+	limit := ClampLimit(request.Limit, LimitAccountMPTIssuances, ctx.IsAdmin)
+	result, rpcErr := mpts.GetAccountMPTIssuances(request.Account, ledgerIndex, marker, limit)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	issuances := make([]map[string]interface{}, len(result.Issuances))
+	for i, iss := range result.Issuances {
+		issuance := map[string]interface{}{
+			"mpt_issuance_id":    FormatHash(iss.MPTIssuanceID[:]),
+			"index":              FormatHash(iss.Index[:]),
+			"sequence":           iss.Sequence,
+			"flags":              iss.Flags,
+			"outstanding_amount": iss.OutstandingAmount,
+		}
+		if iss.AssetScale != 0 {
+			issuance["asset_scale"] = iss.AssetScale
+		}
+		if iss.TransferFee != 0 {
+			issuance["transfer_fee"] = iss.TransferFee
+		}
+		if iss.MaximumAmount != nil {
+			issuance["maximum_amount"] = *iss.MaximumAmount
+		}
+		if iss.LockedAmount != nil {
+			issuance["locked_amount"] = *iss.LockedAmount
+		}
+		if iss.MPTokenMetadata != "" {
+			issuance["mptoken_metadata"] = iss.MPTokenMetadata
+		}
+		if iss.DomainID != nil {
+			issuance["domain_id"] = *iss.DomainID
+		}
+		issuances[i] = issuance
+	}
+
+	response := map[string]interface{}{
+		"account":       result.Account,
+		"mpt_issuances": issuances,
+		"ledger_hash":   FormatLedgerHash(result.LedgerHash),
+		"ledger_index":  result.LedgerIndex,
+		"validated":     result.Validated,
+		"limit":         result.Limit,
+	}
+	if result.Marker != nil {
+		response["marker"] = FormatHash(result.Marker[:])
+	}
+	return response, nil
+}
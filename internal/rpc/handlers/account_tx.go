@@ -23,6 +23,8 @@ func (m *AccountTxMethod) Handle(ctx *types.RpcContext, params json.RawMessage)
 		LedgerIndex    string           `json:"ledger_index,omitempty"`
 		Binary         bool             `json:"binary,omitempty"`
 		Forward        bool             `json:"forward,omitempty"`
+		TxType         string           `json:"tx_type,omitempty"`
+		Direction      string           `json:"direction,omitempty"`
 		types.PaginationParams
 	}
 
@@ -63,10 +65,74 @@ func (m *AccountTxMethod) Handle(ctx *types.RpcContext, params json.RawMessage)
 		return nil, types.RpcErrorInvalidParams("invalidParams")
 	}
 
-	// Parse marker if provided
+	marker, rpcErr := parseTxMarker(request.Marker)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	// A marker returned for a filtered page only resumes that filter.
+	filter, rpcErr := types.ParseAccountTxFilter(request.TxType, request.Direction)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	result, err := types.Services.Ledger.GetAccountTransactions(
+		request.Account,
+		int64(ledgerIndexMin),
+		int64(ledgerIndexMax),
+		request.Limit,
+		marker,
+		request.Forward,
+		filter,
+	)
+	if err != nil {
+		if err.Error() == "transaction history not available (no database configured)" {
+			return nil, &types.RpcError{
+				Code:    73,
+				Message: "Transaction history not available. Database not configured.",
+			}
+		}
+		if err.Error() == "account not found" {
+			return nil, &types.RpcError{
+				Code:    19,
+				Message: "Account not found.",
+			}
+		}
+		return nil, types.RpcErrorInternal("Failed to get account transactions: " + err.Error())
+	}
+
+	transactions := formatTransactions(ctx, result.Transactions, request.Binary)
+
+	response := map[string]interface{}{
+		"account":          result.Account,
+		"ledger_index_min": result.LedgerMin,
+		"ledger_index_max": result.LedgerMax,
+		"limit":            result.Limit,
+		"transactions":     transactions,
+		"validated":        result.Validated,
+	}
+	if filter.TxType != "" {
+		response["tx_type"] = filter.TxType
+	}
+	if filter.Direction != "" {
+		response["direction"] = filter.Direction
+	}
+
+	if result.Marker != nil {
+		response["marker"] = map[string]interface{}{
+			"ledger": result.Marker.LedgerSeq,
+			"seq":    result.Marker.TxnSeq,
+		}
+	}
+
+	return response, nil
+}
+
+// parseTxMarker parses a transaction history marker, {"ledger", "seq"}.
+func parseTxMarker(raw interface{}) (*types.AccountTxMarker, *types.RpcError) {
 	var marker *types.AccountTxMarker
-	if request.Marker != nil {
-		if markerMap, ok := request.Marker.(map[string]interface{}); ok {
+	if raw != nil {
+		if markerMap, ok := raw.(map[string]interface{}); ok {
 			marker = &types.AccountTxMarker{}
 			if ledger, ok := markerMap["ledger"]; ok {
 				switch v := ledger.(type) {
@@ -94,31 +160,12 @@ func (m *AccountTxMethod) Handle(ctx *types.RpcContext, params json.RawMessage)
 			return nil, types.RpcErrorInvalidParams("invalid marker. Provide ledger index via ledger field, and transaction sequence number via seq field")
 		}
 	}
+	return marker, nil
+}
 
-	result, err := types.Services.Ledger.GetAccountTransactions(
-		request.Account,
-		int64(ledgerIndexMin),
-		int64(ledgerIndexMax),
-		request.Limit,
-		marker,
-		request.Forward,
-	)
-	if err != nil {
-		if err.Error() == "transaction history not available (no database configured)" {
-			return nil, &types.RpcError{
-				Code:    73,
-				Message: "Transaction history not available. Database not configured.",
-			}
-		}
-		if err.Error() == "account not found" {
-			return nil, &types.RpcError{
-				Code:    19,
-				Message: "Account not found.",
-			}
-		}
-		return nil, types.RpcErrorInternal("Failed to get account transactions: " + err.Error())
-	}
-
+// formatTransactions renders stored transactions as account_tx does, for
+// the methods that return transaction history.
+func formatTransactions(ctx *types.RpcContext, txs []types.AccountTransaction, binary bool) []map[string]interface{} {
 	// Cache for ledger lookups by sequence, to avoid repeated lookups
 	// for transactions in the same ledger.
 	type ledgerCacheEntry struct {
@@ -149,15 +196,15 @@ func (m *AccountTxMethod) Handle(ctx *types.RpcContext, params json.RawMessage)
 	isV2 := ctx.ApiVersion > 1
 
 	// Build transactions array
-	transactions := make([]map[string]interface{}, len(result.Transactions))
-	for i, txn := range result.Transactions {
+	transactions := make([]map[string]interface{}, len(txs))
+	for i, txn := range txs {
 		txEntry := map[string]interface{}{
 			"validated": true,
 		}
 
 		txHashHex := strings.ToUpper(hex.EncodeToString(txn.Hash[:]))
 
-		if request.Binary {
+		if binary {
 			// Binary mode
 			txEntry["tx_blob"] = strings.ToUpper(hex.EncodeToString(txn.TxBlob))
 			if isV2 {
@@ -242,23 +289,7 @@ func (m *AccountTxMethod) Handle(ctx *types.RpcContext, params json.RawMessage)
 		transactions[i] = txEntry
 	}
 
-	response := map[string]interface{}{
-		"account":          result.Account,
-		"ledger_index_min": result.LedgerMin,
-		"ledger_index_max": result.LedgerMax,
-		"limit":            result.Limit,
-		"transactions":     transactions,
-		"validated":        result.Validated,
-	}
-
-	if result.Marker != nil {
-		response["marker"] = map[string]interface{}{
-			"ledger": result.Marker.LedgerSeq,
-			"seq":    result.Marker.TxnSeq,
-		}
-	}
-
-	return response, nil
+	return transactions
 }
 
 // injectDeliverMax adds DeliverMax to Payment transaction JSON.
@@ -1,19 +1,23 @@
 package handlers
 
 import (
+	"cmp"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math"
 	"math/big"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/LeJamon/goXRPLd/internal/ledger/state"
 	"github.com/LeJamon/goXRPLd/internal/rpc/types"
 )
 
 // BookChangesMethod handles the book_changes RPC method.
-// Computes OHLCV data for all currency pairs that had offer changes in a ledger.
+// Computes OHLCV data for all currency pairs that had offer changes or AMM
+// swaps in a ledger.
 // Reference: rippled BookChanges.h (computeBookChanges)
 type BookChangesMethod struct{ BaseHandler }
 
@@ -21,6 +25,7 @@ type BookChangesMethod struct{ BaseHandler }
 type bookChange struct {
 	CurrencyA string
 	CurrencyB string
+	Domain    string // permissioned domain of the book, empty for the open DEX
 	VolumeA   *big.Float
 	VolumeB   *big.Float
 	High      *big.Float
@@ -32,12 +37,19 @@ type bookChange struct {
 func (m *BookChangesMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
 	var request struct {
 		types.LedgerSpecifier
+		// Domain restricts the report to one permissioned domain's books.
+		Domain json.RawMessage `json:"domain,omitempty"`
 	}
 
 	if err := ParseParams(params, &request); err != nil {
 		return nil, err
 	}
 
+	domainFilter, rpcErr := types.ParseDomainParam(request.Domain)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
 	if err := RequireLedgerService(); err != nil {
 		return nil, err
 	}
@@ -75,204 +87,445 @@ func (m *BookChangesMethod) Handle(ctx *types.RpcContext, params json.RawMessage
 		return nil, types.RpcErrorLgrNotFound("Ledger not found")
 	}
 
-	// Collect all offer changes from transaction metadata
-	changes := make(map[string]*bookChange)
-
+	changes := NewBookChanges(domainFilter)
 	targetLedger.ForEachTransaction(func(txHash [32]byte, txData []byte) bool {
 		// Decode VL-encoded binary blob (or JSON fallback)
 		storedTx, err := decodeTxBlob(txData)
-		if err != nil {
-			return true // skip, continue
+		if err == nil {
+			changes.AddTransaction(storedTx.TxJSON, storedTx.Meta)
 		}
+		return true
+	})
+
+	return BookChangesMessage(targetLedger.Sequence(), targetLedger.Hash(), targetLedger.CloseTime(),
+		targetLedger.IsValidated(), changes.Changes()), nil
+}
+
+// BookChangesMessage builds a bookChanges object, the book_changes
+// response and the message of the book_changes stream.
+func BookChangesMessage(ledgerIndex uint32, ledgerHash [32]byte, ledgerTime int64, validated bool, changes []map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type":         "bookChanges",
+		"ledger_index": ledgerIndex,
+		"ledger_hash":  strings.ToUpper(hex.EncodeToString(ledgerHash[:])),
+		"ledger_time":  ledgerTime,
+		"validated":    validated,
+		"changes":      changes,
+	}
+}
+
+// BookChanges accumulates the OHLCV data of the books changed by a
+// ledger's transactions. Transactions are taken in ledger order, by their
+// TransactionIndex, whatever order they are added in, so that the
+// book_changes RPC and stream report the same figures.
+type BookChanges struct {
+	domainFilter string
+	txs          []txBookDeltas
+}
+
+// txBookDeltas holds the book deltas of one transaction.
+type txBookDeltas struct {
+	index  uint32
+	deltas []bookDelta
+}
+
+// NewBookChanges returns an empty BookChanges. A non-nil domainFilter
+// keeps only the books of that permissioned domain.
+func NewBookChanges(domainFilter *[32]byte) *BookChanges {
+	b := &BookChanges{}
+	if domainFilter != nil {
+		b.domainFilter = strings.ToUpper(hex.EncodeToString(domainFilter[:]))
+	}
+	return b
+}
+
+// bookDelta is the change a transaction made to one book's liquidity:
+// the final amounts of an offer, which name the currencies, and how much
+// its TakerGets and TakerPays changed.
+type bookDelta struct {
+	gets, pays           *parsedAmount
+	deltaGets, deltaPays *big.Float
+	domain               string
+}
+
+// AddTransaction adds the offers a transaction crossed and the swaps AMM
+// pools made in it. Transactions without metadata are ignored.
+func (b *BookChanges) AddTransaction(txJSON, meta map[string]interface{}) {
+	if meta == nil {
+		return
+	}
+	affectedNodes, ok := meta["AffectedNodes"].([]interface{})
+	if !ok {
+		return
+	}
+	deltas := append(offerDeltas(txJSON, affectedNodes), ammSwapDeltas(affectedNodes)...)
+	b.txs = append(b.txs, txBookDeltas{index: toUint32(meta["TransactionIndex"]), deltas: deltas})
+}
+
+// bookChanges aggregates book deltas by currency pair and domain, in the
+// order the books first change.
+type bookChanges struct {
+	books map[string]*bookChange
+	order []*bookChange
+}
+
+func (b *bookChanges) add(d bookDelta) {
+
+	// Determine currency pair ordering.
+	// Reference: rippled BookChanges.h lines 124-131
+	// noswap = isXRP(deltaGets) ? true : (isXRP(deltaPays) ? false : (g < p))
+	g := formatCurrencyKey(d.gets)
+	p := formatCurrencyKey(d.pays)
+
+	var noswap bool
+	if d.gets.isXRP {
+		noswap = true
+	} else if d.pays.isXRP {
+		noswap = false
+	} else {
+		noswap = g < p
+	}
+
+	var first, second *big.Float
+	var currA, currB string
+	if noswap {
+		first, second = d.deltaGets, d.deltaPays
+		currA, currB = g, p
+	} else {
+		first, second = d.deltaPays, d.deltaGets
+		currA, currB = p, g
+	}
+	pairKey := currA + "|" + currB
+	if d.domain != "" {
+		pairKey += "|" + d.domain
+	}
 
-		if storedTx.Meta == nil {
-			return true
+	if second.Sign() == 0 {
+		return
+	}
+
+	// rate = first / second (matching rippled's divide)
+	rate := new(big.Float).Quo(first, second)
+
+	bc, exists := b.books[pairKey]
+	if !exists {
+		bc = &bookChange{
+			CurrencyA: currA,
+			CurrencyB: currB,
+			Domain:    d.domain,
+			VolumeA:   new(big.Float),
+			VolumeB:   new(big.Float),
+			Open:      new(big.Float).Set(rate),
+			High:      new(big.Float).Set(rate),
+			Low:       new(big.Float).Set(rate),
+			Close:     new(big.Float).Set(rate),
+		}
+		b.books[pairKey] = bc
+		b.order = append(b.order, bc)
+	} else {
+		if rate.Cmp(bc.High) > 0 {
+			bc.High.Set(rate)
+		}
+		if rate.Cmp(bc.Low) < 0 {
+			bc.Low.Set(rate)
 		}
+		bc.Close.Set(rate)
+	}
 
-		// Get TransactionType to detect OfferCancel/OfferCreate with OfferSequence
-		txType, _ := storedTx.TxJSON["TransactionType"].(string)
+	// Accumulate volumes (absolute values)
+	bc.VolumeA.Add(bc.VolumeA, new(big.Float).Abs(first))
+	bc.VolumeB.Add(bc.VolumeB, new(big.Float).Abs(second))
+}
 
-		// Read OfferSequence from the tx (used by both OfferCancel and OfferCreate
-		// to cancel a prior offer). Reference: rippled BookChanges.h lines 67-81
-		var offerCancel *uint32
-		if txType == "OfferCancel" || txType == "OfferCreate" {
-			if offerSeqVal, ok := storedTx.TxJSON["OfferSequence"].(float64); ok {
-				v := uint32(offerSeqVal)
-				offerCancel = &v
+// Changes returns the "changes" array of a bookChanges object.
+func (b *BookChanges) Changes() []map[string]interface{} {
+	txs := slices.Clone(b.txs)
+	slices.SortStableFunc(txs, func(x, y txBookDeltas) int { return cmp.Compare(x.index, y.index) })
+	agg := &bookChanges{books: make(map[string]*bookChange)}
+	for _, t := range txs {
+		for _, d := range t.deltas {
+			if b.domainFilter == "" || d.domain == b.domainFilter {
+				agg.add(d)
 			}
 		}
+	}
 
-		affectedNodes, ok := storedTx.Meta["AffectedNodes"].([]interface{})
+	changesArr := make([]map[string]interface{}, 0, len(agg.order))
+	for _, bc := range agg.order {
+		entry := map[string]interface{}{
+			"currency_a": bc.CurrencyA,
+			"currency_b": bc.CurrencyB,
+			"volume_a":   formatBigFloat(bc.VolumeA),
+			"volume_b":   formatBigFloat(bc.VolumeB),
+			"high":       formatBigFloat(bc.High),
+			"low":        formatBigFloat(bc.Low),
+			"open":       formatBigFloat(bc.Open),
+			"close":      formatBigFloat(bc.Close),
+		}
+		if bc.Domain != "" {
+			entry["domain"] = bc.Domain
+		}
+		changesArr = append(changesArr, entry)
+	}
+	return changesArr
+}
+
+// offerDeltas returns the changes of the offers a transaction crossed.
+// Reference: rippled BookChanges.h (computeBookChanges)
+func offerDeltas(txJSON map[string]interface{}, affectedNodes []interface{}) []bookDelta {
+	// Get TransactionType to detect OfferCancel/OfferCreate with OfferSequence
+	txType, _ := txJSON["TransactionType"].(string)
+
+	// Read OfferSequence from the tx (used by both OfferCancel and OfferCreate
+	// to cancel a prior offer). Reference: rippled BookChanges.h lines 67-81
+	var offerCancel *uint32
+	if txType == "OfferCancel" || txType == "OfferCreate" {
+		if offerSeqVal, ok := txJSON["OfferSequence"]; ok {
+			v := toUint32(offerSeqVal)
+			offerCancel = &v
+		}
+	}
+
+	var deltas []bookDelta
+	for _, nodeRaw := range affectedNodes {
+		node, ok := nodeRaw.(map[string]interface{})
 		if !ok {
-			return true
+			continue
 		}
 
-		for _, nodeRaw := range affectedNodes {
-			node, ok := nodeRaw.(map[string]interface{})
-			if !ok {
-				continue
-			}
+		// Only process Modified and Deleted Offer nodes
+		var nodeData map[string]interface{}
+		var nodeType string
+
+		if mn, ok := node["ModifiedNode"].(map[string]interface{}); ok {
+			nodeData = mn
+			nodeType = "ModifiedNode"
+		} else if dn, ok := node["DeletedNode"].(map[string]interface{}); ok {
+			nodeData = dn
+			nodeType = "DeletedNode"
+		} else {
+			continue
+		}
 
-			// Only process Modified and Deleted Offer nodes
-			var nodeData map[string]interface{}
-			var nodeType string
-
-			if mn, ok := node["ModifiedNode"].(map[string]interface{}); ok {
-				nodeData = mn
-				nodeType = "ModifiedNode"
-			} else if dn, ok := node["DeletedNode"].(map[string]interface{}); ok {
-				nodeData = dn
-				nodeType = "DeletedNode"
-			} else {
-				continue
-			}
+		entryType, _ := nodeData["LedgerEntryType"].(string)
+		if entryType != "Offer" {
+			continue
+		}
 
-			entryType, _ := nodeData["LedgerEntryType"].(string)
-			if entryType != "Offer" {
-				continue
-			}
+		finalFields, _ := nodeData["FinalFields"].(map[string]interface{})
+		previousFields, _ := nodeData["PreviousFields"].(map[string]interface{})
+
+		if finalFields == nil || previousFields == nil {
+			continue
+		}
 
-			finalFields, _ := nodeData["FinalFields"].(map[string]interface{})
-			previousFields, _ := nodeData["PreviousFields"].(map[string]interface{})
+		// Offers in a permissioned domain form their own books.
+		// Reference: rippled BookChanges.h (sfDomainID)
+		domain, _ := finalFields["DomainID"].(string)
 
-			if finalFields == nil || previousFields == nil {
+		// Skip explicitly cancelled offers: filter out deleted offers whose
+		// Sequence matches the tx's OfferSequence field.
+		// Reference: rippled BookChanges.h lines 112-115
+		if nodeType == "DeletedNode" && offerCancel != nil {
+			if offerSeq, ok := finalFields["Sequence"]; ok && toUint32(offerSeq) == *offerCancel {
 				continue
 			}
+		}
 
-			// Skip explicitly cancelled offers: filter out deleted offers whose
-			// Sequence matches the tx's OfferSequence field.
-			// Reference: rippled BookChanges.h lines 112-115
-			if nodeType == "DeletedNode" && offerCancel != nil {
-				if offerSeq, ok := finalFields["Sequence"].(float64); ok {
-					if uint32(offerSeq) == *offerCancel {
-						continue
-					}
-				}
-			}
+		// Compute deltas
+		prevGets := parseAmount(previousFields["TakerGets"])
+		prevPays := parseAmount(previousFields["TakerPays"])
+		finalGets := parseAmount(finalFields["TakerGets"])
+		finalPays := parseAmount(finalFields["TakerPays"])
+
+		if prevGets == nil || prevPays == nil || finalGets == nil || finalPays == nil {
+			continue
+		}
+
+		// Reference: rippled BookChanges.h lines 119-122
+		// deltaGets = finalFields.TakerGets - previousFields.TakerGets
+		// deltaPays = finalFields.TakerPays - previousFields.TakerPays
+		deltas = append(deltas, bookDelta{
+			gets:      finalGets,
+			pays:      finalPays,
+			deltaGets: new(big.Float).Sub(finalGets.value, prevGets.value),
+			deltaPays: new(big.Float).Sub(finalPays.value, prevPays.value),
+			domain:    strings.ToUpper(domain),
+		})
+	}
+	return deltas
+}
 
-			// Compute deltas
-			prevGets := parseAmount(previousFields["TakerGets"])
-			prevPays := parseAmount(previousFields["TakerPays"])
-			finalGets := parseAmount(finalFields["TakerGets"])
-			finalPays := parseAmount(finalFields["TakerPays"])
+// poolChange is the change of one asset an AMM pool holds.
+type poolChange struct {
+	asset *parsedAmount // final holding
+	delta *big.Float
+}
 
-			if prevGets == nil || prevPays == nil || finalGets == nil || finalPays == nil {
+// ammPoolChanges returns the changes of the assets held by the AMM
+// accounts a transaction modified, in the order the accounts appear.
+// An AMM account holds XRP in its AccountRoot, which has an AMMID, and
+// tokens in trust lines flagged lsfAMMNode, on which it is the holder.
+func ammPoolChanges(affectedNodes []interface{}) ([]string, map[string][]poolChange) {
+	var accounts []string
+	pools := make(map[string][]poolChange)
+	addChange := func(account string, asset *parsedAmount, delta *big.Float) {
+		if _, ok := pools[account]; !ok {
+			accounts = append(accounts, account)
+		}
+		pools[account] = append(pools[account], poolChange{asset: asset, delta: delta})
+	}
+
+	for _, nodeRaw := range affectedNodes {
+		node, _ := nodeRaw.(map[string]interface{})
+		mn, _ := node["ModifiedNode"].(map[string]interface{})
+		final, _ := mn["FinalFields"].(map[string]interface{})
+		previous, _ := mn["PreviousFields"].(map[string]interface{})
+		if final == nil || previous == nil || previous["Balance"] == nil {
+			continue
+		}
+		prev, cur := parseAmount(previous["Balance"]), parseAmount(final["Balance"])
+		if prev == nil || cur == nil {
+			continue
+		}
+
+		switch mn["LedgerEntryType"] {
+		case "AccountRoot":
+			if final["AMMID"] == nil {
 				continue
 			}
+			account, _ := final["Account"].(string)
+			addChange(account, cur, new(big.Float).Sub(cur.value, prev.value))
 
-			// Reference: rippled BookChanges.h lines 119-122
-			// deltaGets = finalFields.TakerGets - previousFields.TakerGets
-			// deltaPays = finalFields.TakerPays - previousFields.TakerPays
-			deltaGets := new(big.Float).Sub(finalGets.value, prevGets.value)
-			deltaPays := new(big.Float).Sub(finalPays.value, prevPays.value)
-
-			// Determine currency pair ordering.
-			// Reference: rippled BookChanges.h lines 124-131
-			// noswap = isXRP(deltaGets) ? true : (isXRP(deltaPays) ? false : (g < p))
-			g := formatCurrencyKey(finalGets)
-			p := formatCurrencyKey(finalPays)
-
-			var noswap bool
-			if finalGets.isXRP {
-				noswap = true
-			} else if finalPays.isXRP {
-				noswap = false
-			} else {
-				noswap = g < p
+		case "RippleState":
+			if toUint32(final["Flags"])&state.LsfAMMNode == 0 {
+				continue
 			}
-
-			var first, second *big.Float
-			var pairKey string
-			if noswap {
-				first = deltaGets
-				second = deltaPays
-				pairKey = g + "|" + p
-			} else {
-				first = deltaPays
-				second = deltaGets
-				pairKey = p + "|" + g
+			low, _ := final["LowLimit"].(map[string]interface{})
+			high, _ := final["HighLimit"].(map[string]interface{})
+			lowAccount, _ := low["issuer"].(string)
+			highAccount, _ := high["issuer"].(string)
+
+			// The balance is the low account's; the AMM holds the tokens,
+			// so it is the low account while the balance is positive.
+			held := cur.value
+			if held.Sign() == 0 {
+				held = prev.value
 			}
+			account, issuer, sign := lowAccount, highAccount, big.NewFloat(1)
+			if held.Sign() < 0 {
+				account, issuer, sign = highAccount, lowAccount, big.NewFloat(-1)
+			}
+			holding := &parsedAmount{value: new(big.Float).Mul(cur.value, sign), currency: cur.currency, issuer: issuer}
+			delta := new(big.Float).Sub(cur.value, prev.value)
+			addChange(account, holding, delta.Mul(delta, sign))
+		}
+	}
+	return accounts, pools
+}
+
+// ammSwapDeltas returns the swaps AMM pools made in a transaction as
+// offer deltas: a pool that paid out one asset and took in the other
+// acted as an offer whose TakerGets is the asset paid out. Deposits and
+// withdrawals move one asset or both the same way and are not trades.
+func ammSwapDeltas(affectedNodes []interface{}) []bookDelta {
+	accounts, pools := ammPoolChanges(affectedNodes)
+	var deltas []bookDelta
+	for _, account := range accounts {
+		changes := pools[account]
+		if len(changes) != 2 || changes[0].delta.Sign()*changes[1].delta.Sign() >= 0 ||
+			formatCurrencyKey(changes[0].asset) == formatCurrencyKey(changes[1].asset) {
+			continue
+		}
+		out, in := changes[0], changes[1]
+		if out.delta.Sign() > 0 {
+			out, in = in, out
+		}
+		deltas = append(deltas, bookDelta{
+			gets:      out.asset,
+			pays:      in.asset,
+			deltaGets: out.delta,
+			deltaPays: new(big.Float).Neg(in.delta),
+		})
+	}
+	return deltas
+}
+
+// TxOrderBooks returns the order books a transaction changed: those of
+// the offers it created, modified or deleted, and both directions of the
+// AMM pools it traded against, deposited into or withdrew from. Each book
+// is listed once.
+func TxOrderBooks(meta map[string]interface{}) []types.OrderBook {
+	affectedNodes, _ := meta["AffectedNodes"].([]interface{})
+	var books []types.OrderBook
+	addBook := func(book types.OrderBook) {
+		if !slices.Contains(books, book) {
+			books = append(books, book)
+		}
+	}
+	addPool := func(asset, asset2 types.CurrencySpec) {
+		addBook(types.OrderBook{TakerGets: asset, TakerPays: asset2})
+		addBook(types.OrderBook{TakerGets: asset2, TakerPays: asset})
+	}
 
-			if second.Sign() == 0 {
+	for _, nodeRaw := range affectedNodes {
+		node, _ := nodeRaw.(map[string]interface{})
+		for _, nodeType := range []string{"CreatedNode", "ModifiedNode", "DeletedNode"} {
+			nodeData, ok := node[nodeType].(map[string]interface{})
+			if !ok {
 				continue
 			}
-
-			// rate = first / second (matching rippled's divide)
-			rate := new(big.Float).Quo(first, second)
-
-			// Take absolute values for volume accumulation
-			absFirst := new(big.Float).Abs(first)
-			absSecond := new(big.Float).Abs(second)
-
-			// Determine currency labels for output
-			var currA, currB string
-			if noswap {
-				currA = g
-				currB = p
-			} else {
-				currA = p
-				currB = g
+			fields, _ := nodeData["FinalFields"].(map[string]interface{})
+			if nodeType == "CreatedNode" {
+				fields, _ = nodeData["NewFields"].(map[string]interface{})
 			}
-
-			bc, exists := changes[pairKey]
-			if !exists {
-				bc = &bookChange{
-					CurrencyA: currA,
-					CurrencyB: currB,
-					VolumeA:   new(big.Float),
-					VolumeB:   new(big.Float),
-					Open:      new(big.Float).Set(rate),
-					High:      new(big.Float).Set(rate),
-					Low:       new(big.Float).Set(rate),
-					Close:     new(big.Float).Set(rate),
+			switch nodeData["LedgerEntryType"] {
+			case "Offer":
+				gets, pays := parseAmount(fields["TakerGets"]), parseAmount(fields["TakerPays"])
+				if gets != nil && pays != nil {
+					domain, _ := fields["DomainID"].(string)
+					addBook(types.OrderBook{TakerGets: currencySpecOf(gets), TakerPays: currencySpecOf(pays), Domain: strings.ToUpper(domain)})
 				}
-				changes[pairKey] = bc
-			} else {
-				if rate.Cmp(bc.High) > 0 {
-					bc.High.Set(rate)
+			case "AMM":
+				asset, ok := issueSpec(fields["Asset"])
+				asset2, ok2 := issueSpec(fields["Asset2"])
+				if ok && ok2 {
+					addPool(asset, asset2)
 				}
-				if rate.Cmp(bc.Low) < 0 {
-					bc.Low.Set(rate)
-				}
-				bc.Close.Set(rate)
 			}
-
-			// Accumulate volumes (absolute values)
-			bc.VolumeA.Add(bc.VolumeA, absFirst)
-			bc.VolumeB.Add(bc.VolumeB, absSecond)
 		}
-
-		return true
-	})
-
-	// Build response
-	changesArr := make([]map[string]interface{}, 0, len(changes))
-	for _, bc := range changes {
-		changesArr = append(changesArr, map[string]interface{}{
-			"currency_a": bc.CurrencyA,
-			"currency_b": bc.CurrencyB,
-			"volume_a":   formatBigFloat(bc.VolumeA),
-			"volume_b":   formatBigFloat(bc.VolumeB),
-			"high":       formatBigFloat(bc.High),
-			"low":        formatBigFloat(bc.Low),
-			"open":       formatBigFloat(bc.Open),
-			"close":      formatBigFloat(bc.Close),
-		})
 	}
 
-	ledgerHash := targetLedger.Hash()
-	ledgerHashStr := strings.ToUpper(hex.EncodeToString(ledgerHash[:]))
+	// Swaps leave the AMM entry itself untouched.
+	accounts, pools := ammPoolChanges(affectedNodes)
+	for _, account := range accounts {
+		if changes := pools[account]; len(changes) == 2 {
+			addPool(currencySpecOf(changes[0].asset), currencySpecOf(changes[1].asset))
+		}
+	}
+	return books
+}
 
-	response := map[string]interface{}{
-		"type":         "bookChanges",
-		"ledger_index": targetLedger.Sequence(),
-		"ledger_hash":  ledgerHashStr,
-		"ledger_time":  targetLedger.CloseTime(),
-		"validated":    targetLedger.IsValidated(),
-		"changes":      changesArr,
+// currencySpecOf returns the currency and issuer of an amount.
+func currencySpecOf(amt *parsedAmount) types.CurrencySpec {
+	if amt.isXRP {
+		return types.CurrencySpec{Currency: "XRP"}
 	}
+	return types.CurrencySpec{Currency: amt.currency, Issuer: amt.issuer}
+}
 
-	return response, nil
+// issueSpec parses an Issue field such as an AMM's Asset.
+func issueSpec(raw interface{}) (types.CurrencySpec, bool) {
+	issue, ok := raw.(map[string]interface{})
+	if !ok {
+		return types.CurrencySpec{}, false
+	}
+	currency, _ := issue["currency"].(string)
+	issuer, _ := issue["issuer"].(string)
+	if currency == "" {
+		return types.CurrencySpec{}, false
+	}
+	return types.CurrencySpec{Currency: currency, Issuer: issuer}, true
 }
 
 // parsedAmount holds a parsed amount with its currency info
@@ -15,6 +15,7 @@ func (m *BookOffersMethod) Handle(ctx *types.RpcContext, params json.RawMessage)
 		TakerGets json.RawMessage `json:"taker_gets"`
 		TakerPays json.RawMessage `json:"taker_pays"`
 		Taker     string          `json:"taker,omitempty"`
+		Domain    json.RawMessage `json:"domain,omitempty"`
 		types.LedgerSpecifier
 		types.PaginationParams
 	}
@@ -51,6 +52,13 @@ func (m *BookOffersMethod) Handle(ctx *types.RpcContext, params json.RawMessage)
 		return nil, rpcErr
 	}
 
+	// Optional permissioned domain: read that domain's book instead of
+	// the open one. Reference: rippled BookOffers.cpp (jss::domain)
+	domain, rpcErr := types.ParseDomainParam(request.Domain)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
 	// Determine ledger index to use
 	ledgerIndex := "current"
 	if request.LedgerIndex != "" {
@@ -60,7 +68,7 @@ func (m *BookOffersMethod) Handle(ctx *types.RpcContext, params json.RawMessage)
 	// Clamp the limit using rippled's bookOffers range {0, 60, 100}.
 	// When the user omits "limit" (zero value), ClampLimit returns the default (60).
 	limit := ClampLimit(request.Limit, LimitBookOffers, ctx.IsAdmin)
-	result, err := types.Services.Ledger.GetBookOffers(takerGets, takerPays, ledgerIndex, limit)
+	result, err := types.Services.Ledger.GetBookOffers(takerGets, takerPays, domain, ledgerIndex, limit)
 	if err != nil {
 		return nil, types.RpcErrorInternal("Failed to get book offers: " + err.Error())
 	}
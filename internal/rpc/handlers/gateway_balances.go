@@ -148,5 +148,15 @@ func (m *GatewayBalancesMethod) Handle(ctx *types.RpcContext, params json.RawMes
 		response["locked"] = result.Locked
 	}
 
+	// MPT issuers get the outstanding and escrowed amounts of their
+	// issuances, keyed by mpt_issuance_id. rippled has no equivalent.
+	if len(result.MPTObligations) > 0 {
+		response["mpt_obligations"] = result.MPTObligations
+	}
+
+	if len(result.MPTLocked) > 0 {
+		response["mpt_locked"] = result.MPTLocked
+	}
+
 	return response, nil
 }
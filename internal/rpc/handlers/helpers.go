@@ -78,6 +78,15 @@ var (
 	LimitAccountNFTokens = LimitRange{20, 100, 400}
 	LimitNFTOffers       = LimitRange{50, 250, 500}
 
+	// Clio's NFTHistory and NFTsByIssuer limits
+	LimitNFTHistory   = LimitRange{1, 50, 100}
+	LimitNFTsByIssuer = LimitRange{1, 50, 100}
+
+	// Clio's MPTHolders limits; account_mpt_issuances pages like
+	// account_objects
+	LimitMPTHolders          = LimitRange{1, 50, 100}
+	LimitAccountMPTIssuances = LimitRange{10, 200, 400}
+
 	// LedgerData limits from rippled Tuning.h: pageLength(isBinary)
 	// Binary mode: binaryPageLength = 2048
 	// JSON mode: jsonPageLength = 256
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+)
+
+// MptHoldersMethod handles the mpt_holders RPC method, which lists the
+// MPTokens of an issuance as of a ledger, in holder account order.
+// Reference: Clio MPTHolders.cpp
+type MptHoldersMethod struct{ BaseHandler }
+
+func (m *MptHoldersMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
+	var request struct {
+		MPTIssuanceID string `json:"mpt_issuance_id"`
+		types.LedgerSpecifier
+		Limit  uint32 `json:"limit,omitempty"`
+		Marker string `json:"marker,omitempty"`
+	}
+
+	if err := ParseParams(params, &request); err != nil {
+		return nil, err
+	}
+
+	if request.MPTIssuanceID == "" {
+		return nil, types.RpcErrorMissingField("mpt_issuance_id")
+	}
+	var mptID [24]byte
+	b, err := hex.DecodeString(request.MPTIssuanceID)
+	if err != nil || len(b) != len(mptID) {
+		return nil, types.RpcErrorInvalidParams("Malformed mpt_issuance_id.")
+	}
+	copy(mptID[:], b)
+
+	// The marker is the account ID of the last holder of the previous page.
+	var marker *[20]byte
+	if request.Marker != "" {
+		var holder [20]byte
+		b, err := hex.DecodeString(request.Marker)
+		if err != nil || len(b) != len(holder) {
+			return nil, types.RpcErrorInvalidParams("Malformed marker.")
+		}
+		copy(holder[:], b)
+		marker = &holder
+	}
+
+	mpts, rpcErr := requireMPTQueries()
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	ledgerIndex := "validated"
+	if request.LedgerIndex != "" {
+		ledgerIndex = request.LedgerIndex.String()
+	}
+
+	limit := ClampLimit(request.Limit, LimitMPTHolders, ctx.IsAdmin)
+	result, rpcErr := mpts.GetMPTHolders(mptID, ledgerIndex, marker, limit)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	holders := make([]map[string]interface{}, len(result.Holders))
+	for i, h := range result.Holders {
+		holder := map[string]interface{}{
+			"account":       h.Account,
+			"flags":         h.Flags,
+			"mpt_amount":    h.MPTAmount,
+			"mptoken_index": FormatHash(h.MPTokenIndex[:]),
+			"locked":        h.Locked,
+		}
+		if h.LockedAmount != nil {
+			holder["locked_amount"] = *h.LockedAmount
+		}
+		holders[i] = holder
+	}
+
+	response := map[string]interface{}{
+		"mpt_issuance_id": FormatHash(result.MPTIssuanceID[:]),
+		"mptokens":        holders,
+		"ledger_index":    result.LedgerIndex,
+		"validated":       result.Validated,
+		"limit":           result.Limit,
+	}
+	if result.Marker != nil {
+		response["marker"] = FormatHash(result.Marker[:])
+	}
+	return response, nil
+}
+
+// requireMPTQueries returns the MPT query service.
+func requireMPTQueries() (types.MPTQueries, *types.RpcError) {
+	if types.Services == nil || types.Services.MPTs == nil {
+		return nil, types.RpcErrorInternal("Ledger service not available")
+	}
+	return types.Services.MPTs, nil
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+)
+
+// NftHistoryMethod handles the nft_history RPC method, which pages through
+// the transactions that minted, transferred, burned or modified an NFT or
+// created, cancelled or accepted offers for it.
+// Reference: Clio NFTHistory.cpp
+type NftHistoryMethod struct{ BaseHandler }
+
+func (m *NftHistoryMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
+	var request struct {
+		NFTokenID      string `json:"nft_id"`
+		LedgerIndexMin *int32 `json:"ledger_index_min,omitempty"`
+		LedgerIndexMax *int32 `json:"ledger_index_max,omitempty"`
+		types.LedgerSpecifier
+		Binary  bool `json:"binary,omitempty"`
+		Forward bool `json:"forward,omitempty"`
+		types.PaginationParams
+	}
+
+	if err := ParseParams(params, &request); err != nil {
+		return nil, err
+	}
+
+	nftID, rpcErr := parseNFTokenID(request.NFTokenID)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	// -1, or no value, leaves an end of the range open.
+	var ledgerMin, ledgerMax int64
+	if request.LedgerIndexMin != nil {
+		ledgerMin = int64(*request.LedgerIndexMin)
+	}
+	if request.LedgerIndexMax != nil {
+		ledgerMax = int64(*request.LedgerIndexMax)
+	}
+	if ledgerMin > 0 && ledgerMax > 0 && ledgerMax < ledgerMin {
+		return nil, types.NewRpcError(types.RpcLGR_IDXS_INVALID, "lgrIdxsInvalid", "lgrIdxsInvalid",
+			"Ledger indexes invalid.")
+	}
+
+	// A single ledger may be given instead of a range.
+	if request.LedgerIndex != "" {
+		if request.LedgerIndexMin != nil || request.LedgerIndexMax != nil {
+			return nil, types.RpcErrorInvalidParams("containsLedgerSpecifierAndRange")
+		}
+		var seq uint64
+		if request.LedgerIndex == "validated" && types.Services != nil && types.Services.Ledger != nil {
+			seq = uint64(types.Services.Ledger.GetValidatedLedgerIndex())
+		} else {
+			var err error
+			if seq, err = strconv.ParseUint(request.LedgerIndex.String(), 10, 32); err != nil {
+				return nil, types.RpcErrorInvalidParams("ledgerIndexMalformed")
+			}
+		}
+		ledgerMin, ledgerMax = int64(seq), int64(seq)
+	}
+
+	marker, rpcErr := parseTxMarker(request.Marker)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	index, rpcErr := requireNFTIndex()
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	limit := ClampLimit(request.Limit, LimitNFTHistory, ctx.IsAdmin)
+	result, rpcErr := index.GetNFTHistory(nftID, ledgerMin, ledgerMax, limit, marker, request.Forward)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	response := map[string]interface{}{
+		"nft_id":           FormatHash(nftID[:]),
+		"ledger_index_min": result.LedgerMin,
+		"ledger_index_max": result.LedgerMax,
+		"limit":            result.Limit,
+		"transactions":     formatTransactions(ctx, result.Transactions, request.Binary),
+		"validated":        true,
+	}
+	if result.Marker != nil {
+		response["marker"] = map[string]interface{}{
+			"ledger": result.Marker.LedgerSeq,
+			"seq":    result.Marker.TxnSeq,
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+)
+
+// NftInfoMethod handles the nft_info RPC method, which reports the state of
+// an NFT as of a ledger, including burned NFTs.
+// Reference: Clio NFTInfo.cpp
+type NftInfoMethod struct{ BaseHandler }
+
+func (m *NftInfoMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
+	var request struct {
+		NFTokenID string `json:"nft_id"`
+		types.LedgerSpecifier
+	}
+
+	if err := ParseParams(params, &request); err != nil {
+		return nil, err
+	}
+
+	nftID, rpcErr := parseNFTokenID(request.NFTokenID)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	index, rpcErr := requireNFTIndex()
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	ledgerIndex := "validated"
+	if request.LedgerIndex != "" {
+		ledgerIndex = request.LedgerIndex.String()
+	}
+
+	result, rpcErr := index.GetNFTInfo(nftID, ledgerIndex)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	response := formatIndexedNFT(result.NFT, result.LedgerIndex)
+	response["validated"] = result.Validated
+	return response, nil
+}
+
+// parseNFTokenID parses the required nft_id parameter.
+func parseNFTokenID(s string) ([32]byte, *types.RpcError) {
+	var nftID [32]byte
+	if s == "" {
+		return nftID, types.RpcErrorMissingField("nft_id")
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 32 {
+		return nftID, types.RpcErrorInvalidParams("Malformed nft_id.")
+	}
+	copy(nftID[:], b)
+	return nftID, nil
+}
+
+// requireNFTIndex returns the NFT index, which needs a relational database.
+func requireNFTIndex() (types.NFTIndex, *types.RpcError) {
+	if types.Services == nil || types.Services.NFTIndex == nil {
+		return nil, types.NewRpcError(types.RpcNOT_SUPPORTED, "notSupported", "notSupported",
+			"NFT index not available. Database not configured.")
+	}
+	return types.Services.NFTIndex, nil
+}
+
+// formatIndexedNFT renders an NFT as nft_info and nfts_by_issuer do.
+func formatIndexedNFT(nft types.IndexedNFT, ledgerIndex uint32) map[string]interface{} {
+	return map[string]interface{}{
+		"nft_id":       FormatHash(nft.NFTokenID[:]),
+		"ledger_index": ledgerIndex,
+		"owner":        nft.Owner,
+		"is_burned":    nft.IsBurned,
+		"flags":        nft.Flags,
+		"transfer_fee": nft.TransferFee,
+		"issuer":       nft.Issuer,
+		"nft_taxon":    nft.Taxon,
+		"nft_serial":   nft.Serial,
+		"uri":          strings.ToUpper(hex.EncodeToString(nft.URI)),
+	}
+}
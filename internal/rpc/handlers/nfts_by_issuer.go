@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+)
+
+// NftsByIssuerMethod handles the nfts_by_issuer RPC method, which lists the
+// NFTs an account issued, optionally of one taxon, in NFTokenID order.
+// Reference: Clio NFTsByIssuer.cpp
+type NftsByIssuerMethod struct{ BaseHandler }
+
+func (m *NftsByIssuerMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
+	var request struct {
+		Issuer   string  `json:"issuer"`
+		NFTTaxon *uint32 `json:"nft_taxon,omitempty"`
+		types.LedgerSpecifier
+		Limit  uint32 `json:"limit,omitempty"`
+		Marker string `json:"marker,omitempty"`
+	}
+
+	if err := ParseParams(params, &request); err != nil {
+		return nil, err
+	}
+
+	if request.Issuer == "" {
+		return nil, types.RpcErrorMissingField("issuer")
+	}
+	if err := ValidateAccount(request.Issuer); err != nil {
+		return nil, err
+	}
+
+	// The marker is the NFTokenID of the last NFT of the previous page.
+	var marker *[32]byte
+	if request.Marker != "" {
+		id, rpcErr := parseNFTokenID(request.Marker)
+		if rpcErr != nil {
+			return nil, types.RpcErrorInvalidParams("Malformed marker.")
+		}
+		marker = &id
+	}
+
+	index, rpcErr := requireNFTIndex()
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	ledgerIndex := "validated"
+	if request.LedgerIndex != "" {
+		ledgerIndex = request.LedgerIndex.String()
+	}
+
+	limit := ClampLimit(request.Limit, LimitNFTsByIssuer, ctx.IsAdmin)
+	result, rpcErr := index.GetNFTsByIssuer(request.Issuer, request.NFTTaxon, ledgerIndex, marker, limit)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	nfts := make([]map[string]interface{}, len(result.NFTs))
+	for i, nft := range result.NFTs {
+		nfts[i] = formatIndexedNFT(nft, result.LedgerIndex)
+	}
+
+	response := map[string]interface{}{
+		"issuer":       request.Issuer,
+		"nfts":         nfts,
+		"ledger_index": result.LedgerIndex,
+		"validated":    result.Validated,
+		"limit":        result.Limit,
+	}
+	if request.NFTTaxon != nil {
+		response["nft_taxon"] = *request.NFTTaxon
+	}
+	if result.Marker != nil {
+		response["marker"] = FormatHash(result.Marker[:])
+	}
+	return response, nil
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec"
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+)
+
+// OwnerInfoMethod handles the owner_info RPC method, a legacy method that
+// lists the offers and trust lines in an account's owner directory in the
+// last validated ledger ("accepted") and in the open ledger ("current").
+// Reference: rippled OwnerInfo.cpp and NetworkOPs::getOwnerInfo, which
+// uses the closed ledger for "accepted".
+type OwnerInfoMethod struct{}
+
+func (m *OwnerInfoMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
+	var request struct {
+		Account string `json:"account,omitempty"`
+		Ident   string `json:"ident,omitempty"`
+	}
+
+	if params != nil {
+		if err := json.Unmarshal(params, &request); err != nil {
+			return nil, types.RpcErrorInvalidParams("Invalid parameters: " + err.Error())
+		}
+	}
+
+	account := request.Account
+	if account == "" {
+		account = request.Ident
+	}
+	if err := ValidateAccount(account); err != nil {
+		return nil, err
+	}
+
+	if types.Services == nil || types.Services.Owners == nil {
+		return nil, types.RpcErrorInternal("Ledger service not available")
+	}
+
+	response := make(map[string]interface{}, 2)
+	for key, ledgerIndex := range map[string]string{"accepted": "validated", "current": "current"} {
+		info, rpcErr := types.Services.Owners.GetOwnerInfo(account, ledgerIndex)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		response[key] = formatOwnerInfo(info)
+	}
+	return response, nil
+}
+
+// formatOwnerInfo builds one ledger's view, which like rippled's has an
+// "offers" or "ripple_lines" member only when there are some.
+func formatOwnerInfo(info *types.OwnerInfoResult) map[string]interface{} {
+	view := make(map[string]interface{})
+	for key, entries := range map[string][]types.OwnedEntry{"offers": info.Offers, "ripple_lines": info.RippleLines} {
+		if len(entries) == 0 {
+			continue
+		}
+		objects := make([]interface{}, 0, len(entries))
+		for _, entry := range entries {
+			decoded, err := binarycodec.Decode(hex.EncodeToString(entry.Data))
+			if err != nil {
+				continue
+			}
+			decoded["index"] = FormatLedgerHash(entry.Index)
+			objects = append(objects, decoded)
+		}
+		view[key] = objects
+	}
+	return view
+}
+
+func (m *OwnerInfoMethod) RequiredRole() types.Role {
+	return types.RoleGuest
+}
+
+func (m *OwnerInfoMethod) SupportedApiVersions() []int {
+	return []int{types.ApiVersion1, types.ApiVersion2, types.ApiVersion3}
+}
+
+func (m *OwnerInfoMethod) RequiredCondition() types.Condition {
+	return types.NeedsCurrentLedger
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 
+	addresscodec "github.com/LeJamon/goXRPLd/codec/addresscodec"
 	"github.com/LeJamon/goXRPLd/internal/rpc/types"
 )
 
@@ -33,51 +34,135 @@ func (m *PeersMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (int
 	}, nil
 }
 
+// peerReservationRequest is the shared parameter shape of the
+// peer_reservations_add / _del methods. Fields stay raw so type errors
+// can be reported with rippled's expected_field wording.
+type peerReservationRequest struct {
+	PublicKey   json.RawMessage `json:"public_key"`
+	Description json.RawMessage `json:"description"`
+}
+
+// parseReservationKey extracts and validates public_key, mirroring
+// rippled Reservations.cpp: missing → missing_field_error, non-string →
+// expected_field_error, undecodable → rpcPUBLIC_MALFORMED.
+func parseReservationKey(raw json.RawMessage) (string, *types.RpcError) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", types.RpcErrorMissingField("public_key")
+	}
+	var key string
+	if err := json.Unmarshal(raw, &key); err != nil {
+		return "", types.RpcErrorExpectedField("public_key", "a string")
+	}
+	decoded, err := addresscodec.DecodeNodePublicKey(key)
+	if err != nil || len(decoded) != 33 {
+		return "", types.NewRpcError(types.RpcPUBLIC_MALFORMED, "publicMalformed", "publicMalformed",
+			"Public key is malformed.")
+	}
+	return key, nil
+}
+
+func parseReservationRequest(params json.RawMessage) (*peerReservationRequest, *types.RpcError) {
+	var request peerReservationRequest
+	if params != nil {
+		if err := json.Unmarshal(params, &request); err != nil {
+			return nil, types.RpcErrorInvalidParams("Invalid parameters: " + err.Error())
+		}
+	}
+	return &request, nil
+}
+
 // PeerReservationsAddMethod handles the peer_reservations_add RPC method.
-// STUB: Returns empty result. Network-only — not needed for standalone mode.
-//
-// TODO [network]: Implement when adding P2P networking layer.
-//   - Requires: PeerReservationTable service
-//   - Reference: rippled Reservations.cpp → context.app.peerReservations()
-//   - Params: public_key (required), description (optional)
-//   - Should add a peer reservation and return previous + current state
+// Reference: rippled Reservations.cpp doPeerReservationsAdd. Inserts or
+// replaces the reservation; the replaced entry, if any, is returned
+// under "previous". Reservations persist through the relational DB and
+// let the peer bypass the inbound slot limit.
 type PeerReservationsAddMethod struct{ AdminHandler }
 
 func (m *PeerReservationsAddMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
-	return map[string]interface{}{
-		"previous": []interface{}{},
-		"current":  []interface{}{},
-	}, nil
+	request, rpcErr := parseReservationRequest(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	key, rpcErr := parseReservationKey(request.PublicKey)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	var description string
+	if len(request.Description) > 0 && string(request.Description) != "null" {
+		if err := json.Unmarshal(request.Description, &description); err != nil {
+			return nil, types.RpcErrorExpectedField("description", "a string")
+		}
+	}
+
+	admin, rpcErr := requirePeerAdmin()
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	previous, err := admin.AddReservation(key, description)
+	if err != nil {
+		return nil, types.RpcErrorInternal("Failed to add peer reservation: " + err.Error())
+	}
+
+	result := map[string]interface{}{}
+	if previous != nil {
+		result["previous"] = previous
+	}
+	return result, nil
 }
 
 // PeerReservationsDelMethod handles the peer_reservations_del RPC method.
-// STUB: Returns empty result. Network-only — not needed for standalone mode.
-//
-// TODO [network]: Implement when adding P2P networking layer.
-//   - Requires: PeerReservationTable service
-//   - Reference: rippled Reservations.cpp
-//   - Params: public_key (required)
-//   - Should remove a peer reservation and return previous + current state
+// Reference: rippled Reservations.cpp doPeerReservationsDel. The removed
+// entry, if any, is returned under "previous".
 type PeerReservationsDelMethod struct{ AdminHandler }
 
 func (m *PeerReservationsDelMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
-	return map[string]interface{}{
-		"previous": []interface{}{},
-		"current":  []interface{}{},
-	}, nil
+	request, rpcErr := parseReservationRequest(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	key, rpcErr := parseReservationKey(request.PublicKey)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	admin, rpcErr := requirePeerAdmin()
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	previous, err := admin.DeleteReservation(key)
+	if err != nil {
+		return nil, types.RpcErrorInternal("Failed to delete peer reservation: " + err.Error())
+	}
+
+	result := map[string]interface{}{}
+	if previous != nil {
+		result["previous"] = previous
+	}
+	return result, nil
 }
 
 // PeerReservationsListMethod handles the peer_reservations_list RPC method.
-// STUB: Returns empty list. Network-only — not needed for standalone mode.
-//
-// TODO [network]: Implement when adding P2P networking layer.
-//   - Requires: PeerReservationTable service
-//   - Reference: rippled Reservations.cpp
-//   - Returns all peer reservations with their public keys and descriptions
+// Reference: rippled Reservations.cpp doPeerReservationsList. Returns an
+// empty list when no overlay is wired (standalone mode).
 type PeerReservationsListMethod struct{ AdminHandler }
 
 func (m *PeerReservationsListMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
+	reservations := []map[string]any{}
+	if types.Services != nil && types.Services.PeerAdmin != nil {
+		reservations = types.Services.PeerAdmin.ReservationsJSON()
+	}
 	return map[string]interface{}{
-		"reservations": []interface{}{},
+		"reservations": reservations,
 	}, nil
 }
+
+// requirePeerAdmin returns the overlay admin facet, or notSynced when
+// the node runs without networking (rippled refuses these in
+// standalone mode because there is no overlay to act on).
+func requirePeerAdmin() (types.PeerAdmin, *types.RpcError) {
+	if types.Services == nil || types.Services.PeerAdmin == nil {
+		return nil, types.NewRpcError(types.RpcNOT_SYNCED, "notSynced", "notSynced",
+			"Peer management is not available in standalone mode")
+	}
+	return types.Services.PeerAdmin, nil
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"strconv"
+	"strings"
 
 	"github.com/LeJamon/goXRPLd/internal/ledger/state"
 	"github.com/LeJamon/goXRPLd/internal/rpc/types"
@@ -21,6 +23,7 @@ type ripplePathFindRequest struct {
 		Currency string `json:"currency"`
 		Issuer   string `json:"issuer,omitempty"`
 	} `json:"source_currencies,omitempty"`
+	Domain json.RawMessage `json:"domain,omitempty"`
 }
 
 // ripplePathFindResponse represents the ripple_path_find RPC response.
@@ -32,6 +35,7 @@ type ripplePathFindResponse struct {
 	DestinationCurrencies []string              `json:"destination_currencies"`
 	FullReply             bool                  `json:"full_reply"`
 	SourceAccount         string                `json:"source_account"`
+	Domain                string                `json:"domain,omitempty"`
 }
 
 type pathAlternativeJSON struct {
@@ -103,6 +107,13 @@ func (m *RipplePathFindMethod) Handle(ctx *types.RpcContext, params json.RawMess
 		srcCurrencies = append(srcCurrencies, issue)
 	}
 
+	// Optional permissioned domain: only that domain's order books are
+	// searched. Reference: rippled PathRequest::parseJson (jss::domain)
+	domain, rpcErr := types.ParseDomainParam(request.Domain)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
 	view, err := types.Services.Ledger.GetClosedLedgerView()
 	if err != nil {
 		return nil, types.NewRpcError(types.RpcNO_CURRENT, "noCurrent", "noCurrent",
@@ -120,6 +131,7 @@ func (m *RipplePathFindMethod) Handle(ctx *types.RpcContext, params json.RawMess
 
 	// Run pathfinding
 	pr := pathfinder.NewPathRequest(srcAccount, dstAccount, dstAmount, sendMax, srcCurrencies, false)
+	pr.SetDomain(domain)
 	result := pr.Execute(view)
 
 	// Build response matching rippled PathRequest::doUpdate() format.
@@ -131,6 +143,9 @@ func (m *RipplePathFindMethod) Handle(ctx *types.RpcContext, params json.RawMess
 		FullReply:             true, // rippled sets !fast; legacy path always does a full reply
 		SourceAccount:         request.SourceAccount,
 	}
+	if domain != nil {
+		response.Domain = strings.ToUpper(hex.EncodeToString(domain[:]))
+	}
 
 	for _, alt := range result.Alternatives {
 		jAlt := pathAlternativeJSON{
@@ -173,6 +188,13 @@ func formatAmountJSON(amt state.Amount) interface{} {
 	if amt.IsNative() {
 		return amt.Value()
 	}
+	if amt.IsMPT() {
+		raw, _ := amt.MPTRaw()
+		return map[string]string{
+			"mpt_issuance_id": amt.MPTIssuanceID(),
+			"value":           strconv.FormatInt(raw, 10),
+		}
+	}
 	return map[string]string{
 		"currency": amt.Currency,
 		"issuer":   amt.Issuer,
@@ -194,11 +216,22 @@ func parsePathFindAmount(raw json.RawMessage) state.Amount {
 		Currency string `json:"currency"`
 		Issuer   string `json:"issuer"`
 		Value    string `json:"value"`
+
+		MPTIssuanceID string `json:"mpt_issuance_id"`
 	}
 	if err := json.Unmarshal(raw, &iou); err != nil {
 		return state.NewXRPAmountFromInt(0)
 	}
 
+	if iou.MPTIssuanceID != "" {
+		issuer, ok := state.MPTIssuerFromID(iou.MPTIssuanceID)
+		raw, err := strconv.ParseInt(iou.Value, 10, 64)
+		if !ok || err != nil {
+			return state.NewXRPAmountFromInt(0)
+		}
+		return state.NewMPTAmountWithIssuanceID(raw, issuer, iou.MPTIssuanceID)
+	}
+
 	if iou.Currency == "XRP" || iou.Currency == "" {
 		drops, _ := strconv.ParseInt(iou.Value, 10, 64)
 		return state.NewXRPAmountFromInt(drops)
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+	"github.com/LeJamon/goXRPLd/protocol"
 	"github.com/LeJamon/goXRPLd/version"
 )
 
@@ -36,7 +37,7 @@ func (m *ServerInfoMethod) Handle(ctx *types.RpcContext, params json.RawMessage)
 		return nil, err
 	}
 
-	info := buildServerInfo(true)
+	info := buildServerInfo(true, ctx.IsAdmin)
 
 	response := map[string]interface{}{
 		"info": info,
@@ -48,7 +49,8 @@ func (m *ServerInfoMethod) Handle(ctx *types.RpcContext, params json.RawMessage)
 // buildServerInfo constructs the info/state object.
 // When human is true it produces the server_info format (XRP decimals, converge_time_s, hostid).
 // When human is false it produces the server_state format (drops integers, converge_time, load_base, etc.).
-func buildServerInfo(human bool) map[string]interface{} {
+// admin adds the per-source load factors rippled reserves for admin callers.
+func buildServerInfo(human, admin bool) map[string]interface{} {
 	serverInfo := types.Services.Ledger.GetServerInfo()
 	baseFee, reserveBase, reserveIncrement := types.Services.Ledger.GetCurrentFees()
 
@@ -151,13 +153,28 @@ func buildServerInfo(human bool) map[string]interface{} {
 		}
 	}
 
-	// load_factor: human mode is float (loadFactor/loadBase), machine mode has integers
+	// load_factor: human mode is float (loadFactor/loadBase), machine mode has integers.
+	// The server factor is LoadFeeTrack::getLoadFactor — the max of the
+	// local and cluster fees; only the cluster fee is tracked so far.
+	loadFactorServer := uint32(loadBase)
+	clusterFee := getClusterLoadFee()
+	if clusterFee > loadFactorServer {
+		loadFactorServer = clusterFee
+	}
 	if human {
-		info["load_factor"] = 1.0 // TODO: compute from fee tracker
+		info["load_factor"] = float64(loadFactorServer) / loadBase
+		if loadFactorServer != loadBase {
+			info["load_factor_server"] = float64(loadFactorServer) / loadBase
+		}
+		// NetworkOPs.cpp getServerInfo: per-source factors are admin-only
+		// and omitted when equal to the base.
+		if admin && clusterFee != 0 && clusterFee != loadBase {
+			info["load_factor_cluster"] = float64(clusterFee) / loadBase
+		}
 	} else {
-		info["load_base"] = 256                  // TODO: get from fee tracker (rippled default is 256)
-		info["load_factor"] = 256                // TODO: get from fee tracker
-		info["load_factor_server"] = 256         // TODO: get from fee tracker
+		info["load_base"] = loadBase
+		info["load_factor"] = loadFactorServer
+		info["load_factor_server"] = loadFactorServer
 		info["load_factor_fee_escalation"] = 256 // TODO: get from TxQ metrics
 		info["load_factor_fee_queue"] = 256      // TODO: get from TxQ metrics
 		info["load_factor_fee_reference"] = 256  // TODO: get from TxQ metrics
@@ -230,6 +247,16 @@ func buildServerInfo(human bool) map[string]interface{} {
 	return info
 }
 
+// loadBase is LoadFeeTrack::lftNormalFee, the unloaded reference level.
+const loadBase = protocol.LoadFeeBase
+
+func getClusterLoadFee() uint32 {
+	if types.Services.ClusterLoadFee != nil {
+		return types.Services.ClusterLoadFee()
+	}
+	return 0
+}
+
 func getPeerCount() int {
 	if types.Services.PeerCount != nil {
 		return types.Services.PeerCount()
@@ -15,7 +15,7 @@ func (m *ServerStateMethod) Handle(ctx *types.RpcContext, params json.RawMessage
 		return nil, err
 	}
 
-	state := buildServerInfo(false)
+	state := buildServerInfo(false, ctx.IsAdmin)
 
 	response := map[string]interface{}{
 		"state": state,
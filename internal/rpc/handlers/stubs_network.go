@@ -3,6 +3,8 @@ package handlers
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"strconv"
 
 	"github.com/LeJamon/goXRPLd/internal/rpc/types"
 )
@@ -95,12 +97,10 @@ func (m *TxReduceRelayMethod) RequiredCondition() types.Condition {
 }
 
 // ConnectMethod handles the connect RPC method.
-// STUB: Returns message without actually connecting. Network-only.
-//
-// TODO [network]: Implement when adding P2P networking layer.
-//   - Reference: rippled Connect.cpp → context.app.overlay().connect()
-//   - Params: ip (required), port (optional, default 51235)
-//   - Should initiate an outbound peer connection
+// Reference: rippled Connect.cpp → context.app.overlay().connect().
+// Params: ip (required), port (optional, default 51235). Unlike rippled,
+// which queues the dial and returns immediately, the overlay dial runs
+// synchronously so the response reports whether the handshake succeeded.
 type ConnectMethod struct{ AdminHandler }
 
 func (m *ConnectMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
@@ -123,7 +123,7 @@ func (m *ConnectMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (i
 		return nil, types.RpcErrorInternal("Ledger service not available")
 	}
 
-	if types.Services.Ledger.IsStandalone() {
+	if types.Services.Ledger.IsStandalone() || types.Services.PeerAdmin == nil {
 		return nil, types.NewRpcError(types.RpcNOT_SYNCED, "notSynced", "notSynced",
 			"Cannot connect to peers in standalone mode")
 	}
@@ -133,18 +133,25 @@ func (m *ConnectMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (i
 		port = 51235
 	}
 
-	return map[string]interface{}{
+	addr := net.JoinHostPort(request.IP, strconv.Itoa(port))
+	result := map[string]interface{}{
 		"message": fmt.Sprintf("attempting connection to IP:%s port:%d", request.IP, port),
-	}, nil
+	}
+	if err := types.Services.PeerAdmin.Connect(addr); err != nil {
+		result["connected"] = false
+		result["error_message"] = err.Error()
+		return result, nil
+	}
+	result["connected"] = true
+	return result, nil
 }
 
 // UnlListMethod handles the unl_list RPC method.
-// STUB: Returns empty list. Network-only — tracks negative UNL.
-//
-// TODO [network]: Implement when adding UNL/consensus support.
-//   - Reference: rippled UNLList.cpp
-//   - Returns the current Unique Node List (trusted validators)
-//   - In standalone mode, there is no UNL
+// Reference: rippled UNLList.cpp — one entry per listed validator with
+// "pubkey_validator" and "trusted". goXRPL additionally reports
+// "negative_unl" so operators can see which trusted validators the
+// validated ledger's ltNEGATIVE_UNL currently disables. Empty in
+// standalone mode, where there is no UNL.
 type UnlListMethod struct{ AdminHandler }
 
 func (m *UnlListMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
@@ -152,18 +159,27 @@ func (m *UnlListMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (i
 		return nil, types.RpcErrorInternal("Ledger service not available")
 	}
 
+	unl := []interface{}{}
+	if types.Services.UNLList != nil {
+		for _, entry := range types.Services.UNLList() {
+			unl = append(unl, map[string]interface{}{
+				"pubkey_validator": entry.PublicKey,
+				"trusted":          entry.Trusted,
+				"negative_unl":     entry.NegativeUNL,
+			})
+		}
+	}
+
 	return map[string]interface{}{
-		"unl": []interface{}{},
+		"unl": unl,
 	}, nil
 }
 
 // BlackListMethod handles the black_list (blacklist) RPC method.
-// STUB: Returns empty list. Network-only — manages IP blacklisting.
-//
-// TODO [network]: Implement when adding P2P networking layer.
-//   - Reference: rippled BlackList.cpp
-//   - Returns/manages the peer IP blacklist
-//   - Params: threshold (int) — auto-blacklist peers above this score
+// Reference: rippled BlackList.cpp → Resource::Manager::getJson. Lists
+// peers whose bad-data balance is at or above threshold (default
+// 5000, rippled's warningThreshold) together with endpoints recently
+// dropped for exceeding the eviction threshold, keyed by address.
 type BlackListMethod struct{ AdminHandler }
 
 func (m *BlackListMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
@@ -171,7 +187,29 @@ func (m *BlackListMethod) Handle(ctx *types.RpcContext, params json.RawMessage)
 		return nil, types.RpcErrorInternal("Ledger service not available")
 	}
 
+	var request struct {
+		Threshold *int `json:"threshold,omitempty"`
+	}
+	if params != nil {
+		if err := json.Unmarshal(params, &request); err != nil {
+			return nil, types.RpcErrorInvalidParams("Invalid parameters: " + err.Error())
+		}
+	}
+	threshold := blacklistWarningThreshold
+	if request.Threshold != nil {
+		threshold = *request.Threshold
+	}
+
+	blacklist := map[string]any{}
+	if types.Services.PeerAdmin != nil {
+		blacklist = types.Services.PeerAdmin.ResourceJSON(threshold)
+	}
+
 	return map[string]interface{}{
-		"blacklist": []interface{}{},
+		"blacklist": blacklist,
 	}, nil
 }
+
+// blacklistWarningThreshold mirrors rippled's Resource::warningThreshold,
+// the cut-off Resource::Manager::getJson() uses when none is given.
+const blacklistWarningThreshold = 5000
@@ -6,21 +6,41 @@ import (
 	"github.com/LeJamon/goXRPLd/internal/rpc/types"
 )
 
-// SubscribeMethod handles the subscribe RPC command (WebSocket only).
-// STUB over HTTP: Returns notSupported. The real implementation is in websocket.go.
-//
-// TODO [websocket]: This HTTP stub is correct — subscribe requires a persistent
-//
-//	WebSocket connection. The WebSocket server (rpc/websocket.go) already has
-//	a working subscription system. This HTTP handler exists only so the method
-//	is registered and returns a meaningful error over HTTP.
-//	- Reference: rippled Subscribe.cpp
-//	- Streams: ledger, transactions, transactions_proposed, peer_status,
-//	  consensus, server, validations, manifests, book (order book)
-//	- Account subscriptions: accounts, accounts_proposed
+// SubscribeMethod handles the subscribe RPC command. Over HTTP only
+// server-to-server subscriptions ("url", admin only) are supported; their
+// events are posted to the URL. Everything else needs the persistent
+// connection handled in websocket.go.
+// Reference: rippled Subscribe.cpp
 type SubscribeMethod struct{ BaseHandler }
 
 func (m *SubscribeMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
-	return nil, types.NewRpcError(types.RpcNOT_SUPPORTED, "notSupported", "notSupported",
-		"subscribe is only available via WebSocket")
+	var request types.SubscriptionRequest
+	if err := ParseParams(params, &request); err != nil {
+		return nil, err
+	}
+	if request.URL == "" {
+		return nil, types.NewRpcError(types.RpcNOT_SUPPORTED, "notSupported", "notSupported",
+			"subscribe is only available via WebSocket")
+	}
+	subs, err := urlSubscriptions(ctx, "subscribe")
+	if err != nil {
+		return nil, err
+	}
+	if err := subs.SubscribeURL(request); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{}, nil
+}
+
+// urlSubscriptions checks that the caller may manage URL subscriptions
+// and returns the service backing them.
+func urlSubscriptions(ctx *types.RpcContext, method string) (types.URLSubscriptions, *types.RpcError) {
+	if ctx.Role != types.RoleAdmin {
+		return nil, types.RpcErrorNoPermission(method)
+	}
+	if types.Services == nil || types.Services.URLSubscriptions == nil {
+		return nil, types.NewRpcError(types.RpcNOT_SUPPORTED, "notSupported", "notSupported",
+			"URL subscriptions are not available")
+	}
+	return types.Services.URLSubscriptions, nil
 }
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+)
+
+// TxStatusMethod handles the tx_status RPC method, which reports where a
+// transaction submitted to this server is: held for retry, in the open
+// ledger, in a closed or validated ledger, or expired or dropped. Unlike
+// tx it knows of transactions that have not reached a ledger, but only
+// those submitted here. A transaction evicted from the transaction queue
+// is not reported as dropped: it stays queued until it expires or another
+// transaction uses its sequence. It has no rippled counterpart.
+type TxStatusMethod struct{ BaseHandler }
+
+func (m *TxStatusMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
+	var request struct {
+		types.TransactionParam
+	}
+
+	if err := ParseParams(params, &request); err != nil {
+		return nil, err
+	}
+
+	if request.Transaction == "" {
+		return nil, types.RpcErrorMissingField("transaction")
+	}
+	var hash [32]byte
+	b, err := hex.DecodeString(request.Transaction)
+	if err != nil || len(b) != len(hash) {
+		return nil, types.RpcErrorInvalidField("transaction")
+	}
+	copy(hash[:], b)
+
+	if types.Services == nil || types.Services.Submissions == nil {
+		return nil, types.RpcErrorInternal("Ledger service not available")
+	}
+
+	sub, ok := types.Services.Submissions.GetSubmission(hash)
+	if !ok {
+		return nil, types.RpcErrorTxnNotFound("Transaction not submitted to this server")
+	}
+	return SubmissionJSON(sub), nil
+}
+
+// SubmissionJSON is the tx_status view of a submission, which the
+// submissions stream also sends. "ledger_index" appears once a ledger
+// includes the transaction and "last_ledger_sequence" if it has one.
+func SubmissionJSON(sub *types.Submission) map[string]interface{} {
+	result := map[string]interface{}{
+		"hash":                   FormatHash(sub.Hash[:]),
+		"account":                sub.Account,
+		"sequence":               sub.Sequence,
+		"status":                 sub.State,
+		"final":                  sub.Final,
+		"validated":              sub.State == "validated",
+		"engine_result":          sub.EngineResult,
+		"submitted_ledger_index": sub.SubmittedLedger,
+		"relayed":                sub.Relayed,
+		"attempts":               sub.Attempts,
+	}
+	if sub.LastLedgerSequence != 0 {
+		result["last_ledger_sequence"] = sub.LastLedgerSequence
+	}
+	if sub.LedgerIndex != 0 {
+		result["ledger_index"] = sub.LedgerIndex
+	}
+	return result
+}
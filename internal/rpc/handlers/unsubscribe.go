@@ -6,17 +6,26 @@ import (
 	"github.com/LeJamon/goXRPLd/internal/rpc/types"
 )
 
-// UnsubscribeMethod handles the unsubscribe RPC command (WebSocket only).
-// STUB over HTTP: Returns notSupported. The real implementation is in websocket.go.
-//
-// TODO [websocket]: Same as subscribe — this HTTP stub is correct.
-//
-//	The WebSocket server handles actual unsubscriptions.
-//	- Reference: rippled Unsubscribe.cpp
-//	- Removes subscriptions created by subscribe command
+// UnsubscribeMethod handles the unsubscribe RPC command. Like subscribe,
+// over HTTP it only removes server-to-server subscriptions ("url").
+// Reference: rippled Unsubscribe.cpp
 type UnsubscribeMethod struct{ BaseHandler }
 
 func (m *UnsubscribeMethod) Handle(ctx *types.RpcContext, params json.RawMessage) (interface{}, *types.RpcError) {
-	return nil, types.NewRpcError(types.RpcNOT_SUPPORTED, "notSupported", "notSupported",
-		"unsubscribe is only available via WebSocket")
+	var request types.SubscriptionRequest
+	if err := ParseParams(params, &request); err != nil {
+		return nil, err
+	}
+	if request.URL == "" {
+		return nil, types.NewRpcError(types.RpcNOT_SUPPORTED, "notSupported", "notSupported",
+			"unsubscribe is only available via WebSocket")
+	}
+	subs, err := urlSubscriptions(ctx, "unsubscribe")
+	if err != nil {
+		return nil, err
+	}
+	if err := subs.UnsubscribeURL(request); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{}, nil
 }
@@ -18,7 +18,7 @@ import (
 // LedgerServiceAdapter adapts the ledger service to the RPC LedgerService interface
 type LedgerServiceAdapter struct {
 	svc           *service.Service
-	txBroadcaster func(txBlob []byte) // called after successful submit to relay tx to peers
+	txBroadcaster func(txBlob []byte) int // called after successful submit to relay tx to peers; returns how many peers it was sent to
 }
 
 // NewLedgerServiceAdapter creates a new adapter
@@ -27,8 +27,9 @@ func NewLedgerServiceAdapter(svc *service.Service) *LedgerServiceAdapter {
 }
 
 // SetTxBroadcaster sets the callback for relaying submitted transactions to P2P peers.
+// The callback returns the number of peers the transaction was relayed to.
 // Called during server startup once the overlay is available.
-func (a *LedgerServiceAdapter) SetTxBroadcaster(fn func(txBlob []byte)) {
+func (a *LedgerServiceAdapter) SetTxBroadcaster(fn func(txBlob []byte) int) {
 	a.txBroadcaster = fn
 }
 
@@ -217,7 +218,7 @@ func (a *LedgerServiceAdapter) SubmitTransaction(txJSON []byte, txBlobHex ...str
 	// Relay the transaction to P2P peers if successfully applied
 	broadcast := false
 	if result.Applied && rawBlob != nil && a.txBroadcaster != nil {
-		a.txBroadcaster(rawBlob)
+		a.svc.RecordRelay(result.Hash, a.txBroadcaster(rawBlob))
 		broadcast = true
 	}
 
@@ -227,8 +228,8 @@ func (a *LedgerServiceAdapter) SubmitTransaction(txJSON []byte, txBlobHex ...str
 		EngineResultMessage: result.Message,
 		Applied:             result.Applied,
 		Broadcast:           broadcast,
-		Queued:              false,          // Not queued when applied directly
-		Kept:                result.Applied, // If applied, it is kept
+		Queued:              result.Queued,
+		Kept:                result.Applied || result.Queued, // Kept to re-apply after the next close
 		Fee:                 result.Fee,
 		CurrentLedger:       result.CurrentLedger,
 		ValidatedLedger:     result.ValidatedLedger,
@@ -362,8 +363,9 @@ func (a *LedgerServiceAdapter) GetAccountOffers(account string, ledgerIndex stri
 	}, nil
 }
 
-// GetBookOffers retrieves offers from an order book
-func (a *LedgerServiceAdapter) GetBookOffers(takerGets, takerPays types.Amount, ledgerIndex string, limit uint32) (*types.BookOffersResult, error) {
+// GetBookOffers retrieves offers from an order book, optionally the book
+// of a permissioned domain
+func (a *LedgerServiceAdapter) GetBookOffers(takerGets, takerPays types.Amount, domain *[32]byte, ledgerIndex string, limit uint32) (*types.BookOffersResult, error) {
 	// Convert RPC types.Amount to tx.Amount
 	var txTakerGets, txTakerPays tx.Amount
 	if takerGets.Currency == "" || takerGets.Currency == "XRP" {
@@ -377,7 +379,7 @@ func (a *LedgerServiceAdapter) GetBookOffers(takerGets, takerPays types.Amount,
 		txTakerPays = tx.NewIssuedAmountFromFloat64(0, takerPays.Currency, takerPays.Issuer)
 	}
 
-	result, err := a.svc.GetBookOffers(txTakerGets, txTakerPays, ledgerIndex, limit)
+	result, err := a.svc.GetBookOffers(txTakerGets, txTakerPays, domain, ledgerIndex, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -393,6 +395,7 @@ func (a *LedgerServiceAdapter) GetBookOffers(takerGets, takerPays types.Amount,
 			LedgerEntryType: offer.LedgerEntryType,
 			OwnerNode:       offer.OwnerNode,
 			Sequence:        offer.Sequence,
+			DomainID:        offer.DomainID,
 			TakerGets:       offer.TakerGets,
 			TakerPays:       offer.TakerPays,
 			Index:           offer.Index,
@@ -412,7 +415,7 @@ func (a *LedgerServiceAdapter) GetBookOffers(takerGets, takerPays types.Amount,
 }
 
 // GetAccountTransactions retrieves transaction history for an account
-func (a *LedgerServiceAdapter) GetAccountTransactions(account string, ledgerMin, ledgerMax int64, limit uint32, marker *types.AccountTxMarker, forward bool) (*types.AccountTxResult, error) {
+func (a *LedgerServiceAdapter) GetAccountTransactions(account string, ledgerMin, ledgerMax int64, limit uint32, marker *types.AccountTxMarker, forward bool, filter types.AccountTxFilter) (*types.AccountTxResult, error) {
 	// Convert RPC marker to service marker
 	var svcMarker *relationaldb.AccountTxMarker
 	if marker != nil {
@@ -422,7 +425,15 @@ func (a *LedgerServiceAdapter) GetAccountTransactions(account string, ledgerMin,
 		}
 	}
 
-	result, err := a.svc.GetAccountTransactions(account, ledgerMin, ledgerMax, limit, svcMarker, forward)
+	svcFilter := relationaldb.AccountTxFilter{TxType: filter.TxType}
+	switch filter.Direction {
+	case types.AccountTxOutgoing:
+		svcFilter.Direction = relationaldb.AccountTxOutgoing
+	case types.AccountTxIncoming:
+		svcFilter.Direction = relationaldb.AccountTxIncoming
+	}
+
+	result, err := a.svc.GetAccountTransactions(account, ledgerMin, ledgerMax, limit, svcMarker, forward, svcFilter)
 	if err != nil {
 		return nil, err
 	}
@@ -764,6 +775,8 @@ func (a *LedgerServiceAdapter) GetGatewayBalances(account string, hotWallets []s
 		FrozenBalances: frozenBalances,
 		Assets:         assets,
 		Locked:         result.Locked,
+		MPTObligations: result.MPTObligations,
+		MPTLocked:      result.MPTLocked,
 		LedgerIndex:    result.LedgerIndex,
 		LedgerHash:     result.LedgerHash,
 		Validated:      result.Validated,
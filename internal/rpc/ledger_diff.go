@@ -0,0 +1,76 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"errors"
+	"strconv"
+
+	"github.com/LeJamon/goXRPLd/internal/ledger/service"
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+)
+
+var _ types.LedgerDiffer = (*LedgerServiceAdapter)(nil)
+
+// GetLedgerDiff returns a page of the state differences between two ledgers
+func (a *LedgerServiceAdapter) GetLedgerDiff(base, desired types.LedgerSpecifier, entryType string, marker *[32]byte, limit uint32) (*types.LedgerDiffResult, *types.RpcError) {
+	baseIndex, rpcErr := a.ledgerIndexOf(base)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	desiredIndex, rpcErr := a.ledgerIndexOf(desired)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	result, err := a.svc.GetLedgerDiff(baseIndex, desiredIndex, entryType, marker, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrLedgerNotFound), errors.Is(err, service.ErrNoOpenLedger):
+			return nil, types.RpcErrorLgrNotFound("ledgerNotFound")
+		case err.Error() == "invalid ledger_index":
+			return nil, types.RpcErrorInvalidParams("ledgerIndexMalformed")
+		}
+		return nil, types.RpcErrorInternal(err.Error())
+	}
+
+	entries := make([]types.LedgerDiffEntry, len(result.Entries))
+	for i, e := range result.Entries {
+		entries[i] = types.LedgerDiffEntry{
+			Index:  e.Index,
+			Status: e.Status,
+			Before: e.Before,
+			After:  e.After,
+		}
+	}
+	return &types.LedgerDiffResult{
+		BaseLedgerIndex:    result.BaseLedgerIndex,
+		BaseLedgerHash:     result.BaseLedgerHash,
+		DesiredLedgerIndex: result.DesiredLedgerIndex,
+		DesiredLedgerHash:  result.DesiredLedgerHash,
+		Validated:          result.Validated,
+		Entries:            entries,
+		Limit:              result.Limit,
+		Marker:             result.Marker,
+	}, nil
+}
+
+// ledgerIndexOf resolves a ledger specifier to the ledger_index the
+// service queries take. A hash takes precedence over an index; neither
+// means the validated ledger.
+func (a *LedgerServiceAdapter) ledgerIndexOf(spec types.LedgerSpecifier) (string, *types.RpcError) {
+	if spec.LedgerHash != "" {
+		b, err := hex.DecodeString(spec.LedgerHash)
+		if err != nil || len(b) != 32 {
+			return "", types.RpcErrorInvalidParams("ledgerHashMalformed")
+		}
+		l, err := a.svc.GetLedgerByHash([32]byte(b))
+		if err != nil {
+			return "", types.RpcErrorLgrNotFound("ledgerNotFound")
+		}
+		return strconv.FormatUint(uint64(l.Sequence()), 10), nil
+	}
+	if spec.LedgerIndex != "" {
+		return spec.LedgerIndex.String(), nil
+	}
+	return "validated", nil
+}
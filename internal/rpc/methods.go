@@ -68,6 +68,15 @@ func (s *Server) registerAllMethods() {
 	s.registry.Register("nft_buy_offers", &handlers.NftBuyOffersMethod{})
 	s.registry.Register("nft_sell_offers", &handlers.NftSellOffersMethod{})
 
+	// NFT index methods (Clio), served from the relational database
+	s.registry.Register("nft_info", &handlers.NftInfoMethod{})
+	s.registry.Register("nft_history", &handlers.NftHistoryMethod{})
+	s.registry.Register("nfts_by_issuer", &handlers.NftsByIssuerMethod{})
+
+	// MPT query methods
+	s.registry.Register("mpt_holders", &handlers.MptHoldersMethod{})
+	s.registry.Register("account_mpt_issuances", &handlers.AccountMptIssuancesMethod{})
+
 	// Standalone mode methods
 	s.registry.Register("ledger_accept", &handlers.LedgerAcceptMethod{})
 
@@ -92,6 +101,7 @@ func (s *Server) registerAllMethods() {
 	s.registry.Register("ledger_header", &handlers.LedgerHeaderMethod{})
 	s.registry.Register("ledger_request", &handlers.LedgerRequestMethod{})
 	s.registry.Register("ledger_cleaner", &handlers.LedgerCleanerMethod{})
+	s.registry.Register("ledger_diff", &handlers.LedgerDiffMethod{})
 
 	// Account Methods
 	s.registry.Register("owner_info", &handlers.OwnerInfoMethod{})
@@ -99,6 +109,7 @@ func (s *Server) registerAllMethods() {
 	// Transaction Methods
 	s.registry.Register("simulate", &handlers.SimulateMethod{})
 	s.registry.Register("tx_reduce_relay", &handlers.TxReduceRelayMethod{})
+	s.registry.Register("tx_status", &handlers.TxStatusMethod{})
 
 	// Validator Methods
 	s.registry.Register("validator_info", &handlers.ValidatorInfoMethod{})
@@ -2,9 +2,12 @@ package rpc
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"strings"
 	"testing"
 
+	"github.com/LeJamon/goXRPLd/codec/binarycodec"
 	"github.com/LeJamon/goXRPLd/internal/rpc/handlers"
 	"github.com/LeJamon/goXRPLd/internal/rpc/types"
 	"github.com/stretchr/testify/assert"
@@ -126,20 +129,61 @@ func TestOwnerInfoMethod(t *testing.T) {
 		assert.Equal(t, types.RpcINVALID_PARAMS, rpcErr.Code)
 	})
 
-	t.Run("Valid account returns not implemented error (stub)", func(t *testing.T) {
-		// owner_info is a stub - it returns RpcNOT_IMPL until NetworkOPs.GetOwnerInfo is implemented
+	t.Run("Malformed account returns error", func(t *testing.T) {
 		ctx := &types.RpcContext{
 			Context:    context.Background(),
 			Role:       types.RoleGuest,
 			ApiVersion: types.ApiVersion1,
 		}
 
-		params := json.RawMessage(`{"account": "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"}`)
+		params := json.RawMessage(`{"ident": "notAnAccount"}`)
 		result, rpcErr := method.Handle(ctx, params)
 
 		assert.Nil(t, result)
 		require.NotNil(t, rpcErr)
-		assert.Equal(t, types.RpcNOT_IMPL, rpcErr.Code)
+		assert.Equal(t, types.RpcACT_MALFORMED, rpcErr.Code)
+	})
+
+	t.Run("Lists accepted and current owner objects", func(t *testing.T) {
+		owners := &mockOwnerInfo{results: map[string]*types.OwnerInfoResult{
+			"validated": {},
+			"current": {
+				Offers:      []types.OwnedEntry{{Index: [32]byte{0xAA}, Data: encodeLedgerEntry(t, map[string]interface{}{"LedgerEntryType": "Offer", "Sequence": 7})}},
+				RippleLines: []types.OwnedEntry{{Index: [32]byte{0xBB}, Data: encodeLedgerEntry(t, map[string]interface{}{"LedgerEntryType": "RippleState", "Flags": 0})}},
+			},
+		}}
+		types.Services.Owners = owners
+		defer func() { types.Services.Owners = nil }()
+
+		ctx := &types.RpcContext{
+			Context:    context.Background(),
+			Role:       types.RoleGuest,
+			ApiVersion: types.ApiVersion1,
+		}
+
+		params := json.RawMessage(`{"ident": "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"}`)
+		result, rpcErr := method.Handle(ctx, params)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", owners.account)
+
+		resp := result.(map[string]interface{})
+		assert.Empty(t, resp["accepted"])
+		current := resp["current"].(map[string]interface{})
+		require.Len(t, current["offers"], 1)
+		offer := current["offers"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "Offer", offer["LedgerEntryType"])
+		assert.Equal(t, "AA"+strings.Repeat("00", 31), offer["index"])
+		require.Len(t, current["ripple_lines"], 1)
+	})
+
+	t.Run("Ledger errors are returned", func(t *testing.T) {
+		types.Services.Owners = &mockOwnerInfo{}
+		defer func() { types.Services.Owners = nil }()
+
+		params := json.RawMessage(`{"account": "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"}`)
+		_, rpcErr := method.Handle(&types.RpcContext{Context: context.Background()}, params)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, types.RpcLGR_NOT_FOUND, rpcErr.Code)
 	})
 
 	t.Run("RequiredRole is Guest", func(t *testing.T) {
@@ -147,6 +191,31 @@ func TestOwnerInfoMethod(t *testing.T) {
 	})
 }
 
+// mockOwnerInfo returns results by ledger index, and lgrNotFound for
+// ledgers it has none for.
+type mockOwnerInfo struct {
+	account string
+	results map[string]*types.OwnerInfoResult
+}
+
+func (m *mockOwnerInfo) GetOwnerInfo(account, ledgerIndex string) (*types.OwnerInfoResult, *types.RpcError) {
+	m.account = account
+	if result, ok := m.results[ledgerIndex]; ok {
+		return result, nil
+	}
+	return nil, types.RpcErrorLgrNotFound("ledgerNotFound")
+}
+
+// encodeLedgerEntry serializes a ledger entry given as JSON.
+func encodeLedgerEntry(t *testing.T, entry map[string]interface{}) []byte {
+	t.Helper()
+	encoded, err := binarycodec.Encode(entry)
+	require.NoError(t, err)
+	data, err := hex.DecodeString(encoded)
+	require.NoError(t, err)
+	return data
+}
+
 // LedgerHeaderMethod Tests
 // Reference: rippled/src/test/rpc/LedgerHeader_test.cpp
 
@@ -264,33 +333,164 @@ func TestLedgerCleanerMethod(t *testing.T) {
 
 // LedgerDiffMethod Tests
 
+// mockLedgerDiffer records the last ledger_diff query and returns result.
+type mockLedgerDiffer struct {
+	base, desired types.LedgerSpecifier
+	entryType     string
+	marker        *[32]byte
+	limit         uint32
+	result        *types.LedgerDiffResult
+}
+
+func (m *mockLedgerDiffer) GetLedgerDiff(base, desired types.LedgerSpecifier, entryType string, marker *[32]byte, limit uint32) (*types.LedgerDiffResult, *types.RpcError) {
+	m.base, m.desired, m.entryType, m.marker, m.limit = base, desired, entryType, marker, limit
+	return m.result, nil
+}
+
 func TestLedgerDiffMethod(t *testing.T) {
 	mock := newMockLedgerServiceMissingMethods()
 	cleanup := setupTestServicesMissingMethods(mock)
 	defer cleanup()
 
-	method := &handlers.LedgerDiffMethod{}
+	next := [32]byte{0xAB}
+	differ := &mockLedgerDiffer{result: &types.LedgerDiffResult{
+		BaseLedgerIndex:    5,
+		DesiredLedgerIndex: 9,
+		Entries: []types.LedgerDiffEntry{
+			{Index: [32]byte{1}, Status: "created", After: []byte{0xDE, 0xAD}},
+			{Index: [32]byte{2}, Status: "modified", Before: []byte{0x01}, After: []byte{0x02}},
+			{Index: [32]byte{3}, Status: "deleted", Before: []byte{0xBE, 0xEF}},
+		},
+		Limit:  3,
+		Marker: &next,
+	}}
+	types.Services.LedgerDiffs = differ
 
-	t.Run("Returns gRPC only error", func(t *testing.T) {
-		ctx := &types.RpcContext{
-			Context:    context.Background(),
-			Role:       types.RoleAdmin,
-			ApiVersion: types.ApiVersion1,
-		}
+	method := &handlers.LedgerDiffMethod{}
+	ctx := &types.RpcContext{
+		Context:    context.Background(),
+		Role:       types.RoleAdmin,
+		ApiVersion: types.ApiVersion1,
+		IsAdmin:    true,
+	}
 
-		result, rpcErr := method.Handle(ctx, nil)
+	t.Run("Returns created, modified and deleted entries", func(t *testing.T) {
+		params := json.RawMessage(`{"base_ledger":{"ledger_index":5},"desired_ledger":{"ledger_hash":"` +
+			strings.Repeat("CD", 32) + `"},"type":"Offer","binary":true,"limit":3,"marker":"` + strings.Repeat("01", 32) + `"}`)
+		result, rpcErr := method.Handle(ctx, params)
+		require.Nil(t, rpcErr)
 
-		assert.Nil(t, result)
-		require.NotNil(t, rpcErr)
-		// ledger_diff is gRPC only in rippled
-		assert.Contains(t, rpcErr.Message, "gRPC")
+		assert.Equal(t, types.LedgerIndex("5"), differ.base.LedgerIndex)
+		assert.Equal(t, strings.Repeat("CD", 32), differ.desired.LedgerHash)
+		assert.Equal(t, "offer", differ.entryType)
+		require.NotNil(t, differ.marker)
+		assert.Equal(t, byte(0x01), differ.marker[31])
+		assert.Equal(t, uint32(3), differ.limit)
+
+		resp := result.(map[string]interface{})
+		entries := resp["entries"].([]map[string]interface{})
+		require.Len(t, entries, 3)
+		assert.Equal(t, "created", entries[0]["status"])
+		assert.Equal(t, "DEAD", entries[0]["after"])
+		assert.NotContains(t, entries[0], "before")
+		assert.Equal(t, "01", entries[1]["before"])
+		assert.Equal(t, "02", entries[1]["after"])
+		assert.Equal(t, "BEEF", entries[2]["before"])
+		assert.NotContains(t, entries[2], "after")
+		assert.Equal(t, "AB"+strings.Repeat("00", 31), resp["marker"])
+	})
+
+	t.Run("Desired ledger defaults to validated", func(t *testing.T) {
+		_, rpcErr := method.Handle(ctx, json.RawMessage(`{"base_ledger":{"ledger_index":"closed"}}`))
+		require.Nil(t, rpcErr)
+		assert.Equal(t, types.LedgerSpecifier{}, differ.desired)
+		assert.Nil(t, differ.marker)
 	})
 
+	for name, params := range map[string]string{
+		"Missing base_ledger": `{}`,
+		"Unknown type":        `{"base_ledger":{"ledger_index":5},"type":"bogus"}`,
+		"Malformed marker":    `{"base_ledger":{"ledger_index":5},"marker":"XYZ"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, rpcErr := method.Handle(ctx, json.RawMessage(params))
+			require.NotNil(t, rpcErr)
+			assert.Equal(t, types.RpcINVALID_PARAMS, rpcErr.Code)
+		})
+	}
+
 	t.Run("RequiredRole is Admin", func(t *testing.T) {
 		assert.Equal(t, types.RoleAdmin, method.RequiredRole())
 	})
 }
 
+// mockSubmissions returns the submissions it holds by hash.
+type mockSubmissions map[[32]byte]*types.Submission
+
+func (m mockSubmissions) GetSubmission(hash [32]byte) (*types.Submission, bool) {
+	sub, ok := m[hash]
+	return sub, ok
+}
+
+func TestTxStatusMethod(t *testing.T) {
+	mock := newMockLedgerServiceMissingMethods()
+	cleanup := setupTestServicesMissingMethods(mock)
+	defer cleanup()
+
+	types.Services.Submissions = mockSubmissions{
+		{0xAA}: {Hash: [32]byte{0xAA}, Account: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", Sequence: 4, State: "queued", EngineResult: "terPRE_SEQ", SubmittedLedger: 7, Attempts: 1},
+		{0xBB}: {Hash: [32]byte{0xBB}, Sequence: 5, LastLedgerSequence: 12, State: "validated", Final: true, EngineResult: "tesSUCCESS", SubmittedLedger: 8, LedgerIndex: 9, Relayed: 3, Attempts: 1},
+	}
+
+	method := &handlers.TxStatusMethod{}
+	ctx := &types.RpcContext{Context: context.Background(), Role: types.RoleGuest, ApiVersion: types.ApiVersion2}
+
+	t.Run("Reports a held transaction", func(t *testing.T) {
+		result, rpcErr := method.Handle(ctx, json.RawMessage(`{"transaction":"`+"AA"+strings.Repeat("00", 31)+`"}`))
+		require.Nil(t, rpcErr)
+		resp := result.(map[string]interface{})
+		assert.Equal(t, "AA"+strings.Repeat("00", 31), resp["hash"])
+		assert.Equal(t, "queued", resp["status"])
+		assert.Equal(t, false, resp["final"])
+		assert.Equal(t, "terPRE_SEQ", resp["engine_result"])
+		assert.NotContains(t, resp, "ledger_index")
+		assert.NotContains(t, resp, "last_ledger_sequence")
+	})
+
+	t.Run("Reports a validated transaction", func(t *testing.T) {
+		result, rpcErr := method.Handle(ctx, json.RawMessage(`{"transaction":"`+"bb"+strings.Repeat("00", 31)+`"}`))
+		require.Nil(t, rpcErr)
+		resp := result.(map[string]interface{})
+		assert.Equal(t, "validated", resp["status"])
+		assert.Equal(t, true, resp["validated"])
+		assert.Equal(t, true, resp["final"])
+		assert.Equal(t, uint32(9), resp["ledger_index"])
+		assert.Equal(t, uint32(12), resp["last_ledger_sequence"])
+		assert.Equal(t, 3, resp["relayed"])
+	})
+
+	t.Run("Unknown transaction returns txnNotFound", func(t *testing.T) {
+		_, rpcErr := method.Handle(ctx, json.RawMessage(`{"transaction":"`+strings.Repeat("CC", 32)+`"}`))
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, types.RpcTXN_NOT_FOUND, rpcErr.Code)
+	})
+
+	for name, params := range map[string]string{
+		"Missing transaction":   `{}`,
+		"Malformed transaction": `{"transaction":"XYZ"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, rpcErr := method.Handle(ctx, json.RawMessage(params))
+			require.NotNil(t, rpcErr)
+			assert.Equal(t, types.RpcINVALID_PARAMS, rpcErr.Code)
+		})
+	}
+
+	t.Run("RequiredRole is Guest", func(t *testing.T) {
+		assert.Equal(t, types.RoleGuest, method.RequiredRole())
+	})
+}
+
 // SimulateMethod Tests
 // Reference: rippled/src/test/rpc/Simulate_test.cpp
 
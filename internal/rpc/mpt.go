@@ -0,0 +1,97 @@
+package rpc
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/LeJamon/goXRPLd/internal/ledger/service"
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+)
+
+// The adapter serves MPT queries with or without a relational database;
+// only mpt_holders needs one.
+var _ types.MPTQueries = (*LedgerServiceAdapter)(nil)
+
+// GetMPTHolders returns a page of the holders of an issuance
+func (a *LedgerServiceAdapter) GetMPTHolders(mptID [24]byte, ledgerIndex string, marker *[20]byte, limit uint32) (*types.MPTHoldersResult, *types.RpcError) {
+	result, err := a.svc.GetMPTHolders(mptID, ledgerIndex, marker, limit)
+	if err != nil {
+		return nil, mptQueryError(err)
+	}
+
+	holders := make([]types.MPTHolder, len(result.Holders))
+	for i, h := range result.Holders {
+		holders[i] = types.MPTHolder{
+			Account:      h.Account,
+			Flags:        h.Flags,
+			MPTAmount:    h.MPTAmount,
+			LockedAmount: h.LockedAmount,
+			MPTokenIndex: h.MPTokenIndex,
+			Locked:       h.Locked,
+		}
+	}
+	return &types.MPTHoldersResult{
+		MPTIssuanceID: result.MPTIssuanceID,
+		Holders:       holders,
+		LedgerIndex:   result.LedgerIndex,
+		Validated:     result.Validated,
+		Limit:         result.Limit,
+		Marker:        result.Marker,
+	}, nil
+}
+
+// GetAccountMPTIssuances returns a page of the issuances an account created
+func (a *LedgerServiceAdapter) GetAccountMPTIssuances(account string, ledgerIndex string, marker *[32]byte, limit uint32) (*types.AccountMPTIssuancesResult, *types.RpcError) {
+	result, err := a.svc.GetAccountMPTIssuances(account, ledgerIndex, marker, limit)
+	if err != nil {
+		return nil, mptQueryError(err)
+	}
+
+	issuances := make([]types.MPTIssuance, len(result.Issuances))
+	for i, iss := range result.Issuances {
+		issuances[i] = types.MPTIssuance{
+			MPTIssuanceID:     iss.MPTIssuanceID,
+			Index:             iss.Index,
+			Sequence:          iss.Sequence,
+			Flags:             iss.Flags,
+			AssetScale:        iss.AssetScale,
+			TransferFee:       iss.TransferFee,
+			MaximumAmount:     iss.MaximumAmount,
+			OutstandingAmount: iss.OutstandingAmount,
+			LockedAmount:      iss.LockedAmount,
+			MPTokenMetadata:   iss.MPTokenMetadata,
+			DomainID:          iss.DomainID,
+		}
+	}
+	return &types.AccountMPTIssuancesResult{
+		Account:     result.Account,
+		Issuances:   issuances,
+		LedgerIndex: result.LedgerIndex,
+		LedgerHash:  result.LedgerHash,
+		Validated:   result.Validated,
+		Limit:       result.Limit,
+		Marker:      result.Marker,
+	}, nil
+}
+
+// mptQueryError maps a service error to an RPC error.
+func mptQueryError(err error) *types.RpcError {
+	switch {
+	case errors.Is(err, service.ErrMPTIssuanceNotFound):
+		return types.RpcErrorObjectNotFound("MPT issuance not found")
+	case errors.Is(err, service.ErrNoMPTIndex):
+		return types.NewRpcError(types.RpcNOT_SUPPORTED, "notSupported", "notSupported",
+			"MPT holder index not available. Database not configured.")
+	case errors.Is(err, service.ErrLedgerNotFound), errors.Is(err, service.ErrNoOpenLedger):
+		return types.RpcErrorLgrNotFound("ledgerNotFound")
+	case err.Error() == "invalid ledger_index":
+		return types.RpcErrorInvalidParams("ledgerIndexMalformed")
+	case err.Error() == "account not found":
+		return types.RpcErrorActNotFound("Account not found.")
+	case err.Error() == "invalid marker":
+		return types.RpcErrorInvalidParams("Invalid marker.")
+	case strings.HasPrefix(err.Error(), "invalid account address:"):
+		return types.RpcErrorActMalformed("Account malformed.")
+	}
+	return types.RpcErrorInternal(err.Error())
+}
@@ -0,0 +1,176 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/internal/rpc/handlers"
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mpt_holders and account_mpt_issuances tests.
+// mpt_holders is based on Clio's MPTHoldersTests.cpp.
+
+var testMPTID = [24]byte{0x00, 0x00, 0x00, 0x05, 0xB5, 0xF7, 23: 0x01}
+
+// mockMPTQueries records the last query and returns canned results.
+type mockMPTQueries struct {
+	holders   *types.MPTHoldersResult
+	issuances *types.AccountMPTIssuancesResult
+	err       *types.RpcError
+
+	mptID          [24]byte
+	account        string
+	ledgerIndex    string
+	holderMarker   *[20]byte
+	issuanceMarker *[32]byte
+	limit          uint32
+}
+
+func (m *mockMPTQueries) GetMPTHolders(mptID [24]byte, ledgerIndex string, marker *[20]byte, limit uint32) (*types.MPTHoldersResult, *types.RpcError) {
+	m.mptID, m.ledgerIndex, m.holderMarker, m.limit = mptID, ledgerIndex, marker, limit
+	return m.holders, m.err
+}
+
+func (m *mockMPTQueries) GetAccountMPTIssuances(account string, ledgerIndex string, marker *[32]byte, limit uint32) (*types.AccountMPTIssuancesResult, *types.RpcError) {
+	m.account, m.ledgerIndex, m.issuanceMarker, m.limit = account, ledgerIndex, marker, limit
+	return m.issuances, m.err
+}
+
+func setupMPTQueries(t *testing.T, mpts types.MPTQueries) {
+	t.Helper()
+	old := types.Services
+	types.Services = &types.ServiceContainer{Ledger: newMockLedgerService(), MPTs: mpts}
+	t.Cleanup(func() { types.Services = old })
+}
+
+func TestMPTHolders(t *testing.T) {
+	locked := uint64(5)
+	next := [20]byte{0xBB}
+	mpts := &mockMPTQueries{holders: &types.MPTHoldersResult{
+		MPTIssuanceID: testMPTID,
+		Holders: []types.MPTHolder{
+			{Account: testNFTIssuer, Flags: 3, MPTAmount: 100, LockedAmount: &locked, MPTokenIndex: [32]byte{0xCC}, Locked: true},
+		},
+		LedgerIndex: 30,
+		Validated:   true,
+		Limit:       1,
+		Marker:      &next,
+	}}
+	setupMPTQueries(t, mpts)
+	mptID := strings.ToUpper(hex.EncodeToString(testMPTID[:]))
+	marker := strings.ToUpper(hex.EncodeToString([]byte{0xAA, 19: 0}))
+
+	response, rpcErr := callNFTMethod(t, &handlers.MptHoldersMethod{}, map[string]interface{}{
+		"mpt_issuance_id": mptID, "limit": 1, "marker": marker, "ledger_index": 30,
+	})
+	require.Nil(t, rpcErr)
+	assert.Equal(t, testMPTID, mpts.mptID)
+	assert.Equal(t, "30", mpts.ledgerIndex)
+	require.NotNil(t, mpts.holderMarker)
+	assert.Equal(t, [20]byte{0xAA}, *mpts.holderMarker)
+
+	assert.Equal(t, mptID, response["mpt_issuance_id"])
+	assert.Equal(t, float64(30), response["ledger_index"])
+	assert.Equal(t, strings.ToUpper(hex.EncodeToString(next[:])), response["marker"])
+	tokens := response["mptokens"].([]interface{})
+	require.Len(t, tokens, 1)
+	token := tokens[0].(map[string]interface{})
+	assert.Equal(t, testNFTIssuer, token["account"])
+	assert.Equal(t, float64(100), token["mpt_amount"])
+	assert.Equal(t, float64(5), token["locked_amount"])
+	assert.Equal(t, true, token["locked"])
+
+	_, rpcErr = callNFTMethod(t, &handlers.MptHoldersMethod{}, map[string]interface{}{"mpt_issuance_id": mptID, "limit": 1000})
+	require.Nil(t, rpcErr)
+	assert.Equal(t, "validated", mpts.ledgerIndex)
+	assert.Nil(t, mpts.holderMarker)
+	assert.Equal(t, handlers.LimitMPTHolders.Max, mpts.limit)
+}
+
+func TestMPTHoldersErrors(t *testing.T) {
+	setupMPTQueries(t, &mockMPTQueries{})
+
+	_, rpcErr := callNFTMethod(t, &handlers.MptHoldersMethod{}, map[string]interface{}{})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, types.RpcINVALID_PARAMS, rpcErr.Code)
+
+	_, rpcErr = callNFTMethod(t, &handlers.MptHoldersMethod{}, map[string]interface{}{"mpt_issuance_id": "ABCD"})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, "Malformed mpt_issuance_id.", rpcErr.Message)
+
+	mptID := strings.ToUpper(hex.EncodeToString(testMPTID[:]))
+	_, rpcErr = callNFTMethod(t, &handlers.MptHoldersMethod{}, map[string]interface{}{"mpt_issuance_id": mptID, "marker": "xyz"})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, "Malformed marker.", rpcErr.Message)
+
+	setupMPTQueries(t, &mockMPTQueries{err: types.RpcErrorObjectNotFound("MPT issuance not found")})
+	_, rpcErr = callNFTMethod(t, &handlers.MptHoldersMethod{}, map[string]interface{}{"mpt_issuance_id": mptID})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, "objectNotFound", rpcErr.ErrorString)
+}
+
+func TestAccountMPTIssuances(t *testing.T) {
+	maximum := uint64(1000)
+	next := [32]byte{0xEE}
+	mpts := &mockMPTQueries{issuances: &types.AccountMPTIssuancesResult{
+		Account: testNFTIssuer,
+		Issuances: []types.MPTIssuance{{
+			MPTIssuanceID:     testMPTID,
+			Index:             [32]byte{0xDD},
+			Sequence:          5,
+			Flags:             0x22,
+			AssetScale:        2,
+			MaximumAmount:     &maximum,
+			OutstandingAmount: 300,
+		}},
+		LedgerIndex: 7,
+		Limit:       10,
+		Marker:      &next,
+	}}
+	setupMPTQueries(t, mpts)
+
+	response, rpcErr := callNFTMethod(t, &handlers.AccountMptIssuancesMethod{}, map[string]interface{}{"account": testNFTIssuer})
+	require.Nil(t, rpcErr)
+	assert.Equal(t, testNFTIssuer, mpts.account)
+	assert.Equal(t, "current", mpts.ledgerIndex)
+	assert.Equal(t, handlers.LimitAccountMPTIssuances.Default, mpts.limit)
+
+	assert.Equal(t, strings.ToUpper(hex.EncodeToString(next[:])), response["marker"])
+	issuances := response["mpt_issuances"].([]interface{})
+	require.Len(t, issuances, 1)
+	issuance := issuances[0].(map[string]interface{})
+	assert.Equal(t, strings.ToUpper(hex.EncodeToString(testMPTID[:])), issuance["mpt_issuance_id"])
+	assert.Equal(t, float64(300), issuance["outstanding_amount"])
+	assert.Equal(t, float64(1000), issuance["maximum_amount"])
+	assert.Equal(t, float64(2), issuance["asset_scale"])
+	assert.NotContains(t, issuance, "transfer_fee")
+	assert.NotContains(t, issuance, "locked_amount")
+
+	_, rpcErr = callNFTMethod(t, &handlers.AccountMptIssuancesMethod{}, map[string]interface{}{
+		"account": testNFTIssuer, "marker": hex.EncodeToString(next[:]),
+	})
+	require.Nil(t, rpcErr)
+	require.NotNil(t, mpts.issuanceMarker)
+	assert.Equal(t, next, *mpts.issuanceMarker)
+}
+
+func TestAccountMPTIssuancesErrors(t *testing.T) {
+	setupMPTQueries(t, &mockMPTQueries{})
+
+	_, rpcErr := callNFTMethod(t, &handlers.AccountMptIssuancesMethod{}, map[string]interface{}{"account": "notanaccount"})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, "actMalformed", rpcErr.ErrorString)
+
+	_, rpcErr = callNFTMethod(t, &handlers.AccountMptIssuancesMethod{}, map[string]interface{}{"account": testNFTIssuer, "marker": "xyz"})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, "Invalid marker.", rpcErr.Message)
+
+	types.Services.MPTs = nil
+	_, rpcErr = callNFTMethod(t, &handlers.AccountMptIssuancesMethod{}, map[string]interface{}{"account": testNFTIssuer})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, types.RpcINTERNAL, rpcErr.Code)
+}
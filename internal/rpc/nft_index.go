@@ -0,0 +1,119 @@
+package rpc
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/LeJamon/goXRPLd/internal/ledger/service"
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+// The adapter serves the NFT index when a relational database is
+// configured.
+var _ types.NFTIndex = (*LedgerServiceAdapter)(nil)
+
+// GetNFTInfo returns the state of an NFT as of a ledger
+func (a *LedgerServiceAdapter) GetNFTInfo(nftID [32]byte, ledgerIndex string) (*types.IndexedNFTResult, *types.RpcError) {
+	result, err := a.svc.GetNFTInfo(nftID, ledgerIndex)
+	if err != nil {
+		return nil, nftIndexError(err)
+	}
+	return &types.IndexedNFTResult{
+		NFT:         toIndexedNFT(result.NFT),
+		LedgerIndex: result.LedgerIndex,
+		Validated:   result.Validated,
+	}, nil
+}
+
+// GetNFTHistory returns a page of the transactions that touched an NFT
+func (a *LedgerServiceAdapter) GetNFTHistory(nftID [32]byte, ledgerMin, ledgerMax int64, limit uint32, marker *types.AccountTxMarker, forward bool) (*types.NFTHistoryResult, *types.RpcError) {
+	var svcMarker *relationaldb.AccountTxMarker
+	if marker != nil {
+		svcMarker = &relationaldb.AccountTxMarker{
+			LedgerSeq: relationaldb.LedgerIndex(marker.LedgerSeq),
+			TxnSeq:    marker.TxnSeq,
+		}
+	}
+
+	result, err := a.svc.GetNFTHistory(nftID, ledgerMin, ledgerMax, limit, svcMarker, forward)
+	if err != nil {
+		return nil, nftIndexError(err)
+	}
+
+	txs := make([]types.AccountTransaction, len(result.Transactions))
+	for i, tx := range result.Transactions {
+		txs[i] = types.AccountTransaction{
+			Hash:        tx.Hash,
+			LedgerIndex: tx.LedgerIndex,
+			TxnSeq:      tx.TxnSeq,
+			TxBlob:      tx.TxBlob,
+			Meta:        tx.Meta,
+		}
+	}
+
+	var rpcMarker *types.AccountTxMarker
+	if result.Marker != nil {
+		rpcMarker = &types.AccountTxMarker{
+			LedgerSeq: uint32(result.Marker.LedgerSeq),
+			TxnSeq:    result.Marker.TxnSeq,
+		}
+	}
+
+	return &types.NFTHistoryResult{
+		LedgerMin:    result.LedgerMin,
+		LedgerMax:    result.LedgerMax,
+		Limit:        result.Limit,
+		Marker:       rpcMarker,
+		Transactions: txs,
+	}, nil
+}
+
+// GetNFTsByIssuer returns a page of the NFTs an account issued
+func (a *LedgerServiceAdapter) GetNFTsByIssuer(issuer string, taxon *uint32, ledgerIndex string, marker *[32]byte, limit uint32) (*types.NFTsByIssuerResult, *types.RpcError) {
+	result, err := a.svc.GetNFTsByIssuer(issuer, taxon, ledgerIndex, marker, limit)
+	if err != nil {
+		return nil, nftIndexError(err)
+	}
+
+	nfts := make([]types.IndexedNFT, len(result.NFTs))
+	for i, nft := range result.NFTs {
+		nfts[i] = toIndexedNFT(nft)
+	}
+	return &types.NFTsByIssuerResult{
+		NFTs:        nfts,
+		LedgerIndex: result.LedgerIndex,
+		Validated:   result.Validated,
+		Limit:       result.Limit,
+		Marker:      result.Marker,
+	}, nil
+}
+
+func toIndexedNFT(nft service.IndexedNFT) types.IndexedNFT {
+	return types.IndexedNFT{
+		NFTokenID:   nft.NFTokenID,
+		Owner:       nft.Owner,
+		IsBurned:    nft.IsBurned,
+		Flags:       nft.Flags,
+		TransferFee: nft.TransferFee,
+		Issuer:      nft.Issuer,
+		Taxon:       nft.Taxon,
+		Serial:      nft.Serial,
+		URI:         nft.URI,
+	}
+}
+
+// nftIndexError maps a service error to the error Clio returns.
+func nftIndexError(err error) *types.RpcError {
+	switch {
+	case errors.Is(err, service.ErrNFTNotFound):
+		return types.RpcErrorObjectNotFound("NFT not found")
+	case errors.Is(err, service.ErrLedgerNotFound), errors.Is(err, service.ErrNoOpenLedger):
+		return types.RpcErrorLgrNotFound("ledgerNotFound")
+	case err.Error() == "invalid ledger_index":
+		return types.RpcErrorInvalidParams("ledgerIndexMalformed")
+	case strings.HasPrefix(err.Error(), "invalid account address:"):
+		return types.RpcErrorActMalformed("Account malformed.")
+	}
+	return types.RpcErrorInternal(err.Error())
+}
@@ -0,0 +1,256 @@
+package rpc
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/internal/rpc/handlers"
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nft_info, nft_history and nfts_by_issuer tests.
+// Based on Clio's NFTInfoTests.cpp, NFTHistoryTests.cpp and
+// NFTsByIssuerTest.cpp.
+
+const testNFTIssuer = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+
+var testNFTID = [32]byte{0x00, 0x08, 0x00, 0x00, 31: 0x01}
+
+// mockNFTIndex records the last query and returns canned results.
+type mockNFTIndex struct {
+	info    *types.IndexedNFTResult
+	history *types.NFTHistoryResult
+	issued  *types.NFTsByIssuerResult
+	err     *types.RpcError
+
+	ledgerIndex   string
+	ledgerMin     int64
+	ledgerMax     int64
+	limit         uint32
+	historyMarker *types.AccountTxMarker
+	forward       bool
+	taxon         *uint32
+	issuerMarker  *[32]byte
+	issuer        string
+}
+
+func (m *mockNFTIndex) GetNFTInfo(nftID [32]byte, ledgerIndex string) (*types.IndexedNFTResult, *types.RpcError) {
+	m.ledgerIndex = ledgerIndex
+	return m.info, m.err
+}
+
+func (m *mockNFTIndex) GetNFTHistory(nftID [32]byte, ledgerMin, ledgerMax int64, limit uint32, marker *types.AccountTxMarker, forward bool) (*types.NFTHistoryResult, *types.RpcError) {
+	m.ledgerMin, m.ledgerMax, m.limit, m.historyMarker, m.forward = ledgerMin, ledgerMax, limit, marker, forward
+	return m.history, m.err
+}
+
+func (m *mockNFTIndex) GetNFTsByIssuer(issuer string, taxon *uint32, ledgerIndex string, marker *[32]byte, limit uint32) (*types.NFTsByIssuerResult, *types.RpcError) {
+	m.issuer, m.taxon, m.ledgerIndex, m.issuerMarker, m.limit = issuer, taxon, ledgerIndex, marker, limit
+	return m.issued, m.err
+}
+
+func setupNFTIndex(t *testing.T, index types.NFTIndex) {
+	t.Helper()
+	old := types.Services
+	types.Services = &types.ServiceContainer{Ledger: newMockLedgerService(), NFTIndex: index}
+	t.Cleanup(func() { types.Services = old })
+}
+
+func callNFTMethod(t *testing.T, method types.MethodHandler, params map[string]interface{}) (map[string]interface{}, *types.RpcError) {
+	t.Helper()
+	raw, err := json.Marshal(params)
+	require.NoError(t, err)
+	ctx := &types.RpcContext{Context: context.Background(), Role: types.RoleGuest, ApiVersion: types.ApiVersion1}
+	result, rpcErr := method.Handle(ctx, raw)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	out, err := json.Marshal(result)
+	require.NoError(t, err)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(out, &response))
+	return response, nil
+}
+
+func testIndexedNFT() types.IndexedNFT {
+	return types.IndexedNFT{
+		NFTokenID:   testNFTID,
+		Owner:       testNFTIssuer,
+		Flags:       8,
+		TransferFee: 0,
+		Issuer:      testNFTIssuer,
+		Taxon:       7,
+		Serial:      1,
+		URI:         []byte("ipfs://a"),
+	}
+}
+
+func TestNFTInfo(t *testing.T) {
+	index := &mockNFTIndex{info: &types.IndexedNFTResult{NFT: testIndexedNFT(), LedgerIndex: 30, Validated: true}}
+	setupNFTIndex(t, index)
+	nftID := strings.ToUpper(hex.EncodeToString(testNFTID[:]))
+
+	response, rpcErr := callNFTMethod(t, &handlers.NftInfoMethod{}, map[string]interface{}{"nft_id": nftID})
+	require.Nil(t, rpcErr)
+	assert.Equal(t, "validated", index.ledgerIndex, "defaults to the validated ledger")
+	assert.Equal(t, map[string]interface{}{
+		"nft_id":       nftID,
+		"ledger_index": float64(30),
+		"owner":        testNFTIssuer,
+		"is_burned":    false,
+		"flags":        float64(8),
+		"transfer_fee": float64(0),
+		"issuer":       testNFTIssuer,
+		"nft_taxon":    float64(7),
+		"nft_serial":   float64(1),
+		"uri":          strings.ToUpper(hex.EncodeToString([]byte("ipfs://a"))),
+		"validated":    true,
+	}, response)
+
+	_, rpcErr = callNFTMethod(t, &handlers.NftInfoMethod{}, map[string]interface{}{"nft_id": nftID, "ledger_index": 25})
+	require.Nil(t, rpcErr)
+	assert.Equal(t, "25", index.ledgerIndex)
+}
+
+func TestNFTInfoErrors(t *testing.T) {
+	index := &mockNFTIndex{err: types.RpcErrorObjectNotFound("NFT not found")}
+	setupNFTIndex(t, index)
+
+	_, rpcErr := callNFTMethod(t, &handlers.NftInfoMethod{}, map[string]interface{}{})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, types.RpcINVALID_PARAMS, rpcErr.Code)
+
+	_, rpcErr = callNFTMethod(t, &handlers.NftInfoMethod{}, map[string]interface{}{"nft_id": "ABCD"})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, "Malformed nft_id.", rpcErr.Message)
+
+	_, rpcErr = callNFTMethod(t, &handlers.NftInfoMethod{}, map[string]interface{}{"nft_id": hex.EncodeToString(testNFTID[:])})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, "objectNotFound", rpcErr.ErrorString)
+
+	setupNFTIndex(t, nil)
+	_, rpcErr = callNFTMethod(t, &handlers.NftInfoMethod{}, map[string]interface{}{"nft_id": hex.EncodeToString(testNFTID[:])})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, types.RpcNOT_SUPPORTED, rpcErr.Code)
+}
+
+func TestNFTHistory(t *testing.T) {
+	index := &mockNFTIndex{history: &types.NFTHistoryResult{
+		LedgerMin: 1, LedgerMax: 40, Limit: 2,
+		Marker: &types.AccountTxMarker{LedgerSeq: 20, TxnSeq: 3},
+		Transactions: []types.AccountTransaction{
+			{Hash: [32]byte{1}, LedgerIndex: 30, TxBlob: []byte{0x12}, Meta: []byte{0x20}},
+			{Hash: [32]byte{2}, LedgerIndex: 20, TxnSeq: 3, TxBlob: []byte{0x12}, Meta: []byte{0x20}},
+		},
+	}}
+	setupNFTIndex(t, index)
+	nftID := strings.ToUpper(hex.EncodeToString(testNFTID[:]))
+
+	response, rpcErr := callNFTMethod(t, &handlers.NftHistoryMethod{}, map[string]interface{}{
+		"nft_id": nftID, "ledger_index_min": -1, "ledger_index_max": 40, "limit": 2,
+		"binary": true, "forward": true, "marker": map[string]interface{}{"ledger": 10, "seq": 1},
+	})
+	require.Nil(t, rpcErr)
+	assert.Equal(t, int64(-1), index.ledgerMin)
+	assert.Equal(t, int64(40), index.ledgerMax)
+	assert.Equal(t, uint32(2), index.limit)
+	assert.True(t, index.forward)
+	assert.Equal(t, &types.AccountTxMarker{LedgerSeq: 10, TxnSeq: 1}, index.historyMarker)
+
+	assert.Equal(t, nftID, response["nft_id"])
+	assert.Equal(t, float64(1), response["ledger_index_min"])
+	assert.Equal(t, float64(40), response["ledger_index_max"])
+	assert.Equal(t, map[string]interface{}{"ledger": float64(20), "seq": float64(3)}, response["marker"])
+	txs := response["transactions"].([]interface{})
+	require.Len(t, txs, 2)
+	assert.Equal(t, "12", txs[0].(map[string]interface{})["tx_blob"])
+	assert.Equal(t, float64(30), txs[0].(map[string]interface{})["ledger_index"])
+
+	// A single ledger, and the default limit.
+	_, rpcErr = callNFTMethod(t, &handlers.NftHistoryMethod{}, map[string]interface{}{"nft_id": nftID, "ledger_index": 25})
+	require.Nil(t, rpcErr)
+	assert.Equal(t, int64(25), index.ledgerMin)
+	assert.Equal(t, int64(25), index.ledgerMax)
+	assert.Equal(t, handlers.LimitNFTHistory.Default, index.limit)
+}
+
+func TestNFTHistoryErrors(t *testing.T) {
+	setupNFTIndex(t, &mockNFTIndex{})
+	nftID := hex.EncodeToString(testNFTID[:])
+
+	_, rpcErr := callNFTMethod(t, &handlers.NftHistoryMethod{}, map[string]interface{}{
+		"nft_id": nftID, "ledger_index_min": 20, "ledger_index_max": 10,
+	})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, "lgrIdxsInvalid", rpcErr.ErrorString)
+
+	_, rpcErr = callNFTMethod(t, &handlers.NftHistoryMethod{}, map[string]interface{}{
+		"nft_id": nftID, "ledger_index_min": 10, "ledger_index": 12,
+	})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, types.RpcINVALID_PARAMS, rpcErr.Code)
+
+	_, rpcErr = callNFTMethod(t, &handlers.NftHistoryMethod{}, map[string]interface{}{
+		"nft_id": nftID, "marker": "abc",
+	})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, types.RpcINVALID_PARAMS, rpcErr.Code)
+}
+
+func TestNFTsByIssuer(t *testing.T) {
+	next := [32]byte{0xAA}
+	index := &mockNFTIndex{issued: &types.NFTsByIssuerResult{
+		NFTs:        []types.IndexedNFT{testIndexedNFT()},
+		LedgerIndex: 30,
+		Validated:   true,
+		Limit:       1,
+		Marker:      &next,
+	}}
+	setupNFTIndex(t, index)
+	marker := strings.ToUpper(hex.EncodeToString(testNFTID[:]))
+
+	response, rpcErr := callNFTMethod(t, &handlers.NftsByIssuerMethod{}, map[string]interface{}{
+		"issuer": testNFTIssuer, "nft_taxon": 7, "limit": 1, "marker": marker,
+	})
+	require.Nil(t, rpcErr)
+	assert.Equal(t, testNFTIssuer, index.issuer)
+	require.NotNil(t, index.taxon)
+	assert.Equal(t, uint32(7), *index.taxon)
+	assert.Equal(t, testNFTID, *index.issuerMarker)
+	assert.Equal(t, "validated", index.ledgerIndex)
+
+	assert.Equal(t, testNFTIssuer, response["issuer"])
+	assert.Equal(t, float64(7), response["nft_taxon"])
+	assert.Equal(t, float64(30), response["ledger_index"])
+	assert.Equal(t, true, response["validated"])
+	assert.Equal(t, strings.ToUpper(hex.EncodeToString(next[:])), response["marker"])
+	nfts := response["nfts"].([]interface{})
+	require.Len(t, nfts, 1)
+	assert.Equal(t, marker, nfts[0].(map[string]interface{})["nft_id"])
+
+	_, rpcErr = callNFTMethod(t, &handlers.NftsByIssuerMethod{}, map[string]interface{}{"issuer": testNFTIssuer, "limit": 1000})
+	require.Nil(t, rpcErr)
+	assert.Nil(t, index.taxon)
+	assert.Equal(t, handlers.LimitNFTsByIssuer.Max, index.limit)
+}
+
+func TestNFTsByIssuerErrors(t *testing.T) {
+	setupNFTIndex(t, &mockNFTIndex{})
+
+	_, rpcErr := callNFTMethod(t, &handlers.NftsByIssuerMethod{}, map[string]interface{}{})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, types.RpcINVALID_PARAMS, rpcErr.Code)
+
+	_, rpcErr = callNFTMethod(t, &handlers.NftsByIssuerMethod{}, map[string]interface{}{"issuer": "notanaccount"})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, "actMalformed", rpcErr.ErrorString)
+
+	_, rpcErr = callNFTMethod(t, &handlers.NftsByIssuerMethod{}, map[string]interface{}{"issuer": testNFTIssuer, "marker": "xyz"})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, "Malformed marker.", rpcErr.Message)
+}
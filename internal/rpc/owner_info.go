@@ -0,0 +1,37 @@
+package rpc
+
+import (
+	"errors"
+
+	"github.com/LeJamon/goXRPLd/internal/ledger/service"
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+)
+
+var _ types.OwnerInfoQuerier = (*LedgerServiceAdapter)(nil)
+
+// GetOwnerInfo lists the offers and trust lines an account owns in a ledger
+func (a *LedgerServiceAdapter) GetOwnerInfo(account, ledgerIndex string) (*types.OwnerInfoResult, *types.RpcError) {
+	result, err := a.svc.GetOwnerInfo(account, ledgerIndex)
+	if err != nil {
+		if errors.Is(err, service.ErrLedgerNotFound) || errors.Is(err, service.ErrNoOpenLedger) {
+			return nil, types.RpcErrorLgrNotFound("ledgerNotFound")
+		}
+		return nil, types.RpcErrorInternal(err.Error())
+	}
+
+	return &types.OwnerInfoResult{
+		LedgerIndex: result.LedgerIndex,
+		LedgerHash:  result.LedgerHash,
+		Validated:   result.Validated,
+		Offers:      ownedEntries(result.Offers),
+		RippleLines: ownedEntries(result.RippleLines),
+	}, nil
+}
+
+func ownedEntries(entries []service.OwnedEntry) []types.OwnedEntry {
+	out := make([]types.OwnedEntry, len(entries))
+	for i, e := range entries {
+		out[i] = types.OwnedEntry{Index: e.Index, Data: e.Data}
+	}
+	return out
+}
@@ -1,8 +1,10 @@
 package rpc
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/LeJamon/goXRPLd/internal/ledger/state"
@@ -25,6 +27,7 @@ type PathFindSession struct {
 	sendMax       *tx.Amount
 	srcCurrencies []payment.Issue
 	convertAll    bool
+	domain        *[32]byte // permissioned domain, nil for the open DEX
 
 	// Original string representations for response formatting
 	srcAccountStr string
@@ -49,6 +52,7 @@ type pathFindCreateRequest struct {
 		Currency string `json:"currency"`
 		Issuer   string `json:"issuer,omitempty"`
 	} `json:"source_currencies,omitempty"`
+	Domain json.RawMessage `json:"domain,omitempty"`
 }
 
 // ParseAndCreateSession parses a path_find create request and creates a session.
@@ -116,6 +120,12 @@ func ParseAndCreateSession(params json.RawMessage, id interface{}) (*PathFindSes
 		srcCurrencies = append(srcCurrencies, issue)
 	}
 
+	// Optional permissioned domain. Reference: rippled PathRequest::parseJson
+	domain, rpcErr := rpctypes.ParseDomainParam(request.Domain)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
 	session := &PathFindSession{
 		srcAccount:    srcAccount,
 		dstAccount:    dstAccount,
@@ -123,6 +133,7 @@ func ParseAndCreateSession(params json.RawMessage, id interface{}) (*PathFindSes
 		sendMax:       sendMax,
 		srcCurrencies: srcCurrencies,
 		convertAll:    convertAll,
+		domain:        domain,
 		srcAccountStr: request.SourceAccount,
 		dstAccountStr: request.DestinationAccount,
 		dstAmountRaw:  request.DestinationAmount,
@@ -143,6 +154,7 @@ func (s *PathFindSession) Execute(view tx.LedgerView) *PathFindEvent {
 		s.dstAmount, s.sendMax,
 		s.srcCurrencies, s.convertAll,
 	)
+	pr.SetDomain(s.domain)
 	result := pr.Execute(view)
 	s.lastResult = result
 
@@ -167,6 +179,12 @@ func (s *PathFindSession) buildEvent(result *pathfinder.PathRequestResult, fullR
 		var srcAmtJSON json.RawMessage
 		if alt.SourceAmount.IsNative() {
 			srcAmtJSON, _ = json.Marshal(alt.SourceAmount.Value())
+		} else if alt.SourceAmount.IsMPT() {
+			raw, _ := alt.SourceAmount.MPTRaw()
+			srcAmtJSON, _ = json.Marshal(map[string]string{
+				"mpt_issuance_id": alt.SourceAmount.MPTIssuanceID(),
+				"value":           strconv.FormatInt(raw, 10),
+			})
 		} else {
 			srcAmtJSON, _ = json.Marshal(map[string]string{
 				"currency": alt.SourceAmount.Currency,
@@ -180,7 +198,7 @@ func (s *PathFindSession) buildEvent(result *pathfinder.PathRequestResult, fullR
 		})
 	}
 
-	return &PathFindEvent{
+	event := &PathFindEvent{
 		Type:               "path_find",
 		ID:                 s.id,
 		SourceAccount:      s.srcAccountStr,
@@ -189,6 +207,10 @@ func (s *PathFindSession) buildEvent(result *pathfinder.PathRequestResult, fullR
 		FullReply:          fullReply,
 		Alternatives:       alternatives,
 	}
+	if s.domain != nil {
+		event.Domain = strings.ToUpper(hex.EncodeToString(s.domain[:]))
+	}
+	return event
 }
 
 // convertToRPCPathSteps converts payment.PathStep slices to rpctypes.PathStep slices.
@@ -225,11 +247,22 @@ func parseSessionAmount(raw json.RawMessage) tx.Amount {
 		Currency string `json:"currency"`
 		Issuer   string `json:"issuer"`
 		Value    string `json:"value"`
+
+		MPTIssuanceID string `json:"mpt_issuance_id"`
 	}
 	if err := json.Unmarshal(raw, &iou); err != nil {
 		return state.NewXRPAmountFromInt(0)
 	}
 
+	if iou.MPTIssuanceID != "" {
+		issuer, ok := state.MPTIssuerFromID(iou.MPTIssuanceID)
+		raw, err := strconv.ParseInt(iou.Value, 10, 64)
+		if !ok || err != nil {
+			return state.NewXRPAmountFromInt(0)
+		}
+		return state.NewMPTAmountWithIssuanceID(raw, issuer, iou.MPTIssuanceID)
+	}
+
 	if iou.Currency == "XRP" || iou.Currency == "" {
 		drops, _ := strconv.ParseInt(iou.Value, 10, 64)
 		return state.NewXRPAmountFromInt(drops)
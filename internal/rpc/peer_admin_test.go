@@ -0,0 +1,197 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/internal/rpc/handlers"
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePeerAdmin records calls made by the admin network handlers.
+type fakePeerAdmin struct {
+	connected     []string
+	connectErr    error
+	reservations  map[string]string
+	lastThreshold int
+}
+
+func newFakePeerAdmin() *fakePeerAdmin {
+	return &fakePeerAdmin{reservations: make(map[string]string)}
+}
+
+func (f *fakePeerAdmin) Connect(addr string) error {
+	f.connected = append(f.connected, addr)
+	return f.connectErr
+}
+
+func (f *fakePeerAdmin) AddReservation(nodeKey, description string) (map[string]any, error) {
+	prev, ok := f.reservations[nodeKey]
+	f.reservations[nodeKey] = description
+	if !ok {
+		return nil, nil
+	}
+	return map[string]any{"node": nodeKey, "description": prev}, nil
+}
+
+func (f *fakePeerAdmin) DeleteReservation(nodeKey string) (map[string]any, error) {
+	prev, ok := f.reservations[nodeKey]
+	if !ok {
+		return nil, nil
+	}
+	delete(f.reservations, nodeKey)
+	return map[string]any{"node": nodeKey, "description": prev}, nil
+}
+
+func (f *fakePeerAdmin) ReservationsJSON() []map[string]any {
+	out := []map[string]any{}
+	for k, d := range f.reservations {
+		out = append(out, map[string]any{"node": k, "description": d})
+	}
+	return out
+}
+
+func (f *fakePeerAdmin) ResourceJSON(threshold int) map[string]any {
+	f.lastThreshold = threshold
+	return map[string]any{"10.0.0.1": map[string]any{"local": 6000}}
+}
+
+// A valid base58 node public key (rippled's PeerReservations_test uses
+// the same shape of key for its positive cases).
+const testReservationKey = "n9KAa2zVWjPHgfzsE3iZ8HAbzJtPrnoh4H2M2HgE7dfqtvyEb1KJ"
+
+func setupPeerAdminServices(admin *fakePeerAdmin) func() {
+	oldServices := types.Services
+	mock := newMockLedgerServiceMissingMethods()
+	mock.standalone = false
+	types.Services = &types.ServiceContainer{
+		Ledger:    mock,
+		PeerAdmin: admin,
+	}
+	return func() { types.Services = oldServices }
+}
+
+func adminCtx() *types.RpcContext {
+	return &types.RpcContext{
+		Context:    context.Background(),
+		Role:       types.RoleAdmin,
+		ApiVersion: types.ApiVersion1,
+	}
+}
+
+// Reference: rippled/src/test/rpc/Peers_test.cpp / PeerReservations
+func TestPeerReservationsMethods(t *testing.T) {
+	admin := newFakePeerAdmin()
+	cleanup := setupPeerAdminServices(admin)
+	defer cleanup()
+
+	add := &handlers.PeerReservationsAddMethod{}
+	del := &handlers.PeerReservationsDelMethod{}
+	list := &handlers.PeerReservationsListMethod{}
+
+	t.Run("Missing public_key", func(t *testing.T) {
+		_, rpcErr := add.Handle(adminCtx(), json.RawMessage(`{}`))
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, types.RpcINVALID_PARAMS, rpcErr.Code)
+	})
+
+	t.Run("Non-string public_key", func(t *testing.T) {
+		_, rpcErr := add.Handle(adminCtx(), json.RawMessage(`{"public_key": 1}`))
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, types.RpcINVALID_PARAMS, rpcErr.Code)
+	})
+
+	t.Run("Malformed public_key", func(t *testing.T) {
+		_, rpcErr := add.Handle(adminCtx(), json.RawMessage(`{"public_key": "notakey"}`))
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, types.RpcPUBLIC_MALFORMED, rpcErr.Code)
+	})
+
+	t.Run("Add, replace, list, delete", func(t *testing.T) {
+		result, rpcErr := add.Handle(adminCtx(), json.RawMessage(`{"public_key": "`+testReservationKey+`", "description": "hub"}`))
+		require.Nil(t, rpcErr)
+		assert.NotContains(t, result.(map[string]interface{}), "previous")
+
+		result, rpcErr = add.Handle(adminCtx(), json.RawMessage(`{"public_key": "`+testReservationKey+`"}`))
+		require.Nil(t, rpcErr)
+		prev := result.(map[string]interface{})["previous"].(map[string]any)
+		assert.Equal(t, "hub", prev["description"])
+
+		result, rpcErr = list.Handle(adminCtx(), nil)
+		require.Nil(t, rpcErr)
+		assert.Len(t, result.(map[string]interface{})["reservations"], 1)
+
+		result, rpcErr = del.Handle(adminCtx(), json.RawMessage(`{"public_key": "`+testReservationKey+`"}`))
+		require.Nil(t, rpcErr)
+		assert.Contains(t, result.(map[string]interface{}), "previous")
+
+		result, rpcErr = del.Handle(adminCtx(), json.RawMessage(`{"public_key": "`+testReservationKey+`"}`))
+		require.Nil(t, rpcErr)
+		assert.NotContains(t, result.(map[string]interface{}), "previous")
+	})
+
+	t.Run("No overlay returns notSynced", func(t *testing.T) {
+		types.Services.PeerAdmin = nil
+		defer func() { types.Services.PeerAdmin = admin }()
+
+		_, rpcErr := add.Handle(adminCtx(), json.RawMessage(`{"public_key": "`+testReservationKey+`"}`))
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, types.RpcNOT_SYNCED, rpcErr.Code)
+
+		result, rpcErr := list.Handle(adminCtx(), nil)
+		require.Nil(t, rpcErr)
+		assert.Empty(t, result.(map[string]interface{})["reservations"])
+	})
+}
+
+func TestConnectMethodDialsOverlay(t *testing.T) {
+	admin := newFakePeerAdmin()
+	cleanup := setupPeerAdminServices(admin)
+	defer cleanup()
+
+	method := &handlers.ConnectMethod{}
+
+	result, rpcErr := method.Handle(adminCtx(), json.RawMessage(`{"ip": "10.0.0.5"}`))
+	require.Nil(t, rpcErr)
+	assert.Equal(t, []string{"10.0.0.5:51235"}, admin.connected, "port defaults to 51235")
+	assert.Equal(t, true, result.(map[string]interface{})["connected"])
+
+	admin.connectErr = errors.New("connection refused")
+	result, rpcErr = method.Handle(adminCtx(), json.RawMessage(`{"ip": "10.0.0.6", "port": 2459}`))
+	require.Nil(t, rpcErr)
+	resultMap := result.(map[string]interface{})
+	assert.Equal(t, false, resultMap["connected"])
+	assert.Equal(t, "connection refused", resultMap["error_message"])
+	assert.Equal(t, "10.0.0.6:2459", admin.connected[1])
+}
+
+func TestBlackListAndUnlListUseServices(t *testing.T) {
+	admin := newFakePeerAdmin()
+	cleanup := setupPeerAdminServices(admin)
+	defer cleanup()
+
+	result, rpcErr := (&handlers.BlackListMethod{}).Handle(adminCtx(), nil)
+	require.Nil(t, rpcErr)
+	assert.Equal(t, 5000, admin.lastThreshold, "default threshold matches rippled warningThreshold")
+	assert.Contains(t, result.(map[string]interface{})["blacklist"], "10.0.0.1")
+
+	_, rpcErr = (&handlers.BlackListMethod{}).Handle(adminCtx(), json.RawMessage(`{"threshold": 100}`))
+	require.Nil(t, rpcErr)
+	assert.Equal(t, 100, admin.lastThreshold)
+
+	types.Services.UNLList = func() []types.UNLEntry {
+		return []types.UNLEntry{{PublicKey: testReservationKey, Trusted: true, NegativeUNL: true}}
+	}
+	result, rpcErr = (&handlers.UnlListMethod{}).Handle(adminCtx(), nil)
+	require.Nil(t, rpcErr)
+	unl := result.(map[string]interface{})["unl"].([]interface{})
+	require.Len(t, unl, 1)
+	entry := unl[0].(map[string]interface{})
+	assert.Equal(t, testReservationKey, entry["pubkey_validator"])
+	assert.Equal(t, true, entry["trusted"])
+	assert.Equal(t, true, entry["negative_unl"])
+}
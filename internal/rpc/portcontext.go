@@ -15,6 +15,9 @@ type PortContext struct {
 	AdminNets []net.IPNet
 	Limit     int // max concurrent connections; 0 = unlimited
 	SendQueue int // WS send channel buffer size; 0 = use default (100)
+
+	Deflate      bool // negotiate WS permessage-deflate
+	DeflateLevel int  // flate level 1-9; 0 = use default (8)
 }
 
 // WithPortContext returns a new context carrying the given PortContext.
@@ -1,8 +1,10 @@
 package rpc
 
 import (
+	"encoding/hex"
 	"encoding/json"
 
+	"github.com/LeJamon/goXRPLd/internal/rpc/handlers"
 	"github.com/LeJamon/goXRPLd/internal/rpc/subscription"
 	"github.com/LeJamon/goXRPLd/internal/rpc/types"
 	xrpllog "github.com/LeJamon/goXRPLd/log"
@@ -15,6 +17,10 @@ type EventPublisher interface {
 	// PublishLedgerClosed publishes a ledger close event to all ledger stream subscribers
 	PublishLedgerClosed(event *LedgerCloseEvent)
 
+	// PublishLedger publishes a validated ledger's ledgerClosed event, its
+	// transactions and its bookChanges as one unit
+	PublishLedger(event *LedgerCloseEvent, txs []LedgerTransaction)
+
 	// PublishTransaction publishes a transaction event to transaction stream subscribers
 	// If affectedAccounts is provided, the event is also sent to account subscribers
 	PublishTransaction(event *TransactionEvent, affectedAccounts []string)
@@ -37,16 +43,26 @@ type EventPublisher interface {
 	// PublishProposedTransaction publishes a proposed transaction to accounts_proposed subscribers
 	PublishProposedTransaction(event *ProposedTransactionEvent, accounts []string)
 
-	// PublishOrderBookChange publishes an order book change to book subscribers
+	// PublishOrderBookChange publishes an order book change to book subscribers.
+	// event.Domain selects a permissioned domain's book; empty is the open DEX.
 	PublishOrderBookChange(event *OrderBookChangeEvent, takerGets, takerPays types.CurrencySpec)
 
+	// PublishSubmission publishes a submitted transaction's state to the
+	// connections that submitted it
+	PublishSubmission(sub *types.Submission)
+
 	// GetSubscriberCount returns the number of active subscribers for a stream type
 	GetSubscriberCount(streamType types.SubscriptionType) int
 }
 
 // Note: CurrencySpec is defined in subscription_methods.go
 
-// Publisher implements EventPublisher using subscription.Manager
+// Publisher implements EventPublisher using subscription.Manager.
+//
+// Every event on a stream other than order books carries stream_seq, one
+// more than the previous event on that stream, so a subscriber can detect
+// gaps. Events filtered per subscriber (accounts, accounts_proposed,
+// submissions) are numbered per connection, by the subscription manager.
 type Publisher struct {
 	manager *subscription.Manager
 }
@@ -58,19 +74,69 @@ func NewPublisher(manager *subscription.Manager) *Publisher {
 	}
 }
 
-// PublishLedgerClosed broadcasts a ledger close event to all ledger stream subscribers
-func (p *Publisher) PublishLedgerClosed(event *LedgerCloseEvent) {
+// LedgerTransaction is a transaction event with the accounts it affects.
+type LedgerTransaction struct {
+	Event    *TransactionEvent
+	Accounts []string
+}
+
+// PublishLedger publishes a validated ledger's ledgerClosed event, then
+// its transactions and then its bookChanges as one unit, and records the
+// ledger as published for subscribe's resume_ledger.
+func (p *Publisher) PublishLedger(event *LedgerCloseEvent, txs []LedgerTransaction) {
 	if event == nil || p.manager == nil {
 		return
 	}
+	p.manager.Publish(func() {
+		p.publishLedgerClosed(event)
+		for _, tx := range txs {
+			if tx.Event != nil {
+				p.publishTransaction(tx.Event, tx.Accounts)
+			}
+		}
+		if p.manager.GetSubscriberCount(types.SubBookChanges) > 0 {
+			p.publishBookChanges(event, txs)
+		}
+		p.manager.SetPublishedLedger(event.LedgerIndex)
+	})
+}
 
-	data, err := json.Marshal(event)
-	if err != nil {
-		xrpllog.Named(xrpllog.PartitionRPC).Error("Failed to marshal LedgerCloseEvent", "err", err)
+// publishBookChanges broadcasts the ledger's per-book OHLCV aggregates,
+// the same object the book_changes RPC returns for it.
+func (p *Publisher) publishBookChanges(event *LedgerCloseEvent, txs []LedgerTransaction) {
+	changes := handlers.NewBookChanges(nil)
+	for _, tx := range txs {
+		if tx.Event == nil {
+			continue
+		}
+		var txJSON, meta map[string]interface{}
+		_ = json.Unmarshal(tx.Event.Transaction, &txJSON)
+		_ = json.Unmarshal(tx.Event.Meta, &meta)
+		changes.AddTransaction(txJSON, meta)
+	}
+
+	var hash [32]byte
+	_, _ = hex.Decode(hash[:], []byte(event.LedgerHash))
+	msg := handlers.BookChangesMessage(event.LedgerIndex, hash, int64(event.LedgerTime), true, changes.Changes())
+	msg["stream_seq"] = p.manager.NextSeq(types.SubBookChanges)
+	if data, ok := marshalEvent(msg, "bookChanges"); ok {
+		p.manager.BroadcastToStream(types.SubBookChanges, data, nil)
+	}
+}
+
+// PublishLedgerClosed broadcasts a ledger close event to all ledger stream subscribers
+func (p *Publisher) PublishLedgerClosed(event *LedgerCloseEvent) {
+	if event == nil || p.manager == nil {
 		return
 	}
+	p.manager.Publish(func() { p.publishLedgerClosed(event) })
+}
 
-	p.manager.BroadcastToStream(types.SubLedger, data, nil)
+func (p *Publisher) publishLedgerClosed(event *LedgerCloseEvent) {
+	event.StreamSeq = p.manager.NextSeq(types.SubLedger)
+	if data, ok := marshalEvent(event, "LedgerCloseEvent"); ok {
+		p.manager.BroadcastToStream(types.SubLedger, data, nil)
+	}
 }
 
 // PublishTransaction broadcasts a transaction event to subscribers
@@ -78,19 +144,62 @@ func (p *Publisher) PublishTransaction(event *TransactionEvent, affectedAccounts
 	if event == nil || p.manager == nil {
 		return
 	}
+	p.manager.Publish(func() { p.publishTransaction(event, affectedAccounts) })
+}
 
-	data, err := json.Marshal(event)
-	if err != nil {
-		xrpllog.Named(xrpllog.PartitionRPC).Error("Failed to marshal TransactionEvent", "err", err)
+func (p *Publisher) publishTransaction(event *TransactionEvent, affectedAccounts []string) {
+	event.StreamSeq = p.manager.NextSeq(types.SubTransactions)
+	data, ok := marshalEvent(event, "TransactionEvent")
+	if !ok {
 		return
 	}
 
 	// Broadcast to transactions stream
 	p.manager.BroadcastToStream(types.SubTransactions, data, nil)
 
-	// Also broadcast to affected account subscribers
+	// Account and order book subscribers get the transaction without the
+	// transactions stream's sequence number, which their streams don't
+	// follow.
+	var unnumberedData []byte
+	unnumbered := func() ([]byte, bool) {
+		if unnumberedData == nil {
+			event := *event
+			event.StreamSeq = 0
+			var ok bool
+			if unnumberedData, ok = marshalEvent(&event, "TransactionEvent"); !ok {
+				return nil, false
+			}
+		}
+		return unnumberedData, true
+	}
+
+	// Also broadcast to affected account subscribers, whose filters
+	// need the transaction's type, sender and destination
 	if len(affectedAccounts) > 0 {
-		p.manager.BroadcastToAccounts(data, affectedAccounts)
+		var txFields struct {
+			TransactionType string
+			Account         string
+			Destination     string
+		}
+		_ = json.Unmarshal(event.Transaction, &txFields)
+		if accountData, ok := unnumbered(); ok {
+			p.manager.BroadcastTxToAccounts(accountData, affectedAccounts, txFields.TransactionType, txFields.Account, txFields.Destination)
+		}
+	}
+
+	// Successful transactions also go to the subscribers of the order
+	// books whose offers or AMM pools they changed.
+	// Reference: rippled OrderBookDB::processTxn
+	if p.manager.GetSubscriberCount(types.SubOrderBooks) > 0 {
+		var meta map[string]interface{}
+		_ = json.Unmarshal(event.Meta, &meta)
+		if meta["TransactionResult"] == "tesSUCCESS" {
+			if books := handlers.TxOrderBooks(meta); len(books) > 0 {
+				if bookData, ok := unnumbered(); ok {
+					p.manager.BroadcastToOrderBooks(bookData, books)
+				}
+			}
+		}
 	}
 }
 
@@ -99,14 +208,12 @@ func (p *Publisher) PublishValidation(event *ValidationEvent) {
 	if event == nil || p.manager == nil {
 		return
 	}
-
-	data, err := json.Marshal(event)
-	if err != nil {
-		xrpllog.Named(xrpllog.PartitionRPC).Error("Failed to marshal ValidationEvent", "err", err)
-		return
-	}
-
-	p.manager.BroadcastToStream(types.SubValidations, data, nil)
+	p.manager.Publish(func() {
+		event.StreamSeq = p.manager.NextSeq(types.SubValidations)
+		if data, ok := marshalEvent(event, "ValidationEvent"); ok {
+			p.manager.BroadcastToStream(types.SubValidations, data, nil)
+		}
+	})
 }
 
 // PublishServerStatus broadcasts a server status event to server stream subscribers
@@ -114,14 +221,12 @@ func (p *Publisher) PublishServerStatus(event *ServerStatusEvent) {
 	if event == nil || p.manager == nil {
 		return
 	}
-
-	data, err := json.Marshal(event)
-	if err != nil {
-		xrpllog.Named(xrpllog.PartitionRPC).Error("Failed to marshal ServerStatusEvent", "err", err)
-		return
-	}
-
-	p.manager.BroadcastToStream(types.SubServer, data, nil)
+	p.manager.Publish(func() {
+		event.StreamSeq = p.manager.NextSeq(types.SubServer)
+		if data, ok := marshalEvent(event, "ServerStatusEvent"); ok {
+			p.manager.BroadcastToStream(types.SubServer, data, nil)
+		}
+	})
 }
 
 // PublishConsensusPhase broadcasts a consensus phase change event
@@ -129,15 +234,13 @@ func (p *Publisher) PublishConsensusPhase(phase string) {
 	if p.manager == nil {
 		return
 	}
-
 	event := NewConsensusEvent(phase)
-	data, err := json.Marshal(event)
-	if err != nil {
-		xrpllog.Named(xrpllog.PartitionRPC).Error("Failed to marshal ConsensusEvent", "err", err)
-		return
-	}
-
-	p.manager.BroadcastToStream(types.SubConsensus, data, nil)
+	p.manager.Publish(func() {
+		event.StreamSeq = p.manager.NextSeq(types.SubConsensus)
+		if data, ok := marshalEvent(event, "ConsensusEvent"); ok {
+			p.manager.BroadcastToStream(types.SubConsensus, data, nil)
+		}
+	})
 }
 
 // PublishManifest broadcasts a manifest event to manifest stream subscribers
@@ -145,14 +248,12 @@ func (p *Publisher) PublishManifest(event *ManifestEvent) {
 	if event == nil || p.manager == nil {
 		return
 	}
-
-	data, err := json.Marshal(event)
-	if err != nil {
-		xrpllog.Named(xrpllog.PartitionRPC).Error("Failed to marshal ManifestEvent", "err", err)
-		return
-	}
-
-	p.manager.BroadcastToStream(types.SubManifests, data, nil)
+	p.manager.Publish(func() {
+		event.StreamSeq = p.manager.NextSeq(types.SubManifests)
+		if data, ok := marshalEvent(event, "ManifestEvent"); ok {
+			p.manager.BroadcastToStream(types.SubManifests, data, nil)
+		}
+	})
 }
 
 // PublishPeerStatus broadcasts a peer status event to peer_status stream subscribers
@@ -160,14 +261,12 @@ func (p *Publisher) PublishPeerStatus(event *PeerStatusEvent) {
 	if event == nil || p.manager == nil {
 		return
 	}
-
-	data, err := json.Marshal(event)
-	if err != nil {
-		xrpllog.Named(xrpllog.PartitionRPC).Error("Failed to marshal PeerStatusEvent", "err", err)
-		return
-	}
-
-	p.manager.BroadcastToStream(types.SubPeerStatus, data, nil)
+	p.manager.Publish(func() {
+		event.StreamSeq = p.manager.NextSeq(types.SubPeerStatus)
+		if data, ok := marshalEvent(event, "PeerStatusEvent"); ok {
+			p.manager.BroadcastToStream(types.SubPeerStatus, data, nil)
+		}
+	})
 }
 
 // PublishProposedTransaction broadcasts a proposed transaction to accounts_proposed subscribers
@@ -175,35 +274,63 @@ func (p *Publisher) PublishProposedTransaction(event *ProposedTransactionEvent,
 	if event == nil || p.manager == nil {
 		return
 	}
+	p.manager.Publish(func() {
+		event.StreamSeq = p.manager.NextSeq(types.SubTransactionsProposed)
+		data, ok := marshalEvent(event, "ProposedTransactionEvent")
+		if !ok {
+			return
+		}
+		// Broadcast to transactions_proposed stream (all proposed txs)
+		p.manager.BroadcastToStream(types.SubTransactionsProposed, data, nil)
+		// Also broadcast to accounts_proposed subscribers for specific accounts
+		if len(accounts) > 0 {
+			unnumbered := *event
+			unnumbered.StreamSeq = 0
+			if accountData, ok := marshalEvent(&unnumbered, "ProposedTransactionEvent"); ok {
+				p.manager.BroadcastToAccountsProposed(accountData, accounts)
+			}
+		}
+	})
+}
 
-	data, err := json.Marshal(event)
-	if err != nil {
-		xrpllog.Named(xrpllog.PartitionRPC).Error("Failed to marshal ProposedTransactionEvent", "err", err)
+// PublishOrderBookChange broadcasts an order book change to book subscribers
+func (p *Publisher) PublishOrderBookChange(event *OrderBookChangeEvent, takerGets, takerPays types.CurrencySpec) {
+	if event == nil || p.manager == nil {
 		return
 	}
 
-	// Broadcast to transactions_proposed stream (all proposed txs)
-	p.manager.BroadcastToStream(types.SubTransactionsProposed, data, nil)
-	// Also broadcast to accounts_proposed subscribers for specific accounts
-	if len(accounts) > 0 {
-		p.manager.BroadcastToAccountsProposed(data, accounts)
+	data, ok := marshalEvent(event, "OrderBookChangeEvent")
+	if !ok {
+		return
 	}
+
+	// Broadcast to subscribers of this specific order book
+	p.manager.BroadcastToOrderBook(data, takerGets, takerPays, event.Domain)
 }
 
-// PublishOrderBookChange broadcasts an order book change to book subscribers
-func (p *Publisher) PublishOrderBookChange(event *OrderBookChangeEvent, takerGets, takerPays types.CurrencySpec) {
-	if event == nil || p.manager == nil {
+// PublishSubmission sends a submitted transaction's state, as tx_status
+// reports it, to the submissions stream subscribers that submitted it
+func (p *Publisher) PublishSubmission(sub *types.Submission) {
+	if sub == nil || p.manager == nil {
 		return
 	}
+	event := handlers.SubmissionJSON(sub)
+	event["type"] = "submission"
+	p.manager.Publish(func() {
+		if data, ok := marshalEvent(event, "SubmissionEvent"); ok {
+			p.manager.BroadcastToSubmitters(data, event["hash"].(string), sub.Final)
+		}
+	})
+}
 
+// marshalEvent marshals a stream event, logging failures.
+func marshalEvent(event interface{}, name string) ([]byte, bool) {
 	data, err := json.Marshal(event)
 	if err != nil {
-		xrpllog.Named(xrpllog.PartitionRPC).Error("Failed to marshal OrderBookChangeEvent", "err", err)
-		return
+		xrpllog.Named(xrpllog.PartitionRPC).Error("Failed to marshal "+name, "err", err)
+		return nil, false
 	}
-
-	// Broadcast to subscribers of this specific order book
-	p.manager.BroadcastToOrderBook(data, takerGets, takerPays)
+	return data, true
 }
 
 // GetSubscriberCount returns the number of active subscribers for a stream type
@@ -221,17 +348,19 @@ func NewNoOpPublisher() *NoOpPublisher {
 	return &NoOpPublisher{}
 }
 
-func (p *NoOpPublisher) PublishLedgerClosed(event *LedgerCloseEvent)                   {}
-func (p *NoOpPublisher) PublishTransaction(event *TransactionEvent, accounts []string) {}
-func (p *NoOpPublisher) PublishValidation(event *ValidationEvent)                      {}
-func (p *NoOpPublisher) PublishServerStatus(event *ServerStatusEvent)                  {}
-func (p *NoOpPublisher) PublishConsensusPhase(phase string)                            {}
-func (p *NoOpPublisher) PublishManifest(event *ManifestEvent)                          {}
-func (p *NoOpPublisher) PublishPeerStatus(event *PeerStatusEvent)                      {}
+func (p *NoOpPublisher) PublishLedger(event *LedgerCloseEvent, txs []LedgerTransaction) {}
+func (p *NoOpPublisher) PublishLedgerClosed(event *LedgerCloseEvent)                    {}
+func (p *NoOpPublisher) PublishTransaction(event *TransactionEvent, accounts []string)  {}
+func (p *NoOpPublisher) PublishValidation(event *ValidationEvent)                       {}
+func (p *NoOpPublisher) PublishServerStatus(event *ServerStatusEvent)                   {}
+func (p *NoOpPublisher) PublishConsensusPhase(phase string)                             {}
+func (p *NoOpPublisher) PublishManifest(event *ManifestEvent)                           {}
+func (p *NoOpPublisher) PublishPeerStatus(event *PeerStatusEvent)                       {}
 func (p *NoOpPublisher) PublishProposedTransaction(event *ProposedTransactionEvent, accounts []string) {
 }
 func (p *NoOpPublisher) PublishOrderBookChange(event *OrderBookChangeEvent, takerGets, takerPays types.CurrencySpec) {
 }
+func (p *NoOpPublisher) PublishSubmission(sub *types.Submission)                  {}
 func (p *NoOpPublisher) GetSubscriberCount(streamType types.SubscriptionType) int { return 0 }
 
 // Ensure implementations satisfy the interface
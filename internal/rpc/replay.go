@@ -0,0 +1,159 @@
+package rpc
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	binarycodec "github.com/LeJamon/goXRPLd/codec/binarycodec"
+	"github.com/LeJamon/goXRPLd/internal/tx"
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+// MaxResumeLedgers bounds how many ledgers subscribe's resume_ledger may
+// replay.
+const MaxResumeLedgers = 256
+
+// maxReplayTransactions bounds the transactions read for one replay.
+const maxReplayTransactions = 100000
+
+// TransactionReplayer replays the transaction stream events of stored
+// validated ledgers.
+type TransactionReplayer interface {
+	// LedgerRange returns the first and last validated ledgers whose
+	// transactions can be replayed, or 0, 0 if there are none.
+	LedgerRange(ctx context.Context) (minSeq, maxSeq uint32, err error)
+
+	// Replay returns the events of ledgers minSeq through maxSeq, in
+	// ledger and transaction order.
+	Replay(ctx context.Context, minSeq, maxSeq uint32) ([]*TransactionEvent, error)
+}
+
+// relationalTxReplayer replays transactions from the relational database.
+type relationalTxReplayer struct {
+	rm relationaldb.RepositoryManager
+}
+
+// NewRelationalTxReplayer replays transactions from the relational database.
+func NewRelationalTxReplayer(rm relationaldb.RepositoryManager) TransactionReplayer {
+	return &relationalTxReplayer{rm: rm}
+}
+
+// LedgerRange returns the range of validated ledgers in the database.
+func (r *relationalTxReplayer) LedgerRange(ctx context.Context) (uint32, uint32, error) {
+	minSeq, err := r.rm.Ledger().GetMinLedgerSeq(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	maxSeq, err := r.rm.Ledger().GetMaxLedgerSeq(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	if minSeq == nil || maxSeq == nil {
+		return 0, 0, nil
+	}
+	return uint32(*minSeq), uint32(*maxSeq), nil
+}
+
+// Replay reads the transactions of ledgers minSeq through maxSeq.
+func (r *relationalTxReplayer) Replay(ctx context.Context, minSeq, maxSeq uint32) ([]*TransactionEvent, error) {
+	infos, err := r.rm.Transaction().GetTransactionsByLedgerRange(ctx,
+		relationaldb.LedgerIndex(minSeq), relationaldb.LedgerIndex(maxSeq), maxReplayTransactions)
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) == maxReplayTransactions {
+		return nil, fmt.Errorf("more than %d transactions to replay", maxReplayTransactions)
+	}
+	hashes, err := r.rm.Ledger().GetHashesByRange(ctx, relationaldb.LedgerIndex(minSeq), relationaldb.LedgerIndex(maxSeq))
+	if err != nil {
+		return nil, err
+	}
+
+	type replayed struct {
+		event *TransactionEvent
+		index uint32
+	}
+	txs := make([]replayed, 0, len(infos))
+	for _, info := range infos {
+		event, index, err := replayedTransactionEvent(info)
+		if err != nil {
+			return nil, err
+		}
+		if pair, ok := hashes[info.LedgerSeq]; ok {
+			event.LedgerHash = hex.EncodeToString(pair.LedgerHash[:])
+		}
+		txs = append(txs, replayed{event: event, index: index})
+	}
+	sort.SliceStable(txs, func(i, j int) bool {
+		if txs[i].event.LedgerIndex != txs[j].event.LedgerIndex {
+			return txs[i].event.LedgerIndex < txs[j].event.LedgerIndex
+		}
+		return txs[i].index < txs[j].index
+	})
+
+	events := make([]*TransactionEvent, len(txs))
+	for i, t := range txs {
+		events[i] = t.event
+	}
+	return events, nil
+}
+
+// replayedTransactionEvent builds the stream event of a stored transaction
+// and returns its index within the ledger.
+func replayedTransactionEvent(info relationaldb.TransactionInfo) (*TransactionEvent, uint32, error) {
+	txJSON, err := binarycodec.Decode(hex.EncodeToString(info.RawTxn))
+	if err != nil {
+		return nil, 0, fmt.Errorf("decode transaction %x: %w", info.Hash[:], err)
+	}
+	metaJSON, err := binarycodec.Decode(hex.EncodeToString(info.TxnMeta))
+	if err != nil {
+		return nil, 0, fmt.Errorf("decode metadata of %x: %w", info.Hash[:], err)
+	}
+
+	var index uint32
+	if v, ok := metaJSON["TransactionIndex"].(float64); ok {
+		index = uint32(v)
+	}
+	result := tx.TesSUCCESS
+	if name, ok := metaJSON["TransactionResult"].(string); ok {
+		if r, ok := appliedResults[name]; ok {
+			result = r
+		}
+	}
+
+	txBytes, err := json.Marshal(txJSON)
+	if err != nil {
+		return nil, 0, err
+	}
+	metaBytes, err := json.Marshal(metaJSON)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &TransactionEvent{
+		Type:                "transaction",
+		EngineResult:        result.String(),
+		EngineResultCode:    int(result),
+		EngineResultMessage: result.Message(),
+		LedgerIndex:         uint32(info.LedgerSeq),
+		Transaction:         txBytes,
+		Meta:                metaBytes,
+		Hash:                hex.EncodeToString(info.Hash[:]),
+		Validated:           true,
+		Replayed:            true,
+	}, index, nil
+}
+
+// appliedResults maps the names of the results a validated transaction can
+// have, tesSUCCESS and the tec codes, to their values.
+var appliedResults = func() map[string]tx.Result {
+	names := make(map[string]tx.Result)
+	for r := tx.TesSUCCESS; r < 200; r++ {
+		if r.IsApplied() {
+			names[r.String()] = r
+		}
+	}
+	return names
+}()
@@ -875,6 +875,42 @@ func TestServerStateMethod(t *testing.T) {
 	})
 }
 
+// TestServerInfoClusterLoadFactor checks that a cluster fee above the
+// base raises load_factor and that load_factor_cluster is reported to
+// admin callers only, as in rippled NetworkOPsImp::getServerInfo.
+func TestServerInfoClusterLoadFactor(t *testing.T) {
+	mock := newMockLedgerServiceServerInfo()
+	cleanup := setupTestServicesServerInfo(mock)
+	defer cleanup()
+
+	infoFor := func(admin bool) map[string]interface{} {
+		ctx := &types.RpcContext{Context: context.Background(), Role: types.RoleGuest, ApiVersion: types.ApiVersion1, IsAdmin: admin}
+		result, rpcErr := (&handlers.ServerInfoMethod{}).Handle(ctx, nil)
+		require.Nil(t, rpcErr)
+		return result.(map[string]interface{})["info"].(map[string]interface{})
+	}
+
+	info := infoFor(true)
+	assert.Equal(t, 1.0, info["load_factor"])
+	assert.NotContains(t, info, "load_factor_cluster", "no cluster fee reported")
+
+	types.Services.ClusterLoadFee = func() uint32 { return 512 }
+
+	info = infoFor(true)
+	assert.Equal(t, 2.0, info["load_factor"])
+	assert.Equal(t, 2.0, info["load_factor_cluster"])
+
+	info = infoFor(false)
+	assert.Equal(t, 2.0, info["load_factor"])
+	assert.NotContains(t, info, "load_factor_cluster", "per-source factors are admin-only")
+
+	state, rpcErr := (&handlers.ServerStateMethod{}).Handle(&types.RpcContext{Context: context.Background(), ApiVersion: types.ApiVersion1}, nil)
+	require.Nil(t, rpcErr)
+	stateInfo := state.(map[string]interface{})["state"].(map[string]interface{})
+	assert.EqualValues(t, 512, stateInfo["load_factor"])
+	assert.EqualValues(t, 256, stateInfo["load_base"])
+}
+
 // TestServerStateMethodMetadata tests the server_state method's metadata functions
 func TestServerStateMethodMetadata(t *testing.T) {
 	method := &handlers.ServerStateMethod{}
@@ -176,7 +176,7 @@ func TestSubscribeConformanceUnsubscribeAccountStopsDelivery(t *testing.T) {
 
 	select {
 	case received := <-conn.SendChannel:
-		assert.Equal(t, msg1, received)
+		assert.JSONEq(t, `{"type":"transaction","account":"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh","stream_seq":1}`, string(received))
 	default:
 		t.Fatal("Expected to receive message for subscribed account")
 	}
@@ -304,7 +304,7 @@ func TestSubscribeConformanceFullLifecycle(t *testing.T) {
 	sm.BroadcastToAccounts(aliceMsg, []string{alice})
 	select {
 	case received := <-conn.SendChannel:
-		assert.Equal(t, aliceMsg, received)
+		assert.JSONEq(t, `{"type":"transaction","account":"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh","stream_seq":1}`, string(received))
 	default:
 		t.Fatal("Expected message for subscribed account")
 	}
@@ -421,27 +421,27 @@ func TestSubscribeConformanceLedgerResponseFields(t *testing.T) {
 // Based on rippled Subscribe_test.cpp testSubBookChanges()
 
 // TestSubscribeConformanceBookChangesStream verifies that subscribing to the
-// book_changes stream works correctly, matching the SubOrderBooks constant.
+// book_changes stream works correctly, matching the SubBookChanges constant.
 func TestSubscribeConformanceBookChangesStream(t *testing.T) {
 	sm := newTestSubscriptionManager()
 	conn := newTestConnection("test-conn-1")
 	sm.AddConnection(conn)
 	defer sm.RemoveConnection(conn.ID)
 
-	// SubOrderBooks maps to "book_changes" stream name
+	// SubBookChanges maps to "book_changes" stream name
 	request := types.SubscriptionRequest{
-		Streams: []types.SubscriptionType{types.SubOrderBooks},
+		Streams: []types.SubscriptionType{types.SubBookChanges},
 	}
 
 	err := sm.HandleSubscribe(conn, request)
 	require.Nil(t, err, "Subscribe to book_changes stream should succeed")
 
-	_, exists := conn.Subscriptions[types.SubOrderBooks]
+	_, exists := conn.Subscriptions[types.SubBookChanges]
 	assert.True(t, exists, "book_changes subscription should be recorded")
 
 	// Broadcast to book_changes and verify delivery
 	msg := []byte(`{"type":"bookChanges","changes":[]}`)
-	sm.BroadcastToStream(types.SubOrderBooks, msg, nil)
+	sm.BroadcastToStream(types.SubBookChanges, msg, nil)
 
 	select {
 	case received := <-conn.SendChannel:
@@ -452,11 +452,11 @@ func TestSubscribeConformanceBookChangesStream(t *testing.T) {
 
 	// Unsubscribe
 	err = sm.HandleUnsubscribe(conn, types.SubscriptionRequest{
-		Streams: []types.SubscriptionType{types.SubOrderBooks},
+		Streams: []types.SubscriptionType{types.SubBookChanges},
 	})
 	require.Nil(t, err)
 
-	_, exists = conn.Subscriptions[types.SubOrderBooks]
+	_, exists = conn.Subscriptions[types.SubBookChanges]
 	assert.False(t, exists, "book_changes subscription should be removed")
 }
 
@@ -709,7 +709,7 @@ func TestSubscribeConformanceSelectiveUnsubscribe(t *testing.T) {
 	sm.BroadcastToAccounts(acctMsg, []string{alice})
 	select {
 	case received := <-conn.SendChannel:
-		assert.Equal(t, acctMsg, received)
+		assert.JSONEq(t, `{"type":"transaction","account":"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh","stream_seq":1}`, string(received))
 	default:
 		t.Fatal("Account broadcast should still work")
 	}
@@ -0,0 +1,510 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Stream ordering, slow consumer and resume_ledger tests.
+// Based on rippled WSSession.h (send queue limit) and NetworkOPs.cpp.
+
+func TestSlowConsumerCallback(t *testing.T) {
+	sm := newTestSubscriptionManager()
+	var slow atomic.Int32
+	conn := &types.Connection{
+		ID:            "slow",
+		Subscriptions: map[types.SubscriptionType]types.SubscriptionConfig{types.SubLedger: {}},
+		SendChannel:   make(chan []byte, 2),
+		CloseChannel:  make(chan struct{}),
+		SlowConsumer:  func() { slow.Add(1) },
+	}
+	sm.AddConnection(conn)
+
+	sm.BroadcastToStream(types.SubLedger, []byte(`{"n":1}`), nil)
+	sm.BroadcastToStream(types.SubLedger, []byte(`{"n":2}`), nil)
+	assert.Equal(t, int32(0), slow.Load())
+
+	sm.BroadcastToStream(types.SubLedger, []byte(`{"n":3}`), nil)
+	assert.Equal(t, int32(1), slow.Load())
+	assert.Len(t, conn.SendChannel, 2)
+}
+
+func TestPublisherStreamSeq(t *testing.T) {
+	sm := newTestSubscriptionManager()
+	conn := newTestConnection("conn")
+	sm.AddConnection(conn)
+	require.Nil(t, sm.HandleSubscribe(conn, types.SubscriptionRequest{
+		Streams: []types.SubscriptionType{types.SubLedger, types.SubTransactions},
+	}))
+	p := NewPublisher(sm)
+
+	p.PublishLedger(&LedgerCloseEvent{Type: "ledgerClosed", LedgerIndex: 10}, []LedgerTransaction{
+		{Event: &TransactionEvent{Type: "transaction", LedgerIndex: 10, Hash: "A"}},
+		{Event: &TransactionEvent{Type: "transaction", LedgerIndex: 10, Hash: "B"}},
+	})
+	p.PublishLedger(&LedgerCloseEvent{Type: "ledgerClosed", LedgerIndex: 11}, []LedgerTransaction{
+		{Event: &TransactionEvent{Type: "transaction", LedgerIndex: 11, Hash: "C"}},
+	})
+	assert.Equal(t, uint32(11), sm.PublishedLedger())
+
+	var got []string
+	for len(conn.SendChannel) > 0 {
+		var event struct {
+			Type      string `json:"type"`
+			StreamSeq uint64 `json:"stream_seq"`
+		}
+		require.NoError(t, json.Unmarshal(<-conn.SendChannel, &event))
+		got = append(got, fmt.Sprintf("%s:%d", event.Type, event.StreamSeq))
+	}
+	// Each stream is numbered on its own, in publication order.
+	assert.Equal(t, []string{
+		"ledgerClosed:1", "transaction:1", "transaction:2",
+		"ledgerClosed:2", "transaction:3",
+	}, got)
+}
+
+// Account subscribers are numbered per connection, so a filter leaves no
+// gap.
+func TestPublisherAccountStreamSeq(t *testing.T) {
+	sm := newTestSubscriptionManager()
+	const alice = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	const bob = "rPMh7Pi9ct699iZUTWaytJUoHcJ7cgyziK"
+	aliceConn := newTestConnection("alice")
+	bobConn := newTestConnection("bob")
+	sm.AddConnection(aliceConn)
+	sm.AddConnection(bobConn)
+	require.Nil(t, sm.HandleSubscribe(aliceConn, types.SubscriptionRequest{Accounts: []string{alice}}))
+	require.Nil(t, sm.HandleSubscribe(bobConn, types.SubscriptionRequest{Accounts: []string{bob}, AccountsProposed: []string{bob}}))
+	p := NewPublisher(sm)
+
+	p.PublishLedger(&LedgerCloseEvent{Type: "ledgerClosed", LedgerIndex: 10}, []LedgerTransaction{
+		{Event: &TransactionEvent{Type: "transaction", Hash: "A"}, Accounts: []string{alice}},
+		{Event: &TransactionEvent{Type: "transaction", Hash: "B"}, Accounts: []string{bob}},
+		{Event: &TransactionEvent{Type: "transaction", Hash: "C"}, Accounts: []string{alice, bob}},
+	})
+	p.PublishProposedTransaction(&ProposedTransactionEvent{Type: "transaction"}, []string{bob})
+
+	received := func(conn *types.Connection) []string {
+		var got []string
+		for len(conn.SendChannel) > 0 {
+			var event struct {
+				Hash      string `json:"hash"`
+				StreamSeq uint64 `json:"stream_seq"`
+			}
+			require.NoError(t, json.Unmarshal(<-conn.SendChannel, &event))
+			got = append(got, fmt.Sprintf("%s:%d", event.Hash, event.StreamSeq))
+		}
+		return got
+	}
+	assert.Equal(t, []string{"A:1", "C:2"}, received(aliceConn))
+	assert.Equal(t, []string{"B:1", "C:2", ":1"}, received(bobConn))
+}
+
+func TestSubscribeAfter(t *testing.T) {
+	sm := newTestSubscriptionManager()
+	conn := newTestConnection("conn")
+	sm.AddConnection(conn)
+	NewPublisher(sm).PublishLedger(&LedgerCloseEvent{LedgerIndex: 5}, nil)
+
+	request := types.SubscriptionRequest{Streams: []types.SubscriptionType{types.SubTransactions}}
+	ok, err := sm.SubscribeAfter(4, conn, request)
+	require.Nil(t, err)
+	assert.False(t, ok, "ledger 5 was published after the resume point")
+	assert.Equal(t, 0, sm.GetSubscriberCount(types.SubTransactions))
+
+	ok, err = sm.SubscribeAfter(5, conn, request)
+	require.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, sm.GetSubscriberCount(types.SubTransactions))
+}
+
+func TestPublishSubmission(t *testing.T) {
+	sm := newTestSubscriptionManager()
+	submitter := newTestConnection("submitter")
+	other := newTestConnection("other")
+	unsubscribed := newTestConnection("unsubscribed")
+	for _, conn := range []*types.Connection{submitter, other, unsubscribed} {
+		sm.AddConnection(conn)
+	}
+	request := types.SubscriptionRequest{Streams: []types.SubscriptionType{types.SubSubmissions}}
+	require.Nil(t, sm.HandleSubscribe(submitter, request))
+	require.Nil(t, sm.HandleSubscribe(other, request))
+
+	hash := "AB" + strings.Repeat("00", 31)
+	sm.TrackSubmission(submitter.ID, hash)
+	sm.TrackSubmission(unsubscribed.ID, hash)
+	p := NewPublisher(sm)
+
+	// Only the subscribed submitter hears of the transaction, until its
+	// state is final.
+	p.PublishSubmission(&types.Submission{Hash: [32]byte{0xAB}, State: "open_ledger"})
+	p.PublishSubmission(&types.Submission{Hash: [32]byte{0xAB}, State: "validated", Final: true, LedgerIndex: 9})
+	p.PublishSubmission(&types.Submission{Hash: [32]byte{0xAB}, State: "validated", Final: true, LedgerIndex: 9})
+	assert.Empty(t, other.SendChannel)
+	assert.Empty(t, unsubscribed.SendChannel)
+
+	var got []string
+	for len(submitter.SendChannel) > 0 {
+		var event struct {
+			Type      string `json:"type"`
+			Hash      string `json:"hash"`
+			Status    string `json:"status"`
+			StreamSeq uint64 `json:"stream_seq"`
+		}
+		require.NoError(t, json.Unmarshal(<-submitter.SendChannel, &event))
+		assert.Equal(t, "submission", event.Type)
+		assert.Equal(t, hash, event.Hash)
+		got = append(got, fmt.Sprintf("%s:%d", event.Status, event.StreamSeq))
+	}
+	assert.Equal(t, []string{"open_ledger:1", "validated:2"}, got)
+}
+
+// dialTestWebSocket serves ws and returns a client connection to it.
+func dialTestWebSocket(t *testing.T, ws *WebSocketServer, pc *PortContext) *websocket.Conn {
+	t.Helper()
+	srv := httptest.NewServer(PortMiddleware(pc, nil, ws))
+	t.Cleanup(srv.Close)
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func readTestMessage(t *testing.T, client *websocket.Conn) map[string]interface{} {
+	t.Helper()
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var msg map[string]interface{}
+	require.NoError(t, client.ReadJSON(&msg))
+	return msg
+}
+
+func TestWebSocketSlowConsumerDisconnected(t *testing.T) {
+	ws := NewWebSocketServer(time.Second)
+	client := dialTestWebSocket(t, ws, &PortContext{PortName: "ws", SendQueue: 1})
+
+	require.NoError(t, client.WriteJSON(map[string]interface{}{
+		"command": "subscribe", "id": 1, "streams": []string{"ledger"},
+	}))
+	assert.Equal(t, "success", readTestMessage(t, client)["status"])
+
+	// Publish faster than the connection drains a one-message queue.
+	p := NewPublisher(ws.GetSubscriptionManager())
+	for i := uint32(1); i <= 1000; i++ {
+		p.PublishLedgerClosed(&LedgerCloseEvent{Type: "ledgerClosed", LedgerIndex: i})
+	}
+
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		_, _, err := client.ReadMessage()
+		if err == nil {
+			continue
+		}
+		var closeErr *websocket.CloseError
+		require.ErrorAs(t, err, &closeErr)
+		assert.Equal(t, websocket.ClosePolicyViolation, closeErr.Code)
+		assert.Equal(t, slowConsumerReason, closeErr.Text)
+		return
+	}
+}
+
+// testReplayer replays one transaction per stored ledger.
+type testReplayer struct {
+	minSeq, maxSeq uint32
+	replayed       [][2]uint32
+}
+
+func (r *testReplayer) LedgerRange(ctx context.Context) (uint32, uint32, error) {
+	return r.minSeq, r.maxSeq, nil
+}
+
+func (r *testReplayer) Replay(ctx context.Context, minSeq, maxSeq uint32) ([]*TransactionEvent, error) {
+	r.replayed = append(r.replayed, [2]uint32{minSeq, maxSeq})
+	var events []*TransactionEvent
+	for seq := minSeq; seq <= maxSeq; seq++ {
+		events = append(events, &TransactionEvent{Type: "transaction", LedgerIndex: seq, Validated: true, Replayed: true})
+	}
+	return events, nil
+}
+
+func TestWebSocketResumeLedger(t *testing.T) {
+	ws := NewWebSocketServer(time.Second)
+	p := NewPublisher(ws.GetSubscriptionManager())
+	for i := uint32(1); i <= 12; i++ {
+		p.PublishLedger(&LedgerCloseEvent{Type: "ledgerClosed", LedgerIndex: i}, nil)
+	}
+
+	replayer := &testReplayer{minSeq: 1, maxSeq: 12}
+	ws.SetTransactionReplayer(replayer)
+	client := dialTestWebSocket(t, ws, &PortContext{PortName: "ws"})
+
+	require.NoError(t, client.WriteJSON(map[string]interface{}{
+		"command": "subscribe", "id": 1, "streams": []string{"transactions"}, "resume_ledger": 10,
+	}))
+	for seq := 10; seq <= 12; seq++ {
+		msg := readTestMessage(t, client)
+		assert.Equal(t, "transaction", msg["type"])
+		assert.Equal(t, float64(seq), msg["ledger_index"])
+		assert.Equal(t, true, msg["replayed"])
+	}
+	assert.Equal(t, "success", readTestMessage(t, client)["status"])
+	assert.Equal(t, [][2]uint32{{10, 12}}, replayer.replayed)
+
+	// Live events follow the replayed ones.
+	p.PublishLedger(&LedgerCloseEvent{Type: "ledgerClosed", LedgerIndex: 13}, []LedgerTransaction{
+		{Event: &TransactionEvent{Type: "transaction", LedgerIndex: 13}},
+	})
+	msg := readTestMessage(t, client)
+	assert.Equal(t, float64(13), msg["ledger_index"])
+	assert.Nil(t, msg["replayed"])
+}
+
+// After a restart, ledgers validated before it are replayed from storage.
+func TestWebSocketResumeLedgerAfterRestart(t *testing.T) {
+	ws := NewWebSocketServer(time.Second)
+	sm := ws.GetSubscriptionManager()
+	sm.Publish(func() { sm.SetPublishedLedger(30) })
+	replayer := &testReplayer{minSeq: 5, maxSeq: 30}
+	ws.SetTransactionReplayer(replayer)
+	client := dialTestWebSocket(t, ws, &PortContext{PortName: "ws"})
+
+	require.NoError(t, client.WriteJSON(map[string]interface{}{
+		"command": "subscribe", "id": 1, "streams": []string{"transactions"}, "resume_ledger": 28,
+	}))
+	for seq := 28; seq <= 30; seq++ {
+		assert.Equal(t, float64(seq), readTestMessage(t, client)["ledger_index"])
+	}
+	assert.Equal(t, "success", readTestMessage(t, client)["status"])
+	assert.Equal(t, [][2]uint32{{28, 30}}, replayer.replayed)
+
+	// Ledgers outside the stored range cannot be resumed from.
+	for _, resume := range []uint32{4, 32} {
+		require.NoError(t, client.WriteJSON(map[string]interface{}{
+			"command": "subscribe", "streams": []string{"transactions"}, "resume_ledger": resume,
+		}))
+		assert.Equal(t, "lgrNotFound", readTestMessage(t, client)["error"], "resume_ledger %d", resume)
+	}
+}
+
+func TestWebSocketResumeLedgerErrors(t *testing.T) {
+	ws := NewWebSocketServer(time.Second)
+	p := NewPublisher(ws.GetSubscriptionManager())
+	p.PublishLedger(&LedgerCloseEvent{LedgerIndex: MaxResumeLedgers + 10}, nil)
+	client := dialTestWebSocket(t, ws, &PortContext{PortName: "ws"})
+
+	subscribe := func(params map[string]interface{}) map[string]interface{} {
+		params["command"] = "subscribe"
+		require.NoError(t, client.WriteJSON(params))
+		return readTestMessage(t, client)
+	}
+
+	msg := subscribe(map[string]interface{}{"streams": []string{"transactions"}, "resume_ledger": 1})
+	assert.Equal(t, "notEnabled", msg["error"], "no replayer configured")
+
+	ws.SetTransactionReplayer(&testReplayer{minSeq: 1, maxSeq: MaxResumeLedgers + 10})
+	msg = subscribe(map[string]interface{}{"streams": []string{"ledger"}, "resume_ledger": 20})
+	assert.Equal(t, "invalidParams", msg["error"])
+
+	msg = subscribe(map[string]interface{}{"streams": []string{"transactions"}, "resume_ledger": 10})
+	assert.Equal(t, "invalidParams", msg["error"])
+	assert.Contains(t, msg["error_message"], "too old")
+}
+
+func TestPublishLedgerBookStreams(t *testing.T) {
+	sm := newTestSubscriptionManager()
+	const issuer = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	usd := map[string]interface{}{"currency": "USD", "issuer": issuer}
+	usdJSON, _ := json.Marshal(usd)
+
+	bookChanges := newTestConnection("book_changes")
+	book := newTestConnection("book")
+	reverse := newTestConnection("reverse")
+	both := newTestConnection("both")
+	for _, conn := range []*types.Connection{bookChanges, book, reverse, both} {
+		sm.AddConnection(conn)
+	}
+	require.Nil(t, sm.HandleSubscribe(bookChanges, types.SubscriptionRequest{
+		Streams: []types.SubscriptionType{types.SubBookChanges},
+	}))
+	require.Nil(t, sm.HandleSubscribe(book, types.SubscriptionRequest{
+		Books: []types.BookRequest{{TakerGets: json.RawMessage(`{"currency":"XRP"}`), TakerPays: usdJSON}},
+	}))
+	require.Nil(t, sm.HandleSubscribe(reverse, types.SubscriptionRequest{
+		Books: []types.BookRequest{{TakerGets: usdJSON, TakerPays: json.RawMessage(`{"currency":"XRP"}`)}},
+	}))
+	require.Nil(t, sm.HandleSubscribe(both, types.SubscriptionRequest{
+		Books: []types.BookRequest{{TakerGets: usdJSON, TakerPays: json.RawMessage(`{"currency":"XRP"}`), Both: true}},
+	}))
+
+	usdValue := func(v string) map[string]interface{} {
+		return map[string]interface{}{"currency": "USD", "issuer": issuer, "value": v}
+	}
+	meta, _ := json.Marshal(map[string]interface{}{
+		"TransactionIndex":  0,
+		"TransactionResult": "tesSUCCESS",
+		"AffectedNodes": []interface{}{map[string]interface{}{"ModifiedNode": map[string]interface{}{
+			"LedgerEntryType": "Offer",
+			"FinalFields":     map[string]interface{}{"TakerGets": "1000", "TakerPays": usdValue("10")},
+			"PreviousFields":  map[string]interface{}{"TakerGets": "2000", "TakerPays": usdValue("20")},
+		}}},
+	})
+	NewPublisher(sm).PublishLedger(&LedgerCloseEvent{Type: "ledgerClosed", LedgerIndex: 7, LedgerTime: 99}, []LedgerTransaction{
+		{Event: &TransactionEvent{Type: "transaction", Transaction: json.RawMessage(`{"TransactionType":"Payment"}`), Meta: meta}},
+	})
+
+	// Book subscribers receive the transaction, the reverse book only
+	// with "both", without the transactions stream's sequence number.
+	for _, conn := range []*types.Connection{book, both} {
+		require.Len(t, conn.SendChannel, 1, conn.ID)
+		var msg map[string]interface{}
+		require.NoError(t, json.Unmarshal(<-conn.SendChannel, &msg))
+		assert.Equal(t, "transaction", msg["type"])
+		assert.NotContains(t, msg, "stream_seq")
+	}
+	assert.Empty(t, reverse.SendChannel)
+
+	// The book_changes stream carries the ledger's aggregates.
+	require.Len(t, bookChanges.SendChannel, 1)
+	var msg map[string]interface{}
+	require.NoError(t, json.Unmarshal(<-bookChanges.SendChannel, &msg))
+	assert.Equal(t, "bookChanges", msg["type"])
+	assert.Equal(t, float64(7), msg["ledger_index"])
+	assert.Equal(t, float64(99), msg["ledger_time"])
+	assert.Equal(t, float64(1), msg["stream_seq"])
+	changes := msg["changes"].([]interface{})
+	require.Len(t, changes, 1)
+	change := changes[0].(map[string]interface{})
+	assert.Equal(t, "XRP_drops", change["currency_a"])
+	assert.Equal(t, "1000", change["volume_a"])
+	assert.Equal(t, "10", change["volume_b"])
+}
+
+// A domain book subscription receives the transactions that change the
+// domain's offers, published with the ledger, and not those of the open
+// book; the open book subscription the reverse.
+func TestPublishLedgerDomainBookStream(t *testing.T) {
+	sm := newTestSubscriptionManager()
+	const issuer = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	domain := strings.Repeat("AB", 32)
+	usdJSON := json.RawMessage(`{"currency":"USD","issuer":"` + issuer + `"}`)
+
+	open := newTestConnection("open")
+	domainConn := newTestConnection("domain")
+	sm.AddConnection(open)
+	sm.AddConnection(domainConn)
+	require.Nil(t, sm.HandleSubscribe(open, types.SubscriptionRequest{
+		Books: []types.BookRequest{{TakerGets: json.RawMessage(`{"currency":"XRP"}`), TakerPays: usdJSON}},
+	}))
+	require.Nil(t, sm.HandleSubscribe(domainConn, types.SubscriptionRequest{
+		Books: []types.BookRequest{{TakerGets: json.RawMessage(`{"currency":"XRP"}`), TakerPays: usdJSON, Domain: domain}},
+	}))
+
+	offerTx := func(hash, offerDomain string) LedgerTransaction {
+		fields := map[string]interface{}{
+			"TakerGets": "1000",
+			"TakerPays": map[string]interface{}{"currency": "USD", "issuer": issuer, "value": "10"},
+		}
+		if offerDomain != "" {
+			fields["DomainID"] = offerDomain
+		}
+		meta, _ := json.Marshal(map[string]interface{}{
+			"TransactionResult": "tesSUCCESS",
+			"AffectedNodes": []interface{}{map[string]interface{}{"CreatedNode": map[string]interface{}{
+				"LedgerEntryType": "Offer",
+				"NewFields":       fields,
+			}}},
+		})
+		return LedgerTransaction{Event: &TransactionEvent{
+			Type: "transaction", Hash: hash, Transaction: json.RawMessage(`{"TransactionType":"OfferCreate"}`), Meta: meta,
+		}}
+	}
+	NewPublisher(sm).PublishLedger(&LedgerCloseEvent{Type: "ledgerClosed", LedgerIndex: 8}, []LedgerTransaction{
+		offerTx("OPEN", ""),
+		offerTx("DOMAIN", strings.ToLower(domain)),
+	})
+
+	hashes := func(conn *types.Connection) []string {
+		var got []string
+		for len(conn.SendChannel) > 0 {
+			var event struct {
+				Hash string `json:"hash"`
+			}
+			require.NoError(t, json.Unmarshal(<-conn.SendChannel, &event))
+			got = append(got, event.Hash)
+		}
+		return got
+	}
+	assert.Equal(t, []string{"OPEN"}, hashes(open))
+	assert.Equal(t, []string{"DOMAIN"}, hashes(domainConn))
+}
+
+// mockBookSnapshotService returns one offer per book, naming the book's
+// TakerGets currency in its Account.
+type mockBookSnapshotService struct {
+	*mockLedgerService
+	ledgerIndex string
+}
+
+func (m *mockBookSnapshotService) GetBookOffers(takerGets, takerPays types.Amount, domain *[32]byte, ledgerIndex string, limit uint32) (*types.BookOffersResult, error) {
+	m.ledgerIndex = ledgerIndex
+	currency := takerGets.Currency
+	if currency == "" {
+		currency = "XRP"
+	}
+	return &types.BookOffersResult{Offers: []types.BookOffer{{Account: currency}}}, nil
+}
+
+func TestWebSocketBookSnapshot(t *testing.T) {
+	mock := &mockBookSnapshotService{mockLedgerService: newMockLedgerService()}
+	oldServices := types.Services
+	types.Services = &types.ServiceContainer{Ledger: mock}
+	defer func() { types.Services = oldServices }()
+
+	ws := NewWebSocketServer(time.Second)
+	client := dialTestWebSocket(t, ws, &PortContext{PortName: "ws"})
+	subscribe := func(book map[string]interface{}) map[string]interface{} {
+		book["taker_gets"] = map[string]interface{}{"currency": "XRP"}
+		book["taker_pays"] = map[string]interface{}{"currency": "USD", "issuer": "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"}
+		require.NoError(t, client.WriteJSON(map[string]interface{}{
+			"command": "subscribe", "books": []interface{}{book},
+		}))
+		msg := readTestMessage(t, client)
+		require.Equal(t, "success", msg["status"], msg)
+		return msg["result"].(map[string]interface{})
+	}
+
+	result := subscribe(map[string]interface{}{})
+	assert.Empty(t, result)
+
+	result = subscribe(map[string]interface{}{"snapshot": true})
+	require.Len(t, result["offers"], 1)
+	assert.Equal(t, "XRP", result["offers"].([]interface{})[0].(map[string]interface{})["Account"])
+	assert.Equal(t, "validated", mock.ledgerIndex)
+
+	result = subscribe(map[string]interface{}{"snapshot": true, "both": true})
+	assert.Nil(t, result["offers"])
+	require.Len(t, result["bids"], 1)
+	require.Len(t, result["asks"], 1)
+	assert.Equal(t, "XRP", result["bids"].([]interface{})[0].(map[string]interface{})["Account"])
+	assert.Equal(t, "USD", result["asks"].([]interface{})[0].(map[string]interface{})["Account"])
+
+	// A malformed domain is rejected rather than read as the open book.
+	rpcErr := bookSnapshots([]types.BookRequest{{
+		TakerGets: json.RawMessage(`{"currency":"XRP"}`),
+		TakerPays: json.RawMessage(`{"currency":"USD","issuer":"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"}`),
+		Domain:    "not-a-domain",
+		Snapshot:  true,
+	}}, map[string]interface{}{})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, types.RpcErrorInvalidParams("").Code, rpcErr.Code)
+}
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/LeJamon/goXRPLd/internal/rpc/handlers"
@@ -606,6 +607,54 @@ func TestSubscribeBooksMultiple(t *testing.T) {
 	sm.RemoveConnection(conn.ID)
 }
 
+// TestSubscribeBooksDomain tests permissioned domain order book streams:
+// a malformed domain is rejected, and a domain book and the open book of
+// the same pair receive only their own updates.
+func TestSubscribeBooksDomain(t *testing.T) {
+	const domainHex = "9F2D6A8C1B3E5F7092A4C6E8D0B2F4A6C8E0D2B4F6A8C0E2D4B6F8A0C2E4D6B8"
+	sm := newTestSubscriptionManager()
+
+	takerPays, _ := json.Marshal(map[string]interface{}{
+		"currency": "USD",
+		"issuer":   "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+	})
+	takerGets, _ := json.Marshal(map[string]interface{}{
+		"currency": "XRP",
+	})
+
+	bad := newTestConnection("bad")
+	sm.AddConnection(bad)
+	err := sm.HandleSubscribe(bad, types.SubscriptionRequest{
+		Books: []types.BookRequest{{TakerPays: takerPays, TakerGets: takerGets, Domain: "1234"}},
+	})
+	require.NotNil(t, err)
+	assert.Equal(t, types.RpcDOMAIN_MALFORMED, err.Code)
+
+	open := newTestConnection("open")
+	domain := newTestConnection("domain")
+	sm.AddConnection(open)
+	sm.AddConnection(domain)
+	require.Nil(t, sm.HandleSubscribe(open, types.SubscriptionRequest{
+		Books: []types.BookRequest{{TakerPays: takerPays, TakerGets: takerGets}},
+	}))
+	require.Nil(t, sm.HandleSubscribe(domain, types.SubscriptionRequest{
+		Books: []types.BookRequest{{TakerPays: takerPays, TakerGets: takerGets, Domain: domainHex}},
+	}))
+	assert.Equal(t, domainHex, domain.Subscriptions[types.SubOrderBooks].Domain)
+
+	gets := types.CurrencySpec{Currency: "XRP"}
+	pays := types.CurrencySpec{Currency: "USD", Issuer: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"}
+
+	sm.BroadcastToOrderBook([]byte("domain-update"), gets, pays, strings.ToLower(domainHex))
+	require.Len(t, domain.SendChannel, 1)
+	assert.Equal(t, "domain-update", string(<-domain.SendChannel))
+	assert.Empty(t, open.SendChannel, "open book subscribers do not see domain updates")
+
+	sm.BroadcastToOrderBook([]byte("open-update"), gets, pays, "")
+	require.Len(t, open.SendChannel, 1)
+	assert.Empty(t, domain.SendChannel, "domain subscribers do not see open book updates")
+}
+
 // Unsubscribe Tests
 // Based on rippled Subscribe_test.cpp unsubscribe sections
 
@@ -1288,7 +1337,7 @@ func TestBroadcastToAccounts(t *testing.T) {
 	// Only conn1 should receive
 	select {
 	case msg := <-conn1.SendChannel:
-		assert.Equal(t, testData, msg)
+		assert.JSONEq(t, `{"type":"transaction","account":"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh","stream_seq":1}`, string(msg))
 	default:
 		t.Error("conn1 should have received the message")
 	}
@@ -1304,6 +1353,66 @@ func TestBroadcastToAccounts(t *testing.T) {
 	sm.RemoveConnection("conn-2")
 }
 
+// TestBroadcastTxToAccountsFilter tests the tx_type and direction filters
+// of account subscriptions
+func TestBroadcastTxToAccountsFilter(t *testing.T) {
+	sm := newTestSubscriptionManager()
+	alice := "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	bob := "rPMh7Pi9ct699iZUTWaytJUoHcJ7cgyziK"
+
+	all := newTestConnection("all")
+	payments := newTestConnection("payments")
+	outgoing := newTestConnection("outgoing")
+	incoming := newTestConnection("incoming")
+	for _, conn := range []*types.Connection{all, payments, outgoing, incoming} {
+		sm.AddConnection(conn)
+	}
+	require.Nil(t, sm.HandleSubscribe(all, types.SubscriptionRequest{Accounts: []string{alice}}))
+	require.Nil(t, sm.HandleSubscribe(payments, types.SubscriptionRequest{Accounts: []string{alice}, TxType: "Payment"}))
+	require.Nil(t, sm.HandleSubscribe(outgoing, types.SubscriptionRequest{Accounts: []string{alice}, Direction: "outgoing"}))
+	require.Nil(t, sm.HandleSubscribe(incoming, types.SubscriptionRequest{Accounts: []string{alice}, Direction: "incoming"}))
+
+	received := func(conn *types.Connection) bool {
+		select {
+		case <-conn.SendChannel:
+			return true
+		default:
+			return false
+		}
+	}
+
+	// A payment from bob to alice
+	sm.BroadcastTxToAccounts([]byte(`{"n":1}`), []string{alice, bob}, "Payment", bob, alice)
+	assert.True(t, received(all))
+	assert.True(t, received(payments))
+	assert.False(t, received(outgoing))
+	assert.True(t, received(incoming))
+
+	// An offer by alice
+	sm.BroadcastTxToAccounts([]byte(`{"n":2}`), []string{alice}, "OfferCreate", alice, "")
+	assert.True(t, received(all))
+	assert.False(t, received(payments))
+	assert.True(t, received(outgoing))
+	assert.False(t, received(incoming))
+
+	// An offer by bob that crossed alice's: it affected her but was not
+	// sent to her, as in account_tx
+	sm.BroadcastTxToAccounts([]byte(`{"n":3}`), []string{alice, bob}, "OfferCreate", bob, "")
+	assert.True(t, received(all))
+	assert.False(t, received(payments))
+	assert.False(t, received(outgoing))
+	assert.False(t, received(incoming))
+
+	// Invalid filters are rejected
+	conn := newTestConnection("invalid")
+	rpcErr := sm.HandleSubscribe(conn, types.SubscriptionRequest{Accounts: []string{alice}, TxType: "Bogus"})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, types.RpcINVALID_PARAMS, rpcErr.Code)
+	rpcErr = sm.HandleSubscribe(conn, types.SubscriptionRequest{Accounts: []string{alice}, Direction: "both"})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, types.RpcINVALID_PARAMS, rpcErr.Code)
+}
+
 // TestBookMatchesCurrency tests the order book matching logic
 func TestBookMatchesCurrency(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,275 @@
+package rpc
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LeJamon/goXRPLd/internal/rpc/handlers"
+	"github.com/LeJamon/goXRPLd/internal/rpc/subscription"
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// URL subscription tests. Events are received by a local httptest server.
+// Based on rippled RPCSub.cpp and Subscribe_test.cpp testSubByUrl()
+
+// urlReceiver records the events posted to it. Its first failures
+// requests are answered with 500.
+type urlReceiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	failures int
+	events   []map[string]interface{}
+	auth     []string
+	received chan struct{}
+}
+
+func newURLReceiver(t *testing.T, failures int) *urlReceiver {
+	r := &urlReceiver{failures: failures, received: make(chan struct{}, 100)}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.failures > 0 {
+			r.failures--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		body, _ := io.ReadAll(req.Body)
+		var call struct {
+			Method string                   `json:"method"`
+			Params []map[string]interface{} `json:"params"`
+		}
+		if err := json.Unmarshal(body, &call); err != nil || call.Method != "event" || len(call.Params) != 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		user, pass, _ := req.BasicAuth()
+		r.events = append(r.events, call.Params[0])
+		r.auth = append(r.auth, user+":"+pass)
+		r.received <- struct{}{}
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+// wait blocks until n events have been received.
+func (r *urlReceiver) wait(t *testing.T, n int) []map[string]interface{} {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for event %d", i+1)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]map[string]interface{}(nil), r.events...)
+}
+
+// expectNone fails if an event arrives within a short grace period.
+func (r *urlReceiver) expectNone(t *testing.T) {
+	t.Helper()
+	select {
+	case <-r.received:
+		t.Error("unexpected event delivered")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func newURLTestManager(t *testing.T) *subscription.Manager {
+	sm := newTestSubscriptionManager()
+	sm.SetURLOptions(subscription.URLOptions{
+		QueueLimit:   32,
+		Timeout:      time.Second,
+		MaxRetries:   3,
+		RetryBackoff: 10 * time.Millisecond,
+	})
+	t.Cleanup(sm.Close)
+	return sm
+}
+
+func TestURLSubscriptionDeliversStreams(t *testing.T) {
+	sm := newURLTestManager(t)
+	receiver := newURLReceiver(t, 0)
+
+	err := sm.SubscribeURL(types.SubscriptionRequest{
+		Streams:     []types.SubscriptionType{types.SubLedger},
+		URL:         receiver.URL,
+		URLUsername: "admin",
+		URLPassword: "secret",
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 1, sm.GetSubscriberCount(types.SubLedger))
+
+	sm.BroadcastToStream(types.SubTransactions, []byte(`{"type":"transaction"}`), nil)
+	sm.BroadcastToStream(types.SubLedger, []byte(`{"type":"ledgerClosed","ledger_index":5}`), nil)
+	sm.BroadcastToStream(types.SubLedger, []byte(`{"type":"ledgerClosed","ledger_index":6}`), nil)
+
+	events := receiver.wait(t, 2)
+	require.Len(t, events, 2)
+	assert.Equal(t, "ledgerClosed", events[0]["type"])
+	assert.Equal(t, float64(5), events[0]["ledger_index"])
+	assert.Equal(t, float64(0), events[0]["seq"])
+	assert.Equal(t, float64(6), events[1]["ledger_index"])
+	assert.Equal(t, float64(1), events[1]["seq"])
+	assert.Equal(t, "admin:secret", receiver.auth[0])
+	receiver.expectNone(t)
+}
+
+func TestURLSubscriptionFiltersAccounts(t *testing.T) {
+	sm := newURLTestManager(t)
+	receiver := newURLReceiver(t, 0)
+
+	const account = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	const other = "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe"
+	require.Nil(t, sm.SubscribeURL(types.SubscriptionRequest{
+		Accounts: []string{account},
+		URL:      receiver.URL,
+	}))
+
+	sm.BroadcastToAccounts([]byte(`{"type":"transaction","n":1}`), []string{other})
+	sm.BroadcastToAccounts([]byte(`{"type":"transaction","n":2}`), []string{other, account})
+
+	events := receiver.wait(t, 1)
+	assert.Equal(t, float64(2), events[0]["n"])
+	receiver.expectNone(t)
+}
+
+func TestURLSubscriptionRetriesInOrder(t *testing.T) {
+	sm := newURLTestManager(t)
+	receiver := newURLReceiver(t, 2)
+
+	require.Nil(t, sm.SubscribeURL(types.SubscriptionRequest{
+		Streams: []types.SubscriptionType{types.SubLedger},
+		URL:     receiver.URL,
+	}))
+	for i := 1; i <= 3; i++ {
+		data, _ := json.Marshal(map[string]interface{}{"type": "ledgerClosed", "ledger_index": i})
+		sm.BroadcastToStream(types.SubLedger, data, nil)
+	}
+
+	// The first event is accepted on its third attempt; the others
+	// wait behind it.
+	events := receiver.wait(t, 3)
+	for i, event := range events {
+		assert.Equal(t, float64(i+1), event["ledger_index"])
+		assert.Equal(t, float64(i), event["seq"])
+	}
+}
+
+func TestURLSubscriptionDropsAfterRetries(t *testing.T) {
+	sm := newURLTestManager(t)
+	receiver := newURLReceiver(t, 4)
+
+	require.Nil(t, sm.SubscribeURL(types.SubscriptionRequest{
+		Streams: []types.SubscriptionType{types.SubLedger},
+		URL:     receiver.URL,
+	}))
+	sm.BroadcastToStream(types.SubLedger, []byte(`{"type":"ledgerClosed","ledger_index":1}`), nil)
+	sm.BroadcastToStream(types.SubLedger, []byte(`{"type":"ledgerClosed","ledger_index":2}`), nil)
+
+	// Four failures exhaust the first event's attempts; the second gets
+	// through with the next sequence number, leaving a visible gap.
+	events := receiver.wait(t, 1)
+	assert.Equal(t, float64(2), events[0]["ledger_index"])
+	assert.Equal(t, float64(1), events[0]["seq"])
+}
+
+func TestURLSubscriptionUnsubscribe(t *testing.T) {
+	sm := newURLTestManager(t)
+	receiver := newURLReceiver(t, 0)
+
+	require.Nil(t, sm.SubscribeURL(types.SubscriptionRequest{
+		Streams: []types.SubscriptionType{types.SubLedger},
+		URL:     receiver.URL,
+	}))
+	require.NotNil(t, sm.URLSubscriber(receiver.URL))
+
+	require.Nil(t, sm.UnsubscribeURL(types.SubscriptionRequest{URL: receiver.URL}))
+	assert.Nil(t, sm.URLSubscriber(receiver.URL))
+	assert.Equal(t, 0, sm.GetSubscriberCount(types.SubLedger))
+	assert.Equal(t, 0, sm.ConnectionCount())
+
+	sm.BroadcastToStream(types.SubLedger, []byte(`{"type":"ledgerClosed"}`), nil)
+	receiver.expectNone(t)
+
+	// Unknown URLs are ignored.
+	assert.Nil(t, sm.UnsubscribeURL(types.SubscriptionRequest{URL: receiver.URL}))
+}
+
+func TestURLSubscriptionResubscribeMerges(t *testing.T) {
+	sm := newURLTestManager(t)
+	receiver := newURLReceiver(t, 0)
+
+	require.Nil(t, sm.SubscribeURL(types.SubscriptionRequest{
+		Streams: []types.SubscriptionType{types.SubLedger},
+		URL:     receiver.URL,
+	}))
+	require.Nil(t, sm.SubscribeURL(types.SubscriptionRequest{
+		Streams: []types.SubscriptionType{types.SubServer},
+		URL:     receiver.URL,
+	}))
+	assert.Equal(t, 1, sm.ConnectionCount(), "one subscriber per URL")
+
+	sm.BroadcastToStream(types.SubLedger, []byte(`{"type":"ledgerClosed"}`), nil)
+	sm.BroadcastToStream(types.SubServer, []byte(`{"type":"serverStatus"}`), nil)
+	events := receiver.wait(t, 2)
+	assert.Equal(t, "ledgerClosed", events[0]["type"])
+	assert.Equal(t, "serverStatus", events[1]["type"])
+}
+
+func TestURLSubscriptionInvalidURL(t *testing.T) {
+	sm := newURLTestManager(t)
+
+	for _, url := range []string{"not a url", "ftp://example.com/events", "http://"} {
+		err := sm.SubscribeURL(types.SubscriptionRequest{URL: url})
+		require.NotNil(t, err, url)
+		assert.Equal(t, types.RpcINVALID_PARAMS, err.Code)
+	}
+	assert.Equal(t, 0, sm.ConnectionCount())
+}
+
+func TestSubscribeMethodURL(t *testing.T) {
+	sm := newURLTestManager(t)
+	receiver := newURLReceiver(t, 0)
+
+	oldServices := types.Services
+	types.Services = &types.ServiceContainer{URLSubscriptions: sm}
+	t.Cleanup(func() { types.Services = oldServices })
+
+	params, _ := json.Marshal(types.SubscriptionRequest{
+		Streams: []types.SubscriptionType{types.SubLedger},
+		URL:     receiver.URL,
+	})
+
+	t.Run("requires admin", func(t *testing.T) {
+		ctx := &types.RpcContext{Role: types.RoleGuest, ApiVersion: types.ApiVersion1}
+		_, err := (&handlers.SubscribeMethod{}).Handle(ctx, params)
+		require.NotNil(t, err)
+		assert.Equal(t, types.RpcNO_PERMISSION, err.Code)
+		assert.Nil(t, sm.URLSubscriber(receiver.URL))
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		ctx := &types.RpcContext{Role: types.RoleAdmin, IsAdmin: true, ApiVersion: types.ApiVersion1}
+		result, err := (&handlers.SubscribeMethod{}).Handle(ctx, params)
+		require.Nil(t, err)
+		assert.NotNil(t, result)
+
+		sm.BroadcastToStream(types.SubLedger, []byte(`{"type":"ledgerClosed"}`), nil)
+		receiver.wait(t, 1)
+
+		_, err = (&handlers.UnsubscribeMethod{}).Handle(ctx, params)
+		require.Nil(t, err)
+		assert.Nil(t, sm.URLSubscriber(receiver.URL))
+	})
+}
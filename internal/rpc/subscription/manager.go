@@ -14,19 +14,39 @@ var validStreams = map[types.SubscriptionType]bool{
 	types.SubTransactions:         true,
 	types.SubTransactionsProposed: true,
 	types.SubAccounts:             true,
-	types.SubOrderBooks:           true,
+	types.SubBookChanges:          true,
 	types.SubValidations:          true,
 	types.SubManifests:            true,
 	types.SubPeerStatus:           true,
 	types.SubServer:               true,
 	types.SubConsensus:            true,
 	types.SubPath:                 true,
+	types.SubSubmissions:          true,
 }
 
-// Manager manages WebSocket subscriptions
+// Manager manages WebSocket subscriptions and the server-to-server
+// subscriptions that deliver events by HTTP POST.
 type Manager struct {
 	Connections map[string]*types.Connection
 	mu          sync.RWMutex
+
+	// urlSubs holds the URL subscribers by URL. Each one's connection
+	// is also in Connections.
+	urlSubs    map[string]*URLSubscriber
+	urlOptions *URLOptions
+
+	// submitters holds, by transaction hash, the IDs of the connections
+	// that submitted it, until its state is final.
+	submitters map[string]map[string]bool
+
+	// pubMu orders publication; it is taken before mu, never after.
+	pubMu sync.Mutex
+	pub   publication
+
+	// OnBroadcast, if set, is called with each message queued unchanged
+	// on every subscribed connection, before it is queued, so that the
+	// transport can share work between the connections.
+	OnBroadcast func(data []byte)
 }
 
 // NewManager creates a new Manager
@@ -48,16 +68,73 @@ func (sm *Manager) AddConnection(conn *types.Connection) {
 
 // RemoveConnection removes a connection from the subscription manager
 func (sm *Manager) RemoveConnection(connID string) {
+	sm.forgetConnection(connID)
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 	delete(sm.Connections, connID)
+	for hash, conns := range sm.submitters {
+		delete(conns, connID)
+		if len(conns) == 0 {
+			delete(sm.submitters, hash)
+		}
+	}
+}
+
+// TrackSubmission records that a connection submitted the transaction
+// with the given hash, so that it receives the transaction's updates on
+// the submissions stream.
+func (sm *Manager) TrackSubmission(connID, hash string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.submitters == nil {
+		sm.submitters = make(map[string]map[string]bool)
+	}
+	if sm.submitters[hash] == nil {
+		sm.submitters[hash] = make(map[string]bool)
+	}
+	sm.submitters[hash][connID] = true
+}
+
+// BroadcastToSubmitters sends a message about a transaction to the
+// connections that submitted it and subscribe to the submissions stream,
+// numbered per connection. After a final message the connections are no
+// longer tracked. It may only be called while publishing.
+func (sm *Manager) BroadcastToSubmitters(data []byte, hash string, final bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	for connID := range sm.submitters[hash] {
+		conn, ok := sm.Connections[connID]
+		if !ok {
+			continue
+		}
+		if _, ok := conn.Subscriptions[types.SubSubmissions]; ok {
+			sm.sendNumbered(conn, types.SubSubmissions, data)
+		}
+	}
+	if final {
+		delete(sm.submitters, hash)
+	}
 }
 
-// HandleSubscribe handles a subscribe request for a connection
+// HandleSubscribe handles a subscribe request for a connection. A request
+// carrying "url" subscribes that URL instead of the connection, which may
+// then be nil; callers check the admin role first.
 func (sm *Manager) HandleSubscribe(conn *types.Connection, request types.SubscriptionRequest) *types.RpcError {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	if request.URL != "" {
+		sub, err := sm.urlSubscriberLocked(request)
+		if err != nil {
+			return err
+		}
+		if conn != nil {
+			conn.URLSubscription = request.URL
+		}
+		conn = sub.conn
+	}
+
 	// Validate and add stream subscriptions
 	for _, stream := range request.Streams {
 		if !validStreams[stream] {
@@ -80,6 +157,13 @@ func (sm *Manager) HandleSubscribe(conn *types.Connection, request types.Subscri
 			}
 		}
 
+		// The filter applies to all of the connection's accounts; the
+		// latest subscribe sets it.
+		filter, err := types.ParseAccountTxFilter(request.TxType, request.Direction)
+		if err != nil {
+			return err
+		}
+
 		// Merge with existing accounts if already subscribed
 		existing, ok := conn.Subscriptions[types.SubAccounts]
 		accounts := request.Accounts
@@ -97,6 +181,7 @@ func (sm *Manager) HandleSubscribe(conn *types.Connection, request types.Subscri
 		}
 		conn.Subscriptions[types.SubAccounts] = types.SubscriptionConfig{
 			Accounts: accounts,
+			Filter:   filter,
 		}
 	}
 
@@ -176,21 +261,25 @@ func (sm *Manager) HandleSubscribe(conn *types.Connection, request types.Subscri
 				}
 			}
 
+			// Optional permissioned domain: subscribe to that domain's
+			// book rather than the open one. Reference: rippled Subscribe.cpp
+			if book.Domain != "" {
+				if _, ok := types.ParseDomainHex(book.Domain); !ok {
+					return types.RpcErrorDomainMalformed("Unable to parse domain.")
+				}
+			}
+
 			conn.Subscriptions[types.SubOrderBooks] = types.SubscriptionConfig{
 				Books:     request.Books,
 				TakerGets: &takerGets,
 				TakerPays: &takerPays,
 				Snapshot:  book.Snapshot,
 				Both:      book.Both,
+				Domain:    book.Domain,
 			}
 		}
 	}
 
-	// Handle URL subscriptions
-	if request.URL != "" {
-		conn.URLSubscription = request.URL
-	}
-
 	return nil
 }
 
@@ -199,11 +288,23 @@ func isValidXRPLAddress(addr string) bool {
 	return addresscodec.IsValidClassicAddress(addr)
 }
 
-// HandleUnsubscribe handles an unsubscribe request for a connection
+// HandleUnsubscribe handles an unsubscribe request for a connection. A
+// request carrying "url" removes that URL's subscription entirely, as
+// rippled does; unknown URLs are ignored.
 func (sm *Manager) HandleUnsubscribe(conn *types.Connection, request types.SubscriptionRequest) *types.RpcError {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	if request.URL != "" {
+		if conn != nil && conn.URLSubscription == request.URL {
+			conn.URLSubscription = ""
+		}
+		if sub := sm.urlSubs[request.URL]; sub != nil {
+			sm.removeURLSubscriberLocked(sub)
+		}
+		return nil
+	}
+
 	for _, stream := range request.Streams {
 		delete(conn.Subscriptions, stream)
 	}
@@ -223,6 +324,7 @@ func (sm *Manager) HandleUnsubscribe(conn *types.Connection, request types.Subsc
 			if len(remainingAccounts) > 0 {
 				conn.Subscriptions[types.SubAccounts] = types.SubscriptionConfig{
 					Accounts: remainingAccounts,
+					Filter:   existing.Filter,
 				}
 			} else {
 				delete(conn.Subscriptions, types.SubAccounts)
@@ -257,11 +359,6 @@ func (sm *Manager) HandleUnsubscribe(conn *types.Connection, request types.Subsc
 		delete(conn.Subscriptions, types.SubOrderBooks)
 	}
 
-	// Handle URL unsubscription
-	if request.URL != "" {
-		conn.URLSubscription = ""
-	}
-
 	return nil
 }
 
@@ -269,20 +366,33 @@ func (sm *Manager) HandleUnsubscribe(conn *types.Connection, request types.Subsc
 func (sm *Manager) BroadcastToStream(streamType types.SubscriptionType, data []byte, _ interface{}) {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
+	sm.broadcast(data)
 
 	for _, conn := range sm.Connections {
 		if _, ok := conn.Subscriptions[streamType]; ok {
-			select {
-			case conn.SendChannel <- data:
-			default:
-				// Channel full, skip
-			}
+			send(conn, data)
 		}
 	}
 }
 
-// BroadcastToAccounts sends a message to all connections subscribed to any of the accounts
+// BroadcastToAccounts sends a message to all connections subscribed to
+// any of the accounts, numbered per connection. It may only be called
+// while publishing, as may BroadcastTxToAccounts and
+// BroadcastToAccountsProposed.
 func (sm *Manager) BroadcastToAccounts(data []byte, accounts []string) {
+	sm.broadcastToAccounts(data, accounts, nil)
+}
+
+// BroadcastTxToAccounts sends a transaction of type txType sent by sender
+// to destination to the connections subscribed to any of the accounts
+// whose filter it passes for one of them.
+func (sm *Manager) BroadcastTxToAccounts(data []byte, accounts []string, txType, sender, destination string) {
+	sm.broadcastToAccounts(data, accounts, func(filter types.AccountTxFilter, account string) bool {
+		return filter.Matches(account, txType, sender, destination)
+	})
+}
+
+func (sm *Manager) broadcastToAccounts(data []byte, accounts []string, matches func(types.AccountTxFilter, string) bool) {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
 
@@ -294,12 +404,8 @@ func (sm *Manager) BroadcastToAccounts(data []byte, accounts []string) {
 	for _, conn := range sm.Connections {
 		if config, ok := conn.Subscriptions[types.SubAccounts]; ok {
 			for _, subAcc := range config.Accounts {
-				if accountSet[subAcc] {
-					select {
-					case conn.SendChannel <- data:
-					default:
-						// Channel full, skip
-					}
+				if accountSet[subAcc] && (matches == nil || matches(config.Filter, subAcc)) {
+					sm.sendNumbered(conn, types.SubAccounts, data)
 					break
 				}
 			}
@@ -307,7 +413,8 @@ func (sm *Manager) BroadcastToAccounts(data []byte, accounts []string) {
 	}
 }
 
-// BroadcastToAccountsProposed sends a message to accounts_proposed subscribers
+// BroadcastToAccountsProposed sends a message to accounts_proposed
+// subscribers, numbered per connection
 func (sm *Manager) BroadcastToAccountsProposed(data []byte, accounts []string) {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
@@ -320,10 +427,7 @@ func (sm *Manager) BroadcastToAccountsProposed(data []byte, accounts []string) {
 		if config, ok := conn.Subscriptions["accounts_proposed"]; ok {
 			for _, subAcc := range config.Accounts {
 				if accountSet[subAcc] {
-					select {
-					case conn.SendChannel <- data:
-					default:
-					}
+					sm.sendNumbered(conn, "accounts_proposed", data)
 					break
 				}
 			}
@@ -331,27 +435,64 @@ func (sm *Manager) BroadcastToAccountsProposed(data []byte, accounts []string) {
 	}
 }
 
-// BroadcastToOrderBook sends a message to order book subscribers
-func (sm *Manager) BroadcastToOrderBook(data []byte, takerGets, takerPays types.CurrencySpec) {
+// BroadcastToOrderBook sends a message to subscribers of the given order
+// book. domain is the book's permissioned domain in hex, or empty for the
+// open DEX; a domain book and the open book of the same pair are distinct
+// streams.
+func (sm *Manager) BroadcastToOrderBook(data []byte, takerGets, takerPays types.CurrencySpec, domain string) {
+	sm.BroadcastToOrderBooks(data, []types.OrderBook{{TakerGets: takerGets, TakerPays: takerPays, Domain: domain}})
+}
+
+// BroadcastToOrderBooks sends a message once to each connection subscribed
+// to any of the books. A subscription with "both" also receives the
+// messages of the reverse book.
+func (sm *Manager) BroadcastToOrderBooks(data []byte, books []types.OrderBook) {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
+	sm.broadcast(data)
 
 	for _, conn := range sm.Connections {
-		if config, ok := conn.Subscriptions[types.SubOrderBooks]; ok {
-			if config.TakerGets != nil && config.TakerPays != nil {
-				if config.TakerGets.Currency == takerGets.Currency &&
-					config.TakerGets.Issuer == takerGets.Issuer &&
-					config.TakerPays.Currency == takerPays.Currency &&
-					config.TakerPays.Issuer == takerPays.Issuer {
-					select {
-					case conn.SendChannel <- data:
-					default:
-						// Channel full, skip
-					}
-				}
+		config, ok := conn.Subscriptions[types.SubOrderBooks]
+		if !ok {
+			continue
+		}
+		if subscribedToAny(config.Books, books) {
+			send(conn, data)
+		}
+	}
+}
+
+func subscribedToAny(subscribed []types.BookRequest, books []types.OrderBook) bool {
+	for _, sub := range subscribed {
+		for _, book := range books {
+			if types.BookMatches(sub, book.TakerGets, book.TakerPays, book.Domain) ||
+				sub.Both && types.BookMatches(sub, book.TakerPays, book.TakerGets, book.Domain) {
+				return true
 			}
 		}
 	}
+	return false
+}
+
+// broadcast reports data, about to be queued on several connections, to
+// the OnBroadcast hook.
+func (sm *Manager) broadcast(data []byte) {
+	if sm.OnBroadcast != nil {
+		sm.OnBroadcast(data)
+	}
+}
+
+// send queues data for conn. A full queue means the subscriber is not
+// keeping up: the message is dropped and the connection's SlowConsumer
+// callback, if any, disconnects it so the client learns of the gap.
+func send(conn *types.Connection, data []byte) {
+	select {
+	case conn.SendChannel <- data:
+	default:
+		if conn.SlowConsumer != nil {
+			conn.SlowConsumer()
+		}
+	}
 }
 
 // GetSubscriberCount returns the number of subscribers for a stream type
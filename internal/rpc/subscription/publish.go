@@ -0,0 +1,106 @@
+package subscription
+
+import (
+	"strconv"
+
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+)
+
+// Publication state. Events are published in units (a validated ledger
+// with its transactions, or a single event) under pubMu, which gives
+// every stream a total order: the seq numbers stamped on a stream's
+// events increase by one per event in the order they are queued, so a
+// client can tell from a jump that it missed something. Subscribing with
+// a resume point hands over from replayed to live events under pubMu too,
+// so no ledger is replayed twice or skipped.
+//
+// The streams filtered per connection (accounts, accounts_proposed and
+// submissions) are numbered per connection instead, counting only the
+// messages sent to it, so that a subscriber sees no gap for messages
+// meant for others.
+type publication struct {
+	seq     map[types.SubscriptionType]uint64
+	connSeq map[string]map[types.SubscriptionType]uint64
+	ledger  uint32
+}
+
+// Publish runs fn, which publishes one unit of events, exclusively of
+// other units. NextSeq and SetPublishedLedger may only be called from fn.
+func (sm *Manager) Publish(fn func()) {
+	sm.pubMu.Lock()
+	defer sm.pubMu.Unlock()
+	fn()
+}
+
+// NextSeq returns the next sequence number of a stream, starting at 1.
+func (sm *Manager) NextSeq(stream types.SubscriptionType) uint64 {
+	if sm.pub.seq == nil {
+		sm.pub.seq = make(map[types.SubscriptionType]uint64)
+	}
+	sm.pub.seq[stream]++
+	return sm.pub.seq[stream]
+}
+
+// sendNumbered sends a JSON object to conn with the connection's next
+// stream_seq for stream added. It may only be called while publishing.
+func (sm *Manager) sendNumbered(conn *types.Connection, stream types.SubscriptionType, data []byte) {
+	if sm.pub.connSeq == nil {
+		sm.pub.connSeq = make(map[string]map[types.SubscriptionType]uint64)
+	}
+	seqs := sm.pub.connSeq[conn.ID]
+	if seqs == nil {
+		seqs = make(map[types.SubscriptionType]uint64)
+		sm.pub.connSeq[conn.ID] = seqs
+	}
+	seqs[stream]++
+	send(conn, withStreamSeq(data, seqs[stream]))
+}
+
+// forgetConnection drops the stream numbers of a closed connection.
+func (sm *Manager) forgetConnection(connID string) {
+	sm.pubMu.Lock()
+	defer sm.pubMu.Unlock()
+	delete(sm.pub.connSeq, connID)
+}
+
+// withStreamSeq returns a copy of a JSON object with a stream_seq member
+// added. Data that is not an object is returned unchanged.
+func withStreamSeq(data []byte, seq uint64) []byte {
+	if len(data) < 2 || data[0] != '{' || data[len(data)-1] != '}' {
+		return data
+	}
+	out := make([]byte, 0, len(data)+32)
+	out = append(out, data[:len(data)-1]...)
+	if len(data) > 2 {
+		out = append(out, ',')
+	}
+	out = append(out, `"stream_seq":`...)
+	out = strconv.AppendUint(out, seq, 10)
+	return append(out, '}')
+}
+
+// SetPublishedLedger records that the transactions of ledger seq have
+// all been published.
+func (sm *Manager) SetPublishedLedger(seq uint32) {
+	sm.pub.ledger = seq
+}
+
+// PublishedLedger returns the last ledger whose transactions have all
+// been published, or 0 if none has been.
+func (sm *Manager) PublishedLedger() uint32 {
+	sm.pubMu.Lock()
+	defer sm.pubMu.Unlock()
+	return sm.pub.ledger
+}
+
+// SubscribeAfter subscribes conn as HandleSubscribe does, but only if no
+// ledger after ledger has been published yet; otherwise it returns false
+// and the caller replays the newer ledgers before trying again.
+func (sm *Manager) SubscribeAfter(ledger uint32, conn *types.Connection, request types.SubscriptionRequest) (bool, *types.RpcError) {
+	sm.pubMu.Lock()
+	defer sm.pubMu.Unlock()
+	if sm.pub.ledger > ledger {
+		return false, nil
+	}
+	return true, sm.HandleSubscribe(conn, request)
+}
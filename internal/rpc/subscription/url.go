@@ -0,0 +1,281 @@
+package subscription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+	xrpllog "github.com/LeJamon/goXRPLd/log"
+)
+
+// urlLog returns the logger for URL subscription delivery.
+func urlLog() xrpllog.Logger { return xrpllog.Named(xrpllog.PartitionRPC) }
+
+// URLOptions tunes delivery to URL subscribers.
+type URLOptions struct {
+	// QueueLimit bounds the events waiting for delivery per URL. Events
+	// published while the queue is full are dropped.
+	QueueLimit int
+	// Timeout bounds a single POST.
+	Timeout time.Duration
+	// MaxRetries is how often a failed POST is retried before the event
+	// is dropped. Later events wait, so delivery stays in order.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry; it doubles
+	// with every further retry.
+	RetryBackoff time.Duration
+}
+
+// DefaultURLOptions matches rippled's RPCSub queue limit of 32 events.
+var DefaultURLOptions = URLOptions{
+	QueueLimit:   32,
+	Timeout:      10 * time.Second,
+	MaxRetries:   3,
+	RetryBackoff: 500 * time.Millisecond,
+}
+
+// URLSubscriber delivers the events of a server-to-server subscription by
+// HTTP POST, one JSON-RPC "event" call per event. It is the counterpart
+// of rippled's RPCSub: its stream and account subscriptions live in a
+// Connection like a WebSocket client's, so the broadcast filters apply to
+// it unchanged, and a sender goroutine drains the connection's queue.
+type URLSubscriber struct {
+	url    string
+	conn   *types.Connection
+	opts   URLOptions
+	client *http.Client
+
+	mu       sync.Mutex
+	username string
+	password string
+	seq      uint64
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stopped chan struct{}
+}
+
+func newURLSubscriber(rawURL string, opts URLOptions) (*URLSubscriber, *types.RpcError) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, types.RpcErrorInvalidParams("Failed to parse url.")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &URLSubscriber{
+		url: rawURL,
+		conn: &types.Connection{
+			ID:            "url:" + rawURL,
+			Subscriptions: make(map[types.SubscriptionType]types.SubscriptionConfig),
+			SendChannel:   make(chan []byte, opts.QueueLimit),
+			CloseChannel:  make(chan struct{}),
+		},
+		opts:    opts,
+		client:  &http.Client{Timeout: opts.Timeout},
+		ctx:     ctx,
+		cancel:  cancel,
+		stopped: make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// URL returns the address events are posted to.
+func (s *URLSubscriber) URL() string {
+	return s.url
+}
+
+// setCredentials updates the basic auth credentials. Empty values keep
+// the current ones, as rippled does on a repeated subscribe.
+func (s *URLSubscriber) setCredentials(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if username != "" {
+		s.username = username
+	}
+	if password != "" {
+		s.password = password
+	}
+}
+
+// stop cancels any delivery in flight and discards queued events.
+func (s *URLSubscriber) stop() {
+	s.cancel()
+	close(s.conn.CloseChannel)
+}
+
+func (s *URLSubscriber) run() {
+	defer close(s.stopped)
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case data := <-s.conn.SendChannel:
+			s.deliver(data)
+		}
+	}
+}
+
+// deliver posts one event, retrying with backoff until it is accepted,
+// the retries are exhausted or the subscriber is stopped.
+func (s *URLSubscriber) deliver(data []byte) {
+	body, err := s.eventBody(data)
+	if err != nil {
+		urlLog().Warn("URL subscription dropped malformed event", "url", s.url, "err", err)
+		return
+	}
+
+	backoff := s.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := s.post(body)
+		if err == nil {
+			return
+		}
+		if s.ctx.Err() != nil {
+			return
+		}
+		if attempt >= s.opts.MaxRetries {
+			urlLog().Warn("URL subscription dropped event", "url", s.url, "attempts", attempt+1, "err", err)
+			return
+		}
+		urlLog().Debug("URL subscription delivery failed, retrying", "url", s.url, "backoff", backoff, "err", err)
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// eventBody wraps an event in a JSON-RPC "event" call, numbering it so
+// the receiver can detect gaps left by dropped events.
+func (s *URLSubscriber) eventBody(data []byte) ([]byte, error) {
+	var event map[string]json.RawMessage
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	seq := s.seq
+	s.seq++
+	s.mu.Unlock()
+	event["seq"] = json.RawMessage(fmt.Sprint(seq))
+
+	return json.Marshal(map[string]interface{}{
+		"method": "event",
+		"params": []interface{}{event},
+	})
+}
+
+func (s *URLSubscriber) post(body []byte) error {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	s.mu.Lock()
+	if s.username != "" || s.password != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+	s.mu.Unlock()
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// SetURLOptions sets the delivery options of URL subscribers created
+// after the call.
+func (sm *Manager) SetURLOptions(opts URLOptions) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.urlOptions = &opts
+}
+
+// SubscribeURL handles a subscribe request carrying "url". It is the
+// entry point for subscribe over HTTP, where there is no connection.
+func (sm *Manager) SubscribeURL(request types.SubscriptionRequest) *types.RpcError {
+	if request.URL == "" {
+		return types.RpcErrorInvalidParams("Missing field 'url'.")
+	}
+	return sm.HandleSubscribe(nil, request)
+}
+
+// UnsubscribeURL handles an unsubscribe request carrying "url".
+func (sm *Manager) UnsubscribeURL(request types.SubscriptionRequest) *types.RpcError {
+	if request.URL == "" {
+		return types.RpcErrorInvalidParams("Missing field 'url'.")
+	}
+	return sm.HandleUnsubscribe(nil, request)
+}
+
+// URLSubscriber returns the subscriber for a URL, or nil.
+func (sm *Manager) URLSubscriber(rawURL string) *URLSubscriber {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.urlSubs[rawURL]
+}
+
+// Close stops every URL subscriber.
+func (sm *Manager) Close() {
+	sm.mu.Lock()
+	subs := sm.urlSubs
+	sm.urlSubs = nil
+	for _, sub := range subs {
+		delete(sm.Connections, sub.conn.ID)
+	}
+	sm.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.stop()
+		<-sub.stopped
+	}
+}
+
+// urlSubscriberLocked returns the subscriber for the request's URL,
+// creating and registering it on first use.
+func (sm *Manager) urlSubscriberLocked(request types.SubscriptionRequest) (*URLSubscriber, *types.RpcError) {
+	sub := sm.urlSubs[request.URL]
+	if sub == nil {
+		opts := DefaultURLOptions
+		if sm.urlOptions != nil {
+			opts = *sm.urlOptions
+		}
+		var err *types.RpcError
+		sub, err = newURLSubscriber(request.URL, opts)
+		if err != nil {
+			return nil, err
+		}
+		if sm.urlSubs == nil {
+			sm.urlSubs = make(map[string]*URLSubscriber)
+		}
+		if sm.Connections == nil {
+			sm.Connections = make(map[string]*types.Connection)
+		}
+		sm.urlSubs[request.URL] = sub
+		sm.Connections[sub.conn.ID] = sub.conn
+	}
+	sub.setCredentials(request.URLUsername, request.URLPassword)
+	return sub, nil
+}
+
+// removeURLSubscriberLocked unregisters and stops a subscriber.
+func (sm *Manager) removeURLSubscriberLocked(sub *URLSubscriber) {
+	delete(sm.urlSubs, sub.url)
+	delete(sm.Connections, sub.conn.ID)
+	sub.stop()
+}
@@ -0,0 +1,35 @@
+package rpc
+
+import (
+	"github.com/LeJamon/goXRPLd/internal/ledger/service"
+	"github.com/LeJamon/goXRPLd/internal/rpc/types"
+)
+
+var _ types.SubmissionTracker = (*LedgerServiceAdapter)(nil)
+
+// GetSubmission returns the state of a transaction submitted to this server
+func (a *LedgerServiceAdapter) GetSubmission(hash [32]byte) (*types.Submission, bool) {
+	sub, ok := a.svc.GetSubmission(hash)
+	if !ok {
+		return nil, false
+	}
+	return SubmissionStatus(sub), true
+}
+
+// SubmissionStatus converts a submission of the ledger service for the
+// tx_status method and the submissions stream.
+func SubmissionStatus(sub service.Submission) *types.Submission {
+	return &types.Submission{
+		Hash:               sub.Hash,
+		Account:            sub.Account,
+		Sequence:           sub.Sequence,
+		LastLedgerSequence: sub.LastLedgerSequence,
+		State:              string(sub.State),
+		Final:              sub.State.Final(),
+		EngineResult:       sub.EngineResult,
+		SubmittedLedger:    sub.SubmittedLedger,
+		LedgerIndex:        sub.LedgerIndex,
+		Relayed:            sub.Relayed,
+		Attempts:           sub.Attempts,
+	}
+}
@@ -127,6 +127,9 @@ const (
 	// Simulate errors - must match rippled exactly
 	RpcTX_SIGNED         = 96 // Transaction should not be signed (rippled: rpcTX_SIGNED = 96)
 	RpcSRC_ACT_MALFORMED = 65 // Source account is malformed (rippled: rpcSRC_ACT_MALFORMED = 65)
+
+	// Permissioned DEX errors - must match rippled exactly
+	RpcDOMAIN_MALFORMED = 97 // Domain is not a 256-bit hex string (rippled: rpcDOMAIN_MALFORMED = 97)
 )
 
 // Standard error constructors
@@ -220,6 +223,12 @@ func RpcErrorBadCredentials(message string) *RpcError {
 	return NewRpcError(RpcBAD_CREDENTIALS, "badCredentials", "badCredentials", message)
 }
 
+// RpcErrorDomainMalformed returns an error for an unparseable permissioned
+// domain ID (matches rippled rpcDOMAIN_MALFORMED, code 97, token "domainMalformed").
+func RpcErrorDomainMalformed(message string) *RpcError {
+	return NewRpcError(RpcDOMAIN_MALFORMED, "domainMalformed", "domainMalformed", message)
+}
+
 // RpcErrorHighFee returns an error when the auto-filled fee exceeds the requested limit (matches rippled rpcHIGH_FEE).
 func RpcErrorHighFee(message string) *RpcError {
 	return NewRpcError(RpcHIGH_FEE, "highFee", "highFee", message)
@@ -2,6 +2,7 @@ package types
 
 import (
 	"github.com/LeJamon/goXRPLd/amendment"
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/definitions"
 	"github.com/LeJamon/goXRPLd/drops"
 	"github.com/LeJamon/goXRPLd/keylet"
 )
@@ -38,6 +39,38 @@ type ManifestLookup interface {
 	GetDomain(masterKey [33]byte) (string, bool)
 }
 
+// PeerAdmin is the overlay facet behind the admin network RPCs
+// (connect, peer_reservations_*, blacklist). Like PeerSource it speaks
+// JSON-shaped maps so internal/rpc/types need not import
+// internal/peermanagement.
+type PeerAdmin interface {
+	// Connect dials addr ("host:port") and blocks until the handshake
+	// completes or fails.
+	Connect(addr string) error
+	// AddReservation inserts or replaces a reservation and returns the
+	// previous entry ({"node", "description"}) or nil when new.
+	AddReservation(nodeKey, description string) (map[string]any, error)
+	// DeleteReservation removes a reservation and returns the removed
+	// entry or nil when none existed.
+	DeleteReservation(nodeKey string) (map[string]any, error)
+	// ReservationsJSON lists every reservation ordered by node key.
+	ReservationsJSON() []map[string]any
+	// ResourceJSON lists peers at or above the bad-data threshold plus
+	// recently dropped endpoints, keyed by address.
+	ResourceJSON(threshold int) map[string]any
+}
+
+// UNLEntry is one listed validator as reported by unl_list.
+type UNLEntry struct {
+	// PublicKey is the base58 node public key ("n...").
+	PublicKey string
+	// Trusted reports whether the key counts toward quorum.
+	Trusted bool
+	// NegativeUNL reports whether the validator is currently disabled
+	// by the ltNEGATIVE_UNL entry of the validated ledger.
+	NegativeUNL bool
+}
+
 // ServiceContainer holds references to all services needed by RPC handlers
 type ServiceContainer struct {
 	// LedgerService provides ledger operations
@@ -58,6 +91,11 @@ type ServiceContainer struct {
 	// LastCloseInfo returns proposer count and convergence time (ms) from the last consensus round
 	LastCloseInfo func() (proposers int, convergeTimeMs int)
 
+	// ClusterLoadFee returns the median load fee reported by
+	// [cluster_nodes] members, or 0 when none reported recently (nil
+	// when not in consensus mode). Surfaced as load_factor_cluster.
+	ClusterLoadFee func() uint32
+
 	// Manifests is the validator-manifest lookup used by the
 	// `manifest` RPC method. Nil until the consensus components are
 	// built (e.g. in standalone mode without p2p); handlers must
@@ -69,6 +107,218 @@ type ServiceContainer struct {
 	// as a validator. Mirrors rippled's Application::getValidationPublicKey
 	// — validator_info uses emptiness to gate the notValidator response.
 	ValidatorPublicKey []byte
+
+	// PeerAdmin backs the admin network RPCs. Nil in standalone mode;
+	// handlers must nil-check before use.
+	PeerAdmin PeerAdmin
+
+	// UNLList returns the configured validator list with trust and
+	// negative-UNL status (nil when not in consensus mode).
+	UNLList func() []UNLEntry
+
+	// URLSubscriptions backs subscribe and unsubscribe with "url" over
+	// HTTP. Nil until the WebSocket server is built; handlers must
+	// nil-check before use.
+	URLSubscriptions URLSubscriptions
+
+	// NFTIndex backs Clio's nft_info, nft_history and nfts_by_issuer.
+	// Nil without a relational database; handlers must nil-check before
+	// use.
+	NFTIndex NFTIndex
+
+	// MPTs backs mpt_holders and account_mpt_issuances. mpt_holders also
+	// needs a relational database. Handlers must nil-check before use.
+	MPTs MPTQueries
+
+	// LedgerDiffs backs ledger_diff. Handlers must nil-check before use.
+	LedgerDiffs LedgerDiffer
+
+	// Owners backs owner_info. Handlers must nil-check before use.
+	Owners OwnerInfoQuerier
+
+	// Submissions backs tx_status. Handlers must nil-check before use.
+	Submissions SubmissionTracker
+}
+
+// URLSubscriptions manages server-to-server subscriptions, which deliver
+// stream events to a URL by HTTP POST instead of over a connection.
+// Implemented by subscription.Manager.
+type URLSubscriptions interface {
+	SubscribeURL(request SubscriptionRequest) *RpcError
+	UnsubscribeURL(request SubscriptionRequest) *RpcError
+}
+
+// NFTIndex answers NFT queries from the NFT index that the relational
+// database builds from transaction metadata. Implemented by the ledger
+// service adapter.
+type NFTIndex interface {
+	GetNFTInfo(nftID [32]byte, ledgerIndex string) (*IndexedNFTResult, *RpcError)
+	GetNFTHistory(nftID [32]byte, ledgerMin, ledgerMax int64, limit uint32, marker *AccountTxMarker, forward bool) (*NFTHistoryResult, *RpcError)
+	GetNFTsByIssuer(issuer string, taxon *uint32, ledgerIndex string, marker *[32]byte, limit uint32) (*NFTsByIssuerResult, *RpcError)
+}
+
+// IndexedNFT is the state of an NFT as recorded by the NFT index.
+type IndexedNFT struct {
+	NFTokenID   [32]byte
+	Owner       string
+	IsBurned    bool
+	Flags       uint16
+	TransferFee uint16
+	Issuer      string
+	Taxon       uint32
+	Serial      uint32
+	URI         []byte
+}
+
+// IndexedNFTResult contains the result of nft_info
+type IndexedNFTResult struct {
+	NFT         IndexedNFT
+	LedgerIndex uint32
+	Validated   bool
+}
+
+// NFTHistoryResult contains the result of nft_history
+type NFTHistoryResult struct {
+	LedgerMin    uint32
+	LedgerMax    uint32
+	Limit        uint32
+	Marker       *AccountTxMarker
+	Transactions []AccountTransaction
+}
+
+// NFTsByIssuerResult contains the result of nfts_by_issuer
+type NFTsByIssuerResult struct {
+	NFTs        []IndexedNFT
+	LedgerIndex uint32
+	Validated   bool
+	Limit       uint32
+	Marker      *[32]byte
+}
+
+// MPTQueries answers queries about MPT issuances and their holders.
+// Implemented by the ledger service adapter.
+type MPTQueries interface {
+	GetMPTHolders(mptID [24]byte, ledgerIndex string, marker *[20]byte, limit uint32) (*MPTHoldersResult, *RpcError)
+	GetAccountMPTIssuances(account string, ledgerIndex string, marker *[32]byte, limit uint32) (*AccountMPTIssuancesResult, *RpcError)
+}
+
+// MPTHolder is a holder's MPToken. Locked is set when either the token or
+// the whole issuance is locked.
+type MPTHolder struct {
+	Account      string
+	Flags        uint32
+	MPTAmount    uint64
+	LockedAmount *uint64
+	MPTokenIndex [32]byte
+	Locked       bool
+}
+
+// MPTHoldersResult contains the result of mpt_holders
+type MPTHoldersResult struct {
+	MPTIssuanceID [24]byte
+	Holders       []MPTHolder
+	LedgerIndex   uint32
+	Validated     bool
+	Limit         uint32
+	Marker        *[20]byte
+}
+
+// MPTIssuance summarizes an MPTokenIssuance ledger entry
+type MPTIssuance struct {
+	MPTIssuanceID     [24]byte
+	Index             [32]byte
+	Sequence          uint32
+	Flags             uint32
+	AssetScale        uint8
+	TransferFee       uint16
+	MaximumAmount     *uint64
+	OutstandingAmount uint64
+	LockedAmount      *uint64
+	MPTokenMetadata   string
+	DomainID          *string
+}
+
+// AccountMPTIssuancesResult contains the result of account_mpt_issuances
+type AccountMPTIssuancesResult struct {
+	Account     string
+	Issuances   []MPTIssuance
+	LedgerIndex uint32
+	LedgerHash  [32]byte
+	Validated   bool
+	Limit       uint32
+	Marker      *[32]byte
+}
+
+// LedgerDiffer computes the state differences between two ledgers.
+// Implemented by the ledger service adapter.
+type LedgerDiffer interface {
+	GetLedgerDiff(base, desired LedgerSpecifier, entryType string, marker *[32]byte, limit uint32) (*LedgerDiffResult, *RpcError)
+}
+
+// LedgerDiffEntry is a state entry that differs between two ledgers.
+// Status is "created", "modified" or "deleted"; Before is nil for created
+// entries and After for deleted ones.
+type LedgerDiffEntry struct {
+	Index  [32]byte
+	Status string
+	Before []byte
+	After  []byte
+}
+
+// LedgerDiffResult contains the result of ledger_diff
+type LedgerDiffResult struct {
+	BaseLedgerIndex    uint32
+	BaseLedgerHash     [32]byte
+	DesiredLedgerIndex uint32
+	DesiredLedgerHash  [32]byte
+	Validated          bool
+	Entries            []LedgerDiffEntry
+	Limit              uint32
+	Marker             *[32]byte
+}
+
+// OwnerInfoQuerier lists the offers and trust lines in an account's owner
+// directory. Implemented by the ledger service adapter.
+type OwnerInfoQuerier interface {
+	GetOwnerInfo(account, ledgerIndex string) (*OwnerInfoResult, *RpcError)
+}
+
+// OwnedEntry is an entry of an account's owner directory
+type OwnedEntry struct {
+	Index [32]byte
+	Data  []byte
+}
+
+// OwnerInfoResult contains the owner_info view of one ledger
+type OwnerInfoResult struct {
+	LedgerIndex uint32
+	LedgerHash  [32]byte
+	Validated   bool
+	Offers      []OwnedEntry
+	RippleLines []OwnedEntry
+}
+
+// SubmissionTracker reports the state of the transactions submitted to
+// this server. Implemented by the ledger service adapter.
+type SubmissionTracker interface {
+	GetSubmission(hash [32]byte) (*Submission, bool)
+}
+
+// Submission is the state of a transaction submitted to this server: one
+// of "queued", "open_ledger", "included", "validated", "expired" or
+// "dropped", the last three being final.
+type Submission struct {
+	Hash               [32]byte
+	Account            string
+	Sequence           uint32
+	LastLedgerSequence uint32
+	State              string
+	Final              bool
+	EngineResult       string
+	SubmittedLedger    uint32
+	LedgerIndex        uint32
+	Relayed            int
+	Attempts           int
 }
 
 // LedgerNavigator provides ledger index navigation and mode queries.
@@ -108,7 +358,7 @@ type AccountQuerier interface {
 	GetAccountInfo(account string, ledgerIndex string) (*AccountInfo, error)
 	GetAccountLines(account string, ledgerIndex string, peer string, limit uint32) (*AccountLinesResult, error)
 	GetAccountOffers(account string, ledgerIndex string, limit uint32) (*AccountOffersResult, error)
-	GetAccountTransactions(account string, ledgerMin, ledgerMax int64, limit uint32, marker *AccountTxMarker, forward bool) (*AccountTxResult, error)
+	GetAccountTransactions(account string, ledgerMin, ledgerMax int64, limit uint32, marker *AccountTxMarker, forward bool, filter AccountTxFilter) (*AccountTxResult, error)
 	GetAccountChannels(account string, destinationAccount string, ledgerIndex string, limit uint32) (*AccountChannelsResult, error)
 	GetAccountCurrencies(account string, ledgerIndex string) (*AccountCurrenciesResult, error)
 	GetAccountObjects(account string, ledgerIndex string, objType string, limit uint32) (*AccountObjectsResult, error)
@@ -124,7 +374,7 @@ type LedgerService interface {
 	AccountQuerier
 
 	// Book and market data
-	GetBookOffers(takerGets, takerPays Amount, ledgerIndex string, limit uint32) (*BookOffersResult, error)
+	GetBookOffers(takerGets, takerPays Amount, domain *[32]byte, ledgerIndex string, limit uint32) (*BookOffersResult, error)
 
 	// Gateway operations
 	GetGatewayBalances(account string, hotWallets []string, ledgerIndex string) (*GatewayBalancesResult, error)
@@ -233,7 +483,8 @@ type SubmitResult struct {
 	// Broadcast indicates if the transaction was broadcast to peers
 	Broadcast bool
 
-	// Queued indicates if the transaction was placed in the transaction queue
+	// Queued indicates if the transaction was held to retry in a later
+	// open ledger
 	Queued bool
 
 	// Kept indicates if the transaction was kept for retry
@@ -341,6 +592,7 @@ type BookOffer struct {
 	LedgerEntryType string      `json:"LedgerEntryType"`
 	OwnerNode       string      `json:"OwnerNode"`
 	Sequence        uint32      `json:"Sequence"`
+	DomainID        string      `json:"DomainID,omitempty"`
 	TakerGets       interface{} `json:"TakerGets"`
 	TakerPays       interface{} `json:"TakerPays"`
 	Index           string      `json:"index"`
@@ -364,6 +616,52 @@ type AccountTxMarker struct {
 	TxnSeq    uint32 `json:"seq"`
 }
 
+// Directions of an AccountTxFilter
+const (
+	AccountTxIncoming = "incoming"
+	AccountTxOutgoing = "outgoing"
+)
+
+// AccountTxFilter narrows account_tx results and accounts subscriptions to
+// one transaction type and/or direction. Outgoing transactions are those
+// the account sent, incoming ones those sent to it as their Destination;
+// transactions that only affected the account are neither.
+type AccountTxFilter struct {
+	TxType    string `json:"tx_type,omitempty"`
+	Direction string `json:"direction,omitempty"`
+}
+
+// ParseAccountTxFilter validates the tx_type and direction parameters.
+// Empty values match everything.
+func ParseAccountTxFilter(txType, direction string) (AccountTxFilter, *RpcError) {
+	if txType != "" {
+		if _, err := definitions.Get().GetTransactionTypeCodeByTransactionTypeName(txType); err != nil {
+			return AccountTxFilter{}, RpcErrorInvalidField("tx_type")
+		}
+	}
+	switch direction {
+	case "", AccountTxIncoming, AccountTxOutgoing:
+	default:
+		return AccountTxFilter{}, RpcErrorInvalidField("direction")
+	}
+	return AccountTxFilter{TxType: txType, Direction: direction}, nil
+}
+
+// Matches reports whether a transaction of type txType sent by sender to
+// destination, which may be empty, passes the filter for account.
+func (f AccountTxFilter) Matches(account, txType, sender, destination string) bool {
+	if f.TxType != "" && f.TxType != txType {
+		return false
+	}
+	switch f.Direction {
+	case AccountTxOutgoing:
+		return account == sender
+	case AccountTxIncoming:
+		return account == destination && account != sender
+	}
+	return true
+}
+
 // AccountTransaction contains transaction data for account_tx
 type AccountTransaction struct {
 	Hash        [32]byte `json:"hash"`
@@ -530,6 +828,8 @@ type GatewayBalancesResult struct {
 	FrozenBalances map[string][]CurrencyBalance `json:"frozen_balances,omitempty"` // account -> []balance
 	Assets         map[string][]CurrencyBalance `json:"assets,omitempty"`          // account -> []balance
 	Locked         map[string]string            `json:"locked,omitempty"`          // currency -> value (escrows)
+	MPTObligations map[string]string            `json:"mpt_obligations,omitempty"` // mpt_issuance_id -> outstanding amount
+	MPTLocked      map[string]string            `json:"mpt_locked,omitempty"`      // mpt_issuance_id -> locked amount
 	LedgerIndex    uint32                       `json:"ledger_index"`
 	LedgerHash     [32]byte                     `json:"ledger_hash"`
 	Validated      bool                         `json:"validated"`
@@ -2,8 +2,10 @@ package types
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	addresscodec "github.com/LeJamon/goXRPLd/codec/addresscodec"
 )
@@ -205,13 +207,15 @@ const (
 	SubTransactions         SubscriptionType = "transactions"
 	SubTransactionsProposed SubscriptionType = "transactions_proposed"
 	SubAccounts             SubscriptionType = "accounts"
-	SubOrderBooks           SubscriptionType = "book_changes"
+	SubOrderBooks           SubscriptionType = "books" // a connection's books, not a stream
+	SubBookChanges          SubscriptionType = "book_changes"
 	SubValidations          SubscriptionType = "validations"
 	SubManifests            SubscriptionType = "manifests"
 	SubPeerStatus           SubscriptionType = "peer_status"
 	SubServer               SubscriptionType = "server"
 	SubConsensus            SubscriptionType = "consensus"
 	SubPath                 SubscriptionType = "path_find"
+	SubSubmissions          SubscriptionType = "submissions" // the connection's own submissions
 )
 
 // Subscription request structure
@@ -223,6 +227,14 @@ type SubscriptionRequest struct {
 	URL              string             `json:"url,omitempty"`
 	URLUsername      string             `json:"url_username,omitempty"`
 	URLPassword      string             `json:"url_password,omitempty"`
+	// TxType and Direction filter the accounts subscription's events as
+	// account_tx's parameters of the same names do.
+	TxType    string `json:"tx_type,omitempty"`
+	Direction string `json:"direction,omitempty"`
+	// ResumeLedger replays the validated transactions of the ledgers from
+	// this one onwards before live events, for a client reconnecting
+	// after a gap. Only valid with the transactions stream.
+	ResumeLedger uint32 `json:"resume_ledger,omitempty"`
 }
 
 // Book request for order book subscriptions
@@ -231,6 +243,9 @@ type BookRequest struct {
 	TakerGets json.RawMessage `json:"taker_gets"`
 	Snapshot  bool            `json:"snapshot,omitempty"`
 	Both      bool            `json:"both,omitempty"`
+	// Domain selects the order book of a permissioned domain (64 hex chars).
+	// Empty means the open DEX book.
+	Domain string `json:"domain,omitempty"`
 }
 
 // Stream message types
@@ -317,10 +332,19 @@ type CurrencySpec struct {
 	Issuer   string `json:"issuer,omitempty"`
 }
 
+// OrderBook identifies an order book by the currencies its offers give
+// and take and its permissioned domain, empty for the open DEX.
+type OrderBook struct {
+	TakerGets CurrencySpec
+	TakerPays CurrencySpec
+	Domain    string
+}
+
 // SubscriptionConfig holds configuration for a specific subscription
 type SubscriptionConfig struct {
 	// For account subscriptions
-	Accounts []string `json:"accounts,omitempty"`
+	Accounts []string        `json:"accounts,omitempty"`
+	Filter   AccountTxFilter `json:"filter,omitempty"`
 	// For book subscriptions (multiple books)
 	Books []BookRequest `json:"books,omitempty"`
 	// For single book subscription (legacy)
@@ -328,6 +352,7 @@ type SubscriptionConfig struct {
 	TakerPays *CurrencySpec `json:"taker_pays,omitempty"`
 	Snapshot  bool          `json:"snapshot,omitempty"`
 	Both      bool          `json:"both,omitempty"`
+	Domain    string        `json:"domain,omitempty"`
 	// For URL subscriptions
 	URL      string `json:"url,omitempty"`
 	Username string `json:"url_username,omitempty"`
@@ -341,6 +366,9 @@ type Connection struct {
 	SendChannel     chan []byte
 	CloseChannel    chan struct{}
 	URLSubscription string // URL for server-to-server subscriptions
+	// SlowConsumer is called, without blocking, when a message is dropped
+	// because SendChannel is full. Nil just drops the message.
+	SlowConsumer func()
 }
 
 // WebSocketResponseOptions contains optional fields for WebSocket responses
@@ -391,6 +419,49 @@ func BookMatchesCurrency(book BookRequest, specGets, specPays CurrencySpec) bool
 	return true
 }
 
+// BookMatches checks if a book request matches the given currency specs
+// and permissioned domain. Domains compare case-insensitively; an empty
+// domain is the open DEX book.
+func BookMatches(book BookRequest, specGets, specPays CurrencySpec, domain string) bool {
+	if !strings.EqualFold(book.Domain, domain) {
+		return false
+	}
+	return BookMatchesCurrency(book, specGets, specPays)
+}
+
+// ParseDomainParam parses an optional "domain" request field. It returns
+// nil when the field is absent and rpcDOMAIN_MALFORMED unless the value is
+// a string holding exactly 256 bits of hex, matching rippled's
+// uint256::parseHex check in BookOffers.cpp and PathRequest::parseJson.
+func ParseDomainParam(raw json.RawMessage) (*[32]byte, *RpcError) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, RpcErrorDomainMalformed("Unable to parse domain.")
+	}
+	domain, ok := ParseDomainHex(s)
+	if !ok {
+		return nil, RpcErrorDomainMalformed("Unable to parse domain.")
+	}
+	return domain, nil
+}
+
+// ParseDomainHex decodes a 64-character hex domain ID.
+func ParseDomainHex(s string) (*[32]byte, bool) {
+	if len(s) != 64 {
+		return nil, false
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, false
+	}
+	var domain [32]byte
+	copy(domain[:], b)
+	return &domain, true
+}
+
 // LedgerInfoProvider provides current ledger info for subscribe responses
 type LedgerInfoProvider interface {
 	GetCurrentLedgerInfo() *LedgerSubscribeInfo
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/LeJamon/goXRPLd/internal/rpc/handlers"
 	"github.com/LeJamon/goXRPLd/internal/rpc/subscription"
 	"github.com/LeJamon/goXRPLd/internal/rpc/types"
 	xrpllog "github.com/LeJamon/goXRPLd/log"
@@ -23,6 +25,10 @@ func wsLog() xrpllog.Logger { return xrpllog.Named(xrpllog.PartitionRPC) }
 // matching rippled's default ws_queue_limit of 100 (Port.cpp).
 const DefaultSendQueueLimit = 100
 
+// slowConsumerReason is the close reason sent to a client whose send queue
+// overflowed, as in rippled's WSSession.
+const slowConsumerReason = "Policy error: client is too slow."
+
 // WebSocketServer handles WebSocket connections for real-time subscriptions
 type WebSocketServer struct {
 	upgrader            websocket.Upgrader
@@ -33,6 +39,8 @@ type WebSocketServer struct {
 	timeout             time.Duration
 	ledgerInfoProvider  types.LedgerInfoProvider
 	connLimiter         *ConnLimiter
+	txReplayer          TransactionReplayer
+	prepared            *preparedMessages
 }
 
 // WebSocketConnection represents a single WebSocket connection
@@ -47,11 +55,24 @@ type WebSocketConnection struct {
 	cancel          context.CancelFunc
 	pathFindSession *PathFindSession // At most one active path_find session per connection
 	portCtx         *PortContext     // per-port config for role determination
+	deflate         bool             // permessage-deflate negotiated
+	tooSlow         chan struct{}    // closed once the send queue overflowed
+	tooSlowOnce     sync.Once
+}
+
+// markSlow flags the connection for disconnection because a message did
+// not fit in its send queue. Dropping the message instead would leave the
+// client with a silent gap.
+func (c *WebSocketConnection) markSlow() {
+	c.tooSlowOnce.Do(func() {
+		wsLog().Warn("WebSocket send queue full, disconnecting", "connID", c.ID, "limit", cap(c.sendChannel))
+		close(c.tooSlow)
+	})
 }
 
 // NewWebSocketServer creates a new WebSocket server
 func NewWebSocketServer(timeout time.Duration) *WebSocketServer {
-	return &WebSocketServer{
+	ws := &WebSocketServer{
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				// TODO: Implement proper origin checking for security
@@ -66,7 +87,10 @@ func NewWebSocketServer(timeout time.Duration) *WebSocketServer {
 		methodRegistry: types.NewMethodRegistry(),
 		connections:    make(map[string]*WebSocketConnection),
 		timeout:        timeout,
+		prepared:       newPreparedMessages(preparedCacheSize),
 	}
+	ws.subscriptionManager.OnBroadcast = ws.prepared.add
+	return ws
 }
 
 // SetLedgerInfoProvider sets the provider used to return current ledger info
@@ -75,6 +99,12 @@ func (ws *WebSocketServer) SetLedgerInfoProvider(provider types.LedgerInfoProvid
 	ws.ledgerInfoProvider = provider
 }
 
+// SetTransactionReplayer sets the source of the transactions replayed for
+// subscribe's resume_ledger. Without one, resume_ledger is rejected.
+func (ws *WebSocketServer) SetTransactionReplayer(replayer TransactionReplayer) {
+	ws.txReplayer = replayer
+}
+
 // SetConnLimiter sets the connection limiter used to release per-port slots
 // when WebSocket connections close.
 func (ws *WebSocketServer) SetConnLimiter(limiter *ConnLimiter) {
@@ -86,12 +116,22 @@ func (ws *WebSocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Extract per-port context injected by PortMiddleware
 	portCtx := GetPortContext(r.Context())
 
-	// Upgrade HTTP connection to WebSocket
-	conn, err := ws.upgrader.Upgrade(w, r, nil)
+	// Upgrade HTTP connection to WebSocket, compressing if the port
+	// allows it and the client offers it
+	upgrader := ws.upgrader
+	upgrader.EnableCompression = portCtx != nil && portCtx.Deflate && acceptsDeflate(r.Header)
+	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		wsLog().Error("WebSocket upgrade failed", "err", err)
 		return
 	}
+	if upgrader.EnableCompression {
+		level := DefaultDeflateLevel
+		if portCtx.DeflateLevel > 0 {
+			level = portCtx.DeflateLevel
+		}
+		conn.SetCompressionLevel(level)
+	}
 
 	// Determine send queue size from port config, default to 100 (rippled default)
 	sendQueueLimit := DefaultSendQueueLimit
@@ -112,6 +152,8 @@ func (ws *WebSocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		ctx:           ctx,
 		cancel:        cancel,
 		portCtx:       portCtx,
+		deflate:       upgrader.EnableCompression,
+		tooSlow:       make(chan struct{}),
 	}
 
 	// Register connection
@@ -124,6 +166,7 @@ func (ws *WebSocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Subscriptions: wsConn.subscriptions,
 		SendChannel:   wsConn.sendChannel,
 		CloseChannel:  wsConn.closeChannel,
+		SlowConsumer:  wsConn.markSlow,
 	}
 	ws.subscriptionManager.AddConnection(legacyConn)
 
@@ -198,9 +241,15 @@ func (ws *WebSocketServer) handleSend(wsConn *WebSocketConnection) {
 			return
 		case <-wsConn.closeChannel:
 			return
+		case <-wsConn.tooSlow:
+			wsConn.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.ClosePolicyViolation, slowConsumerReason),
+				time.Now().Add(time.Second))
+			wsConn.conn.Close()
+			return
 		case message := <-wsConn.sendChannel:
 			wsConn.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			if err := wsConn.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+			if err := ws.writeMessage(wsConn, message); err != nil {
 				wsLog().Debug("WebSocket send failed", "err", err)
 				return
 			}
@@ -208,6 +257,22 @@ func (ws *WebSocketServer) handleSend(wsConn *WebSocketConnection) {
 	}
 }
 
+// writeMessage writes a queued message. Compressed connections share the
+// compressed form of broadcasts; any other message, such as a response,
+// is compressed for its connection alone.
+func (ws *WebSocketServer) writeMessage(wsConn *WebSocketConnection, message []byte) error {
+	if wsConn.deflate {
+		pm, ok, err := ws.prepared.get(message)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return wsConn.conn.WritePreparedMessage(pm)
+		}
+	}
+	return wsConn.conn.WriteMessage(websocket.TextMessage, message)
+}
+
 // handleMessage processes a single message from WebSocket
 func (ws *WebSocketServer) handleMessage(wsConn *WebSocketConnection, message []byte) {
 	// Parse WebSocket command - XRPL format has command and params at top level
@@ -293,6 +358,12 @@ func (ws *WebSocketServer) handleSubscribe(wsConn *WebSocketConnection, ctx *typ
 		}
 	}
 
+	// URL subscriptions make the server post to arbitrary addresses.
+	if request.URL != "" && ctx.Role != types.RoleAdmin {
+		ws.sendError(wsConn, types.RpcErrorNoPermission(cmd.Command), cmd.ID)
+		return
+	}
+
 	// Handle subscription through subscription manager
 	conn := &types.Connection{
 		ID:            wsConn.ID,
@@ -300,7 +371,12 @@ func (ws *WebSocketServer) handleSubscribe(wsConn *WebSocketConnection, ctx *typ
 		SendChannel:   wsConn.sendChannel,
 		CloseChannel:  wsConn.closeChannel,
 	}
-	if err := ws.subscriptionManager.HandleSubscribe(conn, request); err != nil {
+	if request.ResumeLedger > 0 {
+		if err := ws.resumeSubscribe(wsConn, conn, request); err != nil {
+			ws.sendError(wsConn, err, cmd.ID)
+			return
+		}
+	} else if err := ws.subscriptionManager.HandleSubscribe(conn, request); err != nil {
 		ws.sendError(wsConn, err, cmd.ID)
 		return
 	}
@@ -330,6 +406,11 @@ func (ws *WebSocketServer) handleSubscribe(wsConn *WebSocketConnection, ctx *typ
 		}
 	}
 
+	if err := bookSnapshots(request.Books, result); err != nil {
+		ws.sendError(wsConn, err, cmd.ID)
+		return
+	}
+
 	response := types.WebSocketResponse{
 		Type:       "response",
 		ID:         cmd.ID,
@@ -340,6 +421,147 @@ func (ws *WebSocketServer) handleSubscribe(wsConn *WebSocketConnection, ctx *typ
 	ws.sendResponse(wsConn, response)
 }
 
+// bookSnapshots adds to result the offers, in the validated ledger, of the
+// books subscribed with "snapshot": under "offers", or for books with
+// "both" under "bids" and, for the reverse book, "asks".
+// Reference: rippled Subscribe.cpp (jss::snapshot)
+func bookSnapshots(books []types.BookRequest, result map[string]interface{}) *types.RpcError {
+	for _, book := range books {
+		if !book.Snapshot {
+			continue
+		}
+		if err := handlers.RequireLedgerService(); err != nil {
+			return err
+		}
+		takerGets, err := handlers.ParseAmountFromJSON(book.TakerGets)
+		if err != nil {
+			return types.RpcErrorInvalidParams("Invalid taker_gets: " + err.Error())
+		}
+		takerPays, err := handlers.ParseAmountFromJSON(book.TakerPays)
+		if err != nil {
+			return types.RpcErrorInvalidParams("Invalid taker_pays: " + err.Error())
+		}
+		var domain *[32]byte
+		if book.Domain != "" {
+			var ok bool
+			if domain, ok = types.ParseDomainHex(book.Domain); !ok {
+				return types.RpcErrorInvalidParams("Invalid domain: " + book.Domain)
+			}
+		}
+
+		addOffers := func(key string, gets, pays types.Amount) *types.RpcError {
+			page, err := types.Services.Ledger.GetBookOffers(gets, pays, domain, "validated", handlers.LimitBookOffers.Default)
+			if err != nil {
+				return types.RpcErrorInternal("Failed to get book offers: " + err.Error())
+			}
+			offers, _ := result[key].([]types.BookOffer)
+			result[key] = append(offers, page.Offers...)
+			return nil
+		}
+		if !book.Both {
+			if err := addOffers("offers", takerGets, takerPays); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := addOffers("bids", takerGets, takerPays); err != nil {
+			return err
+		}
+		if err := addOffers("asks", takerPays, takerGets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resumeSubscribe replays the transactions of the ledgers from
+// request.ResumeLedger up to the last published one, read from the stored
+// validated ledgers, then subscribes. Ledgers published while replaying
+// are replayed in turn, until the subscription is taken over by the live
+// stream at a ledger boundary.
+func (ws *WebSocketServer) resumeSubscribe(wsConn *WebSocketConnection, conn *types.Connection, request types.SubscriptionRequest) *types.RpcError {
+	if ws.txReplayer == nil {
+		return types.RpcErrorNotEnabled("resume_ledger")
+	}
+	if request.URL != "" || !slices.Contains(request.Streams, types.SubTransactions) {
+		return types.RpcErrorInvalidParams("resume_ledger requires the transactions stream.")
+	}
+
+	minSeq, maxSeq, err := ws.txReplayer.LedgerRange(wsConn.ctx)
+	if err != nil {
+		wsLog().Error("Failed to read the stored ledger range", "err", err)
+		return types.RpcErrorInternal("Failed to read the stored ledgers.")
+	}
+	if maxSeq == 0 || request.ResumeLedger < minSeq || request.ResumeLedger > maxSeq+1 {
+		return types.RpcErrorLgrNotFound(fmt.Sprintf("resume_ledger is not in the stored validated ledgers (%s).", formatLedgerRange(minSeq, maxSeq)))
+	}
+	if maxSeq >= request.ResumeLedger && maxSeq-request.ResumeLedger >= MaxResumeLedgers {
+		return types.RpcErrorInvalidParams(fmt.Sprintf("resume_ledger is too old; at most %d ledgers can be replayed.", MaxResumeLedgers))
+	}
+
+	next := request.ResumeLedger
+	for {
+		if last := ws.subscriptionManager.PublishedLedger(); last >= next {
+			if last > maxSeq {
+				if _, maxSeq, err = ws.txReplayer.LedgerRange(wsConn.ctx); err != nil {
+					wsLog().Error("Failed to read the stored ledger range", "err", err)
+					return types.RpcErrorInternal("Failed to read the stored ledgers.")
+				}
+				if last > maxSeq {
+					return types.RpcErrorLgrNotFound(fmt.Sprintf("Ledger %d is published but not stored.", last))
+				}
+			}
+			events, err := ws.txReplayer.Replay(wsConn.ctx, next, last)
+			if err != nil {
+				wsLog().Error("Failed to replay transactions", "from", next, "to", last, "err", err)
+				return types.RpcErrorInternal("Failed to replay transactions.")
+			}
+			for _, event := range events {
+				if !ws.sendReplayed(wsConn, event) {
+					return types.RpcErrorInternal("Connection closed during replay.")
+				}
+			}
+			next = last + 1
+		}
+		subscribed, err := ws.subscriptionManager.SubscribeAfter(next-1, conn, request)
+		if err != nil {
+			return err
+		}
+		if subscribed {
+			return nil
+		}
+	}
+}
+
+// formatLedgerRange formats a range of ledgers for an error message.
+func formatLedgerRange(minSeq, maxSeq uint32) string {
+	if maxSeq == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%d-%d", minSeq, maxSeq)
+}
+
+// sendReplayed queues a replayed event, waiting for room in the send queue
+// up to the server timeout. It returns false if the connection is gone.
+func (ws *WebSocketServer) sendReplayed(wsConn *WebSocketConnection, event *TransactionEvent) bool {
+	data, err := json.Marshal(event)
+	if err != nil {
+		wsLog().Error("Failed to marshal replayed transaction", "err", err)
+		return true
+	}
+	timer := time.NewTimer(ws.timeout)
+	defer timer.Stop()
+	select {
+	case wsConn.sendChannel <- data:
+		return true
+	case <-wsConn.ctx.Done():
+	case <-wsConn.tooSlow:
+	case <-timer.C:
+		wsConn.markSlow()
+	}
+	return false
+}
+
 // handleUnsubscribe processes unsubscribe commands
 func (ws *WebSocketServer) handleUnsubscribe(wsConn *WebSocketConnection, ctx *types.RpcContext, cmd types.WebSocketCommand) {
 	var request types.SubscriptionRequest
@@ -350,6 +572,12 @@ func (ws *WebSocketServer) handleUnsubscribe(wsConn *WebSocketConnection, ctx *t
 		}
 	}
 
+	// URL subscriptions make the server post to arbitrary addresses.
+	if request.URL != "" && ctx.Role != types.RoleAdmin {
+		ws.sendError(wsConn, types.RpcErrorNoPermission(cmd.Command), cmd.ID)
+		return
+	}
+
 	conn := &types.Connection{
 		ID:            wsConn.ID,
 		Subscriptions: wsConn.subscriptions,
@@ -523,7 +751,7 @@ func (ws *WebSocketServer) UpdatePathFindSessions(getView func() (types.LedgerSt
 		select {
 		case conn.sendChannel <- data:
 		default:
-			// Channel full, skip this update
+			conn.markSlow()
 		}
 	}
 }
@@ -546,6 +774,11 @@ func (ws *WebSocketServer) handleRPCMethod(wsConn *WebSocketConnection, ctx *typ
 	if rpcErr != nil {
 		ws.sendError(wsConn, rpcErr, cmd.ID)
 	} else {
+		// Route the updates of what the connection submits to its
+		// submissions stream, before it can see the response.
+		if hash := keptSubmission(cmd.Command, result); hash != "" {
+			ws.subscriptionManager.TrackSubmission(wsConn.ID, hash)
+		}
 		response := types.WebSocketResponse{
 			Type:       "response",
 			ID:         cmd.ID,
@@ -557,6 +790,26 @@ func (ws *WebSocketServer) handleRPCMethod(wsConn *WebSocketConnection, ctx *typ
 	}
 }
 
+// keptSubmission returns the hash of the transaction a submit or
+// submit_multisigned result reports as applied or kept, or "".
+func keptSubmission(command string, result interface{}) string {
+	if command != "submit" && command != "submit_multisigned" {
+		return ""
+	}
+	response, ok := result.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	applied, _ := response["applied"].(bool)
+	kept, _ := response["kept"].(bool)
+	if !applied && !kept {
+		return ""
+	}
+	txJSON, _ := response["tx_json"].(map[string]interface{})
+	hash, _ := txJSON["hash"].(string)
+	return hash
+}
+
 // WebSocketResponseOptions contains optional fields for WebSocket responses
 type WebSocketResponseOptions struct {
 	Warning   string                // "load" when approaching rate limit
@@ -590,9 +843,7 @@ func (ws *WebSocketServer) sendResponseWithOptions(wsConn *WebSocketConnection,
 	case <-wsConn.ctx.Done():
 		// Connection closed
 	default:
-		// Channel full, close connection
-		wsLog().Warn("WebSocket send channel full", "connID", wsConn.ID)
-		ws.closeConnection(wsConn)
+		wsConn.markSlow()
 	}
 }
 
@@ -632,9 +883,7 @@ func (ws *WebSocketServer) sendErrorWithOptions(wsConn *WebSocketConnection, rpc
 	case <-wsConn.ctx.Done():
 		// Connection closed
 	default:
-		// Channel full, close connection
-		wsLog().Warn("WebSocket send channel full", "connID", wsConn.ID)
-		ws.closeConnection(wsConn)
+		wsConn.markSlow()
 	}
 }
 
@@ -673,6 +922,8 @@ func (ws *WebSocketServer) BroadcastToSubscribers(msgType types.SubscriptionType
 		return
 	}
 
+	ws.prepared.add(data)
+
 	ws.connectionsMutex.RLock()
 	defer ws.connectionsMutex.RUnlock()
 
@@ -683,8 +934,7 @@ func (ws *WebSocketServer) BroadcastToSubscribers(msgType types.SubscriptionType
 			case conn.sendChannel <- data:
 				// Message sent
 			default:
-				// Channel full, skip this connection
-				wsLog().Debug("Skipping slow WebSocket connection", "connID", conn.ID)
+				conn.markSlow()
 			}
 		}
 		conn.mutex.RUnlock()
@@ -718,8 +968,11 @@ func (ws *WebSocketServer) GetSubscriptionManager() *subscription.Manager {
 	return ws.subscriptionManager
 }
 
-// Close gracefully closes all active WebSocket connections.
+// Close gracefully closes all active WebSocket connections and stops
+// URL subscription delivery.
 func (ws *WebSocketServer) Close() {
+	ws.subscriptionManager.Close()
+
 	ws.connectionsMutex.Lock()
 	defer ws.connectionsMutex.Unlock()
 	for _, conn := range ws.connections {
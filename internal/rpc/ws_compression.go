@@ -0,0 +1,133 @@
+package rpc
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/LeJamon/goXRPLd/config"
+	"github.com/gorilla/websocket"
+)
+
+// DefaultDeflateLevel is the flate level used when a port enables
+// permessage_deflate without compress_level, matching rippled's default.
+const DefaultDeflateLevel = 8
+
+// preparedCacheSize bounds the broadcast messages kept in compressed form.
+const preparedCacheSize = 256
+
+// acceptsDeflate reports whether one of the permessage-deflate offers in
+// a WebSocket upgrade request can be accepted (RFC 7692). The server side
+// always runs without context takeover and with a full 32 KiB window, so
+// an offer is declined only when it limits the server's window.
+func acceptsDeflate(header http.Header) bool {
+	for _, value := range header.Values("Sec-WebSocket-Extensions") {
+		for _, offer := range strings.Split(value, ",") {
+			params := strings.Split(offer, ";")
+			if strings.TrimSpace(params[0]) != "permessage-deflate" {
+				continue
+			}
+			acceptable := true
+			for _, param := range params[1:] {
+				name, bits, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if strings.TrimSpace(name) == "server_max_window_bits" && strings.Trim(strings.TrimSpace(bits), `"`) != "15" {
+					acceptable = false
+				}
+			}
+			if acceptable {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UnsupportedDeflateSettings returns the permessage-deflate settings of a
+// port that cannot be applied. Go's flate always uses a 32 KiB window and
+// has no memory level, and context is never taken over between messages,
+// which RFC 7692 allows whatever the port asks for.
+func UnsupportedDeflateSettings(p *config.PortConfig) []string {
+	var unsupported []string
+	if p.ServerMaxWindowBits != 0 && p.ServerMaxWindowBits != 15 {
+		unsupported = append(unsupported, "server_max_window_bits")
+	}
+	if p.ClientMaxWindowBits != 0 && p.ClientMaxWindowBits != 15 {
+		unsupported = append(unsupported, "client_max_window_bits")
+	}
+	if p.MemoryLevel != 0 {
+		unsupported = append(unsupported, "memory_level")
+	}
+	return unsupported
+}
+
+// preparedMessages shares the compressed form of broadcast messages
+// between connections. A broadcast queues the same byte slice on every
+// subscribed connection, so the slice identifies it; broadcasts are
+// registered with add before they are queued. Without context takeover a
+// message deflates the same on every connection, so it is compressed once
+// per compression level.
+type preparedMessages struct {
+	mu sync.Mutex
+	// messages holds the registered broadcasts, with their prepared
+	// form once a compressed connection has written them.
+	messages map[preparedKey]*websocket.PreparedMessage
+	ring     []preparedKey
+	next     int
+}
+
+// preparedKey identifies a message by its backing array, which the key
+// keeps alive, so the address cannot be reused while cached.
+type preparedKey struct {
+	data *byte
+	len  int
+}
+
+func newPreparedMessages(size int) *preparedMessages {
+	return &preparedMessages{
+		messages: make(map[preparedKey]*websocket.PreparedMessage, size),
+		ring:     make([]preparedKey, size),
+	}
+}
+
+// add registers data as a broadcast, evicting the oldest entry when the
+// cache is full.
+func (c *preparedMessages) add(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	key := preparedKey{data: &data[0], len: len(data)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.messages[key]; ok {
+		return
+	}
+	if old := c.ring[c.next]; old.data != nil {
+		delete(c.messages, old)
+	}
+	c.ring[c.next] = key
+	c.next = (c.next + 1) % len(c.ring)
+	c.messages[key] = nil
+}
+
+// get returns the prepared form of a registered broadcast, creating it on
+// first use. ok is false if data is not a registered broadcast.
+func (c *preparedMessages) get(data []byte) (pm *websocket.PreparedMessage, ok bool, err error) {
+	if len(data) == 0 {
+		return nil, false, nil
+	}
+	key := preparedKey{data: &data[0], len: len(data)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pm, ok = c.messages[key]
+	if !ok || pm != nil {
+		return pm, ok, nil
+	}
+	pm, err = websocket.NewPreparedMessage(websocket.TextMessage, data)
+	if err != nil {
+		return nil, false, err
+	}
+	c.messages[key] = pm
+	return pm, true, nil
+}
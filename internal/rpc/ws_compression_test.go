@@ -0,0 +1,208 @@
+package rpc
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LeJamon/goXRPLd/config"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// permessage-deflate tests (RFC 7692), with a local gorilla client.
+
+// countingConn counts the bytes read from the server.
+type countingConn struct {
+	net.Conn
+	read *atomic.Int64
+}
+
+func (c countingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.read.Add(int64(n))
+	return n, err
+}
+
+// dialDeflate connects to ws on a port with pc, offering compression, and
+// returns the client, the negotiated extensions and a counter of the bytes
+// received.
+func dialDeflate(t *testing.T, ws *WebSocketServer, pc *PortContext) (*websocket.Conn, string, *atomic.Int64) {
+	t.Helper()
+	srv := httptest.NewServer(PortMiddleware(pc, nil, ws))
+	t.Cleanup(srv.Close)
+
+	read := &atomic.Int64{}
+	dialer := websocket.Dialer{
+		EnableCompression: true,
+		NetDial: func(network, addr string) (net.Conn, error) {
+			conn, err := net.Dial(network, addr)
+			return countingConn{Conn: conn, read: read}, err
+		},
+	}
+	client, resp, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client, resp.Header.Get("Sec-WebSocket-Extensions"), read
+}
+
+func subscribeLedgerStream(t *testing.T, client *websocket.Conn) {
+	t.Helper()
+	require.NoError(t, client.WriteJSON(map[string]interface{}{
+		"command": "subscribe", "id": 1, "streams": []string{"ledger"},
+	}))
+	assert.Equal(t, "success", readTestMessage(t, client)["status"])
+}
+
+// largeLedgerEvent is a broadcast that compresses well.
+func largeLedgerEvent() *LedgerCloseEvent {
+	return &LedgerCloseEvent{
+		Type:             "ledgerClosed",
+		LedgerIndex:      7,
+		ValidatedLedgers: strings.Repeat("1-100,", 5000),
+	}
+}
+
+func TestWebSocketDeflateNegotiation(t *testing.T) {
+	tests := []struct {
+		name       string
+		pc         *PortContext
+		extensions string
+		want       bool
+	}{
+		{"enabled", &PortContext{PortName: "ws", Deflate: true}, "permessage-deflate; client_max_window_bits", true},
+		{"disabled on port", &PortContext{PortName: "ws"}, "permessage-deflate", false},
+		{"full server window", &PortContext{PortName: "ws", Deflate: true},
+			"permessage-deflate; server_max_window_bits=15; client_max_window_bits", true},
+		{"small server window", &PortContext{PortName: "ws", Deflate: true},
+			"permessage-deflate; server_max_window_bits=10", false},
+		{"second offer acceptable", &PortContext{PortName: "ws", Deflate: true},
+			"permessage-deflate; server_max_window_bits=10, permessage-deflate", true},
+		{"not offered", &PortContext{PortName: "ws", Deflate: true}, "x-webkit-deflate-frame", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(PortMiddleware(tt.pc, nil, NewWebSocketServer(time.Second)))
+			defer srv.Close()
+
+			// A raw upgrade, as the gorilla client will not send offers
+			// of its own choosing.
+			req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+			require.NoError(t, err)
+			req.Header.Set("Connection", "Upgrade")
+			req.Header.Set("Upgrade", "websocket")
+			req.Header.Set("Sec-WebSocket-Version", "13")
+			req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+			req.Header.Set("Sec-WebSocket-Extensions", tt.extensions)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+			negotiated := resp.Header.Get("Sec-WebSocket-Extensions")
+			assert.Equal(t, tt.want, strings.HasPrefix(negotiated, "permessage-deflate"), negotiated)
+		})
+	}
+}
+
+func TestWebSocketDeflateCompressesStreams(t *testing.T) {
+	event := largeLedgerEvent()
+	size := len(event.ValidatedLedgers)
+
+	received := func(pc *PortContext) int64 {
+		ws := NewWebSocketServer(time.Second)
+		client, _, read := dialDeflate(t, ws, pc)
+		subscribeLedgerStream(t, client)
+
+		before := read.Load()
+		NewPublisher(ws.GetSubscriptionManager()).PublishLedgerClosed(event)
+		msg := readTestMessage(t, client)
+		assert.Equal(t, event.ValidatedLedgers, msg["validated_ledgers"])
+		return read.Load() - before
+	}
+
+	plain := received(&PortContext{PortName: "ws"})
+	compressed := received(&PortContext{PortName: "ws", Deflate: true, DeflateLevel: 9})
+	assert.Greater(t, plain, int64(size))
+	assert.Less(t, compressed*10, plain, "expected at least 10x smaller, got %d vs %d bytes", compressed, plain)
+}
+
+func TestWebSocketDeflateSharesBroadcasts(t *testing.T) {
+	ws := NewWebSocketServer(time.Second)
+	pc := &PortContext{PortName: "ws", Deflate: true}
+	clients := make([]*websocket.Conn, 3)
+	for i := range clients {
+		clients[i], _, _ = dialDeflate(t, ws, pc)
+		subscribeLedgerStream(t, clients[i])
+	}
+
+	ws.prepared.mu.Lock()
+	entries := len(ws.prepared.messages)
+	ws.prepared.mu.Unlock()
+	NewPublisher(ws.GetSubscriptionManager()).PublishLedgerClosed(largeLedgerEvent())
+	for _, client := range clients {
+		assert.Equal(t, "ledgerClosed", readTestMessage(t, client)["type"])
+	}
+
+	ws.prepared.mu.Lock()
+	defer ws.prepared.mu.Unlock()
+	assert.Equal(t, entries+1, len(ws.prepared.messages), "one prepared message for all connections")
+}
+
+func TestPreparedMessagesCache(t *testing.T) {
+	cache := newPreparedMessages(2)
+	a := []byte(`{"a":1}`)
+
+	// Only registered broadcasts are prepared.
+	_, ok, err := cache.get(a)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	cache.add(a)
+	pa, ok, err := cache.get(a)
+	require.NoError(t, err)
+	require.True(t, ok)
+	again, _, err := cache.get(a)
+	require.NoError(t, err)
+	assert.Same(t, pa, again)
+
+	// Equal content in another slice is another message.
+	_, ok, err = cache.get(bytes.Clone(a))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// A third broadcast evicts the oldest.
+	cache.add([]byte(`{"b":2}`))
+	cache.add([]byte(`{"c":3}`))
+	assert.Len(t, cache.messages, 2)
+	_, ok, err = cache.get(a)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+// TestWebSocketDeflateResponsesNotShared checks that responses, written
+// to a single connection, stay out of the broadcast cache.
+func TestWebSocketDeflateResponsesNotShared(t *testing.T) {
+	ws := NewWebSocketServer(time.Second)
+	client, _, _ := dialDeflate(t, ws, &PortContext{PortName: "ws", Deflate: true})
+	subscribeLedgerStream(t, client)
+
+	ws.prepared.mu.Lock()
+	defer ws.prepared.mu.Unlock()
+	assert.Empty(t, ws.prepared.messages)
+}
+
+func TestUnsupportedDeflateSettings(t *testing.T) {
+	assert.Empty(t, UnsupportedDeflateSettings(&config.PortConfig{
+		PermessageDeflate: true, ServerMaxWindowBits: 15, ClientMaxWindowBits: 15, CompressLevel: 6,
+	}))
+	assert.Equal(t, []string{"server_max_window_bits", "memory_level"}, UnsupportedDeflateSettings(&config.PortConfig{
+		PermessageDeflate: true, ServerMaxWindowBits: 10, MemoryLevel: 4,
+	}))
+}
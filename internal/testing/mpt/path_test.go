@@ -0,0 +1,73 @@
+package mpt_test
+
+import (
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/internal/ledger/state"
+	jtx "github.com/LeJamon/goXRPLd/internal/testing"
+	"github.com/LeJamon/goXRPLd/internal/testing/mpt"
+	"github.com/LeJamon/goXRPLd/internal/tx/payment/pathfinder"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMPT_PathFind checks that path finding to an MPT destination amount
+// returns the direct transfer as a single alternative with no paths, its
+// source amount carrying the holder-to-holder transfer fee, and nothing
+// when the transfer could not succeed.
+func TestMPT_PathFind(t *testing.T) {
+	env := jtx.NewTestEnv(t)
+	alice := jtx.NewAccount("alice")
+	bob := jtx.NewAccount("bob")
+	carol := jtx.NewAccount("carol")
+	dan := jtx.NewAccount("dan")
+	env.Fund(alice)
+	env.Fund(bob)
+	env.Fund(carol)
+	env.Fund(dan)
+
+	mptAlice := mpt.NewMPTTester(t, env, alice, mpt.MPTInit{Holders: []*jtx.Account{bob, carol}})
+	transferFee := uint16(10_000) // 10%
+	mptAlice.Create(mpt.CreateOpts{
+		TransferFee: &transferFee,
+		Flags:       mpt.TfMPTCanTransfer,
+	})
+	mptAlice.Authorize(mpt.AuthorizeOpts{Account: bob})
+	mptAlice.Authorize(mpt.AuthorizeOpts{Account: carol})
+	mptAlice.Pay(alice, bob, 1_000)
+
+	amount := func(v int64) state.Amount {
+		return state.NewMPTAmountWithIssuanceID(v, alice.Address, mptAlice.IssuanceID())
+	}
+	find := func(src, dst *jtx.Account, deliver int64) *pathfinder.PathRequestResult {
+		pr := pathfinder.NewPathRequest(src.ID, dst.ID, amount(deliver), nil, nil, false)
+		return pr.Execute(env.Ledger())
+	}
+
+	t.Run("HolderToHolder", func(t *testing.T) {
+		res := find(bob, carol, 100)
+		require.Len(t, res.Alternatives, 1)
+		alt := res.Alternatives[0]
+		require.Empty(t, alt.PathsComputed)
+		require.True(t, alt.SourceAmount.IsMPT())
+		require.Equal(t, mptAlice.IssuanceID(), alt.SourceAmount.MPTIssuanceID())
+		raw, ok := alt.SourceAmount.MPTRaw()
+		require.True(t, ok)
+		require.Equal(t, int64(110), raw, "100 plus the 10% transfer fee")
+
+		// The alternative is a payment that succeeds
+		mptAlice.PayWithSendMax(bob, carol, 100, raw)
+		mptAlice.RequireMPTokenAmount(carol, 100)
+	})
+
+	t.Run("IssuerPaysNoFee", func(t *testing.T) {
+		res := find(alice, carol, 50)
+		require.Len(t, res.Alternatives, 1)
+		raw, _ := res.Alternatives[0].SourceAmount.MPTRaw()
+		require.Equal(t, int64(50), raw)
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		require.Empty(t, find(bob, carol, 1_000).Alternatives, "balance does not cover the fee")
+		require.Empty(t, find(bob, dan, 10).Alternatives, "destination holds no MPToken")
+	})
+}
@@ -3,11 +3,13 @@
 package payment
 
 import (
+	"strconv"
 	"testing"
 
 	xrplgoTesting "github.com/LeJamon/goXRPLd/internal/testing"
 	"github.com/LeJamon/goXRPLd/internal/testing/trustset"
 	"github.com/LeJamon/goXRPLd/internal/tx"
+	"github.com/LeJamon/goXRPLd/internal/tx/amm"
 	"github.com/LeJamon/goXRPLd/internal/tx/payment"
 	"github.com/LeJamon/goXRPLd/internal/tx/payment/pathfinder"
 	"github.com/stretchr/testify/require"
@@ -1337,12 +1339,57 @@ func TestPath_HybridOfferPath(t *testing.T) {
 
 // TestPath_AMMDomainPath tests AMM path finding with domain.
 // From rippled: Path_test::amm_domain_path
+// AMM pools are books on the open DEX only: an XRP/USD pool with no CLOB
+// offers yields a path without a domain and none within a domain.
 func TestPath_AMMDomainPath(t *testing.T) {
-	t.Skip("TODO: Requires AMM support")
+	env := xrplgoTesting.NewTestEnv(t)
+
+	gw := xrplgoTesting.NewAccount("gateway")
+	alice := xrplgoTesting.NewAccount("alice")
+	bob := xrplgoTesting.NewAccount("bob")
+
+	env.FundAmount(gw, uint64(xrplgoTesting.XRP(10000)))
+	env.FundAmount(alice, uint64(xrplgoTesting.XRP(10000)))
+	env.FundAmount(bob, uint64(xrplgoTesting.XRP(10000)))
+	env.Close()
+
+	result := env.Submit(trustset.TrustLine(bob, "USD", gw, "1000").Build())
+	xrplgoTesting.RequireTxSuccess(t, result)
+	env.Close()
+
+	// gw creates an XRP(100)/USD(100) pool; there are no offers
+	createTx := amm.NewAMMCreate(gw.Address,
+		tx.NewXRPAmount(xrplgoTesting.XRP(100)),
+		tx.NewIssuedAmountFromFloat64(100, "USD", gw.Address), 0)
+	createTx.Fee = strconv.FormatUint(env.ReserveIncrement(), 10)
+	result = env.Submit(createTx)
+	xrplgoTesting.RequireTxSuccess(t, result)
+	env.Close()
+
+	usd5 := tx.NewIssuedAmountFromFloat64(5, "USD", gw.Address)
+	srcCurrencies := []payment.Issue{{Currency: "XRP"}}
+
+	pr := pathfinder.NewPathRequest(alice.ID, bob.ID, usd5, nil, srcCurrencies, false)
+	pfResult := pr.Execute(env.Ledger())
+	require.NotEmpty(t, pfResult.Alternatives, "AMM should be included in non-domain path finding")
+	alt := pfResult.Alternatives[0]
+	require.True(t, alt.SourceAmount.IsNative(), "source amount should be XRP")
+	require.Positive(t, alt.SourceAmount.Drops())
+
+	// Pay with the discovered paths; the pool supplies the USD
+	payTx := PayIssued(alice, bob, usd5).
+		SendMax(tx.NewXRPAmount(xrplgoTesting.XRP(10))).
+		Paths(alt.PathsComputed).
+		Build()
+	result = env.Submit(payTx)
+	xrplgoTesting.RequireTxSuccess(t, result)
+	env.Close()
 
-	// AMM should NOT be included in domain path finding
-	// AMM should be included in non-domain path finding
-	t.Log("AMM domain path test: requires AMM support")
+	domain := [32]byte{0xD0}
+	pr = pathfinder.NewPathRequest(alice.ID, bob.ID, usd5, nil, srcCurrencies, false)
+	pr.SetDomain(&domain)
+	pfResult = pr.Execute(env.Ledger())
+	require.Empty(t, pfResult.Alternatives, "AMM should not be included in domain path finding")
 }
 
 // =============================================================================
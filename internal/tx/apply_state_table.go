@@ -2,12 +2,12 @@ package tx
 
 import (
 	"bytes"
-	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"strings"
 
 	"github.com/LeJamon/goXRPLd/amendment"
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 	"github.com/LeJamon/goXRPLd/drops"
 	"github.com/LeJamon/goXRPLd/internal/ledger/state"
 	"github.com/LeJamon/goXRPLd/internal/tx/invariants"
@@ -33,6 +33,56 @@ type TrackedEntry struct {
 	Action   Action
 	Original []byte // Original state (nil for inserts)
 	Current  []byte // Current state (nil for deletes after erase)
+
+	// orig and cur cache the decoded Original and Current. cur may hold
+	// threading changes not yet serialized back into Current.
+	orig *SLE
+	cur  *SLE
+}
+
+// originalSLE returns Original decoded, parsing it on first use. A nil
+// Original decodes to an entry without fields.
+func (e *TrackedEntry) originalSLE() *SLE {
+	if e.orig == nil {
+		e.orig = parseSLEOrEmpty(e.Original)
+	}
+	return e.orig
+}
+
+// currentSLE returns Current decoded, parsing it on first use. An entry
+// that has only been read shares its parse with Original.
+func (e *TrackedEntry) currentSLE() *SLE {
+	if e.cur == nil {
+		if e.orig != nil && sameSlice(e.Current, e.Original) {
+			e.cur = e.orig.Clone()
+		} else {
+			e.cur = parseSLEOrEmpty(e.Current)
+		}
+	}
+	return e.cur
+}
+
+// setCurrent replaces Current and drops its decoded form.
+func (e *TrackedEntry) setCurrent(data []byte) {
+	e.Current = data
+	e.cur = nil
+}
+
+// entryType returns the ledger entry type of the tracked entry, preferring
+// the state before the transaction.
+func (e *TrackedEntry) entryType() string {
+	if e.Original != nil {
+		return e.originalSLE().EntryType()
+	}
+	if e.Current != nil {
+		return e.currentSLE().EntryType()
+	}
+	return "Unknown"
+}
+
+// sameSlice reports whether a and b are the same slice of the same array.
+func sameSlice(a, b []byte) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
 }
 
 // ApplyStateTable wraps a LedgerView and tracks all modifications
@@ -107,7 +157,7 @@ func (t *ApplyStateTable) Insert(k keylet.Keylet, data []byte) error {
 		}
 		// Re-inserting a deleted entry becomes a modify
 		entry.Action = ActionModify
-		entry.Current = data
+		entry.setCurrent(data)
 		return nil
 	}
 
@@ -141,7 +191,7 @@ func (t *ApplyStateTable) Update(k keylet.Keylet, data []byte) error {
 			entry.Action = ActionModify
 		}
 		// For insert, keep it as insert with new data
-		entry.Current = data
+		entry.setCurrent(data)
 		return nil
 	}
 
@@ -327,6 +377,13 @@ func (t *ApplyStateTable) applyImpl(isDryRun bool) (*Metadata, error) {
 	// This updates PreviousTxnID/PreviousTxnLgrSeq on entries and their owners
 	t.applyThreading()
 
+	// Serialize each entry changed by threading exactly once.
+	for _, entry := range t.items {
+		if entry.cur != nil && entry.cur.IsModified() {
+			entry.Current = entry.cur.Bytes()
+		}
+	}
+
 	// Phase 2: Generate metadata and optionally apply to base
 	metadata := &Metadata{
 		AffectedNodes: make([]AffectedNode, 0),
@@ -339,7 +396,7 @@ func (t *ApplyStateTable) applyImpl(isDryRun bool) (*Metadata, error) {
 			continue
 
 		case ActionInsert:
-			node, err := t.buildCreatedNode(key, entry.Current)
+			node, err := t.buildCreatedNode(key, entry.currentSLE())
 			if err != nil {
 				return nil, err
 			}
@@ -357,7 +414,7 @@ func (t *ApplyStateTable) applyImpl(isDryRun bool) (*Metadata, error) {
 				continue
 			}
 
-			node, err := t.buildModifiedNode(key, entry.Original, entry.Current)
+			node, err := t.buildModifiedNode(key, entry.originalSLE(), entry.currentSLE())
 			if err != nil {
 				return nil, err
 			}
@@ -370,7 +427,7 @@ func (t *ApplyStateTable) applyImpl(isDryRun bool) (*Metadata, error) {
 			}
 
 		case ActionErase:
-			node, err := t.buildDeletedNode(key, entry.Original, entry.Current)
+			node, err := t.buildDeletedNode(key, entry.originalSLE(), entry.currentSLE())
 			if err != nil {
 				return nil, err
 			}
@@ -421,67 +478,51 @@ func (t *ApplyStateTable) applyThreading() {
 	fixCheckThreading := t.effectiveRules().Enabled(amendment.FeatureFixCheckThreading)
 
 	for _, w := range work {
-		entryType := getLedgerEntryType(w.entry.Current)
-		if w.entry.Current == nil && w.entry.Original != nil {
-			entryType = getLedgerEntryType(w.entry.Original)
+		sle := w.entry.currentSLE()
+		if w.entry.Current == nil {
+			sle = w.entry.originalSLE()
 		}
+		entryType := sle.EntryType()
 
 		switch w.entry.Action {
 		case ActionInsert:
 			// Thread the created entry itself (set PreviousTxnID/PreviousTxnLgrSeq)
 			if isThreadedType(entryType, fixPreviousTxnID) {
-				_, _, newData, changed := threadItem(w.entry.Current, t.txHash, t.txSeq)
-				if changed {
-					w.entry.Current = newData
-				}
+				threadItem(sle, t.txHash, t.txSeq)
 			}
 
 			// Thread owner accounts
-			t.threadOwners(w.entry.Current, entryType, fixCheckThreading)
+			t.threadOwners(sle, fixCheckThreading)
 
 		case ActionModify:
 			// Thread the modified entry itself
 			if isThreadedType(entryType, fixPreviousTxnID) {
-				_, _, newData, changed := threadItem(w.entry.Current, t.txHash, t.txSeq)
-				if changed {
-					w.entry.Current = newData
-				}
+				threadItem(sle, t.txHash, t.txSeq)
 			}
 
 		case ActionErase:
 			// Thread owner accounts (the entry itself is being deleted)
-			data := w.entry.Current
-			if data == nil {
-				data = w.entry.Original
-			}
-			t.threadOwners(data, entryType, fixCheckThreading)
+			t.threadOwners(sle, fixCheckThreading)
 		}
 	}
 }
 
 // threadOwners updates PreviousTxnID/PreviousTxnLgrSeq on owner accounts
 // of a given ledger entry. fixCheckThreading gates Check→Destination threading.
-func (t *ApplyStateTable) threadOwners(data []byte, entryType string, fixCheckThreading bool) {
-	if data == nil {
-		return
-	}
-
-	owners := getOwnerAccounts(data, entryType, fixCheckThreading)
+func (t *ApplyStateTable) threadOwners(sle *SLE, fixCheckThreading bool) {
+	owners := getOwnerAccounts(sle, fixCheckThreading)
 	for _, ownerID := range owners {
 		ownerKey := keylet.Account(ownerID)
 
 		// Check if already tracked
 		if entry, exists := t.items[ownerKey.Key]; exists {
-			if entry.Action == ActionErase {
+			if entry.Action == ActionErase || entry.Current == nil {
 				continue // Don't thread deleted accounts
 			}
 			// Thread the existing tracked entry
-			_, _, newData, changed := threadItem(entry.Current, t.txHash, t.txSeq)
-			if changed {
-				entry.Current = newData
-				if entry.Action == ActionCache {
-					entry.Action = ActionModify
-				}
+			_, _, changed := threadItem(entry.currentSLE(), t.txHash, t.txSeq)
+			if changed && entry.Action == ActionCache {
+				entry.Action = ActionModify
 			}
 		} else {
 			// Read from base and add to tracking
@@ -489,13 +530,13 @@ func (t *ApplyStateTable) threadOwners(data []byte, entryType string, fixCheckTh
 			if err != nil || ownerData == nil {
 				continue // Owner doesn't exist, skip
 			}
-			_, _, newData, changed := threadItem(ownerData, t.txHash, t.txSeq)
-			if changed {
-				t.items[ownerKey.Key] = &TrackedEntry{
-					Action:   ActionModify,
-					Original: ownerData,
-					Current:  newData,
-				}
+			entry := &TrackedEntry{
+				Action:   ActionModify,
+				Original: ownerData,
+				Current:  ownerData,
+			}
+			if _, _, changed := threadItem(entry.currentSLE(), t.txHash, t.txSeq); changed {
+				t.items[ownerKey.Key] = entry
 			}
 		}
 	}
@@ -542,17 +583,26 @@ func (t *ApplyStateTable) CollectEntries() []invariants.InvariantEntry {
 			}
 			after = nil
 		}
-		typeData := after
-		if typeData == nil {
-			typeData = before
+		ie := invariants.InvariantEntry{
+			Key:      key,
+			IsDelete: entry.Action == ActionErase,
+			Before:   before,
+			After:    after,
+		}
+		if before != nil {
+			if sameSlice(before, entry.Original) {
+				ie.BeforeSLE = entry.originalSLE()
+			} else {
+				ie.BeforeSLE = entry.currentSLE()
+			}
+		}
+		if after != nil {
+			ie.AfterSLE = entry.currentSLE()
+			ie.EntryType = entry.currentSLE().EntryType()
+		} else {
+			ie.EntryType = entry.entryType()
 		}
-		entries = append(entries, invariants.InvariantEntry{
-			Key:       key,
-			IsDelete:  entry.Action == ActionErase,
-			Before:    before,
-			After:     after,
-			EntryType: getLedgerEntryType(typeData),
-		})
+		entries = append(entries, ie)
 	}
 	return entries
 }
@@ -579,26 +629,26 @@ func (t *ApplyStateTable) GetItems() map[[32]byte]*TrackedEntry {
 }
 
 // buildCreatedNode creates metadata for a newly created entry
-func (t *ApplyStateTable) buildCreatedNode(key [32]byte, data []byte) (AffectedNode, error) {
-	entryType := getLedgerEntryType(data)
-
+func (t *ApplyStateTable) buildCreatedNode(key [32]byte, sle *SLE) (AffectedNode, error) {
 	node := AffectedNode{
 		NodeType:        "CreatedNode",
-		LedgerEntryType: entryType,
+		LedgerEntryType: sle.EntryType(),
 		LedgerIndex:     strings.ToUpper(hex.EncodeToString(key[:])),
 		NewFields:       make(map[string]any),
 	}
 
-	// Extract fields for NewFields based on entry type
-	fields, err := extractLedgerFields(data, entryType)
-	if err != nil {
-		return node, err
-	}
-
 	// For CreatedNode, include all non-default fields with sMD_Create | sMD_Always
-	for name, value := range fields {
-		if shouldIncludeInCreate(name) && !state.IsDefaultValue(value) {
-			node.NewFields[name] = value
+	for i := range sle.fields {
+		f := &sle.fields[i]
+		if !shouldIncludeInCreate(f.field.Name) {
+			continue
+		}
+		value, err := sle.fieldJSON(f)
+		if err != nil {
+			continue
+		}
+		if !state.IsDefaultValue(value) {
+			node.NewFields[f.field.Name] = value
 		}
 	}
 
@@ -606,56 +656,28 @@ func (t *ApplyStateTable) buildCreatedNode(key [32]byte, data []byte) (AffectedN
 }
 
 // buildModifiedNode creates metadata for a modified entry
-func (t *ApplyStateTable) buildModifiedNode(key [32]byte, original, current []byte) (AffectedNode, error) {
-	entryType := getLedgerEntryType(current)
-
+func (t *ApplyStateTable) buildModifiedNode(key [32]byte, original, current *SLE) (AffectedNode, error) {
 	node := AffectedNode{
 		NodeType:        "ModifiedNode",
-		LedgerEntryType: entryType,
+		LedgerEntryType: current.EntryType(),
 		LedgerIndex:     strings.ToUpper(hex.EncodeToString(key[:])),
 		FinalFields:     make(map[string]any),
 		PreviousFields:  make(map[string]any),
 	}
 
-	// Extract fields from original and current
-	origFields, err := extractLedgerFields(original, entryType)
-	if err != nil {
-		return node, err
-	}
-
-	currFields, err := extractLedgerFields(current, entryType)
-	if err != nil {
-		return node, err
-	}
-
-	// Extract PreviousTxnID and PreviousTxnLgrSeq from original
-	if prevTxnID, ok := origFields["PreviousTxnID"]; ok {
-		node.PreviousTxnID = fmt.Sprintf("%v", prevTxnID)
-	}
-	if prevTxnLgrSeq, ok := origFields["PreviousTxnLgrSeq"]; ok {
-		if seq, ok := prevTxnLgrSeq.(uint32); ok {
-			node.PreviousTxnLgrSeq = seq
-		}
-	}
+	// PreviousTxnID and PreviousTxnLgrSeq come from the original
+	setPreviousTxn(&node, original)
 
 	// PreviousFields: fields that changed (sMD_ChangeOrig)
-	for name, origValue := range origFields {
-		if shouldIncludeInPreviousFields(name) {
-			if currValue, exists := currFields[name]; exists {
-				if !fieldsEqual(origValue, currValue) {
-					node.PreviousFields[name] = origValue
-				}
-			} else {
-				// Field was removed
-				node.PreviousFields[name] = origValue
-			}
-		}
-	}
+	addPreviousFields(node.PreviousFields, original, current)
 
 	// FinalFields: fields with sMD_Always | sMD_ChangeNew
-	for name, currValue := range currFields {
-		if shouldIncludeInFinalFields(name) {
-			node.FinalFields[name] = currValue
+	for i := range current.fields {
+		f := &current.fields[i]
+		if shouldIncludeInFinalFields(f.field.Name) {
+			if value, err := current.fieldJSON(f); err == nil {
+				node.FinalFields[f.field.Name] = value
+			}
 		}
 	}
 
@@ -672,62 +694,30 @@ func (t *ApplyStateTable) buildModifiedNode(key [32]byte, original, current []by
 
 // buildDeletedNode creates metadata for a deleted entry
 // original = state when first read, current = state just before deletion
-func (t *ApplyStateTable) buildDeletedNode(key [32]byte, original, current []byte) (AffectedNode, error) {
-	// Use current for entry type (it's the state just before deletion)
-	entryType := getLedgerEntryType(current)
-
+func (t *ApplyStateTable) buildDeletedNode(key [32]byte, original, current *SLE) (AffectedNode, error) {
 	node := AffectedNode{
-		NodeType:        "DeletedNode",
-		LedgerEntryType: entryType,
+		NodeType: "DeletedNode",
+		// Use current for entry type (it's the state just before deletion)
+		LedgerEntryType: current.EntryType(),
 		LedgerIndex:     strings.ToUpper(hex.EncodeToString(key[:])),
 		FinalFields:     make(map[string]any),
 		PreviousFields:  make(map[string]any),
 	}
 
-	// Extract fields from both original and current
-	origFields, err := extractLedgerFields(original, entryType)
-	if err != nil {
-		return node, err
-	}
-
-	currFields, err := extractLedgerFields(current, entryType)
-	if err != nil {
-		return node, err
-	}
-
-	// Extract PreviousTxnID and PreviousTxnLgrSeq from original
-	if prevTxnID, ok := origFields["PreviousTxnID"]; ok {
-		node.PreviousTxnID = fmt.Sprintf("%v", prevTxnID)
-	}
-	if prevTxnLgrSeq, ok := origFields["PreviousTxnLgrSeq"]; ok {
-		if seq, ok := prevTxnLgrSeq.(uint32); ok {
-			node.PreviousTxnLgrSeq = seq
-		} else if seq, ok := prevTxnLgrSeq.(float64); ok {
-			node.PreviousTxnLgrSeq = uint32(seq)
-		} else if seq, ok := prevTxnLgrSeq.(int); ok {
-			node.PreviousTxnLgrSeq = uint32(seq)
-		}
-	}
+	// PreviousTxnID and PreviousTxnLgrSeq come from the original
+	setPreviousTxn(&node, original)
 
 	// PreviousFields: fields that changed between original and current (sMD_ChangeOrig)
 	// This captures any modifications made before deletion
-	for name, origValue := range origFields {
-		if shouldIncludeInPreviousFields(name) {
-			if currValue, exists := currFields[name]; exists {
-				if !fieldsEqual(origValue, currValue) {
-					node.PreviousFields[name] = origValue
-				}
-			} else {
-				// Field was removed before deletion
-				node.PreviousFields[name] = origValue
-			}
-		}
-	}
+	addPreviousFields(node.PreviousFields, original, current)
 
 	// FinalFields: fields from current state with sMD_Always | sMD_DeleteFinal
-	for name, value := range currFields {
-		if shouldIncludeInDeleteFinal(name) {
-			node.FinalFields[name] = value
+	for i := range current.fields {
+		f := &current.fields[i]
+		if shouldIncludeInDeleteFinal(f.field.Name) {
+			if value, err := current.fieldJSON(f); err == nil {
+				node.FinalFields[f.field.Name] = value
+			}
 		}
 	}
 
@@ -742,122 +732,43 @@ func (t *ApplyStateTable) buildDeletedNode(key [32]byte, original, current []byt
 	return node, nil
 }
 
-// getLedgerEntryType extracts the ledger entry type from binary data
-func getLedgerEntryType(data []byte) string {
-	if len(data) < 4 {
-		return "Unknown"
+// setPreviousTxn copies the threading fields of the original entry onto the
+// affected node.
+func setPreviousTxn(node *AffectedNode, original *SLE) {
+	if prevTxnID, ok := original.Hash256(st.SFPreviousTxnID); ok {
+		node.PreviousTxnID = hashString(prevTxnID)
 	}
+	if seq, ok := original.UInt32(st.SFPreviousTxnLgrSeq); ok {
+		node.PreviousTxnLgrSeq = seq
+	}
+}
 
-	// Parse the binary to find LedgerEntryType field
-	// LedgerEntryType is a UInt16 with type code 1 and field code 1
-	// Header byte: 0x11 (type 1, field 1)
-	offset := 0
-	for offset < len(data)-2 {
-		header := data[offset]
-		offset++
-
-		typeCode := (header >> 4) & 0x0F
-		fieldCode := header & 0x0F
-
-		if typeCode == 0 {
-			if offset >= len(data) {
-				break
-			}
-			typeCode = data[offset]
-			offset++
+// addPreviousFields records the original value of every sMD_ChangeOrig
+// field that was changed or removed. Fields are compared in their binary
+// encoding and only the ones reported are decoded.
+func addPreviousFields(prev map[string]any, original, current *SLE) {
+	for i := range original.fields {
+		f := &original.fields[i]
+		name := f.field.Name
+		if !shouldIncludeInPreviousFields(name) || original.fieldEqual(current, f.field) {
+			continue
 		}
-
-		if fieldCode == 0 {
-			if offset >= len(data) {
-				break
-			}
-			fieldCode = data[offset]
-			offset++
+		if value, err := original.fieldJSON(f); err == nil {
+			prev[name] = value
 		}
+	}
+}
 
-		// Check for LedgerEntryType (type 1 = UInt16, field 1)
-		if typeCode == 1 && fieldCode == 1 {
-			if offset+2 > len(data) {
-				break
-			}
-			entryType := binary.BigEndian.Uint16(data[offset : offset+2])
-			return ledgerEntryTypeName(entryType)
-		}
-
-		// Skip field value based on type
-		switch typeCode {
-		case 1: // UInt16
-			offset += 2
-		case 2: // UInt32
-			offset += 4
-		case 3: // UInt64
-			offset += 8
-		case 4: // Hash128
-			offset += 16
-		case 5: // Hash256
-			offset += 32
-		case 6: // Amount
-			if offset >= len(data) {
-				return "Unknown"
-			}
-			if (data[offset] & 0x80) != 0 {
-				offset += 48 // IOU
-			} else if (data[offset] & 0x20) != 0 {
-				offset += 33 // MPT (1 header + 8 value + 24 issuance ID)
-			} else {
-				offset += 8 // XRP
-			}
-		case 7: // Blob (variable length)
-			if offset >= len(data) {
-				return "Unknown"
-			}
-			length := int(data[offset])
-			offset++
-			if length > 240 {
-				// 3-byte range (241-254): length 12481-918744
-				if offset+1 >= len(data) {
-					return "Unknown"
-				}
-				length = 12481 + ((length-241)<<16 | int(data[offset])<<8 | int(data[offset+1]))
-				offset += 2
-			} else if length > 192 {
-				// 2-byte range (193-240): length 193-12480
-				if offset >= len(data) {
-					return "Unknown"
-				}
-				length = 193 + ((length-193)<<8 | int(data[offset]))
-				offset++
-			}
-			offset += length
-		case 8: // AccountID (variable-length encoded with length prefix)
-			// Reference: rippled STAccount.cpp uses addVL() — 1-byte length prefix + data
-			if offset >= len(data) {
-				return "Unknown"
-			}
-			length := int(data[offset])
-			offset++
-			if length > 240 {
-				// 3-byte range (241-254): length 12481-918744
-				if offset+1 >= len(data) {
-					return "Unknown"
-				}
-				length = 12481 + ((length-241)<<16 | int(data[offset])<<8 | int(data[offset+1]))
-				offset += 2
-			} else if length > 192 {
-				// 2-byte range (193-240): length 193-12480
-				if offset >= len(data) {
-					return "Unknown"
-				}
-				length = 193 + ((length-193)<<8 | int(data[offset]))
-				offset++
-			}
-			offset += length
-		default:
-			// Unknown type, can't continue
-			return "Unknown"
+// getLedgerEntryType returns the type name of a serialized ledger entry,
+// reading fields up to LedgerEntryType, or "Unknown". It also names entries
+// that ParseSLE rejects further on.
+func getLedgerEntryType(data []byte) string {
+	r := st.NewReader(data)
+	for r.Next() {
+		if r.Field() == st.SFLedgerEntryType {
+			return ledgerEntryTypeName(r.UInt16())
 		}
 	}
-
 	return "Unknown"
 }
 
@@ -931,25 +842,4 @@ func ledgerEntryTypeName(code uint16) string {
 	}
 }
 
-// fieldsEqual compares two field values
-func fieldsEqual(a, b any) bool {
-	// For maps (like Amount), compare recursively
-	aMap, aIsMap := a.(map[string]any)
-	bMap, bIsMap := b.(map[string]any)
-	if aIsMap && bIsMap {
-		if len(aMap) != len(bMap) {
-			return false
-		}
-		for k, v := range aMap {
-			if bv, ok := bMap[k]; !ok || !fieldsEqual(v, bv) {
-				return false
-			}
-		}
-		return true
-	}
-
-	// Direct comparison
-	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
-}
-
 // Note: isDefaultValue is defined in state.go
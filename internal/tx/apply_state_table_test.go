@@ -2,11 +2,15 @@ package tx
 
 import (
 	"bytes"
+	"encoding/hex"
+	"reflect"
 	"sort"
 	"testing"
 
 	"github.com/LeJamon/goXRPLd/amendment"
+	binarycodec "github.com/LeJamon/goXRPLd/codec/binarycodec"
 	"github.com/LeJamon/goXRPLd/drops"
+	"github.com/LeJamon/goXRPLd/internal/ledger/state"
 	"github.com/LeJamon/goXRPLd/keylet"
 )
 
@@ -271,3 +275,124 @@ func TestForEach_NoDuplicates(t *testing.T) {
 		t.Fatalf("expected 1 entry (no duplicate for cached read), got %d", len(results))
 	}
 }
+
+func TestApply_ThreadsAndDiffsEntries(t *testing.T) {
+	alice, _ := state.DecodeAccountID(sleTestAlice)
+	bob, _ := state.DecodeAccountID(sleTestBob)
+	aliceKey, bobKey := keylet.Account(alice), keylet.Account(bob)
+
+	entries := sleTestEntries()
+	aliceRoot := entries["AccountRoot"]
+	bobRoot := map[string]any{}
+	for k, v := range aliceRoot {
+		bobRoot[k] = v
+	}
+	bobRoot["Account"] = sleTestBob
+
+	base := newMockBaseView()
+	base.data[aliceKey.Key] = encodeSLETest(t, aliceRoot)
+	base.data[bobKey.Key] = encodeSLETest(t, bobRoot)
+
+	var txHash [32]byte
+	txHash[31] = 0x42
+	table := NewApplyStateTable(base, txHash, 20, nil)
+
+	// Debit Alice and create a trust line; Bob is only touched through
+	// threading as the trust line's high account.
+	aliceRoot["Balance"] = "999999990"
+	if err := table.Update(aliceKey, encodeSLETest(t, aliceRoot)); err != nil {
+		t.Fatal(err)
+	}
+	line := entries["RippleState"]
+	delete(line, "PreviousTxnID")
+	delete(line, "PreviousTxnLgrSeq")
+	if err := table.Insert(kl(9), encodeSLETest(t, line)); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := table.Apply()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.AffectedNodes) != 3 {
+		t.Fatalf("expected 3 affected nodes, got %d", len(meta.AffectedNodes))
+	}
+
+	nodes := map[string]AffectedNode{}
+	for _, n := range meta.AffectedNodes {
+		nodes[n.LedgerIndex] = n
+	}
+	aliceNode := nodes[hashString(aliceKey.Key)]
+	if aliceNode.NodeType != "ModifiedNode" || aliceNode.LedgerEntryType != "AccountRoot" {
+		t.Fatalf("unexpected Alice node: %+v", aliceNode)
+	}
+	if want := map[string]any{"Balance": "1000000000"}; !reflect.DeepEqual(aliceNode.PreviousFields, want) {
+		t.Errorf("Alice PreviousFields = %v, want %v", aliceNode.PreviousFields, want)
+	}
+	if aliceNode.FinalFields["Balance"] != "999999990" || aliceNode.FinalFields["Sequence"] != uint32(5) {
+		t.Errorf("Alice FinalFields = %v", aliceNode.FinalFields)
+	}
+	if aliceNode.PreviousTxnID != sleTestTxID || aliceNode.PreviousTxnLgrSeq != 10 {
+		t.Errorf("Alice previous txn = %s/%d", aliceNode.PreviousTxnID, aliceNode.PreviousTxnLgrSeq)
+	}
+
+	bobNode := nodes[hashString(bobKey.Key)]
+	if bobNode.NodeType != "ModifiedNode" || bobNode.PreviousFields != nil {
+		t.Errorf("unexpected Bob node: %+v", bobNode)
+	}
+
+	lineNode := nodes[hashString(key(9))]
+	if lineNode.NodeType != "CreatedNode" || lineNode.LedgerEntryType != "RippleState" {
+		t.Fatalf("unexpected trust line node: %+v", lineNode)
+	}
+	if _, ok := lineNode.NewFields["PreviousTxnID"]; ok {
+		t.Error("PreviousTxnID must not appear in NewFields")
+	}
+	if _, ok := lineNode.NewFields["LowNode"]; ok {
+		t.Error("default-valued LowNode must not appear in NewFields")
+	}
+	if !reflect.DeepEqual(lineNode.NewFields["LowLimit"], line["LowLimit"]) {
+		t.Errorf("LowLimit = %v, want %v", lineNode.NewFields["LowLimit"], line["LowLimit"])
+	}
+
+	// Every affected entry is threaded to the transaction in the base view.
+	for k, fields := range map[[32]byte]map[string]any{aliceKey.Key: aliceRoot, bobKey.Key: bobRoot, key(9): line} {
+		fields["PreviousTxnID"] = hashString(txHash)
+		fields["PreviousTxnLgrSeq"] = uint32(20)
+		if want := encodeSLETest(t, fields); !bytes.Equal(base.data[k], want) {
+			t.Errorf("entry %X not threaded as expected", k[:4])
+		}
+	}
+}
+
+func BenchmarkApplyStateTable_Apply(b *testing.B) {
+	alice, _ := state.DecodeAccountID(sleTestAlice)
+	aliceKey := keylet.Account(alice)
+	entries := sleTestEntries()
+	encode := func(fields map[string]any) []byte {
+		encoded, err := binarycodec.Encode(fields)
+		if err != nil {
+			b.Fatal(err)
+		}
+		data, _ := hex.DecodeString(encoded)
+		return data
+	}
+	rootData := encode(entries["AccountRoot"])
+	lineData := encode(entries["RippleState"])
+	entries["RippleState"]["Flags"] = uint32(0)
+	lineUpdate := encode(entries["RippleState"])
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		base := newMockBaseView()
+		base.data[aliceKey.Key] = rootData
+		base.data[key(9)] = lineData
+		table := NewApplyStateTable(base, [32]byte{byte(i)}, 20, nil)
+		if err := table.Update(kl(9), lineUpdate); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := table.Apply(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
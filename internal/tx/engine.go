@@ -160,6 +160,14 @@ type EngineConfig struct {
 	// SkipSignatureVerification skips signature checks (for testing/standalone)
 	SkipSignatureVerification bool
 
+	// SignatureVerified, if set, reports whether the signature of the
+	// transaction with the given hash is already known to be good, as
+	// rippled's HashRouter SF_SIGGOOD flag does for transactions relayed
+	// by cluster members. Such single-signed transactions skip signature
+	// verification; multi-signed ones are still checked, as their signer
+	// quorum is checked with their signatures.
+	SignatureVerified func(txID [32]byte) bool
+
 	// Standalone indicates if running in standalone mode (relaxes some validation)
 	Standalone bool
 
@@ -963,7 +971,7 @@ func (e *Engine) preflight(tx Transaction) Result {
 					return TefBAD_SIGNATURE
 				}
 			}
-		} else {
+		} else if !e.signatureVerified(tx) {
 			// Single-signed transaction — verify cryptographic signature validity.
 			// The signing key authorization (master vs regular key) is checked in preclaim.
 			if err := VerifySignature(tx); err != nil {
@@ -982,6 +990,16 @@ func (e *Engine) preflight(tx Transaction) Result {
 	return TesSUCCESS
 }
 
+// signatureVerified reports whether config.SignatureVerified vouches for
+// the signature of tx.
+func (e *Engine) signatureVerified(tx Transaction) bool {
+	if e.config.SignatureVerified == nil {
+		return false
+	}
+	hash, err := computeTransactionHash(tx)
+	return err == nil && e.config.SignatureVerified(hash)
+}
+
 // parseValidationError extracts a TER result code from a validation error message.
 // If the error message starts with a valid TER code prefix (e.g., "temREDUNDANT:"),
 // it returns the corresponding Result. Otherwise, it returns TemINVALID.
@@ -1939,11 +1957,7 @@ func (e *Engine) doApply(tx Transaction, metadata *Metadata, txHash [32]byte) Re
 			const unfundedOfferRemoveLimit = 1000
 			for key, entry := range table.GetItems() {
 				if entry.Action == ActionErase {
-					entryType := getLedgerEntryType(entry.Original)
-					if entryType == "" && entry.Current != nil {
-						entryType = getLedgerEntryType(entry.Current)
-					}
-					if entryType == "Offer" {
+					if entry.entryType() == "Offer" {
 						removedOfferKeys = append(removedOfferKeys, key)
 						if len(removedOfferKeys) >= unfundedOfferRemoveLimit {
 							break
@@ -1961,11 +1975,7 @@ func (e *Engine) doApply(tx Transaction, metadata *Metadata, txHash [32]byte) Re
 			const maxDeletableAMMTrustLines = 512
 			for key, entry := range table.GetItems() {
 				if entry.Action == ActionErase {
-					entryType := getLedgerEntryType(entry.Original)
-					if entryType == "" && entry.Current != nil {
-						entryType = getLedgerEntryType(entry.Current)
-					}
-					if entryType == "RippleState" {
+					if entry.entryType() == "RippleState" {
 						removedTrustLineKeys = append(removedTrustLineKeys, key)
 						if len(removedTrustLineKeys) >= maxDeletableAMMTrustLines {
 							break
@@ -1982,11 +1992,7 @@ func (e *Engine) doApply(tx Transaction, metadata *Metadata, txHash [32]byte) Re
 			const expiredOfferRemoveLimit = 256
 			for key, entry := range table.GetItems() {
 				if entry.Action == ActionErase {
-					entryType := getLedgerEntryType(entry.Original)
-					if entryType == "" && entry.Current != nil {
-						entryType = getLedgerEntryType(entry.Current)
-					}
-					if entryType == "NFTokenOffer" {
+					if entry.entryType() == "NFTokenOffer" {
 						expiredNFTokenOfferKeys = append(expiredNFTokenOfferKeys, key)
 						if len(expiredNFTokenOfferKeys) >= expiredOfferRemoveLimit {
 							break
@@ -0,0 +1,39 @@
+package tx
+
+import (
+	"encoding/hex"
+	"testing"
+
+	binarycodec "github.com/LeJamon/goXRPLd/codec/binarycodec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineSignatureVerified(t *testing.T) {
+	encoded, err := binarycodec.Encode(map[string]any{
+		"TransactionType": "AccountSet",
+		"Account":         "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+		"Fee":             "12",
+		"Sequence":        uint32(7),
+		"Flags":           uint32(0),
+		"SigningPubKey":   "",
+	})
+	require.NoError(t, err)
+	blob, err := hex.DecodeString(encoded)
+	require.NoError(t, err)
+	parsed, err := ParseFromBinary(blob)
+	require.NoError(t, err)
+	hash, err := computeTransactionHash(parsed)
+	require.NoError(t, err)
+
+	assert.False(t, NewEngine(nil, EngineConfig{}).signatureVerified(parsed))
+
+	verified := map[[32]byte]bool{hash: true}
+	e := NewEngine(nil, EngineConfig{
+		SignatureVerified: func(txID [32]byte) bool { return verified[txID] },
+	})
+	assert.True(t, e.signatureVerified(parsed))
+
+	delete(verified, hash)
+	assert.False(t, e.signatureVerified(parsed))
+}
@@ -1,11 +1,8 @@
 package tx
 
 import (
-	"encoding/hex"
 	"fmt"
 	"strconv"
-
-	binarycodec "github.com/LeJamon/goXRPLd/codec/binarycodec"
 )
 
 // Field metadata flags matching rippled's SField metadata flags.
@@ -104,31 +101,3 @@ func convertHexToDecimal(hexVal string) (string, error) {
 	}
 	return strconv.FormatUint(v, 10), nil
 }
-
-// extractLedgerFields extracts fields from binary ledger entry data.
-// Uses the binary codec to generically decode any ledger entry type.
-// Fields with sMD_BaseTen are converted from hex to decimal representation.
-func extractLedgerFields(data []byte, entryType string) (map[string]any, error) {
-	// Convert binary data to hex string for the codec
-	hexStr := hex.EncodeToString(data)
-
-	// Use the binary codec to decode the ledger entry
-	fields, err := binarycodec.Decode(hexStr)
-	if err != nil {
-		// If decoding fails, return empty map (graceful degradation)
-		return make(map[string]any), nil
-	}
-
-	// Convert BaseTen fields from hex to decimal strings
-	for name, value := range fields {
-		if isBaseTenField(name) {
-			if hexStr, ok := value.(string); ok {
-				if decStr, err := convertHexToDecimal(hexStr); err == nil {
-					fields[name] = decStr
-				}
-			}
-		}
-	}
-
-	return fields, nil
-}
@@ -1,11 +1,10 @@
 package invariants
 
 import (
-	"encoding/hex"
 	"fmt"
 
 	"github.com/LeJamon/goXRPLd/amendment"
-	binarycodec "github.com/LeJamon/goXRPLd/codec/binarycodec"
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 	"github.com/LeJamon/goXRPLd/internal/ledger/state"
 	"github.com/LeJamon/goXRPLd/keylet"
 )
@@ -40,47 +39,14 @@ type ammInvariantFields struct {
 	hasBalance bool
 }
 
-// isLikelyAMMBinary checks if binary data is an AMM SLE entry.
-// AMM entries are now stored using the standard binary codec format, so we
-// check the LedgerEntryType field via the codec. This replaced the old
-// heuristic-based approach that relied on the custom binary format.
-func isLikelyAMMBinary(data []byte) bool {
-	// The standard detection via getLedgerEntryType is now the primary path.
-	// This function serves as a fallback for entries where EntryType != "AMM".
-	return getLedgerEntryType(data) == "AMM"
-}
-
-// parseAMMInvariantFields extracts the Account ID and LPTokenBalance from
-// binary AMM SLE data. AMM data is now stored in the standard binary codec
-// format, so we decode it via binarycodec.Decode.
+// parseAMMInvariantFields extracts the Account ID and LPTokenBalance from a
+// decoded AMM entry.
 // Reference: rippled InvariantCheck.cpp lines 1733-1737 (after), 1749-1754 (before)
-func parseAMMInvariantFields(data []byte) (*ammInvariantFields, error) {
-	hexStr := hex.EncodeToString(data)
-	fields, err := binarycodec.Decode(hexStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode AMM binary: %w", err)
-	}
-
+func parseAMMInvariantFields(sle LedgerEntry) *ammInvariantFields {
 	result := &ammInvariantFields{}
-
-	// Account (r-address string → [20]byte)
-	if acctStr, ok := fields["Account"].(string); ok {
-		id, err := state.DecodeAccountID(acctStr)
-		if err == nil {
-			result.accountID = id
-		}
-	}
-
-	// LPTokenBalance (Amount object)
-	if lptObj, ok := fields["LPTokenBalance"].(map[string]any); ok {
-		valueStr, _ := lptObj["value"].(string)
-		currency, _ := lptObj["currency"].(string)
-		issuer, _ := lptObj["issuer"].(string)
-		result.lptBalance = state.NewIssuedAmountFromDecimalString(valueStr, currency, issuer)
-		result.hasBalance = true
-	}
-
-	return result, nil
+	result.accountID, _ = sle.AccountID(st.SFAccount)
+	result.lptBalance, result.hasBalance = sle.IssuedAmount(st.SFLPTokenBalance)
+	return result
 }
 
 // ammPoolHoldsForInvariant reads the balances of both assets in the AMM pool.
@@ -272,29 +238,14 @@ func checkValidAMM(tx Transaction, result Result, entries []InvariantEntry, view
 
 		// Check "after" data
 		if e.After != nil {
-			// Try to detect AMM entries.
-			// AMM SLE uses a custom binary format (no 0x11 type header), so
-			// e.EntryType from getLedgerEntryType may not return "AMM".
-			// We detect AMM entries by:
-			//   1. Explicit "AMM" EntryType (if binary codec format is used)
-			//   2. Trying to parse as AMM data for entries with unknown/non-standard types
-			isAMMEntry := false
-			if e.EntryType == "AMM" {
-				isAMMEntry = true
-			} else if isLikelyAMMBinary(e.After) {
-				isAMMEntry = true
-			}
-
-			if isAMMEntry {
+			if e.EntryType == "AMM" && e.AfterSLE != nil {
 				// AMM object changed — extract account ID and LPTokenBalance
-				fields, err := parseAMMInvariantFields(e.After)
-				if err == nil {
-					id := fields.accountID
-					ammAccount = &id
-					if fields.hasBalance {
-						bal := fields.lptBalance
-						lptAfter = &bal
-					}
+				fields := parseAMMInvariantFields(e.AfterSLE)
+				id := fields.accountID
+				ammAccount = &id
+				if fields.hasBalance {
+					bal := fields.lptBalance
+					lptAfter = &bal
 				}
 			} else if e.EntryType == "RippleState" {
 				// Check for lsfAMMNode flag
@@ -315,20 +266,11 @@ func checkValidAMM(tx Transaction, result Result, entries []InvariantEntry, view
 		}
 
 		// Check "before" data for LPTokenBalance
-		if e.Before != nil {
-			isAMMBefore := false
-			if e.EntryType == "AMM" {
-				isAMMBefore = true
-			} else if isLikelyAMMBinary(e.Before) {
-				isAMMBefore = true
-			}
-
-			if isAMMBefore {
-				fields, err := parseAMMInvariantFields(e.Before)
-				if err == nil && fields.hasBalance {
-					bal := fields.lptBalance
-					lptBefore = &bal
-				}
+		if e.EntryType == "AMM" && e.BeforeSLE != nil {
+			fields := parseAMMInvariantFields(e.BeforeSLE)
+			if fields.hasBalance {
+				bal := fields.lptBalance
+				lptBefore = &bal
 			}
 		}
 	}
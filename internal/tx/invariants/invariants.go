@@ -11,6 +11,7 @@ import (
 	"fmt"
 
 	"github.com/LeJamon/goXRPLd/amendment"
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 	"github.com/LeJamon/goXRPLd/internal/ledger/state"
 	"github.com/LeJamon/goXRPLd/keylet"
 )
@@ -20,12 +21,24 @@ const InitialXRP uint64 = 100_000_000_000_000_000
 
 // InvariantEntry represents a single ledger entry modification to be checked by invariants.
 // Before is nil for newly created entries; After is nil for deleted entries.
+// BeforeSLE and AfterSLE are the same entries as already decoded by the
+// ApplyStateTable, and are nil exactly when Before and After are.
 type InvariantEntry struct {
-	Key       [32]byte // ledger key of the entry (for invariants like ValidNFTokenPage that need to inspect the key)
-	EntryType string   // e.g. "AccountRoot", "RippleState", "Offer", "Escrow", "PayChannel"
-	Before    []byte   // serialized SLE before the transaction (nil for inserts)
-	After     []byte   // serialized SLE after the transaction (nil for deletes)
-	IsDelete  bool     // true if the entry was deleted
+	Key       [32]byte    // ledger key of the entry (for invariants like ValidNFTokenPage that need to inspect the key)
+	EntryType string      // e.g. "AccountRoot", "RippleState", "Offer", "Escrow", "PayChannel"
+	Before    []byte      // serialized SLE before the transaction (nil for inserts)
+	After     []byte      // serialized SLE after the transaction (nil for deletes)
+	BeforeSLE LedgerEntry // decoded Before
+	AfterSLE  LedgerEntry // decoded After
+	IsDelete  bool        // true if the entry was deleted
+}
+
+// LedgerEntry is a decoded ledger entry. It is satisfied by *tx.SLE, so
+// checkers read fields without decoding the serialized entry again.
+type LedgerEntry interface {
+	EntryType() string
+	AccountID(field *st.Field) ([20]byte, bool)
+	IssuedAmount(field *st.Field) (Amount, bool)
 }
 
 // InvariantViolation holds the name and description of a detected invariant violation.
@@ -7,6 +7,7 @@ import (
 
 	"github.com/LeJamon/goXRPLd/internal/ledger/state"
 	"github.com/LeJamon/goXRPLd/keylet"
+	"github.com/LeJamon/goXRPLd/ledger/entry"
 )
 
 // ---------------------------------------------------------------------------
@@ -150,9 +151,6 @@ func validatePermissionedDomainCredentials(pd *state.PermissionedDomainData, _ b
 //     no regular offers affected
 //   - Bad hybrids always fail for OfferCreate
 
-// lsfHybridInvariant is the ledger flag for hybrid offers.
-const lsfHybridInvariant uint32 = 0x00040000
-
 func checkValidPermissionedDEX(tx Transaction, result Result, entries []InvariantEntry, view ReadView) *InvariantViolation {
 	txType := tx.TxType()
 
@@ -207,7 +205,7 @@ func checkValidPermissionedDEX(tx Transaction, result Result, entries []Invarian
 			// (AdditionalBookDirectory, AdditionalBookNode). We check:
 			//   1. DomainID must be present for hybrid offers
 			//   2. AdditionalBooks (if encoded as STArray) must have <= 1 entry
-			if (offer.Flags & lsfHybridInvariant) != 0 {
+			if (offer.Flags & entry.OfferHybrid) != 0 {
 				if offer.DomainID == zeroHash {
 					badHybrids = true
 				}
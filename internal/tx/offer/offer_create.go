@@ -14,6 +14,7 @@ import (
 	"github.com/LeJamon/goXRPLd/internal/tx/payment"
 	"github.com/LeJamon/goXRPLd/internal/tx/permissioneddomain"
 	"github.com/LeJamon/goXRPLd/keylet"
+	"github.com/LeJamon/goXRPLd/ledger/entry"
 )
 
 // OfferCreate flag mask - invalid flags
@@ -1763,7 +1764,7 @@ func parseFee(ctx *tx.ApplyContext) uint64 {
 // applyHybridInSandbox handles hybrid offer placement in a specific view/sandbox.
 // Reference: rippled CreateOffer.cpp applyHybrid() lines 528-573
 func applyHybridInSandbox(view tx.LedgerView, ctx *tx.ApplyContext, offer *state.LedgerOffer, offerKey keylet.Keylet, takerPays, takerGets tx.Amount, domainBookDir keylet.Keylet) tx.Result {
-	offer.Flags |= lsfHybrid
+	offer.Flags |= entry.OfferHybrid
 
 	// Also place in open book (without domain)
 	takerPaysCurrency := state.GetCurrencyBytes(takerPays.Currency)
@@ -1793,6 +1794,3 @@ func applyHybridInSandbox(view tx.LedgerView, ctx *tx.ApplyContext, offer *state
 
 	return tx.TesSUCCESS
 }
-
-// lsfHybrid is the ledger flag for hybrid offers
-const lsfHybrid uint32 = 0x00040000
@@ -1,11 +1,10 @@
 package tx
 
 import (
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 
-	binarycodec "github.com/LeJamon/goXRPLd/codec/binarycodec"
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 )
 
 // ParseJSON parses a JSON transaction into the appropriate transaction type.
@@ -41,22 +40,28 @@ func TypeFromString(s string) (Type, error) {
 	return t, nil
 }
 
-// ParseFromBinary parses a binary transaction blob into a Transaction
+// ParseFromBinary parses a binary transaction blob into a Transaction.
+// The blob is walked with st.Reader rather than binarycodec.Decode, but the
+// transaction structs are still populated through their JSON tags, so each
+// field is rendered to its JSON value first. The struct codec covers ledger
+// entries (see ledger/entry); transactions have no binary decoders of their
+// own.
 func ParseFromBinary(blob []byte) (Transaction, error) {
-	// Convert binary to hex string for the codec
-	hexStr := hex.EncodeToString(blob)
-
-	// Decode binary to JSON map
-	jsonMap, err := binarycodec.Decode(hexStr)
-	if err != nil {
-		return nil, errors.New("failed to decode binary transaction: " + err.Error())
-	}
-
-	// Extract present fields from the decoded map
-	// This is used to distinguish between absent fields and empty values
+	// Decode each field into its JSON value, recording the present fields
+	// to distinguish between absent fields and empty values
+	jsonMap := make(map[string]any)
 	presentFields := make(map[string]bool)
-	for key := range jsonMap {
-		presentFields[key] = true
+	r := st.NewReader(blob)
+	for r.Next() {
+		value, err := decodeFieldJSON(r.Field(), r.Raw())
+		if err != nil {
+			return nil, errors.New("failed to decode binary transaction: " + err.Error())
+		}
+		jsonMap[r.Field().Name] = value
+		presentFields[r.Field().Name] = true
+	}
+	if err := r.Err(); err != nil {
+		return nil, errors.New("failed to decode binary transaction: " + err.Error())
 	}
 
 	// Convert map to JSON bytes
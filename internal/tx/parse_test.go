@@ -0,0 +1,47 @@
+package tx
+
+import (
+	"encoding/hex"
+	"testing"
+
+	binarycodec "github.com/LeJamon/goXRPLd/codec/binarycodec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFromBinary(t *testing.T) {
+	encoded, err := binarycodec.Encode(map[string]any{
+		"TransactionType": "AccountSet",
+		"Account":         "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+		"Fee":             "12",
+		"Sequence":        uint32(7),
+		"Flags":           uint32(0),
+		"SigningPubKey":   "",
+		"Memos": []any{
+			map[string]any{"Memo": map[string]any{"MemoData": "CAFE"}},
+		},
+	})
+	require.NoError(t, err)
+	blob, err := hex.DecodeString(encoded)
+	require.NoError(t, err)
+
+	parsed, err := ParseFromBinary(blob)
+	require.NoError(t, err)
+	c := parsed.GetCommon()
+	assert.Equal(t, "AccountSet", c.TransactionType)
+	assert.Equal(t, "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", c.Account)
+	assert.Equal(t, "12", c.Fee)
+	require.NotNil(t, c.Sequence)
+	assert.Equal(t, uint32(7), *c.Sequence)
+	require.Len(t, c.Memos, 1)
+	assert.Equal(t, "CAFE", c.Memos[0].Memo.MemoData)
+	assert.Equal(t, blob, c.GetRawBytes())
+
+	// Empty fields are present; fields not in the blob are not.
+	assert.True(t, c.HasField("SigningPubKey"))
+	assert.True(t, c.HasField("Flags"))
+	assert.False(t, c.HasField("LastLedgerSequence"))
+
+	_, err = ParseFromBinary(blob[:len(blob)-2])
+	assert.Error(t, err)
+}
@@ -3,15 +3,27 @@ package pathfinder
 import (
 	"github.com/LeJamon/goXRPLd/internal/ledger/state"
 	tx "github.com/LeJamon/goXRPLd/internal/tx"
+	"github.com/LeJamon/goXRPLd/internal/tx/amm"
 	"github.com/LeJamon/goXRPLd/internal/tx/payment"
 	"github.com/LeJamon/goXRPLd/keylet"
+	"github.com/LeJamon/goXRPLd/ledger/entry"
 )
 
 // BookIndex provides an index of existing order books in the ledger.
 // Rippled maintains an OrderBookDB; we build a lightweight equivalent
 // by scanning the ledger for book directories on demand.
+//
+// An index covers a single venue: the open DEX when domain is nil, or
+// the books of one permissioned domain otherwise. rippled keeps these as
+// OrderBookDB::allBooks_ and domainBooks_ respectively.
+//
+// AMM pools count as books in both directions on the open DEX, so pairs
+// with no CLOB offers are still reachable; the flow engine's BookStep
+// then draws on the pool, so path ranking sees the pool's quality like
+// any offer's. AMMs never join a domain's books.
 type BookIndex struct {
 	ledger tx.LedgerView
+	domain *[32]byte
 	// byTakerPays maps an Issue (what the taker pays) to a list of Issues
 	// (what the taker gets) for all books that exist.
 	byTakerPays map[payment.Issue][]payment.Issue
@@ -26,6 +38,31 @@ func NewBookIndex(ledger tx.LedgerView) *BookIndex {
 	}
 }
 
+// NewDomainBookIndex creates a BookIndex restricted to the order books
+// of the given permissioned domain. A nil domain indexes the open DEX,
+// same as NewBookIndex.
+func NewDomainBookIndex(ledger tx.LedgerView, domain *[32]byte) *BookIndex {
+	bi := NewBookIndex(ledger)
+	bi.domain = domain
+	return bi
+}
+
+// Domain returns the permissioned domain this index covers, or nil for
+// the open DEX.
+func (bi *BookIndex) Domain() *[32]byte {
+	return bi.domain
+}
+
+// inVenue reports whether an offer sits in a book of this index. Domain
+// offers belong only to their domain's books; hybrid offers are also
+// placed in the open book, as OfferCreate::applyHybrid does.
+func (bi *BookIndex) inVenue(offer *state.LedgerOffer) bool {
+	if bi.domain != nil {
+		return offer.DomainID == *bi.domain
+	}
+	return offer.DomainID == [32]byte{} || offer.Flags&entry.OfferHybrid != 0
+}
+
 // Build scans the ledger for all offer entries and builds the book index.
 // This is called lazily on first use.
 func (bi *BookIndex) Build() {
@@ -39,6 +76,13 @@ func (bi *BookIndex) Build() {
 	// during parsing (e.g., IOUAmount overflow from malformed data), the entry
 	// is skipped rather than crashing the entire RPC handler goroutine.
 	seen := make(map[[2]payment.Issue]bool)
+	addBook := func(takerPays, takerGets payment.Issue) {
+		pair := [2]payment.Issue{takerPays, takerGets}
+		if !seen[pair] {
+			seen[pair] = true
+			bi.byTakerPays[takerPays] = append(bi.byTakerPays[takerPays], takerGets)
+		}
+	}
 	_ = bi.ledger.ForEach(func(key [32]byte, data []byte) (cont bool) {
 		defer func() {
 			if r := recover(); r != nil {
@@ -47,18 +91,30 @@ func (bi *BookIndex) Build() {
 			}
 		}()
 
+		// AMM pools. Reference: rippled OrderBookDB::update() ltAMM case
+		if t, err := state.GetLedgerEntryType(data); err == nil && entry.Type(t) == entry.TypeAMM {
+			if bi.domain != nil {
+				return true
+			}
+			pool, err := amm.ParseAMMData(data)
+			if err != nil || pool.LPTokenBalance.IsZero() {
+				return true
+			}
+			a, b := issueFromAsset(pool.Asset), issueFromAsset(pool.Asset2)
+			addBook(a, b)
+			addBook(b, a)
+			return true
+		}
+
 		offer, err := state.ParseLedgerOffer(data)
 		if err != nil {
 			return true // not an offer, continue
 		}
-
-		takerPays := issueFromAmount(offer.TakerPays)
-		takerGets := issueFromAmount(offer.TakerGets)
-		pair := [2]payment.Issue{takerPays, takerGets}
-		if !seen[pair] {
-			seen[pair] = true
-			bi.byTakerPays[takerPays] = append(bi.byTakerPays[takerPays], takerGets)
+		if !bi.inVenue(offer) {
+			return true
 		}
+
+		addBook(issueFromAmount(offer.TakerPays), issueFromAmount(offer.TakerGets))
 		return true
 	})
 }
@@ -85,6 +141,8 @@ func (bi *BookIndex) IsBookToXRP(issue payment.Issue) bool {
 }
 
 // BookExists checks whether a specific book directory exists in the ledger.
+// For a domain index the domain's book directory is checked; on the open
+// DEX an AMM pool for the pair also counts.
 func (bi *BookIndex) BookExists(takerPays, takerGets payment.Issue) bool {
 	var paysCurrency, paysIssuer, getsCurrency, getsIssuer [20]byte
 	paysCurrency = currencyTo20(takerPays.Currency)
@@ -92,7 +150,16 @@ func (bi *BookIndex) BookExists(takerPays, takerGets payment.Issue) bool {
 	getsCurrency = currencyTo20(takerGets.Currency)
 	getsIssuer = takerGets.Issuer
 	k := keylet.BookDir(paysCurrency, paysIssuer, getsCurrency, getsIssuer)
-	exists, _ := bi.ledger.Exists(k)
+	if bi.domain != nil {
+		k = keylet.BookDirWithDomain(paysCurrency, paysIssuer, getsCurrency, getsIssuer, *bi.domain)
+	}
+	if exists, _ := bi.ledger.Exists(k); exists {
+		return true
+	}
+	if bi.domain != nil {
+		return false
+	}
+	exists, _ := bi.ledger.Exists(keylet.AMM(paysIssuer, paysCurrency, getsIssuer, getsCurrency))
 	return exists
 }
 
@@ -105,6 +172,15 @@ func issueFromAmount(amt state.Amount) payment.Issue {
 	return payment.Issue{Currency: amt.Currency, Issuer: issuer}
 }
 
+// issueFromAsset converts an AMM pool asset to an Issue.
+func issueFromAsset(asset tx.Asset) payment.Issue {
+	if asset.Currency == "" || asset.Currency == "XRP" {
+		return payment.Issue{Currency: "XRP"}
+	}
+	issuer, _ := state.DecodeAccountID(asset.Issuer)
+	return payment.Issue{Currency: asset.Currency, Issuer: issuer}
+}
+
 // currencyTo20 converts a currency string to a 20-byte representation.
 // XRP is all zeros; 3-char codes are left-padded in bytes 12-14.
 func currencyTo20(currency string) [20]byte {
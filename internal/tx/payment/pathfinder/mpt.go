@@ -0,0 +1,163 @@
+package pathfinder
+
+import (
+	"encoding/hex"
+	"math/big"
+
+	"github.com/LeJamon/goXRPLd/internal/ledger/state"
+	tx "github.com/LeJamon/goXRPLd/internal/tx"
+	"github.com/LeJamon/goXRPLd/internal/tx/payment"
+	"github.com/LeJamon/goXRPLd/keylet"
+	"github.com/LeJamon/goXRPLd/ledger/entry"
+)
+
+// mptQualityOne is the MPT transfer rate with no fee.
+const mptQualityOne uint64 = 1_000_000_000
+
+// maxMPTAmount is the largest MPT amount when an issuance sets no maximum.
+const maxMPTAmount uint64 = 0x7FFFFFFFFFFFFFFF
+
+// executeMPT answers a request whose destination amount is an MPT.
+// MPTs never cross order books, so the only candidate is the direct
+// transfer applyMPTPayment performs: it is returned as one alternative
+// with no paths when the ledger would let it succeed.
+// Reference: rippled Payment::doApply MPT branch
+func (pr *PathRequest) executeMPT(ledger tx.LedgerView, result *PathRequestResult) *PathRequestResult {
+	idBytes, err := hex.DecodeString(pr.dstAmount.MPTIssuanceID())
+	if err != nil || len(idBytes) != 24 {
+		return result
+	}
+	var mptID [24]byte
+	copy(mptID[:], idBytes)
+
+	issuanceKey := keylet.MPTIssuance(mptID)
+	data, err := ledger.Read(issuanceKey)
+	if err != nil || data == nil {
+		return result
+	}
+	issuance, err := state.ParseMPTokenIssuance(data)
+	if err != nil {
+		return result
+	}
+
+	// A sendMax in another asset cannot fund an MPT payment
+	if pr.sendMax != nil && pr.sendMax.MPTIssuanceID() != pr.dstAmount.MPTIssuanceID() {
+		return result
+	}
+	if pr.srcAccount == pr.dstAccount {
+		return result
+	}
+
+	senderIsIssuer := pr.srcAccount == issuance.Issuer
+	destIsIssuer := pr.dstAccount == issuance.Issuer
+
+	srcToken := readMPToken(ledger, issuanceKey.Key, pr.srcAccount)
+	dstToken := readMPToken(ledger, issuanceKey.Key, pr.dstAccount)
+	if (!senderIsIssuer && !mptAuthorized(issuance, srcToken)) ||
+		(!destIsIssuer && !mptAuthorized(issuance, dstToken)) {
+		return result
+	}
+
+	// Holder-to-holder transfers need CanTransfer, no lock on either side,
+	// and pay the issuance transfer fee.
+	rate := mptQualityOne
+	if !senderIsIssuer && !destIsIssuer {
+		if issuance.Flags&entry.LsfMPTCanTransfer == 0 ||
+			issuance.Flags&entry.LsfMPTLocked != 0 ||
+			srcToken.Flags&entry.LsfMPTLocked != 0 ||
+			dstToken.Flags&entry.LsfMPTLocked != 0 {
+			return result
+		}
+		if issuance.TransferFee > 0 {
+			rate += 10_000 * uint64(issuance.TransferFee)
+		}
+	}
+
+	// What the source can spend: its balance, or the room left under the
+	// issuance maximum when the issuer mints.
+	available := uint64(0)
+	if senderIsIssuer {
+		maximum := maxMPTAmount
+		if issuance.MaximumAmount != nil {
+			maximum = *issuance.MaximumAmount
+		}
+		if maximum > issuance.OutstandingAmount {
+			available = maximum - issuance.OutstandingAmount
+		}
+	} else {
+		available = srcToken.MPTAmount
+	}
+	if pr.sendMax != nil {
+		if limit := mptValue(*pr.sendMax); limit < available {
+			available = limit
+		}
+	}
+
+	var deliver, source uint64
+	if pr.convertAll {
+		source = available
+		deliver = mptScale(available, mptQualityOne, rate)
+	} else {
+		deliver = mptValue(pr.dstAmount)
+		source = mptScale(deliver, rate, mptQualityOne)
+	}
+	if deliver == 0 || source == 0 || source > available {
+		return result
+	}
+
+	srcAmount := state.NewMPTAmountWithIssuanceID(int64(source),
+		state.EncodeAccountIDSafe(issuance.Issuer), pr.dstAmount.MPTIssuanceID())
+	result.Alternatives = append(result.Alternatives, PathAlternative{
+		SourceAmount:  srcAmount,
+		PathsComputed: [][]payment.PathStep{},
+	})
+	return result
+}
+
+// readMPToken returns the holder's MPToken for an issuance, or nil.
+func readMPToken(ledger tx.LedgerView, issuanceKey [32]byte, holder [20]byte) *state.MPTokenData {
+	data, err := ledger.Read(keylet.MPToken(issuanceKey, holder))
+	if err != nil || data == nil {
+		return nil
+	}
+	token, err := state.ParseMPToken(data)
+	if err != nil {
+		return nil
+	}
+	return token
+}
+
+// mptAuthorized reports whether a non-issuer may hold the MPT: it needs
+// an MPToken, authorized by the issuer when the issuance requires it.
+// Reference: rippled View.cpp requireAuth() for MPTIssue
+func mptAuthorized(issuance *state.MPTokenIssuanceData, token *state.MPTokenData) bool {
+	if token == nil {
+		return false
+	}
+	if issuance.Flags&entry.LsfMPTRequireAuth != 0 {
+		return token.Flags&entry.LsfMPTAuthorized != 0
+	}
+	return true
+}
+
+// mptValue returns the integer value of an MPT amount.
+func mptValue(a tx.Amount) uint64 {
+	if raw, ok := a.MPTRaw(); ok && raw > 0 {
+		return uint64(raw)
+	}
+	return 0
+}
+
+// mptScale returns amount*num/den without rounding, as STAmount
+// multiply and divide do for MPTs.
+func mptScale(amount, num, den uint64) uint64 {
+	if num == den {
+		return amount
+	}
+	r := new(big.Int).Mul(new(big.Int).SetUint64(amount), new(big.Int).SetUint64(num))
+	r.Div(r, new(big.Int).SetUint64(den))
+	if !r.IsUint64() {
+		return 0
+	}
+	return r.Uint64()
+}
@@ -35,6 +35,7 @@ type PathRequest struct {
 	sourceCurrencies []payment.Issue // Explicit source currencies (or auto-discovered)
 	convertAll       bool
 	maxPaths         int
+	domain           *[32]byte // Permissioned domain, nil for the open DEX
 }
 
 // NewPathRequest creates a new path request from the given parameters.
@@ -56,6 +57,17 @@ func NewPathRequest(
 	}
 }
 
+// SetDomain restricts the request to the order books of a permissioned
+// domain. Reference: rippled PathRequest::parseJson (jss::domain)
+func (pr *PathRequest) SetDomain(domain *[32]byte) {
+	pr.domain = domain
+}
+
+// Domain returns the permissioned domain of the request, or nil.
+func (pr *PathRequest) Domain() *[32]byte {
+	return pr.domain
+}
+
 // Execute runs the pathfinding algorithm and returns the result.
 // Reference: rippled PathRequest::doUpdate()
 func (pr *PathRequest) Execute(ledger tx.LedgerView) *PathRequestResult {
@@ -113,6 +125,10 @@ func (pr *PathRequest) Execute(ledger tx.LedgerView) *PathRequestResult {
 		result.DestinationCurrencies = append(result.DestinationCurrencies, issue.Currency)
 	}
 
+	if pr.dstAmount.IsMPT() {
+		return pr.executeMPT(ledger, result)
+	}
+
 	// Track previously found paths per source currency (mContext in rippled)
 	context := make(map[payment.Issue][][]payment.PathStep)
 
@@ -144,6 +160,9 @@ func (pr *PathRequest) Execute(ledger tx.LedgerView) *PathRequestResult {
 			srcIssue.Currency, srcIssue.Issuer,
 			pr.convertAll,
 		)
+		if pr.domain != nil {
+			pf.SetDomain(pr.domain)
+		}
 
 		if !pf.FindPaths(DefaultSearchLevel) {
 			continue
@@ -173,6 +192,7 @@ func (pr *PathRequest) Execute(ledger tx.LedgerView) *PathRequestResult {
 			pr.convertAll,
 			false,
 			[32]byte{}, 0,
+			payment.WithDomainID(pr.domain),
 		)
 
 		// If insufficient and we have a full-liquidity path, try adding it
@@ -189,6 +209,7 @@ func (pr *PathRequest) Execute(ledger tx.LedgerView) *PathRequestResult {
 				false,
 				false,
 				[32]byte{}, 0,
+				payment.WithDomainID(pr.domain),
 			)
 		}
 
@@ -204,6 +225,7 @@ func (pr *PathRequest) Execute(ledger tx.LedgerView) *PathRequestResult {
 				pr.convertAll,
 				false,
 				[32]byte{}, 0,
+				payment.WithDomainID(pr.domain),
 			)
 
 			alt := PathAlternative{
@@ -23,6 +23,10 @@ type Pathfinder struct {
 
 	convertAll bool // true for partial payments (tfPartialPayment)
 
+	// domain restricts book traversal and liquidity checks to the order
+	// books of one permissioned domain. nil means the open DEX.
+	domain *[32]byte
+
 	ledger tx.LedgerView
 	cache  *RippleLineCache
 	books  *BookIndex
@@ -97,6 +101,17 @@ func NewPathfinder(
 	}
 }
 
+// SetDomain restricts the search to the order books of a permissioned
+// domain, as the optional domain argument to rippled's Pathfinder
+// constructor does. Must be called before FindPaths; nil restores the
+// open DEX.
+func (pf *Pathfinder) SetDomain(domain *[32]byte) {
+	pf.domain = domain
+	pf.books = NewDomainBookIndex(pf.ledger, domain)
+	pf.paths = make(map[string][][]payment.PathStep)
+	pf.pathsOutCount = make(map[payment.Issue]int)
+}
+
 // CompletePaths returns the discovered complete paths.
 func (pf *Pathfinder) CompletePaths() [][]payment.PathStep {
 	return pf.completePaths
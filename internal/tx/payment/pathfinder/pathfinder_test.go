@@ -10,6 +10,7 @@ import (
 	tx "github.com/LeJamon/goXRPLd/internal/tx"
 	"github.com/LeJamon/goXRPLd/internal/tx/payment"
 	"github.com/LeJamon/goXRPLd/keylet"
+	"github.com/LeJamon/goXRPLd/ledger/entry"
 	"github.com/stretchr/testify/require"
 )
 
@@ -737,6 +738,111 @@ func TestBookIndex_LazyBuild(t *testing.T) {
 	require.True(t, bi.built)
 }
 
+// addDomainOffer stores a LedgerOffer placed in a permissioned domain.
+func addDomainOffer(
+	t *testing.T,
+	ledger *mockLedgerView,
+	account [20]byte,
+	seq uint32,
+	takerPays, takerGets state.Amount,
+	domain [32]byte,
+	flags uint32,
+) {
+	t.Helper()
+	offer := &state.LedgerOffer{
+		Account:   testAccountAddress(account),
+		Sequence:  seq,
+		TakerPays: takerPays,
+		TakerGets: takerGets,
+		Flags:     flags,
+		DomainID:  domain,
+	}
+	data, err := state.SerializeLedgerOffer(offer)
+	require.NoError(t, err, "serialize LedgerOffer")
+	ledger.entries[keylet.Offer(account, seq).Key] = data
+}
+
+func TestBookIndex_DomainVenues(t *testing.T) {
+	ledger := newMockLedger()
+	alice := testAccountID(1)
+	gw := testAccountID(3)
+	gwAddr := testAccountAddress(gw)
+	domain := [32]byte{0xD0, 0x01}
+	other := [32]byte{0xD0, 0x02}
+
+	// Open offer: pays XRP, gets USD
+	addOffer(t, ledger, alice, 1,
+		state.NewXRPAmountFromInt(1000000),
+		state.NewIssuedAmountFromFloat64(10, "USD", gwAddr))
+	// Domain-only offer: pays XRP, gets EUR
+	addDomainOffer(t, ledger, alice, 2,
+		state.NewXRPAmountFromInt(1000000),
+		state.NewIssuedAmountFromFloat64(10, "EUR", gwAddr), domain, 0)
+	// Hybrid offer: pays XRP, gets GBP — in the domain and the open book
+	addDomainOffer(t, ledger, alice, 3,
+		state.NewXRPAmountFromInt(1000000),
+		state.NewIssuedAmountFromFloat64(10, "GBP", gwAddr), domain, entry.OfferHybrid)
+	// Offer in an unrelated domain: pays XRP, gets JPY
+	addDomainOffer(t, ledger, alice, 4,
+		state.NewXRPAmountFromInt(1000000),
+		state.NewIssuedAmountFromFloat64(10, "JPY", gwAddr), other, 0)
+
+	currencies := func(bi *BookIndex) []string {
+		var out []string
+		for _, issue := range bi.GetBooksByTakerPays(payment.Issue{Currency: "XRP"}) {
+			out = append(out, issue.Currency)
+		}
+		return out
+	}
+
+	require.ElementsMatch(t, []string{"USD", "GBP"}, currencies(NewBookIndex(ledger)),
+		"open DEX holds open and hybrid offers")
+	require.ElementsMatch(t, []string{"EUR", "GBP"}, currencies(NewDomainBookIndex(ledger, &domain)),
+		"domain index holds only that domain's offers")
+	require.Nil(t, NewDomainBookIndex(ledger, nil).Domain())
+
+	xrpIssue := payment.Issue{Currency: "XRP"}
+	usdIssue := payment.Issue{Currency: "USD", Issuer: gw}
+	addBookDir(t, ledger, xrpIssue, usdIssue)
+	require.False(t, NewDomainBookIndex(ledger, &domain).BookExists(xrpIssue, usdIssue),
+		"domain index checks the domain's book directory")
+}
+
+// TestPathfinder_SetDomain checks that a domain-restricted search only
+// crosses that domain's books: an XRP->USD payment whose only USD book
+// lives in a domain finds a path there and none on the open DEX.
+func TestPathfinder_SetDomain(t *testing.T) {
+	ledger := newMockLedger()
+	alice := testAccountID(1)
+	bob := testAccountID(2)
+	gw := testAccountID(3)
+	gwAddr := testAccountAddress(gw)
+	domain := [32]byte{0xD0, 0x01}
+
+	addAccount(t, ledger, alice, 10000000000, 0)
+	addAccount(t, ledger, bob, 10000000000, 0)
+	addAccount(t, ledger, gw, 10000000000, 0)
+	addDomainOffer(t, ledger, gw, 1,
+		state.NewXRPAmountFromInt(1000000),
+		state.NewIssuedAmountFromFloat64(10, "USD", gwAddr), domain, 0)
+
+	dstAmount := state.NewIssuedAmountFromFloat64(1, "USD", gwAddr)
+	newPF := func() *Pathfinder {
+		return NewPathfinder(ledger, NewRippleLineCache(ledger), alice, bob,
+			dstAmount, state.NewXRPAmountFromInt(99999999999), "XRP", [20]byte{}, false)
+	}
+
+	open := newPF()
+	open.FindPaths(DefaultSearchLevel)
+	require.Empty(t, open.CompletePaths(), "no open USD book")
+
+	restricted := newPF()
+	restricted.SetDomain(&domain)
+	require.Equal(t, &domain, restricted.books.Domain())
+	restricted.FindPaths(DefaultSearchLevel)
+	require.NotEmpty(t, restricted.CompletePaths(), "domain USD book is traversed")
+}
+
 // Test 6: pathHasSeen and pathHasSeenIssue (loop detection)
 
 func TestPathHasSeen_EmptyPath(t *testing.T) {
@@ -34,6 +34,7 @@ func (pf *Pathfinder) ComputePathRanks(maxPaths int) {
 		true, // partial payment allowed (to measure liquidity)
 		false,
 		[32]byte{}, 0,
+		payment.WithDomainID(pf.domain),
 	)
 
 	// Calculate remaining amount needed after default path
@@ -132,6 +133,7 @@ func (pf *Pathfinder) getPathLiquidity(path []payment.PathStep, minAmount tx.Amo
 		pf.convertAll,
 		false,
 		[32]byte{}, 0,
+		payment.WithDomainID(pf.domain),
 	)
 
 	if result != tx.TesSUCCESS {
@@ -158,6 +160,7 @@ func (pf *Pathfinder) getPathLiquidity(path []payment.PathStep, minAmount tx.Amo
 				true, // partial payment to measure total liquidity
 				false,
 				[32]byte{}, 0,
+				payment.WithDomainID(pf.domain),
 			)
 			if extraResult == tx.TesSUCCESS {
 				totalLiquidity = totalLiquidity.Add(extraOut)
@@ -0,0 +1,269 @@
+package tx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/definitions"
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/serdes"
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/types"
+	"github.com/LeJamon/goXRPLd/internal/ledger/state"
+)
+
+// errMalformedSLE is returned when a serialized ledger entry cannot be split
+// into fields.
+var errMalformedSLE = errors.New("malformed serialized ledger entry")
+
+// fieldDecoder converts a single encoded field into its JSON value. It is
+// stateless on the decode path, so one instance is shared.
+var fieldDecoder = types.NewSTObject(serdes.NewBinarySerializer(serdes.NewFieldIDCodec(definitions.Get())))
+
+// SLE is a serialized ledger entry split into its top-level fields in
+// canonical order. ApplyStateTable decodes each tracked entry at most once
+// per transaction and shares the result between threading, owner lookup,
+// invariant collection and metadata diffing. Field values stay in their
+// wire encoding: comparing or copying a field never round-trips through
+// JSON, and the entry is re-serialized only after a field was set.
+// Reference: rippled STLedgerEntry / STObject
+type SLE struct {
+	fields    []sleField
+	entryType string
+	// data is the canonical serialization. It is stale while modified is
+	// set and rebuilt by Bytes.
+	data     []byte
+	modified bool
+}
+
+// sleField is one top-level field of an SLE.
+type sleField struct {
+	field *st.Field
+	// raw is the complete encoding of the field: header, length prefix for
+	// VL-encoded types, and value.
+	raw []byte
+	// value is the slice of raw following the header and length prefix.
+	value []byte
+}
+
+// ParseSLE splits a serialized ledger entry into its fields. The returned
+// SLE references data; callers must not modify data afterwards.
+func ParseSLE(data []byte) (*SLE, error) {
+	s := &SLE{data: data, entryType: "Unknown"}
+	r := st.NewReader(data)
+	for r.Next() {
+		f := sleField{field: r.Field(), raw: r.Raw(), value: r.Value()}
+		if f.field == st.SFLedgerEntryType {
+			s.entryType = ledgerEntryTypeName(r.UInt16())
+		}
+		s.fields = append(s.fields, f)
+	}
+	if err := r.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errMalformedSLE, err)
+	}
+	return s, nil
+}
+
+// parseSLEOrEmpty parses data, returning an SLE without fields if the entry
+// is malformed so that callers degrade gracefully: nothing is threaded and
+// no fields are reported in metadata, but the original bytes are kept.
+func parseSLEOrEmpty(data []byte) *SLE {
+	s, err := ParseSLE(data)
+	if err != nil {
+		return &SLE{data: data, entryType: getLedgerEntryType(data)}
+	}
+	return s
+}
+
+// EntryType returns the ledger entry type name, e.g. "AccountRoot".
+func (s *SLE) EntryType() string {
+	return s.entryType
+}
+
+// Bytes returns the canonical serialization, rebuilding it if a field was
+// changed since the entry was parsed.
+func (s *SLE) Bytes() []byte {
+	if !s.modified {
+		return s.data
+	}
+	size := 0
+	for _, f := range s.fields {
+		size += len(f.raw)
+	}
+	buf := make([]byte, 0, size)
+	for _, f := range s.fields {
+		buf = append(buf, f.raw...)
+	}
+	s.data, s.modified = buf, false
+	return buf
+}
+
+// IsModified reports whether a field was set since the entry was parsed.
+func (s *SLE) IsModified() bool {
+	return s.modified
+}
+
+// Clone returns a copy that can be modified without affecting s. Field
+// encodings are shared until overwritten.
+func (s *SLE) Clone() *SLE {
+	c := *s
+	c.fields = append([]sleField(nil), s.fields...)
+	return &c
+}
+
+// Has reports whether the field is present.
+func (s *SLE) Has(field *st.Field) bool {
+	return s.field(field) != nil
+}
+
+// field returns the given field, or nil if it is absent.
+func (s *SLE) field(field *st.Field) *sleField {
+	for i := range s.fields {
+		if s.fields[i].field == field {
+			return &s.fields[i]
+		}
+	}
+	return nil
+}
+
+// UInt32 returns the value of a UInt32 field.
+func (s *SLE) UInt32(field *st.Field) (uint32, bool) {
+	f := s.field(field)
+	if f == nil || len(f.value) != 4 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint32(f.value), true
+}
+
+// Hash256 returns the value of a Hash256 field.
+func (s *SLE) Hash256(field *st.Field) ([32]byte, bool) {
+	f := s.field(field)
+	if f == nil || len(f.value) != 32 {
+		return [32]byte{}, false
+	}
+	return [32]byte(f.value), true
+}
+
+// AccountID returns the value of an AccountID field.
+func (s *SLE) AccountID(field *st.Field) ([20]byte, bool) {
+	f := s.field(field)
+	if f == nil || len(f.value) != 20 {
+		return [20]byte{}, false
+	}
+	return [20]byte(f.value), true
+}
+
+// AmountIssuer returns the issuer of an issued-currency Amount field, such
+// as a trust line's LowLimit or HighLimit.
+func (s *SLE) AmountIssuer(field *st.Field) ([20]byte, bool) {
+	f := s.field(field)
+	if f == nil || len(f.value) != 48 {
+		return [20]byte{}, false
+	}
+	return [20]byte(f.value[28:48]), true
+}
+
+// IssuedAmount returns the value of an issued-currency Amount field, such as
+// an AMM's LPTokenBalance.
+func (s *SLE) IssuedAmount(field *st.Field) (state.Amount, bool) {
+	f := s.field(field)
+	if f == nil || len(f.value) != 48 {
+		return state.Amount{}, false
+	}
+	amt, err := state.ParseIOUAmountBinary(f.value)
+	if err != nil {
+		return state.Amount{}, false
+	}
+	return amt, true
+}
+
+// SetUInt32 sets a UInt32 field, adding it if absent.
+func (s *SLE) SetUInt32(field *st.Field, v uint32) error {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	return s.setFixed(field, st.TypeUInt32, b[:])
+}
+
+// SetHash256 sets a Hash256 field, adding it if absent.
+func (s *SLE) SetHash256(field *st.Field, v [32]byte) error {
+	return s.setFixed(field, st.TypeHash256, v[:])
+}
+
+// setFixed sets a field of a fixed-size type, inserting it at its canonical
+// position if absent.
+func (s *SLE) setFixed(field *st.Field, t st.Type, value []byte) error {
+	if field.Type != t {
+		return &st.TypeMismatchError{Field: field, Want: t}
+	}
+	if f := s.field(field); f != nil {
+		if bytes.Equal(f.value, value) {
+			return nil
+		}
+		headerLen := len(f.raw) - len(f.value)
+		raw := make([]byte, headerLen+len(value))
+		copy(raw, f.raw[:headerLen])
+		copy(raw[headerLen:], value)
+		f.raw, f.value = raw, raw[headerLen:]
+		s.modified = true
+		return nil
+	}
+
+	raw := append(st.AppendHeader(nil, field), value...)
+	f := sleField{field: field, raw: raw, value: raw[len(raw)-len(value):]}
+
+	i := 0
+	for i < len(s.fields) && s.fields[i].field.Ordinal() < field.Ordinal() {
+		i++
+	}
+	s.fields = append(s.fields, sleField{})
+	copy(s.fields[i+1:], s.fields[i:])
+	s.fields[i] = f
+	s.modified = true
+	return nil
+}
+
+// fieldEqual reports whether the field has the same encoding in s and
+// other. A field absent from both is equal.
+func (s *SLE) fieldEqual(other *SLE, field *st.Field) bool {
+	a, b := s.field(field), other.field(field)
+	if a == nil || b == nil {
+		return a == b
+	}
+	return bytes.Equal(a.raw, b.raw)
+}
+
+// fieldJSON decodes one field into the JSON value produced by the binary
+// codec. UInt64 fields flagged sMD_BaseTen are rendered in decimal.
+func (s *SLE) fieldJSON(f *sleField) (any, error) {
+	v, err := decodeFieldJSON(f.field, f.raw)
+	if err != nil {
+		return nil, err
+	}
+	if isBaseTenField(f.field.Name) {
+		if hexStr, ok := v.(string); ok {
+			if decStr, err := convertHexToDecimal(hexStr); err == nil {
+				v = decStr
+			}
+		}
+	}
+	return v, nil
+}
+
+// decodeFieldJSON decodes the complete encoding of one field, as returned
+// by st.Reader.Raw, into the JSON value binarycodec.Decode gives it.
+func decodeFieldJSON(field *st.Field, raw []byte) (any, error) {
+	m, err := fieldDecoder.ToJSON(serdes.NewBinaryParser(raw, definitions.Get()))
+	if err != nil {
+		return nil, err
+	}
+	return m.(map[string]any)[field.Name], nil
+}
+
+// hashString formats a 256-bit hash the way the binary codec renders Hash256
+// fields in JSON.
+func hashString(h [32]byte) string {
+	return strings.ToUpper(hex.EncodeToString(h[:]))
+}
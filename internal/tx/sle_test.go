@@ -0,0 +1,247 @@
+package tx
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+
+	binarycodec "github.com/LeJamon/goXRPLd/codec/binarycodec"
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
+	"github.com/LeJamon/goXRPLd/internal/ledger/state"
+)
+
+const (
+	sleTestAlice = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	sleTestBob   = "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe"
+	sleTestTxID  = "1C0FA22BBF5D0A8A7A105EB7D0AD7A2532863AA48584493D4BC45741AEDC4826"
+)
+
+func sleTestEntries() map[string]map[string]any {
+	return map[string]map[string]any{
+		"AccountRoot": {
+			"LedgerEntryType":   "AccountRoot",
+			"Flags":             uint32(0),
+			"Account":           sleTestAlice,
+			"Balance":           "1000000000",
+			"Sequence":          uint32(5),
+			"OwnerCount":        uint32(1),
+			"PreviousTxnID":     sleTestTxID,
+			"PreviousTxnLgrSeq": uint32(10),
+		},
+		"RippleState": {
+			"LedgerEntryType":   "RippleState",
+			"Flags":             uint32(131072),
+			"Balance":           map[string]any{"currency": "USD", "issuer": "rrrrrrrrrrrrrrrrrrrrBZbvji", "value": "10"},
+			"LowLimit":          map[string]any{"currency": "USD", "issuer": sleTestAlice, "value": "100"},
+			"HighLimit":         map[string]any{"currency": "USD", "issuer": sleTestBob, "value": "0"},
+			"LowNode":           "0",
+			"HighNode":          "0",
+			"PreviousTxnID":     sleTestTxID,
+			"PreviousTxnLgrSeq": uint32(3),
+		},
+		"SignerList": {
+			"LedgerEntryType": "SignerList",
+			"Flags":           uint32(0),
+			"OwnerNode":       "0",
+			"SignerQuorum":    uint32(2),
+			"SignerListID":    uint32(0),
+			"SignerEntries": []any{
+				map[string]any{"SignerEntry": map[string]any{"Account": sleTestAlice, "SignerWeight": 1}},
+				map[string]any{"SignerEntry": map[string]any{"Account": sleTestBob, "SignerWeight": 1}},
+			},
+		},
+		"AMM": {
+			"LedgerEntryType": "AMM",
+			"Flags":           uint32(0),
+			"Account":         sleTestAlice,
+			"Asset":           map[string]any{"currency": "XRP"},
+			"Asset2":          map[string]any{"currency": "USD", "issuer": sleTestBob},
+			"LPTokenBalance":  map[string]any{"currency": "039C99CD9AB0B70B32ECDA51EAAE471625608EA2", "issuer": sleTestAlice, "value": "100"},
+			"TradingFee":      10,
+			"OwnerNode":       "0",
+			"AuctionSlot": map[string]any{
+				"Account":    sleTestBob,
+				"Expiration": uint32(1000),
+				"Price":      map[string]any{"currency": "039C99CD9AB0B70B32ECDA51EAAE471625608EA2", "issuer": sleTestAlice, "value": "0"},
+			},
+			"VoteSlots": []any{
+				map[string]any{"VoteEntry": map[string]any{"Account": sleTestBob, "TradingFee": 10, "VoteWeight": uint32(100000)}},
+			},
+		},
+	}
+}
+
+func encodeSLETest(t *testing.T, fields map[string]any) []byte {
+	t.Helper()
+	encoded, err := binarycodec.Encode(fields)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	data, err := hex.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseSLE_MatchesCodec(t *testing.T) {
+	for name, fields := range sleTestEntries() {
+		t.Run(name, func(t *testing.T) {
+			data := encodeSLETest(t, fields)
+			sle, err := ParseSLE(data)
+			if err != nil {
+				t.Fatalf("ParseSLE: %v", err)
+			}
+			if sle.EntryType() != name {
+				t.Errorf("EntryType = %q, want %q", sle.EntryType(), name)
+			}
+			if !bytes.Equal(sle.Bytes(), data) {
+				t.Errorf("Bytes() does not round-trip")
+			}
+
+			decoded, err := binarycodec.Decode(hex.EncodeToString(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sle.fields) != len(decoded) {
+				t.Fatalf("parsed %d fields, codec decoded %d", len(sle.fields), len(decoded))
+			}
+			for i := range sle.fields {
+				f := &sle.fields[i]
+				got, err := sle.fieldJSON(f)
+				if err != nil {
+					t.Fatalf("fieldJSON(%s): %v", f.field.Name, err)
+				}
+				if want := decoded[f.field.Name]; !reflect.DeepEqual(got, want) {
+					t.Errorf("%s = %#v, want %#v", f.field.Name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSLE_Malformed(t *testing.T) {
+	data := encodeSLETest(t, sleTestEntries()["AccountRoot"])
+	if _, err := ParseSLE(data[:len(data)-3]); err == nil {
+		t.Fatal("expected error for truncated entry")
+	}
+
+	sle := parseSLEOrEmpty(data[:len(data)-3])
+	if sle.EntryType() != "AccountRoot" {
+		t.Errorf("EntryType = %q, want AccountRoot", sle.EntryType())
+	}
+	if _, _, changed := threadItem(sle, [32]byte{1}, 20); changed {
+		t.Error("malformed entry must not be threaded")
+	}
+}
+
+func TestThreadItem(t *testing.T) {
+	var txHash [32]byte
+	txHash[0] = 0xAA
+
+	t.Run("replaces existing", func(t *testing.T) {
+		fields := sleTestEntries()["AccountRoot"]
+		sle, err := ParseSLE(encodeSLETest(t, fields))
+		if err != nil {
+			t.Fatal(err)
+		}
+		prevID, prevSeq, changed := threadItem(sle, txHash, 20)
+		if !changed {
+			t.Fatal("expected entry to be threaded")
+		}
+		if hashString(prevID) != sleTestTxID || prevSeq != 10 {
+			t.Errorf("previous = %X/%d, want %s/10", prevID, prevSeq, sleTestTxID)
+		}
+
+		fields["PreviousTxnID"] = hashString(txHash)
+		fields["PreviousTxnLgrSeq"] = uint32(20)
+		if want := encodeSLETest(t, fields); !bytes.Equal(sle.Bytes(), want) {
+			t.Errorf("threaded entry differs from codec encoding")
+		}
+
+		if _, _, changed := threadItem(sle, txHash, 20); changed {
+			t.Error("threading twice to the same transaction must be a no-op")
+		}
+	})
+
+	t.Run("adds missing", func(t *testing.T) {
+		fields := sleTestEntries()["SignerList"]
+		sle, err := ParseSLE(encodeSLETest(t, fields))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, changed := threadItem(sle, txHash, 7); !changed {
+			t.Fatal("expected entry to be threaded")
+		}
+		fields["PreviousTxnID"] = hashString(txHash)
+		fields["PreviousTxnLgrSeq"] = uint32(7)
+		if want := encodeSLETest(t, fields); !bytes.Equal(sle.Bytes(), want) {
+			t.Errorf("threaded entry differs from codec encoding")
+		}
+	})
+}
+
+func TestGetOwnerAccounts(t *testing.T) {
+	alice, _ := state.DecodeAccountID(sleTestAlice)
+	bob, _ := state.DecodeAccountID(sleTestBob)
+
+	entries := sleTestEntries()
+	check := map[string]any{
+		"LedgerEntryType":   "Check",
+		"Flags":             uint32(0),
+		"Account":           sleTestAlice,
+		"Destination":       sleTestBob,
+		"SendMax":           "100",
+		"Sequence":          uint32(1),
+		"OwnerNode":         "0",
+		"DestinationNode":   "0",
+		"PreviousTxnID":     sleTestTxID,
+		"PreviousTxnLgrSeq": uint32(1),
+	}
+
+	tests := []struct {
+		name              string
+		fields            map[string]any
+		fixCheckThreading bool
+		want              [][20]byte
+	}{
+		{"AccountRoot", entries["AccountRoot"], true, nil},
+		{"RippleState", entries["RippleState"], true, [][20]byte{alice, bob}},
+		{"Check", check, true, [][20]byte{alice, bob}},
+		{"CheckWithoutFix", check, false, [][20]byte{alice}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sle, err := ParseSLE(encodeSLETest(t, tt.fields))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := getOwnerAccounts(sle, tt.fixCheckThreading); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("owners = %X, want %X", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSLE_CloneIsIndependent(t *testing.T) {
+	data := encodeSLETest(t, sleTestEntries()["AccountRoot"])
+	orig, err := ParseSLE(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone := orig.Clone()
+	if err := clone.SetUInt32(st.SFSequence, 6); err != nil {
+		t.Fatal(err)
+	}
+	if seq, _ := orig.UInt32(st.SFSequence); seq != 5 {
+		t.Errorf("original Sequence = %d, want 5", seq)
+	}
+	if orig.IsModified() || !bytes.Equal(orig.Bytes(), data) {
+		t.Error("original entry was modified through its clone")
+	}
+	if err := clone.SetHash256(st.SFSequence, [32]byte{}); err == nil || !strings.Contains(err.Error(), "UInt32") {
+		t.Errorf("expected type mismatch error, got %v", err)
+	}
+}
@@ -3,6 +3,8 @@ package entry
 import (
 	"encoding/binary"
 	"errors"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 )
 
 // AccountRoot represents an account in the ledger
@@ -20,6 +22,18 @@ type AccountRoot struct {
 	TickSize             *uint8
 	TransferRate         *uint32
 	FirstNFTokenSequence *uint32
+	AccountTxnID         *[32]byte
+	WalletLocator        *[32]byte
+	WalletSize           *uint32
+	MessageKey           *[]byte
+	TicketCount          *uint32
+	NFTokenMinter        *[20]byte
+	AMMID                *[32]byte
+	VaultID              *[32]byte
+
+	// Default fields (omitted when zero)
+	MintedNFTokens uint32
+	BurnedNFTokens uint32
 }
 
 func (a *AccountRoot) Type() Type {
@@ -60,3 +74,118 @@ func (a *AccountRoot) Hash() ([32]byte, error) {
 
 	return hash, nil
 }
+
+// MarshalBinary encodes the entry in canonical binary form. PreviousTxnID
+// and PreviousTxnLgrSeq are required on an AccountRoot and are written even
+// before the account has been threaded, as in the genesis ledger.
+func (a *AccountRoot) MarshalBinary() ([]byte, error) {
+	w := newEntryWriter(TypeAccountRoot)
+	w.UInt32(st.SFFlags, a.Flags)
+	w.UInt32(st.SFSequence, a.Sequence)
+	w.UInt32(st.SFPreviousTxnLgrSeq, a.PreviousTxnLgrSeq)
+	if a.TransferRate != nil {
+		w.UInt32(st.SFTransferRate, *a.TransferRate)
+	}
+	if a.WalletSize != nil {
+		w.UInt32(st.SFWalletSize, *a.WalletSize)
+	}
+	w.UInt32(st.SFOwnerCount, a.OwnerCount)
+	if a.TicketCount != nil {
+		w.UInt32(st.SFTicketCount, *a.TicketCount)
+	}
+	if a.MintedNFTokens != 0 {
+		w.UInt32(st.SFMintedNFTokens, a.MintedNFTokens)
+	}
+	if a.BurnedNFTokens != 0 {
+		w.UInt32(st.SFBurnedNFTokens, a.BurnedNFTokens)
+	}
+	if a.FirstNFTokenSequence != nil {
+		w.UInt32(st.SFFirstNFTokenSequence, *a.FirstNFTokenSequence)
+	}
+	if a.EmailHash != nil {
+		w.Hash128(st.SFEmailHash, *a.EmailHash)
+	}
+	w.Hash256(st.SFPreviousTxnID, a.PreviousTxnID)
+	if a.WalletLocator != nil {
+		w.Hash256(st.SFWalletLocator, *a.WalletLocator)
+	}
+	if a.AccountTxnID != nil {
+		w.Hash256(st.SFAccountTxnID, *a.AccountTxnID)
+	}
+	if a.AMMID != nil {
+		w.Hash256(st.SFAMMID, *a.AMMID)
+	}
+	if a.VaultID != nil {
+		w.Hash256(st.SFVaultID, *a.VaultID)
+	}
+	w.Amount(st.SFBalance, st.XRPAmount(a.Balance))
+	if a.MessageKey != nil {
+		w.Blob(st.SFMessageKey, *a.MessageKey)
+	}
+	if a.Domain != nil {
+		w.Blob(st.SFDomain, []byte(*a.Domain))
+	}
+	w.AccountID(st.SFAccount, a.Account)
+	if a.RegularKey != nil {
+		w.AccountID(st.SFRegularKey, *a.RegularKey)
+	}
+	if a.NFTokenMinter != nil {
+		w.AccountID(st.SFNFTokenMinter, *a.NFTokenMinter)
+	}
+	if a.TickSize != nil {
+		w.UInt8(st.SFTickSize, *a.TickSize)
+	}
+	return w.Bytes()
+}
+
+// UnmarshalBinary decodes an AccountRoot from its binary form.
+func (a *AccountRoot) UnmarshalBinary(data []byte) error {
+	*a = AccountRoot{}
+	return readEntry(data, TypeAccountRoot, &a.BaseEntry, func(r *st.Reader) (err error) {
+		switch r.Field() {
+		case st.SFAccount:
+			a.Account = r.AccountID()
+		case st.SFSequence:
+			a.Sequence = r.UInt32()
+		case st.SFBalance:
+			a.Balance, err = readDrops(r)
+		case st.SFOwnerCount:
+			a.OwnerCount = r.UInt32()
+		case st.SFDomain:
+			a.Domain = ptr(string(r.Blob()))
+		case st.SFEmailHash:
+			a.EmailHash = ptr(r.Hash128())
+		case st.SFRegularKey:
+			a.RegularKey = ptr(r.AccountID())
+		case st.SFTickSize:
+			a.TickSize = ptr(r.UInt8())
+		case st.SFTransferRate:
+			a.TransferRate = ptr(r.UInt32())
+		case st.SFFirstNFTokenSequence:
+			a.FirstNFTokenSequence = ptr(r.UInt32())
+		case st.SFAccountTxnID:
+			a.AccountTxnID = ptr(r.Hash256())
+		case st.SFWalletLocator:
+			a.WalletLocator = ptr(r.Hash256())
+		case st.SFWalletSize:
+			a.WalletSize = ptr(r.UInt32())
+		case st.SFMessageKey:
+			a.MessageKey = ptr(readBlob(r))
+		case st.SFTicketCount:
+			a.TicketCount = ptr(r.UInt32())
+		case st.SFNFTokenMinter:
+			a.NFTokenMinter = ptr(r.AccountID())
+		case st.SFMintedNFTokens:
+			a.MintedNFTokens = r.UInt32()
+		case st.SFBurnedNFTokens:
+			a.BurnedNFTokens = r.UInt32()
+		case st.SFAMMID:
+			a.AMMID = ptr(r.Hash256())
+		case st.SFVaultID:
+			a.VaultID = ptr(r.Hash256())
+		default:
+			return unexpectedField(r)
+		}
+		return err
+	})
+}
@@ -2,6 +2,9 @@ package entry
 
 import (
 	"errors"
+	"fmt"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 )
 
 // VoteSlot represents a vote slot in an AMM
@@ -70,3 +73,167 @@ func (a *AMM) Hash() ([32]byte, error) {
 	}
 	return hash, nil
 }
+
+// MarshalBinary encodes the entry in canonical binary form. TradingFee and
+// the DiscountedFee of the auction slot are omitted when zero.
+func (a *AMM) MarshalBinary() ([]byte, error) {
+	lpTokens, err := stAmount(a.LPTokenBalance)
+	if err != nil {
+		return nil, err
+	}
+	w := newEntryWriter(TypeAMM)
+	if a.TradingFee != 0 {
+		w.UInt16(st.SFTradingFee, a.TradingFee)
+	}
+	w.UInt32(st.SFFlags, a.Flags)
+	if a.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, a.PreviousTxnLgrSeq)
+	}
+	w.UInt64(st.SFOwnerNode, a.OwnerNode)
+	if a.threaded() {
+		w.Hash256(st.SFPreviousTxnID, a.PreviousTxnID)
+	}
+	w.Amount(st.SFLPTokenBalance, lpTokens)
+	w.AccountID(st.SFAccount, a.Account)
+	if a.AuctionSlot != nil {
+		if err := a.AuctionSlot.write(w); err != nil {
+			return nil, err
+		}
+	}
+	if len(a.VoteSlots) > 0 {
+		w.BeginArray(st.SFVoteSlots)
+		for _, v := range a.VoteSlots {
+			w.BeginObject(st.SFVoteEntry)
+			if v.TradingFee != 0 {
+				w.UInt16(st.SFTradingFee, v.TradingFee)
+			}
+			w.UInt32(st.SFVoteWeight, v.VoteWeight)
+			w.AccountID(st.SFAccount, v.Account)
+			w.EndObject()
+		}
+		w.EndArray()
+	}
+	w.Issue(st.SFAsset, stIssue(a.Asset))
+	w.Issue(st.SFAsset2, stIssue(a.Asset2))
+	return w.Bytes()
+}
+
+// write encodes the auction slot as the AuctionSlot inner object.
+func (s *AuctionSlot) write(w *st.Writer) error {
+	if s.DiscountedFee > 0xFFFF {
+		return fmt.Errorf("discounted fee %d does not fit UInt16", s.DiscountedFee)
+	}
+	price, err := stAmount(s.Price)
+	if err != nil {
+		return err
+	}
+	w.BeginObject(st.SFAuctionSlot)
+	if s.DiscountedFee != 0 {
+		w.UInt16(st.SFDiscountedFee, uint16(s.DiscountedFee))
+	}
+	w.UInt32(st.SFExpiration, s.Expiration)
+	w.Amount(st.SFPrice, price)
+	w.AccountID(st.SFAccount, s.Account)
+	if len(s.AuthAccounts) > 0 {
+		w.BeginArray(st.SFAuthAccounts)
+		for _, account := range s.AuthAccounts {
+			w.BeginObject(st.SFAuthAccount)
+			w.AccountID(st.SFAccount, account)
+			w.EndObject()
+		}
+		w.EndArray()
+	}
+	w.EndObject()
+	return nil
+}
+
+// UnmarshalBinary decodes an AMM from its binary form.
+func (a *AMM) UnmarshalBinary(data []byte) error {
+	*a = AMM{}
+	return readEntry(data, TypeAMM, &a.BaseEntry, func(r *st.Reader) (err error) {
+		switch r.Field() {
+		case st.SFAccount:
+			a.Account = r.AccountID()
+		case st.SFTradingFee:
+			a.TradingFee = r.UInt16()
+		case st.SFOwnerNode:
+			a.OwnerNode = r.UInt64()
+		case st.SFLPTokenBalance:
+			a.LPTokenBalance, err = readAmount(r)
+		case st.SFAsset:
+			a.Asset, err = readIssue(r.Issue())
+		case st.SFAsset2:
+			a.Asset2, err = readIssue(r.Issue())
+		case st.SFAuctionSlot:
+			a.AuctionSlot, err = readAuctionSlot(r)
+		case st.SFVoteSlots:
+			slots := r.Array()
+			for slots.Next() {
+				var v VoteSlot
+				fields := slots.Object()
+				for fields.Next() {
+					switch fields.Field() {
+					case st.SFAccount:
+						v.Account = fields.AccountID()
+					case st.SFTradingFee:
+						v.TradingFee = fields.UInt16()
+					case st.SFVoteWeight:
+						v.VoteWeight = fields.UInt32()
+					default:
+						return unexpectedField(&fields)
+					}
+				}
+				if err := fields.Err(); err != nil {
+					return err
+				}
+				a.VoteSlots = append(a.VoteSlots, v)
+			}
+			err = slots.Err()
+		default:
+			return unexpectedField(r)
+		}
+		return err
+	})
+}
+
+// readAuctionSlot returns the value of the AuctionSlot inner object.
+func readAuctionSlot(r *st.Reader) (*AuctionSlot, error) {
+	s := &AuctionSlot{}
+	fields := r.Object()
+	for fields.Next() {
+		switch fields.Field() {
+		case st.SFAccount:
+			s.Account = fields.AccountID()
+		case st.SFExpiration:
+			s.Expiration = fields.UInt32()
+		case st.SFDiscountedFee:
+			s.DiscountedFee = uint32(fields.UInt16())
+		case st.SFPrice:
+			price, err := readAmount(&fields)
+			if err != nil {
+				return nil, err
+			}
+			s.Price = price
+		case st.SFAuthAccounts:
+			accounts := fields.Array()
+			for accounts.Next() {
+				account := accounts.Object()
+				for account.Next() {
+					if account.Field() != st.SFAccount {
+						return nil, unexpectedField(&account)
+					}
+					s.AuthAccounts = append(s.AuthAccounts, account.AccountID())
+				}
+				if err := account.Err(); err != nil {
+					return nil, err
+				}
+			}
+			if err := accounts.Err(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, unexpectedField(&fields)
+		}
+	}
+	return s, fields.Err()
+}
@@ -0,0 +1,346 @@
+package entry
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
+)
+
+// Binary encoding
+//
+// Entries that map one-to-one onto serialized fields implement
+// encoding.BinaryMarshaler and encoding.BinaryUnmarshaler on top of the st
+// codec. MarshalBinary writes fields in canonical order, so its output is
+// the same as encoding the entry's JSON form with binarycodec.Encode.
+// UnmarshalBinary fails on fields the struct does not model rather than
+// dropping them, so a decoded entry always re-encodes to its input.
+// Reference: rippled STLedgerEntry, ledger_entries.macro
+
+// threaded reports whether the entry carries PreviousTxnID and
+// PreviousTxnLgrSeq. They are optional on singletons such as FeeSettings
+// and are written only once the entry has been threaded.
+func (b *BaseEntry) threaded() bool {
+	return b.PreviousTxnID != [32]byte{} || b.PreviousTxnLgrSeq != 0
+}
+
+// newEntryWriter starts the encoding of an entry of type t.
+func newEntryWriter(t Type) *st.Writer {
+	w := st.NewWriter(make([]byte, 0, 256))
+	w.UInt16(st.SFLedgerEntryType, uint16(t))
+	return w
+}
+
+// readEntry decodes data as a ledger entry of type t. The fields common to
+// all entries are stored in base; every other field is passed to field,
+// which returns unexpectedField for fields it does not model.
+func readEntry(data []byte, t Type, base *BaseEntry, field func(r *st.Reader) error) error {
+	seenType := false
+	r := st.NewReader(data)
+	for r.Next() {
+		switch r.Field() {
+		case st.SFLedgerEntryType:
+			if got := Type(r.UInt16()); got != t {
+				return fmt.Errorf("decoding %s: ledger entry type is %s", t, got)
+			}
+			seenType = true
+		case st.SFFlags:
+			base.Flags = r.UInt32()
+		case st.SFPreviousTxnID:
+			base.PreviousTxnID = r.Hash256()
+		case st.SFPreviousTxnLgrSeq:
+			base.PreviousTxnLgrSeq = r.UInt32()
+		default:
+			if err := field(&r); err != nil {
+				return fmt.Errorf("decoding %s: %w", t, err)
+			}
+		}
+	}
+	if err := r.Err(); err != nil {
+		return fmt.Errorf("decoding %s: %w", t, err)
+	}
+	if !seenType {
+		return fmt.Errorf("decoding %s: missing LedgerEntryType", t)
+	}
+	return nil
+}
+
+// unexpectedField returns the error for a field the entry does not model.
+func unexpectedField(r *st.Reader) error {
+	return fmt.Errorf("unexpected field %s", r.Field())
+}
+
+// readDrops returns the value of an XRP Amount field in drops.
+func readDrops(r *st.Reader) (uint64, error) {
+	a := r.Amount()
+	if a.Kind != st.AmountXRP || a.Negative {
+		return 0, fmt.Errorf("%s is not a positive XRP amount", r.Field())
+	}
+	return a.Mantissa, nil
+}
+
+// readIssue returns the value of an Issue, rejecting MPT issues which
+// Issue cannot represent.
+func readIssue(is st.Issue) (Issue, error) {
+	if is.MPT {
+		return Issue{}, fmt.Errorf("MPT issue is not supported")
+	}
+	return Issue{Currency: is.Currency, Issuer: is.Issuer}, nil
+}
+
+// stIssue returns the st form of is.
+func stIssue(is Issue) st.Issue {
+	return st.Issue{Currency: is.Currency, Issuer: is.Issuer}
+}
+
+// stXChainBridge returns the st form of x.
+func stXChainBridge(x XChainBridge) st.XChainBridge {
+	return st.XChainBridge{
+		LockingChainDoor:  x.LockingChainDoor,
+		LockingChainIssue: stIssue(x.LockingChainIssue),
+		IssuingChainDoor:  x.IssuingChainDoor,
+		IssuingChainIssue: stIssue(x.IssuingChainIssue),
+	}
+}
+
+// readXChainBridge returns the value of an XChainBridge field.
+func readXChainBridge(r *st.Reader) (XChainBridge, error) {
+	x := r.XChainBridge()
+	locking, err := readIssue(x.LockingChainIssue)
+	if err != nil {
+		return XChainBridge{}, err
+	}
+	issuing, err := readIssue(x.IssuingChainIssue)
+	if err != nil {
+		return XChainBridge{}, err
+	}
+	return XChainBridge{
+		LockingChainDoor:  x.LockingChainDoor,
+		LockingChainIssue: locking,
+		IssuingChainDoor:  x.IssuingChainDoor,
+		IssuingChainIssue: issuing,
+	}, nil
+}
+
+// IOU mantissas are normalized to [10^15, 10^16) with an exponent in
+// [-96, 80].
+// Reference: rippled STAmount::canonicalize
+const (
+	iouMinMantissa = 1_000_000_000_000_000
+	iouMaxMantissa = 9_999_999_999_999_999
+	iouMinExponent = -96
+	iouMaxExponent = 80
+)
+
+// stAmount returns the st form of a. The Value of an issued currency
+// amount is parsed as a decimal and normalized.
+func stAmount(a Amount) (st.Amount, error) {
+	if a.IsNative {
+		return st.XRPAmount(a.Drops), nil
+	}
+	out := st.Amount{Kind: st.AmountIOU, Currency: a.Currency, Issuer: a.Issuer}
+	s := a.Value
+	if strings.HasPrefix(s, "-") {
+		out.Negative = true
+		s = s[1:]
+	}
+	mantissa, exponent, err := parseDecimal(s)
+	if err != nil {
+		return st.Amount{}, fmt.Errorf("amount %q: %w", a.Value, err)
+	}
+	if mantissa == 0 {
+		return st.Amount{Kind: st.AmountIOU, Currency: a.Currency, Issuer: a.Issuer}, nil
+	}
+	for mantissa < iouMinMantissa {
+		mantissa *= 10
+		exponent--
+	}
+	for mantissa > iouMaxMantissa {
+		if mantissa%10 != 0 {
+			return st.Amount{}, fmt.Errorf("amount %q has more than 16 significant digits", a.Value)
+		}
+		mantissa /= 10
+		exponent++
+	}
+	if exponent < iouMinExponent || exponent > iouMaxExponent {
+		return st.Amount{}, fmt.Errorf("amount %q is out of range", a.Value)
+	}
+	out.Mantissa, out.Exponent = mantissa, exponent
+	return out, nil
+}
+
+// parseDecimal parses an unsigned decimal such as "12.5" or "1e-3" into
+// mantissa * 10^exponent.
+func parseDecimal(s string) (mantissa uint64, exponent int32, err error) {
+	digits, exp, hasExp := strings.Cut(strings.ToLower(s), "e")
+	if hasExp {
+		e, err := strconv.ParseInt(exp, 10, 32)
+		if err != nil {
+			return 0, 0, err
+		}
+		exponent = int32(e)
+	}
+	whole, frac, _ := strings.Cut(digits, ".")
+	digits = strings.TrimLeft(whole+frac, "0")
+	exponent -= int32(len(frac))
+	if whole+frac == "" {
+		return 0, 0, errors.New("no digits")
+	}
+	if digits == "" {
+		return 0, 0, nil
+	}
+	for len(digits) > 1 && digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+		exponent++
+	}
+	mantissa, err = strconv.ParseUint(digits, 10, 64)
+	return mantissa, exponent, err
+}
+
+// formatDecimal formats mantissa * 10^exponent as a plain decimal.
+func formatDecimal(mantissa uint64, exponent int32) string {
+	if mantissa == 0 {
+		return "0"
+	}
+	for mantissa%10 == 0 {
+		mantissa /= 10
+		exponent++
+	}
+	digits := strconv.FormatUint(mantissa, 10)
+	if exponent >= 0 {
+		return digits + strings.Repeat("0", int(exponent))
+	}
+	if n := int(-exponent); n < len(digits) {
+		return digits[:len(digits)-n] + "." + digits[len(digits)-n:]
+	}
+	return "0." + strings.Repeat("0", int(-exponent)-len(digits)) + digits
+}
+
+// readAmount returns the value of an XRP or issued currency Amount field.
+func readAmount(r *st.Reader) (Amount, error) {
+	a := r.Amount()
+	switch a.Kind {
+	case st.AmountXRP:
+		if a.Negative {
+			return Amount{}, fmt.Errorf("%s is a negative XRP amount", r.Field())
+		}
+		return Amount{IsNative: true, Drops: a.Mantissa}, nil
+	case st.AmountIOU:
+		value := formatDecimal(a.Mantissa, a.Exponent)
+		if a.Negative && a.Mantissa != 0 {
+			value = "-" + value
+		}
+		return Amount{Value: value, Currency: a.Currency, Issuer: a.Issuer}, nil
+	default:
+		return Amount{}, fmt.Errorf("%s is an MPT amount, which Amount cannot represent", r.Field())
+	}
+}
+
+// stNumber returns v as a normalized Number, or an error if v has more
+// significant digits than a Number holds.
+// Reference: rippled Number::normalize
+func stNumber(v int64) (st.Number, error) {
+	if v == 0 {
+		return st.Number{Exponent: math.MinInt32}, nil
+	}
+	m, e := v, int32(0)
+	for abs64(m) < iouMinMantissa {
+		m *= 10
+		e--
+	}
+	for abs64(m) > iouMaxMantissa {
+		if m%10 != 0 {
+			return st.Number{}, fmt.Errorf("%d has more than 16 significant digits", v)
+		}
+		m /= 10
+		e++
+	}
+	return st.Number{Mantissa: m, Exponent: e}, nil
+}
+
+// readInteger returns the value of a Number field holding an integer.
+func readInteger(r *st.Reader) (int64, error) {
+	n := r.Number()
+	if n.Mantissa == 0 {
+		return 0, nil
+	}
+	v := n.Mantissa
+	for e := n.Exponent; e < 0; e++ {
+		if v%10 != 0 {
+			return 0, fmt.Errorf("%s is not an integer", r.Field())
+		}
+		v /= 10
+	}
+	for e := n.Exponent; e > 0; e-- {
+		if abs64(v) > math.MaxInt64/10 {
+			return 0, fmt.Errorf("%s overflows int64", r.Field())
+		}
+		v *= 10
+	}
+	return v, nil
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// currencyCode parses a currency written as "XRP", a three-character ISO
+// code or 40 hex digits.
+func currencyCode(s string) ([20]byte, error) {
+	var c [20]byte
+	switch {
+	case s == "XRP":
+		return c, nil
+	case len(s) == 3:
+		copy(c[12:], s)
+		return c, nil
+	case len(s) == 40:
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return c, fmt.Errorf("currency %q: %w", s, err)
+		}
+		return [20]byte(b), nil
+	default:
+		return c, fmt.Errorf("invalid currency %q", s)
+	}
+}
+
+// currencyString formats c the way currencyCode parses it.
+func currencyString(c [20]byte) string {
+	if c == ([20]byte{}) {
+		return "XRP"
+	}
+	if [12]byte(c[:12]) == [12]byte{} && [5]byte(c[15:]) == [5]byte{} && string(c[12:15]) != "XRP" {
+		return string(c[12:15])
+	}
+	return strings.ToUpper(hex.EncodeToString(c[:]))
+}
+
+// readPublicKey returns the value of a Blob field holding a 33-byte public
+// key.
+func readPublicKey(r *st.Reader) ([33]byte, error) {
+	b := r.Blob()
+	if len(b) != 33 {
+		return [33]byte{}, fmt.Errorf("%s is %d bytes, want 33", r.Field(), len(b))
+	}
+	return [33]byte(b), nil
+}
+
+// readBlob returns a copy of the value of a Blob field. Reader values alias
+// the input, which callers may reuse.
+func readBlob(r *st.Reader) []byte {
+	return bytes.Clone(r.Blob())
+}
+
+// ptr returns a pointer to a copy of v, for optional fields.
+func ptr[T any](v T) *T {
+	return &v
+}
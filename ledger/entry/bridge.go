@@ -2,6 +2,8 @@ package entry
 
 import (
 	"errors"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 )
 
 // XChainBridge represents the bridge specification
@@ -57,3 +59,56 @@ func (b *Bridge) Hash() ([32]byte, error) {
 	}
 	return hash, nil
 }
+
+// MarshalBinary encodes the entry in canonical binary form.
+func (b *Bridge) MarshalBinary() ([]byte, error) {
+	w := newEntryWriter(TypeBridge)
+	w.UInt32(st.SFFlags, b.Flags)
+	if b.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, b.PreviousTxnLgrSeq)
+	}
+	w.UInt64(st.SFOwnerNode, b.OwnerNode)
+	w.UInt64(st.SFXChainClaimID, b.XChainClaimID)
+	w.UInt64(st.SFXChainAccountCreateCount, b.XChainAccountCreateCount)
+	w.UInt64(st.SFXChainAccountClaimCount, b.XChainAccountClaimCount)
+	if b.threaded() {
+		w.Hash256(st.SFPreviousTxnID, b.PreviousTxnID)
+	}
+	w.Amount(st.SFSignatureReward, st.XRPAmount(b.SignatureReward))
+	if b.MinAccountCreateAmount != nil {
+		w.Amount(st.SFMinAccountCreateAmount, st.XRPAmount(*b.MinAccountCreateAmount))
+	}
+	w.AccountID(st.SFAccount, b.Account)
+	w.XChainBridge(st.SFXChainBridge, stXChainBridge(b.XChainBridge))
+	return w.Bytes()
+}
+
+// UnmarshalBinary decodes a Bridge from its binary form.
+func (b *Bridge) UnmarshalBinary(data []byte) error {
+	*b = Bridge{}
+	return readEntry(data, TypeBridge, &b.BaseEntry, func(r *st.Reader) (err error) {
+		switch r.Field() {
+		case st.SFAccount:
+			b.Account = r.AccountID()
+		case st.SFSignatureReward:
+			b.SignatureReward, err = readDrops(r)
+		case st.SFMinAccountCreateAmount:
+			var v uint64
+			v, err = readDrops(r)
+			b.MinAccountCreateAmount = &v
+		case st.SFXChainBridge:
+			b.XChainBridge, err = readXChainBridge(r)
+		case st.SFXChainClaimID:
+			b.XChainClaimID = r.UInt64()
+		case st.SFXChainAccountCreateCount:
+			b.XChainAccountCreateCount = r.UInt64()
+		case st.SFXChainAccountClaimCount:
+			b.XChainAccountClaimCount = r.UInt64()
+		case st.SFOwnerNode:
+			b.OwnerNode = r.UInt64()
+		default:
+			return unexpectedField(r)
+		}
+		return err
+	})
+}
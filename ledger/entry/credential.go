@@ -2,6 +2,8 @@ package entry
 
 import (
 	"errors"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 )
 
 // Credential represents a verifiable credential ledger entry
@@ -49,3 +51,53 @@ func (c *Credential) Hash() ([32]byte, error) {
 	}
 	return hash, nil
 }
+
+// MarshalBinary encodes the entry in canonical binary form.
+func (c *Credential) MarshalBinary() ([]byte, error) {
+	w := newEntryWriter(TypeCredential)
+	w.UInt32(st.SFFlags, c.Flags)
+	if c.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, c.PreviousTxnLgrSeq)
+	}
+	if c.Expiration != nil {
+		w.UInt32(st.SFExpiration, *c.Expiration)
+	}
+	w.UInt64(st.SFIssuerNode, c.IssuerNode)
+	w.UInt64(st.SFSubjectNode, c.SubjectNode)
+	if c.threaded() {
+		w.Hash256(st.SFPreviousTxnID, c.PreviousTxnID)
+	}
+	if c.URI != nil {
+		w.Blob(st.SFURI, []byte(*c.URI))
+	}
+	w.Blob(st.SFCredentialType, c.CredentialType)
+	w.AccountID(st.SFIssuer, c.Issuer)
+	w.AccountID(st.SFSubject, c.Subject)
+	return w.Bytes()
+}
+
+// UnmarshalBinary decodes a Credential from its binary form.
+func (c *Credential) UnmarshalBinary(data []byte) error {
+	*c = Credential{}
+	return readEntry(data, TypeCredential, &c.BaseEntry, func(r *st.Reader) error {
+		switch r.Field() {
+		case st.SFSubject:
+			c.Subject = r.AccountID()
+		case st.SFIssuer:
+			c.Issuer = r.AccountID()
+		case st.SFCredentialType:
+			c.CredentialType = readBlob(r)
+		case st.SFIssuerNode:
+			c.IssuerNode = r.UInt64()
+		case st.SFSubjectNode:
+			c.SubjectNode = r.UInt64()
+		case st.SFExpiration:
+			c.Expiration = ptr(r.UInt32())
+		case st.SFURI:
+			c.URI = ptr(string(r.Blob()))
+		default:
+			return unexpectedField(r)
+		}
+		return nil
+	})
+}
@@ -2,6 +2,8 @@ package entry
 
 import (
 	"errors"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 )
 
 // DelegatePermission represents a single delegated permission.
@@ -71,3 +73,64 @@ func (d *Delegate) HasTxPermission(txType uint32) bool {
 	}
 	return false
 }
+
+// MarshalBinary encodes the entry in canonical binary form. Only
+// DelegatePermissions is encoded: permissions given by name in the legacy
+// Permissions field are rejected.
+func (d *Delegate) MarshalBinary() ([]byte, error) {
+	if len(d.Permissions) > 0 {
+		return nil, errors.New("permissions given by name cannot be encoded, use DelegatePermissions")
+	}
+	w := newEntryWriter(TypeDelegate)
+	w.UInt32(st.SFFlags, d.Flags)
+	if d.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, d.PreviousTxnLgrSeq)
+	}
+	w.UInt64(st.SFOwnerNode, d.OwnerNode)
+	if d.threaded() {
+		w.Hash256(st.SFPreviousTxnID, d.PreviousTxnID)
+	}
+	w.AccountID(st.SFAccount, d.Account)
+	w.AccountID(st.SFAuthorize, d.Authorize)
+	w.BeginArray(st.SFPermissions)
+	for _, p := range d.DelegatePermissions {
+		w.BeginObject(st.SFPermission)
+		w.UInt32(st.SFPermissionValue, p.PermissionValue)
+		w.EndObject()
+	}
+	w.EndArray()
+	return w.Bytes()
+}
+
+// UnmarshalBinary decodes a Delegate from its binary form.
+func (d *Delegate) UnmarshalBinary(data []byte) error {
+	*d = Delegate{}
+	return readEntry(data, TypeDelegate, &d.BaseEntry, func(r *st.Reader) error {
+		switch r.Field() {
+		case st.SFAccount:
+			d.Account = r.AccountID()
+		case st.SFAuthorize:
+			d.Authorize = r.AccountID()
+		case st.SFOwnerNode:
+			d.OwnerNode = r.UInt64()
+		case st.SFPermissions:
+			permissions := r.Array()
+			for permissions.Next() {
+				fields := permissions.Object()
+				for fields.Next() {
+					if fields.Field() != st.SFPermissionValue {
+						return unexpectedField(&fields)
+					}
+					d.DelegatePermissions = append(d.DelegatePermissions, DelegatePermission{PermissionValue: fields.UInt32()})
+				}
+				if err := fields.Err(); err != nil {
+					return err
+				}
+			}
+			return permissions.Err()
+		default:
+			return unexpectedField(r)
+		}
+		return nil
+	})
+}
@@ -2,6 +2,8 @@ package entry
 
 import (
 	"errors"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 )
 
 // DID represents a Decentralized Identifier ledger entry
@@ -41,3 +43,49 @@ func (d *DID) Hash() ([32]byte, error) {
 	}
 	return hash, nil
 }
+
+// MarshalBinary encodes the entry in canonical binary form.
+func (d *DID) MarshalBinary() ([]byte, error) {
+	w := newEntryWriter(TypeDID)
+	w.UInt32(st.SFFlags, d.Flags)
+	if d.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, d.PreviousTxnLgrSeq)
+	}
+	w.UInt64(st.SFOwnerNode, d.OwnerNode)
+	if d.threaded() {
+		w.Hash256(st.SFPreviousTxnID, d.PreviousTxnID)
+	}
+	if d.URI != nil {
+		w.Blob(st.SFURI, []byte(*d.URI))
+	}
+	if d.DIDDocument != nil {
+		w.Blob(st.SFDIDDocument, *d.DIDDocument)
+	}
+	if d.Data != nil {
+		w.Blob(st.SFData, *d.Data)
+	}
+	w.AccountID(st.SFAccount, d.Account)
+	return w.Bytes()
+}
+
+// UnmarshalBinary decodes a DID from its binary form.
+func (d *DID) UnmarshalBinary(data []byte) error {
+	*d = DID{}
+	return readEntry(data, TypeDID, &d.BaseEntry, func(r *st.Reader) error {
+		switch r.Field() {
+		case st.SFAccount:
+			d.Account = r.AccountID()
+		case st.SFOwnerNode:
+			d.OwnerNode = r.UInt64()
+		case st.SFURI:
+			d.URI = ptr(string(r.Blob()))
+		case st.SFDIDDocument:
+			d.DIDDocument = ptr(readBlob(r))
+		case st.SFData:
+			d.Data = ptr(readBlob(r))
+		default:
+			return unexpectedField(r)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,136 @@
+package entry
+
+import (
+	"errors"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
+)
+
+// DirectoryNode represents one page of an owner, order book or NFT offer
+// directory. Owner directories set Owner, order book directories set the
+// Taker* fields and ExchangeRate.
+// Reference: rippled/include/xrpl/protocol/detail/ledger_entries.macro ltDIR_NODE
+type DirectoryNode struct {
+	BaseEntry
+
+	// Required fields
+	RootIndex [32]byte   // Key of the first page of the directory
+	Indexes   [][32]byte // Keys of the entries on this page
+
+	// Optional fields
+	Owner             *[20]byte // Owner of an owner directory
+	TakerPaysCurrency *[20]byte // Order book: currency the taker pays
+	TakerPaysIssuer   *[20]byte // Order book: issuer of TakerPaysCurrency
+	TakerGetsCurrency *[20]byte // Order book: currency the taker gets
+	TakerGetsIssuer   *[20]byte // Order book: issuer of TakerGetsCurrency
+	ExchangeRate      *uint64   // Order book: quality of the offers
+	IndexNext         *uint64   // Next page
+	IndexPrevious     *uint64   // Previous page
+	NFTokenID         *[32]byte // NFT offer directory: the token
+	DomainID          *[32]byte // Order book: permissioned domain
+}
+
+func (d *DirectoryNode) Type() Type {
+	return TypeDirectoryNode
+}
+
+func (d *DirectoryNode) Validate() error {
+	if d.RootIndex == [32]byte{} {
+		return errors.New("root index is required")
+	}
+	return nil
+}
+
+func (d *DirectoryNode) Hash() ([32]byte, error) {
+	hash := d.BaseEntry.Hash()
+	for i := 0; i < 32; i++ {
+		hash[i] ^= d.RootIndex[i]
+	}
+	return hash, nil
+}
+
+// MarshalBinary encodes the entry in canonical binary form.
+func (d *DirectoryNode) MarshalBinary() ([]byte, error) {
+	w := newEntryWriter(TypeDirectoryNode)
+	w.UInt32(st.SFFlags, d.Flags)
+	if d.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, d.PreviousTxnLgrSeq)
+	}
+	if d.IndexNext != nil {
+		w.UInt64(st.SFIndexNext, *d.IndexNext)
+	}
+	if d.IndexPrevious != nil {
+		w.UInt64(st.SFIndexPrevious, *d.IndexPrevious)
+	}
+	if d.ExchangeRate != nil {
+		w.UInt64(st.SFExchangeRate, *d.ExchangeRate)
+	}
+	if d.threaded() {
+		w.Hash256(st.SFPreviousTxnID, d.PreviousTxnID)
+	}
+	w.Hash256(st.SFRootIndex, d.RootIndex)
+	if d.NFTokenID != nil {
+		w.Hash256(st.SFNFTokenID, *d.NFTokenID)
+	}
+	if d.DomainID != nil {
+		w.Hash256(st.SFDomainID, *d.DomainID)
+	}
+	if d.Owner != nil {
+		w.AccountID(st.SFOwner, *d.Owner)
+	}
+	for _, f := range []struct {
+		field *st.Field
+		value *[20]byte
+	}{
+		{st.SFTakerPaysCurrency, d.TakerPaysCurrency},
+		{st.SFTakerPaysIssuer, d.TakerPaysIssuer},
+		{st.SFTakerGetsCurrency, d.TakerGetsCurrency},
+		{st.SFTakerGetsIssuer, d.TakerGetsIssuer},
+	} {
+		if f.value != nil {
+			w.Hash160(f.field, *f.value)
+		}
+	}
+	w.Vector256(st.SFIndexes, d.Indexes)
+	return w.Bytes()
+}
+
+// UnmarshalBinary decodes a DirectoryNode from its binary form.
+func (d *DirectoryNode) UnmarshalBinary(data []byte) error {
+	*d = DirectoryNode{}
+	return readEntry(data, TypeDirectoryNode, &d.BaseEntry, func(r *st.Reader) error {
+		switch r.Field() {
+		case st.SFRootIndex:
+			d.RootIndex = r.Hash256()
+		case st.SFIndexes:
+			indexes := r.Vector256()
+			d.Indexes = make([][32]byte, indexes.Len())
+			for i := range d.Indexes {
+				d.Indexes[i] = indexes.At(i)
+			}
+		case st.SFOwner:
+			d.Owner = ptr(r.AccountID())
+		case st.SFTakerPaysCurrency:
+			d.TakerPaysCurrency = ptr(r.Hash160())
+		case st.SFTakerPaysIssuer:
+			d.TakerPaysIssuer = ptr(r.Hash160())
+		case st.SFTakerGetsCurrency:
+			d.TakerGetsCurrency = ptr(r.Hash160())
+		case st.SFTakerGetsIssuer:
+			d.TakerGetsIssuer = ptr(r.Hash160())
+		case st.SFExchangeRate:
+			d.ExchangeRate = ptr(r.UInt64())
+		case st.SFIndexNext:
+			d.IndexNext = ptr(r.UInt64())
+		case st.SFIndexPrevious:
+			d.IndexPrevious = ptr(r.UInt64())
+		case st.SFNFTokenID:
+			d.NFTokenID = ptr(r.Hash256())
+		case st.SFDomainID:
+			d.DomainID = ptr(r.Hash256())
+		default:
+			return unexpectedField(r)
+		}
+		return nil
+	})
+}
@@ -4,7 +4,10 @@
 // Each ledger object type (AccountRoot, Offer, RippleState, DirectoryNode,
 // NFTokenPage, AMM, etc.) is represented as a Go struct with typed fields
 // that map to the XRPL protocol's serialized field definitions. These structs
-// support conversion to and from JSON maps for binary codec serialization.
+// support conversion to and from JSON maps for binary codec serialization,
+// and entries whose fields map one-to-one onto serialized fields also
+// implement MarshalBinary and UnmarshalBinary on top of the st codec. The
+// genesis ledger is written and FeeSettings is read through them.
 //
 // The package covers 40+ ledger entry types as defined in rippled's
 // ledger_entries.macro.
@@ -3,6 +3,7 @@ package entry
 import (
 	"encoding/binary"
 
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 	"github.com/LeJamon/goXRPLd/drops"
 )
 
@@ -116,3 +117,68 @@ func (f *FeeSettings) GetReserveIncrement() drops.XRPAmount {
 func (f *FeeSettings) IsUsingModernFees() bool {
 	return f.BaseFeeDrops > 0 || f.ReserveBaseDrops > 0 || f.ReserveIncrementDrops > 0
 }
+
+// MarshalBinary encodes the entry in canonical binary form. The legacy fee
+// fields are written when BaseFee is set, the XRPFees fields otherwise.
+func (f *FeeSettings) MarshalBinary() ([]byte, error) {
+	legacy := f.BaseFee != nil
+	w := newEntryWriter(TypeFeeSettings)
+	w.UInt32(st.SFFlags, f.Flags)
+	if f.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, f.PreviousTxnLgrSeq)
+	}
+	if legacy {
+		if f.ReferenceFeeUnits != nil {
+			w.UInt32(st.SFReferenceFeeUnits, *f.ReferenceFeeUnits)
+		}
+		if f.ReserveBase != nil {
+			w.UInt32(st.SFReserveBase, *f.ReserveBase)
+		}
+		if f.ReserveIncrement != nil {
+			w.UInt32(st.SFReserveIncrement, *f.ReserveIncrement)
+		}
+		w.UInt64(st.SFBaseFee, *f.BaseFee)
+	}
+	if f.threaded() {
+		w.Hash256(st.SFPreviousTxnID, f.PreviousTxnID)
+	}
+	if !legacy {
+		w.Amount(st.SFBaseFeeDrops, st.XRPAmount(uint64(f.BaseFeeDrops)))
+		w.Amount(st.SFReserveBaseDrops, st.XRPAmount(uint64(f.ReserveBaseDrops)))
+		w.Amount(st.SFReserveIncrementDrops, st.XRPAmount(uint64(f.ReserveIncrementDrops)))
+	}
+	return w.Bytes()
+}
+
+// UnmarshalBinary decodes a FeeSettings from its binary form.
+func (f *FeeSettings) UnmarshalBinary(data []byte) error {
+	*f = FeeSettings{}
+	return readEntry(data, TypeFeeSettings, &f.BaseEntry, func(r *st.Reader) error {
+		var (
+			v   uint64
+			err error
+		)
+		switch r.Field() {
+		case st.SFBaseFeeDrops:
+			v, err = readDrops(r)
+			f.BaseFeeDrops = drops.XRPAmount(v)
+		case st.SFReserveBaseDrops:
+			v, err = readDrops(r)
+			f.ReserveBaseDrops = drops.XRPAmount(v)
+		case st.SFReserveIncrementDrops:
+			v, err = readDrops(r)
+			f.ReserveIncrementDrops = drops.XRPAmount(v)
+		case st.SFBaseFee:
+			f.BaseFee = ptr(r.UInt64())
+		case st.SFReferenceFeeUnits:
+			f.ReferenceFeeUnits = ptr(r.UInt32())
+		case st.SFReserveBase:
+			f.ReserveBase = ptr(r.UInt32())
+		case st.SFReserveIncrement:
+			f.ReserveIncrement = ptr(r.UInt32())
+		default:
+			return unexpectedField(r)
+		}
+		return err
+	})
+}
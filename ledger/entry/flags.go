@@ -20,6 +20,9 @@ const (
 	// Offer flags
 	OfferPassive uint32 = 0x00010000
 	OfferSell    uint32 = 0x00020000
+	// OfferHybrid marks an offer placed in both its domain book and the
+	// open book (rippled LedgerFormats.h lsfHybrid).
+	OfferHybrid uint32 = 0x00040000
 
 	// MPTokenIssuance flags (ledger entry flags, lsf prefix in rippled)
 	// Reference: rippled LedgerFormats.h
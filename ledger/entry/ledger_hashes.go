@@ -3,6 +3,8 @@ package entry
 import (
 	"encoding/binary"
 	"errors"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 )
 
 var (
@@ -83,3 +85,43 @@ func (lh *LedgerHashes) UpdateSkipList(parentHash [32]byte, prevLedgerSeq uint32
 	// Update the last ledger sequence
 	lh.LastLedgerSequence = prevLedgerSeq
 }
+
+// MarshalBinary encodes the entry in canonical binary form.
+func (l *LedgerHashes) MarshalBinary() ([]byte, error) {
+	w := newEntryWriter(TypeLedgerHashes)
+	w.UInt32(st.SFFlags, l.Flags)
+	if l.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, l.PreviousTxnLgrSeq)
+	}
+	if l.FirstLedgerSequence != nil {
+		w.UInt32(st.SFFirstLedgerSequence, *l.FirstLedgerSequence)
+	}
+	w.UInt32(st.SFLastLedgerSequence, l.LastLedgerSequence)
+	if l.threaded() {
+		w.Hash256(st.SFPreviousTxnID, l.PreviousTxnID)
+	}
+	w.Vector256(st.SFHashes, l.Hashes)
+	return w.Bytes()
+}
+
+// UnmarshalBinary decodes a LedgerHashes from its binary form.
+func (l *LedgerHashes) UnmarshalBinary(data []byte) error {
+	*l = LedgerHashes{}
+	return readEntry(data, TypeLedgerHashes, &l.BaseEntry, func(r *st.Reader) error {
+		switch r.Field() {
+		case st.SFHashes:
+			v := r.Vector256()
+			l.Hashes = make([][32]byte, v.Len())
+			for i := range l.Hashes {
+				l.Hashes[i] = v.At(i)
+			}
+		case st.SFLastLedgerSequence:
+			l.LastLedgerSequence = r.UInt32()
+		case st.SFFirstLedgerSequence:
+			l.FirstLedgerSequence = ptr(r.UInt32())
+		default:
+			return unexpectedField(r)
+		}
+		return nil
+	})
+}
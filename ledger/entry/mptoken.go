@@ -2,6 +2,8 @@ package entry
 
 import (
 	"errors"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 )
 
 // MPTokenIssuance represents a Multi-Purpose Token issuance ledger entry
@@ -86,3 +88,71 @@ func (m *MPToken) Hash() ([32]byte, error) {
 	}
 	return hash, nil
 }
+
+// MarshalBinary encodes the entry in canonical binary form. TransferFee and
+// AssetScale are omitted when zero, as rippled does for soeDEFAULT fields.
+func (m *MPTokenIssuance) MarshalBinary() ([]byte, error) {
+	w := newEntryWriter(TypeMPTokenIssuance)
+	if m.TransferFee != 0 {
+		w.UInt16(st.SFTransferFee, m.TransferFee)
+	}
+	w.UInt32(st.SFFlags, m.Flags)
+	w.UInt32(st.SFSequence, m.Sequence)
+	if m.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, m.PreviousTxnLgrSeq)
+	}
+	w.UInt64(st.SFOwnerNode, m.OwnerNode)
+	if m.MaximumAmount != nil {
+		w.UInt64(st.SFMaximumAmount, *m.MaximumAmount)
+	}
+	w.UInt64(st.SFOutstandingAmount, m.OutstandingAmount)
+	if m.LockedAmount != nil {
+		w.UInt64(st.SFLockedAmount, *m.LockedAmount)
+	}
+	if m.threaded() {
+		w.Hash256(st.SFPreviousTxnID, m.PreviousTxnID)
+	}
+	if m.DomainID != nil {
+		w.Hash256(st.SFDomainID, *m.DomainID)
+	}
+	if m.MPTokenMetadata != nil {
+		w.Blob(st.SFMPTokenMetadata, *m.MPTokenMetadata)
+	}
+	w.AccountID(st.SFIssuer, m.Issuer)
+	if m.AssetScale != 0 {
+		w.UInt8(st.SFAssetScale, m.AssetScale)
+	}
+	return w.Bytes()
+}
+
+// UnmarshalBinary decodes an MPTokenIssuance from its binary form.
+func (m *MPTokenIssuance) UnmarshalBinary(data []byte) error {
+	*m = MPTokenIssuance{}
+	return readEntry(data, TypeMPTokenIssuance, &m.BaseEntry, func(r *st.Reader) error {
+		switch r.Field() {
+		case st.SFIssuer:
+			m.Issuer = r.AccountID()
+		case st.SFSequence:
+			m.Sequence = r.UInt32()
+		case st.SFOwnerNode:
+			m.OwnerNode = r.UInt64()
+		case st.SFOutstandingAmount:
+			m.OutstandingAmount = r.UInt64()
+		case st.SFTransferFee:
+			m.TransferFee = r.UInt16()
+		case st.SFAssetScale:
+			m.AssetScale = r.UInt8()
+		case st.SFMaximumAmount:
+			m.MaximumAmount = ptr(r.UInt64())
+		case st.SFLockedAmount:
+			m.LockedAmount = ptr(r.UInt64())
+		case st.SFMPTokenMetadata:
+			m.MPTokenMetadata = ptr(readBlob(r))
+		case st.SFDomainID:
+			m.DomainID = ptr(r.Hash256())
+		default:
+			return unexpectedField(r)
+		}
+		return nil
+	})
+}
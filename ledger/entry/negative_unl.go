@@ -1,5 +1,7 @@
 package entry
 
+import "github.com/LeJamon/goXRPLd/codec/binarycodec/st"
+
 // DisabledValidator represents a validator that has been disabled
 type DisabledValidator struct {
 	PublicKey      [33]byte // Validator's public key
@@ -30,3 +32,74 @@ func (n *NegativeUNL) Validate() error {
 func (n *NegativeUNL) Hash() ([32]byte, error) {
 	return n.BaseEntry.Hash(), nil
 }
+
+// MarshalBinary encodes the entry in canonical binary form.
+func (n *NegativeUNL) MarshalBinary() ([]byte, error) {
+	w := newEntryWriter(TypeNegativeUNL)
+	w.UInt32(st.SFFlags, n.Flags)
+	if n.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, n.PreviousTxnLgrSeq)
+		w.Hash256(st.SFPreviousTxnID, n.PreviousTxnID)
+	}
+	if n.ValidatorToDisable != nil {
+		w.Blob(st.SFValidatorToDisable, n.ValidatorToDisable[:])
+	}
+	if n.ValidatorToReEnable != nil {
+		w.Blob(st.SFValidatorToReEnable, n.ValidatorToReEnable[:])
+	}
+	if len(n.DisabledValidators) > 0 {
+		w.BeginArray(st.SFDisabledValidators)
+		for _, v := range n.DisabledValidators {
+			w.BeginObject(st.SFDisabledValidator)
+			w.UInt32(st.SFFirstLedgerSequence, v.FirstLedgerSeq)
+			w.Blob(st.SFPublicKey, v.PublicKey[:])
+			w.EndObject()
+		}
+		w.EndArray()
+	}
+	return w.Bytes()
+}
+
+// UnmarshalBinary decodes a NegativeUNL from its binary form.
+func (n *NegativeUNL) UnmarshalBinary(data []byte) error {
+	*n = NegativeUNL{}
+	return readEntry(data, TypeNegativeUNL, &n.BaseEntry, func(r *st.Reader) error {
+		switch r.Field() {
+		case st.SFValidatorToDisable:
+			key, err := readPublicKey(r)
+			n.ValidatorToDisable = &key
+			return err
+		case st.SFValidatorToReEnable:
+			key, err := readPublicKey(r)
+			n.ValidatorToReEnable = &key
+			return err
+		case st.SFDisabledValidators:
+			validators := r.Array()
+			for validators.Next() {
+				var v DisabledValidator
+				fields := validators.Object()
+				for fields.Next() {
+					switch fields.Field() {
+					case st.SFPublicKey:
+						key, err := readPublicKey(&fields)
+						if err != nil {
+							return err
+						}
+						v.PublicKey = key
+					case st.SFFirstLedgerSequence:
+						v.FirstLedgerSeq = fields.UInt32()
+					default:
+						return unexpectedField(&fields)
+					}
+				}
+				if err := fields.Err(); err != nil {
+					return err
+				}
+				n.DisabledValidators = append(n.DisabledValidators, v)
+			}
+			return validators.Err()
+		default:
+			return unexpectedField(r)
+		}
+	})
+}
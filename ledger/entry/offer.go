@@ -0,0 +1,150 @@
+package entry
+
+import (
+	"errors"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
+)
+
+// OfferBook identifies an order book directory an offer is listed in
+type OfferBook struct {
+	BookDirectory [32]byte // Order book directory
+	BookNode      uint64   // Book directory node hint
+}
+
+// Offer represents an order on the decentralized exchange
+// Reference: rippled/include/xrpl/protocol/detail/ledger_entries.macro ltOFFER
+type Offer struct {
+	BaseEntry
+
+	// Required fields
+	Account       [20]byte // Account that placed the offer
+	Sequence      uint32   // Sequence number of the OfferCreate
+	TakerPays     Amount   // Amount the taker pays
+	TakerGets     Amount   // Amount the taker gets
+	BookDirectory [32]byte // Order book directory
+	BookNode      uint64   // Book directory node hint
+	OwnerNode     uint64   // Owner directory node hint
+
+	// Optional fields
+	Expiration      *uint32     // Time after which the offer is unfunded
+	DomainID        *[32]byte   // Permissioned domain of the offer
+	AdditionalBooks []OfferBook // Open book of a hybrid offer
+}
+
+func (o *Offer) Type() Type {
+	return TypeOffer
+}
+
+func (o *Offer) Validate() error {
+	if o.Account == [20]byte{} {
+		return errors.New("account is required")
+	}
+	if o.TakerPays.IsNative && o.TakerGets.IsNative {
+		return errors.New("offer cannot trade XRP for XRP")
+	}
+	return nil
+}
+
+func (o *Offer) Hash() ([32]byte, error) {
+	hash := o.BaseEntry.Hash()
+	for i := 0; i < 20; i++ {
+		hash[i] ^= o.Account[i]
+	}
+	return hash, nil
+}
+
+// MarshalBinary encodes the entry in canonical binary form.
+func (o *Offer) MarshalBinary() ([]byte, error) {
+	takerPays, err := stAmount(o.TakerPays)
+	if err != nil {
+		return nil, err
+	}
+	takerGets, err := stAmount(o.TakerGets)
+	if err != nil {
+		return nil, err
+	}
+	w := newEntryWriter(TypeOffer)
+	w.UInt32(st.SFFlags, o.Flags)
+	w.UInt32(st.SFSequence, o.Sequence)
+	if o.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, o.PreviousTxnLgrSeq)
+	}
+	if o.Expiration != nil {
+		w.UInt32(st.SFExpiration, *o.Expiration)
+	}
+	w.UInt64(st.SFBookNode, o.BookNode)
+	w.UInt64(st.SFOwnerNode, o.OwnerNode)
+	if o.threaded() {
+		w.Hash256(st.SFPreviousTxnID, o.PreviousTxnID)
+	}
+	w.Hash256(st.SFBookDirectory, o.BookDirectory)
+	if o.DomainID != nil {
+		w.Hash256(st.SFDomainID, *o.DomainID)
+	}
+	w.Amount(st.SFTakerPays, takerPays)
+	w.Amount(st.SFTakerGets, takerGets)
+	w.AccountID(st.SFAccount, o.Account)
+	if len(o.AdditionalBooks) > 0 {
+		w.BeginArray(st.SFAdditionalBooks)
+		for _, b := range o.AdditionalBooks {
+			w.BeginObject(st.SFBook)
+			w.UInt64(st.SFBookNode, b.BookNode)
+			w.Hash256(st.SFBookDirectory, b.BookDirectory)
+			w.EndObject()
+		}
+		w.EndArray()
+	}
+	return w.Bytes()
+}
+
+// UnmarshalBinary decodes an Offer from its binary form.
+func (o *Offer) UnmarshalBinary(data []byte) error {
+	*o = Offer{}
+	return readEntry(data, TypeOffer, &o.BaseEntry, func(r *st.Reader) (err error) {
+		switch r.Field() {
+		case st.SFAccount:
+			o.Account = r.AccountID()
+		case st.SFSequence:
+			o.Sequence = r.UInt32()
+		case st.SFTakerPays:
+			o.TakerPays, err = readAmount(r)
+		case st.SFTakerGets:
+			o.TakerGets, err = readAmount(r)
+		case st.SFBookDirectory:
+			o.BookDirectory = r.Hash256()
+		case st.SFBookNode:
+			o.BookNode = r.UInt64()
+		case st.SFOwnerNode:
+			o.OwnerNode = r.UInt64()
+		case st.SFExpiration:
+			o.Expiration = ptr(r.UInt32())
+		case st.SFDomainID:
+			o.DomainID = ptr(r.Hash256())
+		case st.SFAdditionalBooks:
+			books := r.Array()
+			for books.Next() {
+				var b OfferBook
+				fields := books.Object()
+				for fields.Next() {
+					switch fields.Field() {
+					case st.SFBookDirectory:
+						b.BookDirectory = fields.Hash256()
+					case st.SFBookNode:
+						b.BookNode = fields.UInt64()
+					default:
+						return unexpectedField(&fields)
+					}
+				}
+				if err := fields.Err(); err != nil {
+					return err
+				}
+				o.AdditionalBooks = append(o.AdditionalBooks, b)
+			}
+			err = books.Err()
+		default:
+			return unexpectedField(r)
+		}
+		return err
+	})
+}
@@ -2,6 +2,8 @@ package entry
 
 import (
 	"errors"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 )
 
 // PriceData represents a single price data point in an oracle
@@ -62,3 +64,96 @@ func (o *Oracle) Hash() ([32]byte, error) {
 	}
 	return hash, nil
 }
+
+// MarshalBinary encodes the entry in canonical binary form. The AssetPrice
+// and Scale of a price data point are omitted when zero.
+func (o *Oracle) MarshalBinary() ([]byte, error) {
+	w := newEntryWriter(TypeOracle)
+	w.UInt32(st.SFFlags, o.Flags)
+	if o.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, o.PreviousTxnLgrSeq)
+	}
+	w.UInt32(st.SFLastUpdateTime, o.LastUpdateTime)
+	w.UInt64(st.SFOwnerNode, o.OwnerNode)
+	if o.threaded() {
+		w.Hash256(st.SFPreviousTxnID, o.PreviousTxnID)
+	}
+	if o.URI != nil {
+		w.Blob(st.SFURI, []byte(*o.URI))
+	}
+	w.Blob(st.SFAssetClass, o.AssetClass)
+	w.Blob(st.SFProvider, o.Provider)
+	w.AccountID(st.SFOwner, o.Owner)
+	w.BeginArray(st.SFPriceDataSeries)
+	for _, p := range o.PriceDataSeries {
+		base, err := currencyCode(p.BaseAsset)
+		if err != nil {
+			return nil, err
+		}
+		quote, err := currencyCode(p.QuoteAsset)
+		if err != nil {
+			return nil, err
+		}
+		w.BeginObject(st.SFPriceData)
+		if p.AssetPrice != 0 {
+			w.UInt64(st.SFAssetPrice, p.AssetPrice)
+		}
+		if p.Scale != 0 {
+			w.UInt8(st.SFScale, p.Scale)
+		}
+		w.Currency(st.SFBaseAsset, base)
+		w.Currency(st.SFQuoteAsset, quote)
+		w.EndObject()
+	}
+	w.EndArray()
+	return w.Bytes()
+}
+
+// UnmarshalBinary decodes an Oracle from its binary form.
+func (o *Oracle) UnmarshalBinary(data []byte) error {
+	*o = Oracle{}
+	return readEntry(data, TypeOracle, &o.BaseEntry, func(r *st.Reader) error {
+		switch r.Field() {
+		case st.SFOwner:
+			o.Owner = r.AccountID()
+		case st.SFProvider:
+			o.Provider = readBlob(r)
+		case st.SFAssetClass:
+			o.AssetClass = readBlob(r)
+		case st.SFLastUpdateTime:
+			o.LastUpdateTime = r.UInt32()
+		case st.SFOwnerNode:
+			o.OwnerNode = r.UInt64()
+		case st.SFURI:
+			o.URI = ptr(string(r.Blob()))
+		case st.SFPriceDataSeries:
+			series := r.Array()
+			for series.Next() {
+				var p PriceData
+				fields := series.Object()
+				for fields.Next() {
+					switch fields.Field() {
+					case st.SFBaseAsset:
+						p.BaseAsset = currencyString(fields.Currency())
+					case st.SFQuoteAsset:
+						p.QuoteAsset = currencyString(fields.Currency())
+					case st.SFAssetPrice:
+						p.AssetPrice = fields.UInt64()
+					case st.SFScale:
+						p.Scale = fields.UInt8()
+					default:
+						return unexpectedField(&fields)
+					}
+				}
+				if err := fields.Err(); err != nil {
+					return err
+				}
+				o.PriceDataSeries = append(o.PriceDataSeries, p)
+			}
+			return series.Err()
+		default:
+			return unexpectedField(r)
+		}
+		return nil
+	})
+}
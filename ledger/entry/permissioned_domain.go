@@ -2,6 +2,8 @@ package entry
 
 import (
 	"errors"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 )
 
 // AcceptedCredential represents a credential type accepted by a permissioned domain
@@ -54,3 +56,66 @@ func (p *PermissionedDomain) Hash() ([32]byte, error) {
 	}
 	return hash, nil
 }
+
+// MarshalBinary encodes the entry in canonical binary form.
+func (p *PermissionedDomain) MarshalBinary() ([]byte, error) {
+	w := newEntryWriter(TypePermissionedDomain)
+	w.UInt32(st.SFFlags, p.Flags)
+	w.UInt32(st.SFSequence, p.Sequence)
+	if p.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, p.PreviousTxnLgrSeq)
+	}
+	w.UInt64(st.SFOwnerNode, p.OwnerNode)
+	if p.threaded() {
+		w.Hash256(st.SFPreviousTxnID, p.PreviousTxnID)
+	}
+	w.AccountID(st.SFOwner, p.Owner)
+	w.BeginArray(st.SFAcceptedCredentials)
+	for _, c := range p.AcceptedCredentials {
+		w.BeginObject(st.SFCredential)
+		w.Blob(st.SFCredentialType, c.CredentialType)
+		w.AccountID(st.SFIssuer, c.Issuer)
+		w.EndObject()
+	}
+	w.EndArray()
+	return w.Bytes()
+}
+
+// UnmarshalBinary decodes a PermissionedDomain from its binary form.
+func (p *PermissionedDomain) UnmarshalBinary(data []byte) error {
+	*p = PermissionedDomain{}
+	return readEntry(data, TypePermissionedDomain, &p.BaseEntry, func(r *st.Reader) error {
+		switch r.Field() {
+		case st.SFOwner:
+			p.Owner = r.AccountID()
+		case st.SFSequence:
+			p.Sequence = r.UInt32()
+		case st.SFOwnerNode:
+			p.OwnerNode = r.UInt64()
+		case st.SFAcceptedCredentials:
+			credentials := r.Array()
+			for credentials.Next() {
+				var c AcceptedCredential
+				fields := credentials.Object()
+				for fields.Next() {
+					switch fields.Field() {
+					case st.SFIssuer:
+						c.Issuer = fields.AccountID()
+					case st.SFCredentialType:
+						c.CredentialType = readBlob(&fields)
+					default:
+						return unexpectedField(&fields)
+					}
+				}
+				if err := fields.Err(); err != nil {
+					return err
+				}
+				p.AcceptedCredentials = append(p.AcceptedCredentials, c)
+			}
+			return credentials.Err()
+		default:
+			return unexpectedField(r)
+		}
+		return nil
+	})
+}
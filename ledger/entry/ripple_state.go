@@ -0,0 +1,125 @@
+package entry
+
+import (
+	"errors"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
+)
+
+// RippleState represents a trust line between two accounts. The low and
+// high accounts are the issuers of LowLimit and HighLimit.
+// Reference: rippled/include/xrpl/protocol/detail/ledger_entries.macro ltRIPPLE_STATE
+type RippleState struct {
+	BaseEntry
+
+	// Required fields
+	Balance   Amount // Balance from the low account's side; its issuer is unset
+	LowLimit  Amount // Limit set by the low account
+	HighLimit Amount // Limit set by the high account
+	LowNode   uint64 // Low account's owner directory node hint
+	HighNode  uint64 // High account's owner directory node hint
+
+	// Optional fields
+	LowQualityIn   *uint32
+	LowQualityOut  *uint32
+	HighQualityIn  *uint32
+	HighQualityOut *uint32
+}
+
+func (r *RippleState) Type() Type {
+	return TypeRippleState
+}
+
+func (r *RippleState) Validate() error {
+	if r.LowLimit.IsNative || r.HighLimit.IsNative || r.Balance.IsNative {
+		return errors.New("trust line amounts cannot be XRP")
+	}
+	if r.LowLimit.Issuer == [20]byte{} || r.HighLimit.Issuer == [20]byte{} {
+		return errors.New("limit issuers are required")
+	}
+	if r.LowLimit.Issuer == r.HighLimit.Issuer {
+		return errors.New("low and high accounts must differ")
+	}
+	return nil
+}
+
+func (r *RippleState) Hash() ([32]byte, error) {
+	hash := r.BaseEntry.Hash()
+	for i := 0; i < 20; i++ {
+		hash[i] ^= r.LowLimit.Issuer[i]
+		hash[i] ^= r.HighLimit.Issuer[i]
+	}
+	return hash, nil
+}
+
+// MarshalBinary encodes the entry in canonical binary form.
+func (r *RippleState) MarshalBinary() ([]byte, error) {
+	balance, err := stAmount(r.Balance)
+	if err != nil {
+		return nil, err
+	}
+	lowLimit, err := stAmount(r.LowLimit)
+	if err != nil {
+		return nil, err
+	}
+	highLimit, err := stAmount(r.HighLimit)
+	if err != nil {
+		return nil, err
+	}
+	w := newEntryWriter(TypeRippleState)
+	w.UInt32(st.SFFlags, r.Flags)
+	if r.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, r.PreviousTxnLgrSeq)
+	}
+	if r.HighQualityIn != nil {
+		w.UInt32(st.SFHighQualityIn, *r.HighQualityIn)
+	}
+	if r.HighQualityOut != nil {
+		w.UInt32(st.SFHighQualityOut, *r.HighQualityOut)
+	}
+	if r.LowQualityIn != nil {
+		w.UInt32(st.SFLowQualityIn, *r.LowQualityIn)
+	}
+	if r.LowQualityOut != nil {
+		w.UInt32(st.SFLowQualityOut, *r.LowQualityOut)
+	}
+	w.UInt64(st.SFLowNode, r.LowNode)
+	w.UInt64(st.SFHighNode, r.HighNode)
+	if r.threaded() {
+		w.Hash256(st.SFPreviousTxnID, r.PreviousTxnID)
+	}
+	w.Amount(st.SFBalance, balance)
+	w.Amount(st.SFLowLimit, lowLimit)
+	w.Amount(st.SFHighLimit, highLimit)
+	return w.Bytes()
+}
+
+// UnmarshalBinary decodes a RippleState from its binary form.
+func (r *RippleState) UnmarshalBinary(data []byte) error {
+	*r = RippleState{}
+	return readEntry(data, TypeRippleState, &r.BaseEntry, func(f *st.Reader) (err error) {
+		switch f.Field() {
+		case st.SFBalance:
+			r.Balance, err = readAmount(f)
+		case st.SFLowLimit:
+			r.LowLimit, err = readAmount(f)
+		case st.SFHighLimit:
+			r.HighLimit, err = readAmount(f)
+		case st.SFLowNode:
+			r.LowNode = f.UInt64()
+		case st.SFHighNode:
+			r.HighNode = f.UInt64()
+		case st.SFLowQualityIn:
+			r.LowQualityIn = ptr(f.UInt32())
+		case st.SFLowQualityOut:
+			r.LowQualityOut = ptr(f.UInt32())
+		case st.SFHighQualityIn:
+			r.HighQualityIn = ptr(f.UInt32())
+		case st.SFHighQualityOut:
+			r.HighQualityOut = ptr(f.UInt32())
+		default:
+			return unexpectedField(f)
+		}
+		return err
+	})
+}
@@ -2,6 +2,10 @@ package entry
 
 import (
 	"errors"
+	"fmt"
+	"math"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 )
 
 // WithdrawalPolicy represents the withdrawal policy for a vault
@@ -28,11 +32,12 @@ type Vault struct {
 	AssetsTotal      uint64           // Total assets in the vault
 	AssetsAvailable  uint64           // Assets available for withdrawal
 	LossUnrealized   int64            // Unrealized loss (can be negative for gains)
-	ShareMPTID       [32]byte         // MPToken issuance ID for vault shares
+	ShareMPTID       [24]byte         // MPToken issuance ID for vault shares
 	WithdrawalPolicy WithdrawalPolicy // Vault's withdrawal policy
 
 	// Default fields (always present but may be zero)
 	AssetsMaximum uint64 // Maximum assets the vault can hold (0 = unlimited)
+	Scale         uint8  // Decimal scale of the vault shares
 
 	// Optional fields
 	Data *[]byte // Arbitrary data associated with the vault
@@ -49,7 +54,7 @@ func (v *Vault) Validate() error {
 	if v.Account == [20]byte{} {
 		return errors.New("account is required")
 	}
-	if v.ShareMPTID == [32]byte{} {
+	if v.ShareMPTID == [24]byte{} {
 		return errors.New("share MPT ID is required")
 	}
 	if v.AssetsAvailable > v.AssetsTotal {
@@ -69,3 +74,113 @@ func (v *Vault) Hash() ([32]byte, error) {
 	}
 	return hash, nil
 }
+
+// MarshalBinary encodes the entry in canonical binary form. The asset
+// amounts are written as Numbers, and those that default to zero are
+// omitted when zero.
+func (v *Vault) MarshalBinary() ([]byte, error) {
+	w := newEntryWriter(TypeVault)
+	w.UInt32(st.SFFlags, v.Flags)
+	w.UInt32(st.SFSequence, v.Sequence)
+	if v.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, v.PreviousTxnLgrSeq)
+	}
+	w.UInt64(st.SFOwnerNode, v.OwnerNode)
+	if v.threaded() {
+		w.Hash256(st.SFPreviousTxnID, v.PreviousTxnID)
+	}
+	if v.Data != nil {
+		w.Blob(st.SFData, *v.Data)
+	}
+	w.AccountID(st.SFAccount, v.Account)
+	w.AccountID(st.SFOwner, v.Owner)
+	for _, n := range []struct {
+		field *st.Field
+		value uint64
+	}{
+		{st.SFAssetsAvailable, v.AssetsAvailable},
+		{st.SFAssetsMaximum, v.AssetsMaximum},
+		{st.SFAssetsTotal, v.AssetsTotal},
+	} {
+		if n.value == 0 {
+			continue
+		}
+		if n.value > math.MaxInt64 {
+			return nil, fmt.Errorf("%s %d overflows Number", n.field, n.value)
+		}
+		if err := writeNumber(w, n.field, int64(n.value)); err != nil {
+			return nil, err
+		}
+	}
+	if v.LossUnrealized != 0 {
+		if err := writeNumber(w, st.SFLossUnrealized, v.LossUnrealized); err != nil {
+			return nil, err
+		}
+	}
+	if v.Scale != 0 {
+		w.UInt8(st.SFScale, v.Scale)
+	}
+	w.UInt8(st.SFWithdrawalPolicy, uint8(v.WithdrawalPolicy))
+	w.Hash192(st.SFShareMPTID, v.ShareMPTID)
+	w.Issue(st.SFAsset, stIssue(v.Asset))
+	return w.Bytes()
+}
+
+// writeNumber writes an integer Number field.
+func writeNumber(w *st.Writer, f *st.Field, v int64) error {
+	n, err := stNumber(v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", f, err)
+	}
+	w.Number(f, n)
+	return nil
+}
+
+// UnmarshalBinary decodes a Vault from its binary form. The asset amounts
+// must be integers.
+func (v *Vault) UnmarshalBinary(data []byte) error {
+	*v = Vault{}
+	return readEntry(data, TypeVault, &v.BaseEntry, func(r *st.Reader) (err error) {
+		switch r.Field() {
+		case st.SFSequence:
+			v.Sequence = r.UInt32()
+		case st.SFOwnerNode:
+			v.OwnerNode = r.UInt64()
+		case st.SFOwner:
+			v.Owner = r.AccountID()
+		case st.SFAccount:
+			v.Account = r.AccountID()
+		case st.SFData:
+			v.Data = ptr(readBlob(r))
+		case st.SFAsset:
+			v.Asset, err = readIssue(r.Issue())
+		case st.SFAssetsTotal:
+			v.AssetsTotal, err = readAssets(r)
+		case st.SFAssetsAvailable:
+			v.AssetsAvailable, err = readAssets(r)
+		case st.SFAssetsMaximum:
+			v.AssetsMaximum, err = readAssets(r)
+		case st.SFLossUnrealized:
+			v.LossUnrealized, err = readInteger(r)
+		case st.SFScale:
+			v.Scale = r.UInt8()
+		case st.SFWithdrawalPolicy:
+			v.WithdrawalPolicy = WithdrawalPolicy(r.UInt8())
+		case st.SFShareMPTID:
+			v.ShareMPTID = r.Hash192()
+		default:
+			return unexpectedField(r)
+		}
+		return err
+	})
+}
+
+// readAssets returns the value of a Number field holding a non-negative
+// integer amount.
+func readAssets(r *st.Reader) (uint64, error) {
+	n, err := readInteger(r)
+	if err == nil && n < 0 {
+		err = fmt.Errorf("%s is negative", r.Field())
+	}
+	return uint64(n), err
+}
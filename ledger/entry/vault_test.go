@@ -19,7 +19,7 @@ func TestVault_Type(t *testing.T) {
 func TestVault_Validate(t *testing.T) {
 	validOwner := [20]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
 	validAccount := [20]byte{20, 19, 18, 17, 16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
-	validShareMPTID := [32]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32}
+	validShareMPTID := [24]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24}
 
 	t.Run("Valid Vault with minimum fields", func(t *testing.T) {
 		vault := &Vault{
@@ -122,7 +122,7 @@ func TestVault_Validate(t *testing.T) {
 		vault := &Vault{
 			Owner:            validOwner,
 			Account:          validAccount,
-			ShareMPTID:       [32]byte{},
+			ShareMPTID:       [24]byte{},
 			AssetsTotal:      1000000,
 			AssetsAvailable:  1000000,
 			WithdrawalPolicy: WithdrawalPolicyStrict,
@@ -182,7 +182,7 @@ func TestVault_Hash(t *testing.T) {
 	vault := &Vault{
 		Owner:            [20]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20},
 		Account:          [20]byte{20, 19, 18, 17, 16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
-		ShareMPTID:       [32]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32},
+		ShareMPTID:       [24]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24},
 		AssetsTotal:      1000000,
 		AssetsAvailable:  1000000,
 		WithdrawalPolicy: WithdrawalPolicyStrict,
@@ -196,7 +196,7 @@ func TestVault_Hash(t *testing.T) {
 	vault2 := &Vault{
 		Owner:            [20]byte{10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 1, 2, 3, 4, 5, 6, 7, 8, 9},
 		Account:          [20]byte{20, 19, 18, 17, 16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
-		ShareMPTID:       [32]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32},
+		ShareMPTID:       [24]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24},
 		AssetsTotal:      1000000,
 		AssetsAvailable:  1000000,
 		WithdrawalPolicy: WithdrawalPolicyStrict,
@@ -2,6 +2,8 @@ package entry
 
 import (
 	"errors"
+
+	"github.com/LeJamon/goXRPLd/codec/binarycodec/st"
 )
 
 // XChainClaimAttestation represents a single attestation for a cross-chain claim
@@ -9,7 +11,8 @@ type XChainClaimAttestation struct {
 	AttestationSignerAccount [20]byte // Account of the attestation signer
 	PublicKey                [33]byte // Public key of the signer
 	Amount                   uint64   // Amount being claimed
-	Destination              [20]byte // Destination account
+	AttestationRewardAccount [20]byte // Account receiving the signature reward
+	Destination              [20]byte // Destination account, zero if none
 	WasLockingChainSend      bool     // True if sent from locking chain
 }
 
@@ -50,6 +53,108 @@ func (x *XChainOwnedClaimID) Hash() ([32]byte, error) {
 	return hash, nil
 }
 
+// MarshalBinary encodes the entry in canonical binary form. The Destination
+// of an attestation is omitted when zero.
+func (x *XChainOwnedClaimID) MarshalBinary() ([]byte, error) {
+	w := newEntryWriter(TypeXChainOwnedClaimID)
+	w.UInt32(st.SFFlags, x.Flags)
+	if x.threaded() {
+		w.UInt32(st.SFPreviousTxnLgrSeq, x.PreviousTxnLgrSeq)
+	}
+	w.UInt64(st.SFOwnerNode, x.OwnerNode)
+	w.UInt64(st.SFXChainClaimID, x.XChainClaimID)
+	if x.threaded() {
+		w.Hash256(st.SFPreviousTxnID, x.PreviousTxnID)
+	}
+	w.Amount(st.SFSignatureReward, st.XRPAmount(x.SignatureReward))
+	w.AccountID(st.SFAccount, x.Account)
+	w.AccountID(st.SFOtherChainSource, x.OtherChainSource)
+	w.BeginArray(st.SFXChainClaimAttestations)
+	for _, a := range x.XChainClaimAttestations {
+		w.BeginObject(st.SFXChainClaimProofSig)
+		w.Amount(st.SFAmount, st.XRPAmount(a.Amount))
+		w.Blob(st.SFPublicKey, a.PublicKey[:])
+		if a.Destination != ([20]byte{}) {
+			w.AccountID(st.SFDestination, a.Destination)
+		}
+		w.AccountID(st.SFAttestationSignerAccount, a.AttestationSignerAccount)
+		w.AccountID(st.SFAttestationRewardAccount, a.AttestationRewardAccount)
+		var wasLockingChainSend uint8
+		if a.WasLockingChainSend {
+			wasLockingChainSend = 1
+		}
+		w.UInt8(st.SFWasLockingChainSend, wasLockingChainSend)
+		w.EndObject()
+	}
+	w.EndArray()
+	w.XChainBridge(st.SFXChainBridge, stXChainBridge(x.XChainBridge))
+	return w.Bytes()
+}
+
+// UnmarshalBinary decodes an XChainOwnedClaimID from its binary form.
+// Attestation amounts must be XRP.
+func (x *XChainOwnedClaimID) UnmarshalBinary(data []byte) error {
+	*x = XChainOwnedClaimID{}
+	return readEntry(data, TypeXChainOwnedClaimID, &x.BaseEntry, func(r *st.Reader) (err error) {
+		switch r.Field() {
+		case st.SFAccount:
+			x.Account = r.AccountID()
+		case st.SFXChainBridge:
+			x.XChainBridge, err = readXChainBridge(r)
+		case st.SFXChainClaimID:
+			x.XChainClaimID = r.UInt64()
+		case st.SFOtherChainSource:
+			x.OtherChainSource = r.AccountID()
+		case st.SFSignatureReward:
+			x.SignatureReward, err = readDrops(r)
+		case st.SFOwnerNode:
+			x.OwnerNode = r.UInt64()
+		case st.SFXChainClaimAttestations:
+			attestations := r.Array()
+			for attestations.Next() {
+				a, err := readClaimAttestation(&attestations)
+				if err != nil {
+					return err
+				}
+				x.XChainClaimAttestations = append(x.XChainClaimAttestations, a)
+			}
+			err = attestations.Err()
+		default:
+			return unexpectedField(r)
+		}
+		return err
+	})
+}
+
+// readClaimAttestation returns the value of an XChainClaimProofSig object.
+func readClaimAttestation(r *st.Reader) (XChainClaimAttestation, error) {
+	var a XChainClaimAttestation
+	fields := r.Object()
+	for fields.Next() {
+		var err error
+		switch fields.Field() {
+		case st.SFAttestationSignerAccount:
+			a.AttestationSignerAccount = fields.AccountID()
+		case st.SFAttestationRewardAccount:
+			a.AttestationRewardAccount = fields.AccountID()
+		case st.SFPublicKey:
+			a.PublicKey, err = readPublicKey(&fields)
+		case st.SFAmount:
+			a.Amount, err = readDrops(&fields)
+		case st.SFDestination:
+			a.Destination = fields.AccountID()
+		case st.SFWasLockingChainSend:
+			a.WasLockingChainSend = fields.UInt8() != 0
+		default:
+			err = unexpectedField(&fields)
+		}
+		if err != nil {
+			return a, err
+		}
+	}
+	return a, fields.Err()
+}
+
 // XChainCreateAccountAttestation represents a single attestation for cross-chain account creation
 type XChainCreateAccountAttestation struct {
 	AttestationSignerAccount [20]byte // Account of the attestation signer
@@ -14,6 +14,11 @@ const (
 	TickSizeMax uint8 = 16
 )
 
+// LoadFeeBase is the unloaded reference level that load fees are reported
+// against (rippled LoadFeeTrack::lftNormalFee). goXRPL has no load-fee
+// tracker yet, so every load fee it reports is this level.
+const LoadFeeBase = 256
+
 // Fee limits.
 const (
 	// TradingFeeMax is the maximum AMM trading fee in basis points (1000 = 1%).
@@ -3,24 +3,71 @@ package shamap
 import (
 	"container/list"
 	"sync"
+	"time"
 )
 
+// Defaults for the process-wide tree node cache, matching rippled's
+// "tree_cache_size" and "tree_cache_age" for a medium node size.
+const (
+	DefaultTreeNodeCacheSize = 262144
+	DefaultTreeNodeCacheAge  = time.Minute
+)
+
+var sharedTreeNodeCache = sync.OnceValue(func() *TreeNodeCache {
+	return NewTreeNodeCacheWithAge(DefaultTreeNodeCacheSize, DefaultTreeNodeCacheAge)
+})
+
+// SharedTreeNodeCache returns the process-wide tree node cache used by
+// NodeStoreFamily unless another cache is set. Nodes are keyed by hash, so
+// one cache serves every ledger and every family.
+func SharedTreeNodeCache() *TreeNodeCache {
+	return sharedTreeNodeCache()
+}
+
 // TreeNodeCache provides an LRU cache for frequently accessed SHAMap nodes.
 // This improves performance by avoiding repeated deserialization and hash computation
 // for nodes that are accessed multiple times during tree operations.
+//
+// Nodes in the cache are shared between SHAMaps: inner nodes are marked
+// shared when added and are copied before a SHAMap modifies them.
+// This is the equivalent of rippled's TreeNodeCache (a TaggedCache).
 type TreeNodeCache struct {
-	mu      sync.RWMutex
-	maxSize int
-	cache   map[[32]byte]*list.Element
-	lruList *list.List
-	hits    uint64
-	misses  uint64
+	mu        sync.RWMutex
+	maxSize   int
+	targetAge time.Duration // 0 = no age bound
+	cache     map[[32]byte]*list.Element
+	lruList   *list.List
+	hits      uint64
+	misses    uint64
+	canonical uint64 // Canonicalize calls answered with an existing node
+	evictions uint64
 }
 
 // cacheEntry represents an entry in the node cache.
 type cacheEntry struct {
-	hash [32]byte
-	node Node
+	hash     [32]byte
+	node     Node
+	accessed time.Time
+}
+
+// TreeNodeCacheStats is a snapshot of a TreeNodeCache's counters.
+type TreeNodeCacheStats struct {
+	Size          int           // Nodes currently cached
+	MaxSize       int           // Size bound
+	TargetAge     time.Duration // Age bound applied by Sweep (0 = none)
+	Hits          uint64        // Lookups that found a node
+	Misses        uint64        // Lookups that found nothing
+	Canonicalized uint64        // Decoded duplicates replaced by the cached node
+	Evictions     uint64        // Nodes removed by the size or age bound
+}
+
+// HitRate returns the fraction of lookups that found a node.
+func (s TreeNodeCacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
 }
 
 // NewTreeNodeCache creates a new TreeNodeCache with the specified maximum size.
@@ -31,14 +78,21 @@ type cacheEntry struct {
 //
 // Returns a new TreeNodeCache instance.
 func NewTreeNodeCache(maxSize int) *TreeNodeCache {
+	return NewTreeNodeCacheWithAge(maxSize, 0)
+}
+
+// NewTreeNodeCacheWithAge creates a TreeNodeCache that also drops nodes
+// not accessed for targetAge when Sweep is called.
+func NewTreeNodeCacheWithAge(maxSize int, targetAge time.Duration) *TreeNodeCache {
 	if maxSize <= 0 {
 		maxSize = 1024 // Default size
 	}
 
 	return &TreeNodeCache{
-		maxSize: maxSize,
-		cache:   make(map[[32]byte]*list.Element, maxSize),
-		lruList: list.New(),
+		maxSize:   maxSize,
+		targetAge: targetAge,
+		cache:     make(map[[32]byte]*list.Element, min(maxSize, 65536)),
+		lruList:   list.New(),
 	}
 }
 
@@ -52,7 +106,9 @@ func (c *TreeNodeCache) Get(hash [32]byte) Node {
 	if elem, found := c.cache[hash]; found {
 		c.hits++
 		c.lruList.MoveToFront(elem)
-		return elem.Value.(*cacheEntry).node
+		entry := elem.Value.(*cacheEntry)
+		entry.accessed = time.Now()
+		return entry.node
 	}
 
 	c.misses++
@@ -66,26 +122,81 @@ func (c *TreeNodeCache) Put(hash [32]byte, node Node) {
 	if node == nil {
 		return
 	}
+	markShared(node)
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if elem, found := c.cache[hash]; found {
 		c.lruList.MoveToFront(elem)
-		elem.Value.(*cacheEntry).node = node
+		entry := elem.Value.(*cacheEntry)
+		entry.node = node
+		entry.accessed = time.Now()
 		return
 	}
 
-	// Evict if necessary
+	c.insert(hash, node)
+}
+
+// Canonicalize returns the cached node for hash if there is one, so that
+// every SHAMap holding the node shares one instance. Otherwise node is
+// added to the cache and returned.
+// Reference: rippled TaggedCache::canonicalize_replace_client
+func (c *TreeNodeCache) Canonicalize(hash [32]byte, node Node) Node {
+	if node == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, found := c.cache[hash]; found {
+		c.canonical++
+		c.lruList.MoveToFront(elem)
+		entry := elem.Value.(*cacheEntry)
+		entry.accessed = time.Now()
+		return entry.node
+	}
+
+	markShared(node)
+	c.insert(hash, node)
+	return node
+}
+
+// insert adds a new entry, evicting from the back as needed.
+// Caller must hold the write lock.
+func (c *TreeNodeCache) insert(hash [32]byte, node Node) {
 	for c.lruList.Len() >= c.maxSize {
 		c.evictOldest()
 	}
 
-	entry := &cacheEntry{hash: hash, node: node}
+	entry := &cacheEntry{hash: hash, node: node, accessed: time.Now()}
 	elem := c.lruList.PushFront(entry)
 	c.cache[hash] = elem
 }
 
+// Sweep removes nodes not accessed within the target age and returns how
+// many were removed. It does nothing for a cache without an age bound.
+func (c *TreeNodeCache) Sweep() int {
+	if c.targetAge <= 0 {
+		return 0
+	}
+	cutoff := time.Now().Add(-c.targetAge)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for elem := c.lruList.Back(); elem != nil; elem = c.lruList.Back() {
+		if elem.Value.(*cacheEntry).accessed.After(cutoff) {
+			break
+		}
+		c.evictOldest()
+		removed++
+	}
+	return removed
+}
+
 // Evict removes a specific node from the cache.
 func (c *TreeNodeCache) Evict(hash [32]byte) {
 	c.mu.Lock()
@@ -105,6 +216,7 @@ func (c *TreeNodeCache) evictOldest() {
 		entry := elem.Value.(*cacheEntry)
 		c.lruList.Remove(elem)
 		delete(c.cache, entry.hash)
+		c.evictions++
 	}
 }
 
@@ -113,7 +225,7 @@ func (c *TreeNodeCache) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.cache = make(map[[32]byte]*list.Element, c.maxSize)
+	c.cache = make(map[[32]byte]*list.Element, min(c.maxSize, 65536))
 	c.lruList = list.New()
 }
 
@@ -136,6 +248,21 @@ func (c *TreeNodeCache) Stats() (hits, misses uint64, size int) {
 	return c.hits, c.misses, c.lruList.Len()
 }
 
+// CacheStats returns a snapshot of the cache counters.
+func (c *TreeNodeCache) CacheStats() TreeNodeCacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return TreeNodeCacheStats{
+		Size:          c.lruList.Len(),
+		MaxSize:       c.maxSize,
+		TargetAge:     c.targetAge,
+		Hits:          c.hits,
+		Misses:        c.misses,
+		Canonicalized: c.canonical,
+		Evictions:     c.evictions,
+	}
+}
+
 // HitRate returns the cache hit rate as a fraction between 0 and 1.
 func (c *TreeNodeCache) HitRate() float64 {
 	c.mu.RLock()
@@ -2,6 +2,7 @@ package shamap
 
 import (
 	"testing"
+	"time"
 )
 
 func TestTreeNodeCache(t *testing.T) {
@@ -221,6 +222,56 @@ func TestTreeNodeCache(t *testing.T) {
 			t.Error("Putting nil should not add to cache")
 		}
 	})
+
+	t.Run("Canonicalize", func(t *testing.T) {
+		cache := NewTreeNodeCache(100)
+
+		first := NewInnerNode()
+		second := NewInnerNode()
+		hash := first.Hash()
+
+		if got := cache.Canonicalize(hash, first); got != Node(first) {
+			t.Fatal("First Canonicalize should return the given node")
+		}
+		if !first.shared.Load() {
+			t.Error("Cached inner node should be marked shared")
+		}
+		if got := cache.Canonicalize(hash, second); got != Node(first) {
+			t.Error("Canonicalize should return the cached instance")
+		}
+		if second.shared.Load() {
+			t.Error("Discarded duplicate should not be marked shared")
+		}
+
+		stats := cache.CacheStats()
+		if stats.Size != 1 || stats.Canonicalized != 1 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("SweepByAge", func(t *testing.T) {
+		cache := NewTreeNodeCacheWithAge(100, time.Hour)
+
+		var old, fresh [32]byte
+		old[0], fresh[0] = 1, 2
+		cache.Put(old, NewInnerNode())
+		cache.Put(fresh, NewInnerNode())
+		cache.cache[old].Value.(*cacheEntry).accessed = time.Now().Add(-2 * time.Hour)
+
+		if removed := cache.Sweep(); removed != 1 {
+			t.Errorf("Sweep should remove 1 stale node, removed %d", removed)
+		}
+		if cache.Contains(old) || !cache.Contains(fresh) {
+			t.Error("Sweep should remove only the stale node")
+		}
+		if stats := cache.CacheStats(); stats.Evictions != 1 {
+			t.Errorf("Evictions should be 1, got %d", stats.Evictions)
+		}
+
+		if removed := NewTreeNodeCache(10).Sweep(); removed != 0 {
+			t.Error("Sweep without an age bound should do nothing")
+		}
+	})
 }
 
 func TestFullBelowCache(t *testing.T) {
@@ -2,6 +2,7 @@ package shamap
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 )
 
@@ -802,3 +803,134 @@ func (sm *SHAMap) walkBranchWithChannel(node Node, otherMapItem *Item, isFirstMa
 
 	return nil
 }
+
+// WalkDifferences calls fn for each difference between sm and other in
+// ascending key order until fn returns false. Differences at or before
+// after, when it is non-nil, are skipped without visiting their subtrees,
+// so a caller can page through a large difference with the last key of
+// the previous page. Subtrees with the same hash in both maps are never
+// visited either, which keeps the walk proportional to the change rather
+// than to the size of the maps.
+func (sm *SHAMap) WalkDifferences(other *SHAMap, after *Key, fn func(DifferenceItem) bool) error {
+	if other == nil {
+		return errors.New("cannot compare with nil map")
+	}
+	// Identical maps have no differences; checking before locking also
+	// spares a map compared with itself a recursive read lock.
+	ourHash, ourErr := sm.Hash()
+	theirHash, theirErr := other.Hash()
+	if ourErr != nil || theirErr != nil {
+		return fmt.Errorf("cannot compare invalid SHAMaps")
+	}
+	if ourHash == theirHash {
+		return nil
+	}
+
+	if sm.lockForRead() {
+		defer sm.mu.RUnlock()
+	}
+	if other != sm && other.lockForRead() {
+		defer other.mu.RUnlock()
+	}
+
+	if sm.state == StateInvalid || other.state == StateInvalid {
+		return fmt.Errorf("cannot compare invalid SHAMaps")
+	}
+
+	_, err := sm.walkDifferences(other, sm.root, other.root, 0, after != nil, after, fn)
+	return err
+}
+
+// walkDifferences compares ours and theirs, the nodes at the same position
+// and depth of sm and other; either may be nil. onPath is set while the
+// position is a prefix of *after. It returns false once fn has.
+func (sm *SHAMap) walkDifferences(other *SHAMap, ours, theirs Node, depth int, onPath bool, after *Key, fn func(DifferenceItem) bool) (bool, error) {
+	if ours == nil && theirs == nil {
+		return true, nil
+	}
+	if ours != nil && theirs != nil && ours.Hash() == theirs.Hash() {
+		return true, nil
+	}
+
+	ourInner, ourIsInner := ours.(*InnerNode)
+	theirInner, theirIsInner := theirs.(*InnerNode)
+	if (ours == nil || ourIsInner) && (theirs == nil || theirIsInner) {
+		first := 0
+		if onPath {
+			first = int(keyNibble(*after, depth))
+		}
+		for i := first; i < BranchFactor; i++ {
+			var ourChild, theirChild Node
+			var err error
+			if ourInner != nil {
+				if ourChild, err = sm.descend(ourInner, i); err != nil {
+					return false, fmt.Errorf("failed to get our child %d: %w", i, err)
+				}
+			}
+			if theirInner != nil {
+				if theirChild, err = other.descend(theirInner, i); err != nil {
+					return false, fmt.Errorf("failed to get other child %d: %w", i, err)
+				}
+			}
+			more, err := sm.walkDifferences(other, ourChild, theirChild, depth+1, onPath && i == first, after, fn)
+			if err != nil || !more {
+				return more, err
+			}
+		}
+		return true, nil
+	}
+
+	// A leaf on at least one side: the differences are among the few
+	// items below this position, merged in key order.
+	ourItems, err := sm.subtreeItems(ours)
+	if err != nil {
+		return false, err
+	}
+	theirItems, err := other.subtreeItems(theirs)
+	if err != nil {
+		return false, err
+	}
+	for len(ourItems) > 0 || len(theirItems) > 0 {
+		var diff DifferenceItem
+		switch {
+		case len(theirItems) == 0 || (len(ourItems) > 0 && bytes.Compare(ourItems[0].key[:], theirItems[0].key[:]) < 0):
+			diff = DifferenceItem{Key: ourItems[0].key, Type: DiffRemoved, FirstItem: ourItems[0]}
+			ourItems = ourItems[1:]
+		case len(ourItems) == 0 || bytes.Compare(theirItems[0].key[:], ourItems[0].key[:]) < 0:
+			diff = DifferenceItem{Key: theirItems[0].key, Type: DiffAdded, SecondItem: theirItems[0]}
+			theirItems = theirItems[1:]
+		default:
+			ourItem, theirItem := ourItems[0], theirItems[0]
+			ourItems, theirItems = ourItems[1:], theirItems[1:]
+			if bytes.Equal(ourItem.Data(), theirItem.Data()) {
+				continue
+			}
+			diff = DifferenceItem{Key: ourItem.key, Type: DiffModified, FirstItem: ourItem, SecondItem: theirItem}
+		}
+		if after != nil && bytes.Compare(diff.Key[:], after[:]) <= 0 {
+			continue
+		}
+		if !fn(diff) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// subtreeItems returns the items below node in key order.
+func (sm *SHAMap) subtreeItems(node Node) ([]*Item, error) {
+	var items []*Item
+	err := sm.forEachUnsafe(node, func(item *Item) bool {
+		items = append(items, item)
+		return true
+	})
+	return items, err
+}
+
+// keyNibble returns the branch key takes at depth.
+func keyNibble(key Key, depth int) uint8 {
+	if depth%2 == 0 {
+		return key[depth/2] >> 4
+	}
+	return key[depth/2] & 0x0F
+}
@@ -1,8 +1,8 @@
 package shamap
 
 // Family provides access to a persistent store for backed SHAMap instances.
-// Each SHAMap independently fetches and deserializes nodes from the Family,
-// ensuring no shared mutable state between SHAMap instances.
+// Unless the Family also implements CachingFamily, each SHAMap independently
+// fetches and deserializes nodes from it.
 type Family interface {
 	// Fetch retrieves a node's serialized data (prefix format) by its SHAMap hash.
 	// Returns nil, nil if the node is not found.
@@ -11,3 +11,26 @@ type Family interface {
 	// StoreBatch persists a batch of serialized nodes.
 	StoreBatch(entries []FlushEntry) error
 }
+
+// CachingFamily is a Family that shares decoded nodes between SHAMaps.
+// Backed SHAMaps load nodes through FetchNode when their Family implements
+// it, so identical nodes in different ledgers are decoded once and held
+// once. Shared inner nodes are copied before a SHAMap modifies them.
+type CachingFamily interface {
+	Family
+
+	// FetchNode returns the canonical decoded node for hash.
+	// Returns nil, nil if the node is not found.
+	FetchNode(hash [32]byte) (Node, error)
+}
+
+// FlushingFamily is a Family that writes a SHAMap's dirty nodes itself,
+// storing each batch while the next is being serialized. Backed SHAMaps
+// flush through it when their Family implements it.
+type FlushingFamily interface {
+	Family
+
+	// FlushMap flushes sm's dirty nodes as FlushDirtyFunc does and stores
+	// them, returning the number of nodes written.
+	FlushMap(sm *SHAMap, releaseChildren bool) (int, error)
+}
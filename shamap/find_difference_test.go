@@ -1,6 +1,8 @@
 package shamap
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"testing"
 )
 
@@ -330,3 +332,128 @@ func TestCollectAllKeysExcept(t *testing.T) {
 		}
 	}
 }
+
+func TestWalkDifferences(t *testing.T) {
+	base, err := New(TypeState)
+	if err != nil {
+		t.Fatalf("Failed to create map: %v", err)
+	}
+	keyOf := func(i int) [32]byte {
+		return sha256.Sum256([]byte{byte(i >> 8), byte(i)})
+	}
+	dataOf := func(i int, version byte) []byte {
+		data := make([]byte, 12)
+		data[0], data[1], data[2] = byte(i>>8), byte(i), version
+		return data
+	}
+	for i := 0; i < 500; i++ {
+		if err := base.Put(keyOf(i), dataOf(i, 0)); err != nil {
+			t.Fatalf("Failed to put: %v", err)
+		}
+	}
+
+	desired, err := base.Snapshot(true)
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	want := map[[32]byte]DifferenceType{}
+	for i := 0; i < 500; i += 7 {
+		if err := desired.Delete(keyOf(i)); err != nil {
+			t.Fatalf("Failed to delete: %v", err)
+		}
+		want[keyOf(i)] = DiffRemoved
+	}
+	for i := 3; i < 500; i += 11 {
+		if _, deleted := want[keyOf(i)]; deleted {
+			continue
+		}
+		if err := desired.Put(keyOf(i), dataOf(i, 1)); err != nil {
+			t.Fatalf("Failed to put: %v", err)
+		}
+		want[keyOf(i)] = DiffModified
+	}
+	for i := 500; i < 540; i++ {
+		if err := desired.Put(keyOf(i), dataOf(i, 0)); err != nil {
+			t.Fatalf("Failed to put: %v", err)
+		}
+		want[keyOf(i)] = DiffAdded
+	}
+
+	var all []DifferenceItem
+	if err := base.WalkDifferences(desired, nil, func(d DifferenceItem) bool {
+		all = append(all, d)
+		return true
+	}); err != nil {
+		t.Fatalf("WalkDifferences failed: %v", err)
+	}
+	if len(all) != len(want) {
+		t.Fatalf("Expected %d differences, got %d", len(want), len(all))
+	}
+	for i, d := range all {
+		if want[d.Key] != d.Type {
+			t.Errorf("Key %x: expected %v, got %v", d.Key[:4], want[d.Key], d.Type)
+		}
+		if i > 0 && bytes.Compare(all[i-1].Key[:], d.Key[:]) >= 0 {
+			t.Fatalf("Differences not in key order at %d", i)
+		}
+	}
+
+	// Paging with the last key of each page visits the same differences.
+	var paged []DifferenceItem
+	var after *Key
+	for {
+		var page []DifferenceItem
+		if err := base.WalkDifferences(desired, after, func(d DifferenceItem) bool {
+			page = append(page, d)
+			return len(page) < 16
+		}); err != nil {
+			t.Fatalf("WalkDifferences failed: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		paged = append(paged, page...)
+		last := page[len(page)-1].Key
+		after = &last
+	}
+	if len(paged) != len(all) {
+		t.Fatalf("Paging found %d differences, expected %d", len(paged), len(all))
+	}
+	for i := range all {
+		if paged[i].Key != all[i].Key {
+			t.Fatalf("Paging differs at %d", i)
+		}
+	}
+
+	// A map compared with itself, even while it is being written, and a
+	// snapshot of it have no differences.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 600; i < 700; i++ {
+			if err := desired.Put(keyOf(i), dataOf(i, 0)); err != nil {
+				t.Errorf("Failed to put: %v", err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if err := desired.WalkDifferences(desired, nil, func(d DifferenceItem) bool {
+			t.Errorf("Unexpected difference at %x", d.Key[:4])
+			return false
+		}); err != nil {
+			t.Fatalf("WalkDifferences failed: %v", err)
+		}
+	}
+	<-done
+	same, err := desired.Snapshot(false)
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if err := desired.WalkDifferences(same, nil, func(d DifferenceItem) bool {
+		t.Errorf("Unexpected difference at %x", d.Key[:4])
+		return false
+	}); err != nil {
+		t.Fatalf("WalkDifferences failed: %v", err)
+	}
+}
@@ -159,6 +159,27 @@ func FuzzSHAMapOperations(f *testing.F) {
 			t.Fatal("Hash() not deterministic")
 		}
 
+		// Deferred, subtree-parallel hashing must match hashing after
+		// every insert into a freshly built map
+		ref, err := New(TypeState)
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		for key, expectedData := range oracle {
+			if err := ref.Put(key, expectedData); err != nil {
+				t.Fatalf("reference Put failed: %v", err)
+			}
+			if _, err := ref.Hash(); err != nil {
+				t.Fatalf("reference Hash() failed: %v", err)
+			}
+		}
+		if refHash, _ := ref.Hash(); refHash != hash1 {
+			t.Fatalf("Hash() %x differs from reference %x", hash1[:4], refHash[:4])
+		}
+		if err := sm.Invariants(); err != nil {
+			t.Fatalf("Invariants failed: %v", err)
+		}
+
 		// Final verification: all oracle entries retrievable
 		for key, expectedData := range oracle {
 			item, found, err := sm.Get(key)
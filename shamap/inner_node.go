@@ -7,6 +7,7 @@ import (
 	"math/bits"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/LeJamon/goXRPLd/protocol"
 )
@@ -21,10 +22,29 @@ var (
 // InnerNode represents an inner node in the SHAMap tree
 type InnerNode struct {
 	BaseNode
-	mu       sync.RWMutex
-	children [BranchFactor]Node
+	mu sync.RWMutex
+
+	// children holds the loaded child nodes. Each slot is published
+	// atomically so readers can follow child pointers without n.mu, while
+	// lazily-loaded nodes are attached through publishChild.
+	children [BranchFactor]atomic.Pointer[childRef]
 	hashes   [BranchFactor][32]byte
 	isBranch uint16
+
+	// shared is set once the node is published in a TreeNodeCache. A
+	// shared node may be reachable from several SHAMaps, so it is never
+	// modified in place; see mutable.
+	shared atomic.Bool
+
+	// stale is set when a child changes. The node's hash, and the stored
+	// hashes of its loaded children, are recomputed the next time the hash
+	// is needed, so a batch of changes hashes each inner node once.
+	stale atomic.Bool
+}
+
+// childRef boxes a child Node so it can be stored in an atomic.Pointer.
+type childRef struct {
+	node Node
 }
 
 // NewInnerNode creates a new empty inner node
@@ -80,15 +100,31 @@ func (n *InnerNode) Child(index int) (Node, error) {
 		return nil, ErrInvalidBranch
 	}
 
-	n.mu.RLock()
-	defer n.mu.RUnlock()
-	return n.children[index], nil
+	return n.child(index), nil
 }
 
 // ChildUnsafe returns the child without bounds checking or locking
-// Use only when you're certain the index is valid and you hold the lock
+// Use only when you're certain the index is valid
 func (n *InnerNode) ChildUnsafe(index int) Node {
-	return n.children[index]
+	return n.child(index)
+}
+
+// child loads the child pointer at index. Child pointers are atomic, so
+// no lock is needed.
+func (n *InnerNode) child(index int) Node {
+	if ref := n.children[index].Load(); ref != nil {
+		return ref.node
+	}
+	return nil
+}
+
+// storeChild atomically replaces the child pointer at index.
+func (n *InnerNode) storeChild(index int, child Node) {
+	if child == nil {
+		n.children[index].Store(nil)
+		return
+	}
+	n.children[index].Store(&childRef{node: child})
 }
 
 // SetChild sets the child node at the given branch index
@@ -100,9 +136,8 @@ func (n *InnerNode) SetChild(index int, child Node) error {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
-	n.children[index] = child
+	n.storeChild(index, child)
 	if child != nil {
-		n.hashes[index] = child.Hash()
 		n.isBranch |= 1 << index
 	} else {
 		n.hashes[index] = [32]byte{}
@@ -110,7 +145,8 @@ func (n *InnerNode) SetChild(index int, child Node) error {
 	}
 
 	n.dirty = true
-	return n.updateHashUnsafe()
+	n.stale.Store(true)
+	return nil
 }
 
 // SetChildDirect sets the child pointer without updating hash or dirty flag.
@@ -122,7 +158,56 @@ func (n *InnerNode) SetChildDirect(index int, child Node) {
 	}
 	n.mu.Lock()
 	defer n.mu.Unlock()
-	n.children[index] = child
+	n.storeChild(index, child)
+}
+
+// publishChild attaches a node fetched from the store at index. The fetch
+// happens without any lock held, so by the time it completes another
+// reader may already have attached the same node, or a writer may have
+// replaced the branch. publishChild returns the child now in place, or nil
+// if the branch no longer holds the fetched node's hash.
+func (n *InnerNode) publishChild(index int, child Node) Node {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if existing := n.child(index); existing != nil {
+		return existing
+	}
+	if n.hashes[index] != child.Hash() {
+		return nil
+	}
+	n.storeChild(index, child)
+	return child
+}
+
+// mutable returns n if it belongs to a single SHAMap, or a private copy
+// of it if it is shared through a TreeNodeCache. The copy keeps n's child
+// pointers and hashes; the caller then modifies the copy and links it into
+// its own tree in place of n.
+// Reference: rippled SHAMap::unshareNode
+func (n *InnerNode) mutable() *InnerNode {
+	if !n.shared.Load() {
+		return n
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	c := &InnerNode{
+		BaseNode: BaseNode{hash: n.hash},
+		hashes:   n.hashes,
+		isBranch: n.isBranch,
+	}
+	for i := 0; i < BranchFactor; i++ {
+		c.children[i].Store(n.children[i].Load())
+	}
+	return c
+}
+
+// markShared flags an inner node as shared between SHAMaps. Leaf nodes
+// are never modified in place, so they need no flag.
+func markShared(node Node) {
+	if inner, ok := node.(*InnerNode); ok {
+		inner.shared.Store(true)
+	}
 }
 
 // ChildHash returns the hash at a given branch index
@@ -131,16 +216,31 @@ func (n *InnerNode) ChildHash(index int) ([32]byte, error) {
 		return [32]byte{}, ErrInvalidBranch
 	}
 
+	n.Hash()
 	n.mu.RLock()
 	defer n.mu.RUnlock()
 	return n.hashes[index], nil
 }
 
-// ChildHashUnsafe returns the hash without bounds checking or locking
+// ChildHashUnsafe returns the hash without bounds checking or locking.
+// For a loaded child that changed since n was last hashed it may be out
+// of date; unloaded children always have their stored hash.
 func (n *InnerNode) ChildHashUnsafe(index int) [32]byte {
 	return n.hashes[index]
 }
 
+// Hash returns the node's hash, first recomputing it if a child changed.
+func (n *InnerNode) Hash() [32]byte {
+	if n.stale.Load() {
+		n.mu.Lock()
+		if n.stale.Load() {
+			_ = n.updateHashUnsafe()
+		}
+		n.mu.Unlock()
+	}
+	return n.hash
+}
+
 // UpdateHash recalculates the node's hash from its children
 func (n *InnerNode) UpdateHash() error {
 	n.mu.Lock()
@@ -148,8 +248,12 @@ func (n *InnerNode) UpdateHash() error {
 	return n.updateHashUnsafe()
 }
 
-// updateHashUnsafe updates hash without locking (caller must hold lock)
+// updateHashUnsafe updates hash without locking (caller must hold lock).
+// The stored hashes of loaded children are refreshed first; hashing a
+// stale child recurses into its subtree.
 func (n *InnerNode) updateHashUnsafe() error {
+	defer n.stale.Store(false)
+
 	if n.isBranch == 0 {
 		// Empty node - hash is zero
 		n.hash = [32]byte{}
@@ -166,11 +270,11 @@ func (n *InnerNode) updateHashUnsafe() error {
 	zeroHash := make([]byte, 32)
 	for i := 0; i < BranchFactor; i++ {
 		if n.isBranch&(1<<i) != 0 {
-			child := n.children[i]
+			child := n.child(i)
 			if child != nil {
 				// Get hash from actual child node
-				childHash := child.Hash()
-				data = append(data, childHash[:])
+				n.hashes[i] = child.Hash()
+				data = append(data, n.hashes[i][:])
 			} else {
 				// Child is nil but branch is set - use stored hash
 				// This happens when node is deserialized from wire format
@@ -190,6 +294,7 @@ func (n *InnerNode) updateHashUnsafe() error {
 }
 
 func (n *InnerNode) SerializeForWire() ([]byte, error) {
+	n.Hash()
 	n.mu.RLock()
 	defer n.mu.RUnlock()
 
@@ -235,6 +340,7 @@ func (n *InnerNode) SerializeForWire() ([]byte, error) {
 
 // SerializeWithPrefix serializes with type prefix for hashing and storage
 func (n *InnerNode) SerializeWithPrefix() ([]byte, error) {
+	n.Hash()
 	n.mu.RLock()
 	defer n.mu.RUnlock()
 
@@ -356,6 +462,7 @@ func parseCompressedInnerNode(data []byte) (*InnerNode, error) {
 
 // String returns a human-readable representation of the node
 func (n *InnerNode) String(id NodeID) string {
+	n.Hash()
 	n.mu.RLock()
 	defer n.mu.RUnlock()
 
@@ -375,12 +482,14 @@ func (n *InnerNode) String(id NodeID) string {
 
 // Invariants performs internal consistency checks
 func (n *InnerNode) Invariants(isRoot bool) error {
+	n.Hash()
 	n.mu.RLock()
 	defer n.mu.RUnlock()
 
 	count := 0
 	for i := 0; i < BranchFactor; i++ {
-		hasChild := n.children[i] != nil
+		child := n.child(i)
+		hasChild := child != nil
 		hasBit := (n.isBranch & (1 << i)) != 0
 		hasHash := !isZeroHash(n.hashes[i])
 
@@ -401,7 +510,7 @@ func (n *InnerNode) Invariants(isRoot bool) error {
 		if hasChild {
 			count++
 			// Verify child hash matches stored hash
-			childHash := n.children[i].Hash()
+			childHash := child.Hash()
 			if childHash != n.hashes[i] {
 				return fmt.Errorf("branch %d hash mismatch", i)
 			}
@@ -417,11 +526,11 @@ func (n *InnerNode) Invariants(isRoot bool) error {
 
 	// Verify hash is correct
 	if !n.IsZeroHash() {
-		// Create a temporary copy to verify hash
+		// Create a temporary copy to verify hash. Loaded children were
+		// checked against the stored hashes above, so the hashes suffice.
 		temp := &InnerNode{
 			isBranch: n.isBranch,
 			hashes:   n.hashes,
-			children: n.children,
 		}
 		if err := temp.updateHashUnsafe(); err != nil {
 			return fmt.Errorf("failed to verify hash: %w", err)
@@ -444,15 +553,16 @@ func (n *InnerNode) Clone() (Node, error) {
 		isBranch: n.isBranch,
 		hashes:   n.hashes, // Copy the array
 	}
+	clone.stale.Store(n.stale.Load())
 
 	// Deep clone children
 	for i := 0; i < BranchFactor; i++ {
-		if n.children[i] != nil {
-			childClone, err := n.children[i].Clone()
+		if child := n.child(i); child != nil {
+			childClone, err := child.Clone()
 			if err != nil {
 				return nil, fmt.Errorf("failed to clone child at branch %d: %w", i, err)
 			}
-			clone.children[i] = childClone
+			clone.storeChild(i, childClone)
 		}
 	}
 
@@ -466,8 +576,8 @@ func (n *InnerNode) ForEachChild(fn func(index int, child Node) bool) {
 	defer n.mu.RUnlock()
 
 	for i := 0; i < BranchFactor; i++ {
-		if n.children[i] != nil {
-			if !fn(i, n.children[i]) {
+		if child := n.child(i); child != nil {
+			if !fn(i, child) {
 				break
 			}
 		}
@@ -36,8 +36,9 @@ func (it *Iterator) Next() bool {
 		return false
 	}
 
-	it.sm.mu.RLock()
-	defer it.sm.mu.RUnlock()
+	if it.sm.lockForRead() {
+		defer it.sm.mu.RUnlock()
+	}
 
 	if !it.started {
 		it.started = true
@@ -132,8 +133,9 @@ func (sm *SHAMap) Begin() *Iterator {
 		stack: make([]iterStackEntry, 0, MaxDepth),
 	}
 
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
+	if sm.lockForRead() {
+		defer sm.mu.RUnlock()
+	}
 
 	if sm.root != nil {
 		it.stack = append(it.stack, iterStackEntry{
@@ -157,8 +159,9 @@ func (sm *SHAMap) UpperBound(id [32]byte) *Iterator {
 		started: true,
 	}
 
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
+	if sm.lockForRead() {
+		defer sm.mu.RUnlock()
+	}
 
 	if sm.root == nil {
 		return it
@@ -280,8 +283,9 @@ func (sm *SHAMap) LowerBound(id [32]byte) *Iterator {
 		started: true,
 	}
 
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
+	if sm.lockForRead() {
+		defer sm.mu.RUnlock()
+	}
 
 	if sm.root == nil {
 		return it
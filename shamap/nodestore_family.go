@@ -2,6 +2,7 @@ package shamap
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/LeJamon/goXRPLd/storage/kvstore/memorydb"
@@ -17,16 +18,35 @@ import (
 // as Node.Data — the nodestore treats it as opaque bytes. This matches rippled's approach
 // where the hash prefix is stored alongside the node data in the NodeStore.
 //
+// Decoded nodes are shared through a TreeNodeCache, by default the
+// process-wide SharedTreeNodeCache(), so a node common to the open, closed
+// and historic ledgers is decoded once and held once, as with rippled's
+// NodeFamily and its TreeNodeCache. The nodestore's own cache still holds
+// the serialized blobs underneath.
+//
 // For tests: use NewMemoryNodeStoreFamily() — in-memory, zero disk I/O.
 // For production: use NewPebbleNodeStoreFamily() with a persistent path.
 type NodeStoreFamily struct {
-	db nodestore.Database
+	db    nodestore.Database
+	cache *TreeNodeCache
 }
 
 // NewNodeStoreFamily creates a Family backed by the given nodestore.Database.
 // The Database should already be opened and configured with caching.
 func NewNodeStoreFamily(db nodestore.Database) *NodeStoreFamily {
-	return &NodeStoreFamily{db: db}
+	return &NodeStoreFamily{db: db, cache: SharedTreeNodeCache()}
+}
+
+// SetTreeNodeCache replaces the tree node cache. A nil cache disables
+// node sharing, so every SHAMap decodes its own copy of each node.
+// Must be called before the family is used.
+func (f *NodeStoreFamily) SetTreeNodeCache(cache *TreeNodeCache) {
+	f.cache = cache
+}
+
+// TreeNodeCache returns the cache of decoded nodes, or nil if disabled.
+func (f *NodeStoreFamily) TreeNodeCache() *TreeNodeCache {
+	return f.cache
 }
 
 // NewMemoryNodeStoreFamily creates a Family backed by an in-memory kvstore.
@@ -73,6 +93,36 @@ func (f *NodeStoreFamily) Fetch(hash [32]byte) ([]byte, error) {
 	return node.Data, nil
 }
 
+// FetchNode returns the canonical decoded node for hash, consulting the
+// tree node cache before the nodestore. A node decoded here is added to
+// the cache unless another SHAMap added the same node first, in which case
+// that instance is returned instead.
+// Returns nil, nil if the node is not found.
+func (f *NodeStoreFamily) FetchNode(hash [32]byte) (Node, error) {
+	if f.cache != nil {
+		if node := f.cache.Get(hash); node != nil {
+			return node, nil
+		}
+	}
+
+	data, err := f.Fetch(hash)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	node, err := DeserializeFromPrefix(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize node: %w", err)
+	}
+	if node.Hash() != hash {
+		return nil, fmt.Errorf("node %x: stored data hashes to %x", hash[:8], node.Hash())
+	}
+
+	if f.cache == nil {
+		return node, nil
+	}
+	return f.cache.Canonicalize(hash, node), nil
+}
+
 // StoreBatch persists a batch of serialized nodes to the nodestore.
 // Each FlushEntry's Data contains prefix-format bytes which are stored directly
 // as Node.Data (opaque to the nodestore). The Hash is set from FlushEntry.Hash
@@ -84,19 +134,65 @@ func (f *NodeStoreFamily) StoreBatch(entries []FlushEntry) error {
 
 	nodes := make([]*nodestore.Node, len(entries))
 	for i, e := range entries {
-		nodes[i] = &nodestore.Node{
-			Hash: nodestore.Hash256(e.Hash),
-			Data: e.Data,
-			Type: nodestore.NodeAccount, // NodeStore treats data as opaque; type is for categorization only
-		}
+		nodes[i] = flushEntryNode(e)
 	}
 	return f.db.StoreBatch(context.Background(), nodes)
 }
 
-// Sweep removes expired entries from the nodestore's caches.
+// FlushMap flushes sm's dirty nodes into a nodestore.BatchWriter, which
+// stores each batch of DefaultFlushBatchSize nodes in the background while
+// the next batch is serialized.
+func (f *NodeStoreFamily) FlushMap(sm *SHAMap, releaseChildren bool) (int, error) {
+	bw, err := nodestore.NewDatabaseBatchWriter(f.db, &nodestore.BatchWriteConfig{
+		PreallocationSize: DefaultFlushBatchSize,
+		LimitSize:         DefaultFlushBatchSize,
+		FlushInterval:     nodestore.DefaultFlushInterval,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := sm.FlushDirtyFunc(releaseChildren, DefaultFlushBatchSize, func(entries []FlushEntry) error {
+		for _, e := range entries {
+			if err := bw.Add(flushEntryNode(e)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if cerr := bw.Close(); err == nil {
+		err = cerr
+	}
+	return count, err
+}
+
+// flushEntryNode wraps a flushed SHAMap node for the nodestore, typed
+// after the map it belongs to as rippled's SHAMap::flushDirty does
+// (hotACCOUNT_NODE for state maps, hotTRANSACTION_NODE for transaction
+// maps).
+func flushEntryNode(e FlushEntry) *nodestore.Node {
+	nodeType := nodestore.NodeUnknown
+	switch e.MapType {
+	case TypeState:
+		nodeType = nodestore.NodeAccount
+	case TypeTransaction:
+		nodeType = nodestore.NodeTransaction
+	}
+	return &nodestore.Node{
+		Hash: nodestore.Hash256(e.Hash),
+		Data: e.Data,
+		Type: nodeType,
+	}
+}
+
+// Sweep removes expired entries from the tree node cache and the
+// nodestore's caches.
 // Should be called periodically (e.g., on each ledger close) to bound memory usage.
 // This matches rippled's pattern of calling sweep() on NodeFamily.
 func (f *NodeStoreFamily) Sweep() error {
+	if f.cache != nil {
+		f.cache.Sweep()
+	}
 	return f.db.Sweep()
 }
 
@@ -106,6 +202,15 @@ func (f *NodeStoreFamily) Stats() nodestore.Statistics {
 	return f.db.Stats()
 }
 
+// TreeNodeCacheStats returns hit, miss and canonicalization counts for
+// the tree node cache. The cache may be shared with other families.
+func (f *NodeStoreFamily) TreeNodeCacheStats() TreeNodeCacheStats {
+	if f.cache == nil {
+		return TreeNodeCacheStats{}
+	}
+	return f.cache.CacheStats()
+}
+
 // Close gracefully shuts down the underlying nodestore, flushing any pending
 // writes and releasing resources. Must be called on shutdown.
 func (f *NodeStoreFamily) Close() error {
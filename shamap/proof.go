@@ -19,11 +19,23 @@ type ProofPath struct {
 // The path consists of serialized nodes from leaf to root.
 // Returns nil if the key does not exist in the map.
 func (sm *SHAMap) GetProofPath(key [32]byte) (*ProofPath, error) {
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
+	var proof *ProofPath
+	err := sm.read(func(deferFetch bool) (err error) {
+		proof, err = sm.proofPath(key, deferFetch)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
 
+// proofPath builds the proof path for GetProofPath. The nodes are
+// serialized during the walk so that a mutable map cannot change them
+// before the path is complete.
+func (sm *SHAMap) proofPath(key [32]byte, deferFetch bool) (*ProofPath, error) {
 	stack := NewNodeStack()
-	leaf, err := sm.walkToKey(key, stack)
+	leaf, err := sm.walkToKey(key, stack, deferFetch)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,148 @@
+package shamap
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultScrubMaxReported is the default number of missing and corrupt
+// node hashes a ScrubResult keeps.
+const DefaultScrubMaxReported = 100
+
+// ScrubOptions configures ScrubTree.
+type ScrubOptions struct {
+	// MaxReported limits the missing and corrupt hashes kept in the
+	// result; the counts are always complete.
+	// Default is DefaultScrubMaxReported.
+	MaxReported int
+
+	// Skip is called before a node is fetched. Returning true leaves the
+	// node and everything below it unvisited, for example because an
+	// earlier walk already covered that subtree. Can be nil.
+	Skip func(hash [32]byte) bool
+
+	// Visit is called with the stored data of every node that verified.
+	// An error stops the walk and is returned by ScrubTree. Can be nil.
+	Visit func(hash [32]byte, data []byte) error
+}
+
+// ScrubResult reports what ScrubTree found below a root.
+type ScrubResult struct {
+	Inner   int64 // Inner nodes that verified
+	Leaves  int64 // Leaf nodes that verified
+	Bytes   int64 // Stored bytes of the nodes that verified
+	Skipped int64 // Subtrees left out by ScrubOptions.Skip
+
+	MissingCount int64      // Referenced nodes the store does not hold
+	CorruptCount int64      // Stored nodes that do not decode to their hash
+	Missing      [][32]byte // First MaxReported missing hashes
+	Corrupt      [][32]byte // First MaxReported corrupt hashes
+}
+
+// IsValid returns true if no missing or corrupt node was found.
+func (r *ScrubResult) IsValid() bool {
+	return r.MissingCount == 0 && r.CorruptCount == 0
+}
+
+// Add merges other into r, keeping at most max hashes of each kind.
+func (r *ScrubResult) Add(other *ScrubResult, max int) {
+	r.Inner += other.Inner
+	r.Leaves += other.Leaves
+	r.Bytes += other.Bytes
+	r.Skipped += other.Skipped
+	r.MissingCount += other.MissingCount
+	r.CorruptCount += other.CorruptCount
+	r.Missing = appendLimited(r.Missing, other.Missing, max)
+	r.Corrupt = appendLimited(r.Corrupt, other.Corrupt, max)
+}
+
+// String returns a one-line summary of the result.
+func (r *ScrubResult) String() string {
+	return fmt.Sprintf("inner=%d leaves=%d bytes=%d skipped=%d missing=%d corrupt=%d",
+		r.Inner, r.Leaves, r.Bytes, r.Skipped, r.MissingCount, r.CorruptCount)
+}
+
+func appendLimited(dst, src [][32]byte, max int) [][32]byte {
+	for _, h := range src {
+		if len(dst) >= max {
+			break
+		}
+		dst = append(dst, h)
+	}
+	return dst
+}
+
+// ScrubTree walks the tree below root through family without building a
+// SHAMap, checking that every referenced node is stored and that its data
+// decodes to a node with the hash it is stored under. The children of a
+// corrupt inner node cannot be known, so its subtree is not walked. A
+// zero root is an empty tree.
+//
+// The returned error reports a failing fetch, a cancelled context or an
+// error from ScrubOptions.Visit; missing and corrupt nodes are reported
+// in the result only.
+// Reference: rippled SHAMap::walkMap
+func ScrubTree(ctx context.Context, family Family, root [32]byte, opts *ScrubOptions) (*ScrubResult, error) {
+	if opts == nil {
+		opts = &ScrubOptions{}
+	}
+	max := opts.MaxReported
+	if max <= 0 {
+		max = DefaultScrubMaxReported
+	}
+
+	result := &ScrubResult{}
+	if isZeroHash(root) {
+		return result, nil
+	}
+
+	stack := [][32]byte{root}
+	for len(stack) > 0 {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		hash := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if opts.Skip != nil && opts.Skip(hash) {
+			result.Skipped++
+			continue
+		}
+
+		data, err := family.Fetch(hash)
+		if err != nil {
+			return result, fmt.Errorf("fetch node %x: %w", hash[:8], err)
+		}
+		if data == nil {
+			result.MissingCount++
+			result.Missing = appendLimited(result.Missing, [][32]byte{hash}, max)
+			continue
+		}
+
+		node, err := DeserializeFromPrefix(data)
+		if err != nil || node.Hash() != hash {
+			result.CorruptCount++
+			result.Corrupt = appendLimited(result.Corrupt, [][32]byte{hash}, max)
+			continue
+		}
+
+		if inner, ok := node.(*InnerNode); ok {
+			result.Inner++
+			for i := BranchFactor - 1; i >= 0; i-- {
+				if !inner.IsEmptyBranch(i) {
+					stack = append(stack, inner.ChildHashUnsafe(i))
+				}
+			}
+		} else {
+			result.Leaves++
+		}
+		result.Bytes += int64(len(data))
+
+		if opts.Visit != nil {
+			if err := opts.Visit(hash, data); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package shamap
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+)
+
+// newScrubTestTree flushes a state map of n items into a fresh family and
+// returns the family and the root hash.
+func newScrubTestTree(t *testing.T, n int) (*memoryFamily, [32]byte) {
+	t.Helper()
+	sm, err := New(TypeState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		key := sha256.Sum256(intToBytes(i))
+		if err := sm.Put(key, intToBytes(i+1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	family := newMemoryFamily()
+	if err := flushToFamily(sm, family); err != nil {
+		t.Fatal(err)
+	}
+	root, err := sm.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return family, root
+}
+
+func TestScrubTree_Valid(t *testing.T) {
+	family, root := newScrubTestTree(t, 200)
+
+	visited := 0
+	result, err := ScrubTree(context.Background(), family, root, &ScrubOptions{
+		Visit: func(hash [32]byte, data []byte) error {
+			visited++
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsValid() {
+		t.Fatalf("expected valid tree, got %s", result)
+	}
+	if result.Leaves != 200 {
+		t.Errorf("expected 200 leaves, got %d", result.Leaves)
+	}
+	if got := int(result.Inner + result.Leaves); got != family.Len() || visited != got {
+		t.Errorf("walked %d nodes and visited %d, family holds %d", got, visited, family.Len())
+	}
+}
+
+func TestScrubTree_EmptyRoot(t *testing.T) {
+	result, err := ScrubTree(context.Background(), newMemoryFamily(), [32]byte{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsValid() || result.Inner+result.Leaves != 0 {
+		t.Errorf("expected empty result, got %s", result)
+	}
+}
+
+func TestScrubTree_MissingAndCorrupt(t *testing.T) {
+	family, root := newScrubTestTree(t, 50)
+
+	// Drop one leaf and garble another.
+	var missing, corrupt [32]byte
+	for hash, data := range family.store {
+		node, err := DeserializeFromPrefix(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !node.IsLeaf() {
+			continue
+		}
+		if missing == ([32]byte{}) {
+			missing = hash
+		} else if corrupt == ([32]byte{}) {
+			corrupt = hash
+		}
+	}
+	delete(family.store, missing)
+	family.store[corrupt][len(family.store[corrupt])-1] ^= 0xFF
+
+	result, err := ScrubTree(context.Background(), family, root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MissingCount != 1 || len(result.Missing) != 1 || result.Missing[0] != missing {
+		t.Errorf("expected missing %x, got %v", missing[:4], result.Missing)
+	}
+	if result.CorruptCount != 1 || len(result.Corrupt) != 1 || result.Corrupt[0] != corrupt {
+		t.Errorf("expected corrupt %x, got %v", corrupt[:4], result.Corrupt)
+	}
+	if result.Leaves != 48 {
+		t.Errorf("expected 48 verified leaves, got %d", result.Leaves)
+	}
+}
+
+func TestScrubTree_Skip(t *testing.T) {
+	family, root := newScrubTestTree(t, 20)
+
+	result, err := ScrubTree(context.Background(), family, root, &ScrubOptions{
+		Skip: func(hash [32]byte) bool { return hash == root },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Skipped != 1 || result.Inner+result.Leaves != 0 {
+		t.Errorf("expected only the root to be skipped, got %s", result)
+	}
+}
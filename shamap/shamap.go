@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 )
 
 // Common errors
@@ -66,12 +67,19 @@ func (t Type) String() string {
 	}
 }
 
-// SHAMap is the main structure representing the tree
+// SHAMap is the main structure representing the tree.
+//
+// Mutable maps guard their structure with mu, but never hold it across a
+// Family fetch: a walk that reaches a node that is not loaded releases the
+// lock, fetches the node, attaches it and walks again. Immutable maps are
+// never restructured, so Get, Has, ForEach, the iterators and BulkGetNodes
+// read them without taking mu at all.
 type SHAMap struct {
 	mu        sync.RWMutex
 	root      *InnerNode
 	mapType   Type
 	state     State
+	immutable atomic.Bool // state == StateImmutable, readable without mu
 	ledgerSeq uint32
 	full      bool
 	backed    bool
@@ -110,7 +118,8 @@ func NewBacked(mapType Type, family Family) (*SHAMap, error) {
 }
 
 // SetFamily sets the Family on an existing SHAMap, enabling backed mode.
-// This allows converting an unbacked map to a backed map.
+// This allows converting an unbacked map to a backed map. It must not be
+// called on an immutable map that other goroutines are reading.
 func (sm *SHAMap) SetFamily(family Family) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
@@ -126,21 +135,15 @@ func NewFromRootHash(mapType Type, rootHash [32]byte, family Family) (*SHAMap, e
 		return nil, errors.New("family is required for backed SHAMap")
 	}
 
-	// Fetch root node from store
-	data, err := family.Fetch(rootHash)
+	// Fetch root node from store — an InnerNode with hashes set, children nil
+	node, err := fetchNode(family, rootHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch root node: %w", err)
 	}
-	if data == nil {
+	if node == nil {
 		return nil, fmt.Errorf("root node %x not found in store", rootHash[:8])
 	}
 
-	// Deserialize — creates InnerNode with hashes set, children nil
-	node, err := DeserializeFromPrefix(data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to deserialize root node: %w", err)
-	}
-
 	root, ok := node.(*InnerNode)
 	if !ok {
 		return nil, fmt.Errorf("root node is not an InnerNode, got %T", node)
@@ -156,15 +159,40 @@ func NewFromRootHash(mapType Type, rootHash [32]byte, family Family) (*SHAMap, e
 	}, nil
 }
 
+// fetchNode loads a node from a Family. A CachingFamily returns its
+// canonical, possibly shared, instance; otherwise the node is deserialized
+// into a fresh copy owned by the caller. Returns nil, nil if not found.
+func fetchNode(family Family, hash [32]byte) (Node, error) {
+	if cf, ok := family.(CachingFamily); ok {
+		return cf.FetchNode(hash)
+	}
+
+	data, err := family.Fetch(hash)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	node, err := DeserializeFromPrefix(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize node: %w", err)
+	}
+	return node, nil
+}
+
 // descend returns the child node at the given branch of an inner node.
 // For backed maps, if the child pointer is nil but the hash is set,
-// the node is fetched from the Family and deserialized.
-// Each SHAMap gets its own deserialized copy — no shared mutable state.
+// the node is fetched from the Family and attached to the parent.
 func (sm *SHAMap) descend(inner *InnerNode, branch int) (Node, error) {
+	return sm.descendOrDefer(inner, branch, false)
+}
+
+// descendOrDefer is descend, except that with deferFetch set a child that
+// is not loaded is reported as a *pendingFetch error instead of being
+// fetched, so the caller can release sm.mu before doing the I/O.
+func (sm *SHAMap) descendOrDefer(inner *InnerNode, branch int, deferFetch bool) (Node, error) {
 	// Fast path: child already loaded in memory
-	child := inner.ChildUnsafe(branch)
-	if child != nil {
-		return child, nil
+	child, err := inner.Child(branch)
+	if err != nil || child != nil {
+		return child, err
 	}
 
 	// Not backed: nothing to lazy-load
@@ -172,32 +200,114 @@ func (sm *SHAMap) descend(inner *InnerNode, branch int) (Node, error) {
 		return nil, nil
 	}
 
-	// Check if branch has a hash (i.e., non-empty but not yet loaded)
+	// Check if branch has a hash (i.e., non-empty but not yet loaded).
+	// Hashes only change under sm.mu, or never for immutable maps.
 	hash := inner.ChildHashUnsafe(branch)
 	if isZeroHash(hash) {
 		return nil, nil
 	}
 
-	// Fetch from store
-	data, err := sm.family.Fetch(hash)
+	pending := &pendingFetch{parent: inner, branch: branch, hash: hash, family: sm.family}
+	if deferFetch {
+		return nil, pending
+	}
+	return pending.load()
+}
+
+// pendingFetch describes a child that a walk found missing from memory.
+// It is returned as an error so that it unwinds the walk.
+type pendingFetch struct {
+	parent *InnerNode
+	branch int
+	hash   [32]byte
+	family Family
+}
+
+func (p *pendingFetch) Error() string {
+	return fmt.Sprintf("child node %x not loaded", p.hash[:8])
+}
+
+// load fetches the child from the Family and publishes it in the parent.
+// It needs no SHAMap lock. If the branch was rewritten during the fetch the
+// node is dropped and load returns nil; the caller walks again.
+func (p *pendingFetch) load() (Node, error) {
+	node, err := fetchNode(p.family, p.hash)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch child node %x: %w", hash[:8], err)
+		return nil, fmt.Errorf("failed to fetch child node %x: %w", p.hash[:8], err)
 	}
-	if data == nil {
-		return nil, fmt.Errorf("child node %x not found in store", hash[:8])
+	if node == nil {
+		return nil, fmt.Errorf("child node %x not found in store", p.hash[:8])
 	}
+	return p.parent.publishChild(p.branch, node), nil
+}
 
-	// Deserialize (fresh copy — not shared with other SHAMaps)
-	node, err := DeserializeFromPrefix(data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to deserialize child node: %w", err)
+// read runs a walk over the map. Immutable maps are walked without sm.mu,
+// fetching missing nodes inline. Mutable maps are walked under the read
+// lock with deferFetch set; each missing node is fetched with the lock
+// released and the walk is retried.
+func (sm *SHAMap) read(walk func(deferFetch bool) error) error {
+	if sm.immutable.Load() {
+		return walk(false)
+	}
+	for {
+		sm.mu.RLock()
+		err := walk(true)
+		sm.mu.RUnlock()
+		if err = loadPending(err); err != errRetryWalk {
+			return err
+		}
 	}
+}
 
-	// Attach to parent for future access within this SHAMap instance.
-	// SetChildDirect doesn't update hash or dirty flag.
-	inner.SetChildDirect(branch, node)
+// write runs fn under the write lock. fn must walk with deferred fetches
+// and must not modify the map before its walk completes, so that it can be
+// retried from the start after each missing node is fetched.
+func (sm *SHAMap) write(fn func() error) error {
+	for {
+		sm.mu.Lock()
+		err := fn()
+		sm.mu.Unlock()
+		if err = loadPending(err); err != errRetryWalk {
+			return err
+		}
+	}
+}
 
-	return node, nil
+// errRetryWalk is returned by loadPending once a missing node is loaded.
+var errRetryWalk = errors.New("retry walk")
+
+// loadPending loads the node a walk stopped at and returns errRetryWalk,
+// or returns err unchanged if the walk did not stop at a missing node.
+func loadPending(err error) error {
+	var pending *pendingFetch
+	if !errors.As(err, &pending) {
+		return err
+	}
+	if _, err := pending.load(); err != nil {
+		return err
+	}
+	return errRetryWalk
+}
+
+// lockForRead takes the read lock unless the map is immutable, reporting
+// whether the caller must release it.
+func (sm *SHAMap) lockForRead() bool {
+	if sm.immutable.Load() {
+		return false
+	}
+	sm.mu.RLock()
+	return true
+}
+
+// setState changes the map state. The caller holds the write lock or owns
+// a map no other goroutine can see yet.
+func (sm *SHAMap) setState(state State) {
+	if state == StateImmutable {
+		// Readers of an immutable map take no locks, so hash it first
+		sm.updateHash()
+	}
+	sm.state = state
+	sm.immutable.Store(state == StateImmutable)
 }
 
 // Type returns the map type
@@ -223,7 +333,7 @@ func (sm *SHAMap) SetImmutable() error {
 		return errors.New("cannot set invalid map to immutable")
 	}
 
-	sm.state = StateImmutable
+	sm.setState(StateImmutable)
 	return nil
 }
 
@@ -243,13 +353,15 @@ func (sm *SHAMap) SetLedgerSeq(seq uint32) {
 
 // Hash returns the root hash of the SHAMap
 func (sm *SHAMap) Hash() ([32]byte, error) {
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
+	if sm.lockForRead() {
+		defer sm.mu.RUnlock()
+	}
 
 	if sm.state == StateInvalid {
 		return [32]byte{}, errors.New("cannot get hash of invalid map")
 	}
 
+	sm.updateHash()
 	return sm.root.Hash(), nil
 }
 
@@ -314,8 +426,9 @@ func (s *NodeStack) Len() int {
 	return len(s.entries)
 }
 
-// walkToKey traverses the tree toward a specific key
-func (sm *SHAMap) walkToKey(key [32]byte, stack *NodeStack) (Node, error) {
+// walkToKey traverses the tree toward a specific key. With deferFetch set
+// it stops with a *pendingFetch error at the first node not in memory.
+func (sm *SHAMap) walkToKey(key [32]byte, stack *NodeStack, deferFetch bool) (Node, error) {
 	if stack != nil && !stack.IsEmpty() {
 		stack.Clear()
 	}
@@ -338,7 +451,7 @@ func (sm *SHAMap) walkToKey(key [32]byte, stack *NodeStack) (Node, error) {
 			return nil, nil // Empty slot
 		}
 
-		child, err := sm.descend(inner, int(branch))
+		child, err := sm.descendOrDefer(inner, int(branch), deferFetch)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get child: %w", err)
 		}
@@ -362,8 +475,8 @@ func (sm *SHAMap) walkToKey(key [32]byte, stack *NodeStack) (Node, error) {
 }
 
 // findItem returns the item with the specified key, or nil if not found
-func (sm *SHAMap) findItem(key [32]byte) (*Item, error) {
-	node, err := sm.walkToKey(key, nil)
+func (sm *SHAMap) findItem(key [32]byte, deferFetch bool) (*Item, error) {
+	node, err := sm.walkToKey(key, nil, deferFetch)
 	if err != nil {
 		return nil, err
 	}
@@ -387,10 +500,11 @@ func (sm *SHAMap) findItem(key [32]byte) (*Item, error) {
 
 // Has checks if an item with the given key exists
 func (sm *SHAMap) Has(key [32]byte) (bool, error) {
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
-
-	item, err := sm.findItem(key)
+	var item *Item
+	err := sm.read(func(deferFetch bool) (err error) {
+		item, err = sm.findItem(key, deferFetch)
+		return err
+	})
 	if err != nil {
 		return false, err
 	}
@@ -399,10 +513,11 @@ func (sm *SHAMap) Has(key [32]byte) (bool, error) {
 
 // Get returns the item associated with the key
 func (sm *SHAMap) Get(key [32]byte) (*Item, bool, error) {
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
-
-	item, err := sm.findItem(key)
+	var item *Item
+	err := sm.read(func(deferFetch bool) (err error) {
+		item, err = sm.findItem(key, deferFetch)
+		return err
+	})
 	if err != nil {
 		return nil, false, err
 	}
@@ -427,14 +542,12 @@ func (sm *SHAMap) PutItemWithNodeType(item *Item, nodeType NodeType) error {
 		return ErrNilItem
 	}
 
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
-
-	if sm.state != StateModifying {
-		return ErrImmutable
-	}
-
-	return sm.putItemWithNodeTypeUnsafe(item, nodeType)
+	return sm.write(func() error {
+		if sm.state != StateModifying {
+			return ErrImmutable
+		}
+		return sm.putItemWithNodeTypeUnsafe(item, nodeType)
+	})
 }
 
 // putItemWithNodeTypeUnsafe adds an item with specific node type without locking
@@ -539,14 +652,12 @@ func (sm *SHAMap) PutItem(item *Item) error {
 		return ErrNilItem
 	}
 
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
-
-	if sm.state != StateModifying {
-		return ErrImmutable
-	}
-
-	return sm.putItemUnsafe(item)
+	return sm.write(func() error {
+		if sm.state != StateModifying {
+			return ErrImmutable
+		}
+		return sm.putItemUnsafe(item)
+	})
 }
 
 // putItemUnsafe adds an item without locking (caller must hold lock)
@@ -622,7 +733,8 @@ func (sm *SHAMap) putItemUnsafe(item *Item) error {
 	return sm.assignRoot(newRoot, key)
 }
 
-// walkToKeyForDirty walks toward a key but doesn't include the final leaf in the stack
+// walkToKeyForDirty walks toward a key but doesn't include the final leaf in the stack.
+// It stops with a *pendingFetch error at the first node not in memory.
 func (sm *SHAMap) walkToKeyForDirty(key [32]byte, stack *NodeStack) (Node, error) {
 	if stack != nil && !stack.IsEmpty() {
 		stack.Clear()
@@ -646,7 +758,7 @@ func (sm *SHAMap) walkToKeyForDirty(key [32]byte, stack *NodeStack) (Node, error
 			return nil, nil
 		}
 
-		child, err := sm.descend(inner, int(branch))
+		child, err := sm.descendOrDefer(inner, int(branch), true)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get child: %w", err)
 		}
@@ -686,6 +798,8 @@ func (sm *SHAMap) dirtyUp(stack *NodeStack, target [32]byte, child Node) (Node,
 		if !ok {
 			return nil, errors.New("expected InnerNode on stack")
 		}
+		// Copy shared nodes; the copy replaces them in this map's tree
+		inner = inner.mutable()
 
 		branch := SelectBranch(nodeID, target)
 		if err := inner.SetChild(int(branch), currentChild); err != nil {
@@ -721,30 +835,30 @@ func (sm *SHAMap) assignRoot(newRoot Node, key [32]byte) error {
 // It first locates and removes the corresponding leaf node, then reconstructs
 // the tree from the leaf's parent up to the root, consolidating as needed.
 func (sm *SHAMap) Delete(key [32]byte) error {
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
-
-	if sm.state != StateModifying {
-		return ErrImmutable
-	}
+	return sm.write(func() error {
+		if sm.state != StateModifying {
+			return ErrImmutable
+		}
 
-	stack, _, err := sm.findAndRemoveLeaf(key)
-	if err != nil {
-		return err
-	}
+		stack, _, err := sm.findAndRemoveLeaf(key)
+		if err != nil {
+			return err
+		}
 
-	newRoot, err := sm.consolidateAfterDelete(stack, key)
-	if err != nil {
-		return err
-	}
+		// Collapsing a branch may still load a sibling subtree under the lock
+		newRoot, err := sm.consolidateAfterDelete(stack, key)
+		if err != nil {
+			return err
+		}
 
-	if rootInner, ok := newRoot.(*InnerNode); ok {
-		sm.root = rootInner
-	} else {
-		return fmt.Errorf("expected root to be InnerNode, got %T", newRoot)
-	}
+		if rootInner, ok := newRoot.(*InnerNode); ok {
+			sm.root = rootInner
+		} else {
+			return fmt.Errorf("expected root to be InnerNode, got %T", newRoot)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // findAndRemoveLeaf walks the SHAMap to locate the leaf node matching the key.
@@ -752,7 +866,7 @@ func (sm *SHAMap) Delete(key [32]byte) error {
 // the remaining stack for further processing.
 func (sm *SHAMap) findAndRemoveLeaf(key [32]byte) (*NodeStack, LeafNode, error) {
 	stack := NewNodeStack()
-	_, err := sm.walkToKey(key, stack)
+	_, err := sm.walkToKey(key, stack, true)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to walk to key: %w", err)
 	}
@@ -922,27 +1036,28 @@ func (sm *SHAMap) Snapshot(mutable bool) (*SHAMap, error) {
 		return nil, fmt.Errorf("failed to clone tree: %w", err)
 	}
 
-	return &SHAMap{
+	snapshot := &SHAMap{
 		root:      newRoot,
 		mapType:   sm.mapType,
-		state:     newState,
 		ledgerSeq: sm.ledgerSeq,
 		full:      sm.full,
-	}, nil
+	}
+	snapshot.setState(newState)
+	return snapshot, nil
 }
 
 // snapshotBacked creates a snapshot of a backed map by flushing dirty nodes
 // and creating a new SHAMap from the root hash. O(dirty nodes) instead of O(tree).
 func (sm *SHAMap) snapshotBacked(mutable bool) (*SHAMap, error) {
-	// FlushDirty takes its own write lock
-	batch, err := sm.FlushDirty(false)
-	if err != nil {
-		return nil, fmt.Errorf("failed to flush dirty nodes: %w", err)
-	}
-
-	if len(batch.Entries) > 0 {
-		if err := sm.family.StoreBatch(batch.Entries); err != nil {
-			return nil, fmt.Errorf("failed to store flushed nodes: %w", err)
+	// Flushing takes its own write lock
+	if ff, ok := sm.family.(FlushingFamily); ok {
+		if _, err := ff.FlushMap(sm, false); err != nil {
+			return nil, fmt.Errorf("failed to flush dirty nodes: %w", err)
+		}
+	} else {
+		_, err := sm.FlushDirtyFunc(false, DefaultFlushBatchSize, sm.family.StoreBatch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to flush dirty nodes: %w", err)
 		}
 	}
 
@@ -963,7 +1078,7 @@ func (sm *SHAMap) snapshotBacked(mutable bool) (*SHAMap, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create backed snapshot: %w", err)
 	}
-	newMap.state = newState
+	newMap.setState(newState)
 	newMap.ledgerSeq = sm.ledgerSeq
 
 	return newMap, nil
@@ -972,8 +1087,9 @@ func (sm *SHAMap) snapshotBacked(mutable bool) (*SHAMap, error) {
 // ForEach calls fn for every item in the tree
 // If fn returns false, iteration stops early
 func (sm *SHAMap) ForEach(fn func(*Item) bool) error {
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
+	if sm.lockForRead() {
+		defer sm.mu.RUnlock()
+	}
 
 	return sm.forEachUnsafe(sm.root, fn)
 }
@@ -1118,6 +1234,10 @@ func (sm *SHAMap) IsBacked() bool {
 	return sm.backed
 }
 
+// DefaultFlushBatchSize is the batch size used when flushing a backed
+// map to its Family.
+const DefaultFlushBatchSize = 4096
+
 // FlushDirty performs a post-order traversal of the tree, collecting all dirty nodes.
 // Each dirty node is serialized and added to the returned NodeBatch.
 // After serialization, nodes are marked clean (dirty=false).
@@ -1125,42 +1245,69 @@ func (sm *SHAMap) IsBacked() bool {
 // (retaining only hashes), allowing GC to reclaim memory. Children will be
 // lazily reloaded from NodeStore on next access.
 func (sm *SHAMap) FlushDirty(releaseChildren bool) (*NodeBatch, error) {
+	batch := &NodeBatch{}
+	_, err := sm.FlushDirtyFunc(releaseChildren, 0, func(entries []FlushEntry) error {
+		batch.Entries = entries
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// FlushDirtyFunc flushes dirty nodes like FlushDirty, but hands them to
+// emit in batches of up to batchSize as the traversal produces them, so a
+// writer can store one batch while the next is serialized. A batchSize of
+// zero emits everything in one batch. Batches arrive in the same
+// post-order as FlushDirty's entries and emit may keep them. Modified
+// subtrees are hashed concurrently before the traversal starts.
+// Returns the number of nodes flushed.
+func (sm *SHAMap) FlushDirtyFunc(releaseChildren bool, batchSize int, emit func([]FlushEntry) error) (int, error) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
 	if sm.root == nil {
-		return &NodeBatch{}, nil
+		return 0, nil
 	}
+	sm.updateHash()
 
-	batch := &NodeBatch{}
-
-	if err := sm.flushNode(sm.root, releaseChildren, batch); err != nil {
-		return nil, fmt.Errorf("failed to flush: %w", err)
+	f := &flusher{mapType: sm.mapType, releaseChildren: releaseChildren, batchSize: batchSize, emit: emit}
+	if err := f.flushNode(sm.root); err != nil {
+		return f.count, fmt.Errorf("failed to flush: %w", err)
+	}
+	if err := f.send(); err != nil {
+		return f.count, fmt.Errorf("failed to flush: %w", err)
 	}
+	return f.count, nil
+}
 
-	return batch, nil
+// flusher carries the state of one FlushDirtyFunc traversal.
+type flusher struct {
+	mapType         Type
+	releaseChildren bool
+	batchSize       int
+	emit            func([]FlushEntry) error
+	pending         []FlushEntry
+	count           int
 }
 
-// flushNode recursively flushes a dirty node and its dirty children (post-order).
-func (sm *SHAMap) flushNode(node Node, releaseChildren bool, batch *NodeBatch) error {
+// flushNode recursively flushes a dirty node and its dirty children
+// (post-order). The caller holds sm.mu for writing and the tree is already
+// hashed, so no node locks are taken.
+func (f *flusher) flushNode(node Node) error {
 	if node == nil || !node.IsDirty() {
 		return nil
 	}
 
 	// For inner nodes: flush children first (post-order)
-	if inner, ok := node.(*InnerNode); ok {
-		inner.mu.Lock()
+	inner, isInner := node.(*InnerNode)
+	if isInner {
 		for i := 0; i < BranchFactor; i++ {
-			child := inner.children[i]
-			if child != nil && child.IsDirty() {
-				// Flush child first (recursive)
-				if err := sm.flushNode(child, releaseChildren, batch); err != nil {
-					inner.mu.Unlock()
-					return err
-				}
+			if err := f.flushNode(inner.child(i)); err != nil {
+				return err
 			}
 		}
-		inner.mu.Unlock()
 	}
 
 	// Serialize this node
@@ -1169,29 +1316,69 @@ func (sm *SHAMap) flushNode(node Node, releaseChildren bool, batch *NodeBatch) e
 		return fmt.Errorf("failed to serialize node: %w", err)
 	}
 
-	hash := node.Hash()
-	batch.Entries = append(batch.Entries, FlushEntry{
-		Hash: hash,
-		Data: data,
+	f.pending = append(f.pending, FlushEntry{
+		Hash:    node.Hash(),
+		Data:    data,
+		MapType: f.mapType,
 	})
+	f.count++
 
 	// Mark clean
 	node.SetDirty(false)
 
 	// Release children pointers for inner nodes (retain hashes for lazy reload)
-	if releaseChildren {
-		if inner, ok := node.(*InnerNode); ok {
-			inner.mu.Lock()
-			for i := 0; i < BranchFactor; i++ {
-				inner.children[i] = nil
-			}
-			inner.mu.Unlock()
+	if f.releaseChildren && isInner {
+		for i := 0; i < BranchFactor; i++ {
+			inner.storeChild(i, nil)
 		}
 	}
 
+	if f.batchSize > 0 && len(f.pending) >= f.batchSize {
+		return f.send()
+	}
 	return nil
 }
 
+// send hands the pending entries to emit.
+func (f *flusher) send() error {
+	if len(f.pending) == 0 {
+		return nil
+	}
+	batch := f.pending
+	f.pending = nil
+	return f.emit(batch)
+}
+
+// updateHash brings every hash in the tree up to date. The root's modified
+// subtrees are independent, so they are hashed concurrently; the result
+// is the same as hashing them one by one. The caller holds sm.mu.
+// Reference: rippled SHAMap::flushDirty, which hashes before writing
+func (sm *SHAMap) updateHash() {
+	root := sm.root
+	if root == nil || !root.stale.Load() {
+		return
+	}
+
+	var stale []*InnerNode
+	for i := 0; i < BranchFactor; i++ {
+		if child, ok := root.child(i).(*InnerNode); ok && child.stale.Load() {
+			stale = append(stale, child)
+		}
+	}
+	if len(stale) > 1 {
+		var wg sync.WaitGroup
+		for _, child := range stale {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				child.Hash()
+			}()
+		}
+		wg.Wait()
+	}
+	root.Hash()
+}
+
 // cloneNodeTree deep clones a node and all its children
 func (sm *SHAMap) cloneNodeTree(node Node) (*InnerNode, error) {
 	if node == nil {
@@ -4,7 +4,11 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+
+	"github.com/LeJamon/goXRPLd/crypto/common"
 )
 
 // Helper function to create a byte slice filled with a repeating byte
@@ -611,6 +615,95 @@ func BenchmarkSnapshot(b *testing.B) {
 	}
 }
 
+// BenchmarkLedgerDataScan pages through an immutable backed map the way
+// ledger_data does: 256 items per call from a marker, stepping with
+// UpperBound as Ledger.Succ does. Each pass over the markers opens
+// the map from its root hash so pages load their nodes from the Family;
+// the parallel variant shows readers are not serialised by those loads.
+func BenchmarkLedgerDataScan(b *testing.B) {
+	const items, pageSize = 20000, 256
+
+	family := NewMemoryFamily()
+	sMap, err := NewBacked(TypeState, family)
+	if err != nil {
+		b.Fatalf("Failed to create SHAMap: %v", err)
+	}
+	for i := 0; i < items; i++ {
+		key := common.Sha512Half([]byte(fmt.Sprintf("item %d", i)))
+		if err := sMap.Put(key, intToBytes(i)); err != nil {
+			b.Fatalf("Failed to put item %d: %v", i, err)
+		}
+	}
+	batch, err := sMap.FlushDirty(true)
+	if err != nil {
+		b.Fatalf("FlushDirty: %v", err)
+	}
+	if err := family.StoreBatch(batch.Entries); err != nil {
+		b.Fatalf("StoreBatch: %v", err)
+	}
+	rootHash, _ := sMap.Hash()
+	var markers [][32]byte
+	n := 0
+	for it := sMap.Begin(); it.Next(); n++ {
+		if n%pageSize == 0 {
+			markers = append(markers, it.Item().Key())
+		}
+	}
+
+	open := func(b *testing.B) *SHAMap {
+		m, err := NewFromRootHash(TypeState, rootHash, family)
+		if err != nil {
+			b.Fatalf("NewFromRootHash: %v", err)
+		}
+		if err := m.SetImmutable(); err != nil {
+			b.Fatalf("SetImmutable: %v", err)
+		}
+		return m
+	}
+	page := func(b *testing.B, m *SHAMap, marker [32]byte) {
+		for n := 0; n < pageSize; n++ {
+			it := m.UpperBound(marker)
+			if it.Err() != nil {
+				b.Fatalf("scan: %v", it.Err())
+			}
+			if !it.Valid() {
+				return
+			}
+			marker = it.Item().Key()
+		}
+	}
+
+	b.Run("Serial", func(b *testing.B) {
+		m := open(b)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if i%len(markers) == 0 {
+				m = open(b)
+			}
+			page(b, m, markers[i%len(markers)])
+		}
+	})
+
+	b.Run("Parallel", func(b *testing.B) {
+		var next atomic.Int64
+		var mu sync.Mutex
+		m := open(b)
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				i := int(next.Add(1) - 1)
+				mu.Lock()
+				if i%len(markers) == 0 {
+					m = open(b)
+				}
+				cur := m
+				mu.Unlock()
+				page(b, cur, markers[i%len(markers)])
+			}
+		})
+	})
+}
+
 // Helper function for debugging - simplified tree dump
 func dumpTree(node Node, prefix string, isTail bool) {
 	switch n := node.(type) {
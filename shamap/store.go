@@ -9,8 +9,9 @@ import (
 
 // FlushEntry holds a serialized node ready to be written to NodeStore.
 type FlushEntry struct {
-	Hash [32]byte // SHAMap node hash (used as key in NodeStore)
-	Data []byte   // SerializeWithPrefix() output
+	Hash    [32]byte // SHAMap node hash (used as key in NodeStore)
+	Data    []byte   // SerializeWithPrefix() output
+	MapType Type     // Type of the SHAMap the node was flushed from
 }
 
 // NodeBatch holds a batch of serialized nodes from FlushDirty().
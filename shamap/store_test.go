@@ -2,9 +2,15 @@ package shamap
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"math/rand"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/LeJamon/goXRPLd/crypto/common"
+	"github.com/LeJamon/goXRPLd/storage/nodestore"
 )
 
 // memoryFamily is a test implementation of Family using an in-memory map.
@@ -1167,3 +1173,405 @@ func TestBacked_Iterator(t *testing.T) {
 		t.Errorf("Iterator found %d items, expected %d", count, len(keys))
 	}
 }
+
+// TestBacked_SharedNodeCache verifies that maps loaded through a
+// NodeStoreFamily share decoded nodes, and that modifying one map copies
+// the shared nodes instead of changing them under the other.
+func TestBacked_SharedNodeCache(t *testing.T) {
+	family, err := NewMemoryNodeStoreFamily()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer family.Close()
+	cache := NewTreeNodeCache(1000)
+	family.SetTreeNodeCache(cache)
+
+	sMap, err := New(TypeState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := make([][32]byte, 50)
+	for i := range keys {
+		keys[i] = hexToHash(fmt.Sprintf("%02x%062x", i*5, i+1))
+		if err := sMap.Put(keys[i], intToBytes(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rootHash, _ := sMap.Hash()
+	batch, err := sMap.FlushDirty(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := family.StoreBatch(batch.Entries); err != nil {
+		t.Fatal(err)
+	}
+
+	map1, err := NewFromRootHash(TypeState, rootHash, family)
+	if err != nil {
+		t.Fatal(err)
+	}
+	map2, err := NewFromRootHash(TypeState, rootHash, family)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if map1.root != map2.root {
+		t.Fatal("Maps from the same root hash should share the root node")
+	}
+	for _, key := range keys {
+		if _, found, err := map1.Get(key); err != nil || !found {
+			t.Fatalf("map1.Get: found=%v err=%v", found, err)
+		}
+		if _, found, err := map2.Get(key); err != nil || !found {
+			t.Fatalf("map2.Get: found=%v err=%v", found, err)
+		}
+	}
+	stats := family.TreeNodeCacheStats()
+	if stats.Hits == 0 {
+		t.Errorf("Second map should be served from the cache: %+v", stats)
+	}
+
+	shared := map2.root
+	if err := map1.Put(keys[0], intToBytes(99)); err != nil {
+		t.Fatal(err)
+	}
+	if err := map1.Delete(keys[1]); err != nil {
+		t.Fatal(err)
+	}
+	if map1.root == shared {
+		t.Error("Modified map should hold its own copy of the root")
+	}
+	if h, _ := map2.Hash(); h != rootHash || shared.Hash() != rootHash {
+		t.Error("Shared nodes must not change when another map is modified")
+	}
+	item, found, err := map2.Get(keys[0])
+	if err != nil || !found || !bytes.Equal(item.Data(), intToBytes(0)) {
+		t.Error("map2 should still see the original value")
+	}
+	if found, _ := map2.Has(keys[1]); !found {
+		t.Error("map2 should still have the deleted key")
+	}
+	if err := map1.Invariants(); err != nil {
+		t.Errorf("map1 invariants: %v", err)
+	}
+}
+
+// gatedFamily blocks fetches of one node until release is closed.
+type gatedFamily struct {
+	*memoryFamily
+	gate    [32]byte
+	waiting chan struct{}
+	release chan struct{}
+}
+
+func (f *gatedFamily) Fetch(hash [32]byte) ([]byte, error) {
+	if hash == f.gate {
+		close(f.waiting)
+		<-f.release
+	}
+	return f.memoryFamily.Fetch(hash)
+}
+
+// TestBacked_FetchReleasesLock verifies that a mutable map does not hold
+// its lock while a reader waits on the Family.
+func TestBacked_FetchReleasesLock(t *testing.T) {
+	family := newMemoryFamily()
+	sMap, err := NewBacked(TypeState, family)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyA := hexToHash("10" + fmt.Sprintf("%062x", 1))
+	keyB := hexToHash("20" + fmt.Sprintf("%062x", 2))
+	for _, key := range [][32]byte{keyA, keyB} {
+		if err := sMap.Put(key, intToBytes(1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := flushToFamily(sMap, family); err != nil {
+		t.Fatal(err)
+	}
+	rootHash, _ := sMap.Hash()
+
+	gated := &gatedFamily{
+		memoryFamily: family,
+		gate:         sMap.root.ChildHashUnsafe(2),
+		waiting:      make(chan struct{}),
+		release:      make(chan struct{}),
+	}
+	backed, err := NewFromRootHash(TypeState, rootHash, gated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, found, err := backed.Get(keyB)
+		if err == nil && !found {
+			err = fmt.Errorf("keyB not found")
+		}
+		done <- err
+	}()
+	<-gated.waiting
+
+	// The reader is blocked in Fetch; writers must still get through
+	wrote := make(chan error, 1)
+	go func() { wrote <- backed.Put(keyA, intToBytes(2)) }()
+	select {
+	case err := <-wrote:
+		if err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Put blocked behind a pending fetch")
+	}
+
+	close(gated.release)
+	if err := <-done; err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if item, _, _ := backed.Get(keyA); item == nil || !bytes.Equal(item.Data(), intToBytes(2)) {
+		t.Error("Put lost while a fetch was pending")
+	}
+	if err := backed.Invariants(); err != nil {
+		t.Errorf("Invariants: %v", err)
+	}
+}
+
+// TestBacked_ConcurrentImmutableReads runs lock-free readers over an
+// immutable backed map while its nodes are still being loaded.
+func TestBacked_ConcurrentImmutableReads(t *testing.T) {
+	family := newMemoryFamily()
+	sMap, err := NewBacked(TypeState, family)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := make([][32]byte, 500)
+	for i := range keys {
+		keys[i] = hexToHash(fmt.Sprintf("%04x%060x", i*131, i+1))
+		if err := sMap.Put(keys[i], intToBytes(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := flushToFamily(sMap, family); err != nil {
+		t.Fatal(err)
+	}
+	rootHash, _ := sMap.Hash()
+	var wanted [][32]byte
+	for i := 0; i < BranchFactor; i++ {
+		if h := sMap.root.ChildHashUnsafe(i); !isZeroHash(h) {
+			wanted = append(wanted, h)
+		}
+	}
+
+	backed, err := NewFromRootHash(TypeState, rootHash, family)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backed.SetImmutable(); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 24)
+	for g := 0; g < 8; g++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for i, key := range keys {
+				item, found, err := backed.Get(key)
+				if err != nil || !found || !bytes.Equal(item.Data(), intToBytes(i)) {
+					errs <- fmt.Errorf("Get %d: found=%v err=%v", i, found, err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			count := 0
+			it := backed.Begin()
+			for it.Next() {
+				count++
+			}
+			if it.Err() != nil || count != len(keys) {
+				errs <- fmt.Errorf("iterated %d items, err=%v", count, it.Err())
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			nodes, err := backed.BulkGetNodes(wanted)
+			if err != nil || len(nodes) != len(wanted) {
+				errs <- fmt.Errorf("BulkGetNodes returned %d of %d nodes, err=%v", len(nodes), len(wanted), err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if err := backed.Invariants(); err != nil {
+		t.Errorf("Invariants: %v", err)
+	}
+}
+
+// TestFlushDirtyFunc_Deterministic verifies that maps with the same items
+// hash and flush identically whatever the insertion order, and that the
+// batched flush yields exactly FlushDirty's entries.
+func TestFlushDirtyFunc_Deterministic(t *testing.T) {
+	keys := make([][32]byte, 1000)
+	for i := range keys {
+		keys[i] = common.Sha512Half([]byte(fmt.Sprintf("key %d", i)))
+	}
+
+	build := func(order []int) *SHAMap {
+		sMap, err := New(TypeState)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, i := range order {
+			if err := sMap.Put(keys[i], intToBytes(i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return sMap
+	}
+	forward := make([]int, len(keys))
+	for i := range forward {
+		forward[i] = i
+	}
+	shuffled := rand.New(rand.NewSource(1)).Perm(len(keys))
+
+	serial := build(forward)
+	for i := range keys {
+		// Hash after every change, one inner node at a time
+		if i%2 == 0 {
+			if err := serial.Delete(keys[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		serial.Hash()
+	}
+	want, err := serial.FlushDirty(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parallel := build(shuffled)
+	for i := range keys {
+		if i%2 == 0 {
+			if err := parallel.Delete(keys[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	var got []FlushEntry
+	var batches int
+	count, err := parallel.FlushDirtyFunc(true, 64, func(entries []FlushEntry) error {
+		if len(entries) > 64 {
+			t.Errorf("batch of %d entries exceeds the batch size", len(entries))
+		}
+		batches++
+		got = append(got, entries...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != len(want.Entries) || len(got) != count || batches < count/64 {
+		t.Fatalf("flushed %d entries in %d batches, want %d", len(got), batches, len(want.Entries))
+	}
+	for i := range got {
+		if got[i].Hash != want.Entries[i].Hash || !bytes.Equal(got[i].Data, want.Entries[i].Data) {
+			t.Fatalf("entry %d differs", i)
+		}
+	}
+	wantHash, _ := serial.Hash()
+	if gotHash, _ := parallel.Hash(); gotHash != wantHash {
+		t.Fatal("root hashes differ")
+	}
+	if err := serial.Invariants(); err != nil {
+		t.Errorf("Invariants: %v", err)
+	}
+}
+
+// TestNodeStoreFamily_FlushMap verifies that a map flushed through the
+// family's BatchWriter can be reopened from its root hash.
+func TestNodeStoreFamily_FlushMap(t *testing.T) {
+	family, err := NewMemoryNodeStoreFamily()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer family.Close()
+
+	sMap, err := NewBacked(TypeState, family)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := make([][32]byte, 10000)
+	for i := range keys {
+		keys[i] = common.Sha512Half([]byte(fmt.Sprintf("key %d", i)))
+		if err := sMap.Put(keys[i], intToBytes(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	count, err := family.FlushMap(sMap, true)
+	if err != nil {
+		t.Fatalf("FlushMap: %v", err)
+	}
+	if count <= len(keys) {
+		t.Fatalf("flushed %d nodes, want more than %d", count, len(keys))
+	}
+	if count, err := family.FlushMap(sMap, false); err != nil || count != 0 {
+		t.Fatalf("second FlushMap flushed %d nodes, err=%v", count, err)
+	}
+
+	rootHash, _ := sMap.Hash()
+	reopened, err := NewFromRootHash(TypeState, rootHash, family)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, key := range keys {
+		item, found, err := reopened.Get(key)
+		if err != nil || !found || !bytes.Equal(item.Data(), intToBytes(i)) {
+			t.Fatalf("Get %d: found=%v err=%v", i, found, err)
+		}
+	}
+}
+
+// TestNodeStoreFamily_FlushNodeType verifies that flushed nodes are
+// stored with the node type of the map they belong to.
+func TestNodeStoreFamily_FlushNodeType(t *testing.T) {
+	family, err := NewMemoryNodeStoreFamily()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer family.Close()
+
+	for _, tc := range []struct {
+		mapType Type
+		want    nodestore.NodeType
+	}{
+		{TypeState, nodestore.NodeAccount},
+		{TypeTransaction, nodestore.NodeTransaction},
+	} {
+		m, err := NewBacked(tc.mapType, family)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key := common.Sha512Half([]byte(tc.mapType.String()))
+		if err := m.Put(key, intToBytes(1)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := family.FlushMap(m, false); err != nil {
+			t.Fatalf("FlushMap: %v", err)
+		}
+		rootHash, _ := m.Hash()
+		node, err := family.db.Fetch(context.Background(), nodestore.Hash256(rootHash))
+		if err != nil || node == nil {
+			t.Fatalf("%s root: node=%v err=%v", tc.mapType, node, err)
+		}
+		if node.Type != tc.want {
+			t.Errorf("%s root stored as %v, want %v", tc.mapType, node.Type, tc.want)
+		}
+	}
+}
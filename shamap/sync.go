@@ -367,7 +367,7 @@ func (sm *SHAMap) AddRootNode(hash [32]byte, data []byte) error {
 	}
 
 	sm.root = innerNode
-	sm.state = StateSyncing
+	sm.setState(StateSyncing)
 
 	return nil
 }
@@ -382,7 +382,7 @@ func (sm *SHAMap) StartSync() error {
 		return errors.New("cannot start sync on invalid map")
 	}
 
-	sm.state = StateSyncing
+	sm.setState(StateSyncing)
 	sm.full = false
 
 	return nil
@@ -404,7 +404,7 @@ func (sm *SHAMap) FinishSync() error {
 		return fmt.Errorf("sync incomplete: still have %d missing nodes", len(missingNodes))
 	}
 
-	sm.state = StateModifying
+	sm.setState(StateModifying)
 	sm.full = true
 
 	return nil
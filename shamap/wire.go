@@ -3,6 +3,8 @@ package shamap
 import (
 	"errors"
 	"fmt"
+
+	"golang.org/x/sync/errgroup"
 )
 
 // Wire protocol errors
@@ -251,18 +253,23 @@ func (sm *SHAMap) GetChildHashes(nodeHash [32]byte) ([][32]byte, error) {
 	return hashes, nil
 }
 
+// bulkFetchWorkers bounds the concurrent Family fetches BulkGetNodes issues
+// while prefetching one level of the tree.
+const bulkFetchWorkers = 8
+
 // BulkGetNodes retrieves multiple nodes by their hashes in a single call.
 // This is more efficient than calling GetNodeByHash multiple times.
 //
+// The tree is searched breadth first. In a backed map the children of each
+// level that are not yet in memory are fetched from the Family
+// concurrently, with the map's lock released for mutable maps.
+//
 // Parameters:
 //   - hashes: the hashes of nodes to retrieve
 //
 // Returns a map from hash to NodeData for all found nodes.
 // Nodes that are not found are omitted from the result.
 func (sm *SHAMap) BulkGetNodes(hashes [][32]byte) (map[[32]byte]NodeData, error) {
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
-
 	result := make(map[[32]byte]NodeData, len(hashes))
 
 	// Build a set of requested hashes for O(1) lookup
@@ -271,62 +278,99 @@ func (sm *SHAMap) BulkGetNodes(hashes [][32]byte) (map[[32]byte]NodeData, error)
 		requested[h] = struct{}{}
 	}
 
+	locked := sm.lockForRead()
+	defer func() {
+		if locked {
+			sm.mu.RUnlock()
+		}
+	}()
+
 	if sm.root == nil {
 		return result, nil
 	}
 
-	// BFS search collecting matching nodes
-	type workItem struct {
-		node Node
-	}
-
-	queue := []workItem{{node: sm.root}}
-
-	for len(queue) > 0 && len(result) < len(hashes) {
-		item := queue[0]
-		queue = queue[1:]
-
-		if item.node == nil {
-			continue
-		}
-
-		nodeHash := item.node.Hash()
-
-		if _, wanted := requested[nodeHash]; wanted {
-			data, err := item.node.SerializeForWire()
-			if err == nil {
-				result[nodeHash] = NodeData{
-					Hash: nodeHash,
-					Data: data,
+	level := []Node{sm.root}
+	for len(level) > 0 && len(result) < len(requested) {
+		var next []Node
+		var missing []*pendingFetch
+
+		for _, node := range level {
+			nodeHash := node.Hash()
+			if _, wanted := requested[nodeHash]; wanted {
+				data, err := node.SerializeForWire()
+				if err == nil {
+					result[nodeHash] = NodeData{
+						Hash: nodeHash,
+						Data: data,
+					}
 				}
 			}
-		}
 
-		// Continue traversal if not a leaf
-		if !item.node.IsLeaf() {
-			inner, ok := item.node.(*InnerNode)
+			inner, ok := node.(*InnerNode)
 			if !ok {
 				continue
 			}
-
 			for branch := 0; branch < BranchFactor; branch++ {
-				if inner.IsEmptyBranch(branch) {
-					continue
-				}
-
-				child, err := inner.Child(branch)
-				if err != nil || child == nil {
-					continue
+				child, err := sm.descendOrDefer(inner, branch, true)
+				var pending *pendingFetch
+				switch {
+				case errors.As(err, &pending):
+					missing = append(missing, pending)
+				case err != nil:
+					return nil, err
+				case child != nil:
+					next = append(next, child)
 				}
+			}
+		}
 
-				queue = append(queue, workItem{node: child})
+		if len(missing) > 0 {
+			// Nodes are content addressed, so a subtree a writer replaces
+			// while the lock is released still yields correct data.
+			if locked {
+				sm.mu.RUnlock()
+			}
+			loaded, err := prefetch(missing)
+			if locked {
+				sm.mu.RLock()
 			}
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, loaded...)
 		}
+		level = next
 	}
 
 	return result, nil
 }
 
+// prefetch loads missing children concurrently and returns the ones that
+// were attached to their parents.
+func prefetch(missing []*pendingFetch) ([]Node, error) {
+	nodes := make([]Node, len(missing))
+	var g errgroup.Group
+	g.SetLimit(bulkFetchWorkers)
+	for i, pending := range missing {
+		g.Go(func() error {
+			node, err := pending.load()
+			nodes[i] = node
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	loaded := nodes[:0]
+	for _, node := range nodes {
+		if node != nil {
+			loaded = append(loaded, node)
+		}
+	}
+	return loaded, nil
+}
+
 // WireMessage represents a collection of nodes for wire transmission.
 // This is used for batch node transfer during synchronization.
 type WireMessage struct {
@@ -342,16 +386,16 @@ type WireMessage struct {
 //   - nodeHashes: specific nodes to include, or nil for all nodes
 //   - maxNodes: maximum number of nodes to include (0 = no limit)
 func (sm *SHAMap) CreateWireMessage(nodeHashes [][32]byte, maxNodes int) (*WireMessage, error) {
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
-
 	msg := &WireMessage{
 		Nodes:   make([]NodeData, 0),
-		MapType: sm.mapType,
-		Seq:     sm.ledgerSeq,
+		MapType: sm.Type(),
 	}
 
 	if nodeHashes != nil {
+		sm.mu.RLock()
+		msg.Seq = sm.ledgerSeq
+		sm.mu.RUnlock()
+
 		nodes, err := sm.BulkGetNodes(nodeHashes)
 		if err != nil {
 			return nil, err
@@ -364,6 +408,10 @@ func (sm *SHAMap) CreateWireMessage(nodeHashes [][32]byte, maxNodes int) (*WireM
 			}
 		}
 	} else {
+		sm.mu.RLock()
+		defer sm.mu.RUnlock()
+
+		msg.Seq = sm.ledgerSeq
 		if sm.root == nil {
 			return msg, nil
 		}
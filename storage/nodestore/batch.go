@@ -1,7 +1,11 @@
 package nodestore
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,7 +21,6 @@ const (
 )
 
 // BatchWriteConfig holds configuration for the batch writer.
-// Kept for backwards compatibility; batch writing is now handled internally.
 type BatchWriteConfig struct {
 	// PreallocationSize is the initial capacity of the write buffer.
 	PreallocationSize int
@@ -59,19 +62,68 @@ func (c *BatchWriteConfig) Validate() error {
 	return nil
 }
 
-// BatchWriter is kept for backwards compatibility.
-// It is a stub that wraps a Backend for synchronous writes.
+// ErrBatchWriterClosed is returned when adding to a closed BatchWriter.
+var ErrBatchWriterClosed = errors.New("batch writer is closed")
+
+// BatchWriter buffers nodes added with Add and stores them in batches of
+// LimitSize. A full batch is handed to a background goroutine, so the
+// caller keeps producing nodes while the previous batch is written; at
+// most one further batch waits behind it. Flush writes what is buffered
+// and waits for every batch. Write and WriteNode bypass the buffer and
+// store a single node synchronously.
+// Reference: rippled BatchWriter
 type BatchWriter struct {
-	backend Backend
+	store  func([]*Node) error
+	syncFn func() error
+	config *BatchWriteConfig
+
+	mu      sync.Mutex
+	pending []*Node
+	queue   chan []*Node
+	closed  bool
+	writes  sync.WaitGroup
+
+	errMu sync.Mutex
+	err   error // first background write error since the last Flush
+
+	totalWrites   atomic.Int64
+	batchedWrites atomic.Int64
+	flushes       atomic.Int64
+	failures      atomic.Int64
+	bytesWritten  atomic.Int64
+	queued        atomic.Int64
 }
 
-// NewBatchWriter creates a new BatchWriter with the given backend.
-// The config is validated but ignored (batch writing is now synchronous).
+// NewBatchWriter creates a BatchWriter that stores batches in a backend.
 func NewBatchWriter(backend Backend, config *BatchWriteConfig) (*BatchWriter, error) {
 	if backend == nil {
 		return nil, fmt.Errorf("backend must not be nil")
 	}
+	return newBatchWriter(func(nodes []*Node) error {
+		if status := backend.StoreBatch(nodes); status != OK {
+			return fmt.Errorf("batch store failed: %s", status.String())
+		}
+		return nil
+	}, func() error {
+		if status := backend.Sync(); status != OK {
+			return fmt.Errorf("sync failed: %s", status.String())
+		}
+		return nil
+	}, config)
+}
+
+// NewDatabaseBatchWriter creates a BatchWriter that stores batches through
+// a Database, so they also pass through its caches.
+func NewDatabaseBatchWriter(db Database, config *BatchWriteConfig) (*BatchWriter, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database must not be nil")
+	}
+	return newBatchWriter(func(nodes []*Node) error {
+		return db.StoreBatch(context.Background(), nodes)
+	}, db.Sync, config)
+}
 
+func newBatchWriter(store func([]*Node) error, syncFn func() error, config *BatchWriteConfig) (*BatchWriter, error) {
 	if config == nil {
 		config = DefaultBatchWriteConfig()
 	}
@@ -80,10 +132,15 @@ func NewBatchWriter(backend Backend, config *BatchWriteConfig) (*BatchWriter, er
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
-	return &BatchWriter{backend: backend}, nil
+	return &BatchWriter{
+		store:   store,
+		syncFn:  syncFn,
+		config:  config,
+		pending: make([]*Node, 0, config.PreallocationSize),
+	}, nil
 }
 
-// Write submits a write operation synchronously.
+// Write stores a single node synchronously.
 func (bw *BatchWriter) Write(hash Hash256, data []byte) <-chan error {
 	result := make(chan error, 1)
 
@@ -94,12 +151,8 @@ func (bw *BatchWriter) Write(hash Hash256, data []byte) <-chan error {
 	}
 	copy(node.Data, data)
 
-	status := bw.backend.Store(node)
-	if status != OK {
-		result <- fmt.Errorf("batch store failed: %s", status.String())
-	} else {
-		result <- nil
-	}
+	bw.totalWrites.Add(1)
+	result <- bw.writeBatch([]*Node{node})
 	close(result)
 	return result
 }
@@ -125,24 +178,135 @@ func (bw *BatchWriter) WriteNodeSync(node *Node) error {
 	return <-bw.WriteNode(node)
 }
 
-// Flush is a no-op since writes are synchronous.
-func (bw *BatchWriter) Flush() error {
+// Add buffers a node for a batched write. It blocks only while two full
+// batches are already waiting to be written. Errors from earlier
+// background writes are returned here and by Flush.
+func (bw *BatchWriter) Add(node *Node) error {
+	if node == nil {
+		return fmt.Errorf("node cannot be nil")
+	}
+	if err := bw.firstError(); err != nil {
+		return err
+	}
+
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	if bw.closed {
+		return ErrBatchWriterClosed
+	}
+	bw.totalWrites.Add(1)
+	bw.pending = append(bw.pending, node)
+	if len(bw.pending) >= bw.config.LimitSize {
+		bw.enqueueLocked()
+	}
 	return nil
 }
 
-// Close is a no-op.
-func (bw *BatchWriter) Close() error {
+// enqueueLocked hands the pending nodes to the writer goroutine, starting
+// it on first use. The caller holds bw.mu.
+func (bw *BatchWriter) enqueueLocked() {
+	if len(bw.pending) == 0 {
+		return
+	}
+	if bw.queue == nil {
+		bw.queue = make(chan []*Node, 1)
+		go bw.run(bw.queue)
+	}
+	batch := bw.pending
+	bw.pending = make([]*Node, 0, bw.config.PreallocationSize)
+	bw.writes.Add(1)
+	bw.queued.Add(int64(len(batch)))
+	bw.queue <- batch
+}
+
+// run writes queued batches until the queue is closed.
+func (bw *BatchWriter) run(queue <-chan []*Node) {
+	for batch := range queue {
+		if err := bw.writeBatch(batch); err != nil {
+			bw.errMu.Lock()
+			if bw.err == nil {
+				bw.err = err
+			}
+			bw.errMu.Unlock()
+		}
+		bw.queued.Add(-int64(len(batch)))
+		bw.writes.Done()
+	}
+}
+
+// writeBatch stores one batch and updates the statistics.
+func (bw *BatchWriter) writeBatch(batch []*Node) error {
+	bw.flushes.Add(1)
+	if err := bw.store(batch); err != nil {
+		bw.failures.Add(1)
+		return err
+	}
+	var size int64
+	for _, node := range batch {
+		size += int64(len(node.Data))
+	}
+	bw.batchedWrites.Add(int64(len(batch)))
+	bw.bytesWritten.Add(size)
 	return nil
 }
 
-// PendingCount always returns 0 for synchronous writes.
+func (bw *BatchWriter) firstError() error {
+	bw.errMu.Lock()
+	defer bw.errMu.Unlock()
+	return bw.err
+}
+
+// Flush writes the buffered nodes, waits for all batches to be stored and
+// returns the first error since the previous Flush.
+func (bw *BatchWriter) Flush() error {
+	bw.mu.Lock()
+	bw.enqueueLocked()
+	bw.mu.Unlock()
+	bw.writes.Wait()
+
+	bw.errMu.Lock()
+	err := bw.err
+	bw.err = nil
+	bw.errMu.Unlock()
+
+	if err == nil && bw.config.SyncOnFlush && bw.syncFn != nil {
+		err = bw.syncFn()
+	}
+	return err
+}
+
+// Close flushes the buffered nodes and stops the writer goroutine.
+func (bw *BatchWriter) Close() error {
+	err := bw.Flush()
+
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	if !bw.closed {
+		bw.closed = true
+		if bw.queue != nil {
+			close(bw.queue)
+		}
+	}
+	return err
+}
+
+// PendingCount returns the number of nodes added but not yet stored.
 func (bw *BatchWriter) PendingCount() int {
-	return 0
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	return len(bw.pending) + int(bw.queued.Load())
 }
 
 // Stats returns statistics about the batch writer.
 func (bw *BatchWriter) Stats() BatchWriterStats {
-	return BatchWriterStats{}
+	return BatchWriterStats{
+		TotalWrites:   bw.totalWrites.Load(),
+		BatchedWrites: bw.batchedWrites.Load(),
+		Flushes:       bw.flushes.Load(),
+		Errors:        bw.failures.Load(),
+		BytesWritten:  bw.bytesWritten.Load(),
+		PendingCount:  bw.PendingCount(),
+	}
 }
 
 // BatchWriterStats holds statistics for the batch writer.
@@ -29,6 +29,10 @@ type Config struct {
 
 	// CreateIfMissing controls whether the database should be created if it doesn't exist.
 	CreateIfMissing bool `json:"create_if_missing" yaml:"create_if_missing"`
+
+	// ReadOnly opens the backend without write access. Backends that
+	// support it reject writes; others ignore it.
+	ReadOnly bool `json:"read_only" yaml:"read_only"`
 }
 
 // DefaultConfig returns a configuration with sensible defaults.
@@ -127,5 +131,6 @@ func (c *Config) Clone() *Config {
 		Compressor:      c.Compressor,
 		BatchSize:       c.BatchSize,
 		CreateIfMissing: c.CreateIfMissing,
+		ReadOnly:        c.ReadOnly,
 	}
 }
@@ -488,3 +488,75 @@ func TestDatabaseConfig(t *testing.T) {
 		}
 	})
 }
+
+func TestBatchWriter(t *testing.T) {
+	config := &nodestore.BatchWriteConfig{
+		PreallocationSize: 10,
+		LimitSize:         100,
+		FlushInterval:     10 * time.Millisecond,
+	}
+
+	t.Run("Add", func(t *testing.T) {
+		backend := nodestore.NewMemoryBackend()
+		if err := backend.Open(true); err != nil {
+			t.Fatalf("failed to open backend: %v", err)
+		}
+		defer backend.Close()
+
+		bw, err := nodestore.NewBatchWriter(backend, config)
+		if err != nil {
+			t.Fatalf("failed to create batch writer: %v", err)
+		}
+
+		nodes := make([]*nodestore.Node, 250)
+		for i := range nodes {
+			nodes[i] = nodestore.NewNode(nodestore.NodeAccount, nodestore.Blob(time.Duration(i).String()))
+			if err := bw.Add(nodes[i]); err != nil {
+				t.Fatalf("Add: %v", err)
+			}
+		}
+		if err := bw.Flush(); err != nil {
+			t.Fatalf("Flush: %v", err)
+		}
+		for _, node := range nodes {
+			if _, status := backend.Fetch(node.Hash); status != nodestore.OK {
+				t.Fatalf("node %x not stored: %s", node.Hash[:4], status)
+			}
+		}
+
+		stats := bw.Stats()
+		if stats.BatchedWrites != 250 || stats.Flushes != 3 || stats.PendingCount != 0 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+
+		if err := bw.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		if err := bw.Add(nodes[0]); err != nodestore.ErrBatchWriterClosed {
+			t.Errorf("Add after Close returned %v", err)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		backend := nodestore.NewMemoryBackend()
+		if err := backend.Open(true); err != nil {
+			t.Fatalf("failed to open backend: %v", err)
+		}
+		bw, err := nodestore.NewBatchWriter(backend, config)
+		if err != nil {
+			t.Fatalf("failed to create batch writer: %v", err)
+		}
+		defer bw.Close()
+
+		backend.Close()
+		for i := 0; i < 150; i++ {
+			bw.Add(nodestore.NewNode(nodestore.NodeAccount, nodestore.Blob(time.Duration(i).String())))
+		}
+		if err := bw.Flush(); err == nil {
+			t.Fatal("expected Flush to report the failed batch")
+		}
+		if stats := bw.Stats(); stats.Errors == 0 {
+			t.Errorf("expected errors in stats: %+v", stats)
+		}
+	})
+}
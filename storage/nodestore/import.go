@@ -0,0 +1,159 @@
+package nodestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultImportBatchSize is the number of objects written per StoreBatch
+// call during an import. Matches rippled's batchWritePreallocationSize.
+const DefaultImportBatchSize = 256
+
+// ImportOptions configures Import.
+type ImportOptions struct {
+	// BatchSize is the number of objects written per StoreBatch call.
+	// Default is DefaultImportBatchSize.
+	BatchSize int
+
+	// Verify checks every source object's hash before it is written and
+	// aborts the import on the first mismatch.
+	Verify bool
+
+	// HashFunc computes the expected key when Verify is set.
+	// Default (nil) is XRPLNodeHash.
+	HashFunc func(Blob) Hash256
+
+	// ProgressCallback is called after every ProgressInterval scanned
+	// objects and once more when the import finishes. Can be nil.
+	ProgressCallback func(ImportProgress)
+
+	// ProgressInterval specifies how often to call ProgressCallback.
+	// Default is every 100000 objects.
+	ProgressInterval int64
+}
+
+// ImportProgress reports how far an import has got.
+type ImportProgress struct {
+	Scanned  int64         // Objects read from the source
+	Imported int64         // Objects written to the destination
+	Skipped  int64         // Objects the destination already held
+	Bytes    int64         // Data bytes written
+	Elapsed  time.Duration // Time since the import started
+}
+
+// String returns a one-line summary of the progress.
+func (p ImportProgress) String() string {
+	return fmt.Sprintf("scanned=%d imported=%d skipped=%d bytes=%d elapsed=%s",
+		p.Scanned, p.Imported, p.Skipped, p.Bytes, p.Elapsed.Round(time.Second))
+}
+
+// Import copies every object in src into dst, as rippled's --import
+// does from [import_db] into [node_db]. Both backends must already be
+// open; src is only read.
+//
+// Objects dst already holds are skipped, so an interrupted import is
+// resumed by running it again. With Verify set, each object's hash is
+// checked through a BackendVerifier before it is written.
+func Import(ctx context.Context, src, dst Backend, opts *ImportOptions) (ImportProgress, error) {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultImportBatchSize
+	}
+	interval := opts.ProgressInterval
+	if interval <= 0 {
+		interval = 100000
+	}
+	hashFn := opts.HashFunc
+	if hashFn == nil {
+		hashFn = XRPLNodeHash
+	}
+	if !src.IsOpen() || !dst.IsOpen() {
+		return ImportProgress{}, ErrBackendClosed
+	}
+
+	start := time.Now()
+	verifier := NewBackendVerifier(src)
+	var progress ImportProgress
+	batch := make([]*Node, 0, batchSize)
+	keys := make([]Hash256, 0, batchSize)
+
+	report := func() {
+		if opts.ProgressCallback != nil {
+			progress.Elapsed = time.Since(start)
+			opts.ProgressCallback(progress)
+		}
+	}
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		keys = keys[:0]
+		for _, node := range batch {
+			keys = append(keys, node.Hash)
+		}
+		existing, status := dst.FetchBatch(keys)
+		if status != OK {
+			return fmt.Errorf("import: fetch from destination: %s", status)
+		}
+
+		pending := batch[:0]
+		for i, node := range batch {
+			if existing[i] != nil {
+				progress.Skipped++
+				continue
+			}
+			pending = append(pending, node)
+		}
+		if len(pending) > 0 {
+			if status := dst.StoreBatch(pending); status != OK {
+				return fmt.Errorf("import: store batch: %s", status)
+			}
+			for _, node := range pending {
+				progress.Imported++
+				progress.Bytes += int64(len(node.Data))
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	err := src.ForEach(func(node *Node) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if opts.Verify {
+			if err := verifier.CheckNode(node, hashFn); err != nil {
+				return fmt.Errorf("import: %w", err)
+			}
+		}
+
+		batch = append(batch, node)
+		progress.Scanned++
+		if len(batch) >= batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		if progress.Scanned%interval == 0 {
+			report()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err == nil {
+		if status := dst.Sync(); status != OK {
+			err = fmt.Errorf("import: sync destination: %s", status)
+		}
+	}
+
+	progress.Elapsed = time.Since(start)
+	report()
+	return progress, err
+}
@@ -0,0 +1,103 @@
+package nodestore_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/storage/nodestore"
+)
+
+func openMemory(t *testing.T) *nodestore.MemoryBackend {
+	t.Helper()
+	backend := nodestore.NewMemoryBackend()
+	if err := backend.Open(true); err != nil {
+		t.Fatalf("failed to open backend: %v", err)
+	}
+	t.Cleanup(func() { backend.Close() })
+	return backend
+}
+
+// xrplNode builds a node keyed the way rippled keys node objects.
+func xrplNode(i int) *nodestore.Node {
+	data := nodestore.Blob(fmt.Sprintf("MIN\x00import test node %d", i))
+	return &nodestore.Node{
+		Type: nodestore.NodeAccount,
+		Hash: nodestore.XRPLNodeHash(data),
+		Data: data,
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Run("CopiesAndResumes", func(t *testing.T) {
+		src := openMemory(t)
+		dst := openMemory(t)
+		for i := 0; i < 25; i++ {
+			src.Store(xrplNode(i))
+		}
+		// Objects from an earlier, interrupted run
+		dst.Store(xrplNode(3))
+		dst.Store(xrplNode(7))
+
+		var reports int
+		progress, err := nodestore.Import(context.Background(), src, dst, &nodestore.ImportOptions{
+			BatchSize:        4,
+			Verify:           true,
+			ProgressInterval: 10,
+			ProgressCallback: func(nodestore.ImportProgress) { reports++ },
+		})
+		if err != nil {
+			t.Fatalf("Import returned error: %v", err)
+		}
+		if progress.Scanned != 25 || progress.Imported != 23 || progress.Skipped != 2 {
+			t.Errorf("unexpected progress: %s", progress)
+		}
+		if reports != 3 {
+			t.Errorf("expected 3 progress reports, got %d", reports)
+		}
+		for i := 0; i < 25; i++ {
+			want := xrplNode(i)
+			got, status := dst.Fetch(want.Hash)
+			if status != nodestore.OK || string(got.Data) != string(want.Data) {
+				t.Fatalf("node %d not imported (status %s)", i, status)
+			}
+		}
+
+		again, err := nodestore.Import(context.Background(), src, dst, nil)
+		if err != nil {
+			t.Fatalf("second Import returned error: %v", err)
+		}
+		if again.Imported != 0 || again.Skipped != 25 {
+			t.Errorf("rerun should skip everything, got %s", again)
+		}
+	})
+
+	t.Run("VerifyRejectsHashMismatch", func(t *testing.T) {
+		src := openMemory(t)
+		dst := openMemory(t)
+		bad := xrplNode(1)
+		bad.Hash[0] ^= 0xFF
+		src.Store(bad)
+
+		_, err := nodestore.Import(context.Background(), src, dst, &nodestore.ImportOptions{Verify: true})
+		if !errors.Is(err, nodestore.ErrDataCorrupt) {
+			t.Fatalf("expected ErrDataCorrupt, got %v", err)
+		}
+		if _, status := dst.Fetch(bad.Hash); status != nodestore.NotFound {
+			t.Errorf("corrupt node must not be imported")
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		src := openMemory(t)
+		dst := openMemory(t)
+		src.Store(xrplNode(1))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := nodestore.Import(ctx, src, dst, nil); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package nodestore
+
+import (
+	"bytes"
+	"errors"
+	"sync/atomic"
+
+	"github.com/LeJamon/goXRPLd/storage/kvstore"
+)
+
+// KVBackend adapts a kvstore.KeyValueStore holding node objects, as
+// written by KVDatabaseImpl, to the Backend interface, so tools built on
+// Backend (BackendVerifier, Import, CollectStats) work on the stores the
+// server opens through the kvstore layer.
+type KVBackend struct {
+	store kvstore.KeyValueStore
+	name  string
+	open  atomic.Bool
+}
+
+// NewKVBackend wraps an already opened store. Closing the backend closes
+// the store.
+func NewKVBackend(store kvstore.KeyValueStore, name string) *KVBackend {
+	b := &KVBackend{store: store, name: name}
+	b.open.Store(true)
+	return b
+}
+
+// Name returns the name given to NewKVBackend.
+func (b *KVBackend) Name() string {
+	return b.name
+}
+
+// Open is a no-op: the store is opened before it is wrapped.
+func (b *KVBackend) Open(createIfMissing bool) error {
+	if !b.IsOpen() {
+		return ErrBackendClosed
+	}
+	return nil
+}
+
+// Close closes the underlying store.
+func (b *KVBackend) Close() error {
+	if !b.open.CompareAndSwap(true, false) {
+		return nil
+	}
+	return b.store.Close()
+}
+
+// IsOpen returns true until Close is called.
+func (b *KVBackend) IsOpen() bool {
+	return b.open.Load()
+}
+
+// Fetch retrieves a single object by key.
+func (b *KVBackend) Fetch(key Hash256) (*Node, Status) {
+	if !b.IsOpen() {
+		return nil, BackendError
+	}
+	data, err := b.store.Get(key[:])
+	if err != nil {
+		if errors.Is(err, kvstore.ErrNotFound) {
+			return nil, NotFound
+		}
+		return nil, BackendError
+	}
+	node, err := decodeNodeData(key, data)
+	if err != nil {
+		return nil, DataCorrupt
+	}
+	return node, OK
+}
+
+// FetchBatch retrieves multiple objects. Missing objects are nil.
+func (b *KVBackend) FetchBatch(keys []Hash256) ([]*Node, Status) {
+	nodes := make([]*Node, len(keys))
+	for i, key := range keys {
+		node, status := b.Fetch(key)
+		switch status {
+		case OK:
+			nodes[i] = node
+		case NotFound:
+		default:
+			return nil, status
+		}
+	}
+	return nodes, OK
+}
+
+// Store saves a single object.
+func (b *KVBackend) Store(node *Node) Status {
+	if !b.IsOpen() {
+		return BackendError
+	}
+	if err := b.store.Put(node.Hash[:], encodeNodeData(node)); err != nil {
+		return BackendError
+	}
+	return OK
+}
+
+// StoreBatch saves multiple objects in one store batch.
+func (b *KVBackend) StoreBatch(nodes []*Node) Status {
+	if !b.IsOpen() {
+		return BackendError
+	}
+	batch := b.store.NewBatch()
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		if err := batch.Put(node.Hash[:], encodeNodeData(node)); err != nil {
+			return BackendError
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return BackendError
+	}
+	return OK
+}
+
+// Sync flushes the store if it supports it.
+func (b *KVBackend) Sync() Status {
+	if !b.IsOpen() {
+		return BackendError
+	}
+	if s, ok := b.store.(interface{ Sync() error }); ok {
+		if err := s.Sync(); err != nil {
+			return BackendError
+		}
+	}
+	return OK
+}
+
+// ForEach iterates over all objects in key order.
+func (b *KVBackend) ForEach(fn func(*Node) error) error {
+	if !b.IsOpen() {
+		return ErrBackendClosed
+	}
+	it := b.store.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		hash, err := Hash256FromData(it.Key())
+		if err != nil {
+			return err
+		}
+		node, err := decodeNodeData(hash, it.Value())
+		if err != nil {
+			return err
+		}
+		if err := fn(node); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// GetWriteLoad returns 0; writes go straight to the store.
+func (b *KVBackend) GetWriteLoad() int {
+	return 0
+}
+
+// SetDeletePath is not supported and does nothing.
+func (b *KVBackend) SetDeletePath() {}
+
+// FdRequired returns 0; the store manages its own file descriptors.
+func (b *KVBackend) FdRequired() int {
+	return 0
+}
+
+// Compact compacts the whole key space of the store.
+func (b *KVBackend) Compact() error {
+	if !b.IsOpen() {
+		return ErrBackendClosed
+	}
+	// Keys are 32-byte hashes, so 33 0xFF bytes sorts after all of them.
+	return b.store.Compact(nil, bytes.Repeat([]byte{0xFF}, 33))
+}
+
+// Stat returns the store's own statistics report.
+func (b *KVBackend) Stat() (string, error) {
+	return b.store.Stat()
+}
+
+// Ensure KVBackend implements Backend at compile time.
+var _ Backend = (*KVBackend)(nil)
@@ -0,0 +1,106 @@
+package nodestore_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LeJamon/goXRPLd/storage/kvstore/memorydb"
+	"github.com/LeJamon/goXRPLd/storage/nodestore"
+)
+
+func TestKVBackend(t *testing.T) {
+	store := memorydb.New()
+
+	// Objects written through the database are visible to the backend.
+	db := nodestore.NewKVDatabase(store, "memory", 0, time.Hour)
+	nodes := make([]*nodestore.Node, 10)
+	for i := range nodes {
+		nodes[i] = xrplNode(i)
+		nodes[i].LedgerSeq = uint32(i)
+	}
+	if err := db.StoreBatch(context.Background(), nodes); err != nil {
+		t.Fatalf("StoreBatch failed: %v", err)
+	}
+
+	backend := nodestore.NewKVBackend(store, "memory")
+	t.Cleanup(func() { backend.Close() })
+
+	got, status := backend.Fetch(nodes[4].Hash)
+	if status != nodestore.OK {
+		t.Fatalf("Fetch returned %s", status)
+	}
+	if !bytes.Equal(got.Data, nodes[4].Data) || got.Type != nodes[4].Type || got.LedgerSeq != 4 {
+		t.Errorf("Fetch returned %+v, want %+v", got, nodes[4])
+	}
+	if _, status := backend.Fetch(xrplNode(99).Hash); status != nodestore.NotFound {
+		t.Errorf("expected NotFound for a missing object, got %s", status)
+	}
+
+	batch, status := backend.FetchBatch([]nodestore.Hash256{nodes[1].Hash, xrplNode(99).Hash})
+	if status != nodestore.OK || batch[0] == nil || batch[1] != nil {
+		t.Errorf("unexpected FetchBatch result: %v %s", batch, status)
+	}
+
+	if status := backend.Store(xrplNode(10)); status != nodestore.OK {
+		t.Fatalf("Store returned %s", status)
+	}
+	var count int
+	if err := backend.ForEach(func(*nodestore.Node) error { count++; return nil }); err != nil {
+		t.Fatalf("ForEach failed: %v", err)
+	}
+	if count != 11 {
+		t.Errorf("ForEach visited %d objects, want 11", count)
+	}
+
+	result, err := nodestore.NewBackendVerifier(backend).VerifyAll(&nodestore.VerifyOptions{
+		MaxCorruptNodes: 10,
+		HashFunc:        nodestore.XRPLNodeHash,
+	})
+	if err != nil || !result.IsValid() || result.TotalNodes != 11 {
+		t.Errorf("unexpected verification result: %v %v", result, err)
+	}
+
+	if err := backend.Compact(); err != nil {
+		t.Errorf("Compact failed: %v", err)
+	}
+	if err := backend.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, status := backend.Fetch(nodes[0].Hash); status != nodestore.BackendError {
+		t.Errorf("expected BackendError after Close, got %s", status)
+	}
+}
+
+func TestCollectStats(t *testing.T) {
+	backend := openMemory(t)
+	for i := 0; i < 5; i++ {
+		backend.Store(xrplNode(i))
+	}
+	header := &nodestore.Node{Type: nodestore.NodeLedger, Data: make(nodestore.Blob, 150)}
+	header.Hash = nodestore.XRPLNodeHash(header.Data)
+	backend.Store(header)
+
+	stats, err := nodestore.CollectStats(context.Background(), backend)
+	if err != nil {
+		t.Fatalf("CollectStats failed: %v", err)
+	}
+	if stats.Total.Count != 6 {
+		t.Errorf("expected 6 objects, got %d", stats.Total.Count)
+	}
+	if got := stats.ByType[nodestore.NodeAccount]; got == nil || got.Count != 5 {
+		t.Errorf("unexpected account stats: %+v", got)
+	}
+	ledger := stats.ByType[nodestore.NodeLedger]
+	if ledger == nil || ledger.Count != 1 || ledger.Bytes != 150 || ledger.Largest != 150 {
+		t.Fatalf("unexpected ledger stats: %+v", ledger)
+	}
+	// 150 bytes falls in the 128-255 bucket.
+	if ledger.Histogram[8] != 1 || ledger.Histogram.BucketLabel(8) != "128-255" {
+		t.Errorf("unexpected histogram: %v", ledger.Histogram)
+	}
+	if stats.Backend.Name != "memory" {
+		t.Errorf("unexpected backend info: %v", stats.Backend)
+	}
+}
@@ -0,0 +1,193 @@
+package nodestore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/pierrec/lz4"
+)
+
+// rippled stores node objects as an "encoded blob": 8 unused bytes
+// (formerly the ledger index, always zero), one type byte, then the
+// serialized object. Backends that compress, like NuDB, pass the blob
+// through nodeobject_compress, which prefixes a varint codec type:
+//
+//	0  uncompressed blob
+//	1  varint(blob size) followed by an LZ4 block
+//	2  inner node: uint16 branch mask followed by the non-zero child hashes
+//	3  inner node: all 16 child hashes
+//
+// Reference: rippled/src/xrpld/nodestore/detail/EncodedBlob.h and codec.h
+const (
+	codecUncompressed   = 0
+	codecLZ4            = 1
+	codecInnerCompact   = 2
+	codecInnerFull      = 3
+	blobHeaderSize      = 9
+	innerNodeBranches   = 16
+	innerNodeHashesSize = innerNodeBranches * 32
+	innerNodeBlobSize   = blobHeaderSize + 4 + innerNodeHashesSize
+)
+
+// innerNodePrefix is HashPrefix::innerNode ("MIN\0").
+var innerNodePrefix = [4]byte{'M', 'I', 'N', 0}
+
+// EncodeNodeObject returns a node in rippled's compressed node object
+// format, as written by rippled's NuDB backend. LedgerSeq is not stored.
+// Reference: rippled nodeobject_compress()
+func EncodeNodeObject(n *Node) []byte {
+	blob := make([]byte, blobHeaderSize+len(n.Data))
+	blob[8] = byte(n.Type)
+	copy(blob[blobHeaderSize:], n.Data)
+
+	if len(blob) == innerNodeBlobSize && bytes.Equal(blob[blobHeaderSize:blobHeaderSize+4], innerNodePrefix[:]) {
+		hashes := blob[blobHeaderSize+4:]
+		var mask uint16
+		compact := make([]byte, 0, innerNodeHashesSize)
+		var zero [32]byte
+		for i := 0; i < innerNodeBranches; i++ {
+			h := hashes[i*32 : (i+1)*32]
+			if bytes.Equal(h, zero[:]) {
+				continue
+			}
+			mask |= 0x8000 >> i
+			compact = append(compact, h...)
+		}
+		if len(compact) < innerNodeHashesSize {
+			out := appendVarint(nil, codecInnerCompact)
+			out = binary.BigEndian.AppendUint16(out, mask)
+			return append(out, compact...)
+		}
+		out := appendVarint(nil, codecInnerFull)
+		return append(out, hashes...)
+	}
+
+	out := appendVarint(nil, codecLZ4)
+	out = appendVarint(out, uint64(len(blob)))
+	head := len(out)
+	out = append(out, make([]byte, lz4.CompressBlockBound(len(blob)))...)
+	// A destination of CompressBlockBound always holds a valid block
+	written, _ := lz4.CompressBlock(blob, out[head:], nil)
+	if written == 0 {
+		// Incompressible input; store it uncompressed instead
+		out = appendVarint(out[:0], codecUncompressed)
+		return append(out, blob...)
+	}
+	return out[:head+written]
+}
+
+// DecodeNodeObject parses a value written by EncodeNodeObject or by
+// rippled's NuDB backend.
+// Reference: rippled nodeobject_decompress() and DecodedBlob
+func DecodeNodeObject(hash Hash256, value []byte) (*Node, error) {
+	codec, n := readVarint(value)
+	if n == 0 {
+		return nil, fmt.Errorf("%w: bad codec type", ErrDataCorrupt)
+	}
+	in := value[n:]
+
+	var blob []byte
+	switch codec {
+	case codecUncompressed:
+		blob = in
+	case codecLZ4:
+		size, n := readVarint(in)
+		if n == 0 || size == 0 || size > 1<<30 {
+			return nil, fmt.Errorf("%w: bad LZ4 size", ErrDataCorrupt)
+		}
+		blob = make([]byte, size)
+		written, err := lz4.UncompressBlock(in[n:], blob)
+		if err != nil || uint64(written) != size {
+			return nil, fmt.Errorf("%w: LZ4 decompression failed", ErrDataCorrupt)
+		}
+	case codecInnerCompact:
+		if len(in) < 2 {
+			return nil, fmt.Errorf("%w: short inner node", ErrDataCorrupt)
+		}
+		mask := binary.BigEndian.Uint16(in)
+		in = in[2:]
+		blob = newInnerNodeBlob()
+		hashes := blob[blobHeaderSize+4:]
+		for i := 0; i < innerNodeBranches; i++ {
+			if mask&(0x8000>>i) == 0 {
+				continue
+			}
+			if len(in) < 32 {
+				return nil, fmt.Errorf("%w: short inner node", ErrDataCorrupt)
+			}
+			copy(hashes[i*32:], in[:32])
+			in = in[32:]
+		}
+		if len(in) != 0 {
+			return nil, fmt.Errorf("%w: trailing inner node bytes", ErrDataCorrupt)
+		}
+	case codecInnerFull:
+		if len(in) != innerNodeHashesSize {
+			return nil, fmt.Errorf("%w: bad full inner node size %d", ErrDataCorrupt, len(in))
+		}
+		blob = newInnerNodeBlob()
+		copy(blob[blobHeaderSize+4:], in)
+	default:
+		return nil, fmt.Errorf("%w: unknown codec type %d", ErrDataCorrupt, codec)
+	}
+
+	if len(blob) < blobHeaderSize {
+		return nil, fmt.Errorf("%w: node object too short (%d bytes)", ErrDataCorrupt, len(blob))
+	}
+	nodeType := NodeType(blob[8])
+	switch nodeType {
+	case NodeUnknown, NodeLedger, NodeAccount, NodeTransaction:
+	default:
+		return nil, fmt.Errorf("%w: invalid node type %d", ErrDataCorrupt, nodeType)
+	}
+	return &Node{
+		Type: nodeType,
+		Hash: hash,
+		Data: append(Blob(nil), blob[blobHeaderSize:]...),
+	}, nil
+}
+
+// newInnerNodeBlob returns an inner node blob with zero hashes. rippled
+// writes decompressed inner nodes with type hotUNKNOWN.
+func newInnerNodeBlob() []byte {
+	blob := make([]byte, innerNodeBlobSize)
+	blob[8] = byte(NodeUnknown)
+	copy(blob[blobHeaderSize:], innerNodePrefix[:])
+	return blob
+}
+
+// appendVarint appends v in NuDB's base-127 varint encoding, least
+// significant digit first, with the high bit set on all but the last byte.
+// Reference: nudb/detail/varint.hpp
+func appendVarint(b []byte, v uint64) []byte {
+	for {
+		d := byte(v % 127)
+		v /= 127
+		if v != 0 {
+			d |= 0x80
+		}
+		b = append(b, d)
+		if v == 0 {
+			return b
+		}
+	}
+}
+
+// readVarint decodes a varint from the start of b, returning the value
+// and the number of bytes read, or 0 bytes if b does not start with one.
+func readVarint(b []byte) (uint64, int) {
+	n := 0
+	for n < len(b) && b[n]&0x80 != 0 {
+		n++
+	}
+	if n >= len(b) || n >= 10 {
+		return 0, 0
+	}
+	n++
+	var v uint64
+	for i := n - 1; i >= 0; i-- {
+		v = v*127 + uint64(b[i]&0x7f)
+	}
+	return v, n
+}
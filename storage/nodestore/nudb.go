@@ -0,0 +1,252 @@
+package nodestore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/LeJamon/goXRPLd/storage/nudb"
+)
+
+// NuDB file names inside the backend directory, as used by rippled.
+const (
+	nudbDatFile = "nudb.dat"
+	nudbKeyFile = "nudb.key"
+	nudbLogFile = "nudb.log"
+
+	// nudbAppNum is the application number rippled stamps on node stores.
+	nudbAppNum = 1
+)
+
+// NuDBBackend stores nodes in a NuDB database using rippled's on-disk
+// layout and node object codec, so an existing rippled node store
+// directory can be opened directly. With Config.ReadOnly set the files
+// are never modified and writes fail.
+//
+// Every Store or StoreBatch call is one durable NuDB commit, so callers
+// should prefer StoreBatch.
+// Reference: rippled/src/xrpld/nodestore/backend/NuDBFactory.cpp
+type NuDBBackend struct {
+	config *Config
+
+	mu         sync.RWMutex
+	db         *nudb.Store
+	deletePath int64
+}
+
+// NewNuDBBackend creates a new NuDB backend.
+func NewNuDBBackend(config *Config) (Backend, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return &NuDBBackend{config: config}, nil
+}
+
+// Name returns the name of this backend.
+func (b *NuDBBackend) Name() string {
+	return fmt.Sprintf("nudb(%s)", b.config.Path)
+}
+
+// Open opens the database, creating it when missing if createIfMissing
+// is set and the backend is writable.
+func (b *NuDBBackend) Open(createIfMissing bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.db != nil {
+		return fmt.Errorf("backend already open")
+	}
+
+	dir := b.config.Path
+	dat := filepath.Join(dir, nudbDatFile)
+	key := filepath.Join(dir, nudbKeyFile)
+	log := filepath.Join(dir, nudbLogFile)
+
+	if _, err := os.Stat(dat); errors.Is(err, os.ErrNotExist) {
+		if !createIfMissing || b.config.ReadOnly {
+			return fmt.Errorf("no NuDB database at %s", dir)
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+		if err := nudb.Create(dat, key, log, nudb.CreateOptions{
+			AppNum:     nudbAppNum,
+			KeySize:    len(Hash256{}),
+			BlockSize:  nudb.DefaultBlockSize,
+			LoadFactor: nudb.DefaultLoadFactor,
+		}); err != nil {
+			return fmt.Errorf("failed to create NuDB at %s: %w", dir, err)
+		}
+	}
+
+	db, err := nudb.Open(dat, key, log, b.config.ReadOnly)
+	if err != nil {
+		return fmt.Errorf("failed to open NuDB at %s: %w", dir, err)
+	}
+	if info := db.Info(); info.KeySize != len(Hash256{}) {
+		db.Close()
+		return fmt.Errorf("NuDB at %s has %d-byte keys, want %d", dir, info.KeySize, len(Hash256{}))
+	}
+	b.db = db
+	return nil
+}
+
+// Close closes the database, removing its directory if SetDeletePath
+// was called.
+func (b *NuDBBackend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.db == nil {
+		return nil
+	}
+	err := b.db.Close()
+	b.db = nil
+	if atomic.LoadInt64(&b.deletePath) != 0 {
+		if rerr := os.RemoveAll(b.config.Path); rerr != nil && err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+// IsOpen returns true if the backend is currently open.
+func (b *NuDBBackend) IsOpen() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.db != nil
+}
+
+// Fetch retrieves a single object by key.
+func (b *NuDBBackend) Fetch(key Hash256) (*Node, Status) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.db == nil {
+		return nil, BackendError
+	}
+	return b.fetch(key)
+}
+
+func (b *NuDBBackend) fetch(key Hash256) (*Node, Status) {
+	value, err := b.db.Fetch(key[:])
+	if err != nil {
+		if errors.Is(err, nudb.ErrNotFound) {
+			return nil, NotFound
+		}
+		return nil, BackendError
+	}
+	node, err := DecodeNodeObject(key, value)
+	if err != nil {
+		return nil, DataCorrupt
+	}
+	return node, OK
+}
+
+// FetchBatch retrieves multiple objects. Missing objects are nil.
+func (b *NuDBBackend) FetchBatch(keys []Hash256) ([]*Node, Status) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.db == nil {
+		return nil, BackendError
+	}
+	results := make([]*Node, len(keys))
+	for i, key := range keys {
+		node, status := b.fetch(key)
+		if status == OK {
+			results[i] = node
+		} else if status != NotFound {
+			return nil, status
+		}
+	}
+	return results, OK
+}
+
+// Store saves a single object.
+func (b *NuDBBackend) Store(node *Node) Status {
+	if node == nil {
+		return BackendError
+	}
+	return b.StoreBatch([]*Node{node})
+}
+
+// StoreBatch saves multiple objects in one NuDB commit. Objects already
+// present are left unchanged.
+func (b *NuDBBackend) StoreBatch(nodes []*Node) Status {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.db == nil || b.config.ReadOnly {
+		return BackendError
+	}
+	items := make([]nudb.Item, 0, len(nodes))
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		items = append(items, nudb.Item{Key: node.Hash[:], Value: EncodeNodeObject(node)})
+	}
+	if err := b.db.Commit(items); err != nil {
+		return BackendError
+	}
+	return OK
+}
+
+// Sync is a no-op; every commit is already durable.
+func (b *NuDBBackend) Sync() Status {
+	if !b.IsOpen() {
+		return BackendError
+	}
+	return OK
+}
+
+// ForEach iterates over all objects in insertion order. Unlike the
+// Pebble backend it stops at the first object that fails to decode, as
+// rippled's NuDB backend does.
+func (b *NuDBBackend) ForEach(fn func(*Node) error) error {
+	b.mu.RLock()
+	db := b.db
+	b.mu.RUnlock()
+	if db == nil {
+		return ErrBackendClosed
+	}
+	return db.Visit(func(key, value []byte) error {
+		var hash Hash256
+		copy(hash[:], key)
+		node, err := DecodeNodeObject(hash, value)
+		if err != nil {
+			return fmt.Errorf("node %x: %w", key, err)
+		}
+		return fn(node)
+	})
+}
+
+// GetWriteLoad returns 0 (no async write queue).
+func (b *NuDBBackend) GetWriteLoad() int {
+	return 0
+}
+
+// SetDeletePath marks the backend for deletion when closed.
+func (b *NuDBBackend) SetDeletePath() {
+	atomic.StoreInt64(&b.deletePath, 1)
+}
+
+// FdRequired returns the number of file descriptors needed: the data,
+// key and log files.
+func (b *NuDBBackend) FdRequired() int {
+	return 3
+}
+
+// BackendInfo returns information about this backend.
+func (b *NuDBBackend) BackendInfo() BackendInfo {
+	return BackendInfo{
+		Name:            "nudb",
+		Description:     "NuDB append-only store compatible with rippled node databases",
+		FileDescriptors: b.FdRequired(),
+		Persistent:      true,
+		Compression:     true,
+	}
+}
+
+func init() {
+	RegisterBackend("nudb", NewNuDBBackend)
+}
@@ -0,0 +1,139 @@
+package nodestore_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/storage/nodestore"
+)
+
+// innerNode builds an inner node payload with the given non-zero branches.
+func innerNode(branches ...int) nodestore.Blob {
+	data := make(nodestore.Blob, 4+16*32)
+	copy(data, "MIN\x00")
+	for _, b := range branches {
+		for i := 0; i < 32; i++ {
+			data[4+b*32+i] = byte(b + 1)
+		}
+	}
+	return data
+}
+
+func TestNodeObjectCodec(t *testing.T) {
+	leaf := bytes.Repeat([]byte("MLN\x00account state leaf "), 20)
+	tests := []struct {
+		name  string
+		node  *nodestore.Node
+		codec byte
+		size  int
+	}{
+		{"LZ4", &nodestore.Node{Type: nodestore.NodeAccount, Data: leaf}, 1, 0},
+		{"Small", &nodestore.Node{Type: nodestore.NodeLedger, Data: nodestore.Blob{1, 2, 3}}, 1, 0},
+		{"CompactInner", &nodestore.Node{Type: nodestore.NodeUnknown, Data: innerNode(0, 15)}, 2, 1 + 2 + 2*32},
+		{"FullInner", &nodestore.Node{Type: nodestore.NodeUnknown, Data: innerNode(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15)}, 3, 1 + 512},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.node.Hash = nodestore.XRPLNodeHash(tc.node.Data)
+			value := nodestore.EncodeNodeObject(tc.node)
+			if value[0] != tc.codec {
+				t.Fatalf("codec type %d, want %d", value[0], tc.codec)
+			}
+			if tc.size != 0 && len(value) != tc.size {
+				t.Fatalf("encoded size %d, want %d", len(value), tc.size)
+			}
+			got, err := nodestore.DecodeNodeObject(tc.node.Hash, value)
+			if err != nil {
+				t.Fatalf("DecodeNodeObject: %v", err)
+			}
+			if got.Type != tc.node.Type || !bytes.Equal(got.Data, tc.node.Data) {
+				t.Fatalf("round trip mismatch: type %d, %d bytes", got.Type, len(got.Data))
+			}
+		})
+	}
+
+	t.Run("CompactInnerLayout", func(t *testing.T) {
+		value := nodestore.EncodeNodeObject(&nodestore.Node{Data: innerNode(0, 15)})
+		if value[1] != 0x80 || value[2] != 0x01 {
+			t.Fatalf("branch mask %x, want 8001", value[1:3])
+		}
+	})
+
+	t.Run("Corrupt", func(t *testing.T) {
+		for _, value := range [][]byte{nil, {4}, {3, 0}, {2, 0x80, 0}, {1, 10, 0xFF}, {0, 0, 0}} {
+			if _, err := nodestore.DecodeNodeObject(nodestore.Hash256{}, value); !nodestore.IsDataCorrupt(err) {
+				t.Errorf("value %x: expected ErrDataCorrupt, got %v", value, err)
+			}
+		}
+	})
+}
+
+func TestNuDBBackend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nudb")
+
+	backend, err := nodestore.CreateBackend("nudb", &nodestore.Config{Path: path})
+	if err != nil {
+		t.Fatalf("CreateBackend: %v", err)
+	}
+	if err := backend.Open(false); err == nil {
+		t.Fatalf("Open without createIfMissing should fail on a missing database")
+	}
+	if err := backend.Open(true); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	src := openMemory(t)
+	for i := 0; i < 500; i++ {
+		src.Store(xrplNode(i))
+	}
+	inner := &nodestore.Node{Type: nodestore.NodeUnknown, Data: innerNode(3, 7)}
+	inner.Hash = nodestore.XRPLNodeHash(inner.Data)
+	src.Store(inner)
+
+	progress, err := nodestore.Import(context.Background(), src, backend, &nodestore.ImportOptions{BatchSize: 64, Verify: true})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if progress.Imported != 501 {
+		t.Fatalf("imported %d, want 501", progress.Imported)
+	}
+	if err := backend.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	ro, err := nodestore.CreateBackend("nudb", &nodestore.Config{Path: path, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("CreateBackend: %v", err)
+	}
+	if err := ro.Open(false); err != nil {
+		t.Fatalf("Open read-only: %v", err)
+	}
+	defer ro.Close()
+
+	for i := 0; i < 500; i++ {
+		want := xrplNode(i)
+		got, status := ro.Fetch(want.Hash)
+		if status != nodestore.OK || got.Type != want.Type || !bytes.Equal(got.Data, want.Data) {
+			t.Fatalf("node %d: status %s", i, status)
+		}
+	}
+	if got, status := ro.Fetch(inner.Hash); status != nodestore.OK || !bytes.Equal(got.Data, inner.Data) {
+		t.Fatalf("inner node: status %s", status)
+	}
+	if _, status := ro.Fetch(nodestore.Hash256{1}); status != nodestore.NotFound {
+		t.Fatalf("expected NotFound, got %s", status)
+	}
+	if status := ro.Store(xrplNode(1000)); status != nodestore.BackendError {
+		t.Fatalf("read-only Store returned %s", status)
+	}
+
+	var count int
+	if err := ro.ForEach(func(*nodestore.Node) error { count++; return nil }); err != nil {
+		t.Fatalf("ForEach: %v", err)
+	}
+	if count != 501 {
+		t.Fatalf("ForEach visited %d nodes, want 501", count)
+	}
+}
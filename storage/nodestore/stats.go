@@ -0,0 +1,120 @@
+package nodestore
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// sizeBuckets is the number of power-of-two buckets in a SizeHistogram.
+// The last bucket holds every object of 2^(sizeBuckets-2) bytes or more.
+const sizeBuckets = 18
+
+// SizeHistogram counts objects by data size in power-of-two buckets:
+// bucket 0 holds empty objects and bucket i objects of [2^(i-1), 2^i) bytes.
+type SizeHistogram [sizeBuckets]int64
+
+// Add counts an object of size bytes.
+func (h *SizeHistogram) Add(size int) {
+	i := bits.Len(uint(size))
+	if i >= sizeBuckets {
+		i = sizeBuckets - 1
+	}
+	h[i]++
+}
+
+// BucketLabel returns the size range counted by bucket i.
+func (h *SizeHistogram) BucketLabel(i int) string {
+	switch {
+	case i == 0:
+		return "0"
+	case i == sizeBuckets-1:
+		return fmt.Sprintf(">=%d", 1<<(i-1))
+	default:
+		return fmt.Sprintf("%d-%d", 1<<(i-1), 1<<i-1)
+	}
+}
+
+// TypeStats holds the object count and size distribution of one NodeType.
+type TypeStats struct {
+	Count     int64
+	Bytes     int64
+	Largest   int
+	Histogram SizeHistogram
+}
+
+// ObjectStats summarizes the objects held by a backend.
+type ObjectStats struct {
+	Backend BackendInfo
+	Total   TypeStats
+	ByType  map[NodeType]*TypeStats
+}
+
+// String returns a multi-line report of the statistics.
+func (s *ObjectStats) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Backend: %s\n", s.Backend)
+	fmt.Fprintf(&b, "Objects: %d (%d bytes)\n", s.Total.Count, s.Total.Bytes)
+
+	types := make([]NodeType, 0, len(s.ByType))
+	for t := range s.ByType {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	for _, t := range types {
+		ts := s.ByType[t]
+		fmt.Fprintf(&b, "  %-16s %12d objects %14d bytes  largest %d\n", t, ts.Count, ts.Bytes, ts.Largest)
+	}
+
+	b.WriteString("Size histogram (bytes: objects):\n")
+	for i, n := range s.Total.Histogram {
+		if n > 0 {
+			fmt.Fprintf(&b, "  %-14s %d\n", s.Total.Histogram.BucketLabel(i), n)
+		}
+	}
+	return b.String()
+}
+
+func (t *TypeStats) add(size int) {
+	t.Count++
+	t.Bytes += int64(size)
+	if size > t.Largest {
+		t.Largest = size
+	}
+	t.Histogram.Add(size)
+}
+
+// CollectStats scans every object in backend and counts them by type and
+// size. The backend must already be open.
+func CollectStats(ctx context.Context, backend Backend) (*ObjectStats, error) {
+	if !backend.IsOpen() {
+		return nil, ErrBackendClosed
+	}
+
+	stats := &ObjectStats{ByType: make(map[NodeType]*TypeStats)}
+	switch b := backend.(type) {
+	case BackendWithInfo:
+		stats.Backend = b.Info()
+	case interface{ BackendInfo() BackendInfo }:
+		stats.Backend = b.BackendInfo()
+	default:
+		stats.Backend = BackendInfo{Name: backend.Name()}
+	}
+
+	err := backend.ForEach(func(node *Node) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ts := stats.ByType[node.Type]
+		if ts == nil {
+			ts = &TypeStats{}
+			stats.ByType[node.Type] = ts
+		}
+		ts.add(len(node.Data))
+		stats.Total.add(len(node.Data))
+		return nil
+	})
+	return stats, err
+}
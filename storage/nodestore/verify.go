@@ -3,6 +3,8 @@ package nodestore
 import (
 	"fmt"
 	"sync/atomic"
+
+	"github.com/LeJamon/goXRPLd/crypto/common"
 )
 
 // Verifier defines the interface for data verification operations.
@@ -65,6 +67,11 @@ type VerifyOptions struct {
 	// ProgressInterval specifies how often to call ProgressCallback.
 	// Default is every 10000 nodes.
 	ProgressInterval int64
+
+	// HashFunc computes the key a node's data must hash to.
+	// Default (nil) is ComputeHash256; use XRPLNodeHash for node objects
+	// keyed the way rippled keys them.
+	HashFunc func(Blob) Hash256
 }
 
 // DefaultVerifyOptions returns default verification options.
@@ -150,7 +157,7 @@ func (v *BackendVerifier) VerifyAll(opts *VerifyOptions) (*VerificationResult, e
 		}
 
 		// Verify hash matches content
-		expectedHash := ComputeHash256(node.Data)
+		expectedHash := hashNode(opts.HashFunc, node.Data)
 		if node.Hash != expectedHash {
 			atomic.AddInt64(&result.HashMismatch, 1)
 			atomic.AddInt64(&result.CorruptNodes, 1)
@@ -186,16 +193,36 @@ func (v *BackendVerifier) VerifyNode(hash Hash256) error {
 		return fmt.Errorf("failed to fetch node %x: %s", hash, status.String())
 	}
 
+	return v.CheckNode(node, nil)
+}
+
+// CheckNode verifies a node that has already been read, such as one
+// streamed out of the backend by ForEach. hashFn computes the expected
+// key from the data; nil means ComputeHash256.
+func (v *BackendVerifier) CheckNode(node *Node, hashFn func(Blob) Hash256) error {
 	// Check for missing data
 	if len(node.Data) == 0 {
-		return fmt.Errorf("node has missing data: %x", hash)
+		return fmt.Errorf("%w: node has missing data: %x", ErrDataCorrupt, node.Hash)
 	}
 
 	// Verify hash matches content
-	expectedHash := ComputeHash256(node.Data)
+	expectedHash := hashNode(hashFn, node.Data)
 	if node.Hash != expectedHash {
-		return fmt.Errorf("hash mismatch for node %x: computed %x", hash, expectedHash)
+		return fmt.Errorf("%w: hash mismatch for node %x: computed %x", ErrDataCorrupt, node.Hash, expectedHash)
 	}
 
 	return nil
 }
+
+// XRPLNodeHash is the key rippled stores a node object under: the
+// SHA-512Half of its serialized data, hash prefix included.
+func XRPLNodeHash(data Blob) Hash256 {
+	return Hash256(common.Sha512Half(data))
+}
+
+func hashNode(hashFn func(Blob) Hash256, data Blob) Hash256 {
+	if hashFn == nil {
+		return ComputeHash256(data)
+	}
+	return hashFn(data)
+}
@@ -0,0 +1,112 @@
+package nudb
+
+import (
+	"fmt"
+	"sort"
+)
+
+// entry is one bucket slot: where a value lives in the data file, how
+// big it is, and the 48-bit hash of its key.
+type entry struct {
+	offset uint64
+	size   uint64
+	hash   uint64
+}
+
+// bucket is a decoded key file bucket. Entries are kept sorted by hash.
+// A non-zero spill is the data file offset of the bucket that overflowed
+// out of this one.
+// Reference: nudb/detail/bucket.hpp
+type bucket struct {
+	capacity int
+	spill    uint64
+	entries  []entry
+}
+
+func newBucket(capacity int) *bucket {
+	return &bucket{capacity: capacity, entries: make([]entry, 0, capacity)}
+}
+
+// decodeBucket parses a bucket image. b may be longer than the encoded
+// bucket (a padded block) but not shorter.
+func decodeBucket(b []byte, capacity int) (*bucket, error) {
+	if len(b) < bucketHeaderSize {
+		return nil, fmt.Errorf("%w: short bucket", ErrCorrupt)
+	}
+	count := int(uint16(b[0])<<8 | uint16(b[1]))
+	if count > capacity || len(b) < bucketSize(count) {
+		return nil, fmt.Errorf("%w: bucket count %d exceeds capacity %d", ErrCorrupt, count, capacity)
+	}
+	bk := newBucket(capacity)
+	bk.spill = getUint48(b[2:])
+	p := b[bucketHeaderSize:]
+	for i := 0; i < count; i++ {
+		bk.entries = append(bk.entries, entry{
+			offset: getUint48(p),
+			size:   getUint48(p[6:]),
+			hash:   getUint48(p[12:]),
+		})
+		p = p[bucketEntrySize:]
+	}
+	return bk, nil
+}
+
+// encode writes the bucket into b, which must hold at least
+// bucketSize(len(entries)) bytes. Any remaining bytes are zeroed so a
+// padded block is written deterministically.
+func (bk *bucket) encode(b []byte) {
+	n := len(bk.entries)
+	b[0] = byte(n >> 8)
+	b[1] = byte(n)
+	putUint48(b[2:], bk.spill)
+	p := b[bucketHeaderSize:]
+	for _, e := range bk.entries {
+		putUint48(p, e.offset)
+		putUint48(p[6:], e.size)
+		putUint48(p[12:], e.hash)
+		p = p[bucketEntrySize:]
+	}
+	clear(p)
+}
+
+// bytes returns the bucket encoded at its actual size, as stored in spill
+// records and the log file.
+func (bk *bucket) bytes() []byte {
+	b := make([]byte, bucketSize(len(bk.entries)))
+	bk.encode(b)
+	return b
+}
+
+func (bk *bucket) full() bool {
+	return len(bk.entries) >= bk.capacity
+}
+
+// lowerBound returns the index of the first entry whose hash is not less
+// than h.
+func (bk *bucket) lowerBound(h uint64) int {
+	return sort.Search(len(bk.entries), func(i int) bool {
+		return bk.entries[i].hash >= h
+	})
+}
+
+// insert adds an entry, keeping entries sorted by hash. Entries with
+// equal hashes keep insertion order.
+func (bk *bucket) insert(e entry) {
+	i := sort.Search(len(bk.entries), func(i int) bool {
+		return bk.entries[i].hash > e.hash
+	})
+	bk.entries = append(bk.entries, entry{})
+	copy(bk.entries[i+1:], bk.entries[i:])
+	bk.entries[i] = e
+}
+
+// erase removes the entry at index i.
+func (bk *bucket) erase(i int) {
+	bk.entries = append(bk.entries[:i], bk.entries[i+1:]...)
+}
+
+// reset empties the bucket, keeping its capacity.
+func (bk *bucket) reset() {
+	bk.entries = bk.entries[:0]
+	bk.spill = 0
+}
@@ -0,0 +1,286 @@
+// Package nudb reads and writes NuDB databases, the append-only key/value
+// store rippled uses for its node store.
+//
+// A database is three files. The data file (.dat) holds every value as an
+// appended record, plus "spill" records for overflowing buckets. The key
+// file (.key) is a linear hash table of fixed-size bucket blocks pointing
+// into the data file. The log file (.log) holds the pre-image of every
+// bucket a commit is about to overwrite, so an interrupted commit can be
+// rolled back on the next open.
+//
+// All integers are big-endian. The layout matches NuDB version 2 as
+// vendored by rippled (nudb/detail/format.hpp), so databases created by
+// rippled open here and vice versa.
+package nudb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// Version is the NuDB file format version.
+const Version = 2
+
+// Header sizes. Reference: nudb/detail/format.hpp
+const (
+	DatHeaderSize = 8 + 2 + 8 + 8 + 2 + 64                 // type, version, uid, appnum, key size, reserved
+	KeyHeaderSize = 8 + 2 + 8 + 8 + 2 + 8 + 8 + 2 + 2 + 56 // ... salt, pepper, block size, load factor, reserved
+	LogHeaderSize = 8 + 2 + 8 + 8 + 2 + 8 + 8 + 2 + 8 + 8  // ... salt, pepper, block size, key/dat file sizes
+)
+
+// Defaults used by rippled's NuDB factory.
+const (
+	DefaultBlockSize  = 4096
+	DefaultLoadFactor = 0.5
+)
+
+const (
+	bucketHeaderSize = 2 + 6     // count, spill
+	bucketEntrySize  = 6 + 6 + 6 // offset, size, hash
+	spillHeaderSize  = 6 + 2     // zero, size
+	maxUint48        = 1<<48 - 1
+)
+
+var (
+	datType = [8]byte{'n', 'u', 'd', 'b', '.', 'd', 'a', 't'}
+	keyType = [8]byte{'n', 'u', 'd', 'b', '.', 'k', 'e', 'y'}
+	logType = [8]byte{'n', 'u', 'd', 'b', '.', 'l', 'o', 'g'}
+)
+
+var (
+	// ErrNotFound is returned when a key is not in the database.
+	ErrNotFound = errors.New("nudb: key not found")
+
+	// ErrReadOnly is returned when writing to a database opened read-only.
+	ErrReadOnly = errors.New("nudb: database is read-only")
+
+	// ErrNeedsRecovery is returned when a read-only open finds an
+	// unfinished commit in the log file.
+	ErrNeedsRecovery = errors.New("nudb: log file present, database needs recovery")
+
+	// ErrCorrupt is returned when a file does not parse as NuDB.
+	ErrCorrupt = errors.New("nudb: corrupt database")
+
+	// ErrClosed is returned when using a closed database.
+	ErrClosed = errors.New("nudb: database closed")
+)
+
+type datFileHeader struct {
+	version uint16
+	uid     uint64
+	appnum  uint64
+	keySize uint16
+}
+
+func (h *datFileHeader) encode() []byte {
+	b := make([]byte, DatHeaderSize)
+	copy(b, datType[:])
+	binary.BigEndian.PutUint16(b[8:], h.version)
+	binary.BigEndian.PutUint64(b[10:], h.uid)
+	binary.BigEndian.PutUint64(b[18:], h.appnum)
+	binary.BigEndian.PutUint16(b[26:], h.keySize)
+	return b
+}
+
+func decodeDatHeader(b []byte) (datFileHeader, error) {
+	var h datFileHeader
+	if len(b) < DatHeaderSize || [8]byte(b[:8]) != datType {
+		return h, fmt.Errorf("%w: bad data file header", ErrCorrupt)
+	}
+	h.version = binary.BigEndian.Uint16(b[8:])
+	h.uid = binary.BigEndian.Uint64(b[10:])
+	h.appnum = binary.BigEndian.Uint64(b[18:])
+	h.keySize = binary.BigEndian.Uint16(b[26:])
+	return h, nil
+}
+
+type keyFileHeader struct {
+	version    uint16
+	uid        uint64
+	appnum     uint64
+	keySize    uint16
+	salt       uint64
+	pepper     uint64
+	blockSize  uint16
+	loadFactor uint16
+
+	// Computed from the block size and the key file size
+	capacity int    // entries per bucket
+	buckets  uint64 // number of buckets
+	modulus  uint64 // ceilPow2(buckets)
+}
+
+func (h *keyFileHeader) encode() []byte {
+	b := make([]byte, KeyHeaderSize)
+	copy(b, keyType[:])
+	binary.BigEndian.PutUint16(b[8:], h.version)
+	binary.BigEndian.PutUint64(b[10:], h.uid)
+	binary.BigEndian.PutUint64(b[18:], h.appnum)
+	binary.BigEndian.PutUint16(b[26:], h.keySize)
+	binary.BigEndian.PutUint64(b[28:], h.salt)
+	binary.BigEndian.PutUint64(b[36:], h.pepper)
+	binary.BigEndian.PutUint16(b[44:], h.blockSize)
+	binary.BigEndian.PutUint16(b[46:], h.loadFactor)
+	return b
+}
+
+func decodeKeyHeader(b []byte, fileSize int64) (keyFileHeader, error) {
+	var h keyFileHeader
+	if len(b) < KeyHeaderSize || [8]byte(b[:8]) != keyType {
+		return h, fmt.Errorf("%w: bad key file header", ErrCorrupt)
+	}
+	h.version = binary.BigEndian.Uint16(b[8:])
+	h.uid = binary.BigEndian.Uint64(b[10:])
+	h.appnum = binary.BigEndian.Uint64(b[18:])
+	h.keySize = binary.BigEndian.Uint16(b[26:])
+	h.salt = binary.BigEndian.Uint64(b[28:])
+	h.pepper = binary.BigEndian.Uint64(b[36:])
+	h.blockSize = binary.BigEndian.Uint16(b[44:])
+	h.loadFactor = binary.BigEndian.Uint16(b[46:])
+
+	h.capacity = bucketCapacity(int(h.blockSize))
+	if h.blockSize > 0 && fileSize > int64(h.blockSize) {
+		h.buckets = uint64((fileSize - int64(h.blockSize)) / int64(h.blockSize))
+	}
+	h.modulus = ceilPow2(h.buckets)
+	return h, nil
+}
+
+type logFileHeader struct {
+	version     uint16
+	uid         uint64
+	appnum      uint64
+	keySize     uint16
+	salt        uint64
+	pepper      uint64
+	blockSize   uint16
+	keyFileSize uint64
+	datFileSize uint64
+}
+
+func (h *logFileHeader) encode() []byte {
+	b := make([]byte, LogHeaderSize)
+	copy(b, logType[:])
+	binary.BigEndian.PutUint16(b[8:], h.version)
+	binary.BigEndian.PutUint64(b[10:], h.uid)
+	binary.BigEndian.PutUint64(b[18:], h.appnum)
+	binary.BigEndian.PutUint16(b[26:], h.keySize)
+	binary.BigEndian.PutUint64(b[28:], h.salt)
+	binary.BigEndian.PutUint64(b[36:], h.pepper)
+	binary.BigEndian.PutUint16(b[44:], h.blockSize)
+	binary.BigEndian.PutUint64(b[46:], h.keyFileSize)
+	binary.BigEndian.PutUint64(b[54:], h.datFileSize)
+	return b
+}
+
+func decodeLogHeader(b []byte) (logFileHeader, error) {
+	var h logFileHeader
+	if len(b) < LogHeaderSize || [8]byte(b[:8]) != logType {
+		return h, fmt.Errorf("%w: bad log file header", ErrCorrupt)
+	}
+	h.version = binary.BigEndian.Uint16(b[8:])
+	h.uid = binary.BigEndian.Uint64(b[10:])
+	h.appnum = binary.BigEndian.Uint64(b[18:])
+	h.keySize = binary.BigEndian.Uint16(b[26:])
+	h.salt = binary.BigEndian.Uint64(b[28:])
+	h.pepper = binary.BigEndian.Uint64(b[36:])
+	h.blockSize = binary.BigEndian.Uint16(b[44:])
+	h.keyFileSize = binary.BigEndian.Uint64(b[46:])
+	h.datFileSize = binary.BigEndian.Uint64(b[54:])
+	return h, nil
+}
+
+// verifyHeaders checks the data and key file headers against each other,
+// as nudb::detail::verify does on open.
+func verifyHeaders(dh datFileHeader, kh keyFileHeader) error {
+	switch {
+	case dh.version != Version || kh.version != Version:
+		return fmt.Errorf("%w: unsupported version %d/%d", ErrCorrupt, dh.version, kh.version)
+	case dh.keySize == 0 || dh.keySize != kh.keySize:
+		return fmt.Errorf("%w: key size mismatch", ErrCorrupt)
+	case dh.uid != kh.uid:
+		return fmt.Errorf("%w: uid mismatch", ErrCorrupt)
+	case dh.appnum != kh.appnum:
+		return fmt.Errorf("%w: appnum mismatch", ErrCorrupt)
+	case kh.pepper != pepper(kh.salt):
+		return fmt.Errorf("%w: hash function mismatch", ErrCorrupt)
+	case kh.loadFactor == 0:
+		return fmt.Errorf("%w: zero load factor", ErrCorrupt)
+	case kh.capacity < 1:
+		return fmt.Errorf("%w: block size %d too small", ErrCorrupt, kh.blockSize)
+	case kh.buckets < 1:
+		return fmt.Errorf("%w: key file has no buckets", ErrCorrupt)
+	}
+	return nil
+}
+
+// hashKey returns the bucket hash of a key: the XXH64 digest seeded with
+// the salt, reduced to the 48 bits stored in bucket entries.
+// Reference: nudb hash() and make_hash<uint48_t>()
+func hashKey(key []byte, salt uint64) uint64 {
+	d := xxhash.NewWithSeed(salt)
+	_, _ = d.Write(key)
+	return (d.Sum64() >> 16) & maxUint48
+}
+
+// pepper is the salt hashed with itself, stored so that opening with a
+// different hash function is detected.
+func pepper(salt uint64) uint64 {
+	var v [8]byte
+	binary.LittleEndian.PutUint64(v[:], salt)
+	d := xxhash.NewWithSeed(salt)
+	_, _ = d.Write(v[:])
+	return d.Sum64()
+}
+
+// bucketCapacity returns the number of entries that fit in one block.
+func bucketCapacity(blockSize int) int {
+	if blockSize < KeyHeaderSize || blockSize < bucketHeaderSize {
+		return 0
+	}
+	n := (blockSize - bucketHeaderSize) / bucketEntrySize
+	if n > 0xFFFF {
+		n = 0xFFFF
+	}
+	return n
+}
+
+// bucketSize returns the encoded size of a bucket holding n entries.
+func bucketSize(n int) int {
+	return bucketHeaderSize + n*bucketEntrySize
+}
+
+// bucketIndex maps a hash to a bucket under linear hashing.
+func bucketIndex(h, buckets, modulus uint64) uint64 {
+	n := h % modulus
+	if n >= buckets {
+		n -= modulus / 2
+	}
+	return n
+}
+
+// ceilPow2 returns the smallest power of two not less than x.
+func ceilPow2(x uint64) uint64 {
+	p := uint64(1)
+	for p < x {
+		p <<= 1
+	}
+	return p
+}
+
+func getUint48(b []byte) uint64 {
+	return uint64(b[0])<<40 | uint64(b[1])<<32 | uint64(b[2])<<24 |
+		uint64(b[3])<<16 | uint64(b[4])<<8 | uint64(b[5])
+}
+
+func putUint48(b []byte, v uint64) {
+	b[0] = byte(v >> 40)
+	b[1] = byte(v >> 32)
+	b[2] = byte(v >> 24)
+	b[3] = byte(v >> 16)
+	b[4] = byte(v >> 8)
+	b[5] = byte(v)
+}
@@ -0,0 +1,770 @@
+package nudb
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+)
+
+// CreateOptions configures a new database.
+type CreateOptions struct {
+	// AppNum is an application-defined value stored in the headers.
+	// rippled's node store uses 1.
+	AppNum uint64
+
+	// KeySize is the fixed key length in bytes. Required.
+	KeySize int
+
+	// BlockSize is the key file block size. Default is DefaultBlockSize.
+	BlockSize int
+
+	// LoadFactor is the target bucket fill ratio in (0, 1).
+	// Default is DefaultLoadFactor.
+	LoadFactor float64
+
+	// Salt seeds the key hash. Zero picks a random salt.
+	Salt uint64
+}
+
+// Item is a key/value pair passed to Commit.
+type Item struct {
+	Key   []byte
+	Value []byte
+}
+
+// Info describes an open database.
+type Info struct {
+	AppNum    uint64
+	KeySize   int
+	BlockSize int
+	Buckets   uint64
+	DataSize  int64
+	ReadOnly  bool
+}
+
+// Store is an open NuDB database. Fetch and Visit may run concurrently
+// with each other and with Commit; commits are serialized.
+type Store struct {
+	mu       sync.RWMutex
+	datPath  string
+	keyPath  string
+	logPath  string
+	dat      *os.File
+	key      *os.File
+	log      *os.File
+	readOnly bool
+	closed   bool
+
+	dh datFileHeader
+	kh keyFileHeader
+
+	datSize int64  // end of the data file
+	thresh  uint64 // split threshold, scaled by 65536
+	frac    uint64 // accumulated load since the last split
+
+	// err is set when a commit fails part way. The files are recovered
+	// on the next open; until then writes are refused.
+	err error
+}
+
+// Create makes a new, empty database. It fails if any of the files
+// already exist.
+// Reference: nudb/create.hpp
+func Create(datPath, keyPath, logPath string, opts CreateOptions) error {
+	if opts.KeySize < 1 || opts.KeySize > math.MaxUint16 {
+		return fmt.Errorf("nudb: invalid key size %d", opts.KeySize)
+	}
+	blockSize := opts.BlockSize
+	if blockSize == 0 {
+		blockSize = DefaultBlockSize
+	}
+	if blockSize > math.MaxUint16 || bucketCapacity(blockSize) < 1 {
+		return fmt.Errorf("nudb: invalid block size %d", blockSize)
+	}
+	loadFactor := opts.LoadFactor
+	if loadFactor == 0 {
+		loadFactor = DefaultLoadFactor
+	}
+	if loadFactor <= 0 || loadFactor >= 1 {
+		return fmt.Errorf("nudb: invalid load factor %v", loadFactor)
+	}
+	salt := opts.Salt
+	if salt == 0 {
+		salt = randomUint64()
+	}
+	if _, err := os.Stat(logPath); err == nil {
+		return fmt.Errorf("nudb: %s already exists", logPath)
+	}
+
+	dh := datFileHeader{
+		version: Version,
+		uid:     randomUint64(),
+		appnum:  opts.AppNum,
+		keySize: uint16(opts.KeySize),
+	}
+	kh := keyFileHeader{
+		version:    Version,
+		uid:        dh.uid,
+		appnum:     dh.appnum,
+		keySize:    dh.keySize,
+		salt:       salt,
+		pepper:     pepper(salt),
+		blockSize:  uint16(blockSize),
+		loadFactor: uint16(min(65536*loadFactor, math.MaxUint16)),
+	}
+
+	// Header block followed by one empty bucket
+	keyImage := make([]byte, 2*blockSize)
+	copy(keyImage, kh.encode())
+
+	if err := writeNewFile(datPath, dh.encode()); err != nil {
+		return err
+	}
+	if err := writeNewFile(keyPath, keyImage); err != nil {
+		os.Remove(datPath)
+		return err
+	}
+	return nil
+}
+
+func writeNewFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("nudb: %w", err)
+	}
+	if _, err := f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("nudb: write %s: %w", path, err)
+	}
+	return nil
+}
+
+func randomUint64() uint64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// Open opens an existing database. A writable open first rolls back any
+// commit left unfinished in the log file; a read-only open refuses such a
+// database with ErrNeedsRecovery instead of modifying it.
+func Open(datPath, keyPath, logPath string, readOnly bool) (*Store, error) {
+	flag := os.O_RDWR
+	if readOnly {
+		flag = os.O_RDONLY
+	}
+	s := &Store{datPath: datPath, keyPath: keyPath, logPath: logPath, readOnly: readOnly}
+
+	var err error
+	if s.dat, err = os.OpenFile(datPath, flag, 0); err != nil {
+		return nil, fmt.Errorf("nudb: %w", err)
+	}
+	if s.key, err = os.OpenFile(keyPath, flag, 0); err != nil {
+		s.dat.Close()
+		return nil, fmt.Errorf("nudb: %w", err)
+	}
+	if err := s.open(); err != nil {
+		s.closeFiles()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) open() error {
+	if fi, err := os.Stat(s.logPath); err == nil && fi.Size() > 0 {
+		if s.readOnly {
+			return ErrNeedsRecovery
+		}
+		if err := s.recover(); err != nil {
+			return err
+		}
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("nudb: %w", err)
+	}
+
+	if !s.readOnly {
+		log, err := os.OpenFile(s.logPath, os.O_RDWR|os.O_CREATE, 0644)
+		if err != nil {
+			return fmt.Errorf("nudb: %w", err)
+		}
+		s.log = log
+	}
+
+	hdr := make([]byte, KeyHeaderSize)
+	if _, err := s.dat.ReadAt(hdr[:DatHeaderSize], 0); err != nil {
+		return fmt.Errorf("%w: read data file header: %v", ErrCorrupt, err)
+	}
+	dh, err := decodeDatHeader(hdr)
+	if err != nil {
+		return err
+	}
+	if _, err := s.key.ReadAt(hdr, 0); err != nil {
+		return fmt.Errorf("%w: read key file header: %v", ErrCorrupt, err)
+	}
+	keyInfo, err := s.key.Stat()
+	if err != nil {
+		return fmt.Errorf("nudb: %w", err)
+	}
+	kh, err := decodeKeyHeader(hdr, keyInfo.Size())
+	if err != nil {
+		return err
+	}
+	if err := verifyHeaders(dh, kh); err != nil {
+		return err
+	}
+	datInfo, err := s.dat.Stat()
+	if err != nil {
+		return fmt.Errorf("nudb: %w", err)
+	}
+
+	s.dh, s.kh = dh, kh
+	s.datSize = datInfo.Size()
+	s.thresh = max(65536, uint64(kh.loadFactor)*uint64(kh.capacity))
+	s.frac = s.thresh / 2
+	return nil
+}
+
+// recover restores the buckets saved in the log file and truncates the
+// key and data files to their sizes before the interrupted commit.
+// Reference: nudb/recover.hpp
+func (s *Store) recover() error {
+	log, err := os.OpenFile(s.logPath, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("nudb: %w", err)
+	}
+	defer log.Close()
+
+	r := bufio.NewReader(log)
+	hdr := make([]byte, LogHeaderSize)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		// The header is written before anything else, so a short header
+		// means the commit never touched the other files.
+		return truncateSync(log, 0)
+	}
+	lh, err := decodeLogHeader(hdr)
+	if err != nil {
+		return err
+	}
+
+	khdr := make([]byte, KeyHeaderSize)
+	if _, err := s.key.ReadAt(khdr, 0); err != nil {
+		return fmt.Errorf("%w: read key file header: %v", ErrCorrupt, err)
+	}
+	kh, err := decodeKeyHeader(khdr, int64(lh.keyFileSize))
+	if err != nil {
+		return err
+	}
+	if lh.version != kh.version || lh.uid != kh.uid || lh.appnum != kh.appnum ||
+		lh.keySize != kh.keySize || lh.salt != kh.salt || lh.pepper != kh.pepper ||
+		lh.blockSize != kh.blockSize {
+		return fmt.Errorf("%w: log file does not match key file", ErrCorrupt)
+	}
+
+	block := make([]byte, kh.blockSize)
+	var idx [8]byte
+	for {
+		// A record cut short was never followed by key file writes
+		if _, err := io.ReadFull(r, idx[:]); err != nil {
+			break
+		}
+		var count [bucketHeaderSize]byte
+		if _, err := io.ReadFull(r, count[:]); err != nil {
+			break
+		}
+		n := int(binary.BigEndian.Uint16(count[:]))
+		if n > kh.capacity {
+			return fmt.Errorf("%w: log bucket count %d exceeds capacity", ErrCorrupt, n)
+		}
+		clear(block)
+		copy(block, count[:])
+		if _, err := io.ReadFull(r, block[bucketHeaderSize:bucketSize(n)]); err != nil {
+			break
+		}
+		index := binary.BigEndian.Uint64(idx[:])
+		if index >= kh.buckets {
+			return fmt.Errorf("%w: log bucket index %d out of range", ErrCorrupt, index)
+		}
+		if _, err := s.key.WriteAt(block, int64(index+1)*int64(kh.blockSize)); err != nil {
+			return fmt.Errorf("nudb: restore bucket: %w", err)
+		}
+	}
+
+	if err := truncateSync(s.key, int64(lh.keyFileSize)); err != nil {
+		return err
+	}
+	if err := truncateSync(s.dat, int64(lh.datFileSize)); err != nil {
+		return err
+	}
+	return truncateSync(log, 0)
+}
+
+func truncateSync(f *os.File, size int64) error {
+	if err := f.Truncate(size); err != nil {
+		return fmt.Errorf("nudb: truncate %s: %w", f.Name(), err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("nudb: sync %s: %w", f.Name(), err)
+	}
+	return nil
+}
+
+// Info returns the database parameters.
+func (s *Store) Info() Info {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Info{
+		AppNum:    s.dh.appnum,
+		KeySize:   int(s.kh.keySize),
+		BlockSize: int(s.kh.blockSize),
+		Buckets:   s.kh.buckets,
+		DataSize:  s.datSize,
+		ReadOnly:  s.readOnly,
+	}
+}
+
+// Fetch returns the value stored under key, or ErrNotFound.
+// Reference: nudb basic_store::fetch
+func (s *Store) Fetch(key []byte) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+	if len(key) != int(s.kh.keySize) {
+		return nil, fmt.Errorf("nudb: key size %d, want %d", len(key), s.kh.keySize)
+	}
+	return s.fetch(key, hashKey(key, s.kh.salt))
+}
+
+func (s *Store) fetch(key []byte, h uint64) ([]byte, error) {
+	b, err := s.readBucket(bucketIndex(h, s.kh.buckets, s.kh.modulus))
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, len(key))
+	for {
+		for i := b.lowerBound(h); i < len(b.entries) && b.entries[i].hash == h; i++ {
+			e := b.entries[i]
+			if _, err := s.dat.ReadAt(buf, int64(e.offset)+6); err != nil {
+				return nil, fmt.Errorf("nudb: read key: %w", err)
+			}
+			if !bytes.Equal(buf, key) {
+				continue
+			}
+			value := make([]byte, e.size)
+			if _, err := s.dat.ReadAt(value, int64(e.offset)+6+int64(len(key))); err != nil {
+				return nil, fmt.Errorf("nudb: read value: %w", err)
+			}
+			return value, nil
+		}
+		if b.spill == 0 {
+			return nil, ErrNotFound
+		}
+		if b, err = s.readSpill(b.spill); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readBucket reads bucket n from the key file.
+func (s *Store) readBucket(n uint64) (*bucket, error) {
+	block := make([]byte, s.kh.blockSize)
+	if _, err := s.key.ReadAt(block, int64(n+1)*int64(s.kh.blockSize)); err != nil {
+		return nil, fmt.Errorf("nudb: read bucket %d: %w", n, err)
+	}
+	return decodeBucket(block, s.kh.capacity)
+}
+
+// readSpill reads the bucket a spill pointer refers to. The bucket may
+// sit at the very end of the data file, so a short read is not an error.
+func (s *Store) readSpill(off uint64) (*bucket, error) {
+	buf := make([]byte, bucketSize(s.kh.capacity))
+	n, err := s.dat.ReadAt(buf, int64(off))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("nudb: read spill: %w", err)
+	}
+	return decodeBucket(buf[:n], s.kh.capacity)
+}
+
+// Visit calls fn for every key/value pair in the data file, in insertion
+// order. It sees the records committed before it started and does not
+// block commits. Returning an error from fn stops the walk and returns
+// that error.
+// Reference: nudb/visit.hpp
+func (s *Store) Visit(fn func(key, value []byte) error) error {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return ErrClosed
+	}
+	keySize := int64(s.kh.keySize)
+	end := s.datSize
+	s.mu.RUnlock()
+
+	r := bufio.NewReaderSize(io.NewSectionReader(s.dat, DatHeaderSize, end-DatHeaderSize), 1<<20)
+	var size [6]byte
+	for {
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("%w: truncated data record", ErrCorrupt)
+		}
+		n := getUint48(size[:])
+		if n == 0 {
+			// Spill record
+			var spill [2]byte
+			if _, err := io.ReadFull(r, spill[:]); err != nil {
+				return fmt.Errorf("%w: truncated spill record", ErrCorrupt)
+			}
+			if _, err := r.Discard(int(binary.BigEndian.Uint16(spill[:]))); err != nil {
+				return fmt.Errorf("%w: truncated spill record", ErrCorrupt)
+			}
+			continue
+		}
+		if n > uint64(end) {
+			return fmt.Errorf("%w: data record size %d", ErrCorrupt, n)
+		}
+		rec := make([]byte, keySize+int64(n))
+		if _, err := io.ReadFull(r, rec); err != nil {
+			return fmt.Errorf("%w: truncated data record", ErrCorrupt)
+		}
+		if err := fn(rec[:keySize], rec[keySize:]); err != nil {
+			return err
+		}
+	}
+}
+
+// datWriter appends to the data file through a buffer, tracking the
+// file offset of the next byte written.
+type datWriter struct {
+	f       *os.File
+	w       *bufio.Writer
+	offset  int64 // offset of the next byte
+	flushed int64 // bytes at or above this offset are still buffered
+}
+
+func (w *datWriter) write(p []byte) (int64, error) {
+	off := w.offset
+	if _, err := w.w.Write(p); err != nil {
+		return 0, err
+	}
+	w.offset += int64(len(p))
+	return off, nil
+}
+
+func (w *datWriter) flush() error {
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+	w.flushed = w.offset
+	return nil
+}
+
+// Commit inserts items as one atomic batch. Keys already in the database
+// or repeated within the batch are skipped, as rippled ignores
+// key_exists on insert. Values must not be empty.
+//
+// The batch is appended to the data file, the key file buckets it is
+// about to overwrite are saved to the log file, and only then are the
+// new buckets written, with an fsync between each step. A crash at any
+// point leaves the database recoverable to its state before the commit.
+// Reference: nudb basic_store::commit
+func (s *Store) Commit(items []Item) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.closed:
+		return ErrClosed
+	case s.readOnly:
+		return ErrReadOnly
+	case s.err != nil:
+		return s.err
+	}
+
+	type pending struct {
+		hash  uint64
+		key   []byte
+		value []byte
+	}
+	batch := make([]pending, 0, len(items))
+	seen := make(map[string]struct{}, len(items))
+	for _, it := range items {
+		if len(it.Key) != int(s.kh.keySize) {
+			return fmt.Errorf("nudb: key size %d, want %d", len(it.Key), s.kh.keySize)
+		}
+		if len(it.Value) == 0 || uint64(len(it.Value)) > maxUint48 {
+			return fmt.Errorf("nudb: invalid value size %d", len(it.Value))
+		}
+		if _, dup := seen[string(it.Key)]; dup {
+			continue
+		}
+		seen[string(it.Key)] = struct{}{}
+		h := hashKey(it.Key, s.kh.salt)
+		switch _, err := s.fetch(it.Key, h); {
+		case err == nil:
+			continue
+		case !errors.Is(err, ErrNotFound):
+			return err
+		}
+		batch = append(batch, pending{hash: h, key: it.Key, value: it.Value})
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := s.commit(func(w *datWriter, insert func(entry) error) error {
+		for _, p := range batch {
+			var size [6]byte
+			putUint48(size[:], uint64(len(p.value)))
+			off, err := w.write(size[:])
+			if err == nil {
+				_, err = w.write(p.key)
+			}
+			if err == nil {
+				_, err = w.write(p.value)
+			}
+			if err != nil {
+				return err
+			}
+			if err := insert(entry{offset: uint64(off), size: uint64(len(p.value)), hash: p.hash}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		s.err = fmt.Errorf("nudb: commit failed, reopen to recover: %w", err)
+		return s.err
+	}
+	return nil
+}
+
+// commit runs one logged commit. write appends the data records and
+// calls insert for each of them.
+func (s *Store) commit(write func(*datWriter, func(entry) error) error) error {
+	kh := s.kh
+	oldKeySize := int64(kh.buckets+1) * int64(kh.blockSize)
+
+	lh := logFileHeader{
+		version:     Version,
+		uid:         kh.uid,
+		appnum:      kh.appnum,
+		keySize:     kh.keySize,
+		salt:        kh.salt,
+		pepper:      kh.pepper,
+		blockSize:   kh.blockSize,
+		keyFileSize: uint64(oldKeySize),
+		datFileSize: uint64(s.datSize),
+	}
+	if err := s.log.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := s.log.WriteAt(lh.encode(), 0); err != nil {
+		return err
+	}
+	if err := s.log.Sync(); err != nil {
+		return err
+	}
+
+	if _, err := s.dat.Seek(s.datSize, io.SeekStart); err != nil {
+		return err
+	}
+	w := &datWriter{f: s.dat, w: bufio.NewWriterSize(s.dat, 1<<20), offset: s.datSize, flushed: s.datSize}
+
+	buckets, modulus, frac := kh.buckets, kh.modulus, s.frac
+	c0 := make(map[uint64][]byte)  // original images of loaded buckets
+	c1 := make(map[uint64]*bucket) // modified buckets
+
+	load := func(n uint64) (*bucket, error) {
+		if b, ok := c1[n]; ok {
+			return b, nil
+		}
+		b, err := s.readBucket(n)
+		if err != nil {
+			return nil, err
+		}
+		c0[n] = b.bytes()
+		c1[n] = b
+		return b, nil
+	}
+
+	maybeSpill := func(b *bucket) error {
+		if !b.full() {
+			return nil
+		}
+		image := b.bytes()
+		var hdr [spillHeaderSize]byte
+		putUint48(hdr[:], 0)
+		binary.BigEndian.PutUint16(hdr[6:], uint16(len(image)))
+		off, err := w.write(hdr[:])
+		if err == nil {
+			_, err = w.write(image)
+		}
+		if err != nil {
+			return err
+		}
+		b.reset()
+		b.spill = uint64(off) + spillHeaderSize
+		return nil
+	}
+
+	// split moves the entries of b1 that now hash to n2 into b2,
+	// including those in b1's spill chain.
+	// Reference: nudb basic_store::split
+	split := func(b1, b2 *bucket, n1 uint64) error {
+		for i := 0; i < len(b1.entries); {
+			e := b1.entries[i]
+			if bucketIndex(e.hash, buckets, modulus) == n1 {
+				i++
+				continue
+			}
+			b2.insert(e)
+			b1.erase(i)
+		}
+		spill := b1.spill
+		b1.spill = 0
+		for spill != 0 {
+			if spill+uint64(bucketSize(kh.capacity)) > uint64(w.flushed) {
+				if err := w.flush(); err != nil {
+					return err
+				}
+			}
+			tmp, err := s.readSpill(spill)
+			if err != nil {
+				return err
+			}
+			for _, e := range tmp.entries {
+				dst := b2
+				if bucketIndex(e.hash, buckets, modulus) == n1 {
+					dst = b1
+				}
+				if err := maybeSpill(dst); err != nil {
+					return err
+				}
+				dst.insert(e)
+			}
+			spill = tmp.spill
+		}
+		return nil
+	}
+
+	insert := func(e entry) error {
+		frac += 65536
+		if frac >= s.thresh {
+			frac -= s.thresh
+			if buckets == modulus {
+				modulus *= 2
+			}
+			n1 := buckets - modulus/2
+			n2 := buckets
+			buckets++
+			b1, err := load(n1)
+			if err != nil {
+				return err
+			}
+			b2 := newBucket(kh.capacity)
+			c1[n2] = b2
+			if err := split(b1, b2, n1); err != nil {
+				return err
+			}
+		}
+		b, err := load(bucketIndex(e.hash, buckets, modulus))
+		if err != nil {
+			return err
+		}
+		if err := maybeSpill(b); err != nil {
+			return err
+		}
+		b.insert(e)
+		return nil
+	}
+
+	if err := write(w, insert); err != nil {
+		return err
+	}
+	if err := w.flush(); err != nil {
+		return err
+	}
+	if err := s.dat.Sync(); err != nil {
+		return err
+	}
+
+	// Save the original buckets before overwriting them
+	lw := bufio.NewWriter(io.NewOffsetWriter(s.log, LogHeaderSize))
+	for n, image := range c0 {
+		var idx [8]byte
+		binary.BigEndian.PutUint64(idx[:], n)
+		lw.Write(idx[:])
+		lw.Write(image)
+	}
+	if err := lw.Flush(); err != nil {
+		return err
+	}
+	if err := s.log.Sync(); err != nil {
+		return err
+	}
+
+	block := make([]byte, kh.blockSize)
+	for n, b := range c1 {
+		b.encode(block)
+		if _, err := s.key.WriteAt(block, int64(n+1)*int64(kh.blockSize)); err != nil {
+			return err
+		}
+	}
+	if err := s.key.Sync(); err != nil {
+		return err
+	}
+	if err := s.log.Truncate(0); err != nil {
+		return err
+	}
+	if err := s.log.Sync(); err != nil {
+		return err
+	}
+
+	s.kh.buckets, s.kh.modulus, s.frac = buckets, modulus, frac
+	s.datSize = w.offset
+	return nil
+}
+
+// Close closes the database. A writable database removes its empty log
+// file, as NuDB does on a clean close.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	err := s.closeFiles()
+	if !s.readOnly && s.err == nil {
+		if rerr := os.Remove(s.logPath); rerr != nil && err == nil {
+			err = fmt.Errorf("nudb: %w", rerr)
+		}
+	}
+	return err
+}
+
+func (s *Store) closeFiles() error {
+	var errs []error
+	for _, f := range []*os.File{s.dat, s.key, s.log} {
+		if f != nil {
+			errs = append(errs, f.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
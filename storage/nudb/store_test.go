@@ -0,0 +1,191 @@
+package nudb
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type paths struct{ dat, key, log string }
+
+func newPaths(t *testing.T) paths {
+	dir := t.TempDir()
+	return paths{
+		dat: filepath.Join(dir, "nudb.dat"),
+		key: filepath.Join(dir, "nudb.key"),
+		log: filepath.Join(dir, "nudb.log"),
+	}
+}
+
+// create makes a database with tiny buckets so that commits exercise
+// splits and spill records.
+func create(t *testing.T, p paths) *Store {
+	t.Helper()
+	if err := Create(p.dat, p.key, p.log, CreateOptions{AppNum: 1, KeySize: 32, BlockSize: 128, Salt: 42}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	s, err := Open(p.dat, p.key, p.log, false)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return s
+}
+
+func testItem(i int) Item {
+	key := sha256.Sum256([]byte(fmt.Sprintf("key %d", i)))
+	return Item{Key: key[:], Value: []byte(fmt.Sprintf("value %d", i))}
+}
+
+func testItems(from, to int) []Item {
+	items := make([]Item, 0, to-from)
+	for i := from; i < to; i++ {
+		items = append(items, testItem(i))
+	}
+	return items
+}
+
+func requireItems(t *testing.T, s *Store, from, to int) {
+	t.Helper()
+	for i := from; i < to; i++ {
+		it := testItem(i)
+		got, err := s.Fetch(it.Key)
+		if err != nil {
+			t.Fatalf("Fetch item %d: %v", i, err)
+		}
+		if string(got) != string(it.Value) {
+			t.Fatalf("item %d: got %q, want %q", i, got, it.Value)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	t.Run("CommitFetchVisit", func(t *testing.T) {
+		p := newPaths(t)
+		s := create(t, p)
+		if bucketCapacity(128) != 6 {
+			t.Fatalf("unexpected capacity %d", bucketCapacity(128))
+		}
+		for from := 0; from < 1000; from += 100 {
+			if err := s.Commit(testItems(from, from+100)); err != nil {
+				t.Fatalf("Commit: %v", err)
+			}
+		}
+		// Duplicates within and across batches are skipped
+		if err := s.Commit([]Item{testItem(5), testItem(1000), testItem(1000)}); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		requireItems(t, s, 0, 1001)
+		if _, err := s.Fetch(testItem(5000).Key); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+		if info := s.Info(); info.Buckets < 2 {
+			t.Fatalf("expected the key file to grow, got %d buckets", info.Buckets)
+		}
+		if err := s.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		if _, err := os.Stat(p.log); !os.IsNotExist(err) {
+			t.Fatalf("clean close should remove the log file")
+		}
+
+		ro, err := Open(p.dat, p.key, p.log, true)
+		if err != nil {
+			t.Fatalf("Open read-only: %v", err)
+		}
+		defer ro.Close()
+		requireItems(t, ro, 0, 1001)
+		if err := ro.Commit(testItems(2000, 2001)); !errors.Is(err, ErrReadOnly) {
+			t.Fatalf("expected ErrReadOnly, got %v", err)
+		}
+
+		var count int
+		err = ro.Visit(func(key, value []byte) error {
+			it := testItem(count)
+			if string(key) != string(it.Key) || string(value) != string(it.Value) {
+				return fmt.Errorf("record %d out of order", count)
+			}
+			count++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Visit: %v", err)
+		}
+		if count != 1001 {
+			t.Fatalf("Visit saw %d records, want 1001", count)
+		}
+	})
+
+	t.Run("CreateRefusesExisting", func(t *testing.T) {
+		p := newPaths(t)
+		create(t, p).Close()
+		if err := Create(p.dat, p.key, p.log, CreateOptions{KeySize: 32}); err == nil {
+			t.Fatalf("Create over an existing database should fail")
+		}
+	})
+
+	t.Run("Recover", func(t *testing.T) {
+		p := newPaths(t)
+		s := create(t, p)
+		if err := s.Commit(testItems(0, 50)); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		s.Close()
+
+		// Snapshot the database, then commit more on top of it
+		datImage, _ := os.ReadFile(p.dat)
+		keyImage, _ := os.ReadFile(p.key)
+		s, err := Open(p.dat, p.key, p.log, false)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		if err := s.Commit(testItems(50, 100)); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		kh := s.kh
+		s.Close()
+
+		// A log from a commit that stopped after writing some buckets
+		lh := logFileHeader{
+			version: Version, uid: kh.uid, appnum: kh.appnum, keySize: kh.keySize,
+			salt: kh.salt, pepper: kh.pepper, blockSize: kh.blockSize,
+			keyFileSize: uint64(len(keyImage)), datFileSize: uint64(len(datImage)),
+		}
+		log := lh.encode()
+		bs := int(kh.blockSize)
+		for n := 0; n < len(keyImage)/bs-1; n++ {
+			b, err := decodeBucket(keyImage[(n+1)*bs:], kh.capacity)
+			if err != nil {
+				t.Fatalf("decode bucket: %v", err)
+			}
+			log = binary.BigEndian.AppendUint64(log, uint64(n))
+			log = append(log, b.bytes()...)
+		}
+		if err := os.WriteFile(p.log, log, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Open(p.dat, p.key, p.log, true); !errors.Is(err, ErrNeedsRecovery) {
+			t.Fatalf("expected ErrNeedsRecovery, got %v", err)
+		}
+		s, err = Open(p.dat, p.key, p.log, false)
+		if err != nil {
+			t.Fatalf("Open with recovery: %v", err)
+		}
+		defer s.Close()
+		requireItems(t, s, 0, 50)
+		if _, err := s.Fetch(testItem(75).Key); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("rolled back item still present: %v", err)
+		}
+		if got, _ := os.ReadFile(p.key); string(got) != string(keyImage) {
+			t.Fatalf("key file not restored")
+		}
+		if err := s.Commit(testItems(50, 100)); err != nil {
+			t.Fatalf("Commit after recovery: %v", err)
+		}
+		requireItems(t, s, 0, 100)
+	})
+}
@@ -54,6 +54,10 @@ type TransactionInfo struct {
 	RawTxn    []byte      `json:"raw_txn"`
 	TxnMeta   []byte      `json:"txn_meta"`
 	Account   AccountID   `json:"account"`
+	TxType    string      `json:"tx_type,omitempty"`
+	// Destination is only used to index the transaction for its accounts;
+	// it is not stored with the transaction.
+	Destination AccountID `json:"destination"`
 }
 
 // AccountTxOptions contains criteria for account transaction queries
@@ -72,6 +76,53 @@ type AccountTxMarker struct {
 	TxnSeq    uint32      `json:"txn_seq"`
 }
 
+// AccountTxDirection is an account's role in a transaction indexed for it.
+type AccountTxDirection int
+
+const (
+	// AccountTxAnyDirection matches every transaction in a filter. As a
+	// stored direction it marks rows for an account the transaction only
+	// affected, which only unfiltered queries return.
+	AccountTxAnyDirection AccountTxDirection = iota
+	// AccountTxOutgoing: the account sent the transaction.
+	AccountTxOutgoing
+	// AccountTxIncoming: the account received the transaction, as its
+	// destination.
+	AccountTxIncoming
+)
+
+// AccountTxDirectionOf returns the direction of txInfo for an account it
+// is indexed for: outgoing for its sender, incoming for its destination,
+// and neither for any other account it affected.
+func AccountTxDirectionOf(accountID AccountID, txInfo *TransactionInfo) AccountTxDirection {
+	switch accountID {
+	case txInfo.Account:
+		return AccountTxOutgoing
+	case txInfo.Destination:
+		return AccountTxIncoming
+	}
+	return AccountTxAnyDirection
+}
+
+// UntypedAccountTx is an account transaction row indexed before its
+// transaction type and direction were recorded, which are backfilled from
+// the raw transaction.
+type UntypedAccountTx struct {
+	Hash      Hash
+	Account   AccountID
+	RawTxn    []byte
+	TxType    string
+	Direction AccountTxDirection
+}
+
+// AccountTxFilter narrows account transaction pages. An empty TxType
+// matches every transaction type. Markers stay valid as long as the same
+// filter is passed with them.
+type AccountTxFilter struct {
+	TxType    string             `json:"tx_type,omitempty"`
+	Direction AccountTxDirection `json:"direction,omitempty"`
+}
+
 // AccountTxPageOptions contains criteria for paginated account transaction queries
 type AccountTxPageOptions struct {
 	Account   AccountID        `json:"account"`
@@ -80,6 +131,7 @@ type AccountTxPageOptions struct {
 	Marker    *AccountTxMarker `json:"marker,omitempty"`
 	Limit     uint32           `json:"limit"`
 	Admin     bool             `json:"admin"`
+	Filter    AccountTxFilter  `json:"filter"`
 }
 
 // AccountTxResult contains the result of an account transaction query
@@ -131,6 +183,7 @@ type TransactionRepository interface {
 	GetTransactionCount(ctx context.Context) (int64, error)
 	GetTransaction(ctx context.Context, hash Hash, ledgerRange *LedgerRange) (*TransactionInfo, TxSearchResult, error)
 	GetTxHistory(ctx context.Context, startIndex LedgerIndex, limit int) ([]TransactionInfo, error)
+	GetTransactionsByLedgerRange(ctx context.Context, minSeq, maxSeq LedgerIndex, limit int) ([]TransactionInfo, error)
 	SaveTransaction(ctx context.Context, txInfo *TransactionInfo) error
 	DeleteTransactionsByLedgerSeq(ctx context.Context, ledgerSeq LedgerIndex) error
 	DeleteTransactionsBeforeLedgerSeq(ctx context.Context, ledgerSeq LedgerIndex) error
@@ -148,6 +201,14 @@ type AccountTransactionRepository interface {
 	GetNewestAccountTxsPage(ctx context.Context, options AccountTxPageOptions) (*AccountTxResult, error)
 	SaveAccountTransaction(ctx context.Context, accountID AccountID, txInfo *TransactionInfo) error
 	DeleteAccountTransactionsBeforeLedgerSeq(ctx context.Context, ledgerSeq LedgerIndex) error
+
+	// GetUntypedAccountTransactions returns up to limit rows indexed before
+	// transaction types were recorded, in (hash, account) order after
+	// marker, with their raw transactions.
+	GetUntypedAccountTransactions(ctx context.Context, marker *UntypedAccountTx, limit uint32) ([]UntypedAccountTx, error)
+	// SetAccountTransactionTypes records the TxType and Direction of rows
+	// returned by GetUntypedAccountTransactions.
+	SetAccountTransactionTypes(ctx context.Context, rows []UntypedAccountTx) error
 }
 
 // SystemRepository handles system-level database operations
@@ -177,6 +238,9 @@ type RepositoryManager interface {
 	Transaction() TransactionRepository
 	AccountTransaction() AccountTransactionRepository
 	Validation() ValidationRepository
+	PeerReservation() PeerReservationRepository
+	NFT() NFTRepository
+	MPT() MPTRepository
 	System() SystemRepository
 
 	// Connection management
@@ -0,0 +1,224 @@
+package relationaldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SchemaVersionsTable records the migrations applied to a database.
+const SchemaVersionsTable = "schema_versions"
+
+// Dialect selects the SQL syntax differences a Migrator has to handle.
+type Dialect int
+
+const (
+	DialectSQLite Dialect = iota
+	DialectPostgres
+)
+
+// placeholder returns the n-th (1-based) bind parameter.
+func (d Dialect) placeholder(n int) string {
+	if d == DialectPostgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// Migration is one schema change. Its statements run in a single
+// transaction together with the row recording it in SchemaVersionsTable.
+type Migration struct {
+	Version     int
+	Description string
+	Statements  []string
+}
+
+// AppliedMigration is a row of SchemaVersionsTable.
+type AppliedMigration struct {
+	Version     int       `json:"version"`
+	Description string    `json:"description"`
+	AppliedAt   time.Time `json:"applied_at"`
+}
+
+// SchemaStatus reports the schema version of one database.
+type SchemaStatus struct {
+	Schema  string             `json:"schema"`
+	Current int                `json:"current"`
+	Latest  int                `json:"latest"`
+	Applied []AppliedMigration `json:"applied"`
+	Pending []Migration        `json:"pending"`
+}
+
+// IsNewer returns true if the database was migrated by a newer build.
+func (s *SchemaStatus) IsNewer() bool {
+	return s.Current > s.Latest
+}
+
+// Migrator brings one database up to the latest of an ordered list of
+// migrations. Migrations are only ever applied upwards; a database whose
+// version is above the latest known migration is refused, since this
+// build cannot know what the newer migrations changed.
+type Migrator struct {
+	db         *sql.DB
+	schema     string
+	dialect    Dialect
+	migrations []Migration
+}
+
+// NewMigrator returns a Migrator for db. schema names the database in
+// status reports and errors. Versions must start at 1 and increase by one.
+func NewMigrator(db *sql.DB, schema string, dialect Dialect, migrations []Migration) (*Migrator, error) {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, NewSchemaError("new_migrator",
+				fmt.Sprintf("%s migration %d has version %d, want %d", schema, i, m.Version, i+1), nil)
+		}
+		if len(m.Statements) == 0 {
+			return nil, NewSchemaError("new_migrator",
+				fmt.Sprintf("%s migration %d has no statements", schema, m.Version), nil)
+		}
+	}
+	return &Migrator{db: db, schema: schema, dialect: dialect, migrations: migrations}, nil
+}
+
+// Schema returns the name given to NewMigrator.
+func (m *Migrator) Schema() string {
+	return m.schema
+}
+
+// Latest returns the version of the last known migration.
+func (m *Migrator) Latest() int {
+	return len(m.migrations)
+}
+
+// Status reads the applied migrations and lists the pending ones.
+// It creates SchemaVersionsTable if the database has none yet.
+func (m *Migrator) Status(ctx context.Context) (*SchemaStatus, error) {
+	if err := m.ensureVersionsTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx,
+		`SELECT version, description, applied_at FROM `+SchemaVersionsTable+` ORDER BY version`)
+	if err != nil {
+		return nil, NewSchemaError("schema_status", "failed to read "+m.schema+" schema versions", err)
+	}
+	defer rows.Close()
+
+	status := &SchemaStatus{Schema: m.schema, Latest: m.Latest()}
+	for rows.Next() {
+		var applied AppliedMigration
+		var appliedAt int64
+		if err := rows.Scan(&applied.Version, &applied.Description, &appliedAt); err != nil {
+			return nil, NewSchemaError("schema_status", "failed to read "+m.schema+" schema versions", err)
+		}
+		applied.AppliedAt = time.Unix(appliedAt, 0).UTC()
+		status.Applied = append(status.Applied, applied)
+		if applied.Version > status.Current {
+			status.Current = applied.Version
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, NewSchemaError("schema_status", "failed to read "+m.schema+" schema versions", err)
+	}
+
+	if status.Current < len(m.migrations) {
+		status.Pending = m.migrations[status.Current:]
+	}
+	return status, nil
+}
+
+// Check returns an error wrapping ErrSchemaVersion if the database is
+// newer than this build.
+func (m *Migrator) Check(ctx context.Context) (*SchemaStatus, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if status.IsNewer() {
+		return status, NewSchemaError("check_schema",
+			fmt.Sprintf("%s database is newer than this build", m.schema),
+			fmt.Errorf("%w: schema version %d, latest known %d", ErrSchemaVersion, status.Current, status.Latest))
+	}
+	return status, nil
+}
+
+// Migrate applies the pending migrations in order and returns them.
+// With dryRun set nothing is changed and the migrations that would be
+// applied are returned. A failing migration is rolled back and stops the
+// run; the migrations before it stay applied.
+func (m *Migrator) Migrate(ctx context.Context, dryRun bool) ([]Migration, error) {
+	status, err := m.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return status.Pending, nil
+	}
+
+	for i, migration := range status.Pending {
+		if err := m.apply(ctx, migration); err != nil {
+			return status.Pending[:i], err
+		}
+	}
+	return status.Pending, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	fail := func(err error) error {
+		return NewSchemaError("migrate",
+			fmt.Sprintf("%s migration %d (%s) failed", m.schema, migration.Version, migration.Description),
+			fmt.Errorf("%w: %v", ErrMigrationFailed, err))
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fail(err)
+	}
+	for _, stmt := range migration.Statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			tx.Rollback()
+			return fail(err)
+		}
+	}
+	insert := fmt.Sprintf(`INSERT INTO %s (version, description, applied_at) VALUES (%s, %s, %s)`,
+		SchemaVersionsTable, m.dialect.placeholder(1), m.dialect.placeholder(2), m.dialect.placeholder(3))
+	if _, err := tx.ExecContext(ctx, insert, migration.Version, migration.Description, time.Now().Unix()); err != nil {
+		tx.Rollback()
+		return fail(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fail(err)
+	}
+	return nil
+}
+
+func (m *Migrator) ensureVersionsTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+SchemaVersionsTable+` (
+		version     INTEGER PRIMARY KEY,
+		description TEXT NOT NULL,
+		applied_at  BIGINT NOT NULL
+	)`)
+	if err != nil {
+		return NewSchemaError("schema_status", "failed to create "+m.schema+" schema versions table", err)
+	}
+	return nil
+}
+
+// SchemaManager is implemented by repository managers with versioned
+// schemas. Open checks the schema before anything else and, unless
+// automatic migration is disabled, applies pending migrations.
+type SchemaManager interface {
+	// SetAutoMigrate controls whether Open applies pending migrations.
+	// Must be called before Open. Enabled by default.
+	SetAutoMigrate(enabled bool)
+
+	// SchemaStatus reports the schema version of every database.
+	SchemaStatus(ctx context.Context) ([]*SchemaStatus, error)
+
+	// Migrate applies pending migrations to every database and returns
+	// them per schema. With dryRun set nothing is changed.
+	Migrate(ctx context.Context, dryRun bool) (map[string][]Migration, error)
+}
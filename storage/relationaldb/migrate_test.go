@@ -0,0 +1,202 @@
+package relationaldb_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+	_ "modernc.org/sqlite"
+)
+
+var testMigrations = []relationaldb.Migration{
+	{Version: 1, Description: "items", Statements: []string{
+		`CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT NOT NULL)`,
+	}},
+	{Version: 2, Description: "items index", Statements: []string{
+		`CREATE INDEX idx_items_name ON items(name)`,
+	}},
+}
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newTestMigrator(t *testing.T, db *sql.DB, dialect relationaldb.Dialect, migrations []relationaldb.Migration) *relationaldb.Migrator {
+	t.Helper()
+	m, err := relationaldb.NewMigrator(db, "test", dialect, migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = ?`, name).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	return count > 0
+}
+
+func TestMigrator_Apply(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	m := newTestMigrator(t, db, relationaldb.DialectSQLite, testMigrations)
+
+	status, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Current != 0 || status.Latest != 2 || len(status.Pending) != 2 {
+		t.Fatalf("unexpected status of a new database: %+v", status)
+	}
+
+	applied, err := m.Migrate(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 2 || !tableExists(t, db, "items") || !tableExists(t, db, "idx_items_name") {
+		t.Fatalf("expected both migrations applied, got %v", applied)
+	}
+
+	status, err = m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Current != 2 || len(status.Applied) != 2 || len(status.Pending) != 0 {
+		t.Errorf("unexpected status after migrating: %+v", status)
+	}
+	if status.Applied[1].Description != "items index" || status.Applied[1].AppliedAt.IsZero() {
+		t.Errorf("unexpected applied migration: %+v", status.Applied[1])
+	}
+
+	// Running again is a no-op.
+	applied, err = m.Migrate(ctx, false)
+	if err != nil || len(applied) != 0 {
+		t.Errorf("expected nothing to apply, got %v %v", applied, err)
+	}
+}
+
+func TestMigrator_DryRun(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	m := newTestMigrator(t, db, relationaldb.DialectSQLite, testMigrations)
+
+	pending, err := m.Migrate(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 {
+		t.Errorf("expected 2 pending migrations, got %v", pending)
+	}
+	if tableExists(t, db, "items") {
+		t.Error("dry run changed the database")
+	}
+}
+
+func TestMigrator_Upgrade(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	if _, err := newTestMigrator(t, db, relationaldb.DialectSQLite, testMigrations[:1]).Migrate(ctx, false); err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := newTestMigrator(t, db, relationaldb.DialectSQLite, testMigrations).Migrate(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 1 || applied[0].Version != 2 {
+		t.Errorf("expected only migration 2 applied, got %v", applied)
+	}
+}
+
+func TestMigrator_RefusesNewerSchema(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	if _, err := newTestMigrator(t, db, relationaldb.DialectSQLite, testMigrations).Migrate(ctx, false); err != nil {
+		t.Fatal(err)
+	}
+
+	older := newTestMigrator(t, db, relationaldb.DialectSQLite, testMigrations[:1])
+	status, err := older.Check(ctx)
+	if !errors.Is(err, relationaldb.ErrSchemaVersion) {
+		t.Fatalf("expected ErrSchemaVersion, got %v", err)
+	}
+	if !status.IsNewer() || status.Current != 2 || status.Latest != 1 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	if _, err := older.Migrate(ctx, true); !errors.Is(err, relationaldb.ErrSchemaVersion) {
+		t.Errorf("expected dry run to be refused too, got %v", err)
+	}
+}
+
+func TestMigrator_FailedMigrationRollsBack(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	migrations := append(testMigrations[:1:1], relationaldb.Migration{
+		Version:     2,
+		Description: "broken",
+		Statements: []string{
+			`CREATE TABLE other (id INTEGER PRIMARY KEY)`,
+			`CREATE INDEX idx_missing ON missing(id)`,
+		},
+	})
+
+	applied, err := newTestMigrator(t, db, relationaldb.DialectSQLite, migrations).Migrate(ctx, false)
+	if !errors.Is(err, relationaldb.ErrMigrationFailed) {
+		t.Fatalf("expected ErrMigrationFailed, got %v", err)
+	}
+	if len(applied) != 1 {
+		t.Errorf("expected migration 1 to stay applied, got %v", applied)
+	}
+	if tableExists(t, db, "other") {
+		t.Error("failed migration was not rolled back")
+	}
+
+	status, err := newTestMigrator(t, db, relationaldb.DialectSQLite, migrations).Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Current != 1 || len(status.Pending) != 1 {
+		t.Errorf("unexpected status after failure: %+v", status)
+	}
+}
+
+// SQLite accepts $n parameters, so it stands in for PostgreSQL here.
+func TestMigrator_PostgresDialect(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	m := newTestMigrator(t, db, relationaldb.DialectPostgres, testMigrations)
+
+	if _, err := m.Migrate(ctx, false); err != nil {
+		t.Fatal(err)
+	}
+	status, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Current != 2 || status.Applied[0].Description != "items" {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+func TestNewMigrator_Validates(t *testing.T) {
+	gap := []relationaldb.Migration{testMigrations[1]}
+	if _, err := relationaldb.NewMigrator(nil, "test", relationaldb.DialectSQLite, gap); err == nil {
+		t.Error("expected an error for migrations not starting at version 1")
+	}
+	empty := []relationaldb.Migration{{Version: 1, Description: "empty"}}
+	if _, err := relationaldb.NewMigrator(nil, "test", relationaldb.DialectSQLite, empty); err == nil {
+		t.Error("expected an error for a migration without statements")
+	}
+}
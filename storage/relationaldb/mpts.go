@@ -0,0 +1,38 @@
+package relationaldb
+
+import "context"
+
+// MPTHolder records that an account created an MPToken for an issuance.
+// Rows are never removed: whether the holder still has the MPToken at a
+// given ledger is read from that ledger's state. Modelled on Clio's
+// mp_token_holders table.
+type MPTHolder struct {
+	MPTIssuanceID [24]byte
+	Holder        AccountID
+}
+
+// MPTHoldersOptions contains criteria for listing the holders of an
+// issuance. Holders are returned in account order, starting after Marker.
+type MPTHoldersOptions struct {
+	MPTIssuanceID [24]byte
+	Marker        *AccountID
+	Limit         uint32
+}
+
+// MPTHoldersResult contains a page of an issuance's holders. Marker is set
+// when more holders follow.
+type MPTHoldersResult struct {
+	Holders []AccountID
+	Marker  *AccountID
+}
+
+// MPTRepository indexes the holders of MPT issuances from transaction
+// metadata, backing the mpt_holders method.
+type MPTRepository interface {
+	// SaveMPTHolders records holders. Saving a holder twice is a no-op.
+	SaveMPTHolders(ctx context.Context, holders []MPTHolder) error
+
+	// GetMPTHolders returns a page of the accounts that ever held an
+	// MPToken of an issuance.
+	GetMPTHolders(ctx context.Context, options MPTHoldersOptions) (*MPTHoldersResult, error)
+}
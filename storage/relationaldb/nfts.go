@@ -0,0 +1,81 @@
+package relationaldb
+
+import "context"
+
+// NFTRecord is the state of an NFT as of a ledger: one row per ledger in
+// which a transaction changed its owner, burned it or modified its URI.
+// Flags, transfer fee, issuer, taxon and serial are encoded in the
+// NFTokenID itself. Modelled on Clio's nf_tokens table.
+type NFTRecord struct {
+	NFTokenID Hash
+	LedgerSeq LedgerIndex
+	Owner     AccountID
+	IsBurned  bool
+	URI       []byte
+}
+
+// NFTTransaction links an NFT to a transaction that minted, transferred,
+// burned or modified it, or created or cancelled an offer for it.
+type NFTTransaction struct {
+	NFTokenID Hash
+	LedgerSeq LedgerIndex
+	TxnSeq    uint32
+	Hash      Hash
+}
+
+// NFTHistoryOptions contains criteria for paginated NFT history queries.
+// Marker works as for account transactions.
+type NFTHistoryOptions struct {
+	NFTokenID Hash
+	MinLedger LedgerIndex
+	MaxLedger LedgerIndex
+	Marker    *AccountTxMarker
+	Limit     uint32
+	Forward   bool
+}
+
+// NFTHistoryResult contains a page of an NFT's transactions.
+type NFTHistoryResult struct {
+	Transactions []TransactionInfo
+	Marker       *AccountTxMarker
+}
+
+// NFTsByIssuerOptions contains criteria for listing an issuer's NFTs.
+// NFTs are returned in NFTokenID order, starting after Marker.
+type NFTsByIssuerOptions struct {
+	Issuer    AccountID
+	Taxon     *uint32
+	LedgerSeq LedgerIndex
+	Marker    *Hash
+	Limit     uint32
+}
+
+// NFTsByIssuerResult contains a page of an issuer's NFTs. Marker is set
+// when more NFTs follow.
+type NFTsByIssuerResult struct {
+	NFTs   []NFTRecord
+	Marker *Hash
+}
+
+// NFTRepository indexes NFTs by ID and issuer from transaction metadata,
+// backing Clio's nft_info, nft_history and nfts_by_issuer methods.
+type NFTRepository interface {
+	// SaveNFTs records NFT states. A state for the same NFT and ledger
+	// replaces the stored one. taxons gives the unciphered taxon of each
+	// NFT, indexed like nfts, for the issuer index.
+	SaveNFTs(ctx context.Context, nfts []NFTRecord, taxons []uint32) error
+
+	// SaveNFTTransactions links NFTs to transactions. Saving a link twice
+	// is a no-op.
+	SaveNFTTransactions(ctx context.Context, txs []NFTTransaction) error
+
+	// GetNFT returns the state of an NFT as of ledgerSeq, or nil if it
+	// had not been minted by then.
+	GetNFT(ctx context.Context, nftID Hash, ledgerSeq LedgerIndex) (*NFTRecord, error)
+
+	// GetNFTHistory returns a page of the transactions of an NFT.
+	GetNFTHistory(ctx context.Context, options NFTHistoryOptions) (*NFTHistoryResult, error)
+
+	// GetNFTsByIssuer returns a page of an issuer's NFTs as of a ledger.
+	GetNFTsByIssuer(ctx context.Context, options NFTsByIssuerOptions) (*NFTsByIssuerResult, error)
+}
@@ -0,0 +1,27 @@
+package relationaldb
+
+import "context"
+
+// PeerReservationRecord is one row of the peer-reservation table. Mirrors
+// rippled's PeerReservations table (WalletDB, DBInit.h): the node's
+// base58 public key plus an optional operator-supplied description.
+type PeerReservationRecord struct {
+	PublicKey   string // base58 node public key ("n...")
+	Description string
+}
+
+// PeerReservationRepository persists the admin-managed peer reservation
+// list so peer_reservations_add survives a restart, matching rippled's
+// PeerReservationTable::load / insert_or_assign / erase.
+type PeerReservationRepository interface {
+	// List returns every stored reservation. Order is unspecified.
+	List(ctx context.Context) ([]*PeerReservationRecord, error)
+
+	// Upsert inserts the reservation or replaces the description of an
+	// existing one keyed by PublicKey.
+	Upsert(ctx context.Context, r *PeerReservationRecord) error
+
+	// Delete removes the reservation for publicKey. Deleting a key that
+	// is not stored is not an error.
+	Delete(ctx context.Context, publicKey string) error
+}
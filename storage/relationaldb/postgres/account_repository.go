@@ -215,6 +215,17 @@ func (r *AccountTransactionRepository) GetOldestAccountTxsPage(ctx context.Conte
 		args = append(args, options.MaxLedger)
 	}
 
+	if options.Filter.TxType != "" {
+		argCount++
+		query += fmt.Sprintf(" AND at.tx_type = $%d", argCount)
+		args = append(args, options.Filter.TxType)
+	}
+	if options.Filter.Direction != relationaldb.AccountTxAnyDirection {
+		argCount++
+		query += fmt.Sprintf(" AND at.direction = $%d", argCount)
+		args = append(args, int(options.Filter.Direction))
+	}
+
 	// Add marker-based pagination
 	if options.Marker != nil {
 		argCount++
@@ -306,6 +317,17 @@ func (r *AccountTransactionRepository) GetNewestAccountTxsPage(ctx context.Conte
 		args = append(args, options.MaxLedger)
 	}
 
+	if options.Filter.TxType != "" {
+		argCount++
+		query += fmt.Sprintf(" AND at.tx_type = $%d", argCount)
+		args = append(args, options.Filter.TxType)
+	}
+	if options.Filter.Direction != relationaldb.AccountTxAnyDirection {
+		argCount++
+		query += fmt.Sprintf(" AND at.direction = $%d", argCount)
+		args = append(args, int(options.Filter.Direction))
+	}
+
 	// Add marker-based pagination (reverse logic for DESC order)
 	if options.Marker != nil {
 		argCount++
@@ -376,14 +398,17 @@ func (r *AccountTransactionRepository) GetNewestAccountTxsPage(ctx context.Conte
 }
 
 func (r *AccountTransactionRepository) SaveAccountTransaction(ctx context.Context, accountID relationaldb.AccountID, txInfo *relationaldb.TransactionInfo) error {
-	query := `INSERT INTO account_transactions (trans_id, account, ledger_seq, txn_seq)
-			  VALUES ($1, $2, $3, $4)
+	query := `INSERT INTO account_transactions (trans_id, account, ledger_seq, txn_seq, tx_type, direction)
+			  VALUES ($1, $2, $3, $4, $5, $6)
 			  ON CONFLICT (trans_id, account) DO UPDATE SET
 			  ledger_seq = EXCLUDED.ledger_seq,
-			  txn_seq = EXCLUDED.txn_seq`
+			  txn_seq = EXCLUDED.txn_seq,
+			  tx_type = EXCLUDED.tx_type,
+			  direction = EXCLUDED.direction`
 
 	_, err := r.getExecutor().ExecContext(ctx, query,
-		txInfo.Hash[:], accountID.String(), txInfo.LedgerSeq, txInfo.TxnSeq)
+		txInfo.Hash[:], accountID.String(), txInfo.LedgerSeq, txInfo.TxnSeq,
+		txInfo.TxType, int(relationaldb.AccountTxDirectionOf(accountID, txInfo)))
 
 	if err != nil {
 		return relationaldb.NewQueryError("save_account_transaction", "failed to save account transaction", err)
@@ -400,3 +425,53 @@ func (r *AccountTransactionRepository) DeleteAccountTransactionsBeforeLedgerSeq(
 
 	return nil
 }
+
+func (r *AccountTransactionRepository) GetUntypedAccountTransactions(ctx context.Context, marker *relationaldb.UntypedAccountTx, limit uint32) ([]relationaldb.UntypedAccountTx, error) {
+	query := `SELECT at.trans_id, at.account, t.raw_txn
+			  FROM account_transactions at
+			  INNER JOIN transactions t ON t.trans_id = at.trans_id
+			  WHERE at.tx_type = ''`
+	var args []interface{}
+	if marker != nil {
+		query += ` AND (at.trans_id > $1 OR (at.trans_id = $2 AND at.account > $3))`
+		args = append(args, marker.Hash[:], marker.Hash[:], marker.Account.String())
+	}
+	query += fmt.Sprintf(" ORDER BY at.trans_id, at.account LIMIT %d", limit)
+
+	rows, err := r.getExecutor().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, relationaldb.NewQueryError("get_untyped_account_transactions", "failed to query untyped account transactions", err)
+	}
+	defer rows.Close()
+
+	var result []relationaldb.UntypedAccountTx
+	for rows.Next() {
+		var row relationaldb.UntypedAccountTx
+		var hash []byte
+		var account string
+		if err := rows.Scan(&hash, &account, &row.RawTxn); err != nil {
+			return nil, relationaldb.NewQueryError("get_untyped_account_transactions", "failed to scan row", err)
+		}
+		copy(row.Hash[:], hash)
+		if row.Account, err = relationaldb.ParseAccountID(account); err != nil {
+			return nil, relationaldb.NewQueryError("get_untyped_account_transactions", "invalid account", err)
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, relationaldb.NewQueryError("get_untyped_account_transactions", "failed to iterate rows", err)
+	}
+	return result, nil
+}
+
+func (r *AccountTransactionRepository) SetAccountTransactionTypes(ctx context.Context, rows []relationaldb.UntypedAccountTx) error {
+	query := `UPDATE account_transactions SET tx_type = $1, direction = $2
+			  WHERE trans_id = $3 AND account = $4`
+	for _, row := range rows {
+		_, err := r.getExecutor().ExecContext(ctx, query, row.TxType, int(row.Direction), row.Hash[:], row.Account.String())
+		if err != nil {
+			return relationaldb.NewQueryError("set_account_transaction_types", "failed to update account transaction", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import "github.com/LeJamon/goXRPLd/storage/relationaldb"
+
+// schemaName identifies the PostgreSQL database in schema status reports.
+const schemaName = "postgres"
+
+// migrations version the PostgreSQL schema. Append new migrations to the
+// end; never edit or reorder one that has shipped, since databases record
+// only the version they reached.
+//
+// Version 1 is the schema that predates versioning, based on rippled's
+// SQLite tables but adapted for PostgreSQL. Its statements use IF NOT
+// EXISTS, so databases created before versioning adopt it unchanged.
+var migrations = []relationaldb.Migration{
+	{
+		Version:     1,
+		Description: "ledger, transaction, validation and peer reservation tables",
+		Statements: []string{
+			// Ledgers table - matches rippled's Ledgers table structure
+			`CREATE TABLE IF NOT EXISTS ledgers (
+				ledger_hash BYTEA PRIMARY KEY,
+				ledger_seq BIGINT UNIQUE NOT NULL,
+				prev_hash BYTEA NOT NULL,
+				total_coins DECIMAL(20,0) NOT NULL,
+				closing_time BIGINT NOT NULL,
+				prev_closing_time BIGINT NOT NULL,
+				close_time_res INTEGER NOT NULL,
+				close_flags INTEGER NOT NULL,
+				account_set_hash BYTEA NOT NULL,
+				trans_set_hash BYTEA NOT NULL,
+				created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+			)`,
+
+			// Transactions table - matches rippled's Transactions table
+			`CREATE TABLE IF NOT EXISTS transactions (
+				trans_id BYTEA PRIMARY KEY,
+				ledger_seq BIGINT NOT NULL,
+				status VARCHAR(50) NOT NULL,
+				raw_txn BYTEA NOT NULL,
+				txn_meta BYTEA,
+				created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+			)`,
+
+			// AccountTransactions table - matches rippled's AccountTransactions table
+			`CREATE TABLE IF NOT EXISTS account_transactions (
+				trans_id BYTEA NOT NULL,
+				account VARCHAR(34) NOT NULL,
+				ledger_seq BIGINT NOT NULL,
+				txn_seq INTEGER NOT NULL,
+				created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+				PRIMARY KEY (trans_id, account)
+			)`,
+
+			// Validations table — rippled's pre-May-2019 historical schema,
+			// augmented with seen_time + flags for receive-side forensics.
+			`CREATE TABLE IF NOT EXISTS validations (
+				ledger_seq   BIGINT NOT NULL,
+				initial_seq  BIGINT NOT NULL,
+				ledger_hash  BYTEA NOT NULL,
+				node_pubkey  BYTEA NOT NULL,
+				sign_time    BIGINT NOT NULL,
+				seen_time    BIGINT NOT NULL,
+				flags        BIGINT NOT NULL,
+				raw          BYTEA NOT NULL,
+				created_at   TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+				PRIMARY KEY (ledger_hash, node_pubkey)
+			)`,
+
+			// PeerReservations table — rippled keeps this in its wallet DB.
+			`CREATE TABLE IF NOT EXISTS peer_reservations (
+				public_key  VARCHAR(64) PRIMARY KEY,
+				description TEXT NOT NULL DEFAULT ''
+			)`,
+
+			// Indexes matching rippled's performance optimizations
+			`CREATE INDEX IF NOT EXISTS idx_ledgers_seq ON ledgers(ledger_seq)`,
+			`CREATE INDEX IF NOT EXISTS idx_ledgers_closing_time ON ledgers(closing_time)`,
+			`CREATE INDEX IF NOT EXISTS idx_transactions_ledger_seq ON transactions(ledger_seq)`,
+			`CREATE INDEX IF NOT EXISTS idx_account_transactions_account ON account_transactions(account)`,
+			`CREATE INDEX IF NOT EXISTS idx_account_transactions_ledger_seq ON account_transactions(ledger_seq)`,
+			`CREATE INDEX IF NOT EXISTS idx_account_transactions_account_ledger_txn ON account_transactions(account, ledger_seq, txn_seq)`,
+			`CREATE INDEX IF NOT EXISTS idx_validations_seq       ON validations(ledger_seq)`,
+			`CREATE INDEX IF NOT EXISTS idx_validations_node      ON validations(node_pubkey, ledger_seq)`,
+			`CREATE INDEX IF NOT EXISTS idx_validations_sign_time ON validations(sign_time)`,
+			`CREATE INDEX IF NOT EXISTS idx_validations_initial   ON validations(initial_seq, ledger_seq)`,
+		},
+	},
+	{
+		Version:     2,
+		Description: "nfts, issuer_nfts and nft_transactions tables",
+		Statements: []string{
+			// NFT index modelled on Clio's nf_tokens, issuer_nf_tokens_v2
+			// and nf_token_transactions tables.
+			`CREATE TABLE nfts (
+				nft_id      BYTEA NOT NULL,
+				ledger_seq  BIGINT NOT NULL,
+				owner       BYTEA NOT NULL,
+				is_burned   BOOLEAN NOT NULL,
+				uri         BYTEA,
+				PRIMARY KEY (nft_id, ledger_seq)
+			)`,
+			`CREATE TABLE issuer_nfts (
+				issuer  BYTEA NOT NULL,
+				taxon   BIGINT NOT NULL,
+				nft_id  BYTEA NOT NULL,
+				PRIMARY KEY (issuer, taxon, nft_id)
+			)`,
+			`CREATE INDEX idx_issuer_nfts_id ON issuer_nfts(issuer, nft_id)`,
+			`CREATE TABLE nft_transactions (
+				nft_id      BYTEA NOT NULL,
+				ledger_seq  BIGINT NOT NULL,
+				txn_seq     INTEGER NOT NULL,
+				trans_id    BYTEA NOT NULL,
+				PRIMARY KEY (nft_id, ledger_seq, txn_seq)
+			)`,
+		},
+	},
+	{
+		Version:     3,
+		Description: "mpt_holders table",
+		Statements: []string{
+			// Modelled on Clio's mp_token_holders table.
+			`CREATE TABLE mpt_holders (
+				mpt_issuance_id  BYTEA NOT NULL,
+				holder           BYTEA NOT NULL,
+				PRIMARY KEY (mpt_issuance_id, holder)
+			)`,
+		},
+	},
+	{
+		Version:     4,
+		Description: "tx_type and direction columns on account_transactions",
+		Statements: []string{
+			// Rows indexed before this version start with an empty tx_type
+			// and direction 0; the ledger service backfills both from
+			// raw_txn at startup.
+			`ALTER TABLE account_transactions ADD COLUMN tx_type TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE account_transactions ADD COLUMN direction SMALLINT NOT NULL DEFAULT 0`,
+			`CREATE INDEX idx_acct_tx_type ON account_transactions(account, tx_type, ledger_seq, txn_seq)`,
+		},
+	},
+}
@@ -0,0 +1,13 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+func TestMigrationsValid(t *testing.T) {
+	if _, err := relationaldb.NewMigrator(nil, schemaName, relationaldb.DialectPostgres, migrations); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+// MPTRepository implements relationaldb.MPTRepository for PostgreSQL,
+// mirroring the SQLite backend.
+type MPTRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+// Compile-time interface check.
+var _ relationaldb.MPTRepository = (*MPTRepository)(nil)
+
+func NewMPTRepository(db *sql.DB) *MPTRepository {
+	return &MPTRepository{db: db}
+}
+
+func NewMPTRepositoryWithTx(tx *sql.Tx) *MPTRepository {
+	return &MPTRepository{tx: tx}
+}
+
+func (r *MPTRepository) getExecutor() executor {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+func (r *MPTRepository) SaveMPTHolders(ctx context.Context, holders []relationaldb.MPTHolder) error {
+	exec := r.getExecutor()
+	for _, h := range holders {
+		_, err := exec.ExecContext(ctx, `
+			INSERT INTO mpt_holders (mpt_issuance_id, holder) VALUES ($1, $2)
+			ON CONFLICT (mpt_issuance_id, holder) DO NOTHING`,
+			h.MPTIssuanceID[:], h.Holder[:])
+		if err != nil {
+			return relationaldb.NewQueryError("save_mpt_holders", "failed to insert MPT holder", err)
+		}
+	}
+	return nil
+}
+
+func (r *MPTRepository) GetMPTHolders(ctx context.Context, options relationaldb.MPTHoldersOptions) (*relationaldb.MPTHoldersResult, error) {
+	query := `SELECT holder FROM mpt_holders WHERE mpt_issuance_id = $1`
+	args := []interface{}{options.MPTIssuanceID[:]}
+	argCount := 1
+
+	if options.Marker != nil {
+		argCount++
+		query += fmt.Sprintf(" AND holder > $%d", argCount)
+		args = append(args, options.Marker[:])
+	}
+	argCount++
+	query += fmt.Sprintf(" ORDER BY holder LIMIT $%d", argCount)
+	args = append(args, options.Limit+1)
+
+	rows, err := r.getExecutor().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, relationaldb.NewQueryError("get_mpt_holders", "failed to query MPT holders", err)
+	}
+	defer rows.Close()
+
+	var holders []relationaldb.AccountID
+	for rows.Next() {
+		var holderBytes []byte
+		if err := rows.Scan(&holderBytes); err != nil {
+			return nil, relationaldb.NewQueryError("get_mpt_holders", "failed to scan row", err)
+		}
+		var holder relationaldb.AccountID
+		copy(holder[:], holderBytes)
+		holders = append(holders, holder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, relationaldb.NewQueryError("get_mpt_holders", "error iterating rows", err)
+	}
+
+	result := &relationaldb.MPTHoldersResult{}
+	if len(holders) > int(options.Limit) {
+		holders = holders[:options.Limit]
+		marker := holders[len(holders)-1]
+		result.Marker = &marker
+	}
+	result.Holders = holders
+	return result, nil
+}
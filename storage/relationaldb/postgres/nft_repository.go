@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+// NFTRepository implements relationaldb.NFTRepository for PostgreSQL,
+// mirroring the SQLite backend.
+type NFTRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+// Compile-time interface check.
+var _ relationaldb.NFTRepository = (*NFTRepository)(nil)
+
+func NewNFTRepository(db *sql.DB) *NFTRepository {
+	return &NFTRepository{db: db}
+}
+
+func NewNFTRepositoryWithTx(tx *sql.Tx) *NFTRepository {
+	return &NFTRepository{tx: tx}
+}
+
+func (r *NFTRepository) getExecutor() executor {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// inTx runs fn in the repository's transaction, or in a new one.
+func (r *NFTRepository) inTx(ctx context.Context, opName string, fn func(executor) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return relationaldb.NewTransactionError(opName, "failed to begin transaction", err)
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return relationaldb.NewTransactionError(opName, "failed to commit", err)
+	}
+	return nil
+}
+
+func (r *NFTRepository) SaveNFTs(ctx context.Context, nfts []relationaldb.NFTRecord, taxons []uint32) error {
+	if len(nfts) == 0 {
+		return nil
+	}
+	if len(taxons) != len(nfts) {
+		return relationaldb.NewDataError("save_nfts", "one taxon is required per NFT", nil)
+	}
+	return r.inTx(ctx, "save_nfts", func(exec executor) error {
+		for i, nft := range nfts {
+			_, err := exec.ExecContext(ctx, `
+				INSERT INTO nfts (nft_id, ledger_seq, owner, is_burned, uri)
+				VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT (nft_id, ledger_seq) DO UPDATE SET
+				owner = EXCLUDED.owner,
+				is_burned = EXCLUDED.is_burned,
+				uri = EXCLUDED.uri`,
+				nft.NFTokenID[:], int64(nft.LedgerSeq), nft.Owner[:], nft.IsBurned, nft.URI)
+			if err != nil {
+				return relationaldb.NewQueryError("save_nfts", "failed to insert NFT", err)
+			}
+			_, err = exec.ExecContext(ctx, `
+				INSERT INTO issuer_nfts (issuer, taxon, nft_id) VALUES ($1, $2, $3)
+				ON CONFLICT (issuer, taxon, nft_id) DO NOTHING`,
+				nft.NFTokenID[4:24], int64(taxons[i]), nft.NFTokenID[:])
+			if err != nil {
+				return relationaldb.NewQueryError("save_nfts", "failed to insert issuer NFT", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *NFTRepository) SaveNFTTransactions(ctx context.Context, txs []relationaldb.NFTTransaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+	return r.inTx(ctx, "save_nft_transactions", func(exec executor) error {
+		for _, t := range txs {
+			_, err := exec.ExecContext(ctx, `
+				INSERT INTO nft_transactions (nft_id, ledger_seq, txn_seq, trans_id)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (nft_id, ledger_seq, txn_seq) DO NOTHING`,
+				t.NFTokenID[:], int64(t.LedgerSeq), int64(t.TxnSeq), t.Hash[:])
+			if err != nil {
+				return relationaldb.NewQueryError("save_nft_transactions", "failed to insert NFT transaction", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *NFTRepository) GetNFT(ctx context.Context, nftID relationaldb.Hash, ledgerSeq relationaldb.LedgerIndex) (*relationaldb.NFTRecord, error) {
+	row := r.getExecutor().QueryRowContext(ctx, `
+		SELECT nft_id, ledger_seq, owner, is_burned, uri FROM nfts
+		WHERE nft_id = $1 AND ledger_seq <= $2
+		ORDER BY ledger_seq DESC LIMIT 1`,
+		nftID[:], int64(ledgerSeq))
+	nft, err := scanNFT(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, relationaldb.NewQueryError("get_nft", "failed to query NFT", err)
+	}
+	return nft, nil
+}
+
+func (r *NFTRepository) GetNFTHistory(ctx context.Context, options relationaldb.NFTHistoryOptions) (*relationaldb.NFTHistoryResult, error) {
+	orderDir, markerCmp := "DESC", "<"
+	if options.Forward {
+		orderDir, markerCmp = "ASC", ">"
+	}
+
+	query := `SELECT t.trans_id, t.ledger_seq, t.status, t.raw_txn, t.txn_meta, nt.txn_seq
+			  FROM nft_transactions nt
+			  INNER JOIN transactions t ON t.trans_id = nt.trans_id
+			  WHERE nt.nft_id = $1`
+	args := []interface{}{options.NFTokenID[:]}
+	argCount := 1
+
+	if options.MinLedger > 0 {
+		argCount++
+		query += fmt.Sprintf(" AND nt.ledger_seq >= $%d", argCount)
+		args = append(args, int64(options.MinLedger))
+	}
+	if options.MaxLedger > 0 {
+		argCount++
+		query += fmt.Sprintf(" AND nt.ledger_seq <= $%d", argCount)
+		args = append(args, int64(options.MaxLedger))
+	}
+	if options.Marker != nil {
+		query += fmt.Sprintf(" AND (nt.ledger_seq %s $%d OR (nt.ledger_seq = $%d AND nt.txn_seq %s $%d))",
+			markerCmp, argCount+1, argCount+1, markerCmp, argCount+2)
+		argCount += 2
+		args = append(args, int64(options.Marker.LedgerSeq), int64(options.Marker.TxnSeq))
+	}
+	argCount++
+	query += fmt.Sprintf(" ORDER BY nt.ledger_seq %s, nt.txn_seq %s LIMIT $%d", orderDir, orderDir, argCount)
+	args = append(args, options.Limit+1)
+
+	rows, err := r.getExecutor().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, relationaldb.NewQueryError("get_nft_history", "failed to query NFT transactions", err)
+	}
+	defer rows.Close()
+
+	var transactions []relationaldb.TransactionInfo
+	for rows.Next() {
+		var info relationaldb.TransactionInfo
+		var hashBytes []byte
+		var txnMeta sql.NullString
+		if err := rows.Scan(&hashBytes, &info.LedgerSeq, &info.Status, &info.RawTxn, &txnMeta, &info.TxnSeq); err != nil {
+			return nil, relationaldb.NewQueryError("get_nft_history", "failed to scan row", err)
+		}
+		copy(info.Hash[:], hashBytes)
+		if txnMeta.Valid {
+			info.TxnMeta = []byte(txnMeta.String)
+		}
+		transactions = append(transactions, info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, relationaldb.NewQueryError("get_nft_history", "error iterating rows", err)
+	}
+
+	result := &relationaldb.NFTHistoryResult{}
+	if len(transactions) > int(options.Limit) {
+		transactions = transactions[:options.Limit]
+		last := transactions[len(transactions)-1]
+		result.Marker = &relationaldb.AccountTxMarker{LedgerSeq: last.LedgerSeq, TxnSeq: last.TxnSeq}
+	}
+	result.Transactions = transactions
+	return result, nil
+}
+
+func (r *NFTRepository) GetNFTsByIssuer(ctx context.Context, options relationaldb.NFTsByIssuerOptions) (*relationaldb.NFTsByIssuerResult, error) {
+	query := `SELECT n.nft_id, n.ledger_seq, n.owner, n.is_burned, n.uri
+			  FROM issuer_nfts i
+			  INNER JOIN nfts n ON n.nft_id = i.nft_id
+			  WHERE i.issuer = $1
+			  AND n.ledger_seq = (SELECT MAX(ledger_seq) FROM nfts
+			                      WHERE nft_id = i.nft_id AND ledger_seq <= $2)`
+	args := []interface{}{options.Issuer[:], int64(options.LedgerSeq)}
+	argCount := 2
+
+	if options.Taxon != nil {
+		argCount++
+		query += fmt.Sprintf(" AND i.taxon = $%d", argCount)
+		args = append(args, int64(*options.Taxon))
+	}
+	if options.Marker != nil {
+		argCount++
+		query += fmt.Sprintf(" AND i.nft_id > $%d", argCount)
+		args = append(args, options.Marker[:])
+	}
+	argCount++
+	query += fmt.Sprintf(" ORDER BY i.nft_id LIMIT $%d", argCount)
+	args = append(args, options.Limit+1)
+
+	rows, err := r.getExecutor().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, relationaldb.NewQueryError("get_nfts_by_issuer", "failed to query issuer NFTs", err)
+	}
+	defer rows.Close()
+
+	var nfts []relationaldb.NFTRecord
+	for rows.Next() {
+		nft, err := scanNFT(rows)
+		if err != nil {
+			return nil, relationaldb.NewQueryError("get_nfts_by_issuer", "failed to scan row", err)
+		}
+		nfts = append(nfts, *nft)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, relationaldb.NewQueryError("get_nfts_by_issuer", "error iterating rows", err)
+	}
+
+	result := &relationaldb.NFTsByIssuerResult{}
+	if len(nfts) > int(options.Limit) {
+		nfts = nfts[:options.Limit]
+		marker := nfts[len(nfts)-1].NFTokenID
+		result.Marker = &marker
+	}
+	result.NFTs = nfts
+	return result, nil
+}
+
+func scanNFT(row interface {
+	Scan(dest ...interface{}) error
+}) (*relationaldb.NFTRecord, error) {
+	var nft relationaldb.NFTRecord
+	var idBytes, owner []byte
+	var ledgerSeq int64
+	if err := row.Scan(&idBytes, &ledgerSeq, &owner, &nft.IsBurned, &nft.URI); err != nil {
+		return nil, err
+	}
+	copy(nft.NFTokenID[:], idBytes)
+	copy(nft.Owner[:], owner)
+	nft.LedgerSeq = relationaldb.LedgerIndex(ledgerSeq)
+	return &nft, nil
+}
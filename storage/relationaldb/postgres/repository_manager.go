@@ -13,14 +13,28 @@ type RepositoryManager struct {
 	db     *sql.DB
 	config *relationaldb.Config
 
+	// migrator versions the schema. manualMigrate leaves pending
+	// migrations to Migrate.
+	migrator      *relationaldb.Migrator
+	manualMigrate bool
+
 	// Repository instances
 	ledgerRepo             *LedgerRepository
 	transactionRepo        *TransactionRepository
 	accountTransactionRepo *AccountTransactionRepository
 	systemRepo             *SystemRepository
 	validationRepo         *ValidationRepository
+	peerReservationRepo    *PeerReservationRepository
+	nftRepo                *NFTRepository
+	mptRepo                *MPTRepository
 }
 
+// Compile-time interface checks
+var (
+	_ relationaldb.RepositoryManager = (*RepositoryManager)(nil)
+	_ relationaldb.SchemaManager     = (*RepositoryManager)(nil)
+)
+
 // NewRepositoryManager creates a new PostgreSQL repository manager
 func NewRepositoryManager(config *relationaldb.Config) (*RepositoryManager, error) {
 	if err := config.Validate(); err != nil {
@@ -60,11 +74,11 @@ func (rm *RepositoryManager) Open(ctx context.Context) error {
 
 	rm.db = sqlDB
 
-	// Initialize schema (matches rippled's table structure)
 	if err := rm.initSchema(ctx); err != nil {
 		rm.db.Close()
 		rm.db = nil
-		return relationaldb.NewSchemaError("open", "failed to initialize schema", err)
+		rm.migrator = nil
+		return err
 	}
 
 	rm.ledgerRepo = NewLedgerRepository(rm.db)
@@ -72,6 +86,9 @@ func (rm *RepositoryManager) Open(ctx context.Context) error {
 	rm.accountTransactionRepo = NewAccountTransactionRepository(rm.db)
 	rm.systemRepo = NewSystemRepository(rm.db)
 	rm.validationRepo = NewValidationRepository(rm.db)
+	rm.peerReservationRepo = NewPeerReservationRepository(rm.db)
+	rm.nftRepo = NewNFTRepository(rm.db)
+	rm.mptRepo = NewMPTRepository(rm.db)
 
 	return nil
 }
@@ -83,6 +100,7 @@ func (rm *RepositoryManager) Close(ctx context.Context) error {
 
 	err := rm.db.Close()
 	rm.db = nil
+	rm.migrator = nil
 
 	// Clear repository instances
 	rm.ledgerRepo = nil
@@ -90,6 +108,9 @@ func (rm *RepositoryManager) Close(ctx context.Context) error {
 	rm.accountTransactionRepo = nil
 	rm.systemRepo = nil
 	rm.validationRepo = nil
+	rm.peerReservationRepo = nil
+	rm.nftRepo = nil
+	rm.mptRepo = nil
 
 	if err != nil {
 		return relationaldb.NewConnectionError("close", "failed to close database connection", err)
@@ -118,6 +139,18 @@ func (rm *RepositoryManager) Validation() relationaldb.ValidationRepository {
 	return rm.validationRepo
 }
 
+func (rm *RepositoryManager) PeerReservation() relationaldb.PeerReservationRepository {
+	return rm.peerReservationRepo
+}
+
+func (rm *RepositoryManager) NFT() relationaldb.NFTRepository {
+	return rm.nftRepo
+}
+
+func (rm *RepositoryManager) MPT() relationaldb.MPTRepository {
+	return rm.mptRepo
+}
+
 func (rm *RepositoryManager) WithTransaction(ctx context.Context, fn func(relationaldb.TransactionContext) error) error {
 	tx, err := rm.systemRepo.Begin(ctx)
 	if err != nil {
@@ -142,78 +175,48 @@ func (rm *RepositoryManager) WithTransaction(ctx context.Context, fn func(relati
 	return tx.Commit(ctx)
 }
 
-// initSchema initializes the database schema matching rippled's structure
-func (rm *RepositoryManager) initSchema(ctx context.Context) error {
-	// Schema based on rippled's SQLite tables but adapted for PostgreSQL
-	queries := []string{
-		// Ledgers table - matches rippled's Ledgers table structure
-		`CREATE TABLE IF NOT EXISTS ledgers (
-			ledger_hash BYTEA PRIMARY KEY,
-			ledger_seq BIGINT UNIQUE NOT NULL,
-			prev_hash BYTEA NOT NULL,
-			total_coins DECIMAL(20,0) NOT NULL,
-			closing_time BIGINT NOT NULL,
-			prev_closing_time BIGINT NOT NULL,
-			close_time_res INTEGER NOT NULL,
-			close_flags INTEGER NOT NULL,
-			account_set_hash BYTEA NOT NULL,
-			trans_set_hash BYTEA NOT NULL,
-			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
-		)`,
-
-		// Transactions table - matches rippled's Transactions table
-		`CREATE TABLE IF NOT EXISTS transactions (
-			trans_id BYTEA PRIMARY KEY,
-			ledger_seq BIGINT NOT NULL,
-			status VARCHAR(50) NOT NULL,
-			raw_txn BYTEA NOT NULL,
-			txn_meta BYTEA,
-			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
-		)`,
-
-		// AccountTransactions table - matches rippled's AccountTransactions table
-		`CREATE TABLE IF NOT EXISTS account_transactions (
-			trans_id BYTEA NOT NULL,
-			account VARCHAR(34) NOT NULL,
-			ledger_seq BIGINT NOT NULL,
-			txn_seq INTEGER NOT NULL,
-			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-			PRIMARY KEY (trans_id, account)
-		)`,
-
-		// Validations table — rippled's pre-May-2019 historical schema,
-		// augmented with seen_time + flags for receive-side forensics.
-		`CREATE TABLE IF NOT EXISTS validations (
-			ledger_seq   BIGINT NOT NULL,
-			initial_seq  BIGINT NOT NULL,
-			ledger_hash  BYTEA NOT NULL,
-			node_pubkey  BYTEA NOT NULL,
-			sign_time    BIGINT NOT NULL,
-			seen_time    BIGINT NOT NULL,
-			flags        BIGINT NOT NULL,
-			raw          BYTEA NOT NULL,
-			created_at   TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-			PRIMARY KEY (ledger_hash, node_pubkey)
-		)`,
-
-		// Indexes matching rippled's performance optimizations
-		`CREATE INDEX IF NOT EXISTS idx_ledgers_seq ON ledgers(ledger_seq)`,
-		`CREATE INDEX IF NOT EXISTS idx_ledgers_closing_time ON ledgers(closing_time)`,
-		`CREATE INDEX IF NOT EXISTS idx_transactions_ledger_seq ON transactions(ledger_seq)`,
-		`CREATE INDEX IF NOT EXISTS idx_account_transactions_account ON account_transactions(account)`,
-		`CREATE INDEX IF NOT EXISTS idx_account_transactions_ledger_seq ON account_transactions(ledger_seq)`,
-		`CREATE INDEX IF NOT EXISTS idx_account_transactions_account_ledger_txn ON account_transactions(account, ledger_seq, txn_seq)`,
-		`CREATE INDEX IF NOT EXISTS idx_validations_seq       ON validations(ledger_seq)`,
-		`CREATE INDEX IF NOT EXISTS idx_validations_node      ON validations(node_pubkey, ledger_seq)`,
-		`CREATE INDEX IF NOT EXISTS idx_validations_sign_time ON validations(sign_time)`,
-		`CREATE INDEX IF NOT EXISTS idx_validations_initial   ON validations(initial_seq, ledger_seq)`,
+// SetAutoMigrate controls whether Open applies pending migrations.
+// Must be called before Open.
+func (rm *RepositoryManager) SetAutoMigrate(enabled bool) {
+	rm.manualMigrate = !enabled
+}
+
+// SchemaStatus reports the schema version of the database.
+func (rm *RepositoryManager) SchemaStatus(ctx context.Context) ([]*relationaldb.SchemaStatus, error) {
+	if rm.migrator == nil {
+		return nil, relationaldb.ErrDatabaseClosed
+	}
+	status, err := rm.migrator.Status(ctx)
+	if err != nil {
+		return nil, err
 	}
+	return []*relationaldb.SchemaStatus{status}, nil
+}
 
-	for _, query := range queries {
-		if _, err := rm.db.ExecContext(ctx, query); err != nil {
-			return relationaldb.NewSchemaError("init_schema", "failed to execute schema query", err)
-		}
+// Migrate applies pending migrations to the database.
+func (rm *RepositoryManager) Migrate(ctx context.Context, dryRun bool) (map[string][]relationaldb.Migration, error) {
+	if rm.migrator == nil {
+		return nil, relationaldb.ErrDatabaseClosed
 	}
+	applied, err := rm.migrator.Migrate(ctx, dryRun)
+	return map[string][]relationaldb.Migration{schemaName: applied}, err
+}
 
-	return nil
+// initSchema refuses a schema newer than this build and, unless automatic
+// migration is disabled, applies pending migrations.
+func (rm *RepositoryManager) initSchema(ctx context.Context) error {
+	m, err := relationaldb.NewMigrator(rm.db, schemaName, relationaldb.DialectPostgres, migrations)
+	if err != nil {
+		return err
+	}
+	rm.migrator = m
+
+	if _, err := m.Check(ctx); err != nil {
+		return err
+	}
+	if rm.manualMigrate {
+		return nil
+	}
+	_, err = m.Migrate(ctx, false)
+	return err
 }
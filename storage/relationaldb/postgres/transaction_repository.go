@@ -136,6 +136,46 @@ func (r *TransactionRepository) GetTxHistory(ctx context.Context, startIndex rel
 	return results, nil
 }
 
+// GetTransactionsByLedgerRange returns the transactions of ledgers minSeq
+// through maxSeq in ascending ledger order, at most limit of them.
+func (r *TransactionRepository) GetTransactionsByLedgerRange(ctx context.Context, minSeq, maxSeq relationaldb.LedgerIndex, limit int) ([]relationaldb.TransactionInfo, error) {
+	query := `SELECT trans_id, ledger_seq, status, raw_txn, txn_meta
+			  FROM transactions
+			  WHERE ledger_seq >= $1 AND ledger_seq <= $2
+			  ORDER BY ledger_seq ASC
+			  LIMIT $3`
+
+	rows, err := r.getExecutor().QueryContext(ctx, query, minSeq, maxSeq, limit)
+	if err != nil {
+		return nil, relationaldb.NewQueryError("get_transactions_by_ledger_range", "failed to query transactions", err)
+	}
+	defer rows.Close()
+
+	var results []relationaldb.TransactionInfo
+
+	for rows.Next() {
+		var info relationaldb.TransactionInfo
+		var hashBytes []byte
+		var txnMeta sql.NullString
+
+		if err := rows.Scan(&hashBytes, &info.LedgerSeq, &info.Status, &info.RawTxn, &txnMeta); err != nil {
+			return nil, relationaldb.NewQueryError("get_transactions_by_ledger_range", "failed to scan row", err)
+		}
+
+		copy(info.Hash[:], hashBytes)
+		if txnMeta.Valid {
+			info.TxnMeta = []byte(txnMeta.String)
+		}
+		results = append(results, info)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, relationaldb.NewQueryError("get_transactions_by_ledger_range", "error iterating rows", err)
+	}
+
+	return results, nil
+}
+
 func (r *TransactionRepository) SaveTransaction(ctx context.Context, txInfo *relationaldb.TransactionInfo) error {
 	query := `INSERT INTO transactions (trans_id, ledger_seq, status, raw_txn, txn_meta)
 			  VALUES ($1, $2, $3, $4, $5)
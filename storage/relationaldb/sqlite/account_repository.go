@@ -3,6 +3,7 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/LeJamon/goXRPLd/storage/relationaldb"
 )
@@ -127,6 +128,15 @@ func (r *AccountTransactionRepository) queryAccountTxsPage(ctx context.Context,
 		args = append(args, options.MaxLedger)
 	}
 
+	if options.Filter.TxType != "" {
+		query += " AND at.tx_type = ?"
+		args = append(args, options.Filter.TxType)
+	}
+	if options.Filter.Direction != relationaldb.AccountTxAnyDirection {
+		query += " AND at.direction = ?"
+		args = append(args, int(options.Filter.Direction))
+	}
+
 	if options.Marker != nil {
 		// For ASC: > marker; for DESC: < marker
 		query += " AND (at.ledger_seq " + markerCmp + " ? OR (at.ledger_seq = ? AND at.txn_seq " + markerCmp + " ?))"
@@ -193,14 +203,17 @@ func (r *AccountTransactionRepository) GetNewestAccountTxsPage(ctx context.Conte
 }
 
 func (r *AccountTransactionRepository) SaveAccountTransaction(ctx context.Context, accountID relationaldb.AccountID, txInfo *relationaldb.TransactionInfo) error {
-	query := `INSERT INTO account_transactions (trans_id, account, ledger_seq, txn_seq)
-			  VALUES (?, ?, ?, ?)
+	query := `INSERT INTO account_transactions (trans_id, account, ledger_seq, txn_seq, tx_type, direction)
+			  VALUES (?, ?, ?, ?, ?, ?)
 			  ON CONFLICT (trans_id, account) DO UPDATE SET
 			  ledger_seq = excluded.ledger_seq,
-			  txn_seq = excluded.txn_seq`
+			  txn_seq = excluded.txn_seq,
+			  tx_type = excluded.tx_type,
+			  direction = excluded.direction`
 
 	_, err := r.getExecutor().ExecContext(ctx, query,
-		txInfo.Hash[:], accountID.String(), txInfo.LedgerSeq, txInfo.TxnSeq)
+		txInfo.Hash[:], accountID.String(), txInfo.LedgerSeq, txInfo.TxnSeq,
+		txInfo.TxType, relationaldb.AccountTxDirectionOf(accountID, txInfo))
 	if err != nil {
 		return relationaldb.NewQueryError("save_account_transaction", "failed to save account transaction", err)
 	}
@@ -214,3 +227,53 @@ func (r *AccountTransactionRepository) DeleteAccountTransactionsBeforeLedgerSeq(
 	}
 	return nil
 }
+
+func (r *AccountTransactionRepository) GetUntypedAccountTransactions(ctx context.Context, marker *relationaldb.UntypedAccountTx, limit uint32) ([]relationaldb.UntypedAccountTx, error) {
+	query := `SELECT at.trans_id, at.account, t.raw_txn
+			  FROM account_transactions at
+			  INNER JOIN transactions t ON t.trans_id = at.trans_id
+			  WHERE at.tx_type = ''`
+	var args []interface{}
+	if marker != nil {
+		query += ` AND (at.trans_id > ? OR (at.trans_id = ? AND at.account > ?))`
+		args = append(args, marker.Hash[:], marker.Hash[:], marker.Account.String())
+	}
+	query += fmt.Sprintf(" ORDER BY at.trans_id, at.account LIMIT %d", limit)
+
+	rows, err := r.getExecutor().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, relationaldb.NewQueryError("get_untyped_account_transactions", "failed to query untyped account transactions", err)
+	}
+	defer rows.Close()
+
+	var result []relationaldb.UntypedAccountTx
+	for rows.Next() {
+		var row relationaldb.UntypedAccountTx
+		var hash []byte
+		var account string
+		if err := rows.Scan(&hash, &account, &row.RawTxn); err != nil {
+			return nil, relationaldb.NewQueryError("get_untyped_account_transactions", "failed to scan row", err)
+		}
+		copy(row.Hash[:], hash)
+		if row.Account, err = relationaldb.ParseAccountID(account); err != nil {
+			return nil, relationaldb.NewQueryError("get_untyped_account_transactions", "invalid account", err)
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, relationaldb.NewQueryError("get_untyped_account_transactions", "failed to iterate rows", err)
+	}
+	return result, nil
+}
+
+func (r *AccountTransactionRepository) SetAccountTransactionTypes(ctx context.Context, rows []relationaldb.UntypedAccountTx) error {
+	query := `UPDATE account_transactions SET tx_type = ?, direction = ?
+			  WHERE trans_id = ? AND account = ?`
+	for _, row := range rows {
+		_, err := r.getExecutor().ExecContext(ctx, query, row.TxType, int(row.Direction), row.Hash[:], row.Account.String())
+		if err != nil {
+			return relationaldb.NewQueryError("set_account_transaction_types", "failed to update account transaction", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package sqlite
+
+import "github.com/LeJamon/goXRPLd/storage/relationaldb"
+
+// Schema migrations for ledger.db and transaction.db. Append new
+// migrations to the end of a list; never edit or reorder one that has
+// shipped, since databases record only the version they reached.
+//
+// Version 1 of each list is the schema that predates versioning. Its
+// statements use IF NOT EXISTS, so databases created before versioning
+// adopt version 1 unchanged.
+
+// ledgerMigrations version ledger.db.
+var ledgerMigrations = []relationaldb.Migration{
+	{
+		Version:     1,
+		Description: "ledgers, validations and peer_reservations tables",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS ledgers (
+				ledger_hash BLOB PRIMARY KEY,
+				ledger_seq INTEGER UNIQUE NOT NULL,
+				prev_hash BLOB NOT NULL,
+				total_coins INTEGER NOT NULL,
+				closing_time INTEGER NOT NULL,
+				prev_closing_time INTEGER NOT NULL,
+				close_time_res INTEGER NOT NULL,
+				close_flags INTEGER NOT NULL,
+				account_set_hash BLOB NOT NULL,
+				trans_set_hash BLOB NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_ledgers_seq ON ledgers(ledger_seq)`,
+
+			// On-disk validation archive. Cohabits ledger.db — see
+			// ValidationRepository for the rationale. Columns mirror
+			// rippled's historical Validations DDL (DBInit.h,
+			// pre-May-2019) with SeenTime + Flags added for receive-side
+			// forensics.
+			`CREATE TABLE IF NOT EXISTS validations (
+				ledger_seq   INTEGER NOT NULL,
+				initial_seq  INTEGER NOT NULL,
+				ledger_hash  BLOB NOT NULL,
+				node_pubkey  BLOB NOT NULL,
+				sign_time    INTEGER NOT NULL,
+				seen_time    INTEGER NOT NULL,
+				flags        INTEGER NOT NULL,
+				raw          BLOB NOT NULL,
+				PRIMARY KEY (ledger_hash, node_pubkey)
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_validations_seq       ON validations(ledger_seq)`,
+			`CREATE INDEX IF NOT EXISTS idx_validations_node      ON validations(node_pubkey, ledger_seq)`,
+			`CREATE INDEX IF NOT EXISTS idx_validations_sign_time ON validations(sign_time)`,
+			`CREATE INDEX IF NOT EXISTS idx_validations_initial   ON validations(initial_seq, ledger_seq)`,
+
+			// Peer reservations used by the peer_reservations_* admin
+			// RPCs. Mirrors rippled's PeerReservations DDL (DBInit.h)
+			// keyed by the base58 node public key.
+			`CREATE TABLE IF NOT EXISTS peer_reservations (
+				public_key  TEXT PRIMARY KEY NOT NULL,
+				description TEXT NOT NULL DEFAULT ''
+			)`,
+		},
+	},
+}
+
+// txMigrations version transaction.db.
+var txMigrations = []relationaldb.Migration{
+	{
+		Version:     1,
+		Description: "transactions and account_transactions tables",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS transactions (
+				trans_id BLOB PRIMARY KEY,
+				ledger_seq INTEGER NOT NULL,
+				status TEXT NOT NULL,
+				raw_txn BLOB NOT NULL,
+				txn_meta BLOB
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_transactions_ledger_seq ON transactions(ledger_seq)`,
+
+			`CREATE TABLE IF NOT EXISTS account_transactions (
+				trans_id BLOB NOT NULL,
+				account TEXT NOT NULL,
+				ledger_seq INTEGER NOT NULL,
+				txn_seq INTEGER NOT NULL,
+				PRIMARY KEY (trans_id, account)
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_acct_tx_id ON account_transactions(trans_id)`,
+			`CREATE INDEX IF NOT EXISTS idx_acct_tx ON account_transactions(account, ledger_seq, txn_seq, trans_id)`,
+			`CREATE INDEX IF NOT EXISTS idx_acct_lgr ON account_transactions(ledger_seq, account, trans_id)`,
+		},
+	},
+	{
+		Version:     2,
+		Description: "nfts, issuer_nfts and nft_transactions tables",
+		Statements: []string{
+			// NFT index modelled on Clio's nf_tokens, issuer_nf_tokens_v2
+			// and nf_token_transactions tables. Owners and issuers are
+			// stored as hex like account_transactions.account.
+			`CREATE TABLE nfts (
+				nft_id BLOB NOT NULL,
+				ledger_seq INTEGER NOT NULL,
+				owner TEXT NOT NULL,
+				is_burned INTEGER NOT NULL,
+				uri BLOB,
+				PRIMARY KEY (nft_id, ledger_seq)
+			)`,
+			`CREATE TABLE issuer_nfts (
+				issuer TEXT NOT NULL,
+				taxon INTEGER NOT NULL,
+				nft_id BLOB NOT NULL,
+				PRIMARY KEY (issuer, taxon, nft_id)
+			)`,
+			`CREATE INDEX idx_issuer_nfts_id ON issuer_nfts(issuer, nft_id)`,
+			`CREATE TABLE nft_transactions (
+				nft_id BLOB NOT NULL,
+				ledger_seq INTEGER NOT NULL,
+				txn_seq INTEGER NOT NULL,
+				trans_id BLOB NOT NULL,
+				PRIMARY KEY (nft_id, ledger_seq, txn_seq)
+			)`,
+		},
+	},
+	{
+		Version:     3,
+		Description: "mpt_holders table",
+		Statements: []string{
+			// Modelled on Clio's mp_token_holders table.
+			`CREATE TABLE mpt_holders (
+				mpt_issuance_id BLOB NOT NULL,
+				holder TEXT NOT NULL,
+				PRIMARY KEY (mpt_issuance_id, holder)
+			)`,
+		},
+	},
+	{
+		Version:     4,
+		Description: "tx_type and direction columns on account_transactions",
+		Statements: []string{
+			// Rows indexed before this version start with an empty tx_type
+			// and direction 0; the ledger service backfills both from
+			// raw_txn at startup.
+			`ALTER TABLE account_transactions ADD COLUMN tx_type TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE account_transactions ADD COLUMN direction INTEGER NOT NULL DEFAULT 0`,
+			`CREATE INDEX idx_acct_tx_type ON account_transactions(account, tx_type, ledger_seq, txn_seq)`,
+		},
+	},
+}
@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+func TestOpenMigratesSchema(t *testing.T) {
+	ctx := context.Background()
+	rm := setupTestDB(t)
+
+	statuses, err := rm.SchemaStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("expected ledger.db and transaction.db, got %d statuses", len(statuses))
+	}
+	for _, status := range statuses {
+		if status.Current != status.Latest || len(status.Pending) != 0 {
+			t.Errorf("%s not migrated on open: %+v", status.Schema, status)
+		}
+	}
+}
+
+func TestOpenAdoptsUnversionedDatabase(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// A ledger.db created before versioning, holding a ledger.
+	db, err := sql.Open("sqlite", filepath.Join(dir, "ledger.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range ledgerMigrations[0].Statements {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	rm, err := NewRepositoryManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rm.Open(ctx); err != nil {
+		t.Fatalf("Open failed on an unversioned database: %v", err)
+	}
+	defer rm.Close(ctx)
+
+	if err := rm.Ledger().SaveValidatedLedger(ctx, makeLedgerInfo(5), true); err != nil {
+		t.Fatal(err)
+	}
+	statuses, err := rm.SchemaStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statuses[0].Current != len(ledgerMigrations) {
+		t.Errorf("unexpected ledger.db status: %+v", statuses[0])
+	}
+}
+
+func TestOpenManualMigrate(t *testing.T) {
+	ctx := context.Background()
+	rm, err := NewRepositoryManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm.SetAutoMigrate(false)
+	if err := rm.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer rm.Close(ctx)
+
+	pending, err := rm.Migrate(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending["ledger.db"]) != len(ledgerMigrations) || len(pending["transaction.db"]) != len(txMigrations) {
+		t.Fatalf("unexpected dry run result: %v", pending)
+	}
+
+	applied, err := rm.Migrate(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied["ledger.db"]) != len(ledgerMigrations) {
+		t.Errorf("unexpected migrate result: %v", applied)
+	}
+	if err := rm.Ledger().SaveValidatedLedger(ctx, makeLedgerInfo(1), true); err != nil {
+		t.Errorf("ledger table missing after Migrate: %v", err)
+	}
+}
+
+func TestOpenRefusesNewerSchema(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	rm, err := NewRepositoryManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rm.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_, err = rm.ledgerDB.Exec(`INSERT INTO `+relationaldb.SchemaVersionsTable+
+		` (version, description, applied_at) VALUES (?, 'from the future', 0)`, len(ledgerMigrations)+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm.Close(ctx)
+
+	err = rm.Open(ctx)
+	if !errors.Is(err, relationaldb.ErrSchemaVersion) {
+		t.Fatalf("expected ErrSchemaVersion, got %v", err)
+	}
+	if rm.ledgerRepo != nil {
+		t.Error("repositories set up after a failed Open")
+	}
+}
@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+func TestMPTHolders(t *testing.T) {
+	rm := setupTestDB(t)
+	ctx := context.Background()
+	issuance := [24]byte{1}
+	other := [24]byte{2}
+
+	var holders []relationaldb.MPTHolder
+	for i := byte(5); i >= 1; i-- {
+		holders = append(holders, relationaldb.MPTHolder{MPTIssuanceID: issuance, Holder: relationaldb.AccountID{i}})
+	}
+	holders = append(holders, relationaldb.MPTHolder{MPTIssuanceID: other, Holder: relationaldb.AccountID{9}})
+	if err := rm.MPT().SaveMPTHolders(ctx, holders); err != nil {
+		t.Fatal(err)
+	}
+	// Saving a holder again is a no-op.
+	if err := rm.MPT().SaveMPTHolders(ctx, holders[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []relationaldb.AccountID
+	options := relationaldb.MPTHoldersOptions{MPTIssuanceID: issuance, Limit: 2}
+	for {
+		page, err := rm.MPT().GetMPTHolders(ctx, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, page.Holders...)
+		if page.Marker == nil {
+			break
+		}
+		options.Marker = page.Marker
+	}
+	if len(got) != 5 {
+		t.Fatalf("expected five holders, got %x", got)
+	}
+	for i, holder := range got {
+		if holder != (relationaldb.AccountID{byte(i + 1)}) {
+			t.Fatalf("holders out of order: %x", got)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+// NFTRepository implements relationaldb.NFTRepository. Its tables live in
+// transaction.db next to the transactions they are joined with.
+type NFTRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+// Compile-time interface check.
+var _ relationaldb.NFTRepository = (*NFTRepository)(nil)
+
+func NewNFTRepository(db *sql.DB) *NFTRepository {
+	return &NFTRepository{db: db}
+}
+
+func NewNFTRepositoryWithTx(tx *sql.Tx) *NFTRepository {
+	return &NFTRepository{tx: tx}
+}
+
+func (r *NFTRepository) getExecutor() executor {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// inTx runs fn in the repository's transaction, or in a new one.
+func (r *NFTRepository) inTx(ctx context.Context, opName string, fn func(executor) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return relationaldb.NewTransactionError(opName, "failed to begin transaction", err)
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return relationaldb.NewTransactionError(opName, "failed to commit", err)
+	}
+	return nil
+}
+
+func (r *NFTRepository) SaveNFTs(ctx context.Context, nfts []relationaldb.NFTRecord, taxons []uint32) error {
+	if len(nfts) == 0 {
+		return nil
+	}
+	if len(taxons) != len(nfts) {
+		return relationaldb.NewDataError("save_nfts", "one taxon is required per NFT", nil)
+	}
+	return r.inTx(ctx, "save_nfts", func(exec executor) error {
+		for i, nft := range nfts {
+			_, err := exec.ExecContext(ctx, `
+				INSERT INTO nfts (nft_id, ledger_seq, owner, is_burned, uri)
+				VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (nft_id, ledger_seq) DO UPDATE SET
+				owner = excluded.owner,
+				is_burned = excluded.is_burned,
+				uri = excluded.uri`,
+				nft.NFTokenID[:], nft.LedgerSeq, nft.Owner.String(), nft.IsBurned, nft.URI)
+			if err != nil {
+				return relationaldb.NewQueryError("save_nfts", "failed to insert NFT", err)
+			}
+			issuer := nftIssuer(nft.NFTokenID)
+			_, err = exec.ExecContext(ctx, `
+				INSERT INTO issuer_nfts (issuer, taxon, nft_id) VALUES (?, ?, ?)
+				ON CONFLICT (issuer, taxon, nft_id) DO NOTHING`,
+				issuer.String(), taxons[i], nft.NFTokenID[:])
+			if err != nil {
+				return relationaldb.NewQueryError("save_nfts", "failed to insert issuer NFT", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *NFTRepository) SaveNFTTransactions(ctx context.Context, txs []relationaldb.NFTTransaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+	return r.inTx(ctx, "save_nft_transactions", func(exec executor) error {
+		for _, t := range txs {
+			_, err := exec.ExecContext(ctx, `
+				INSERT INTO nft_transactions (nft_id, ledger_seq, txn_seq, trans_id)
+				VALUES (?, ?, ?, ?)
+				ON CONFLICT (nft_id, ledger_seq, txn_seq) DO NOTHING`,
+				t.NFTokenID[:], t.LedgerSeq, t.TxnSeq, t.Hash[:])
+			if err != nil {
+				return relationaldb.NewQueryError("save_nft_transactions", "failed to insert NFT transaction", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *NFTRepository) GetNFT(ctx context.Context, nftID relationaldb.Hash, ledgerSeq relationaldb.LedgerIndex) (*relationaldb.NFTRecord, error) {
+	row := r.getExecutor().QueryRowContext(ctx, `
+		SELECT nft_id, ledger_seq, owner, is_burned, uri FROM nfts
+		WHERE nft_id = ? AND ledger_seq <= ?
+		ORDER BY ledger_seq DESC LIMIT 1`,
+		nftID[:], ledgerSeq)
+	nft, err := scanNFT(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, relationaldb.NewQueryError("get_nft", "failed to query NFT", err)
+	}
+	return nft, nil
+}
+
+func (r *NFTRepository) GetNFTHistory(ctx context.Context, options relationaldb.NFTHistoryOptions) (*relationaldb.NFTHistoryResult, error) {
+	orderDir, markerCmp := "DESC", "<"
+	if options.Forward {
+		orderDir, markerCmp = "ASC", ">"
+	}
+
+	query := `SELECT t.trans_id, t.ledger_seq, t.status, t.raw_txn, t.txn_meta, nt.txn_seq
+			  FROM nft_transactions nt
+			  INNER JOIN transactions t ON t.trans_id = nt.trans_id
+			  WHERE nt.nft_id = ?`
+	args := []interface{}{options.NFTokenID[:]}
+
+	if options.MinLedger > 0 {
+		query += " AND nt.ledger_seq >= ?"
+		args = append(args, options.MinLedger)
+	}
+	if options.MaxLedger > 0 {
+		query += " AND nt.ledger_seq <= ?"
+		args = append(args, options.MaxLedger)
+	}
+	if options.Marker != nil {
+		query += " AND (nt.ledger_seq " + markerCmp + " ? OR (nt.ledger_seq = ? AND nt.txn_seq " + markerCmp + " ?))"
+		args = append(args, options.Marker.LedgerSeq, options.Marker.LedgerSeq, options.Marker.TxnSeq)
+	}
+	query += " ORDER BY nt.ledger_seq " + orderDir + ", nt.txn_seq " + orderDir + " LIMIT ?"
+	args = append(args, options.Limit+1)
+
+	rows, err := r.getExecutor().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, relationaldb.NewQueryError("get_nft_history", "failed to query NFT transactions", err)
+	}
+	defer rows.Close()
+
+	var transactions []relationaldb.TransactionInfo
+	for rows.Next() {
+		var info relationaldb.TransactionInfo
+		var hashBytes, txnMeta []byte
+		if err := rows.Scan(&hashBytes, &info.LedgerSeq, &info.Status, &info.RawTxn, &txnMeta, &info.TxnSeq); err != nil {
+			return nil, relationaldb.NewQueryError("get_nft_history", "failed to scan row", err)
+		}
+		copy(info.Hash[:], hashBytes)
+		info.TxnMeta = txnMeta
+		transactions = append(transactions, info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, relationaldb.NewQueryError("get_nft_history", "error iterating rows", err)
+	}
+
+	result := &relationaldb.NFTHistoryResult{}
+	if len(transactions) > int(options.Limit) {
+		transactions = transactions[:options.Limit]
+		last := transactions[len(transactions)-1]
+		result.Marker = &relationaldb.AccountTxMarker{LedgerSeq: last.LedgerSeq, TxnSeq: last.TxnSeq}
+	}
+	result.Transactions = transactions
+	return result, nil
+}
+
+func (r *NFTRepository) GetNFTsByIssuer(ctx context.Context, options relationaldb.NFTsByIssuerOptions) (*relationaldb.NFTsByIssuerResult, error) {
+	// The state of each NFT is its latest row at or before the ledger, so
+	// NFTs minted later are left out.
+	query := `SELECT n.nft_id, n.ledger_seq, n.owner, n.is_burned, n.uri
+			  FROM issuer_nfts i
+			  INNER JOIN nfts n ON n.nft_id = i.nft_id
+			  WHERE i.issuer = ?
+			  AND n.ledger_seq = (SELECT MAX(ledger_seq) FROM nfts
+			                      WHERE nft_id = i.nft_id AND ledger_seq <= ?)`
+	args := []interface{}{options.Issuer.String(), options.LedgerSeq}
+
+	if options.Taxon != nil {
+		query += " AND i.taxon = ?"
+		args = append(args, *options.Taxon)
+	}
+	if options.Marker != nil {
+		query += " AND i.nft_id > ?"
+		args = append(args, options.Marker[:])
+	}
+	query += " ORDER BY i.nft_id LIMIT ?"
+	args = append(args, options.Limit+1)
+
+	rows, err := r.getExecutor().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, relationaldb.NewQueryError("get_nfts_by_issuer", "failed to query issuer NFTs", err)
+	}
+	defer rows.Close()
+
+	var nfts []relationaldb.NFTRecord
+	for rows.Next() {
+		nft, err := scanNFT(rows)
+		if err != nil {
+			return nil, relationaldb.NewQueryError("get_nfts_by_issuer", "failed to scan row", err)
+		}
+		nfts = append(nfts, *nft)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, relationaldb.NewQueryError("get_nfts_by_issuer", "error iterating rows", err)
+	}
+
+	result := &relationaldb.NFTsByIssuerResult{}
+	if len(nfts) > int(options.Limit) {
+		nfts = nfts[:options.Limit]
+		marker := nfts[len(nfts)-1].NFTokenID
+		result.Marker = &marker
+	}
+	result.NFTs = nfts
+	return result, nil
+}
+
+func scanNFT(row interface {
+	Scan(dest ...interface{}) error
+}) (*relationaldb.NFTRecord, error) {
+	var nft relationaldb.NFTRecord
+	var idBytes []byte
+	var owner string
+	if err := row.Scan(&idBytes, &nft.LedgerSeq, &owner, &nft.IsBurned, &nft.URI); err != nil {
+		return nil, err
+	}
+	copy(nft.NFTokenID[:], idBytes)
+	ownerBytes, err := hex.DecodeString(owner)
+	if err != nil {
+		return nil, err
+	}
+	copy(nft.Owner[:], ownerBytes)
+	return &nft, nil
+}
+
+// nftIssuer returns the issuer encoded in bytes 4 to 24 of an NFTokenID.
+func nftIssuer(id relationaldb.Hash) relationaldb.AccountID {
+	var issuer relationaldb.AccountID
+	copy(issuer[:], id[4:24])
+	return issuer
+}
@@ -0,0 +1,184 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+// makeNFTokenID returns an NFTokenID of issuer with the given serial.
+func makeNFTokenID(issuer byte, serial byte) relationaldb.Hash {
+	var id relationaldb.Hash
+	id[4] = issuer
+	id[31] = serial
+	return id
+}
+
+func TestNFTStateAsOfLedger(t *testing.T) {
+	rm := setupTestDB(t)
+	ctx := context.Background()
+	id := makeNFTokenID(1, 1)
+	alice := relationaldb.AccountID{0xa1}
+	bob := relationaldb.AccountID{0xb0}
+
+	err := rm.NFT().SaveNFTs(ctx, []relationaldb.NFTRecord{
+		{NFTokenID: id, LedgerSeq: 10, Owner: alice, URI: []byte("ipfs://a")},
+		{NFTokenID: id, LedgerSeq: 20, Owner: bob, URI: []byte("ipfs://a")},
+		{NFTokenID: id, LedgerSeq: 30, Owner: bob, IsBurned: true, URI: []byte("ipfs://a")},
+	}, []uint32{7, 7, 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nft, err := rm.NFT().GetNFT(ctx, id, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nft != nil {
+		t.Fatalf("expected no NFT before its mint, got %+v", nft)
+	}
+
+	for _, tt := range []struct {
+		seq    relationaldb.LedgerIndex
+		owner  relationaldb.AccountID
+		burned bool
+	}{
+		{10, alice, false},
+		{25, bob, false},
+		{30, bob, true},
+	} {
+		nft, err := rm.NFT().GetNFT(ctx, id, tt.seq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nft == nil || nft.Owner != tt.owner || nft.IsBurned != tt.burned {
+			t.Fatalf("ledger %d: unexpected state %+v", tt.seq, nft)
+		}
+		if !bytes.Equal(nft.URI, []byte("ipfs://a")) {
+			t.Fatalf("ledger %d: unexpected URI %q", tt.seq, nft.URI)
+		}
+	}
+
+	// A later state in the same ledger replaces the earlier one.
+	carol := relationaldb.AccountID{0xc0}
+	if err := rm.NFT().SaveNFTs(ctx, []relationaldb.NFTRecord{{NFTokenID: id, LedgerSeq: 20, Owner: carol}}, []uint32{7}); err != nil {
+		t.Fatal(err)
+	}
+	nft, err = rm.NFT().GetNFT(ctx, id, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nft.Owner != carol {
+		t.Fatalf("expected the replaced owner, got %x", nft.Owner)
+	}
+}
+
+func TestNFTHistory(t *testing.T) {
+	rm := setupTestDB(t)
+	ctx := context.Background()
+	id := makeNFTokenID(1, 1)
+
+	var links []relationaldb.NFTTransaction
+	for i := uint32(1); i <= 5; i++ {
+		tx := &relationaldb.TransactionInfo{
+			LedgerSeq: relationaldb.LedgerIndex(10 * i),
+			Status:    "validated",
+			RawTxn:    []byte("data"),
+		}
+		tx.Hash[0] = byte(i)
+		if err := rm.Transaction().SaveTransaction(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+		links = append(links, relationaldb.NFTTransaction{NFTokenID: id, LedgerSeq: tx.LedgerSeq, TxnSeq: i, Hash: tx.Hash})
+	}
+	if err := rm.NFT().SaveNFTTransactions(ctx, links); err != nil {
+		t.Fatal(err)
+	}
+	// Saving a link again is a no-op.
+	if err := rm.NFT().SaveNFTTransactions(ctx, links[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := rm.NFT().GetNFTHistory(ctx, relationaldb.NFTHistoryOptions{NFTokenID: id, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Transactions) != 2 || page.Transactions[0].LedgerSeq != 50 || page.Marker == nil {
+		t.Fatalf("unexpected newest page: %+v", page)
+	}
+
+	var seqs []relationaldb.LedgerIndex
+	options := relationaldb.NFTHistoryOptions{NFTokenID: id, MinLedger: 20, Limit: 2, Forward: true}
+	for {
+		page, err := rm.NFT().GetNFTHistory(ctx, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tx := range page.Transactions {
+			seqs = append(seqs, tx.LedgerSeq)
+		}
+		if page.Marker == nil {
+			break
+		}
+		options.Marker = page.Marker
+	}
+	if len(seqs) != 4 || seqs[0] != 20 || seqs[3] != 50 {
+		t.Fatalf("unexpected forward pages: %v", seqs)
+	}
+}
+
+func TestNFTsByIssuer(t *testing.T) {
+	rm := setupTestDB(t)
+	ctx := context.Background()
+	owner := relationaldb.AccountID{0xa1}
+
+	var nfts []relationaldb.NFTRecord
+	var taxons []uint32
+	for serial := byte(1); serial <= 5; serial++ {
+		nfts = append(nfts, relationaldb.NFTRecord{
+			NFTokenID: makeNFTokenID(1, serial),
+			LedgerSeq: relationaldb.LedgerIndex(serial),
+			Owner:     owner,
+		})
+		taxons = append(taxons, uint32(serial%2))
+	}
+	// Another issuer's NFT.
+	nfts = append(nfts, relationaldb.NFTRecord{NFTokenID: makeNFTokenID(2, 1), LedgerSeq: 1, Owner: owner})
+	taxons = append(taxons, 1)
+	if err := rm.NFT().SaveNFTs(ctx, nfts, taxons); err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := relationaldb.AccountID{1}
+	var ids []relationaldb.Hash
+	options := relationaldb.NFTsByIssuerOptions{Issuer: issuer, LedgerSeq: 4, Limit: 3}
+	for {
+		page, err := rm.NFT().GetNFTsByIssuer(ctx, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, nft := range page.NFTs {
+			ids = append(ids, nft.NFTokenID)
+		}
+		if page.Marker == nil {
+			break
+		}
+		options.Marker = page.Marker
+	}
+	if len(ids) != 4 || ids[0] != makeNFTokenID(1, 1) || ids[3] != makeNFTokenID(1, 4) {
+		t.Fatalf("expected the four NFTs minted by ledger 4, got %x", ids)
+	}
+
+	taxon := uint32(1)
+	page, err := rm.NFT().GetNFTsByIssuer(ctx, relationaldb.NFTsByIssuerOptions{
+		Issuer: issuer, Taxon: &taxon, LedgerSeq: 10, Limit: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.NFTs) != 3 || page.Marker != nil {
+		t.Fatalf("expected three NFTs of taxon 1, got %d", len(page.NFTs))
+	}
+}
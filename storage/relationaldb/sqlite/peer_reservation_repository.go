@@ -0,0 +1,66 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+// PeerReservationRepository is the SQLite-backed peer reservation table.
+// Lives in ledger.db alongside the validation archive; rippled keeps the
+// equivalent PeerReservations table in its wallet DB, which goXRPL does
+// not have.
+type PeerReservationRepository struct {
+	db *sql.DB
+}
+
+// Compile-time interface check.
+var _ relationaldb.PeerReservationRepository = (*PeerReservationRepository)(nil)
+
+func NewPeerReservationRepository(db *sql.DB) *PeerReservationRepository {
+	return &PeerReservationRepository{db: db}
+}
+
+func (r *PeerReservationRepository) List(ctx context.Context) ([]*relationaldb.PeerReservationRecord, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT public_key, description FROM peer_reservations`)
+	if err != nil {
+		return nil, relationaldb.NewQueryError("peer_reservation_list", "failed to query reservations", err)
+	}
+	defer rows.Close()
+
+	var result []*relationaldb.PeerReservationRecord
+	for rows.Next() {
+		var rec relationaldb.PeerReservationRecord
+		if err := rows.Scan(&rec.PublicKey, &rec.Description); err != nil {
+			return nil, relationaldb.NewQueryError("peer_reservation_list", "failed to scan row", err)
+		}
+		result = append(result, &rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, relationaldb.NewQueryError("peer_reservation_list", "row iteration error", err)
+	}
+	return result, nil
+}
+
+func (r *PeerReservationRepository) Upsert(ctx context.Context, rec *relationaldb.PeerReservationRecord) error {
+	if rec == nil || rec.PublicKey == "" {
+		return relationaldb.NewDataError("peer_reservation_upsert", "missing public key", nil)
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO peer_reservations (public_key, description) VALUES (?, ?)
+		ON CONFLICT(public_key) DO UPDATE SET description = excluded.description
+	`, rec.PublicKey, rec.Description)
+	if err != nil {
+		return relationaldb.NewQueryError("peer_reservation_upsert", "failed to upsert reservation", err)
+	}
+	return nil
+}
+
+func (r *PeerReservationRepository) Delete(ctx context.Context, publicKey string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM peer_reservations WHERE public_key = ?`, publicKey)
+	if err != nil {
+		return relationaldb.NewQueryError("peer_reservation_delete", "failed to delete reservation", err)
+	}
+	return nil
+}
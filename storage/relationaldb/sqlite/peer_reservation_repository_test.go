@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LeJamon/goXRPLd/storage/relationaldb"
+)
+
+func TestPeerReservationRepository_UpsertListDelete(t *testing.T) {
+	rm := setupTestDB(t)
+	repo := rm.PeerReservation()
+	ctx := context.Background()
+
+	key := "n9KAa2zVWjPHgfzsE3iZ8HAbzJtPrnoh4H2M2HgE7dfqtvyEb1KJ"
+	if err := repo.Upsert(ctx, &relationaldb.PeerReservationRecord{PublicKey: key, Description: "hub"}); err != nil {
+		t.Fatal(err)
+	}
+	// Second upsert replaces the description instead of inserting a row.
+	if err := repo.Upsert(ctx, &relationaldb.PeerReservationRecord{PublicKey: key, Description: "hub-2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := repo.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].PublicKey != key || list[0].Description != "hub-2" {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+
+	if err := repo.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	// Deleting an absent key is a no-op.
+	if err := repo.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	list, err = repo.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("expected empty list after delete, got %d", len(list))
+	}
+}
+
+func TestPeerReservationRepository_PersistsAcrossReopen(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	rm, err := NewRepositoryManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rm.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	key := "n9KAa2zVWjPHgfzsE3iZ8HAbzJtPrnoh4H2M2HgE7dfqtvyEb1KJ"
+	if err := rm.PeerReservation().Upsert(ctx, &relationaldb.PeerReservationRecord{PublicKey: key}); err != nil {
+		t.Fatal(err)
+	}
+	rm.Close(ctx)
+
+	rm2, err := NewRepositoryManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rm2.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer rm2.Close(ctx)
+
+	list, err := rm2.PeerReservation().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].PublicKey != key {
+		t.Fatalf("reservation did not survive reopen: %+v", list)
+	}
+}
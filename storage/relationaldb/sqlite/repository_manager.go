@@ -19,15 +19,26 @@ type RepositoryManager struct {
 	ledgerDB *sql.DB
 	txDB     *sql.DB
 
+	// migrators version ledger.db and transaction.db, in that order.
+	// manualMigrate leaves pending migrations to Migrate.
+	migrators     []*relationaldb.Migrator
+	manualMigrate bool
+
 	ledgerRepo             *LedgerRepository
 	transactionRepo        *TransactionRepository
 	accountTransactionRepo *AccountTransactionRepository
 	systemRepo             *SystemRepository
 	validationRepo         *ValidationRepository
+	peerReservationRepo    *PeerReservationRepository
+	nftRepo                *NFTRepository
+	mptRepo                *MPTRepository
 }
 
-// Compile-time interface check
-var _ relationaldb.RepositoryManager = (*RepositoryManager)(nil)
+// Compile-time interface checks
+var (
+	_ relationaldb.RepositoryManager = (*RepositoryManager)(nil)
+	_ relationaldb.SchemaManager     = (*RepositoryManager)(nil)
+)
 
 // NewRepositoryManager creates a new SQLite repository manager.
 // dbDir is the directory where ledger.db and transaction.db will be created.
@@ -77,17 +88,22 @@ func (rm *RepositoryManager) Open(ctx context.Context) error {
 		return relationaldb.NewConnectionError("open", "failed to apply transaction DB pragmas", err)
 	}
 
-	if err := rm.initLedgerSchema(ctx); err != nil {
-		rm.close()
-		return relationaldb.NewSchemaError("open", "failed to initialize ledger schema", err)
-	}
-	if err := rm.initValidationSchema(ctx); err != nil {
+	if err := rm.initMigrators(); err != nil {
 		rm.close()
-		return relationaldb.NewSchemaError("open", "failed to initialize validation schema", err)
+		return err
 	}
-	if err := rm.initTxSchema(ctx); err != nil {
-		rm.close()
-		return relationaldb.NewSchemaError("open", "failed to initialize transaction schema", err)
+	for _, m := range rm.migrators {
+		if _, err := m.Check(ctx); err != nil {
+			rm.close()
+			return err
+		}
+		if rm.manualMigrate {
+			continue
+		}
+		if _, err := m.Migrate(ctx, false); err != nil {
+			rm.close()
+			return err
+		}
 	}
 
 	rm.ledgerRepo = NewLedgerRepository(rm.ledgerDB)
@@ -95,6 +111,9 @@ func (rm *RepositoryManager) Open(ctx context.Context) error {
 	rm.accountTransactionRepo = NewAccountTransactionRepository(rm.txDB)
 	rm.systemRepo = NewSystemRepository(rm.ledgerDB, rm.txDB)
 	rm.validationRepo = NewValidationRepository(rm.ledgerDB)
+	rm.peerReservationRepo = NewPeerReservationRepository(rm.ledgerDB)
+	rm.nftRepo = NewNFTRepository(rm.txDB)
+	rm.mptRepo = NewMPTRepository(rm.txDB)
 
 	return nil
 }
@@ -117,11 +136,15 @@ func (rm *RepositoryManager) close() error {
 		}
 		rm.txDB = nil
 	}
+	rm.migrators = nil
 	rm.ledgerRepo = nil
 	rm.transactionRepo = nil
 	rm.accountTransactionRepo = nil
 	rm.systemRepo = nil
 	rm.validationRepo = nil
+	rm.peerReservationRepo = nil
+	rm.nftRepo = nil
+	rm.mptRepo = nil
 
 	if firstErr != nil {
 		return relationaldb.NewConnectionError("close", "failed to close database", firstErr)
@@ -149,6 +172,18 @@ func (rm *RepositoryManager) Validation() relationaldb.ValidationRepository {
 	return rm.validationRepo
 }
 
+func (rm *RepositoryManager) PeerReservation() relationaldb.PeerReservationRepository {
+	return rm.peerReservationRepo
+}
+
+func (rm *RepositoryManager) NFT() relationaldb.NFTRepository {
+	return rm.nftRepo
+}
+
+func (rm *RepositoryManager) MPT() relationaldb.MPTRepository {
+	return rm.mptRepo
+}
+
 func (rm *RepositoryManager) WithTransaction(ctx context.Context, fn func(relationaldb.TransactionContext) error) error {
 	tx, err := rm.txDB.BeginTx(ctx, nil)
 	if err != nil {
@@ -190,86 +225,59 @@ func (rm *RepositoryManager) applyPragmas(ctx context.Context, db *sql.DB) error
 	return nil
 }
 
-func (rm *RepositoryManager) initLedgerSchema(ctx context.Context) error {
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS ledgers (
-			ledger_hash BLOB PRIMARY KEY,
-			ledger_seq INTEGER UNIQUE NOT NULL,
-			prev_hash BLOB NOT NULL,
-			total_coins INTEGER NOT NULL,
-			closing_time INTEGER NOT NULL,
-			prev_closing_time INTEGER NOT NULL,
-			close_time_res INTEGER NOT NULL,
-			close_flags INTEGER NOT NULL,
-			account_set_hash BLOB NOT NULL,
-			trans_set_hash BLOB NOT NULL
-		)`,
-		`CREATE INDEX IF NOT EXISTS idx_ledgers_seq ON ledgers(ledger_seq)`,
+// SetAutoMigrate controls whether Open applies pending migrations.
+// Must be called before Open.
+func (rm *RepositoryManager) SetAutoMigrate(enabled bool) {
+	rm.manualMigrate = !enabled
+}
+
+// SchemaStatus reports the schema versions of ledger.db and transaction.db.
+func (rm *RepositoryManager) SchemaStatus(ctx context.Context) ([]*relationaldb.SchemaStatus, error) {
+	if rm.migrators == nil {
+		return nil, relationaldb.ErrDatabaseClosed
 	}
-	for _, q := range queries {
-		if _, err := rm.ledgerDB.ExecContext(ctx, q); err != nil {
-			return err
+	statuses := make([]*relationaldb.SchemaStatus, 0, len(rm.migrators))
+	for _, m := range rm.migrators {
+		status, err := m.Status(ctx)
+		if err != nil {
+			return nil, err
 		}
+		statuses = append(statuses, status)
 	}
-	return nil
+	return statuses, nil
 }
 
-// initValidationSchema installs the on-disk validation archive table.
-// Cohabits ledger.db — see ValidationRepository for the rationale.
-// Columns mirror rippled's historical Validations DDL (DBInit.h,
-// pre-May-2019) with SeenTime + Flags added for receive-side forensics.
-func (rm *RepositoryManager) initValidationSchema(ctx context.Context) error {
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS validations (
-			ledger_seq   INTEGER NOT NULL,
-			initial_seq  INTEGER NOT NULL,
-			ledger_hash  BLOB NOT NULL,
-			node_pubkey  BLOB NOT NULL,
-			sign_time    INTEGER NOT NULL,
-			seen_time    INTEGER NOT NULL,
-			flags        INTEGER NOT NULL,
-			raw          BLOB NOT NULL,
-			PRIMARY KEY (ledger_hash, node_pubkey)
-		)`,
-		`CREATE INDEX IF NOT EXISTS idx_validations_seq       ON validations(ledger_seq)`,
-		`CREATE INDEX IF NOT EXISTS idx_validations_node      ON validations(node_pubkey, ledger_seq)`,
-		`CREATE INDEX IF NOT EXISTS idx_validations_sign_time ON validations(sign_time)`,
-		`CREATE INDEX IF NOT EXISTS idx_validations_initial   ON validations(initial_seq, ledger_seq)`,
+// Migrate applies pending migrations to ledger.db and transaction.db.
+func (rm *RepositoryManager) Migrate(ctx context.Context, dryRun bool) (map[string][]relationaldb.Migration, error) {
+	if rm.migrators == nil {
+		return nil, relationaldb.ErrDatabaseClosed
 	}
-	for _, q := range queries {
-		if _, err := rm.ledgerDB.ExecContext(ctx, q); err != nil {
-			return err
+	applied := make(map[string][]relationaldb.Migration, len(rm.migrators))
+	for _, m := range rm.migrators {
+		migrations, err := m.Migrate(ctx, dryRun)
+		applied[m.Schema()] = migrations
+		if err != nil {
+			return applied, err
 		}
 	}
-	return nil
+	return applied, nil
 }
 
-func (rm *RepositoryManager) initTxSchema(ctx context.Context) error {
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS transactions (
-			trans_id BLOB PRIMARY KEY,
-			ledger_seq INTEGER NOT NULL,
-			status TEXT NOT NULL,
-			raw_txn BLOB NOT NULL,
-			txn_meta BLOB
-		)`,
-		`CREATE INDEX IF NOT EXISTS idx_transactions_ledger_seq ON transactions(ledger_seq)`,
-
-		`CREATE TABLE IF NOT EXISTS account_transactions (
-			trans_id BLOB NOT NULL,
-			account TEXT NOT NULL,
-			ledger_seq INTEGER NOT NULL,
-			txn_seq INTEGER NOT NULL,
-			PRIMARY KEY (trans_id, account)
-		)`,
-		`CREATE INDEX IF NOT EXISTS idx_acct_tx_id ON account_transactions(trans_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_acct_tx ON account_transactions(account, ledger_seq, txn_seq, trans_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_acct_lgr ON account_transactions(ledger_seq, account, trans_id)`,
-	}
-	for _, q := range queries {
-		if _, err := rm.txDB.ExecContext(ctx, q); err != nil {
+func (rm *RepositoryManager) initMigrators() error {
+	rm.migrators = nil
+	for _, schema := range []struct {
+		db         *sql.DB
+		name       string
+		migrations []relationaldb.Migration
+	}{
+		{rm.ledgerDB, "ledger.db", ledgerMigrations},
+		{rm.txDB, "transaction.db", txMigrations},
+	} {
+		m, err := relationaldb.NewMigrator(schema.db, schema.name, relationaldb.DialectSQLite, schema.migrations)
+		if err != nil {
 			return err
 		}
+		rm.migrators = append(rm.migrators, m)
 	}
 	return nil
 }
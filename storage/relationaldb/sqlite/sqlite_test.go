@@ -2,6 +2,7 @@ package sqlite
 
 import (
 	"context"
+	"reflect"
 	"testing"
 	"time"
 
@@ -327,6 +328,43 @@ func TestTransactionHistory(t *testing.T) {
 	}
 }
 
+func TestTransactionsByLedgerRange(t *testing.T) {
+	rm := setupTestDB(t)
+	ctx := context.Background()
+
+	for i := uint32(1); i <= 5; i++ {
+		tx := &relationaldb.TransactionInfo{
+			LedgerSeq: relationaldb.LedgerIndex(i),
+			Status:    "validated",
+			RawTxn:    []byte("data"),
+		}
+		tx.Hash[0] = byte(i)
+		if err := rm.Transaction().SaveTransaction(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	txs, err := rm.Transaction().GetTransactionsByLedgerRange(ctx, 2, 4, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(txs))
+	}
+	// Should be ascending order
+	if txs[0].LedgerSeq != 2 || txs[2].LedgerSeq != 4 {
+		t.Fatal("unexpected order")
+	}
+
+	txs, err = rm.Transaction().GetTransactionsByLedgerRange(ctx, 2, 4, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 || txs[0].LedgerSeq != 2 {
+		t.Fatalf("expected the limit to apply, got %d results", len(txs))
+	}
+}
+
 func TestTransactionDelete(t *testing.T) {
 	rm := setupTestDB(t)
 	ctx := context.Background()
@@ -472,6 +510,185 @@ func TestAccountTransactionPagination(t *testing.T) {
 	}
 }
 
+func TestAccountTransactionFilters(t *testing.T) {
+	rm := setupTestDB(t)
+	ctx := context.Background()
+
+	alice := relationaldb.AccountID{0xa1}
+	bob := relationaldb.AccountID{0xb0}
+
+	// Alternate payments sent by alice to bob and by bob to alice, with
+	// an offer by alice every third ledger.
+	for i := uint32(1); i <= 9; i++ {
+		tx := &relationaldb.TransactionInfo{
+			LedgerSeq: relationaldb.LedgerIndex(i),
+			Status:    "validated",
+			RawTxn:    []byte("raw"),
+			Account:   alice,
+			TxType:    "Payment",
+		}
+		tx.Hash[0] = byte(i)
+		switch {
+		case i%3 == 0:
+			tx.TxType = "OfferCreate"
+		case i%2 == 0:
+			tx.Account = bob
+		}
+		if err := rm.Transaction().SaveTransaction(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+		if err := rm.AccountTransaction().SaveAccountTransaction(ctx, tx.Account, tx); err != nil {
+			t.Fatal(err)
+		}
+		if tx.TxType == "Payment" {
+			tx.Destination = bob
+			if tx.Account == bob {
+				tx.Destination = alice
+			}
+			if err := rm.AccountTransaction().SaveAccountTransaction(ctx, tx.Destination, tx); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	collect := func(filter relationaldb.AccountTxFilter, forward bool) []relationaldb.LedgerIndex {
+		t.Helper()
+		var seqs []relationaldb.LedgerIndex
+		options := relationaldb.AccountTxPageOptions{Account: alice, Limit: 2, Filter: filter}
+		for {
+			get := rm.AccountTransaction().GetNewestAccountTxsPage
+			if forward {
+				get = rm.AccountTransaction().GetOldestAccountTxsPage
+			}
+			page, err := get(ctx, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, tx := range page.Transactions {
+				seqs = append(seqs, tx.LedgerSeq)
+			}
+			if page.Marker == nil {
+				return seqs
+			}
+			options.Marker = page.Marker
+		}
+	}
+	// A payment from bob to carol through alice's trust line affects alice
+	// without being sent to her.
+	rippled := &relationaldb.TransactionInfo{
+		LedgerSeq:   10,
+		Status:      "validated",
+		RawTxn:      []byte("raw"),
+		Account:     bob,
+		TxType:      "Payment",
+		Destination: relationaldb.AccountID{0xc0},
+	}
+	rippled.Hash[0] = 10
+	if err := rm.Transaction().SaveTransaction(ctx, rippled); err != nil {
+		t.Fatal(err)
+	}
+	if err := rm.AccountTransaction().SaveAccountTransaction(ctx, alice, rippled); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ledgers 1, 5, 7: payments alice sent; 2, 4, 8: payments she
+	// received; 3, 6, 9: her offers; 10: a payment that only affected her.
+	for _, tt := range []struct {
+		name    string
+		filter  relationaldb.AccountTxFilter
+		forward bool
+		want    []relationaldb.LedgerIndex
+	}{
+		{"all", relationaldb.AccountTxFilter{}, true, []relationaldb.LedgerIndex{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		{"payments", relationaldb.AccountTxFilter{TxType: "Payment"}, true, []relationaldb.LedgerIndex{1, 2, 4, 5, 7, 8, 10}},
+		{"outgoing", relationaldb.AccountTxFilter{Direction: relationaldb.AccountTxOutgoing}, false, []relationaldb.LedgerIndex{9, 7, 6, 5, 3, 1}},
+		{"incoming payments", relationaldb.AccountTxFilter{TxType: "Payment", Direction: relationaldb.AccountTxIncoming}, false, []relationaldb.LedgerIndex{8, 4, 2}},
+		{"no match", relationaldb.AccountTxFilter{TxType: "OfferCreate", Direction: relationaldb.AccountTxIncoming}, true, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := collect(tt.filter, tt.forward); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected ledgers %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAccountTransactionTypeBackfill(t *testing.T) {
+	rm := setupTestDB(t)
+	ctx := context.Background()
+
+	alice := relationaldb.AccountID{0xa1}
+	bob := relationaldb.AccountID{0xb0}
+
+	// Three payments indexed for both parties before types were recorded.
+	for i := uint32(1); i <= 3; i++ {
+		tx := &relationaldb.TransactionInfo{
+			LedgerSeq: relationaldb.LedgerIndex(i),
+			Status:    "validated",
+			RawTxn:    []byte{byte(i)},
+		}
+		tx.Hash[0] = byte(i)
+		if err := rm.Transaction().SaveTransaction(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+		for _, account := range []relationaldb.AccountID{alice, bob} {
+			if err := rm.AccountTransaction().SaveAccountTransaction(ctx, account, tx); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	repo := rm.AccountTransaction()
+	var marker *relationaldb.UntypedAccountTx
+	var untyped []relationaldb.UntypedAccountTx
+	for {
+		rows, err := repo.GetUntypedAccountTransactions(ctx, marker, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+		untyped = append(untyped, rows...)
+		marker = &rows[len(rows)-1]
+	}
+	if len(untyped) != 6 {
+		t.Fatalf("expected 6 untyped rows, got %d", len(untyped))
+	}
+	for i := range untyped {
+		if untyped[i].RawTxn[0] != untyped[i].Hash[0] {
+			t.Fatalf("row %d: raw transaction %x does not belong to %x", i, untyped[i].RawTxn, untyped[i].Hash[0])
+		}
+		untyped[i].TxType = "Payment"
+		untyped[i].Direction = relationaldb.AccountTxIncoming
+		if untyped[i].Account == alice {
+			untyped[i].Direction = relationaldb.AccountTxOutgoing
+		}
+	}
+	if err := repo.SetAccountTransactionTypes(ctx, untyped); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := repo.GetUntypedAccountTransactions(ctx, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 0 {
+		t.Fatalf("expected no untyped rows after backfill, got %d", len(rows))
+	}
+	page, err := repo.GetOldestAccountTxsPage(ctx, relationaldb.AccountTxPageOptions{
+		Account: alice,
+		Limit:   10,
+		Filter:  relationaldb.AccountTxFilter{TxType: "Payment", Direction: relationaldb.AccountTxOutgoing},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Transactions) != 3 {
+		t.Fatalf("expected 3 outgoing payments after backfill, got %d", len(page.Transactions))
+	}
+}
+
 func TestWithTransaction(t *testing.T) {
 	rm := setupTestDB(t)
 	ctx := context.Background()